@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	_ "github.com/fintrack/account-service/docs"
@@ -53,6 +54,14 @@ func main() {
 	}
 	defer application.Close()
 
+	// Card key rotation mode: re-wraps every card number under the active master key.
+	// Run it after deploying the new key first in the keyring, with the old keys still loaded.
+	if len(os.Args) > 1 && os.Args[1] == "rotate-card-keys" {
+		code := rotateCardKeys(application)
+		application.Close()
+		os.Exit(code)
+	}
+
 	// Gin setup
 	if cfg.LogLevel == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -97,3 +106,30 @@ func main() {
 		log.Fatalf("server error: %v", err)
 	}
 }
+
+// rotateCardKeys runs the card key rotation, with an optional batch size argument
+func rotateCardKeys(application *app.Application) int {
+	batchSize := 100
+	if len(os.Args) > 2 {
+		size, err := strconv.Atoi(os.Args[2])
+		if err != nil {
+			log.Printf("invalid batch size %q: %v", os.Args[2], err)
+			return 1
+		}
+		batchSize = size
+	}
+
+	result, err := application.CardNumberService.RotateKeys(batchSize)
+	if err != nil {
+		log.Printf("card key rotation failed: %v", err)
+		return 1
+	}
+
+	log.Printf("card key rotation to %s finished: scanned=%d rewrapped=%d sealed=%d skipped=%d failed=%d",
+		result.ActiveFingerprint, result.Scanned, result.Rewrapped, result.Sealed, result.Skipped, result.Failed)
+	if result.Failed > 0 || result.Skipped > 0 {
+		// Skipped cards changed during the run and failed ones need their old key back in the keyring
+		return 2
+	}
+	return 0
+}
@@ -7,17 +7,26 @@ import (
 	"github.com/fintrack/account-service/internal/config"
 	"github.com/fintrack/account-service/internal/core/domain/entities"
 	"github.com/fintrack/account-service/internal/core/service"
+	"github.com/fintrack/account-service/internal/infrastructure/encryption"
 	mysqlrepo "github.com/fintrack/account-service/internal/infrastructure/repositories/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
 type Application struct {
-	Config             *config.Config
-	DB                 *gorm.DB
-	AccountService     *service.AccountService
-	CardService        *service.CardService
-	InstallmentService *service.InstallmentService
+	Config              *config.Config
+	DB                  *gorm.DB
+	AccountService      *service.AccountService
+	CardService         *service.CardService
+	InstallmentService  *service.InstallmentService
+	MembershipService   *service.MembershipService
+	CreditLineService   *service.CreditLineService
+	CardControlsService *service.CardControlsService
+	CardNumberService   *service.CardNumberService
+	ClosureService      *service.AccountClosureService
+	CreditLimitService  *service.CreditLimitService
+	DebtPayoffService   *service.DebtPayoffService
+	InflationService    *service.InflationService
 }
 
 func New(cfg *config.Config) (*Application, error) {
@@ -41,28 +50,58 @@ func New(cfg *config.Config) (*Application, error) {
 	sqlDB.SetConnMaxLifetime(5 * time.Minute)
 
 	// Auto-migrate tables (excluding installment tables that are managed via SQL migrations)
-	if err := gormDB.AutoMigrate(&entities.Account{}, &entities.Card{}); err != nil {
+	if err := gormDB.AutoMigrate(&entities.Account{}, &entities.CreditLine{}, &entities.Card{}, &entities.CardControls{}, &entities.CardControlRejection{}, &entities.CardNumberAccessLog{}, &entities.AccountClosure{}, &entities.AccountClosureEvent{}, &entities.CreditLimitChangeRequest{}, &entities.CreditLimitHistory{}, &entities.DebtPayoffPlan{}, &entities.InflationRate{}); err != nil {
 		return nil, fmt.Errorf("failed to migrate tables: %w", err)
 	}
 
+	// Card number keyring (the old master keys stay loaded until the rotation finishes)
+	keyring, err := encryption.LoadKeyring(cfg.CardKeyringFile, cfg.CardMasterKeys, cfg.CardKeyringDev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load card keyring: %w", err)
+	}
+
 	// repositories
 	accountRepo := mysqlrepo.NewAccountRepository(gormDB)
 	cardRepo := mysqlrepo.NewCardRepository(gormDB)
 	installmentRepo := mysqlrepo.NewInstallmentRepository(gormDB)
 	installmentPlanRepo := mysqlrepo.NewInstallmentPlanRepository(gormDB)
 	installmentAuditRepo := mysqlrepo.NewInstallmentPlanAuditRepository(gormDB)
+	accountMemberRepo := mysqlrepo.NewAccountMemberRepository(gormDB)
+	creditLineRepo := mysqlrepo.NewCreditLineRepository(gormDB)
+	cardControlsRepo := mysqlrepo.NewCardControlsRepository(gormDB)
+	cardNumberRepo := mysqlrepo.NewCardNumberRepository(gormDB)
+	closureRepo := mysqlrepo.NewAccountClosureRepository(gormDB)
+	creditLimitRepo := mysqlrepo.NewCreditLimitRepository(gormDB)
+	debtPayoffRepo := mysqlrepo.NewDebtPayoffRepository(gormDB)
+	inflationRepo := mysqlrepo.NewInflationRepository(gormDB)
 
 	// services
-	accountSvc := service.NewAccountService(accountRepo)
-	installmentSvc := service.NewInstallmentService(installmentRepo, installmentPlanRepo, installmentAuditRepo, cardRepo, accountRepo)
-	cardSvc := service.NewCardService(cardRepo, accountRepo, installmentSvc)
+	accountSvc := service.NewAccountService(accountRepo, closureRepo)
+	membershipSvc := service.NewMembershipService(accountMemberRepo, accountRepo, cardRepo)
+	installmentSvc := service.NewInstallmentService(installmentRepo, installmentPlanRepo, installmentAuditRepo, cardRepo, accountRepo, membershipSvc)
+	cardControlsSvc := service.NewCardControlsService(cardControlsRepo, cardRepo, membershipSvc)
+	cardSvc := service.NewCardService(cardRepo, accountRepo, creditLineRepo, installmentSvc, membershipSvc, cardControlsSvc, keyring)
+	creditLineSvc := service.NewCreditLineService(creditLineRepo, accountRepo, cardRepo, membershipSvc)
+	cardNumberSvc := service.NewCardNumberService(cardNumberRepo, cardRepo, keyring)
+	closureSvc := service.NewAccountClosureService(closureRepo, accountRepo, cardRepo, installmentSvc)
+	creditLimitSvc := service.NewCreditLimitService(creditLimitRepo, accountRepo, cardRepo, membershipSvc, cfg.CreditLimitApprovalThreshold)
+	debtPayoffSvc := service.NewDebtPayoffService(debtPayoffRepo, accountRepo, cardRepo, cfg.DebtPayoffCardMonthlyRate, cfg.DebtPayoffMinimumPaymentPercent)
+	inflationSvc := service.NewInflationService(inflationRepo, installmentSvc, cfg.InflationMonthlyExpectation)
 
 	return &Application{
-		Config:             cfg,
-		DB:                 gormDB,
-		AccountService:     accountSvc,
-		CardService:        cardSvc,
-		InstallmentService: installmentSvc,
+		Config:              cfg,
+		DB:                  gormDB,
+		AccountService:      accountSvc,
+		CardService:         cardSvc,
+		InstallmentService:  installmentSvc,
+		MembershipService:   membershipSvc,
+		CreditLineService:   creditLineSvc,
+		CardControlsService: cardControlsSvc,
+		CardNumberService:   cardNumberSvc,
+		ClosureService:      closureSvc,
+		CreditLimitService:  creditLimitSvc,
+		DebtPayoffService:   debtPayoffSvc,
+		InflationService:    inflationSvc,
 	}, nil
 }
 
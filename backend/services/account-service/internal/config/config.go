@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,6 +19,30 @@ type Config struct {
 	JWTExpiry     time.Duration
 	RefreshExpiry time.Duration
 	LogLevel      string
+
+	// Master keys wrapping the card number data keys: a local keyring file takes
+	// precedence over the comma separated keys of the environment. The first key is active.
+	CardKeyringFile string
+	CardMasterKeys  string
+	// Local environments only: seal card numbers with the public development key when no
+	// keyring is configured. Without it the service refuses to start.
+	CardKeyringDev bool
+
+	// Shared secret other FinTrack services send in X-Internal-Service-Token to operate cards
+	// on their own, without a user. Empty disables internal calls.
+	InternalServiceToken string
+
+	// Credit limit increases above this amount wait for a treasurer's approval
+	CreditLimitApprovalThreshold float64
+
+	// Debt payoff planner: monthly interest rate (percent) of card balances when the user does not
+	// set one, and the share of the balance paid each month on top of its interest
+	DebtPayoffCardMonthlyRate       float64
+	DebtPayoffMinimumPaymentPercent float64
+
+	// Monthly inflation (percent) the cash versus installments calculator expects when the request
+	// gives neither a rate nor a stored series
+	InflationMonthlyExpectation float64
 }
 
 func getenv(key, def string) string {
@@ -35,6 +61,22 @@ func ParseDurationEnv(key, def string) time.Duration {
 	return d
 }
 
+func ParseFloatEnv(key string, def float64) float64 {
+	v, err := strconv.ParseFloat(getenv(key, ""), 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func ParseBoolEnv(key string, def bool) bool {
+	v, err := strconv.ParseBool(getenv(key, ""))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{
 		Port:          getenv("PORT", "8082"), // Default port for account-service
@@ -47,10 +89,29 @@ func Load() (*Config, error) {
 		JWTExpiry:     ParseDurationEnv("JWT_EXPIRY", "24h"),
 		RefreshExpiry: ParseDurationEnv("JWT_REFRESH_EXPIRY", "168h"),
 		LogLevel:      getenv("LOG_LEVEL", "info"),
+
+		CardKeyringFile: getenv("CARD_KEYRING_FILE", ""),
+		CardMasterKeys:  getenv("CARD_MASTER_KEYS", ""),
+		CardKeyringDev:  ParseBoolEnv("CARD_KEYRING_DEV", false),
+
+		InternalServiceToken: getenv("INTERNAL_SERVICE_TOKEN", ""),
+
+		CreditLimitApprovalThreshold: ParseFloatEnv("CREDIT_LIMIT_APPROVAL_THRESHOLD", 100000),
+
+		DebtPayoffCardMonthlyRate:       ParseFloatEnv("DEBT_PAYOFF_CARD_MONTHLY_RATE", 6),
+		DebtPayoffMinimumPaymentPercent: ParseFloatEnv("DEBT_PAYOFF_MINIMUM_PAYMENT_PERCENT", 5),
+
+		InflationMonthlyExpectation: ParseFloatEnv("INFLATION_MONTHLY_EXPECTATION", 2.5),
 	}
 	if cfg.JWTSecret == "change-me" {
 		// not fatal but warn; keep simple
 		fmt.Fprintf(os.Stderr, "[WARN] using default JWT secret, please set JWT_SECRET\n")
 	}
+	if cfg.CardKeyringFile == "" && strings.TrimSpace(cfg.CardMasterKeys) == "" {
+		if !cfg.CardKeyringDev {
+			return nil, fmt.Errorf("no card keyring configured: set CARD_KEYRING_FILE or CARD_MASTER_KEYS (CARD_KEYRING_DEV=true only for local development)")
+		}
+		fmt.Fprintf(os.Stderr, "[WARN] CARD_KEYRING_DEV is set: card numbers are sealed with the public development master key\n")
+	}
 	return cfg, nil
 }
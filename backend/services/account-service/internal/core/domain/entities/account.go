@@ -102,6 +102,7 @@ type Account struct {
 	DNI *string `gorm:"type:varchar(20);null" json:"dni,omitempty"`
 
 	IsActive  bool           `gorm:"not null;default:true;index" json:"is_active"`
+	ClosedAt  *time.Time     `gorm:"type:timestamp;null" json:"closed_at,omitempty"` // Set by the closure process
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
@@ -132,6 +133,14 @@ type Card struct {
 	ClosingDate *time.Time `gorm:"type:date;null" json:"closing_date,omitempty"`
 	DueDate     *time.Time `gorm:"type:date;null" json:"due_date,omitempty"`
 
+	// Shared credit line (optional - credit cards drawing from a single account-level limit)
+	CreditLineID *string  `gorm:"type:varchar(36);null;index" json:"credit_line_id,omitempty"`
+	IsAdditional bool     `gorm:"not null;default:false" json:"is_additional"`        // Additional card (extensión) of the line
+	SubLimit     *float64 `gorm:"type:decimal(15,2);null" json:"sub_limit,omitempty"` // Optional cap within the line limit
+
+	// Renewal - card replaced by this one when it was renewed
+	ReplacesCardID *string `gorm:"type:varchar(36);null;index" json:"replaces_card_id,omitempty"`
+
 	// Security - encrypted fields (stored separately for security)
 	EncryptedNumber string `gorm:"type:text;not null" json:"-"` // Never expose in JSON
 	KeyFingerprint  string `gorm:"type:varchar(64);not null" json:"-"`
@@ -142,6 +151,12 @@ type Card struct {
 
 	// Relationship back to account
 	Account Account `gorm:"foreignKey:AccountID" json:"-"`
+
+	// Credit line shared with other cards (loaded by the repository when needed)
+	CreditLine *CreditLine `gorm:"foreignKey:CreditLineID" json:"-"`
+
+	// Active installment plans (loaded by the repository when needed)
+	InstallmentPlans []InstallmentPlan `gorm:"foreignKey:CardID" json:"-"`
 }
 
 // TableName returns the table name for the Account model
@@ -264,10 +279,35 @@ func (c *Card) Validate() error {
 	}
 
 	// Validate credit card specific fields
-	if c.CardType == CardTypeCredit && c.CreditLimit == nil {
+	if c.CardType == CardTypeCredit && c.CreditLimit == nil && c.CreditLineID == nil {
 		return &ValidationError{Field: "credit_limit", Message: "credit limit is required for credit cards"}
 	}
 
+	return c.validateCreditLine()
+}
+
+// validateCreditLine validates the shared credit line fields
+func (c *Card) validateCreditLine() error {
+	if c.CreditLineID == nil {
+		if c.IsAdditional {
+			return &ValidationError{Field: "credit_line_id", Message: "additional cards must belong to a credit line"}
+		}
+		if c.SubLimit != nil {
+			return &ValidationError{Field: "sub_limit", Message: "sub-limits are only allowed for cards in a credit line"}
+		}
+		return nil
+	}
+
+	if c.CardType != CardTypeCredit {
+		return &ValidationError{Field: "credit_line_id", Message: "only credit cards can belong to a credit line"}
+	}
+	if c.SubLimit != nil && *c.SubLimit <= 0 {
+		return &ValidationError{Field: "sub_limit", Message: "sub-limit must be positive"}
+	}
+	if c.CreditLine != nil && c.SubLimit != nil && *c.SubLimit > c.CreditLine.TotalLimit {
+		return &ValidationError{Field: "sub_limit", Message: "sub-limit cannot exceed the credit line limit"}
+	}
+
 	return nil
 }
 
@@ -302,7 +342,7 @@ func (c *Card) ValidateForUpdate() error {
 		}
 	}
 
-	return nil
+	return c.validateCreditLine()
 }
 
 // IsWallet checks if the account is a virtual wallet
@@ -341,11 +381,57 @@ func (a *Account) HasActiveCards() bool {
 	return false
 }
 
+// ExpiresAt returns the moment the card stops being valid. Cards are valid through
+// the last day of their expiration month.
+func (c *Card) ExpiresAt() time.Time {
+	return time.Date(c.ExpirationYear, time.Month(c.ExpirationMonth)+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
 // IsExpired checks if the card is expired
 func (c *Card) IsExpired() bool {
-	now := time.Now()
-	expiry := time.Date(c.ExpirationYear, time.Month(c.ExpirationMonth), 1, 0, 0, 0, 0, time.UTC)
-	return now.After(expiry)
+	return !time.Now().Before(c.ExpiresAt())
+}
+
+// CanBeRenewed checks if a replacement can be issued for the card.
+// Inactive cards were already replaced or disabled.
+func (c *Card) CanBeRenewed() bool {
+	return c.Status == CardStatusActive || c.Status == CardStatusBlocked || c.Status == CardStatusExpired
+}
+
+// Renew hands the card over to its replacement. The replacement inherits the balance,
+// credit settings and default flag, and the card is left inactive with no debt.
+func (c *Card) Renew(replacement *Card) error {
+	if !c.CanBeRenewed() {
+		return &ValidationError{Field: "status", Message: "card with status " + string(c.Status) + " cannot be renewed"}
+	}
+	if !replacement.ExpiresAt().After(c.ExpiresAt()) {
+		return &ValidationError{Field: "expiration_year", Message: "replacement card must expire after the current card"}
+	}
+	if replacement.IsExpired() {
+		return &ValidationError{Field: "expiration_year", Message: "replacement card is already expired"}
+	}
+
+	replacement.AccountID = c.AccountID
+	replacement.CardType = c.CardType
+	replacement.CardBrand = c.CardBrand
+	replacement.HolderName = c.HolderName
+	replacement.Nickname = c.Nickname
+	replacement.Status = CardStatusActive
+	replacement.IsDefault = c.IsDefault
+	replacement.Balance = c.Balance
+	replacement.CreditLimit = c.CreditLimit
+	replacement.ClosingDate = c.ClosingDate
+	replacement.DueDate = c.DueDate
+	replacement.CreditLineID = c.CreditLineID
+	replacement.IsAdditional = c.IsAdditional
+	replacement.SubLimit = c.SubLimit
+	replacement.CreditLine = c.CreditLine
+	replacement.ReplacesCardID = &c.ID
+
+	c.Balance = 0
+	c.IsDefault = false
+	c.Status = CardStatusInactive
+	return nil
 }
 
 // IsActive checks if the card is active and not expired
@@ -358,6 +444,9 @@ func (c *Card) GetAvailableBalance() float64 {
 	if c.CardType == CardTypeDebit {
 		// For debit cards, available balance is the account balance
 		return c.Account.Balance
+	} else if c.CardType == CardTypeCredit && c.CreditLine != nil {
+		// For cards in a credit line, the line and the card sub-limit both cap the available credit
+		return c.capToSubLimit(c.CreditLine.GetAvailableCredit(c), c.Balance)
 	} else if c.CardType == CardTypeCredit && c.CreditLimit != nil {
 		// For credit cards, available balance is credit limit minus debt
 		return *c.CreditLimit - c.Balance
@@ -365,6 +454,34 @@ func (c *Card) GetAvailableBalance() float64 {
 	return 0
 }
 
+// GetEffectiveCreditLimit returns the limit the card can draw up to:
+// its sub-limit or the line limit for cards in a credit line, its own limit otherwise
+func (c *Card) GetEffectiveCreditLimit() float64 {
+	if c.CardType != CardTypeCredit {
+		return 0
+	}
+	if c.CreditLine != nil {
+		if c.SubLimit != nil && *c.SubLimit < c.CreditLine.TotalLimit {
+			return *c.SubLimit
+		}
+		return c.CreditLine.TotalLimit
+	}
+	if c.CreditLimit != nil {
+		return *c.CreditLimit
+	}
+	return 0
+}
+
+// capToSubLimit limits the credit available on the line to what is left of the card sub-limit
+func (c *Card) capToSubLimit(lineAvailable, used float64) float64 {
+	if c.SubLimit != nil {
+		if cardAvailable := *c.SubLimit - used; cardAvailable < lineAvailable {
+			return cardAvailable
+		}
+	}
+	return lineAvailable
+}
+
 // GetDebt returns the debt amount for credit cards
 func (c *Card) GetDebt() float64 {
 	if c.CardType == CardTypeCredit {
@@ -383,8 +500,8 @@ func (c *Card) CanCharge(amount float64) bool {
 		// For debit cards, check account balance
 		return c.Account.Balance >= amount
 	} else if c.CardType == CardTypeCredit {
-		// For credit cards, check available credit
-		return c.GetAvailableBalance() >= amount
+		// For credit cards, check available credit (shared line and installment commitments included)
+		return c.GetAvailableCreditWithInstallments() >= amount
 	}
 
 	return false
@@ -879,15 +996,19 @@ func (c *Card) CanCreateInstallmentPlan(amount float64) bool {
 	}
 
 	// Check available credit
-	availableCredit := c.GetAvailableBalance()
+	availableCredit := c.GetAvailableCreditWithInstallments()
 	return availableCredit >= amount
 }
 
-// GetActiveInstallmentPlans returns active installment plans (would be loaded separately)
-// This is a placeholder method - actual implementation would load from repository
+// GetActiveInstallmentPlans returns the active installment plans loaded by the repository
 func (c *Card) GetActiveInstallmentPlans() []InstallmentPlan {
-	// This would be populated by the repository when loading card with installment plans
-	return []InstallmentPlan{}
+	var plans []InstallmentPlan
+	for _, plan := range c.InstallmentPlans {
+		if plan.IsActive() {
+			plans = append(plans, plan)
+		}
+	}
+	return plans
 }
 
 // GetTotalInstallmentCommitments calculates total committed amount in active installment plans
@@ -902,14 +1023,33 @@ func (c *Card) GetTotalInstallmentCommitments() float64 {
 	return total
 }
 
+// GetCommittedCredit returns the credit held by the card: its debt or, when higher,
+// what is still owed on its installment plans. Installment purchases are charged in full
+// to the balance, so commitments are only added on top when payments brought the debt below them.
+func (c *Card) GetCommittedCredit() float64 {
+	if c.CardType != CardTypeCredit {
+		return 0
+	}
+
+	commitments := c.GetTotalInstallmentCommitments()
+	if commitments > c.Balance {
+		return commitments
+	}
+	return c.Balance
+}
+
 // GetAvailableCreditWithInstallments calculates available credit considering installment commitments
 func (c *Card) GetAvailableCreditWithInstallments() float64 {
-	if c.CardType != CardTypeCredit || c.CreditLimit == nil {
+	if c.CardType != CardTypeCredit {
 		return 0
 	}
 
-	baseAvailable := c.GetAvailableBalance()
-	commitments := c.GetTotalInstallmentCommitments()
+	if c.CreditLine != nil {
+		return c.capToSubLimit(c.CreditLine.GetAvailableCreditWithInstallments(c), c.GetCommittedCredit())
+	}
 
-	return baseAvailable - commitments
+	if c.CreditLimit == nil {
+		return 0
+	}
+	return *c.CreditLimit - c.GetCommittedCredit()
 }
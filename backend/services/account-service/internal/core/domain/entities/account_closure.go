@@ -0,0 +1,223 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccountClosureStatus represents the status of an account closure process
+type AccountClosureStatus string
+
+const (
+	AccountClosureStatusPending   AccountClosureStatus = "pending"
+	AccountClosureStatusCompleted AccountClosureStatus = "completed"
+	AccountClosureStatusCancelled AccountClosureStatus = "cancelled"
+)
+
+// ClosureStep identifies an audited step of an account closure
+type ClosureStep string
+
+const (
+	ClosureStepStarted            ClosureStep = "started"
+	ClosureStepBalanceTransferred ClosureStep = "balance_transferred"
+	ClosureStepCardSettled        ClosureStep = "card_settled"
+	ClosureStepPlanCancelled      ClosureStep = "plan_cancelled"
+	ClosureStepPlanTransferred    ClosureStep = "plan_transferred"
+	ClosureStepCompleted          ClosureStep = "completed"
+	ClosureStepCancelled          ClosureStep = "cancelled"
+)
+
+// ClosureBlockerType identifies what prevents an account from being closed
+type ClosureBlockerType string
+
+const (
+	ClosureBlockerBalance         ClosureBlockerType = "balance"          // Move it to another account or withdraw it
+	ClosureBlockerCardDebt        ClosureBlockerType = "card_debt"        // Settle it from the account balance
+	ClosureBlockerCardCredit      ClosureBlockerType = "card_credit"      // Overpaid card, refunded to the account on settlement
+	ClosureBlockerInstallmentPlan ClosureBlockerType = "installment_plan" // Cancel it or transfer it to another card
+)
+
+// ClosureBlocker describes a pending item that must be resolved before closing an account
+type ClosureBlocker struct {
+	Type        ClosureBlockerType `json:"type"`
+	ReferenceID string             `json:"reference_id"`
+	Description string             `json:"description"`
+	Amount      float64            `json:"amount"`
+}
+
+// ClosureCheck is the result of the pre-checks of an account closure
+type ClosureCheck struct {
+	AccountID string           `json:"account_id"`
+	Balance   float64          `json:"balance"`
+	CanClose  bool             `json:"can_close"`
+	Blockers  []ClosureBlocker `json:"blockers"`
+}
+
+// AccountClosure represents the process of closing an account
+type AccountClosure struct {
+	ID          string               `gorm:"type:varchar(36);primaryKey" json:"id"`
+	AccountID   string               `gorm:"type:varchar(36);not null;index" json:"account_id"`
+	RequestedBy string               `gorm:"type:varchar(36);not null" json:"requested_by"`
+	Reason      string               `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	Status      AccountClosureStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+
+	// Final closing statement, generated when the closure completes
+	Statement *ClosingStatement `gorm:"type:json;serializer:json" json:"statement,omitempty"`
+
+	CompletedAt *time.Time `gorm:"type:timestamp;null" json:"completed_at,omitempty"`
+	CancelledAt *time.Time `gorm:"type:timestamp;null" json:"cancelled_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Audit trail of every step of the closure
+	Events []AccountClosureEvent `gorm:"foreignKey:ClosureID" json:"events,omitempty"`
+}
+
+// AccountClosureEvent records a step of an account closure
+type AccountClosureEvent struct {
+	ID          string      `gorm:"type:varchar(36);primaryKey" json:"id"`
+	ClosureID   string      `gorm:"type:varchar(36);not null;index" json:"closure_id"`
+	AccountID   string      `gorm:"type:varchar(36);not null;index" json:"account_id"`
+	Step        ClosureStep `gorm:"type:varchar(30);not null" json:"step"`
+	ReferenceID string      `gorm:"type:varchar(36)" json:"reference_id,omitempty"` // Card, plan or account involved
+	Amount      *float64    `gorm:"type:decimal(15,2);null" json:"amount,omitempty"`
+	Details     string      `gorm:"type:text" json:"details,omitempty"`
+	PerformedBy string      `gorm:"type:varchar(36);not null" json:"performed_by"`
+	CreatedAt   time.Time   `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ClosingStatement summarizes the final settlement of a closed account
+type ClosingStatement struct {
+	AccountID          string                 `json:"account_id"`
+	AccountName        string                 `json:"account_name"`
+	Currency           Currency               `json:"currency"`
+	FinalBalance       float64                `json:"final_balance"`
+	BalanceTransferred float64                `json:"balance_transferred"`
+	CardsSettled       float64                `json:"cards_settled"` // Net amount paid from the account to settle its cards
+	PlansCancelled     int                    `json:"plans_cancelled"`
+	PlansTransferred   int                    `json:"plans_transferred"`
+	Cards              []ClosingStatementCard `json:"cards"`
+	Lines              []ClosingStatementLine `json:"lines"`
+	ClosedBy           string                 `json:"closed_by"`
+	GeneratedAt        time.Time              `json:"generated_at"`
+}
+
+// ClosingStatementCard summarizes a card of a closed account
+type ClosingStatementCard struct {
+	CardID       string   `json:"card_id"`
+	CardType     CardType `json:"card_type"`
+	MaskedNumber string   `json:"masked_number"`
+	FinalBalance float64  `json:"final_balance"`
+}
+
+// ClosingStatementLine is a step of the closure as shown in the closing statement
+type ClosingStatementLine struct {
+	Step        ClosureStep `json:"step"`
+	ReferenceID string      `json:"reference_id,omitempty"`
+	Amount      *float64    `json:"amount,omitempty"`
+	Details     string      `json:"details,omitempty"`
+	Date        time.Time   `json:"date"`
+}
+
+// TableName returns the table name for the AccountClosure model
+func (AccountClosure) TableName() string {
+	return "account_closures"
+}
+
+// TableName returns the table name for the AccountClosureEvent model
+func (AccountClosureEvent) TableName() string {
+	return "account_closure_events"
+}
+
+// BeforeCreate is called before creating a new account closure
+func (ac *AccountClosure) BeforeCreate(tx *gorm.DB) error {
+	if ac.ID == "" {
+		ac.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// BeforeCreate is called before creating a new closure event
+func (e *AccountClosureEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// IsPending checks if the closure is still in progress
+func (ac *AccountClosure) IsPending() bool {
+	return ac.Status == AccountClosureStatusPending
+}
+
+// NewEvent creates an audit event for a step of the closure
+func (ac *AccountClosure) NewEvent(step ClosureStep, referenceID string, amount *float64, details, performedBy string) *AccountClosureEvent {
+	return &AccountClosureEvent{
+		ID:          uuid.New().String(),
+		ClosureID:   ac.ID,
+		AccountID:   ac.AccountID,
+		Step:        step,
+		ReferenceID: referenceID,
+		Amount:      amount,
+		Details:     details,
+		PerformedBy: performedBy,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// CheckClosure runs the pre-checks of closing the account with its cards and open installment plans
+func (a *Account) CheckClosure(cards []Card, openPlans []*InstallmentPlan) *ClosureCheck {
+	check := &ClosureCheck{AccountID: a.ID, Balance: a.Balance, Blockers: []ClosureBlocker{}}
+
+	if a.Balance != 0 {
+		description := fmt.Sprintf("account has a balance of %.2f %s", a.Balance, a.Currency)
+		if a.Balance < 0 {
+			description = fmt.Sprintf("account has a negative balance of %.2f %s, cover it from another account", -a.Balance, a.Currency)
+		}
+		check.Blockers = append(check.Blockers, ClosureBlocker{
+			Type:        ClosureBlockerBalance,
+			ReferenceID: a.ID,
+			Description: description,
+			Amount:      a.Balance,
+		})
+	}
+
+	for _, card := range cards {
+		switch {
+		case card.CardType == CardTypeCredit && card.Balance > 0:
+			check.Blockers = append(check.Blockers, ClosureBlocker{
+				Type:        ClosureBlockerCardDebt,
+				ReferenceID: card.ID,
+				Description: fmt.Sprintf("card %s has a debt of %.2f", card.MaskedNumber, card.Balance),
+				Amount:      card.Balance,
+			})
+		case card.CardType == CardTypeCredit && card.Balance < 0:
+			check.Blockers = append(check.Blockers, ClosureBlocker{
+				Type:        ClosureBlockerCardCredit,
+				ReferenceID: card.ID,
+				Description: fmt.Sprintf("card %s has a credit balance of %.2f", card.MaskedNumber, -card.Balance),
+				Amount:      card.Balance,
+			})
+		}
+	}
+
+	for _, plan := range openPlans {
+		check.Blockers = append(check.Blockers, ClosureBlocker{
+			Type:        ClosureBlockerInstallmentPlan,
+			ReferenceID: plan.ID,
+			Description: fmt.Sprintf("%s installment plan %q has %.2f remaining", plan.Status, plan.Description, plan.RemainingAmount),
+			Amount:      plan.RemainingAmount,
+		})
+	}
+
+	check.CanClose = len(check.Blockers) == 0
+	return check
+}
+
+// IsClosed checks if the account went through the closure process
+func (a *Account) IsClosed() bool {
+	return a.ClosedAt != nil
+}
@@ -0,0 +1,161 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MemberRole represents the role a user has on a shared account
+type MemberRole string
+
+const (
+	MemberRoleOwner      MemberRole = "owner"       // Full access, can manage members
+	MemberRoleCoOwner    MemberRole = "co_owner"    // Can view and operate the account and all its cards
+	MemberRoleViewer     MemberRole = "viewer"      // Read-only access
+	MemberRoleCardHolder MemberRole = "card_holder" // Can only see and operate the assigned card
+)
+
+// InternalServicePerformer performs the card operations other FinTrack services request on their
+// own, without a user. Only requests carrying the internal service token act as it.
+const InternalServicePerformer = "internal-service"
+
+// MemberStatus represents the status of an account membership
+type MemberStatus string
+
+const (
+	MemberStatusPending  MemberStatus = "pending"
+	MemberStatusActive   MemberStatus = "active"
+	MemberStatusDeclined MemberStatus = "declined"
+	MemberStatusRevoked  MemberStatus = "revoked"
+)
+
+// AccountMember represents a user that has access to an account owned by another user
+type AccountMember struct {
+	ID        string       `gorm:"type:varchar(36);primaryKey" json:"id"`
+	AccountID string       `gorm:"type:varchar(36);not null;index" json:"account_id"`
+	UserID    string       `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	Role      MemberRole   `gorm:"type:varchar(20);not null" json:"role"`
+	Status    MemberStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+
+	// Card holders are restricted to a single card of the account
+	CardID *string `gorm:"type:varchar(36);null;index" json:"card_id,omitempty"`
+
+	// Invitation tracking
+	InvitedBy  string     `gorm:"type:varchar(36);not null" json:"invited_by"`
+	InvitedAt  time.Time  `gorm:"not null" json:"invited_at"`
+	AcceptedAt *time.Time `gorm:"type:timestamp;null" json:"accepted_at,omitempty"`
+	RevokedAt  *time.Time `gorm:"type:timestamp;null" json:"revoked_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Account Account `gorm:"foreignKey:AccountID" json:"account,omitempty"`
+}
+
+// TableName returns the table name for the AccountMember model
+func (AccountMember) TableName() string {
+	return "account_members"
+}
+
+// BeforeCreate is called before creating a new membership
+func (m *AccountMember) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	if m.InvitedAt.IsZero() {
+		m.InvitedAt = time.Now()
+	}
+	return nil
+}
+
+// IsValidMemberRole checks if the member role is valid
+func IsValidMemberRole(role MemberRole) bool {
+	switch role {
+	case MemberRoleOwner, MemberRoleCoOwner, MemberRoleViewer, MemberRoleCardHolder:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate validates the membership data
+func (m *AccountMember) Validate() error {
+	if m.AccountID == "" {
+		return &ValidationError{Field: "account_id", Message: "account ID is required"}
+	}
+	if m.UserID == "" {
+		return &ValidationError{Field: "user_id", Message: "user ID is required"}
+	}
+	if !IsValidMemberRole(m.Role) {
+		return &ValidationError{Field: "role", Message: "invalid member role"}
+	}
+	if m.Role == MemberRoleCardHolder && (m.CardID == nil || *m.CardID == "") {
+		return &ValidationError{Field: "card_id", Message: "card ID is required for card holders"}
+	}
+	if m.Role != MemberRoleCardHolder && m.CardID != nil {
+		return &ValidationError{Field: "card_id", Message: "card ID can only be set for card holders"}
+	}
+	return nil
+}
+
+// IsActive checks if the membership has been accepted and not revoked
+func (m *AccountMember) IsActive() bool {
+	return m.Status == MemberStatusActive
+}
+
+// IsPending checks if the invitation is waiting for an answer
+func (m *AccountMember) IsPending() bool {
+	return m.Status == MemberStatusPending
+}
+
+// CanManageMembers checks if the member can invite, update or remove other members
+func (m *AccountMember) CanManageMembers() bool {
+	return m.IsActive() && m.Role == MemberRoleOwner
+}
+
+// CanOperateAccount checks if the member can move money using the account and any of its cards
+func (m *AccountMember) CanOperateAccount() bool {
+	return m.IsActive() && (m.Role == MemberRoleOwner || m.Role == MemberRoleCoOwner)
+}
+
+// CanUseCard checks if the member can operate the given card
+func (m *AccountMember) CanUseCard(cardID string) bool {
+	if m.CanOperateAccount() {
+		return true
+	}
+	return m.IsActive() && m.Role == MemberRoleCardHolder && m.CardID != nil && *m.CardID == cardID
+}
+
+// Accept marks the invitation as accepted
+func (m *AccountMember) Accept() error {
+	if !m.IsPending() {
+		return &ValidationError{Field: "status", Message: "only pending invitations can be accepted"}
+	}
+	now := time.Now()
+	m.Status = MemberStatusActive
+	m.AcceptedAt = &now
+	return nil
+}
+
+// Decline marks the invitation as declined
+func (m *AccountMember) Decline() error {
+	if !m.IsPending() {
+		return &ValidationError{Field: "status", Message: "only pending invitations can be declined"}
+	}
+	m.Status = MemberStatusDeclined
+	return nil
+}
+
+// Revoke removes the member access to the account
+func (m *AccountMember) Revoke() error {
+	if m.Status != MemberStatusPending && m.Status != MemberStatusActive {
+		return &ValidationError{Field: "status", Message: "membership is not active"}
+	}
+	now := time.Now()
+	m.Status = MemberStatusRevoked
+	m.RevokedAt = &now
+	return nil
+}
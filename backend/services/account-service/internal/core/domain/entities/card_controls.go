@@ -0,0 +1,186 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CardControlReason identifies why a card operation was rejected by its spending controls
+type CardControlReason string
+
+const (
+	CardControlReasonFrozen             CardControlReason = "card_frozen"
+	CardControlReasonMerchantBlocked    CardControlReason = "merchant_blocked"
+	CardControlReasonCategoryBlocked    CardControlReason = "category_blocked"
+	CardControlReasonOnlineDisabled     CardControlReason = "online_purchases_disabled"
+	CardControlReasonAmountExceeded     CardControlReason = "transaction_limit_exceeded"
+	CardControlReasonCurrencyNotAllowed CardControlReason = "currency_not_allowed"
+)
+
+// PurchaseDetails describes a card operation for the evaluation of spending controls
+type PurchaseDetails struct {
+	MerchantName string
+	Category     string
+	Currency     Currency // Defaults to the account currency when empty
+	IsOnline     bool
+}
+
+// CardControls represents the fine-grained spending controls of a card
+type CardControls struct {
+	ID     string `gorm:"type:varchar(36);primaryKey" json:"id"`
+	CardID string `gorm:"type:varchar(36);not null;uniqueIndex" json:"card_id"`
+
+	// Blocked merchants match by name (case-insensitive, partial) and categories exactly (case-insensitive)
+	BlockedMerchants  []string `gorm:"type:json;serializer:json" json:"blocked_merchants"`
+	BlockedCategories []string `gorm:"type:json;serializer:json" json:"blocked_categories"`
+
+	// Online purchases are allowed unless explicitly blocked
+	OnlinePurchasesBlocked bool `gorm:"not null;default:false" json:"online_purchases_blocked"`
+
+	// Maximum amount of a single operation (nil = no cap)
+	MaxTransactionAmount *float64 `gorm:"type:decimal(15,2);null" json:"max_transaction_amount,omitempty"`
+
+	// Currencies the card can operate in (empty = any)
+	AllowedCurrencies []string `gorm:"type:json;serializer:json" json:"allowed_currencies"`
+
+	// Temporary freeze, lifted automatically once FrozenUntil passes
+	FrozenUntil  *time.Time `gorm:"type:timestamp;null" json:"frozen_until,omitempty"`
+	FreezeReason string     `gorm:"type:varchar(255)" json:"freeze_reason,omitempty"`
+
+	UpdatedBy string    `gorm:"type:varchar(36)" json:"updated_by,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CardControlRejection records an operation rejected by the spending controls of a card
+type CardControlRejection struct {
+	ID           string            `gorm:"type:varchar(36);primaryKey" json:"id"`
+	CardID       string            `gorm:"type:varchar(36);not null;index" json:"card_id"`
+	Reason       CardControlReason `gorm:"type:varchar(50);not null;index" json:"reason"`
+	Message      string            `gorm:"type:varchar(255);not null" json:"message"`
+	Amount       float64           `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Currency     Currency          `gorm:"type:varchar(3)" json:"currency,omitempty"`
+	MerchantName string            `gorm:"type:varchar(255)" json:"merchant_name,omitempty"`
+	Category     string            `gorm:"type:varchar(100)" json:"category,omitempty"`
+	IsOnline     bool              `gorm:"not null;default:false" json:"is_online"`
+	AttemptedBy  string            `gorm:"type:varchar(36)" json:"attempted_by,omitempty"`
+	CreatedAt    time.Time         `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName returns the table name for the CardControls model
+func (CardControls) TableName() string {
+	return "card_controls"
+}
+
+// TableName returns the table name for the CardControlRejection model
+func (CardControlRejection) TableName() string {
+	return "card_control_rejections"
+}
+
+// BeforeCreate is called before creating new card controls
+func (cc *CardControls) BeforeCreate(tx *gorm.DB) error {
+	if cc.ID == "" {
+		cc.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// BeforeCreate is called before creating a new rejection record
+func (r *CardControlRejection) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Validate validates the card controls data
+func (cc *CardControls) Validate() error {
+	if cc.CardID == "" {
+		return &ValidationError{Field: "card_id", Message: "card ID is required"}
+	}
+	if cc.MaxTransactionAmount != nil && *cc.MaxTransactionAmount <= 0 {
+		return &ValidationError{Field: "max_transaction_amount", Message: "max transaction amount must be positive"}
+	}
+	for _, currency := range cc.AllowedCurrencies {
+		if !IsValidCurrency(Currency(currency)) {
+			return &ValidationError{Field: "allowed_currencies", Message: fmt.Sprintf("invalid currency: %s", currency)}
+		}
+	}
+	return nil
+}
+
+// IsFrozen checks if the card is temporarily frozen at the given time
+func (cc *CardControls) IsFrozen(now time.Time) bool {
+	return cc.FrozenUntil != nil && now.Before(*cc.FrozenUntil)
+}
+
+// FreezeExpired checks if a freeze was set and already passed, so it can be cleared
+func (cc *CardControls) FreezeExpired(now time.Time) bool {
+	return cc.FrozenUntil != nil && !now.Before(*cc.FrozenUntil)
+}
+
+// Freeze freezes the card until the given time
+func (cc *CardControls) Freeze(until time.Time, reason string, now time.Time) error {
+	if !until.After(now) {
+		return &ValidationError{Field: "frozen_until", Message: "freeze end must be in the future"}
+	}
+	cc.FrozenUntil = &until
+	cc.FreezeReason = reason
+	return nil
+}
+
+// Unfreeze lifts the temporary freeze of the card
+func (cc *CardControls) Unfreeze() {
+	cc.FrozenUntil = nil
+	cc.FreezeReason = ""
+}
+
+// Evaluate checks an operation against the controls, returning the rejection reason and
+// message, or an empty reason when the operation is allowed
+func (cc *CardControls) Evaluate(amount float64, purchase PurchaseDetails, now time.Time) (CardControlReason, string) {
+	if cc.IsFrozen(now) {
+		return CardControlReasonFrozen, fmt.Sprintf("card is frozen until %s", cc.FrozenUntil.Format(time.RFC3339))
+	}
+
+	if purchase.IsOnline && cc.OnlinePurchasesBlocked {
+		return CardControlReasonOnlineDisabled, "online purchases are disabled for this card"
+	}
+
+	if cc.MaxTransactionAmount != nil && amount > *cc.MaxTransactionAmount {
+		return CardControlReasonAmountExceeded,
+			fmt.Sprintf("amount %.2f exceeds the per-transaction limit of %.2f", amount, *cc.MaxTransactionAmount)
+	}
+
+	if len(cc.AllowedCurrencies) > 0 && !containsFold(cc.AllowedCurrencies, string(purchase.Currency)) {
+		return CardControlReasonCurrencyNotAllowed, fmt.Sprintf("currency %s is not allowed for this card", purchase.Currency)
+	}
+
+	if merchant := strings.ToLower(strings.TrimSpace(purchase.MerchantName)); merchant != "" {
+		for _, blocked := range cc.BlockedMerchants {
+			if blocked = strings.ToLower(strings.TrimSpace(blocked)); blocked != "" && strings.Contains(merchant, blocked) {
+				return CardControlReasonMerchantBlocked, fmt.Sprintf("merchant %s is blocked for this card", purchase.MerchantName)
+			}
+		}
+	}
+
+	if purchase.Category != "" && containsFold(cc.BlockedCategories, purchase.Category) {
+		return CardControlReasonCategoryBlocked, fmt.Sprintf("category %s is blocked for this card", purchase.Category)
+	}
+
+	return "", ""
+}
+
+// containsFold checks if the list contains the value ignoring case and surrounding spaces
+func containsFold(values []string, value string) bool {
+	value = strings.TrimSpace(value)
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
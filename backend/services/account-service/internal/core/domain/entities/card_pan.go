@@ -0,0 +1,176 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PAN is a full card number as sent by the client. It formats and marshals masked,
+// so it never reaches logs or responses; use Digits to read the number.
+type PAN string
+
+// Digits returns the card number without separators
+func (p PAN) Digits() string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(string(p))
+}
+
+// Format implements fmt.Formatter so that every verb prints the masked number
+func (p PAN) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, maskPAN(p.Digits()))
+}
+
+// MarshalJSON marshals the masked number
+func (p PAN) MarshalJSON() ([]byte, error) {
+	return json.Marshal(maskPAN(p.Digits()))
+}
+
+// CardNumberDetails holds what the service derives from a valid card number
+type CardNumberDetails struct {
+	Number         string
+	Brand          CardBrand
+	Type           CardType // Empty when the BIN table does not know the card type
+	LastFourDigits string
+	MaskedNumber   string
+}
+
+// binRange maps an issuer identification number range to its brand, card type and lengths.
+// From and To have the same number of digits; the longest matching range wins.
+type binRange struct {
+	From    string
+	To      string
+	Brand   CardBrand
+	Type    CardType
+	Lengths []int
+}
+
+// binRanges is the local BIN table used to detect brand and card type
+var binRanges = []binRange{
+	// Visa, with Visa Electron debit ranges
+	{From: "4", To: "4", Brand: CardBrandVisa, Lengths: []int{13, 16, 19}},
+	{From: "4026", To: "4026", Brand: CardBrandVisa, Type: CardTypeDebit, Lengths: []int{16}},
+	{From: "417500", To: "417500", Brand: CardBrandVisa, Type: CardTypeDebit, Lengths: []int{16}},
+	{From: "4508", To: "4508", Brand: CardBrandVisa, Type: CardTypeDebit, Lengths: []int{16}},
+	{From: "4844", To: "4844", Brand: CardBrandVisa, Type: CardTypeDebit, Lengths: []int{16}},
+	{From: "4913", To: "4913", Brand: CardBrandVisa, Type: CardTypeDebit, Lengths: []int{16}},
+	{From: "4917", To: "4917", Brand: CardBrandVisa, Type: CardTypeDebit, Lengths: []int{16}},
+
+	// Mastercard
+	{From: "51", To: "55", Brand: CardBrandMastercard, Lengths: []int{16}},
+	{From: "2221", To: "2720", Brand: CardBrandMastercard, Lengths: []int{16}},
+
+	// American Express (credit only)
+	{From: "34", To: "34", Brand: CardBrandAmex, Type: CardTypeCredit, Lengths: []int{15}},
+	{From: "37", To: "37", Brand: CardBrandAmex, Type: CardTypeCredit, Lengths: []int{15}},
+
+	// Diners Club (credit only)
+	{From: "300", To: "305", Brand: CardBrandDiners, Type: CardTypeCredit, Lengths: []int{14, 16}},
+	{From: "36", To: "36", Brand: CardBrandDiners, Type: CardTypeCredit, Lengths: []int{14, 16}},
+	{From: "38", To: "39", Brand: CardBrandDiners, Type: CardTypeCredit, Lengths: []int{14, 16}},
+
+	// Discover
+	{From: "6011", To: "6011", Brand: CardBrandDiscover, Lengths: []int{16, 19}},
+	{From: "644", To: "649", Brand: CardBrandDiscover, Lengths: []int{16, 19}},
+	{From: "65", To: "65", Brand: CardBrandDiscover, Lengths: []int{16, 19}},
+
+	// Maestro (debit only)
+	{From: "5018", To: "5018", Brand: CardBrandOther, Type: CardTypeDebit, Lengths: []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{From: "5020", To: "5020", Brand: CardBrandOther, Type: CardTypeDebit, Lengths: []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{From: "5038", To: "5038", Brand: CardBrandOther, Type: CardTypeDebit, Lengths: []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{From: "5893", To: "5893", Brand: CardBrandOther, Type: CardTypeDebit, Lengths: []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{From: "6304", To: "6304", Brand: CardBrandOther, Type: CardTypeDebit, Lengths: []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{From: "6759", To: "6759", Brand: CardBrandOther, Type: CardTypeDebit, Lengths: []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{From: "6761", To: "6763", Brand: CardBrandOther, Type: CardTypeDebit, Lengths: []int{12, 13, 14, 15, 16, 17, 18, 19}},
+
+	// Local brands: Naranja (credit) and Cabal
+	{From: "589562", To: "589562", Brand: CardBrandOther, Type: CardTypeCredit, Lengths: []int{16}},
+	{From: "604201", To: "604219", Brand: CardBrandOther, Lengths: []int{16}},
+}
+
+// ParseCardNumber validates a full card number (digits, length and Luhn checksum) and
+// derives its brand, card type, last four digits and masked number. Errors never include the number.
+func ParseCardNumber(pan PAN) (*CardNumberDetails, error) {
+	number := pan.Digits()
+	if len(number) < 12 || len(number) > 19 {
+		return nil, &ValidationError{Field: "card_number", Message: "card number must have between 12 and 19 digits"}
+	}
+	for _, digit := range number {
+		if digit < '0' || digit > '9' {
+			return nil, &ValidationError{Field: "card_number", Message: "card number must contain only digits"}
+		}
+	}
+	if !IsValidLuhn(number) {
+		return nil, &ValidationError{Field: "card_number", Message: "invalid card number"}
+	}
+
+	details := &CardNumberDetails{
+		Number:         number,
+		Brand:          CardBrandOther,
+		LastFourDigits: number[len(number)-4:],
+		MaskedNumber:   maskPAN(number),
+	}
+
+	if bin := lookupBIN(number); bin != nil {
+		if !containsLength(bin.Lengths, len(number)) {
+			return nil, &ValidationError{Field: "card_number", Message: fmt.Sprintf("invalid card number length for %s", bin.Brand)}
+		}
+		details.Brand = bin.Brand
+		details.Type = bin.Type
+	}
+
+	return details, nil
+}
+
+// IsValidLuhn checks the Luhn checksum of a string of digits
+func IsValidLuhn(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// lookupBIN finds the longest BIN range matching the number
+func lookupBIN(number string) *binRange {
+	var match *binRange
+	for i := range binRanges {
+		bin := &binRanges[i]
+		if len(number) < len(bin.From) {
+			continue
+		}
+		prefix := number[:len(bin.From)]
+		if prefix < bin.From || prefix > bin.To {
+			continue
+		}
+		if match == nil || len(bin.From) > len(match.From) {
+			match = bin
+		}
+	}
+	return match
+}
+
+func containsLength(lengths []int, length int) bool {
+	for _, l := range lengths {
+		if l == length {
+			return true
+		}
+	}
+	return false
+}
+
+// maskPAN masks every digit but the last four, as **** **** **** 1234
+func maskPAN(number string) string {
+	if len(number) < 4 {
+		return "****"
+	}
+	return "**** **** **** " + number[len(number)-4:]
+}
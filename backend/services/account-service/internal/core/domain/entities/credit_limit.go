@@ -0,0 +1,120 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreditLimitTarget identifies what a credit limit applies to
+type CreditLimitTarget string
+
+const (
+	CreditLimitTargetAccount CreditLimitTarget = "account" // Credit card accounts
+	CreditLimitTargetCard    CreditLimitTarget = "card"    // Credit cards with their own limit
+)
+
+// CreditLimitChangeStatus represents the status of a credit limit change request
+type CreditLimitChangeStatus string
+
+const (
+	CreditLimitChangeStatusPending  CreditLimitChangeStatus = "pending"  // Waiting for a treasurer
+	CreditLimitChangeStatusApplied  CreditLimitChangeStatus = "applied"  // The new limit is in effect
+	CreditLimitChangeStatusRejected CreditLimitChangeStatus = "rejected" // By a treasurer or by the debt floor
+)
+
+// CreditLimitChangeRequest is a request to increase or decrease the credit limit of an account or card
+type CreditLimitChangeRequest struct {
+	ID         string            `gorm:"type:varchar(36);primaryKey" json:"id"`
+	AccountID  string            `gorm:"type:varchar(36);not null;index" json:"account_id"`
+	CardID     *string           `gorm:"type:varchar(36);null;index" json:"card_id,omitempty"`
+	TargetType CreditLimitTarget `gorm:"type:varchar(10);not null" json:"target_type"`
+
+	PreviousLimit  float64 `gorm:"type:decimal(15,2);not null" json:"previous_limit"`
+	RequestedLimit float64 `gorm:"type:decimal(15,2);not null" json:"requested_limit"`
+	Reason         string  `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	RequestedBy    string  `gorm:"type:varchar(36);not null" json:"requested_by"`
+
+	Status           CreditLimitChangeStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	RequiresApproval bool                    `gorm:"not null;default:false" json:"requires_approval"`
+	ReviewedBy       string                  `gorm:"type:varchar(36)" json:"reviewed_by,omitempty"`
+	ReviewNote       string                  `gorm:"type:varchar(255)" json:"review_note,omitempty"`
+	ReviewedAt       *time.Time              `gorm:"type:timestamp;null" json:"reviewed_at,omitempty"`
+	AppliedAt        *time.Time              `gorm:"type:timestamp;null" json:"applied_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CreditLimitHistory is a credit limit that applied to an account or card during a period.
+// The entry in effect has no EffectiveTo.
+type CreditLimitHistory struct {
+	ID              string            `gorm:"type:varchar(36);primaryKey" json:"id"`
+	AccountID       string            `gorm:"type:varchar(36);not null;index" json:"account_id"`
+	CardID          *string           `gorm:"type:varchar(36);null;index" json:"card_id,omitempty"`
+	TargetType      CreditLimitTarget `gorm:"type:varchar(10);not null" json:"target_type"`
+	CreditLimit     float64           `gorm:"type:decimal(15,2);not null" json:"credit_limit"`
+	ChangeRequestID *string           `gorm:"type:varchar(36);null" json:"change_request_id,omitempty"` // Empty for the limit set before the history started
+	ChangedBy       string            `gorm:"type:varchar(36)" json:"changed_by,omitempty"`
+	EffectiveFrom   time.Time         `gorm:"type:timestamp;not null;index" json:"effective_from"`
+	EffectiveTo     *time.Time        `gorm:"type:timestamp;null" json:"effective_to,omitempty"`
+	CreatedAt       time.Time         `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName returns the table name for the CreditLimitChangeRequest model
+func (CreditLimitChangeRequest) TableName() string {
+	return "credit_limit_change_requests"
+}
+
+// TableName returns the table name for the CreditLimitHistory model
+func (CreditLimitHistory) TableName() string {
+	return "credit_limit_history"
+}
+
+// BeforeCreate is called before creating a new change request
+func (r *CreditLimitChangeRequest) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// BeforeCreate is called before creating a new history entry
+func (h *CreditLimitHistory) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == "" {
+		h.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// IsPending checks if the request waits for a treasurer
+func (r *CreditLimitChangeRequest) IsPending() bool {
+	return r.Status == CreditLimitChangeStatusPending
+}
+
+// IsIncrease checks if the request raises the limit
+func (r *CreditLimitChangeRequest) IsIncrease() bool {
+	return r.RequestedLimit > r.PreviousLimit
+}
+
+// Increase returns how much the request raises the limit, zero for decreases
+func (r *CreditLimitChangeRequest) Increase() float64 {
+	if !r.IsIncrease() {
+		return 0
+	}
+	return r.RequestedLimit - r.PreviousLimit
+}
+
+// AppliesAt checks if the limit was in effect at the given time
+func (h *CreditLimitHistory) AppliesAt(at time.Time) bool {
+	return !h.EffectiveFrom.After(at) && (h.EffectiveTo == nil || h.EffectiveTo.After(at))
+}
+
+// GetUsedCredit returns the credit used by a credit card account: its negative balance
+func (a *Account) GetUsedCredit() float64 {
+	if a.AccountType != AccountTypeCredit || a.Balance >= 0 {
+		return 0
+	}
+	return -a.Balance
+}
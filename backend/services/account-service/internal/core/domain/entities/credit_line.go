@@ -0,0 +1,116 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreditLine represents a credit limit granted at the account level and shared by several
+// credit cards, such as a primary card and its additional cards (extensiones)
+type CreditLine struct {
+	ID         string  `gorm:"type:varchar(36);primaryKey" json:"id"`
+	AccountID  string  `gorm:"type:varchar(36);not null;index" json:"account_id"`
+	Name       string  `gorm:"type:varchar(100);not null" json:"name"`
+	TotalLimit float64 `gorm:"type:decimal(15,2);not null" json:"total_limit"`
+
+	// Cards drawing from the line (loaded by the repository when needed)
+	Cards []Card `gorm:"foreignKey:CreditLineID" json:"cards,omitempty"`
+
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relationship back to account
+	Account Account `gorm:"foreignKey:AccountID" json:"-"`
+}
+
+// TableName returns the table name for the CreditLine model
+func (CreditLine) TableName() string {
+	return "credit_lines"
+}
+
+// BeforeCreate is called before creating a new credit line
+func (cl *CreditLine) BeforeCreate(tx *gorm.DB) error {
+	if cl.ID == "" {
+		cl.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Validate validates the credit line data
+func (cl *CreditLine) Validate() error {
+	if cl.AccountID == "" {
+		return &ValidationError{Field: "account_id", Message: "account ID is required"}
+	}
+	if cl.Name == "" {
+		return &ValidationError{Field: "name", Message: "name is required"}
+	}
+	if cl.TotalLimit <= 0 {
+		return &ValidationError{Field: "total_limit", Message: "total limit must be positive"}
+	}
+	if cl.TotalLimit > 10000000 { // 10 million max, same as card limits
+		return &ValidationError{Field: "total_limit", Message: "total limit cannot exceed 10,000,000"}
+	}
+	return nil
+}
+
+// GetUsedCredit returns the debt of all the cards of the line.
+// current, when given, replaces the loaded copy of the same card so pending changes are considered.
+func (cl *CreditLine) GetUsedCredit(current *Card) float64 {
+	var used float64
+	cl.eachCard(current, func(card *Card) {
+		used += card.GetDebt()
+	})
+	return used
+}
+
+// GetTotalInstallmentCommitments returns what is still owed on the installment plans of all the cards of the line
+func (cl *CreditLine) GetTotalInstallmentCommitments(current *Card) float64 {
+	var total float64
+	cl.eachCard(current, func(card *Card) {
+		total += card.GetTotalInstallmentCommitments()
+	})
+	return total
+}
+
+// GetAvailableCredit returns the credit left on the line
+func (cl *CreditLine) GetAvailableCredit(current *Card) float64 {
+	return cl.TotalLimit - cl.GetUsedCredit(current)
+}
+
+// GetCommittedCredit returns the credit held by all the cards of the line: for each card, its debt or,
+// when higher, what is still owed on its installment plans
+func (cl *CreditLine) GetCommittedCredit(current *Card) float64 {
+	var committed float64
+	cl.eachCard(current, func(card *Card) {
+		committed += card.GetCommittedCredit()
+	})
+	return committed
+}
+
+// GetAvailableCreditWithInstallments returns the credit left on the line considering
+// the installment commitments of every card
+func (cl *CreditLine) GetAvailableCreditWithInstallments(current *Card) float64 {
+	return cl.TotalLimit - cl.GetCommittedCredit(current)
+}
+
+// eachCard iterates the credit cards of the line, using current instead of its loaded copy.
+// Blocked cards are included since their debt still counts against the line.
+func (cl *CreditLine) eachCard(current *Card, fn func(card *Card)) {
+	currentVisited := false
+	for i := range cl.Cards {
+		card := &cl.Cards[i]
+		if current != nil && card.ID == current.ID {
+			card = current
+			currentVisited = true
+		}
+		if card.CardType == CardTypeCredit {
+			fn(card)
+		}
+	}
+	if current != nil && !currentVisited && current.CardType == CardTypeCredit {
+		fn(current)
+	}
+}
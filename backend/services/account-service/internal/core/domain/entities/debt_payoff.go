@@ -0,0 +1,167 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DebtPayoffStrategy decides which debt receives the budget left after the minimum payments
+type DebtPayoffStrategy string
+
+const (
+	DebtPayoffStrategyAvalanche DebtPayoffStrategy = "avalanche" // Highest interest rate first: the cheapest way out
+	DebtPayoffStrategySnowball  DebtPayoffStrategy = "snowball"  // Smallest balance first: the first debts go away sooner
+	DebtPayoffStrategyCustom    DebtPayoffStrategy = "custom"    // Order chosen by the user
+)
+
+// DebtType identifies what a debt of a payoff plan is
+type DebtType string
+
+const (
+	DebtTypeCard            DebtType = "card"             // Revolving balance of a credit card, without its installment plans
+	DebtTypeInstallmentPlan DebtType = "installment_plan" // Remaining installments of a plan
+)
+
+// DebtPayoffPlanStatus represents the status of a saved payoff plan
+type DebtPayoffPlanStatus string
+
+const (
+	DebtPayoffPlanStatusActive    DebtPayoffPlanStatus = "active"    // The plan the user follows
+	DebtPayoffPlanStatusCompleted DebtPayoffPlanStatus = "completed" // Every debt of the plan was paid off
+	DebtPayoffPlanStatusAbandoned DebtPayoffPlanStatus = "abandoned" // Replaced by another plan or dropped by the user
+)
+
+// DebtPayoffOptions are the inputs of a payoff simulation
+type DebtPayoffOptions struct {
+	Strategy        DebtPayoffStrategy
+	MonthlyBudget   float64
+	Currency        Currency
+	CustomOrder     []string // Debt IDs in payoff order, for the custom strategy
+	CardMonthlyRate *float64 // Monthly interest rate (percent) of the card balances; the configured one when nil
+}
+
+// PayoffDebt is a debt at the start of a payoff simulation
+type PayoffDebt struct {
+	ID             string   `json:"id"` // Card ID or installment plan ID
+	Type           DebtType `json:"type"`
+	CardID         string   `json:"card_id"`
+	Name           string   `json:"name"`
+	Balance        float64  `json:"balance"`      // Installment plans include the interest of the remaining installments
+	MonthlyRate    float64  `json:"monthly_rate"` // Percent; for installment plans, the equivalent of their flat rate
+	MinimumPayment float64  `json:"minimum_payment"`
+	PaidOffMonth   int      `json:"paid_off_month"`
+}
+
+// PayoffPayment is what a debt receives in a month of the schedule
+type PayoffPayment struct {
+	DebtID   string  `json:"debt_id"`
+	Amount   float64 `json:"amount"`
+	Interest float64 `json:"interest"` // Card interest accrued after the payment, or the interest part of an installment
+	Balance  float64 `json:"balance"`  // Left after the payment and the interest
+}
+
+// PayoffMonth is a month of the payoff schedule
+type PayoffMonth struct {
+	Month            int             `json:"month"`
+	Date             time.Time       `json:"date"`
+	Payment          float64         `json:"payment"`
+	Interest         float64         `json:"interest"`
+	RemainingBalance float64         `json:"remaining_balance"`
+	Payments         []PayoffPayment `json:"payments"`
+}
+
+// DebtPayoffSimulation is the month by month schedule to pay off the debts of a user with a strategy
+type DebtPayoffSimulation struct {
+	Strategy      DebtPayoffStrategy `gorm:"type:varchar(20);not null" json:"strategy"`
+	MonthlyBudget float64            `gorm:"type:decimal(15,2);not null" json:"monthly_budget"`
+	Currency      Currency           `gorm:"type:varchar(3);not null" json:"currency"`
+	CustomOrder   []string           `gorm:"type:json;serializer:json" json:"custom_order,omitempty"`
+	CardRate      float64            `gorm:"type:decimal(5,2);not null" json:"card_monthly_rate"`
+
+	StartingDebt  float64   `gorm:"type:decimal(15,2);not null" json:"starting_debt"`
+	TotalInterest float64   `gorm:"type:decimal(15,2);not null" json:"total_interest"`
+	TotalPaid     float64   `gorm:"type:decimal(15,2);not null" json:"total_paid"`
+	Months        int       `gorm:"type:int;not null" json:"months"`
+	StartDate     time.Time `gorm:"type:timestamp;not null" json:"start_date"`
+	PayoffDate    time.Time `gorm:"type:timestamp;not null" json:"payoff_date"`
+
+	Debts    []PayoffDebt  `gorm:"type:json;serializer:json" json:"debts"`
+	Schedule []PayoffMonth `gorm:"type:json;serializer:json" json:"schedule"`
+}
+
+// DebtPayoffComparison compares the strategies for the same budget
+type DebtPayoffComparison struct {
+	Cheapest    DebtPayoffStrategy     `json:"cheapest"` // Least interest paid
+	Fastest     DebtPayoffStrategy     `json:"fastest"`  // Earliest payoff date
+	Simulations []DebtPayoffSimulation `json:"simulations"`
+}
+
+// DebtPayoffPlan is a payoff simulation chosen by the user, kept to track their progress against it
+type DebtPayoffPlan struct {
+	ID     string `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID string `gorm:"type:varchar(36);not null;index" json:"user_id"`
+
+	DebtPayoffSimulation `gorm:"embedded"`
+
+	Status      DebtPayoffPlanStatus `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	CompletedAt *time.Time           `gorm:"type:timestamp;null" json:"completed_at,omitempty"`
+	AbandonedAt *time.Time           `gorm:"type:timestamp;null" json:"abandoned_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// DebtProgress compares a debt of a plan with its current balance
+type DebtProgress struct {
+	DebtID         string   `json:"debt_id"`
+	Type           DebtType `json:"type"`
+	Name           string   `json:"name"`
+	PlannedBalance float64  `json:"planned_balance"`
+	ActualBalance  float64  `json:"actual_balance"`
+	PaidOff        bool     `json:"paid_off"`
+}
+
+// DebtPayoffProgress compares the current debts with where the plan expected them to be
+type DebtPayoffProgress struct {
+	PlanID          string               `json:"plan_id"`
+	Status          DebtPayoffPlanStatus `json:"status"`
+	MonthsElapsed   int                  `json:"months_elapsed"`
+	StartingDebt    float64              `json:"starting_debt"`
+	PlannedBalance  float64              `json:"planned_balance"`
+	ActualBalance   float64              `json:"actual_balance"`
+	Difference      float64              `json:"difference"` // Actual minus planned: positive means behind the plan
+	OnTrack         bool                 `json:"on_track"`
+	ProgressPercent float64              `json:"progress_percent"` // Share of the starting debt already paid
+	NewDebt         float64              `json:"new_debt"`         // Debts taken after the plan was saved, not part of it
+	PayoffDate      time.Time            `json:"payoff_date"`
+	Debts           []DebtProgress       `json:"debts"`
+}
+
+// TableName returns the table name for the DebtPayoffPlan model
+func (DebtPayoffPlan) TableName() string {
+	return "debt_payoff_plans"
+}
+
+// BeforeCreate is called before creating a new payoff plan
+func (p *DebtPayoffPlan) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// IsActive reports whether the plan is the one the user follows
+func (p *DebtPayoffPlan) IsActive() bool {
+	return p.Status == DebtPayoffPlanStatusActive
+}
+
+// IsValidDebtPayoffStrategy checks if the payoff strategy is valid
+func IsValidDebtPayoffStrategy(strategy DebtPayoffStrategy) bool {
+	switch strategy {
+	case DebtPayoffStrategyAvalanche, DebtPayoffStrategySnowball, DebtPayoffStrategyCustom:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InflationSource tells where the monthly inflation of a cash comparison came from
+type InflationSource string
+
+const (
+	InflationSourceExpectation InflationSource = "expectation" // Monthly rate given with the comparison
+	InflationSourceSeries      InflationSource = "series"      // Stored index series
+	InflationSourceDefault     InflationSource = "default"     // Configured expectation
+)
+
+// PaymentOptionType identifies how a purchase is paid
+type PaymentOptionType string
+
+const (
+	PaymentOptionCash         PaymentOptionType = "cash"
+	PaymentOptionInstallments PaymentOptionType = "installments"
+)
+
+// InflationRate is the monthly inflation of a month in a stored index series, such as the
+// official CPI or the market expectations survey
+type InflationRate struct {
+	ID          string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Series      string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_inflation_rates_series_period" json:"series"`
+	Period      time.Time `gorm:"type:date;not null;uniqueIndex:idx_inflation_rates_series_period" json:"period"` // First day of the month
+	MonthlyRate float64   `gorm:"type:decimal(7,4);not null" json:"monthly_rate"`                                 // Percent
+	UpdatedBy   string    `gorm:"type:varchar(36)" json:"updated_by,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// InstallmentOption is a way to pay a purchase in installments
+type InstallmentOption struct {
+	InstallmentsCount int
+	InterestRate      float64 // Percent over the price, as in the installment plan preview; 0 is "sin interés"
+}
+
+// CashComparisonOptions are the inputs of a cash versus installments comparison
+type CashComparisonOptions struct {
+	Price            float64
+	CashDiscount     float64 // Percent off the price for paying cash
+	Installments     []InstallmentOption
+	FirstDueDate     *time.Time // Due date of the first installment; a month from now when nil
+	MonthlyInflation *float64   // Percent; takes precedence over the series
+	InflationSeries  string
+}
+
+// MonthlyInflation is the inflation a comparison used for a month
+type MonthlyInflation struct {
+	Period time.Time `json:"period"`
+	Rate   float64   `json:"rate"` // Percent
+}
+
+// DiscountedPayment is a payment of an option brought to today's money
+type DiscountedPayment struct {
+	Number         int       `json:"number"`
+	DueDate        time.Time `json:"due_date"`
+	Amount         float64   `json:"amount"`
+	DiscountFactor float64   `json:"discount_factor"`
+	PresentValue   float64   `json:"present_value"`
+}
+
+// PaymentOption is cash or a number of installments with its cost in today's money
+type PaymentOption struct {
+	Name               string              `json:"name"`
+	Type               PaymentOptionType   `json:"type"`
+	InstallmentsCount  int                 `json:"installments_count,omitempty"`
+	InterestRate       float64             `json:"interest_rate"`
+	InstallmentAmount  float64             `json:"installment_amount,omitempty"`
+	TotalToPay         float64             `json:"total_to_pay"`
+	PresentValue       float64             `json:"present_value"`
+	DifferenceVsCash   float64             `json:"difference_vs_cash"`             // Present value minus the cash price: negative beats cash
+	BreakEvenInflation *float64            `json:"break_even_inflation,omitempty"` // Monthly inflation (percent) at which it costs the same as cash
+	Best               bool                `json:"best"`
+	Payments           []DiscountedPayment `json:"payments"`
+}
+
+// CashComparison compares paying a purchase in cash against paying it in installments, discounting
+// each installment with the expected inflation
+type CashComparison struct {
+	Price            float64            `json:"price"`
+	CashDiscount     float64            `json:"cash_discount"`
+	CashPrice        float64            `json:"cash_price"`
+	InflationSource  InflationSource    `json:"inflation_source"`
+	InflationSeries  string             `json:"inflation_series,omitempty"`
+	MonthlyInflation []MonthlyInflation `json:"monthly_inflation"`
+	Options          []PaymentOption    `json:"options"`
+	BestOption       string             `json:"best_option"`
+	SavingVsCash     float64            `json:"saving_vs_cash"` // Present value the best option saves against paying cash
+}
+
+// TableName returns the table name for the InflationRate model
+func (InflationRate) TableName() string {
+	return "inflation_rates"
+}
+
+// BeforeCreate is called before creating a new inflation rate
+func (r *InflationRate) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -124,3 +124,27 @@ func IsBusinessLogicError(err error) bool {
 func IsPermissionError(err error) bool {
 	return err == ErrUnauthorized || err == ErrInsufficientRights
 }
+
+// CardControlError represents an operation rejected by the spending controls of a card
+type CardControlError struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+func (e *CardControlError) Error() string {
+	return e.Message
+}
+
+// NewCardControlError creates a card control rejection with its reason code
+func NewCardControlError(reason, message string) *CardControlError {
+	return &CardControlError{
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// IsCardControlError checks if the error is a card control rejection
+func IsCardControlError(err error) bool {
+	_, ok := err.(*CardControlError)
+	return ok
+}
@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/card/dto"
+)
+
+// CardControlsServiceInterface defines the contract for card spending controls operations
+type CardControlsServiceInterface interface {
+	// Controls management
+	GetControls(cardID, userID string) (*entities.CardControls, error)
+	UpdateControls(cardID, userID string, req *dto.UpdateCardControlsRequest) (*entities.CardControls, error)
+	FreezeCard(cardID, userID string, until time.Time, reason string) (*entities.CardControls, error)
+	UnfreezeCard(cardID, userID string) (*entities.CardControls, error)
+	GetRejections(cardID, userID string, page, pageSize int) ([]*entities.CardControlRejection, int64, error)
+
+	// Evaluation of card operations, returning a *errors.CardControlError when rejected
+	EvaluateOperation(card *entities.Card, amount float64, purchase entities.PurchaseDetails, attemptedBy string) error
+}
+
+// CardControlsRepositoryInterface defines the contract for card controls repository operations
+type CardControlsRepositoryInterface interface {
+	GetByCard(cardID string) (*entities.CardControls, error) // Returns nil when the card has no controls
+	Save(controls *entities.CardControls) (*entities.CardControls, error)
+	CreateRejection(rejection *entities.CardControlRejection) error
+	GetRejectionsByCard(cardID string, limit, offset int) ([]*entities.CardControlRejection, int64, error)
+}
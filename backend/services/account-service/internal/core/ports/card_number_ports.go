@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// CardNumberCipherInterface defines the contract for the envelope encryption of card numbers
+type CardNumberCipherInterface interface {
+	// Seal encrypts a value under the active master key, returning the sealed value and the key fingerprint
+	Seal(plaintext []byte) (string, string, error)
+	Open(sealed, fingerprint string) ([]byte, error)
+	// Rewrap wraps the data key of a sealed value under the active master key
+	Rewrap(sealed, fingerprint string) (string, string, error)
+	IsSealed(value string) bool
+	Has(fingerprint string) bool
+	ActiveFingerprint() string
+}
+
+// CardNumberServiceInterface defines the contract for the audited access to card numbers
+type CardNumberServiceInterface interface {
+	RevealCardNumber(cardID, adminID, reason, clientIP string) (string, error)
+	GetAccessLog(cardID string, page, pageSize int) ([]*entities.CardNumberAccessLog, int64, error)
+	RotateKeys(batchSize int) (*entities.KeyRotationResult, error)
+}
+
+// CardNumberRepositoryInterface defines the contract for card number storage operations
+type CardNumberRepositoryInterface interface {
+	// GetCardsToRotate returns cards (including deleted ones) not wrapped by the given key, ordered by ID after afterID
+	GetCardsToRotate(activeFingerprint, afterID string, limit int) ([]*entities.Card, error)
+	// UpdateEncryptedNumber replaces the encrypted number only if it was not changed since it was read
+	UpdateEncryptedNumber(cardID, previousNumber, encryptedNumber, fingerprint string) (bool, error)
+	CreateAccessLog(log *entities.CardNumberAccessLog) error
+	GetAccessLogsByCard(cardID string, limit, offset int) ([]*entities.CardNumberAccessLog, int64, error)
+}
@@ -20,14 +20,15 @@ type CardServiceInterface interface {
 	BlockCard(cardID string) (*entities.Card, error)
 	UnblockCard(cardID string) (*entities.Card, error)
 	SetDefaultCard(cardID string) (*entities.Card, error)
+	RenewCard(cardID, userID string, req *dto.RenewCardRequest) (*entities.Card, error)
 
 	// Credit card financial operations
-	ChargeCard(cardID string, amount float64, description, reference string) (*entities.Card, error)
+	ChargeCard(cardID string, amount float64, description, reference, performedBy string, purchase entities.PurchaseDetails) (*entities.Card, error)
 	ChargeCardWithInstallments(req *dto.CreateInstallmentPlanRequest) (*dto.ChargeWithInstallmentsResponse, error)
-	PaymentCard(cardID string, amount float64, paymentMethod, reference string) (*entities.Card, error)
+	PaymentCard(cardID string, amount float64, paymentMethod, reference, performedBy string) (*entities.Card, error)
 
 	// Debit card operations
-	ProcessDebitTransaction(cardID string, amount float64, description, merchantName, reference string) (*entities.Card, error)
+	ProcessDebitTransaction(cardID string, amount float64, description, reference, performedBy string, purchase entities.PurchaseDetails) (*entities.Card, error)
 }
 
 // InstallmentServiceInterface defines the contract for installment service operations
@@ -38,6 +39,7 @@ type InstallmentServiceInterface interface {
 	GetInstallmentPlan(planID string) (*entities.InstallmentPlan, error)
 	GetInstallmentPlansByCard(cardID string, page, pageSize int) ([]*entities.InstallmentPlan, int64, error)
 	GetInstallmentPlansByUser(userID string, status string, page, pageSize int) ([]*entities.InstallmentPlan, int64, error)
+	CanAccessInstallmentPlan(plan *entities.InstallmentPlan, userID string) (bool, error)
 	CancelInstallmentPlan(planID, reason string, cancelledBy string) (*entities.InstallmentPlan, error)
 	SuspendInstallmentPlan(planID, reason string, suspendedBy string) (*entities.InstallmentPlan, error)
 	ReactivateInstallmentPlan(planID, reason string, reactivatedBy string) (*entities.InstallmentPlan, error)
@@ -68,6 +70,7 @@ type CardRepositoryInterface interface {
 	Delete(cardID string) error
 	GetDefaultByAccount(accountID string) (*entities.Card, error)
 	SetDefaultByAccount(accountID, cardID string) error
+	Replace(oldCard, newCard *entities.Card) error // Moves active installment plans, controls and card holders to the new card
 }
 
 // InstallmentPlanRepositoryInterface defines the contract for installment plan repository operations
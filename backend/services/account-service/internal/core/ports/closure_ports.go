@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// AccountClosureServiceInterface defines the contract for the account closure workflow
+type AccountClosureServiceInterface interface {
+	// Closure lifecycle
+	CheckClosure(accountID, userID string) (*entities.ClosureCheck, error)
+	StartClosure(accountID, userID, reason string) (*entities.AccountClosure, error)
+	GetClosure(accountID, userID string) (*entities.AccountClosure, error)
+	CompleteClosure(accountID, userID string) (*entities.AccountClosure, error)
+	CancelClosure(accountID, userID string) (*entities.AccountClosure, error)
+
+	// Final settlement steps
+	TransferBalance(accountID, userID, targetAccountID string) (*entities.AccountClosure, error)
+	SettleCard(accountID, userID, cardID string) (*entities.AccountClosure, error)
+	CancelInstallmentPlan(accountID, userID, planID string) (*entities.AccountClosure, error)
+	TransferInstallmentPlan(accountID, userID, planID, targetCardID string) (*entities.AccountClosure, error)
+}
+
+// AccountClosureRepositoryInterface defines the contract for account closure repository operations.
+// Every settlement step is saved together with its audit event.
+type AccountClosureRepositoryInterface interface {
+	Create(closure *entities.AccountClosure, event *entities.AccountClosureEvent) error
+	GetPendingByAccount(accountID string) (*entities.AccountClosure, error) // Returns nil when there is none
+	GetLatestByAccount(accountID string) (*entities.AccountClosure, error)  // With its events, nil when there is none
+	CreateEvent(event *entities.AccountClosureEvent) error
+	GetOpenPlansByAccount(accountID string) ([]*entities.InstallmentPlan, error)
+
+	TransferBalance(from, to *entities.Account, event *entities.AccountClosureEvent) error
+	SettleCard(account *entities.Account, card *entities.Card, event *entities.AccountClosureEvent) error
+	TransferInstallmentPlan(plan *entities.InstallmentPlan, source, target *entities.Card, event *entities.AccountClosureEvent) error
+	Close(closure *entities.AccountClosure, account *entities.Account, event *entities.AccountClosureEvent) error
+	Cancel(closure *entities.AccountClosure, event *entities.AccountClosureEvent) error
+}
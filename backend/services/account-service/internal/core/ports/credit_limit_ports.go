@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// CreditLimitServiceInterface defines the contract for credit limit change requests and their history
+type CreditLimitServiceInterface interface {
+	// Change requests (cardID is nil for the limit of a credit card account)
+	RequestChange(accountID, userID string, cardID *string, creditLimit float64, reason string) (*entities.CreditLimitChangeRequest, error)
+	GetRequestsByAccount(accountID, userID, status string, page, pageSize int) ([]*entities.CreditLimitChangeRequest, int64, error)
+
+	// Treasury review of the increases above the approval threshold
+	GetPendingRequests(page, pageSize int) ([]*entities.CreditLimitChangeRequest, int64, error)
+	ApproveRequest(requestID, treasurerID, note string) (*entities.CreditLimitChangeRequest, error)
+	RejectRequest(requestID, treasurerID, note string) (*entities.CreditLimitChangeRequest, error)
+
+	// History
+	GetHistory(accountID, userID string, cardID *string) ([]*entities.CreditLimitHistory, error)
+	GetLimitAt(accountID, userID string, cardID *string, at time.Time) (*entities.CreditLimitHistory, error)
+}
+
+// CreditLimitRepositoryInterface defines the contract for credit limit repository operations
+type CreditLimitRepositoryInterface interface {
+	CreateRequest(request *entities.CreditLimitChangeRequest) error
+	GetRequestByID(requestID string) (*entities.CreditLimitChangeRequest, error)
+	UpdateRequest(request *entities.CreditLimitChangeRequest) error
+	GetPendingByTarget(accountID string, cardID *string) (*entities.CreditLimitChangeRequest, error) // Returns nil when there is none
+	GetRequestsByAccount(accountID, status string, limit, offset int) ([]*entities.CreditLimitChangeRequest, int64, error)
+	GetRequestsByStatus(status string, limit, offset int) ([]*entities.CreditLimitChangeRequest, int64, error)
+
+	GetHistory(accountID string, cardID *string) ([]*entities.CreditLimitHistory, error) // Newest first
+
+	// ApplyChange closes the limit in effect, records the new history entries, sets the new
+	// limit on the account or card and saves the request, atomically
+	ApplyChange(request *entities.CreditLimitChangeRequest, entries ...*entities.CreditLimitHistory) error
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// CreditLineServiceInterface defines the contract for shared credit line operations
+type CreditLineServiceInterface interface {
+	CreateCreditLine(accountID, userID, name string, totalLimit float64) (*entities.CreditLine, error)
+	GetCreditLine(creditLineID, userID string) (*entities.CreditLine, error)
+	GetCreditLinesByAccount(accountID, userID string) ([]*entities.CreditLine, error)
+	UpdateCreditLine(creditLineID, userID string, name *string, totalLimit *float64) (*entities.CreditLine, error)
+	AssignCard(creditLineID, cardID, userID string, isAdditional bool, subLimit *float64) (*entities.Card, error)
+	RemoveCard(creditLineID, cardID, userID string, creditLimit *float64) (*entities.Card, error)
+}
+
+// CreditLineRepositoryInterface defines the contract for credit line repository operations
+type CreditLineRepositoryInterface interface {
+	Create(creditLine *entities.CreditLine) (*entities.CreditLine, error)
+	GetByID(creditLineID string) (*entities.CreditLine, error) // Cards and their active installment plans preloaded
+	GetByAccount(accountID string) ([]*entities.CreditLine, error)
+	Update(creditLine *entities.CreditLine) (*entities.CreditLine, error)
+}
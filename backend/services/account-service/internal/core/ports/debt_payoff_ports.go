@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// DebtPayoffServiceInterface defines the contract for the debt payoff planner
+type DebtPayoffServiceInterface interface {
+	// Simulations over the current card debt and installment plans of the user
+	Simulate(userID string, options entities.DebtPayoffOptions) (*entities.DebtPayoffSimulation, error)
+	Compare(userID string, options entities.DebtPayoffOptions) (*entities.DebtPayoffComparison, error)
+
+	// Saved plans and their progress
+	SavePlan(userID string, options entities.DebtPayoffOptions) (*entities.DebtPayoffPlan, error)
+	GetPlans(userID, status string) ([]*entities.DebtPayoffPlan, error)
+	GetPlan(planID, userID string) (*entities.DebtPayoffPlan, error)
+	GetProgress(planID, userID string) (*entities.DebtPayoffProgress, error)
+	AbandonPlan(planID, userID string) (*entities.DebtPayoffPlan, error)
+}
+
+// DebtPayoffRepositoryInterface defines the contract for payoff plan repository operations
+type DebtPayoffRepositoryInterface interface {
+	Create(plan *entities.DebtPayoffPlan) error // Abandons the active plan of the user, atomically
+	GetByID(planID string) (*entities.DebtPayoffPlan, error)
+	GetByUser(userID, status string) ([]*entities.DebtPayoffPlan, error) // Newest first
+	Update(plan *entities.DebtPayoffPlan) error
+}
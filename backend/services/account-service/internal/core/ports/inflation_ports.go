@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// InflationServiceInterface defines the contract for the inflation aware purchase calculator
+type InflationServiceInterface interface {
+	// Cash versus installments ("cuotas vs contado")
+	CompareCashWithInstallments(options entities.CashComparisonOptions) (*entities.CashComparison, error)
+
+	// Stored index series
+	GetSeries(series string) ([]*entities.InflationRate, error)
+	SaveSeries(series string, rates []entities.InflationRate, updatedBy string) ([]*entities.InflationRate, error)
+}
+
+// InflationRepositoryInterface defines the contract for inflation series repository operations
+type InflationRepositoryInterface interface {
+	GetSeries(series string) ([]*entities.InflationRate, error)                      // Oldest first
+	GetSeriesSince(series string, from time.Time) ([]*entities.InflationRate, error) // Oldest first, with the last rate before from
+	SaveRates(rates []*entities.InflationRate) error                                 // Replaces the rates of the same series and period
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// MembershipServiceInterface defines the contract for shared account operations
+type MembershipServiceInterface interface {
+	// Invitations
+	InviteMember(accountID, invitedBy, userID string, role entities.MemberRole, cardID *string) (*entities.AccountMember, error)
+	AcceptInvitation(memberID, userID string) (*entities.AccountMember, error)
+	DeclineInvitation(memberID, userID string) (*entities.AccountMember, error)
+	GetPendingInvitations(userID string) ([]*entities.AccountMember, error)
+
+	// Member management
+	GetAccountMembers(accountID, requestedBy string) ([]*entities.AccountMember, error)
+	UpdateMemberRole(accountID, memberID, updatedBy string, role entities.MemberRole, cardID *string) (*entities.AccountMember, error)
+	RemoveMember(accountID, memberID, removedBy string) error
+
+	// Access checks
+	CanViewAccount(account *entities.Account, userID string) (bool, error)
+	CanOperateAccount(account *entities.Account, userID string) (bool, error)
+	CanViewCard(card *entities.Card, userID string) (bool, error)
+	CanUseCard(card *entities.Card, userID string) (bool, error)
+}
+
+// AccountMemberRepositoryInterface defines the contract for account membership repository operations
+type AccountMemberRepositoryInterface interface {
+	Create(member *entities.AccountMember) (*entities.AccountMember, error)
+	GetByID(memberID string) (*entities.AccountMember, error)
+	GetByAccount(accountID string) ([]*entities.AccountMember, error)
+	GetByAccountAndUser(accountID, userID string) (*entities.AccountMember, error) // Returns nil when the user is not a member
+	GetPendingByUser(userID string) ([]*entities.AccountMember, error)
+	Update(member *entities.AccountMember) (*entities.AccountMember, error)
+}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/fintrack/account-service/internal/infrastructure/clients"
+)
+
+// AccountClosureService handles the closure of accounts: pre-checks, final settlement and closing statement
+type AccountClosureService struct {
+	closureRepo        ports.AccountClosureRepositoryInterface
+	accountRepo        ports.AccountRepositoryInterface
+	cardRepo           ports.CardRepositoryInterface
+	installmentService ports.InstallmentServiceInterface
+	transactionClient  *clients.TransactionClient // To record the settlement movements
+}
+
+// NewAccountClosureService creates a new account closure service
+func NewAccountClosureService(closureRepo ports.AccountClosureRepositoryInterface, accountRepo ports.AccountRepositoryInterface, cardRepo ports.CardRepositoryInterface, installmentService ports.InstallmentServiceInterface) *AccountClosureService {
+	return &AccountClosureService{
+		closureRepo:        closureRepo,
+		accountRepo:        accountRepo,
+		cardRepo:           cardRepo,
+		installmentService: installmentService,
+		transactionClient:  clients.NewTransactionClient(),
+	}
+}
+
+// CheckClosure runs the pre-checks of closing an account and lists what must be resolved first
+func (s *AccountClosureService) CheckClosure(accountID, userID string) (*entities.ClosureCheck, error) {
+	account, err := s.getOwnedAccount(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.check(account)
+}
+
+// StartClosure opens the closure process of an account. Settlement steps are only accepted while it is pending.
+func (s *AccountClosureService) StartClosure(accountID, userID, reason string) (*entities.AccountClosure, error) {
+	account, err := s.getOwnedAccount(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account.IsClosed() {
+		return nil, errors.ErrAccountClosed
+	}
+
+	pending, err := s.closureRepo.GetPendingByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, errors.NewValidationError("account_id", "the account already has a closure in progress")
+	}
+
+	closure := &entities.AccountClosure{
+		AccountID:   accountID,
+		RequestedBy: userID,
+		Reason:      strings.TrimSpace(reason),
+		Status:      entities.AccountClosureStatusPending,
+	}
+	if err := s.closureRepo.Create(closure, closure.NewEvent(entities.ClosureStepStarted, "", nil, closure.Reason, userID)); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("📋 Closure of account %s started by %s\n", accountID, userID)
+
+	return s.closureRepo.GetLatestByAccount(accountID)
+}
+
+// GetClosure gets the latest closure of an account with its audit trail
+func (s *AccountClosureService) GetClosure(accountID, userID string) (*entities.AccountClosure, error) {
+	if _, err := s.getOwnedAccount(accountID, userID); err != nil {
+		return nil, err
+	}
+
+	closure, err := s.closureRepo.GetLatestByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if closure == nil {
+		return nil, fmt.Errorf("account closure not found")
+	}
+	return closure, nil
+}
+
+// TransferBalance moves the whole balance of the account to another active account of the same owner and currency.
+// A negative balance is settled the other way: the target account covers it.
+func (s *AccountClosureService) TransferBalance(accountID, userID, targetAccountID string) (*entities.AccountClosure, error) {
+	account, closure, err := s.getPendingClosure(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Balance == 0 {
+		return nil, errors.NewValidationError("balance", "the account has no balance to transfer")
+	}
+	if targetAccountID == accountID {
+		return nil, errors.NewValidationError("target_account_id", "the target account must be a different account")
+	}
+
+	target, err := s.accountRepo.GetByID(targetAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("target account not found: %w", err)
+	}
+	if target.UserID != account.UserID {
+		return nil, errors.ErrInsufficientRights
+	}
+	if !target.IsActive || target.IsClosed() {
+		return nil, errors.NewValidationError("target_account_id", "the target account must be active")
+	}
+	if target.Currency != account.Currency {
+		return nil, errors.NewValidationError("target_account_id",
+			fmt.Sprintf("the target account must be in %s, got %s", account.Currency, target.Currency))
+	}
+
+	// Negative amounts are covered by the target account
+	amount := account.Balance
+	if amount < 0 && target.Balance < -amount {
+		return nil, errors.NewValidationError("target_account_id",
+			fmt.Sprintf("the target account balance (%.2f) does not cover the negative balance (%.2f)", target.Balance, amount))
+	}
+	account.Balance = 0
+	target.Balance += amount
+
+	details := fmt.Sprintf("balance transferred to account %s", target.Name)
+	from, to := &account.ID, &target.ID
+	if amount < 0 {
+		details = fmt.Sprintf("negative balance covered from account %s", target.Name)
+		from, to = to, from
+	}
+	event := closure.NewEvent(entities.ClosureStepBalanceTransferred, target.ID, &amount, details, userID)
+	if err := s.closureRepo.TransferBalance(account, target, event); err != nil {
+		return nil, err
+	}
+
+	s.recordMovement(account.UserID, clients.CreateTransactionRequest{
+		Type:          "account_transfer",
+		Amount:        math.Abs(amount),
+		Currency:      string(account.Currency),
+		FromAccountID: from,
+		ToAccountID:   to,
+		Description:   fmt.Sprintf("Closing balance of account %s", account.Name),
+		ReferenceID:   fmt.Sprintf("closure-%s-balance", closure.ID),
+		Metadata: map[string]interface{}{
+			"closureId":  closure.ID,
+			"category":   "account_closure",
+			"recordOnly": true, // Balances already moved by Account Service
+		},
+	})
+
+	return s.closureRepo.GetLatestByAccount(accountID)
+}
+
+// SettleCard pays the debt of a credit card from the account balance, or refunds its credit balance to the account.
+// The installment plans of the card must be resolved first, as their remaining amount is part of its balance.
+func (s *AccountClosureService) SettleCard(accountID, userID, cardID string) (*entities.AccountClosure, error) {
+	account, closure, err := s.getPendingClosure(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := s.cardRepo.GetByID(cardID)
+	if err != nil || card.AccountID != accountID {
+		return nil, fmt.Errorf("card not found in account")
+	}
+	if card.CardType != entities.CardTypeCredit || card.Balance == 0 {
+		return nil, errors.NewValidationError("card_id", "the card has nothing to settle")
+	}
+
+	plans, err := s.closureRepo.GetOpenPlansByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	for _, plan := range plans {
+		if plan.CardID == card.ID {
+			return nil, errors.NewValidationError("card_id", "resolve the installment plans of the card before settling it")
+		}
+	}
+
+	amount := card.Balance
+	if amount > account.Balance {
+		return nil, errors.NewValidationError("balance",
+			fmt.Sprintf("the account balance (%.2f) does not cover the card debt (%.2f)", account.Balance, amount))
+	}
+	account.Balance -= amount
+	card.Balance = 0
+
+	details := fmt.Sprintf("debt of card %s paid from the account", card.MaskedNumber)
+	if amount < 0 {
+		details = fmt.Sprintf("credit balance of card %s refunded to the account", card.MaskedNumber)
+	}
+	event := closure.NewEvent(entities.ClosureStepCardSettled, card.ID, &amount, details, userID)
+	if err := s.closureRepo.SettleCard(account, card, event); err != nil {
+		return nil, err
+	}
+
+	if amount > 0 {
+		s.recordMovement(account.UserID, clients.CreateTransactionRequest{
+			Type:          "credit_payment",
+			Amount:        amount,
+			Currency:      string(account.Currency),
+			FromAccountID: &account.ID,
+			Description:   fmt.Sprintf("Closing payment of card %s", card.MaskedNumber),
+			PaymentMethod: "bank_transfer",
+			ReferenceID:   fmt.Sprintf("closure-%s-card-%s", closure.ID, card.ID),
+			Metadata: map[string]interface{}{
+				"closureId":  closure.ID,
+				"cardId":     card.ID,
+				"category":   "account_closure",
+				"recordOnly": true, // Balances already moved by Account Service
+			},
+		})
+	}
+
+	return s.closureRepo.GetLatestByAccount(accountID)
+}
+
+// CancelInstallmentPlan cancels an open installment plan of the account
+func (s *AccountClosureService) CancelInstallmentPlan(accountID, userID, planID string) (*entities.AccountClosure, error) {
+	_, closure, err := s.getPendingClosure(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.getOpenPlan(accountID, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != entities.InstallmentPlanStatusActive {
+		return nil, errors.NewValidationError("plan_id", "only active plans can be cancelled, transfer suspended plans to another card")
+	}
+
+	if _, err := s.installmentService.CancelInstallmentPlan(plan.ID, "account closure", userID); err != nil {
+		return nil, err
+	}
+
+	remaining := plan.RemainingAmount
+	event := closure.NewEvent(entities.ClosureStepPlanCancelled, plan.ID, &remaining,
+		fmt.Sprintf("installment plan %q cancelled", plan.Description), userID)
+	if err := s.closureRepo.CreateEvent(event); err != nil {
+		return nil, err
+	}
+
+	return s.closureRepo.GetLatestByAccount(accountID)
+}
+
+// TransferInstallmentPlan moves an open installment plan and its remaining amount to an active credit
+// card of another account of the same owner and currency
+func (s *AccountClosureService) TransferInstallmentPlan(accountID, userID, planID, targetCardID string) (*entities.AccountClosure, error) {
+	account, closure, err := s.getPendingClosure(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.getOpenPlan(accountID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := s.cardRepo.GetByID(plan.CardID)
+	if err != nil {
+		return nil, fmt.Errorf("card not found: %w", err)
+	}
+
+	target, err := s.cardRepo.GetByIDWithAccount(targetCardID)
+	if err != nil {
+		return nil, fmt.Errorf("target card not found: %w", err)
+	}
+	if target.AccountID == accountID {
+		return nil, errors.NewValidationError("target_card_id", "the target card must belong to another account")
+	}
+	if target.Account.UserID != account.UserID {
+		return nil, errors.ErrInsufficientRights
+	}
+	if target.CardType != entities.CardTypeCredit || !target.IsActive() || !target.Account.IsActive || target.Account.IsClosed() {
+		return nil, errors.NewValidationError("target_card_id", "the target card must be an active credit card")
+	}
+	if target.Account.Currency != account.Currency {
+		return nil, errors.NewValidationError("target_card_id",
+			fmt.Sprintf("the target card must be in %s, got %s", account.Currency, target.Account.Currency))
+	}
+
+	// The plan adds to the installment commitments of the target, checked as a new installment purchase would be
+	remaining := plan.RemainingAmount
+	if !target.CanCreateInstallmentPlan(remaining) {
+		available := target.GetAvailableCreditWithInstallments()
+		return nil, errors.NewValidationError("target_card_id",
+			fmt.Sprintf("the target card available credit (%.2f) does not cover the remaining amount (%.2f)", available, remaining))
+	}
+
+	// The remaining amount was charged upfront to the source card, so it moves with the plan
+	target.Balance += remaining
+	source.Balance -= remaining
+	plan.CardID = target.ID
+
+	event := closure.NewEvent(entities.ClosureStepPlanTransferred, plan.ID, &remaining,
+		fmt.Sprintf("installment plan %q transferred to card %s", plan.Description, target.MaskedNumber), userID)
+	if err := s.closureRepo.TransferInstallmentPlan(plan, source, target, event); err != nil {
+		return nil, err
+	}
+
+	return s.closureRepo.GetLatestByAccount(accountID)
+}
+
+// CompleteClosure re-runs the pre-checks and, when nothing is left, generates the closing
+// statement, deactivates the cards and marks the account closed
+func (s *AccountClosureService) CompleteClosure(accountID, userID string) (*entities.AccountClosure, error) {
+	account, closure, err := s.getPendingClosure(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	check, err := s.check(account)
+	if err != nil {
+		return nil, err
+	}
+	if !check.CanClose {
+		pending := make([]string, 0, len(check.Blockers))
+		for _, blocker := range check.Blockers {
+			pending = append(pending, blocker.Description)
+		}
+		return nil, errors.NewValidationError("account_id", "the account cannot be closed yet: "+strings.Join(pending, "; "))
+	}
+
+	history, err := s.closureRepo.GetLatestByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	account.IsActive = false
+	account.ClosedAt = &now
+	closure.Status = entities.AccountClosureStatusCompleted
+	closure.CompletedAt = &now
+
+	event := closure.NewEvent(entities.ClosureStepCompleted, "", nil, "account closed", userID)
+	closure.Statement = buildClosingStatement(account, append(history.Events, *event), userID, now)
+
+	if err := s.closureRepo.Close(closure, account, event); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("🔒 Account %s closed by %s\n", accountID, userID)
+
+	return s.closureRepo.GetLatestByAccount(accountID)
+}
+
+// CancelClosure stops a pending closure. Settlement steps already done are not reverted.
+func (s *AccountClosureService) CancelClosure(accountID, userID string) (*entities.AccountClosure, error) {
+	_, closure, err := s.getPendingClosure(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	closure.Status = entities.AccountClosureStatusCancelled
+	closure.CancelledAt = &now
+
+	if err := s.closureRepo.Cancel(closure, closure.NewEvent(entities.ClosureStepCancelled, "", nil, "closure cancelled", userID)); err != nil {
+		return nil, err
+	}
+
+	return s.closureRepo.GetLatestByAccount(accountID)
+}
+
+// getOwnedAccount gets an account that only its owner can close
+func (s *AccountClosureService) getOwnedAccount(accountID, userID string) (*entities.Account, error) {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+	if account.UserID != userID {
+		return nil, errors.ErrInsufficientRights
+	}
+	return account, nil
+}
+
+// getPendingClosure gets an owned account with its closure in progress
+func (s *AccountClosureService) getPendingClosure(accountID, userID string) (*entities.Account, *entities.AccountClosure, error) {
+	account, err := s.getOwnedAccount(accountID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closure, err := s.closureRepo.GetPendingByAccount(accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if closure == nil {
+		return nil, nil, errors.NewValidationError("account_id", "the account has no closure in progress, start one first")
+	}
+	return account, closure, nil
+}
+
+// getOpenPlan gets an open installment plan on a card of the account
+func (s *AccountClosureService) getOpenPlan(accountID, planID string) (*entities.InstallmentPlan, error) {
+	plans, err := s.closureRepo.GetOpenPlansByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	for _, plan := range plans {
+		if plan.ID == planID {
+			return plan, nil
+		}
+	}
+	return nil, fmt.Errorf("open installment plan not found in account")
+}
+
+// check runs the pre-checks with the current cards and plans of the account
+func (s *AccountClosureService) check(account *entities.Account) (*entities.ClosureCheck, error) {
+	plans, err := s.closureRepo.GetOpenPlansByAccount(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	return account.CheckClosure(account.Cards, plans), nil
+}
+
+// recordMovement records a settlement movement in the transaction service (async, best effort)
+func (s *AccountClosureService) recordMovement(userID string, req clients.CreateTransactionRequest) {
+	go func() {
+		if _, err := s.transactionClient.CreateTransaction(userID, req); err != nil {
+			fmt.Printf("Warning: Failed to record account closure transaction: %v\n", err)
+		}
+	}()
+}
+
+// buildClosingStatement summarizes the final settlement of an account from the audit trail of its closure
+func buildClosingStatement(account *entities.Account, events []entities.AccountClosureEvent, closedBy string, generatedAt time.Time) *entities.ClosingStatement {
+	statement := &entities.ClosingStatement{
+		AccountID:    account.ID,
+		AccountName:  account.Name,
+		Currency:     account.Currency,
+		FinalBalance: account.Balance,
+		Cards:        make([]entities.ClosingStatementCard, 0, len(account.Cards)),
+		Lines:        make([]entities.ClosingStatementLine, 0, len(events)),
+		ClosedBy:     closedBy,
+		GeneratedAt:  generatedAt,
+	}
+
+	for _, event := range events {
+		switch event.Step {
+		case entities.ClosureStepBalanceTransferred:
+			statement.BalanceTransferred += *event.Amount
+		case entities.ClosureStepCardSettled:
+			statement.CardsSettled += *event.Amount
+		case entities.ClosureStepPlanCancelled:
+			statement.PlansCancelled++
+		case entities.ClosureStepPlanTransferred:
+			statement.PlansTransferred++
+		}
+		statement.Lines = append(statement.Lines, entities.ClosingStatementLine{
+			Step:        event.Step,
+			ReferenceID: event.ReferenceID,
+			Amount:      event.Amount,
+			Details:     event.Details,
+			Date:        event.CreatedAt,
+		})
+	}
+
+	for _, card := range account.Cards {
+		statement.Cards = append(statement.Cards, entities.ClosingStatementCard{
+			CardID:       card.ID,
+			CardType:     card.CardType,
+			MaskedNumber: card.MaskedNumber,
+			FinalBalance: card.Balance,
+		})
+	}
+
+	return statement
+}
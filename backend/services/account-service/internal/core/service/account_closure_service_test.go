@@ -0,0 +1,385 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// MockAccountClosureRepository implements AccountClosureRepositoryInterface for testing
+type MockAccountClosureRepository struct {
+	closures []*entities.AccountClosure
+	plans    []*entities.InstallmentPlan
+	cardRepo *MockCardRepository
+}
+
+func (m *MockAccountClosureRepository) Create(closure *entities.AccountClosure, event *entities.AccountClosureEvent) error {
+	if closure.ID == "" {
+		closure.ID = uuid.NewString()
+	}
+	m.closures = append(m.closures, closure)
+	event.ClosureID = closure.ID
+	return m.CreateEvent(event)
+}
+
+func (m *MockAccountClosureRepository) GetPendingByAccount(accountID string) (*entities.AccountClosure, error) {
+	for _, closure := range m.closures {
+		if closure.AccountID == accountID && closure.IsPending() {
+			return closure, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockAccountClosureRepository) GetLatestByAccount(accountID string) (*entities.AccountClosure, error) {
+	for i := len(m.closures) - 1; i >= 0; i-- {
+		if m.closures[i].AccountID == accountID {
+			return m.closures[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockAccountClosureRepository) CreateEvent(event *entities.AccountClosureEvent) error {
+	for _, closure := range m.closures {
+		if closure.ID == event.ClosureID {
+			closure.Events = append(closure.Events, *event)
+		}
+	}
+	return nil
+}
+
+func (m *MockAccountClosureRepository) GetOpenPlansByAccount(accountID string) ([]*entities.InstallmentPlan, error) {
+	var plans []*entities.InstallmentPlan
+	for _, plan := range m.plans {
+		card, exists := m.cardRepo.cards[plan.CardID]
+		if !exists || card.AccountID != accountID {
+			continue
+		}
+		if plan.Status == entities.InstallmentPlanStatusActive || plan.Status == entities.InstallmentPlanStatusSuspended {
+			plans = append(plans, plan)
+		}
+	}
+	return plans, nil
+}
+
+func (m *MockAccountClosureRepository) TransferBalance(from, to *entities.Account, event *entities.AccountClosureEvent) error {
+	return m.CreateEvent(event)
+}
+
+func (m *MockAccountClosureRepository) SettleCard(account *entities.Account, card *entities.Card, event *entities.AccountClosureEvent) error {
+	syncCards(account, card)
+	return m.CreateEvent(event)
+}
+
+func (m *MockAccountClosureRepository) TransferInstallmentPlan(plan *entities.InstallmentPlan, source, target *entities.Card, event *entities.AccountClosureEvent) error {
+	return m.CreateEvent(event)
+}
+
+func (m *MockAccountClosureRepository) Close(closure *entities.AccountClosure, account *entities.Account, event *entities.AccountClosureEvent) error {
+	for i := range account.Cards {
+		account.Cards[i].Status = entities.CardStatusInactive
+	}
+	return m.CreateEvent(event)
+}
+
+func (m *MockAccountClosureRepository) Cancel(closure *entities.AccountClosure, event *entities.AccountClosureEvent) error {
+	return m.CreateEvent(event)
+}
+
+var _ ports.AccountClosureRepositoryInterface = (*MockAccountClosureRepository)(nil)
+
+// syncCards mirrors a saved card into the cards preloaded with its account, as a reload would
+func syncCards(account *entities.Account, card *entities.Card) {
+	for i := range account.Cards {
+		if account.Cards[i].ID == card.ID {
+			account.Cards[i] = *card
+		}
+	}
+}
+
+// MockClosureInstallmentService implements the plan cancellation used by the closure service
+type MockClosureInstallmentService struct {
+	ports.InstallmentServiceInterface
+	repo *MockAccountClosureRepository
+}
+
+func (m *MockClosureInstallmentService) CancelInstallmentPlan(planID, reason string, cancelledBy string) (*entities.InstallmentPlan, error) {
+	for _, plan := range m.repo.plans {
+		if plan.ID == planID {
+			plan.Status = entities.InstallmentPlanStatusCancelled
+			return plan, nil
+		}
+	}
+	return nil, errors.ErrInvalidInput
+}
+
+type closureFixture struct {
+	service     *AccountClosureService
+	accountRepo *MockAccountRepository
+	cardRepo    *MockCardRepository
+	closureRepo *MockAccountClosureRepository
+	account     *entities.Account
+	card        *entities.Card
+	plan        *entities.InstallmentPlan
+}
+
+// setupAccountClosureService creates an account with balance 1000, a credit card owing 300 and an
+// active plan with 120 remaining on that card
+func setupAccountClosureService(t *testing.T) *closureFixture {
+	accountRepo := NewMockAccountRepository()
+	cardRepo := &MockCardRepository{cards: make(map[string]*entities.Card)}
+	closureRepo := &MockAccountClosureRepository{cardRepo: cardRepo}
+
+	account := newClosureAccount(t, accountRepo, uuid.NewString(), entities.CurrencyARS, 1000)
+	card := addClosureCard(account, cardRepo, 300)
+
+	plan := &entities.InstallmentPlan{
+		ID:              uuid.NewString(),
+		CardID:          card.ID,
+		UserID:          account.UserID,
+		Description:     "Heladera",
+		Status:          entities.InstallmentPlanStatusActive,
+		RemainingAmount: 120,
+	}
+	closureRepo.plans = append(closureRepo.plans, plan)
+
+	service := NewAccountClosureService(closureRepo, accountRepo, cardRepo, &MockClosureInstallmentService{repo: closureRepo})
+	return &closureFixture{
+		service:     service,
+		accountRepo: accountRepo,
+		cardRepo:    cardRepo,
+		closureRepo: closureRepo,
+		account:     account,
+		card:        card,
+		plan:        plan,
+	}
+}
+
+func newClosureAccount(t *testing.T, accountRepo *MockAccountRepository, userID string, currency entities.Currency, balance float64) *entities.Account {
+	account := &entities.Account{
+		UserID:      userID,
+		AccountType: entities.AccountTypeBankAccount,
+		Name:        "Banco Nación",
+		Currency:    currency,
+		Balance:     balance,
+		IsActive:    true,
+	}
+	if err := accountRepo.Create(account); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	return account
+}
+
+// addClosureCard adds a credit card to the account as a preloaded card and to the card repository
+func addClosureCard(account *entities.Account, cardRepo *MockCardRepository, balance float64) *entities.Card {
+	card := newCreditCard(account.ID, balance)
+	limit := 5000.0
+	card.CreditLimit = &limit
+	card.MaskedNumber = "**** **** **** 1234"
+	account.Cards = append(account.Cards, *card)
+
+	card.Account = *account
+	cardRepo.cards[card.ID] = card
+	return card
+}
+
+func TestCheckClosure(t *testing.T) {
+	f := setupAccountClosureService(t)
+
+	check, err := f.service.CheckClosure(f.account.ID, f.account.UserID)
+	if err != nil {
+		t.Fatalf("CheckClosure() unexpected error: %v", err)
+	}
+	if check.CanClose {
+		t.Error("CheckClosure() expected the account not to be closable")
+	}
+
+	blockers := make(map[entities.ClosureBlockerType]float64)
+	for _, blocker := range check.Blockers {
+		blockers[blocker.Type] = blocker.Amount
+	}
+	if blockers[entities.ClosureBlockerBalance] != 1000 || blockers[entities.ClosureBlockerCardDebt] != 300 || blockers[entities.ClosureBlockerInstallmentPlan] != 120 {
+		t.Errorf("CheckClosure() blockers = %+v", check.Blockers)
+	}
+
+	if _, err := f.service.CheckClosure(f.account.ID, uuid.NewString()); err != errors.ErrInsufficientRights {
+		t.Errorf("CheckClosure() by another user error = %v, want %v", err, errors.ErrInsufficientRights)
+	}
+}
+
+func TestAccountClosureSettlement(t *testing.T) {
+	f := setupAccountClosureService(t)
+	target := newClosureAccount(t, f.accountRepo, f.account.UserID, entities.CurrencyARS, 50)
+	userID := f.account.UserID
+
+	if _, err := f.service.SettleCard(f.account.ID, userID, f.card.ID); !errors.IsValidationError(err) {
+		t.Fatalf("SettleCard() without a closure error = %v, want validation error", err)
+	}
+
+	if _, err := f.service.StartClosure(f.account.ID, userID, "moving to another bank"); err != nil {
+		t.Fatalf("StartClosure() unexpected error: %v", err)
+	}
+	if _, err := f.service.StartClosure(f.account.ID, userID, ""); !errors.IsValidationError(err) {
+		t.Errorf("StartClosure() twice error = %v, want validation error", err)
+	}
+
+	if _, err := f.service.CompleteClosure(f.account.ID, userID); !errors.IsValidationError(err) {
+		t.Fatalf("CompleteClosure() with blockers error = %v, want validation error", err)
+	}
+
+	// The plan is part of the card debt, so the card cannot be settled before resolving it
+	if _, err := f.service.SettleCard(f.account.ID, userID, f.card.ID); !errors.IsValidationError(err) {
+		t.Errorf("SettleCard() with an open plan error = %v, want validation error", err)
+	}
+	if _, err := f.service.CancelInstallmentPlan(f.account.ID, userID, f.plan.ID); err != nil {
+		t.Fatalf("CancelInstallmentPlan() unexpected error: %v", err)
+	}
+	if _, err := f.service.SettleCard(f.account.ID, userID, f.card.ID); err != nil {
+		t.Fatalf("SettleCard() unexpected error: %v", err)
+	}
+	if f.account.Balance != 700 || f.card.Balance != 0 {
+		t.Errorf("after settlement account balance = %.2f, card balance = %.2f, want 700 and 0", f.account.Balance, f.card.Balance)
+	}
+
+	if _, err := f.service.TransferBalance(f.account.ID, userID, target.ID); err != nil {
+		t.Fatalf("TransferBalance() unexpected error: %v", err)
+	}
+	if f.account.Balance != 0 || target.Balance != 750 {
+		t.Errorf("after transfer balances = %.2f and %.2f, want 0 and 750", f.account.Balance, target.Balance)
+	}
+
+	closure, err := f.service.CompleteClosure(f.account.ID, userID)
+	if err != nil {
+		t.Fatalf("CompleteClosure() unexpected error: %v", err)
+	}
+	if closure.Status != entities.AccountClosureStatusCompleted || !f.account.IsClosed() || f.account.IsActive {
+		t.Errorf("CompleteClosure() status = %s, closed = %v, active = %v", closure.Status, f.account.IsClosed(), f.account.IsActive)
+	}
+
+	statement := closure.Statement
+	if statement == nil {
+		t.Fatal("CompleteClosure() expected a closing statement")
+	}
+	if statement.BalanceTransferred != 700 || statement.CardsSettled != 300 || statement.PlansCancelled != 1 || statement.FinalBalance != 0 {
+		t.Errorf("closing statement = %+v", statement)
+	}
+
+	steps := make([]string, 0, len(closure.Events))
+	for _, event := range closure.Events {
+		steps = append(steps, string(event.Step))
+	}
+	if got, want := strings.Join(steps, ","), "started,plan_cancelled,card_settled,balance_transferred,completed"; got != want {
+		t.Errorf("closure events = %s, want %s", got, want)
+	}
+
+	// Closed accounts no longer accept balance changes or reactivation
+	accountService := NewAccountService(f.accountRepo, f.closureRepo)
+	if _, err := accountService.UpdateAccountBalance(f.account.ID, 10); err != errors.ErrAccountClosed {
+		t.Errorf("UpdateAccountBalance() on a closed account error = %v, want %v", err, errors.ErrAccountClosed)
+	}
+	if _, err := accountService.UpdateAccountStatus(f.account.ID, true); err != errors.ErrAccountClosed {
+		t.Errorf("UpdateAccountStatus() on a closed account error = %v, want %v", err, errors.ErrAccountClosed)
+	}
+}
+
+func TestTransferInstallmentPlan(t *testing.T) {
+	f := setupAccountClosureService(t)
+	userID := f.account.UserID
+
+	otherAccount := newClosureAccount(t, f.accountRepo, userID, entities.CurrencyARS, 0)
+	targetCard := addClosureCard(otherAccount, f.cardRepo, 100)
+	usdAccount := newClosureAccount(t, f.accountRepo, userID, entities.CurrencyUSD, 0)
+	usdCard := addClosureCard(usdAccount, f.cardRepo, 0)
+
+	if _, err := f.service.StartClosure(f.account.ID, userID, ""); err != nil {
+		t.Fatalf("StartClosure() unexpected error: %v", err)
+	}
+
+	if _, err := f.service.TransferInstallmentPlan(f.account.ID, userID, f.plan.ID, usdCard.ID); !errors.IsValidationError(err) {
+		t.Errorf("TransferInstallmentPlan() to another currency error = %v, want validation error", err)
+	}
+
+	// The debt of this card is low, but 4900 of its 5000 limit is committed in cuotas
+	committedAccount := newClosureAccount(t, f.accountRepo, userID, entities.CurrencyARS, 0)
+	committedCard := addClosureCard(committedAccount, f.cardRepo, 100)
+	committedCard.InstallmentPlans = []entities.InstallmentPlan{{
+		ID:              uuid.NewString(),
+		CardID:          committedCard.ID,
+		Status:          entities.InstallmentPlanStatusActive,
+		RemainingAmount: 4900,
+	}}
+	if _, err := f.service.TransferInstallmentPlan(f.account.ID, userID, f.plan.ID, committedCard.ID); !errors.IsValidationError(err) {
+		t.Errorf("TransferInstallmentPlan() to a card committed in installments error = %v, want validation error", err)
+	}
+
+	if _, err := f.service.TransferInstallmentPlan(f.account.ID, userID, f.plan.ID, targetCard.ID); err != nil {
+		t.Fatalf("TransferInstallmentPlan() unexpected error: %v", err)
+	}
+	if f.plan.CardID != targetCard.ID {
+		t.Errorf("plan card = %s, want %s", f.plan.CardID, targetCard.ID)
+	}
+	if f.card.Balance != 180 || targetCard.Balance != 220 {
+		t.Errorf("card balances = %.2f and %.2f, want 180 and 220", f.card.Balance, targetCard.Balance)
+	}
+
+	check, err := f.service.CheckClosure(f.account.ID, userID)
+	if err != nil {
+		t.Fatalf("CheckClosure() unexpected error: %v", err)
+	}
+	for _, blocker := range check.Blockers {
+		if blocker.Type == entities.ClosureBlockerInstallmentPlan {
+			t.Errorf("CheckClosure() still lists the transferred plan: %+v", blocker)
+		}
+	}
+}
+
+func TestAccountClosureNegativeBalance(t *testing.T) {
+	f := setupAccountClosureService(t)
+	userID := f.account.UserID
+	f.account.Balance = -400
+	f.account.Cards = nil
+	f.closureRepo.plans = nil
+	delete(f.cardRepo.cards, f.card.ID)
+
+	check, err := f.service.CheckClosure(f.account.ID, userID)
+	if err != nil {
+		t.Fatalf("CheckClosure() unexpected error: %v", err)
+	}
+	if check.CanClose || len(check.Blockers) != 1 || check.Blockers[0].Amount != -400 ||
+		!strings.Contains(check.Blockers[0].Description, "negative balance") {
+		t.Fatalf("CheckClosure() blockers = %+v, want the negative balance", check.Blockers)
+	}
+
+	if _, err := f.service.StartClosure(f.account.ID, userID, ""); err != nil {
+		t.Fatalf("StartClosure() unexpected error: %v", err)
+	}
+
+	short := newClosureAccount(t, f.accountRepo, userID, entities.CurrencyARS, 300)
+	if _, err := f.service.TransferBalance(f.account.ID, userID, short.ID); !errors.IsValidationError(err) {
+		t.Errorf("TransferBalance() from an account that does not cover it error = %v, want validation error", err)
+	}
+	if f.account.Balance != -400 || short.Balance != 300 {
+		t.Errorf("after a rejected transfer balances = %.2f and %.2f, want -400 and 300", f.account.Balance, short.Balance)
+	}
+
+	target := newClosureAccount(t, f.accountRepo, userID, entities.CurrencyARS, 1000)
+	if _, err := f.service.TransferBalance(f.account.ID, userID, target.ID); err != nil {
+		t.Fatalf("TransferBalance() unexpected error: %v", err)
+	}
+	if f.account.Balance != 0 || target.Balance != 600 {
+		t.Errorf("after covering the balance = %.2f and %.2f, want 0 and 600", f.account.Balance, target.Balance)
+	}
+
+	closure, err := f.service.CompleteClosure(f.account.ID, userID)
+	if err != nil {
+		t.Fatalf("CompleteClosure() unexpected error: %v", err)
+	}
+	if !f.account.IsClosed() || closure.Statement.BalanceTransferred != -400 {
+		t.Errorf("CompleteClosure() closed = %v, balance transferred = %.2f, want closed and -400", f.account.IsClosed(), closure.Statement.BalanceTransferred)
+	}
+}
@@ -2,8 +2,11 @@ package service
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
 	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/account/dto"
 	"github.com/fintrack/account-service/internal/infrastructure/repositories"
 )
@@ -11,12 +14,14 @@ import (
 // AccountService provides business logic for account operations
 type AccountService struct {
 	accountRepo repositories.AccountRepository
+	closureRepo ports.AccountClosureRepositoryInterface // Open installment plans for the deletion pre-checks
 }
 
 // NewAccountService creates a new account service instance
-func NewAccountService(accountRepo repositories.AccountRepository) *AccountService {
+func NewAccountService(accountRepo repositories.AccountRepository, closureRepo ports.AccountClosureRepositoryInterface) *AccountService {
 	return &AccountService{
 		accountRepo: accountRepo,
+		closureRepo: closureRepo,
 	}
 }
 
@@ -135,7 +140,7 @@ func (s *AccountService) UpdateAccount(accountID string, req *dto.UpdateAccountR
 		updated = true
 	}
 
-	// Handle credit limit updates
+	// Credit limit changes go through change requests, which keep the history of limits
 	if req.CreditLimit != nil {
 		currentLimit := float64(0)
 		if account.CreditLimit != nil {
@@ -143,9 +148,7 @@ func (s *AccountService) UpdateAccount(accountID string, req *dto.UpdateAccountR
 		}
 
 		if *req.CreditLimit != currentLimit {
-			fmt.Printf("🔄 DEBUG - Updating CreditLimit from %.2f to %.2f\n", currentLimit, *req.CreditLimit)
-			account.CreditLimit = req.CreditLimit
-			updated = true
+			return nil, errors.NewValidationError("credit_limit", "invalid update: credit limit changes go through credit limit requests")
 		}
 	}
 
@@ -234,9 +237,18 @@ func (s *AccountService) DeleteAccount(accountID string) error {
 		return fmt.Errorf("failed to get account: %w", err)
 	}
 
-	// Check if account has balance
-	if account.Balance > 0 {
-		return fmt.Errorf("cannot delete account with balance: current balance %.2f", account.Balance)
+	// Accounts with balance, card balances or open installment plans go through the closure process for their
+	// final settlement, so deleting runs the same pre-checks
+	plans, err := s.closureRepo.GetOpenPlansByAccount(accountID)
+	if err != nil {
+		return fmt.Errorf("failed to get installment plans: %w", err)
+	}
+	if check := account.CheckClosure(account.Cards, plans); !check.CanClose {
+		blockers := make([]string, len(check.Blockers))
+		for i, blocker := range check.Blockers {
+			blockers[i] = blocker.Description
+		}
+		return fmt.Errorf("cannot delete account: %s, use the account closure process", strings.Join(blockers, "; "))
 	}
 
 	if err := s.accountRepo.Delete(accountID); err != nil {
@@ -272,6 +284,10 @@ func (s *AccountService) UpdateAccountBalance(accountID string, amount float64)
 		return 0, fmt.Errorf("failed to get account: %w", err)
 	}
 
+	if account.IsClosed() {
+		return 0, errors.ErrAccountClosed
+	}
+
 	// Calculate new balance
 	newBalance := account.Balance + amount
 
@@ -303,6 +319,11 @@ func (s *AccountService) UpdateAccountStatus(accountID string, isActive bool) (*
 		return nil, fmt.Errorf("failed to get account: %w", err)
 	}
 
+	// Closed accounts cannot be reactivated
+	if account.IsClosed() {
+		return nil, errors.ErrAccountClosed
+	}
+
 	// Update status
 	account.IsActive = isActive
 
@@ -82,9 +82,14 @@ func (m *MockAccountRepository) Delete(id string) error {
 // Verify interface compliance
 var _ repositories.AccountRepository = (*MockAccountRepository)(nil)
 
+// newTestAccountService creates an account service over accounts without installment plans
+func newTestAccountService(repo *MockAccountRepository) *AccountService {
+	return NewAccountService(repo, &MockAccountClosureRepository{cardRepo: &MockCardRepository{cards: make(map[string]*entities.Card)}})
+}
+
 func TestCreateAccount(t *testing.T) {
 	repo := NewMockAccountRepository()
-	service := NewAccountService(repo)
+	service := newTestAccountService(repo)
 
 	tests := []struct {
 		name        string
@@ -176,7 +181,7 @@ func TestCreateAccount(t *testing.T) {
 
 func TestGetAccountByID(t *testing.T) {
 	repo := NewMockAccountRepository()
-	service := NewAccountService(repo)
+	service := newTestAccountService(repo)
 
 	// Create test account
 	account := &entities.Account{
@@ -236,7 +241,7 @@ func TestGetAccountByID(t *testing.T) {
 
 func TestUpdateAccountBalance(t *testing.T) {
 	repo := NewMockAccountRepository()
-	service := NewAccountService(repo)
+	service := newTestAccountService(repo)
 
 	// Create test account
 	account := &entities.Account{
@@ -306,7 +311,7 @@ func TestUpdateAccountBalance(t *testing.T) {
 
 func TestUpdateAccountStatus(t *testing.T) {
 	repo := NewMockAccountRepository()
-	service := NewAccountService(repo)
+	service := newTestAccountService(repo)
 
 	// Create test account
 	account := &entities.Account{
@@ -370,7 +375,7 @@ func TestUpdateAccountStatus(t *testing.T) {
 
 func TestGetAccountsByUserID(t *testing.T) {
 	repo := NewMockAccountRepository()
-	service := NewAccountService(repo)
+	service := newTestAccountService(repo)
 
 	userID := uuid.NewString()
 	otherUserID := uuid.NewString()
@@ -459,7 +464,9 @@ func TestGetAccountsByUserID(t *testing.T) {
 
 func TestDeleteAccount(t *testing.T) {
 	repo := NewMockAccountRepository()
-	service := NewAccountService(repo)
+	cardRepo := &MockCardRepository{cards: make(map[string]*entities.Card)}
+	closureRepo := &MockAccountClosureRepository{cardRepo: cardRepo}
+	service := NewAccountService(repo, closureRepo)
 
 	// Create test account
 	account := &entities.Account{
@@ -483,6 +490,18 @@ func TestDeleteAccount(t *testing.T) {
 	}
 	createdAccountWithBalance, _ := service.CreateAccount(accountWithBalance)
 
+	// And one with no balance but an installment plan still open on its credit card
+	accountWithPlan := newClosureAccount(t, repo, uuid.NewString(), entities.CurrencyARS, 0)
+	card := addClosureCard(accountWithPlan, cardRepo, 0)
+	closureRepo.plans = append(closureRepo.plans, &entities.InstallmentPlan{
+		ID:              uuid.NewString(),
+		CardID:          card.ID,
+		UserID:          accountWithPlan.UserID,
+		Description:     "Televisor",
+		Status:          entities.InstallmentPlanStatusActive,
+		RemainingAmount: 250,
+	})
+
 	tests := []struct {
 		name        string
 		accountID   string
@@ -498,6 +517,11 @@ func TestDeleteAccount(t *testing.T) {
 			accountID:   createdAccountWithBalance.ID,
 			expectError: true,
 		},
+		{
+			name:        "cannot delete account with open installment plans",
+			accountID:   accountWithPlan.ID,
+			expectError: true,
+		},
 		{
 			name:        "non-existing account",
 			accountID:   uuid.NewString(),
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/card/dto"
+)
+
+// CardControlsService provides business logic for per-card spending controls
+type CardControlsService struct {
+	controlsRepo      ports.CardControlsRepositoryInterface
+	cardRepo          ports.CardRepositoryInterface
+	membershipService ports.MembershipServiceInterface
+	now               func() time.Time
+}
+
+// NewCardControlsService creates a new card controls service instance
+func NewCardControlsService(controlsRepo ports.CardControlsRepositoryInterface, cardRepo ports.CardRepositoryInterface, membershipService ports.MembershipServiceInterface) *CardControlsService {
+	return &CardControlsService{
+		controlsRepo:      controlsRepo,
+		cardRepo:          cardRepo,
+		membershipService: membershipService,
+		now:               time.Now,
+	}
+}
+
+// GetControls retrieves the controls of a card. Cards without controls get the permissive defaults.
+func (s *CardControlsService) GetControls(cardID, userID string) (*entities.CardControls, error) {
+	card, err := s.getCard(cardID, userID, s.membershipService.CanViewCard)
+	if err != nil {
+		return nil, err
+	}
+	return s.getOrDefault(card.ID)
+}
+
+// UpdateControls changes the controls of a card. Omitted fields keep their current value.
+func (s *CardControlsService) UpdateControls(cardID, userID string, req *dto.UpdateCardControlsRequest) (*entities.CardControls, error) {
+	controls, err := s.getManagedControls(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BlockedMerchants != nil {
+		controls.BlockedMerchants = req.BlockedMerchants
+	}
+	if req.BlockedCategories != nil {
+		controls.BlockedCategories = req.BlockedCategories
+	}
+	if req.OnlinePurchasesEnabled != nil {
+		controls.OnlinePurchasesBlocked = !*req.OnlinePurchasesEnabled
+	}
+	if req.MaxTransactionAmount != nil {
+		if *req.MaxTransactionAmount == 0 {
+			controls.MaxTransactionAmount = nil
+		} else {
+			controls.MaxTransactionAmount = req.MaxTransactionAmount
+		}
+	}
+	if req.AllowedCurrencies != nil {
+		currencies := make([]string, len(req.AllowedCurrencies))
+		for i, currency := range req.AllowedCurrencies {
+			currencies[i] = strings.ToUpper(currency)
+		}
+		controls.AllowedCurrencies = currencies
+	}
+
+	return s.save(controls, userID)
+}
+
+// FreezeCard freezes a card until the given time, after which it is unfrozen automatically
+func (s *CardControlsService) FreezeCard(cardID, userID string, until time.Time, reason string) (*entities.CardControls, error) {
+	controls, err := s.getManagedControls(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := controls.Freeze(until, reason, s.now()); err != nil {
+		return nil, validateEntity(err)
+	}
+
+	return s.save(controls, userID)
+}
+
+// UnfreezeCard lifts the temporary freeze of a card before it expires
+func (s *CardControlsService) UnfreezeCard(cardID, userID string) (*entities.CardControls, error) {
+	controls, err := s.getManagedControls(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	controls.Unfreeze()
+	return s.save(controls, userID)
+}
+
+// GetRejections retrieves the operations rejected by the controls of a card
+func (s *CardControlsService) GetRejections(cardID, userID string, page, pageSize int) ([]*entities.CardControlRejection, int64, error) {
+	card, err := s.getCard(cardID, userID, s.membershipService.CanViewCard)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	return s.controlsRepo.GetRejectionsByCard(card.ID, pageSize, offset)
+}
+
+// EvaluateOperation checks an operation against the controls of the card.
+// Rejections are recorded and returned as *errors.CardControlError carrying the reason code.
+func (s *CardControlsService) EvaluateOperation(card *entities.Card, amount float64, purchase entities.PurchaseDetails, attemptedBy string) error {
+	controls, err := s.controlsRepo.GetByCard(card.ID)
+	if err != nil {
+		return err
+	}
+	if controls == nil {
+		return nil
+	}
+
+	now := s.now()
+
+	// Auto-unfreeze once the freeze period is over
+	if controls.FreezeExpired(now) {
+		controls.Unfreeze()
+		if _, err := s.controlsRepo.Save(controls); err != nil {
+			fmt.Printf("Warning: failed to clear expired freeze of card %s: %v\n", card.ID, err)
+		}
+	}
+
+	if purchase.Currency == "" {
+		purchase.Currency = card.Account.Currency
+	}
+
+	reason, message := controls.Evaluate(amount, purchase, now)
+	if reason == "" {
+		return nil
+	}
+
+	fmt.Printf("🚫 Card control rejection - card: %s, reason: %s, amount: %.2f, merchant: %s, category: %s, online: %t, by: %s\n",
+		card.ID, reason, amount, purchase.MerchantName, purchase.Category, purchase.IsOnline, attemptedBy)
+
+	rejection := &entities.CardControlRejection{
+		CardID:       card.ID,
+		Reason:       reason,
+		Message:      message,
+		Amount:       amount,
+		Currency:     purchase.Currency,
+		MerchantName: purchase.MerchantName,
+		Category:     purchase.Category,
+		IsOnline:     purchase.IsOnline,
+		AttemptedBy:  attemptedBy,
+	}
+	if err := s.controlsRepo.CreateRejection(rejection); err != nil {
+		// Log error but still reject the operation
+		fmt.Printf("Warning: failed to record card control rejection: %v\n", err)
+	}
+
+	return errors.NewCardControlError(string(reason), message)
+}
+
+// getCard loads a card verifying the user access with the given membership check
+func (s *CardControlsService) getCard(cardID, userID string, check func(*entities.Card, string) (bool, error)) (*entities.Card, error) {
+	card, err := s.cardRepo.GetByIDWithAccount(cardID)
+	if err != nil {
+		return nil, fmt.Errorf("card not found: %w", err)
+	}
+
+	allowed, err := check(card, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify card access: %w", err)
+	}
+	if !allowed {
+		return nil, errors.ErrInsufficientRights
+	}
+	return card, nil
+}
+
+// getManagedControls loads the controls of a card verifying the user can change them.
+// Only users that operate the whole account can set controls, so card holders cannot lift their own.
+func (s *CardControlsService) getManagedControls(cardID, userID string) (*entities.CardControls, error) {
+	card, err := s.getCard(cardID, userID, func(card *entities.Card, userID string) (bool, error) {
+		return s.membershipService.CanOperateAccount(&card.Account, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.getOrDefault(card.ID)
+}
+
+// getOrDefault retrieves the controls of a card or the permissive defaults when none were set
+func (s *CardControlsService) getOrDefault(cardID string) (*entities.CardControls, error) {
+	controls, err := s.controlsRepo.GetByCard(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if controls == nil {
+		controls = &entities.CardControls{CardID: cardID}
+	}
+	return controls, nil
+}
+
+// save validates and stores the controls
+func (s *CardControlsService) save(controls *entities.CardControls, userID string) (*entities.CardControls, error) {
+	controls.UpdatedBy = userID
+	if err := controls.Validate(); err != nil {
+		return nil, validateEntity(err)
+	}
+	return s.controlsRepo.Save(controls)
+}
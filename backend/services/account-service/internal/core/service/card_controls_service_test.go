@@ -0,0 +1,236 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/card/dto"
+	"github.com/google/uuid"
+)
+
+// MockCardControlsRepository implements a mock card controls repository for testing
+type MockCardControlsRepository struct {
+	controls   map[string]*entities.CardControls
+	rejections []*entities.CardControlRejection
+}
+
+func NewMockCardControlsRepository() *MockCardControlsRepository {
+	return &MockCardControlsRepository{
+		controls: make(map[string]*entities.CardControls),
+	}
+}
+
+func (m *MockCardControlsRepository) GetByCard(cardID string) (*entities.CardControls, error) {
+	return m.controls[cardID], nil
+}
+
+func (m *MockCardControlsRepository) Save(controls *entities.CardControls) (*entities.CardControls, error) {
+	if controls.ID == "" {
+		controls.ID = uuid.NewString()
+	}
+	m.controls[controls.CardID] = controls
+	return controls, nil
+}
+
+func (m *MockCardControlsRepository) CreateRejection(rejection *entities.CardControlRejection) error {
+	m.rejections = append(m.rejections, rejection)
+	return nil
+}
+
+func (m *MockCardControlsRepository) GetRejectionsByCard(cardID string, limit, offset int) ([]*entities.CardControlRejection, int64, error) {
+	var rejections []*entities.CardControlRejection
+	for _, rejection := range m.rejections {
+		if rejection.CardID == cardID {
+			rejections = append(rejections, rejection)
+		}
+	}
+	total := int64(len(rejections))
+	if offset >= len(rejections) {
+		return nil, total, nil
+	}
+	end := offset + limit
+	if end > len(rejections) {
+		end = len(rejections)
+	}
+	return rejections[offset:end], total, nil
+}
+
+// Verify interface compliance
+var _ ports.CardControlsRepositoryInterface = (*MockCardControlsRepository)(nil)
+
+func setupCardControlsService(t *testing.T) (*CardControlsService, *MockCardControlsRepository, *entities.Card) {
+	accountRepo := NewMockAccountRepository()
+	account := &entities.Account{
+		UserID:      uuid.NewString(),
+		AccountType: entities.AccountTypeBankAccount,
+		Name:        "Banco Galicia",
+		Currency:    entities.CurrencyARS,
+		IsActive:    true,
+	}
+	if err := accountRepo.Create(account); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	card := newCreditCard(account.ID, 0)
+	card.Account = *account
+	cardRepo := &MockCardRepository{cards: map[string]*entities.Card{card.ID: card}}
+	membershipService := NewMembershipService(NewMockAccountMemberRepository(), accountRepo, cardRepo)
+	controlsRepo := NewMockCardControlsRepository()
+
+	return NewCardControlsService(controlsRepo, cardRepo, membershipService), controlsRepo, card
+}
+
+func TestEvaluateOperation(t *testing.T) {
+	service, controlsRepo, card := setupCardControlsService(t)
+	owner := card.Account.UserID
+
+	maxAmount := 50000.0
+	enabled := false
+	_, err := service.UpdateControls(card.ID, owner, &dto.UpdateCardControlsRequest{
+		BlockedMerchants:       []string{"Casino"},
+		BlockedCategories:      []string{"gambling"},
+		OnlinePurchasesEnabled: &enabled,
+		MaxTransactionAmount:   &maxAmount,
+		AllowedCurrencies:      []string{"ars"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateControls() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		amount         float64
+		purchase       entities.PurchaseDetails
+		expectedReason entities.CardControlReason
+	}{
+		{name: "allowed purchase", amount: 1000, purchase: entities.PurchaseDetails{MerchantName: "Supermercado Día", Category: "groceries"}},
+		{name: "merchant blocked by partial name", amount: 1000, purchase: entities.PurchaseDetails{MerchantName: "CASINO Buenos Aires"}, expectedReason: entities.CardControlReasonMerchantBlocked},
+		{name: "category blocked", amount: 1000, purchase: entities.PurchaseDetails{Category: "Gambling"}, expectedReason: entities.CardControlReasonCategoryBlocked},
+		{name: "online purchase disabled", amount: 1000, purchase: entities.PurchaseDetails{IsOnline: true}, expectedReason: entities.CardControlReasonOnlineDisabled},
+		{name: "per-transaction cap exceeded", amount: 50000.01, expectedReason: entities.CardControlReasonAmountExceeded},
+		{name: "currency not allowed", amount: 100, purchase: entities.PurchaseDetails{Currency: entities.CurrencyUSD}, expectedReason: entities.CardControlReasonCurrencyNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(controlsRepo.rejections)
+			err := service.EvaluateOperation(card, tt.amount, tt.purchase, owner)
+
+			if tt.expectedReason == "" {
+				if err != nil {
+					t.Errorf("EvaluateOperation() unexpected error: %v", err)
+				}
+				if len(controlsRepo.rejections) != before {
+					t.Error("EvaluateOperation() recorded a rejection for an allowed operation")
+				}
+				return
+			}
+
+			controlErr, ok := err.(*errors.CardControlError)
+			if !ok {
+				t.Fatalf("EvaluateOperation() error = %v, want *errors.CardControlError", err)
+			}
+			if controlErr.Reason != string(tt.expectedReason) {
+				t.Errorf("EvaluateOperation() reason = %v, want %v", controlErr.Reason, tt.expectedReason)
+			}
+			if len(controlsRepo.rejections) != before+1 {
+				t.Fatalf("EvaluateOperation() recorded %d rejections, want 1", len(controlsRepo.rejections)-before)
+			}
+			if rejection := controlsRepo.rejections[before]; rejection.Reason != tt.expectedReason || rejection.Amount != tt.amount {
+				t.Errorf("EvaluateOperation() recorded %+v", rejection)
+			}
+		})
+	}
+}
+
+func TestEvaluateOperationWithoutControls(t *testing.T) {
+	service, controlsRepo, card := setupCardControlsService(t)
+
+	if err := service.EvaluateOperation(card, 1000000, entities.PurchaseDetails{IsOnline: true}, card.Account.UserID); err != nil {
+		t.Errorf("EvaluateOperation() unexpected error: %v", err)
+	}
+	if len(controlsRepo.rejections) != 0 {
+		t.Errorf("EvaluateOperation() recorded %d rejections, want 0", len(controlsRepo.rejections))
+	}
+}
+
+func TestFreezeCard(t *testing.T) {
+	service, controlsRepo, card := setupCardControlsService(t)
+	owner := card.Account.UserID
+
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	if _, err := service.FreezeCard(card.ID, owner, now.Add(-time.Hour), "lost"); !errors.IsValidationError(err) {
+		t.Errorf("FreezeCard() with past end error = %v, want validation error", err)
+	}
+	if _, err := service.FreezeCard(card.ID, uuid.NewString(), now.Add(time.Hour), "lost"); err != errors.ErrInsufficientRights {
+		t.Errorf("FreezeCard() by stranger error = %v, want %v", err, errors.ErrInsufficientRights)
+	}
+
+	if _, err := service.FreezeCard(card.ID, owner, now.Add(24*time.Hour), "lost wallet"); err != nil {
+		t.Fatalf("FreezeCard() unexpected error: %v", err)
+	}
+
+	err := service.EvaluateOperation(card, 100, entities.PurchaseDetails{}, owner)
+	if controlErr, ok := err.(*errors.CardControlError); !ok || controlErr.Reason != string(entities.CardControlReasonFrozen) {
+		t.Fatalf("EvaluateOperation() on frozen card error = %v, want %v", err, entities.CardControlReasonFrozen)
+	}
+
+	// Auto-unfreeze once the period is over
+	now = now.Add(25 * time.Hour)
+	if err := service.EvaluateOperation(card, 100, entities.PurchaseDetails{}, owner); err != nil {
+		t.Errorf("EvaluateOperation() after freeze expired unexpected error: %v", err)
+	}
+	if controls := controlsRepo.controls[card.ID]; controls.FrozenUntil != nil || controls.FreezeReason != "" {
+		t.Errorf("EvaluateOperation() did not clear the expired freeze: %+v", controls)
+	}
+}
+
+func TestUnfreezeCard(t *testing.T) {
+	service, _, card := setupCardControlsService(t)
+	owner := card.Account.UserID
+
+	if _, err := service.FreezeCard(card.ID, owner, time.Now().Add(time.Hour), ""); err != nil {
+		t.Fatalf("FreezeCard() unexpected error: %v", err)
+	}
+
+	controls, err := service.UnfreezeCard(card.ID, owner)
+	if err != nil {
+		t.Fatalf("UnfreezeCard() unexpected error: %v", err)
+	}
+	if controls.IsFrozen(time.Now()) {
+		t.Error("UnfreezeCard() card is still frozen")
+	}
+	if err := service.EvaluateOperation(card, 100, entities.PurchaseDetails{}, owner); err != nil {
+		t.Errorf("EvaluateOperation() after unfreeze unexpected error: %v", err)
+	}
+}
+
+func TestGetRejections(t *testing.T) {
+	service, _, card := setupCardControlsService(t)
+	owner := card.Account.UserID
+
+	maxAmount := 100.0
+	if _, err := service.UpdateControls(card.ID, owner, &dto.UpdateCardControlsRequest{MaxTransactionAmount: &maxAmount}); err != nil {
+		t.Fatalf("UpdateControls() unexpected error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		_ = service.EvaluateOperation(card, 500, entities.PurchaseDetails{}, owner)
+	}
+
+	rejections, total, err := service.GetRejections(card.ID, owner, 1, 2)
+	if err != nil {
+		t.Fatalf("GetRejections() unexpected error: %v", err)
+	}
+	if total != 3 || len(rejections) != 2 {
+		t.Errorf("GetRejections() = %d rejections of %d, want 2 of 3", len(rejections), total)
+	}
+
+	if _, _, err := service.GetRejections(card.ID, uuid.NewString(), 1, 10); err != errors.ErrInsufficientRights {
+		t.Errorf("GetRejections() by stranger error = %v, want %v", err, errors.ErrInsufficientRights)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+)
+
+// CardNumberService handles the audited access to card numbers and the rotation of their keys
+type CardNumberService struct {
+	numberRepo ports.CardNumberRepositoryInterface
+	cardRepo   ports.CardRepositoryInterface
+	cipher     ports.CardNumberCipherInterface
+}
+
+// NewCardNumberService creates a new card number service
+func NewCardNumberService(numberRepo ports.CardNumberRepositoryInterface, cardRepo ports.CardRepositoryInterface, cipher ports.CardNumberCipherInterface) *CardNumberService {
+	return &CardNumberService{
+		numberRepo: numberRepo,
+		cardRepo:   cardRepo,
+		cipher:     cipher,
+	}
+}
+
+// RevealCardNumber decrypts the number of a card for an administrator. Every attempt is
+// recorded, and the number is only returned once its access has been logged.
+func (s *CardNumberService) RevealCardNumber(cardID, adminID, reason, clientIP string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", errors.NewValidationError("reason", "a reason is required to reveal a card number")
+	}
+
+	access := &entities.CardNumberAccessLog{
+		CardID:     cardID,
+		AccessedBy: adminID,
+		Reason:     reason,
+		ClientIP:   clientIP,
+	}
+
+	card, err := s.cardRepo.GetByID(cardID)
+	if err != nil {
+		s.recordDenied(access, "card not found")
+		return "", fmt.Errorf("card not found: %w", err)
+	}
+	access.KeyFingerprint = card.KeyFingerprint
+
+	if !s.cipher.IsSealed(card.EncryptedNumber) {
+		s.recordDenied(access, "card number not sealed")
+		return "", fmt.Errorf("card number is not sealed yet, run the key rotation first")
+	}
+
+	number, err := s.cipher.Open(card.EncryptedNumber, card.KeyFingerprint)
+	if err != nil {
+		s.recordDenied(access, "decryption failed")
+		return "", fmt.Errorf("failed to decrypt card number: %w", err)
+	}
+
+	access.Granted = true
+	if err := s.numberRepo.CreateAccessLog(access); err != nil {
+		return "", err
+	}
+
+	fmt.Printf("🔐 Card number of %s revealed to admin %s\n", cardID, adminID)
+
+	return string(number), nil
+}
+
+// GetAccessLog gets the number access log of a card
+func (s *CardNumberService) GetAccessLog(cardID string, page, pageSize int) ([]*entities.CardNumberAccessLog, int64, error) {
+	offset := (page - 1) * pageSize
+	return s.numberRepo.GetAccessLogsByCard(cardID, pageSize, offset)
+}
+
+// RotateKeys wraps the data key of every card number under the active master key, sealing
+// legacy values that were stored as sent by the client. Cards are processed in batches with
+// conditional updates, so the service keeps running with the old and new keys in the keyring.
+func (s *CardNumberService) RotateKeys(batchSize int) (*entities.KeyRotationResult, error) {
+	if batchSize < 1 {
+		return nil, errors.NewValidationError("batch_size", "batch size must be positive")
+	}
+
+	result := &entities.KeyRotationResult{ActiveFingerprint: s.cipher.ActiveFingerprint()}
+	afterID := ""
+
+	for {
+		cards, err := s.numberRepo.GetCardsToRotate(result.ActiveFingerprint, afterID, batchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(cards) == 0 {
+			return result, nil
+		}
+
+		for _, card := range cards {
+			result.Scanned++
+			afterID = card.ID
+
+			var encrypted, fingerprint string
+			sealed := s.cipher.IsSealed(card.EncryptedNumber)
+			switch {
+			case sealed && !s.cipher.Has(card.KeyFingerprint):
+				fmt.Printf("❌ Card %s is wrapped by a master key missing from the keyring\n", card.ID)
+				result.Failed++
+				continue
+			case sealed:
+				encrypted, fingerprint, err = s.cipher.Rewrap(card.EncryptedNumber, card.KeyFingerprint)
+			default:
+				encrypted, fingerprint, err = s.cipher.Seal([]byte(card.EncryptedNumber))
+			}
+			if err != nil {
+				fmt.Printf("❌ Failed to rotate the number of card %s: %v\n", card.ID, err)
+				result.Failed++
+				continue
+			}
+
+			updated, err := s.numberRepo.UpdateEncryptedNumber(card.ID, card.EncryptedNumber, encrypted, fingerprint)
+			if err != nil {
+				return result, err
+			}
+			switch {
+			case !updated:
+				result.Skipped++
+			case sealed:
+				result.Rewrapped++
+			default:
+				result.Sealed++
+			}
+		}
+	}
+}
+
+// recordDenied logs a failed access attempt. Failing to log it does not hide the original error.
+func (s *CardNumberService) recordDenied(access *entities.CardNumberAccessLog, reason string) {
+	access.Granted = false
+	access.FailureReason = reason
+	if err := s.numberRepo.CreateAccessLog(access); err != nil {
+		fmt.Printf("⚠️ Failed to record card number access to %s: %v\n", access.CardID, err)
+	}
+}
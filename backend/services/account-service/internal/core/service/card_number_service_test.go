@@ -0,0 +1,171 @@
+package service
+
+import (
+	"crypto/sha256"
+	"sort"
+	"testing"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/fintrack/account-service/internal/infrastructure/encryption"
+	"github.com/google/uuid"
+)
+
+// MockCardNumberRepository implements a mock card number repository over the card repository mock
+type MockCardNumberRepository struct {
+	cardRepo *MockCardRepository
+	logs     []*entities.CardNumberAccessLog
+}
+
+func (m *MockCardNumberRepository) GetCardsToRotate(activeFingerprint, afterID string, limit int) ([]*entities.Card, error) {
+	var cards []*entities.Card
+	for _, card := range m.cardRepo.cards {
+		if card.KeyFingerprint != activeFingerprint && card.ID > afterID {
+			copied := *card
+			cards = append(cards, &copied)
+		}
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].ID < cards[j].ID })
+	if len(cards) > limit {
+		cards = cards[:limit]
+	}
+	return cards, nil
+}
+
+func (m *MockCardNumberRepository) UpdateEncryptedNumber(cardID, previousNumber, encryptedNumber, fingerprint string) (bool, error) {
+	card, ok := m.cardRepo.cards[cardID]
+	if !ok || card.EncryptedNumber != previousNumber {
+		return false, nil
+	}
+	card.EncryptedNumber = encryptedNumber
+	card.KeyFingerprint = fingerprint
+	return true, nil
+}
+
+func (m *MockCardNumberRepository) CreateAccessLog(log *entities.CardNumberAccessLog) error {
+	m.logs = append(m.logs, log)
+	return nil
+}
+
+func (m *MockCardNumberRepository) GetAccessLogsByCard(cardID string, limit, offset int) ([]*entities.CardNumberAccessLog, int64, error) {
+	var logs []*entities.CardNumberAccessLog
+	for _, log := range m.logs {
+		if log.CardID == cardID {
+			logs = append(logs, log)
+		}
+	}
+	return logs, int64(len(logs)), nil
+}
+
+// Verify interface compliance
+var _ ports.CardNumberRepositoryInterface = (*MockCardNumberRepository)(nil)
+
+func testKeyring(t *testing.T, seeds ...string) *encryption.Keyring {
+	keys := make([][]byte, len(seeds))
+	for i, seed := range seeds {
+		key := sha256.Sum256([]byte(seed))
+		keys[i] = key[:]
+	}
+	keyring, err := encryption.NewKeyring(keys...)
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	return keyring
+}
+
+func newCardWithNumber(t *testing.T, keyring *encryption.Keyring, number string) *entities.Card {
+	card := newCreditCard(uuid.NewString(), 0)
+	sealed, fingerprint, err := keyring.Seal([]byte(number))
+	if err != nil {
+		t.Fatalf("failed to seal card number: %v", err)
+	}
+	card.EncryptedNumber = sealed
+	card.KeyFingerprint = fingerprint
+	return card
+}
+
+func TestRevealCardNumber(t *testing.T) {
+	keyring := testKeyring(t, "master-1")
+	card := newCardWithNumber(t, keyring, "4111111111111111")
+	cardRepo := &MockCardRepository{cards: map[string]*entities.Card{card.ID: card}}
+	numberRepo := &MockCardNumberRepository{cardRepo: cardRepo}
+	service := NewCardNumberService(numberRepo, cardRepo, keyring)
+	adminID := uuid.NewString()
+
+	if _, err := service.RevealCardNumber(card.ID, adminID, " ", "10.0.0.1"); !errors.IsValidationError(err) {
+		t.Errorf("RevealCardNumber() without reason error = %v, want validation error", err)
+	}
+
+	number, err := service.RevealCardNumber(card.ID, adminID, "chargeback investigation", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("RevealCardNumber() unexpected error: %v", err)
+	}
+	if number != "4111111111111111" {
+		t.Errorf("RevealCardNumber() = %v, want 4111111111111111", number)
+	}
+
+	if _, err := service.RevealCardNumber(uuid.NewString(), adminID, "chargeback investigation", "10.0.0.1"); err == nil {
+		t.Error("RevealCardNumber() of unknown card expected error but got none")
+	}
+
+	if len(numberRepo.logs) != 2 {
+		t.Fatalf("RevealCardNumber() recorded %d accesses, want 2", len(numberRepo.logs))
+	}
+	granted, denied := numberRepo.logs[0], numberRepo.logs[1]
+	if !granted.Granted || granted.AccessedBy != adminID || granted.Reason != "chargeback investigation" || granted.KeyFingerprint != card.KeyFingerprint {
+		t.Errorf("RevealCardNumber() recorded %+v", granted)
+	}
+	if denied.Granted || denied.FailureReason == "" {
+		t.Errorf("RevealCardNumber() recorded failed access as %+v", denied)
+	}
+}
+
+func TestRotateKeys(t *testing.T) {
+	oldKeyring := testKeyring(t, "master-1")
+	rotated := newCardWithNumber(t, oldKeyring, "4111111111111111")
+	legacy := newCreditCard(uuid.NewString(), 0)
+	legacy.EncryptedNumber = "bGVnYWN5LWNsaWVudC1wYXlsb2Fk"
+	legacy.KeyFingerprint = "client-fingerprint"
+	orphan := newCardWithNumber(t, testKeyring(t, "lost-master"), "5500000000000004")
+
+	cardRepo := &MockCardRepository{cards: map[string]*entities.Card{rotated.ID: rotated, legacy.ID: legacy, orphan.ID: orphan}}
+	numberRepo := &MockCardNumberRepository{cardRepo: cardRepo}
+
+	// New master key first, old one kept until the rotation finishes
+	keyring := testKeyring(t, "master-2", "master-1")
+	service := NewCardNumberService(numberRepo, cardRepo, keyring)
+
+	if _, err := service.RotateKeys(0); !errors.IsValidationError(err) {
+		t.Errorf("RotateKeys(0) error = %v, want validation error", err)
+	}
+
+	result, err := service.RotateKeys(1)
+	if err != nil {
+		t.Fatalf("RotateKeys() unexpected error: %v", err)
+	}
+	if result.Scanned != 3 || result.Rewrapped != 1 || result.Sealed != 1 || result.Failed != 1 || result.Skipped != 0 {
+		t.Errorf("RotateKeys() = %+v", result)
+	}
+
+	for _, card := range []*entities.Card{rotated, legacy} {
+		if card.KeyFingerprint != keyring.ActiveFingerprint() {
+			t.Errorf("RotateKeys() card %s fingerprint = %v, want active key", card.ID, card.KeyFingerprint)
+		}
+	}
+
+	// Rotated numbers open with the new key alone
+	newOnly := testKeyring(t, "master-2")
+	if number, err := newOnly.Open(rotated.EncryptedNumber, rotated.KeyFingerprint); err != nil || string(number) != "4111111111111111" {
+		t.Errorf("Open() after rotation = %s, %v", number, err)
+	}
+	if payload, err := newOnly.Open(legacy.EncryptedNumber, legacy.KeyFingerprint); err != nil || string(payload) != "bGVnYWN5LWNsaWVudC1wYXlsb2Fk" {
+		t.Errorf("Open() of sealed legacy value = %s, %v", payload, err)
+	}
+
+	// A second run only finds the card whose key is missing
+	result, err = service.RotateKeys(10)
+	if err != nil || result.Scanned != 1 || result.Failed != 1 {
+		t.Errorf("RotateKeys() second run = %+v, %v", result, err)
+	}
+}
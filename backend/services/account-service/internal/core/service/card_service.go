@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
 	"github.com/fintrack/account-service/internal/core/ports"
 	"github.com/fintrack/account-service/internal/infrastructure/clients"
 	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/card/dto"
@@ -13,16 +14,24 @@ import (
 
 type CardService struct {
 	cardRepo           ports.CardRepositoryInterface
-	accountRepo        ports.AccountRepositoryInterface  // To validate account exists
-	installmentService ports.InstallmentServiceInterface // To handle installment plans
-	transactionClient  *clients.TransactionClient        // To record transactions
+	accountRepo        ports.AccountRepositoryInterface    // To validate account exists
+	creditLineRepo     ports.CreditLineRepositoryInterface // To attach cards to shared credit lines
+	installmentService ports.InstallmentServiceInterface   // To handle installment plans
+	membershipService  ports.MembershipServiceInterface    // To authorize members of shared accounts
+	controlsService    ports.CardControlsServiceInterface  // To enforce per-card spending controls
+	numberCipher       ports.CardNumberCipherInterface     // To seal card numbers under the master keyring
+	transactionClient  *clients.TransactionClient          // To record transactions
 }
 
-func NewCardService(cardRepo ports.CardRepositoryInterface, accountRepo ports.AccountRepositoryInterface, installmentService ports.InstallmentServiceInterface) *CardService {
+func NewCardService(cardRepo ports.CardRepositoryInterface, accountRepo ports.AccountRepositoryInterface, creditLineRepo ports.CreditLineRepositoryInterface, installmentService ports.InstallmentServiceInterface, membershipService ports.MembershipServiceInterface, controlsService ports.CardControlsServiceInterface, numberCipher ports.CardNumberCipherInterface) *CardService {
 	return &CardService{
 		cardRepo:           cardRepo,
 		accountRepo:        accountRepo,
+		creditLineRepo:     creditLineRepo,
 		installmentService: installmentService,
+		membershipService:  membershipService,
+		controlsService:    controlsService,
+		numberCipher:       numberCipher,
 		transactionClient:  clients.NewTransactionClient(),
 	}
 }
@@ -38,12 +47,22 @@ func (s *CardService) CreateCard(req *dto.CreateCardRequest) (*entities.Card, er
 		return nil, fmt.Errorf("account type %s cannot have cards", account.AccountType)
 	}
 
+	// Brand, type and masked digits come from the number itself, never from the client
+	number, err := entities.ParseCardNumber(req.CardNumber)
+	if err != nil {
+		return nil, validateEntity(err)
+	}
+	cardType, err := resolveCardType(number, req.CardType, req.CardBrand)
+	if err != nil {
+		return nil, err
+	}
+
 	// Set default due date if not provided and it's a credit card
 	var dueDate *time.Time
 	if req.DueDate != nil {
 		dueDate = req.DueDate.ToTimePointer()
 		fmt.Printf("🗓️ DEBUG - Due date provided: %s\n", dueDate.Format("2006-01-02"))
-	} else if req.CardType == "credit" {
+	} else if cardType == entities.CardTypeCredit {
 		// Set due date to the 5th of next month by default
 		now := time.Now()
 		nextMonth := now.AddDate(0, 1, 0)
@@ -58,14 +77,19 @@ func (s *CardService) CreateCard(req *dto.CreateCardRequest) (*entities.Card, er
 		closingDate = req.ClosingDate.ToTimePointer()
 	}
 
+	encryptedNumber, keyFingerprint, err := s.sealCardNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create card entity
 	card := &entities.Card{
 		ID:              uuid.New().String(),
 		AccountID:       req.AccountID,
-		CardType:        entities.CardType(req.CardType),
-		CardBrand:       entities.CardBrand(req.CardBrand),
-		LastFourDigits:  req.LastFourDigits,
-		MaskedNumber:    req.MaskedNumber,
+		CardType:        cardType,
+		CardBrand:       number.Brand,
+		LastFourDigits:  number.LastFourDigits,
+		MaskedNumber:    number.MaskedNumber,
 		HolderName:      req.HolderName,
 		ExpirationMonth: req.ExpirationMonth,
 		ExpirationYear:  req.ExpirationYear,
@@ -75,14 +99,29 @@ func (s *CardService) CreateCard(req *dto.CreateCardRequest) (*entities.Card, er
 		CreditLimit:     req.CreditLimit,
 		ClosingDate:     closingDate,
 		DueDate:         dueDate,
-		EncryptedNumber: req.EncryptedNumber,
-		KeyFingerprint:  req.KeyFingerprint,
+		CreditLineID:    req.CreditLineID,
+		IsAdditional:    req.IsAdditional,
+		SubLimit:        req.SubLimit,
+		EncryptedNumber: encryptedNumber,
+		KeyFingerprint:  keyFingerprint,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
 
 	fmt.Printf("🃏 DEBUG - Creating card with DueDate: %v\n", dueDate)
 
+	// Cards joining a shared credit line must belong to the same account
+	if req.CreditLineID != nil {
+		creditLine, err := s.creditLineRepo.GetByID(*req.CreditLineID)
+		if err != nil {
+			return nil, fmt.Errorf("credit line not found: %w", err)
+		}
+		if creditLine.AccountID != req.AccountID {
+			return nil, fmt.Errorf("credit line does not belong to this account")
+		}
+		card.CreditLine = creditLine
+	}
+
 	// Validate card data
 	if err := card.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid card data: %w", err)
@@ -213,6 +252,11 @@ func (s *CardService) UpdateCard(cardID string, req *dto.UpdateCardRequest) (*en
 			return nil, fmt.Errorf("credit limit can only be updated for credit cards")
 		}
 
+		// Cards in a shared credit line draw from the line limit
+		if card.CreditLineID != nil {
+			return nil, fmt.Errorf("card belongs to a credit line: update the credit line limit or the card sub-limit instead")
+		}
+
 		// Validate minimum credit limit
 		if *req.CreditLimit < 0 {
 			return nil, fmt.Errorf("credit limit cannot be negative")
@@ -224,16 +268,9 @@ func (s *CardService) UpdateCard(cardID string, req *dto.UpdateCardRequest) (*en
 			currentLimit = *card.CreditLimit
 		}
 
+		// Credit limit changes go through change requests, which keep the history of limits
 		if *req.CreditLimit != currentLimit {
-			fmt.Printf("🔄 DEBUG - Updating CreditLimit from %.2f to %.2f\n", currentLimit, *req.CreditLimit)
-
-			// Validate against current balance
-			if *req.CreditLimit < card.Balance {
-				return nil, fmt.Errorf("credit limit (%.2f) cannot be lower than current balance (%.2f)", *req.CreditLimit, card.Balance)
-			}
-
-			card.CreditLimit = req.CreditLimit
-			updated = true
+			return nil, errors.NewValidationError("credit_limit", "credit limit changes go through credit limit requests")
 		}
 	}
 
@@ -330,6 +367,93 @@ func (s *CardService) SetDefaultCard(cardID string) (*entities.Card, error) {
 	return updatedCard, nil
 }
 
+// RenewCard issues the replacement of an expiring or expired card. The new card inherits the
+// balance, credit settings, spending controls, card holders and active installment plans,
+// and the old card is left inactive.
+func (s *CardService) RenewCard(cardID, userID string, req *dto.RenewCardRequest) (*entities.Card, error) {
+	card, err := s.cardRepo.GetByIDWithAccount(cardID)
+	if err != nil {
+		return nil, fmt.Errorf("card not found: %w", err)
+	}
+
+	// Issuing cards is reserved to users that operate the whole account
+	canOperate, err := s.membershipService.CanOperateAccount(&card.Account, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify account access: %w", err)
+	}
+	if !canOperate {
+		return nil, errors.ErrInsufficientRights
+	}
+
+	// The replacement keeps the brand and type of the renewed card
+	number, err := entities.ParseCardNumber(req.CardNumber)
+	if err != nil {
+		return nil, validateEntity(err)
+	}
+	if _, err := resolveCardType(number, string(card.CardType), string(card.CardBrand)); err != nil {
+		return nil, err
+	}
+
+	encryptedNumber, keyFingerprint, err := s.sealCardNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := &entities.Card{
+		ID:              uuid.New().String(),
+		LastFourDigits:  number.LastFourDigits,
+		MaskedNumber:    number.MaskedNumber,
+		ExpirationMonth: req.ExpirationMonth,
+		ExpirationYear:  req.ExpirationYear,
+		EncryptedNumber: encryptedNumber,
+		KeyFingerprint:  keyFingerprint,
+	}
+
+	if err := card.Renew(replacement); err != nil {
+		return nil, validateEntity(err)
+	}
+	if err := replacement.Validate(); err != nil {
+		return nil, validateEntity(err)
+	}
+
+	if err := s.cardRepo.Replace(card, replacement); err != nil {
+		return nil, fmt.Errorf("failed to renew card: %w", err)
+	}
+
+	fmt.Printf("🔄 Card %s renewed as %s (balance %.2f transferred)\n", card.ID, replacement.ID, replacement.Balance)
+
+	return replacement, nil
+}
+
+// sealCardNumber seals the card number with a fresh data key wrapped by the active master
+// key, returning the fingerprint of that key
+func (s *CardService) sealCardNumber(number *entities.CardNumberDetails) (string, string, error) {
+	sealed, fingerprint, err := s.numberCipher.Seal([]byte(number.Number))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to seal card number: %w", err)
+	}
+	return sealed, fingerprint, nil
+}
+
+// resolveCardType checks the brand and type asserted by the client against those detected
+// from the card number, returning the card type. The type is required when the BIN does not tell.
+func resolveCardType(number *entities.CardNumberDetails, cardType, cardBrand string) (entities.CardType, error) {
+	if cardBrand != "" && entities.CardBrand(cardBrand) != number.Brand {
+		return "", errors.NewValidationError("card_brand", fmt.Sprintf("card brand %s does not match the card number (%s)", cardBrand, number.Brand))
+	}
+
+	if cardType == "" {
+		if number.Type == "" {
+			return "", errors.NewValidationError("card_type", "card type cannot be detected from the card number and is required")
+		}
+		return number.Type, nil
+	}
+	if number.Type != "" && entities.CardType(cardType) != number.Type {
+		return "", errors.NewValidationError("card_type", fmt.Sprintf("card type %s does not match the card number (%s)", cardType, number.Type))
+	}
+	return entities.CardType(cardType), nil
+}
+
 // GetCardByIDWithAccount gets a card by ID with account preloaded
 func (s *CardService) GetCardByIDWithAccount(cardID string) (*entities.Card, error) {
 	card, err := s.cardRepo.GetByIDWithAccount(cardID)
@@ -342,19 +466,32 @@ func (s *CardService) GetCardByIDWithAccount(cardID string) (*entities.Card, err
 // CREDIT CARD FINANCIAL OPERATIONS
 
 // ChargeCard processes a charge to a credit card
-func (s *CardService) ChargeCard(cardID string, amount float64, description, reference string) (*entities.Card, error) {
+func (s *CardService) ChargeCard(cardID string, amount float64, description, reference, performedBy string, purchase entities.PurchaseDetails) (*entities.Card, error) {
 	// Get card with account data
 	card, err := s.cardRepo.GetByIDWithAccount(cardID)
 	if err != nil {
 		return nil, fmt.Errorf("card not found: %w", err)
 	}
 
+	if err := s.authorizeCardOperation(card, performedBy); err != nil {
+		return nil, err
+	}
+
 	// Validate that it's a credit card
 	if card.CardType != entities.CardTypeCredit {
 		return nil, fmt.Errorf("charges can only be made to credit cards")
 	}
 
-	// Use the business logic from the entity
+	// Evaluate the spending controls of the card
+	if err := s.controlsService.EvaluateOperation(card, amount, purchase, performedBy); err != nil {
+		return nil, err
+	}
+
+	return s.applyCharge(card, amount)
+}
+
+// applyCharge charges the card using the entity business logic and saves it
+func (s *CardService) applyCharge(card *entities.Card, amount float64) (*entities.Card, error) {
 	if err := card.Charge(amount); err != nil {
 		return nil, fmt.Errorf("failed to charge card: %w", err)
 	}
@@ -369,13 +506,17 @@ func (s *CardService) ChargeCard(cardID string, amount float64, description, ref
 }
 
 // PaymentCard processes a payment to a credit card
-func (s *CardService) PaymentCard(cardID string, amount float64, paymentMethod, reference string) (*entities.Card, error) {
+func (s *CardService) PaymentCard(cardID string, amount float64, paymentMethod, reference, performedBy string) (*entities.Card, error) {
 	// Get card
 	card, err := s.cardRepo.GetByID(cardID)
 	if err != nil {
 		return nil, fmt.Errorf("card not found: %w", err)
 	}
 
+	if err := s.authorizeCardOperation(card, performedBy); err != nil {
+		return nil, err
+	}
+
 	// Use the business logic from the entity
 	if err := card.Payment(amount); err != nil {
 		return nil, fmt.Errorf("failed to process payment: %w", err)
@@ -393,18 +534,27 @@ func (s *CardService) PaymentCard(cardID string, amount float64, paymentMethod,
 // DEBIT CARD OPERATIONS
 
 // ProcessDebitTransaction processes a transaction with a debit card
-func (s *CardService) ProcessDebitTransaction(cardID string, amount float64, description, merchantName, reference string) (*entities.Card, error) {
+func (s *CardService) ProcessDebitTransaction(cardID string, amount float64, description, reference, performedBy string, purchase entities.PurchaseDetails) (*entities.Card, error) {
 	// Get card with account data
 	card, err := s.cardRepo.GetByIDWithAccount(cardID)
 	if err != nil {
 		return nil, fmt.Errorf("card not found: %w", err)
 	}
 
+	if err := s.authorizeCardOperation(card, performedBy); err != nil {
+		return nil, err
+	}
+
 	// Validate that it's a debit card
 	if card.CardType != entities.CardTypeDebit {
 		return nil, fmt.Errorf("transactions can only be made with debit cards")
 	}
 
+	// Evaluate the spending controls of the card
+	if err := s.controlsService.EvaluateOperation(card, amount, purchase, performedBy); err != nil {
+		return nil, err
+	}
+
 	// Use the business logic from the entity
 	if err := card.Charge(amount); err != nil {
 		return nil, fmt.Errorf("failed to process transaction: %w", err)
@@ -415,16 +565,22 @@ func (s *CardService) ProcessDebitTransaction(cardID string, amount float64, des
 		return nil, fmt.Errorf("failed to update account balance: %w", err)
 	}
 
-	// Record transaction in transaction service (async, don't fail if this fails)
+	// Record transaction in transaction service (async, don't fail if this fails).
+	// The account owner keeps the record while the member that made the purchase is the initiator.
 	go func() {
-		userID := card.Account.UserID // Assuming account has UserID field
+		userID := card.Account.UserID
+		initiatedBy := performedBy
+		if initiatedBy == "" {
+			initiatedBy = userID
+		}
 		if err := s.transactionClient.CreateDebitCardTransaction(
 			userID,
+			initiatedBy,
 			card.Account.ID,
 			cardID,
 			amount,
 			description,
-			merchantName,
+			purchase.MerchantName,
 			reference,
 		); err != nil {
 			// Log error but don't fail the transaction
@@ -438,25 +594,6 @@ func (s *CardService) ProcessDebitTransaction(cardID string, amount float64, des
 		return nil, fmt.Errorf("failed to get updated card: %w", err)
 	}
 
-	// Record transaction in transaction service (async, only for logging - does not modify balance)
-	go func() {
-		// Use a default user ID - in a real implementation, this should come from the request context
-		userID := "system"
-		err := s.transactionClient.CreateDebitCardTransaction(
-			userID,
-			card.Account.ID,
-			cardID,
-			amount,
-			description,
-			merchantName,
-			reference,
-		)
-		if err != nil {
-			// Log error but don't fail the main transaction
-			fmt.Printf("Warning: Failed to record transaction in transaction service: %v\n", err)
-		}
-	}()
-
 	return updatedCard, nil
 }
 
@@ -474,17 +611,37 @@ func (s *CardService) ChargeCardWithInstallments(req *dto.CreateInstallmentPlanR
 		return nil, fmt.Errorf("installment plans are only available for credit cards")
 	}
 
+	// Verificar que quien compra puede usar la tarjeta (titular o miembro de la cuenta compartida)
+	if err := s.authorizeCardOperation(card, req.InitiatedBy); err != nil {
+		return nil, err
+	}
+
+	// Evaluar los controles de gasto de la tarjeta
+	purchase := entities.PurchaseDetails{
+		MerchantName: req.MerchantName,
+		Category:     req.Category,
+		Currency:     entities.Currency(req.Currency),
+		IsOnline:     req.IsOnline,
+	}
+	if err := s.controlsService.EvaluateOperation(card, req.TotalAmount, purchase, req.InitiatedBy); err != nil {
+		return nil, err
+	}
+
+	// Verificar el crédito disponible antes de crear el plan (incluye la línea compartida y otras cuotas)
+	if !card.CanCreateInstallmentPlan(req.TotalAmount) {
+		return nil, fmt.Errorf("insufficient credit available for installment purchase")
+	}
+
 	// Crear el plan de cuotas usando InstallmentService
 	installmentPlan, err := s.installmentService.CreateInstallmentPlan(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create installment plan: %w", err)
 	}
 
-	// Cargar el monto total inmediatamente
+	// Cargar el monto total inmediatamente sobre la tarjeta ya validada
+	// (recargarla contaría el plan recién creado dos veces)
 	fmt.Printf("DEBUG - About to charge card %s with total amount %.2f\n", req.CardID, req.TotalAmount)
-	chargedCard, err := s.ChargeCard(req.CardID, req.TotalAmount,
-		fmt.Sprintf("Purchase with %d installments - %s", installmentPlan.InstallmentsCount, req.Description),
-		req.Reference)
+	chargedCard, err := s.applyCharge(card, req.TotalAmount)
 	if err != nil {
 		fmt.Printf("DEBUG - Card charge failed: %v\n", err)
 		// Tratar de cancelar el plan de cuotas si falla el cargo de tarjeta
@@ -505,3 +662,24 @@ func (s *CardService) ChargeCardWithInstallments(req *dto.CreateInstallmentPlanR
 		TransactionID:           installmentPlan.TransactionID,
 	}, nil
 }
+
+// authorizeCardOperation verifies that the user performing the operation can use the card.
+// Only operations requested by other services on their own skip the check; an operation
+// without a user is rejected.
+func (s *CardService) authorizeCardOperation(card *entities.Card, performedBy string) error {
+	if performedBy == entities.InternalServicePerformer {
+		return nil
+	}
+	if performedBy == "" {
+		return errors.ErrInsufficientRights
+	}
+
+	canUse, err := s.membershipService.CanUseCard(card, performedBy)
+	if err != nil {
+		return fmt.Errorf("failed to verify card access: %w", err)
+	}
+	if !canUse {
+		return errors.ErrInsufficientRights
+	}
+	return nil
+}
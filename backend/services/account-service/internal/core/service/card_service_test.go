@@ -0,0 +1,292 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/card/dto"
+	"github.com/google/uuid"
+)
+
+// Create stores a new card
+func (m *MockCardRepository) Create(card *entities.Card) (*entities.Card, error) {
+	m.cards[card.ID] = card
+	return card, nil
+}
+
+// Replace stores the renewed card and its replacement like the repository transaction
+func (m *MockCardRepository) Replace(oldCard, newCard *entities.Card) error {
+	m.cards[oldCard.ID] = oldCard
+	m.cards[newCard.ID] = newCard
+	return nil
+}
+
+func setupCardService(t *testing.T) (*CardService, *MockCardRepository, *entities.Card) {
+	accountRepo := NewMockAccountRepository()
+	account := &entities.Account{
+		UserID:      uuid.NewString(),
+		AccountType: entities.AccountTypeBankAccount,
+		Name:        "Banco Santander",
+		Currency:    entities.CurrencyARS,
+		IsActive:    true,
+	}
+	if err := accountRepo.Create(account); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	limit := 100000.0
+	card := newCreditCard(account.ID, 25000)
+	card.Account = *account
+	card.CreditLimit = &limit
+	card.IsDefault = true
+	card.ExpirationYear = time.Now().Year()
+	card.ExpirationMonth = int(time.Now().Month())
+
+	cardRepo := &MockCardRepository{cards: map[string]*entities.Card{card.ID: card}}
+	membershipService := NewMembershipService(NewMockAccountMemberRepository(), accountRepo, cardRepo)
+
+	return NewCardService(cardRepo, accountRepo, nil, nil, membershipService, nil, testKeyring(t, "master-1")), cardRepo, card
+}
+
+func renewRequest(number string, expirationYear int) *dto.RenewCardRequest {
+	return &dto.RenewCardRequest{
+		CardNumber:      entities.PAN(number),
+		ExpirationMonth: 12,
+		ExpirationYear:  expirationYear,
+	}
+}
+
+func createRequest(accountID, number, cardType, cardBrand string) *dto.CreateCardRequest {
+	limit := 50000.0
+	return &dto.CreateCardRequest{
+		AccountID:       accountID,
+		CardNumber:      entities.PAN(number),
+		CardType:        cardType,
+		CardBrand:       cardBrand,
+		HolderName:      "Juan Perez",
+		ExpirationMonth: 12,
+		ExpirationYear:  time.Now().Year() + 3,
+		CreditLimit:     &limit,
+	}
+}
+
+func TestCreateCardFromNumber(t *testing.T) {
+	tests := []struct {
+		name          string
+		number        string
+		cardType      string
+		cardBrand     string
+		expectedType  entities.CardType
+		expectedBrand entities.CardBrand
+		expectedField string
+	}{
+		{name: "visa credit", number: "4111 1111 1111 1111", cardType: "credit", expectedType: entities.CardTypeCredit, expectedBrand: entities.CardBrandVisa},
+		{name: "amex type detected", number: "378282246310005", expectedType: entities.CardTypeCredit, expectedBrand: entities.CardBrandAmex},
+		{name: "visa electron type detected", number: "4026000000000002", expectedType: entities.CardTypeDebit, expectedBrand: entities.CardBrandVisa},
+		{name: "mastercard 2-series", number: "2221-0000-0000-0009", cardType: "debit", cardBrand: "mastercard", expectedType: entities.CardTypeDebit, expectedBrand: entities.CardBrandMastercard},
+		{name: "unknown BIN", number: "9999999999999995", cardType: "debit", expectedType: entities.CardTypeDebit, expectedBrand: entities.CardBrandOther},
+		{name: "luhn check fails", number: "4111111111111112", cardType: "credit", expectedField: "card_number"},
+		{name: "not only digits", number: "4111-1111-1111-111a", cardType: "credit", expectedField: "card_number"},
+		{name: "invalid length for brand", number: "41111111111111111", cardType: "credit", expectedField: "card_number"},
+		{name: "brand mismatch", number: "4111111111111111", cardType: "credit", cardBrand: "amex", expectedField: "card_brand"},
+		{name: "type mismatch", number: "378282246310005", cardType: "debit", expectedField: "card_type"},
+		{name: "type required when BIN does not tell", number: "5555555555554444", expectedField: "card_type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, cardRepo, existing := setupCardService(t)
+
+			card, err := service.CreateCard(createRequest(existing.AccountID, tt.number, tt.cardType, tt.cardBrand))
+			if tt.expectedField != "" {
+				accountErr, ok := err.(*errors.AccountError)
+				if !ok || accountErr.Field != tt.expectedField {
+					t.Fatalf("CreateCard() error = %v, want validation error on %s", err, tt.expectedField)
+				}
+				if strings.Contains(err.Error(), strings.NewReplacer(" ", "", "-", "").Replace(tt.number)) {
+					t.Errorf("CreateCard() error leaks the card number: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateCard() unexpected error: %v", err)
+			}
+
+			digits := strings.NewReplacer(" ", "", "-", "").Replace(tt.number)
+			last4 := digits[len(digits)-4:]
+			if card.CardType != tt.expectedType || card.CardBrand != tt.expectedBrand {
+				t.Errorf("CreateCard() type = %v, brand = %v, want %v and %v", card.CardType, card.CardBrand, tt.expectedType, tt.expectedBrand)
+			}
+			if card.LastFourDigits != last4 || card.MaskedNumber != "**** **** **** "+last4 {
+				t.Errorf("CreateCard() last four = %v, masked = %v", card.LastFourDigits, card.MaskedNumber)
+			}
+			if strings.Contains(card.EncryptedNumber, digits) || cardRepo.cards[card.ID] == nil {
+				t.Errorf("CreateCard() stored number = %v, want it sealed", card.EncryptedNumber)
+			}
+			if number, err := testKeyring(t, "master-1").Open(card.EncryptedNumber, card.KeyFingerprint); err != nil || string(number) != digits {
+				t.Errorf("CreateCard() sealed number does not open: %v", err)
+			}
+		})
+	}
+}
+
+func TestCardNumberIsNeverPrinted(t *testing.T) {
+	req := createRequest("account", "4111 1111 1111 1111", "credit", "")
+
+	printed := fmt.Sprintf("%v %+v %s %q %#v", req.CardNumber, req, req.CardNumber, req.CardNumber, req.CardNumber)
+	if strings.Contains(printed, "1111 1111 1111 1111") || strings.Contains(printed, "411111") {
+		t.Errorf("formatted request leaks the card number: %s", printed)
+	}
+
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+	if strings.Contains(string(encoded), "411111") || !strings.Contains(string(encoded), "**** **** **** 1111") {
+		t.Errorf("marshalled request = %s, want masked card number", encoded)
+	}
+}
+
+func TestRenewCard(t *testing.T) {
+	service, cardRepo, card := setupCardService(t)
+	owner := card.Account.UserID
+	oldID := card.ID
+
+	replacement, err := service.RenewCard(oldID, owner, renewRequest("4012888888881881", time.Now().Year()+4))
+	if err != nil {
+		t.Fatalf("RenewCard() unexpected error: %v", err)
+	}
+
+	if replacement.ReplacesCardID == nil || *replacement.ReplacesCardID != oldID {
+		t.Errorf("RenewCard() replaces card = %v, want %v", replacement.ReplacesCardID, oldID)
+	}
+	if replacement.Balance != 25000 || replacement.CreditLimit == nil || *replacement.CreditLimit != 100000 {
+		t.Errorf("RenewCard() replacement balance = %v, limit = %v, want 25000 and 100000", replacement.Balance, replacement.CreditLimit)
+	}
+	if replacement.Status != entities.CardStatusActive || !replacement.IsDefault || replacement.HolderName != card.HolderName {
+		t.Errorf("RenewCard() replacement = %+v", replacement)
+	}
+	if replacement.LastFourDigits != "1881" || replacement.MaskedNumber != "**** **** **** 1881" || replacement.KeyFingerprint == "" {
+		t.Errorf("RenewCard() replacement last four = %v, masked = %v", replacement.LastFourDigits, replacement.MaskedNumber)
+	}
+
+	old := cardRepo.cards[oldID]
+	if old.Status != entities.CardStatusInactive || old.Balance != 0 || old.IsDefault {
+		t.Errorf("RenewCard() old card status = %v, balance = %v, default = %v", old.Status, old.Balance, old.IsDefault)
+	}
+
+	// Replaced cards cannot be renewed again
+	if _, err := service.RenewCard(oldID, owner, renewRequest("4012888888881881", time.Now().Year()+5)); !errors.IsValidationError(err) {
+		t.Errorf("RenewCard() of replaced card error = %v, want validation error", err)
+	}
+}
+
+func TestRenewCardValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         func(card *entities.Card) string
+		number         string
+		expirationYear int
+		expectedError  error
+	}{
+		{
+			name:           "stranger cannot renew",
+			userID:         func(card *entities.Card) string { return uuid.NewString() },
+			number:         "4012888888881881",
+			expirationYear: time.Now().Year() + 4,
+			expectedError:  errors.ErrInsufficientRights,
+		},
+		{
+			name:           "replacement must expire later",
+			userID:         func(card *entities.Card) string { return card.Account.UserID },
+			number:         "4012888888881881",
+			expirationYear: time.Now().Year() - 1,
+		},
+		{
+			name:           "replacement must keep the brand",
+			userID:         func(card *entities.Card) string { return card.Account.UserID },
+			number:         "5555555555554444",
+			expirationYear: time.Now().Year() + 4,
+		},
+		{
+			name:           "replacement number must pass the luhn check",
+			userID:         func(card *entities.Card) string { return card.Account.UserID },
+			number:         "4012888888881882",
+			expirationYear: time.Now().Year() + 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, cardRepo, card := setupCardService(t)
+
+			_, err := service.RenewCard(card.ID, tt.userID(card), renewRequest(tt.number, tt.expirationYear))
+			if err == nil {
+				t.Fatal("RenewCard() expected error but got none")
+			}
+			if tt.expectedError != nil && err != tt.expectedError {
+				t.Errorf("RenewCard() error = %v, want %v", err, tt.expectedError)
+			}
+			if len(cardRepo.cards) != 1 || card.Status != entities.CardStatusActive || card.Balance != 25000 {
+				t.Errorf("RenewCard() changed the card on error: %+v", card)
+			}
+		})
+	}
+}
+
+func TestCardIsExpired(t *testing.T) {
+	now := time.Now()
+	lastMonth := now.AddDate(0, -1, 0)
+
+	tests := []struct {
+		name     string
+		month    int
+		year     int
+		expected bool
+	}{
+		{name: "valid through the end of the expiration month", month: int(now.Month()), year: now.Year(), expected: false},
+		{name: "expired after the expiration month", month: int(lastMonth.Month()), year: lastMonth.Year(), expected: true},
+		{name: "future expiration", month: 1, year: now.Year() + 2, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := &entities.Card{ExpirationMonth: tt.month, ExpirationYear: tt.year}
+			if got := card.IsExpired(); got != tt.expected {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAuthorizeCardOperation(t *testing.T) {
+	svc, _, card := setupCardService(t)
+
+	tests := []struct {
+		name        string
+		performedBy string
+		allowed     bool
+	}{
+		{name: "account owner", performedBy: card.Account.UserID, allowed: true},
+		{name: "internal service", performedBy: entities.InternalServicePerformer, allowed: true},
+		{name: "user without access", performedBy: uuid.NewString(), allowed: false},
+		{name: "no user", performedBy: "", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.authorizeCardOperation(card, tt.performedBy)
+			if tt.allowed && err != nil {
+				t.Errorf("authorizeCardOperation() unexpected error: %v", err)
+			}
+			if !tt.allowed && !errors.IsPermissionError(err) {
+				t.Errorf("authorizeCardOperation() error = %v, want permission error", err)
+			}
+		})
+	}
+}
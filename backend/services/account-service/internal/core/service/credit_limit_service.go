@@ -0,0 +1,386 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+)
+
+// CreditLimitService handles credit limit change requests, their treasury approval and the history of limits
+type CreditLimitService struct {
+	limitRepo         ports.CreditLimitRepositoryInterface
+	accountRepo       ports.AccountRepositoryInterface
+	cardRepo          ports.CardRepositoryInterface
+	membershipService ports.MembershipServiceInterface
+	approvalThreshold float64 // Increases above this amount wait for a treasurer
+}
+
+// NewCreditLimitService creates a new credit limit service
+func NewCreditLimitService(limitRepo ports.CreditLimitRepositoryInterface, accountRepo ports.AccountRepositoryInterface, cardRepo ports.CardRepositoryInterface, membershipService ports.MembershipServiceInterface, approvalThreshold float64) *CreditLimitService {
+	return &CreditLimitService{
+		limitRepo:         limitRepo,
+		accountRepo:       accountRepo,
+		cardRepo:          cardRepo,
+		membershipService: membershipService,
+		approvalThreshold: approvalThreshold,
+	}
+}
+
+// limitTarget is the account or card whose limit changes, with the credit it holds
+type limitTarget struct {
+	account      *entities.Account
+	card         *entities.Card
+	currentLimit float64
+	floor        float64   // Debt plus installment commitments: the limit cannot go below it
+	since        time.Time // When the target was created, start of the limit set before the history
+}
+
+// RequestChange requests a new credit limit for a credit card account or, with cardID, for a credit card.
+// Decreases below the debt plus installment commitments are rejected, increases above the approval
+// threshold wait for a treasurer and the rest are applied right away. Every request is recorded.
+func (s *CreditLimitService) RequestChange(accountID, userID string, cardID *string, creditLimit float64, reason string) (*entities.CreditLimitChangeRequest, error) {
+	if creditLimit < 0 {
+		return nil, errors.NewValidationError("credit_limit", "credit limit cannot be negative")
+	}
+
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+	if err := s.authorize(account, userID, s.membershipService.CanOperateAccount); err != nil {
+		return nil, err
+	}
+	if account.IsClosed() {
+		return nil, errors.ErrAccountClosed
+	}
+
+	target, err := s.loadTarget(account, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if creditLimit == target.currentLimit {
+		return nil, errors.NewValidationError("credit_limit", fmt.Sprintf("credit limit is already %.2f", creditLimit))
+	}
+
+	pending, err := s.limitRepo.GetPendingByTarget(accountID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, errors.NewValidationError("credit_limit", "there is already a credit limit change waiting for approval")
+	}
+
+	request := &entities.CreditLimitChangeRequest{
+		AccountID:      accountID,
+		CardID:         cardID,
+		TargetType:     entities.CreditLimitTargetAccount,
+		PreviousLimit:  target.currentLimit,
+		RequestedLimit: creditLimit,
+		Reason:         strings.TrimSpace(reason),
+		RequestedBy:    userID,
+		Status:         entities.CreditLimitChangeStatusPending,
+	}
+	if cardID != nil {
+		request.TargetType = entities.CreditLimitTargetCard
+	}
+
+	// Decreases below what the target already holds are rejected, keeping the attempt on record
+	if creditLimit < target.floor {
+		message := fmt.Sprintf("credit limit (%.2f) cannot be lower than the current debt plus installment commitments (%.2f)", creditLimit, target.floor)
+		now := time.Now()
+		request.Status = entities.CreditLimitChangeStatusRejected
+		request.ReviewNote = message
+		request.ReviewedAt = &now
+		if err := s.limitRepo.CreateRequest(request); err != nil {
+			return nil, err
+		}
+		return nil, errors.NewValidationError("credit_limit", message)
+	}
+
+	if request.Increase() > s.approvalThreshold {
+		request.RequiresApproval = true
+		if err := s.limitRepo.CreateRequest(request); err != nil {
+			return nil, err
+		}
+		fmt.Printf("🏦 Credit limit increase %s waiting for treasury approval (%.2f -> %.2f)\n", request.ID, request.PreviousLimit, request.RequestedLimit)
+		return request, nil
+	}
+
+	if err := s.limitRepo.CreateRequest(request); err != nil {
+		return nil, err
+	}
+	if err := s.apply(request, target, userID); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// GetRequestsByAccount lists the change requests of an account and its cards
+func (s *CreditLimitService) GetRequestsByAccount(accountID, userID, status string, page, pageSize int) ([]*entities.CreditLimitChangeRequest, int64, error) {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("account not found: %w", err)
+	}
+	if err := s.authorize(account, userID, s.membershipService.CanViewAccount); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	return s.limitRepo.GetRequestsByAccount(accountID, status, pageSize, offset)
+}
+
+// GetPendingRequests lists the increases waiting for a treasurer, oldest first
+func (s *CreditLimitService) GetPendingRequests(page, pageSize int) ([]*entities.CreditLimitChangeRequest, int64, error) {
+	offset := (page - 1) * pageSize
+	return s.limitRepo.GetRequestsByStatus(string(entities.CreditLimitChangeStatusPending), pageSize, offset)
+}
+
+// ApproveRequest applies a pending increase. The treasurer cannot approve their own request,
+// and the limit is checked again against the credit held at approval time.
+func (s *CreditLimitService) ApproveRequest(requestID, treasurerID, note string) (*entities.CreditLimitChangeRequest, error) {
+	request, err := s.getReviewableRequest(requestID, treasurerID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.GetByID(request.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+	if account.IsClosed() {
+		return nil, errors.ErrAccountClosed
+	}
+
+	target, err := s.loadTarget(account, request.CardID)
+	if err != nil {
+		return nil, err
+	}
+	if request.RequestedLimit < target.floor {
+		return nil, errors.NewValidationError("credit_limit",
+			fmt.Sprintf("credit limit (%.2f) is now lower than the current debt plus installment commitments (%.2f)", request.RequestedLimit, target.floor))
+	}
+
+	now := time.Now()
+	request.ReviewedBy = treasurerID
+	request.ReviewNote = strings.TrimSpace(note)
+	request.ReviewedAt = &now
+	request.PreviousLimit = target.currentLimit // The limit may have changed while waiting
+
+	if err := s.apply(request, target, treasurerID); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("✅ Credit limit change %s approved by treasurer %s\n", request.ID, treasurerID)
+
+	return request, nil
+}
+
+// RejectRequest rejects a pending increase with the reason given by the treasurer
+func (s *CreditLimitService) RejectRequest(requestID, treasurerID, note string) (*entities.CreditLimitChangeRequest, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, errors.NewValidationError("note", "a note is required to reject a credit limit change")
+	}
+
+	request, err := s.getReviewableRequest(requestID, treasurerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	request.Status = entities.CreditLimitChangeStatusRejected
+	request.ReviewedBy = treasurerID
+	request.ReviewNote = note
+	request.ReviewedAt = &now
+
+	if err := s.limitRepo.UpdateRequest(request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// GetHistory lists the limits of a credit card account or, with cardID, of a card, newest first
+func (s *CreditLimitService) GetHistory(accountID, userID string, cardID *string) ([]*entities.CreditLimitHistory, error) {
+	target, err := s.getViewableTarget(accountID, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.limitRepo.GetHistory(accountID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		// Limits set before the history started apply since the target was created
+		return []*entities.CreditLimitHistory{s.initialEntry(target, nil)}, nil
+	}
+	return history, nil
+}
+
+// GetLimitAt gets the limit that applied at the given time. It returns nil when there was none.
+func (s *CreditLimitService) GetLimitAt(accountID, userID string, cardID *string, at time.Time) (*entities.CreditLimitHistory, error) {
+	history, err := s.GetHistory(accountID, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range history {
+		if entry.AppliesAt(at) {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// apply puts the requested limit in effect, recording the limit set before the history started
+// the first time a target changes
+func (s *CreditLimitService) apply(request *entities.CreditLimitChangeRequest, target *limitTarget, changedBy string) error {
+	history, err := s.limitRepo.GetHistory(request.AccountID, request.CardID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	request.Status = entities.CreditLimitChangeStatusApplied
+	request.AppliedAt = &now
+
+	entry := &entities.CreditLimitHistory{
+		AccountID:       request.AccountID,
+		CardID:          request.CardID,
+		TargetType:      request.TargetType,
+		CreditLimit:     request.RequestedLimit,
+		ChangeRequestID: &request.ID,
+		ChangedBy:       changedBy,
+		EffectiveFrom:   now,
+	}
+
+	entries := []*entities.CreditLimitHistory{entry}
+	if len(history) == 0 {
+		entries = append([]*entities.CreditLimitHistory{s.initialEntry(target, &now)}, entries...)
+	}
+
+	if err := s.limitRepo.ApplyChange(request, entries...); err != nil {
+		return err
+	}
+
+	if target.card != nil {
+		target.card.CreditLimit = &request.RequestedLimit
+	} else {
+		target.account.CreditLimit = &request.RequestedLimit
+	}
+	return nil
+}
+
+// initialEntry builds the history entry of the limit set before the history started
+func (s *CreditLimitService) initialEntry(target *limitTarget, until *time.Time) *entities.CreditLimitHistory {
+	entry := &entities.CreditLimitHistory{
+		AccountID:     target.account.ID,
+		TargetType:    entities.CreditLimitTargetAccount,
+		CreditLimit:   target.currentLimit,
+		EffectiveFrom: target.since,
+		EffectiveTo:   until,
+	}
+	if target.card != nil {
+		entry.CardID = &target.card.ID
+		entry.TargetType = entities.CreditLimitTargetCard
+	}
+	return entry
+}
+
+// loadTarget loads the account or card whose limit changes with its current limit and the credit it holds
+func (s *CreditLimitService) loadTarget(account *entities.Account, cardID *string) (*limitTarget, error) {
+	if cardID == nil {
+		if account.AccountType != entities.AccountTypeCredit {
+			return nil, errors.NewValidationError("account_id", "credit limit can only be set for credit card accounts")
+		}
+		floor, err := s.accountCommittedCredit(account)
+		if err != nil {
+			return nil, err
+		}
+		target := &limitTarget{account: account, floor: floor, since: account.CreatedAt}
+		if account.CreditLimit != nil {
+			target.currentLimit = *account.CreditLimit
+		}
+		return target, nil
+	}
+
+	// Installment plans are preloaded so the floor includes their commitments
+	card, err := s.cardRepo.GetWithInstallmentPlans(*cardID)
+	if err != nil || card.AccountID != account.ID {
+		return nil, fmt.Errorf("card not found in account")
+	}
+	if card.CardType != entities.CardTypeCredit {
+		return nil, errors.NewValidationError("card_id", "credit limit can only be set for credit cards")
+	}
+	if card.CreditLineID != nil {
+		return nil, errors.NewValidationError("card_id", "card belongs to a credit line: update the credit line limit or the card sub-limit instead")
+	}
+
+	target := &limitTarget{account: account, card: card, floor: card.GetCommittedCredit(), since: card.CreatedAt}
+	if card.CreditLimit != nil {
+		target.currentLimit = *card.CreditLimit
+	}
+	return target, nil
+}
+
+// accountCommittedCredit returns the credit held by an account: its debt or, when higher, what is still owed
+// on the installment plans of its credit cards, as GetCommittedCredit does for a card
+func (s *CreditLimitService) accountCommittedCredit(account *entities.Account) (float64, error) {
+	cards, _, err := s.cardRepo.GetByAccountWithInstallmentPlans(account.ID, 100, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cards: %w", err)
+	}
+
+	var commitments float64
+	for _, card := range cards {
+		if card.CardType == entities.CardTypeCredit {
+			commitments += card.GetTotalInstallmentCommitments()
+		}
+	}
+	if used := account.GetUsedCredit(); used > commitments {
+		return used, nil
+	}
+	return commitments, nil
+}
+
+// getViewableTarget loads the target of a history query verifying the user can view its account
+func (s *CreditLimitService) getViewableTarget(accountID, userID string, cardID *string) (*limitTarget, error) {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+	if err := s.authorize(account, userID, s.membershipService.CanViewAccount); err != nil {
+		return nil, err
+	}
+	return s.loadTarget(account, cardID)
+}
+
+// getReviewableRequest loads a pending request that the treasurer did not make
+func (s *CreditLimitService) getReviewableRequest(requestID, treasurerID string) (*entities.CreditLimitChangeRequest, error) {
+	request, err := s.limitRepo.GetRequestByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if !request.IsPending() {
+		return nil, errors.NewValidationError("status", fmt.Sprintf("credit limit change is already %s", request.Status))
+	}
+	if request.RequestedBy == treasurerID {
+		return nil, errors.ErrInsufficientRights
+	}
+	return request, nil
+}
+
+// authorize runs an access check of the membership service for the account
+func (s *CreditLimitService) authorize(account *entities.Account, userID string, check func(*entities.Account, string) (bool, error)) error {
+	allowed, err := check(account, userID)
+	if err != nil {
+		return fmt.Errorf("failed to verify account access: %w", err)
+	}
+	if !allowed {
+		return errors.ErrInsufficientRights
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// MockCreditLimitRepository implements a mock credit limit repository for testing
+type MockCreditLimitRepository struct {
+	requests map[string]*entities.CreditLimitChangeRequest
+	history  []*entities.CreditLimitHistory
+}
+
+func NewMockCreditLimitRepository() *MockCreditLimitRepository {
+	return &MockCreditLimitRepository{requests: make(map[string]*entities.CreditLimitChangeRequest)}
+}
+
+func (m *MockCreditLimitRepository) CreateRequest(request *entities.CreditLimitChangeRequest) error {
+	if request.ID == "" {
+		request.ID = uuid.NewString()
+	}
+	request.CreatedAt = time.Now()
+	m.requests[request.ID] = request
+	return nil
+}
+
+func (m *MockCreditLimitRepository) GetRequestByID(requestID string) (*entities.CreditLimitChangeRequest, error) {
+	request, exists := m.requests[requestID]
+	if !exists {
+		return nil, fmt.Errorf("credit limit change request not found")
+	}
+	return request, nil
+}
+
+func (m *MockCreditLimitRepository) UpdateRequest(request *entities.CreditLimitChangeRequest) error {
+	m.requests[request.ID] = request
+	return nil
+}
+
+func (m *MockCreditLimitRepository) GetPendingByTarget(accountID string, cardID *string) (*entities.CreditLimitChangeRequest, error) {
+	for _, request := range m.requests {
+		if request.IsPending() && request.AccountID == accountID && sameCard(request.CardID, cardID) {
+			return request, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockCreditLimitRepository) GetRequestsByAccount(accountID, status string, limit, offset int) ([]*entities.CreditLimitChangeRequest, int64, error) {
+	var requests []*entities.CreditLimitChangeRequest
+	for _, request := range m.requests {
+		if request.AccountID == accountID && (status == "" || string(request.Status) == status) {
+			requests = append(requests, request)
+		}
+	}
+	return requests, int64(len(requests)), nil
+}
+
+func (m *MockCreditLimitRepository) GetRequestsByStatus(status string, limit, offset int) ([]*entities.CreditLimitChangeRequest, int64, error) {
+	var requests []*entities.CreditLimitChangeRequest
+	for _, request := range m.requests {
+		if string(request.Status) == status {
+			requests = append(requests, request)
+		}
+	}
+	return requests, int64(len(requests)), nil
+}
+
+func (m *MockCreditLimitRepository) GetHistory(accountID string, cardID *string) ([]*entities.CreditLimitHistory, error) {
+	var history []*entities.CreditLimitHistory
+	for _, entry := range m.history {
+		if entry.AccountID == accountID && sameCard(entry.CardID, cardID) {
+			history = append(history, entry)
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].EffectiveFrom.After(history[j].EffectiveFrom)
+	})
+	return history, nil
+}
+
+func (m *MockCreditLimitRepository) ApplyChange(request *entities.CreditLimitChangeRequest, entries ...*entities.CreditLimitHistory) error {
+	from := entries[len(entries)-1].EffectiveFrom
+	for _, entry := range m.history {
+		if entry.EffectiveTo == nil && entry.AccountID == request.AccountID && sameCard(entry.CardID, request.CardID) {
+			entry.EffectiveTo = &from
+		}
+	}
+	for _, entry := range entries {
+		if entry.ID == "" {
+			entry.ID = uuid.NewString()
+		}
+		m.history = append(m.history, entry)
+	}
+	m.requests[request.ID] = request
+	return nil
+}
+
+func sameCard(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// Verify interface compliance
+var _ ports.CreditLimitRepositoryInterface = (*MockCreditLimitRepository)(nil)
+
+func (m *MockCardRepository) GetWithInstallmentPlans(cardID string) (*entities.Card, error) {
+	return m.GetByID(cardID)
+}
+
+type creditLimitFixture struct {
+	service  *CreditLimitService
+	repo     *MockCreditLimitRepository
+	account  *entities.Account
+	card     *entities.Card
+	cardRepo *MockCardRepository
+}
+
+func setupCreditLimitService(t *testing.T) *creditLimitFixture {
+	accountRepo := NewMockAccountRepository()
+	limit := 10000.0
+	account := &entities.Account{
+		UserID:      uuid.NewString(),
+		AccountType: entities.AccountTypeCredit,
+		Name:        "Visa Galicia",
+		Currency:    entities.CurrencyARS,
+		Balance:     -2000, // Used 2000 of credit
+		CreditLimit: &limit,
+		IsActive:    true,
+	}
+	if err := accountRepo.Create(account); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	account.CreatedAt = time.Now().AddDate(-1, 0, 0)
+
+	cardRepo := &MockCardRepository{cards: make(map[string]*entities.Card)}
+	card := newCreditCard(account.ID, 1500)
+	cardLimit := 5000.0
+	card.CreditLimit = &cardLimit
+	card.CreatedAt = account.CreatedAt
+	cardRepo.cards[card.ID] = card
+
+	membershipService := NewMembershipService(NewMockAccountMemberRepository(), accountRepo, cardRepo)
+	repo := NewMockCreditLimitRepository()
+
+	return &creditLimitFixture{
+		service:  NewCreditLimitService(repo, accountRepo, cardRepo, membershipService, 50000),
+		repo:     repo,
+		account:  account,
+		card:     card,
+		cardRepo: cardRepo,
+	}
+}
+
+func TestRequestCreditLimitChange(t *testing.T) {
+	t.Run("increase under the threshold applies right away", func(t *testing.T) {
+		f := setupCreditLimitService(t)
+
+		request, err := f.service.RequestChange(f.account.ID, f.account.UserID, nil, 20000, "more spending")
+		if err != nil {
+			t.Fatalf("RequestChange() unexpected error: %v", err)
+		}
+		if request.Status != entities.CreditLimitChangeStatusApplied || request.RequiresApproval {
+			t.Errorf("RequestChange() status = %v (requires approval %v), want applied", request.Status, request.RequiresApproval)
+		}
+		if request.PreviousLimit != 10000 {
+			t.Errorf("RequestChange() previous limit = %v, want 10000", request.PreviousLimit)
+		}
+		if *f.account.CreditLimit != 20000 {
+			t.Errorf("account credit limit = %v, want 20000", *f.account.CreditLimit)
+		}
+	})
+
+	t.Run("increase above the threshold waits for approval", func(t *testing.T) {
+		f := setupCreditLimitService(t)
+
+		request, err := f.service.RequestChange(f.account.ID, f.account.UserID, nil, 80000, "")
+		if err != nil {
+			t.Fatalf("RequestChange() unexpected error: %v", err)
+		}
+		if !request.IsPending() || !request.RequiresApproval {
+			t.Errorf("RequestChange() status = %v, want pending with approval", request.Status)
+		}
+		if *f.account.CreditLimit != 10000 {
+			t.Errorf("account credit limit = %v, want 10000 until approval", *f.account.CreditLimit)
+		}
+
+		if _, err := f.service.RequestChange(f.account.ID, f.account.UserID, nil, 90000, ""); !errors.IsValidationError(err) {
+			t.Errorf("RequestChange() with a pending request error = %v, want validation error", err)
+		}
+	})
+
+	t.Run("decrease below the debt is rejected and recorded", func(t *testing.T) {
+		f := setupCreditLimitService(t)
+
+		_, err := f.service.RequestChange(f.account.ID, f.account.UserID, nil, 1000, "")
+		if !errors.IsValidationError(err) {
+			t.Fatalf("RequestChange() error = %v, want validation error", err)
+		}
+		requests, total, _ := f.repo.GetRequestsByAccount(f.account.ID, string(entities.CreditLimitChangeStatusRejected), 10, 0)
+		if total != 1 || requests[0].RequestedLimit != 1000 {
+			t.Errorf("rejected requests = %d, want the 1000 request recorded", total)
+		}
+		if *f.account.CreditLimit != 10000 {
+			t.Errorf("account credit limit = %v, want 10000", *f.account.CreditLimit)
+		}
+	})
+
+	t.Run("card decrease counts installment commitments", func(t *testing.T) {
+		f := setupCreditLimitService(t)
+		f.card.InstallmentPlans = []entities.InstallmentPlan{{
+			ID:                uuid.NewString(),
+			CardID:            f.card.ID,
+			Status:            entities.InstallmentPlanStatusActive,
+			RemainingAmount:   3000,
+			InstallmentsCount: 6,
+			InstallmentAmount: 500,
+		}}
+
+		if _, err := f.service.RequestChange(f.account.ID, f.account.UserID, &f.card.ID, 2500, ""); !errors.IsValidationError(err) {
+			t.Errorf("RequestChange() below the commitments error = %v, want validation error", err)
+		}
+		request, err := f.service.RequestChange(f.account.ID, f.account.UserID, &f.card.ID, 3500, "")
+		if err != nil {
+			t.Fatalf("RequestChange() unexpected error: %v", err)
+		}
+		if request.TargetType != entities.CreditLimitTargetCard || *f.card.CreditLimit != 3500 {
+			t.Errorf("card credit limit = %v, want 3500", *f.card.CreditLimit)
+		}
+	})
+
+	t.Run("account decrease counts the installment commitments of its cards", func(t *testing.T) {
+		f := setupCreditLimitService(t)
+		// The debt is 2000 but 4500 is still owed in cuotas of the card
+		f.card.InstallmentPlans = []entities.InstallmentPlan{{
+			ID:                uuid.NewString(),
+			CardID:            f.card.ID,
+			Status:            entities.InstallmentPlanStatusActive,
+			RemainingAmount:   4500,
+			InstallmentsCount: 9,
+			InstallmentAmount: 500,
+		}}
+
+		if _, err := f.service.RequestChange(f.account.ID, f.account.UserID, nil, 3000, ""); !errors.IsValidationError(err) {
+			t.Errorf("RequestChange() below the installment commitments error = %v, want validation error", err)
+		}
+		if *f.account.CreditLimit != 10000 {
+			t.Errorf("account credit limit = %v, want 10000", *f.account.CreditLimit)
+		}
+		if _, err := f.service.RequestChange(f.account.ID, f.account.UserID, nil, 4500, ""); err != nil {
+			t.Fatalf("RequestChange() unexpected error: %v", err)
+		}
+		if *f.account.CreditLimit != 4500 {
+			t.Errorf("account credit limit = %v, want 4500", *f.account.CreditLimit)
+		}
+	})
+
+	t.Run("other users cannot request changes", func(t *testing.T) {
+		f := setupCreditLimitService(t)
+
+		if _, err := f.service.RequestChange(f.account.ID, uuid.NewString(), nil, 20000, ""); err != errors.ErrInsufficientRights {
+			t.Errorf("RequestChange() error = %v, want %v", err, errors.ErrInsufficientRights)
+		}
+	})
+}
+
+func TestReviewCreditLimitChange(t *testing.T) {
+	f := setupCreditLimitService(t)
+	treasurerID := uuid.NewString()
+
+	request, err := f.service.RequestChange(f.account.ID, f.account.UserID, nil, 80000, "")
+	if err != nil {
+		t.Fatalf("RequestChange() unexpected error: %v", err)
+	}
+
+	if _, err := f.service.ApproveRequest(request.ID, f.account.UserID, ""); err != errors.ErrInsufficientRights {
+		t.Errorf("ApproveRequest() by the requester error = %v, want %v", err, errors.ErrInsufficientRights)
+	}
+	if _, err := f.service.RejectRequest(request.ID, treasurerID, " "); !errors.IsValidationError(err) {
+		t.Errorf("RejectRequest() without note error = %v, want validation error", err)
+	}
+
+	approved, err := f.service.ApproveRequest(request.ID, treasurerID, "income verified")
+	if err != nil {
+		t.Fatalf("ApproveRequest() unexpected error: %v", err)
+	}
+	if approved.Status != entities.CreditLimitChangeStatusApplied || approved.ReviewedBy != treasurerID {
+		t.Errorf("ApproveRequest() status = %v reviewed by %q, want applied by the treasurer", approved.Status, approved.ReviewedBy)
+	}
+	if *f.account.CreditLimit != 80000 {
+		t.Errorf("account credit limit = %v, want 80000", *f.account.CreditLimit)
+	}
+
+	if _, err := f.service.RejectRequest(request.ID, treasurerID, "too late"); !errors.IsValidationError(err) {
+		t.Errorf("RejectRequest() on an applied request error = %v, want validation error", err)
+	}
+}
+
+func TestCreditLimitHistory(t *testing.T) {
+	f := setupCreditLimitService(t)
+
+	history, err := f.service.GetHistory(f.account.ID, f.account.UserID, nil)
+	if err != nil {
+		t.Fatalf("GetHistory() unexpected error: %v", err)
+	}
+	if len(history) != 1 || history[0].CreditLimit != 10000 || history[0].EffectiveTo != nil {
+		t.Fatalf("GetHistory() before any change = %+v, want the current limit", history)
+	}
+
+	if _, err := f.service.RequestChange(f.account.ID, f.account.UserID, nil, 15000, ""); err != nil {
+		t.Fatalf("RequestChange() unexpected error: %v", err)
+	}
+
+	history, err = f.service.GetHistory(f.account.ID, f.account.UserID, nil)
+	if err != nil {
+		t.Fatalf("GetHistory() unexpected error: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("GetHistory() entries = %d, want 2", len(history))
+	}
+	if history[0].CreditLimit != 15000 || history[0].EffectiveTo != nil {
+		t.Errorf("GetHistory() newest = %v, want the open 15000 entry", history[0].CreditLimit)
+	}
+	if history[1].CreditLimit != 10000 || history[1].EffectiveTo == nil {
+		t.Errorf("GetHistory() oldest = %v, want the closed 10000 entry", history[1].CreditLimit)
+	}
+
+	lastMonth, err := f.service.GetLimitAt(f.account.ID, f.account.UserID, nil, time.Now().AddDate(0, -1, 0))
+	if err != nil {
+		t.Fatalf("GetLimitAt() unexpected error: %v", err)
+	}
+	if lastMonth == nil || lastMonth.CreditLimit != 10000 {
+		t.Errorf("GetLimitAt() last month = %+v, want 10000", lastMonth)
+	}
+
+	now, err := f.service.GetLimitAt(f.account.ID, f.account.UserID, nil, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("GetLimitAt() unexpected error: %v", err)
+	}
+	if now == nil || now.CreditLimit != 15000 {
+		t.Errorf("GetLimitAt() now = %+v, want 15000", now)
+	}
+
+	beforeAccount, _ := f.service.GetLimitAt(f.account.ID, f.account.UserID, nil, time.Now().AddDate(-2, 0, 0))
+	if beforeAccount != nil {
+		t.Errorf("GetLimitAt() before the account existed = %+v, want nil", beforeAccount)
+	}
+}
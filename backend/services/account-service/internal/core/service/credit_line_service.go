@@ -0,0 +1,207 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+)
+
+// CreditLineService provides business logic for credit lines shared by several cards
+type CreditLineService struct {
+	creditLineRepo    ports.CreditLineRepositoryInterface
+	accountRepo       ports.AccountRepositoryInterface
+	cardRepo          ports.CardRepositoryInterface
+	membershipService ports.MembershipServiceInterface
+}
+
+// NewCreditLineService creates a new credit line service instance
+func NewCreditLineService(creditLineRepo ports.CreditLineRepositoryInterface, accountRepo ports.AccountRepositoryInterface, cardRepo ports.CardRepositoryInterface, membershipService ports.MembershipServiceInterface) *CreditLineService {
+	return &CreditLineService{
+		creditLineRepo:    creditLineRepo,
+		accountRepo:       accountRepo,
+		cardRepo:          cardRepo,
+		membershipService: membershipService,
+	}
+}
+
+// CreateCreditLine creates a credit line for an account
+func (s *CreditLineService) CreateCreditLine(accountID, userID, name string, totalLimit float64) (*entities.CreditLine, error) {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+
+	if err := s.authorize(account, userID, s.membershipService.CanOperateAccount); err != nil {
+		return nil, err
+	}
+
+	if !account.CanHaveCards() {
+		return nil, errors.NewValidationError("account_id", fmt.Sprintf("account type %s cannot have cards", account.AccountType))
+	}
+
+	creditLine := &entities.CreditLine{
+		AccountID:  accountID,
+		Name:       name,
+		TotalLimit: totalLimit,
+	}
+	if err := validateEntity(creditLine.Validate()); err != nil {
+		return nil, err
+	}
+
+	return s.creditLineRepo.Create(creditLine)
+}
+
+// GetCreditLine retrieves a credit line with its cards
+func (s *CreditLineService) GetCreditLine(creditLineID, userID string) (*entities.CreditLine, error) {
+	creditLine, err := s.creditLineRepo.GetByID(creditLineID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(&creditLine.Account, userID, s.membershipService.CanViewAccount); err != nil {
+		return nil, err
+	}
+
+	return creditLine, nil
+}
+
+// GetCreditLinesByAccount retrieves the credit lines of an account
+func (s *CreditLineService) GetCreditLinesByAccount(accountID, userID string) ([]*entities.CreditLine, error) {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+
+	if err := s.authorize(account, userID, s.membershipService.CanViewAccount); err != nil {
+		return nil, err
+	}
+
+	return s.creditLineRepo.GetByAccount(accountID)
+}
+
+// UpdateCreditLine changes the name or total limit of a credit line
+func (s *CreditLineService) UpdateCreditLine(creditLineID, userID string, name *string, totalLimit *float64) (*entities.CreditLine, error) {
+	creditLine, err := s.getOperableCreditLine(creditLineID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != nil {
+		creditLine.Name = *name
+	}
+	if totalLimit != nil {
+		if committed := creditLine.GetCommittedCredit(nil); *totalLimit < committed {
+			return nil, errors.NewValidationError("total_limit",
+				fmt.Sprintf("total limit (%.2f) cannot be lower than the debt and installment commitments of its cards (%.2f)", *totalLimit, committed))
+		}
+		creditLine.TotalLimit = *totalLimit
+	}
+
+	if err := validateEntity(creditLine.Validate()); err != nil {
+		return nil, err
+	}
+
+	return s.creditLineRepo.Update(creditLine)
+}
+
+// AssignCard adds a credit card of the account to the line, or updates its settings when it already belongs to it
+func (s *CreditLineService) AssignCard(creditLineID, cardID, userID string, isAdditional bool, subLimit *float64) (*entities.Card, error) {
+	creditLine, err := s.getOperableCreditLine(creditLineID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := s.cardRepo.GetByIDWithAccount(cardID)
+	if err != nil {
+		return nil, fmt.Errorf("card not found: %w", err)
+	}
+	if card.AccountID != creditLine.AccountID {
+		return nil, errors.NewValidationError("card_id", "card does not belong to the credit line account")
+	}
+	if card.CreditLineID != nil && *card.CreditLineID != creditLineID {
+		return nil, errors.NewValidationError("card_id", "card already belongs to another credit line")
+	}
+
+	card.CreditLineID = &creditLine.ID
+	card.CreditLine = creditLine
+	card.IsAdditional = isAdditional
+	card.SubLimit = subLimit
+
+	if err := validateEntity(card.ValidateForUpdate()); err != nil {
+		return nil, err
+	}
+	if creditLine.GetAvailableCreditWithInstallments(card) < 0 {
+		return nil, errors.NewValidationError("card_id", "card debt exceeds the credit available on the line")
+	}
+
+	return s.cardRepo.Update(card)
+}
+
+// RemoveCard takes a card out of the line. creditLimit sets the card's own limit and is
+// required when the card did not have one before joining the line.
+func (s *CreditLineService) RemoveCard(creditLineID, cardID, userID string, creditLimit *float64) (*entities.Card, error) {
+	if _, err := s.getOperableCreditLine(creditLineID, userID); err != nil {
+		return nil, err
+	}
+
+	card, err := s.cardRepo.GetByID(cardID)
+	if err != nil {
+		return nil, fmt.Errorf("card not found: %w", err)
+	}
+	if card.CreditLineID == nil || *card.CreditLineID != creditLineID {
+		return nil, fmt.Errorf("card not found in credit line")
+	}
+
+	if creditLimit != nil {
+		card.CreditLimit = creditLimit
+	}
+	if card.CreditLimit == nil {
+		return nil, errors.NewValidationError("credit_limit", "credit limit is required for cards leaving a credit line")
+	}
+
+	card.CreditLineID = nil
+	card.IsAdditional = false
+	card.SubLimit = nil
+
+	if err := validateEntity(card.ValidateForUpdate()); err != nil {
+		return nil, err
+	}
+
+	return s.cardRepo.Update(card)
+}
+
+// getOperableCreditLine loads a credit line verifying the user can operate its account
+func (s *CreditLineService) getOperableCreditLine(creditLineID, userID string) (*entities.CreditLine, error) {
+	creditLine, err := s.creditLineRepo.GetByID(creditLineID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(&creditLine.Account, userID, s.membershipService.CanOperateAccount); err != nil {
+		return nil, err
+	}
+
+	return creditLine, nil
+}
+
+// authorize runs an access check of the membership service for the account
+func (s *CreditLineService) authorize(account *entities.Account, userID string, check func(*entities.Account, string) (bool, error)) error {
+	allowed, err := check(account, userID)
+	if err != nil {
+		return fmt.Errorf("failed to verify account access: %w", err)
+	}
+	if !allowed {
+		return errors.ErrInsufficientRights
+	}
+	return nil
+}
+
+// validateEntity converts entity validation errors into service validation errors
+func validateEntity(err error) error {
+	if validationErr, ok := err.(*entities.ValidationError); ok {
+		return errors.NewValidationError(validationErr.Field, validationErr.Message)
+	}
+	return err
+}
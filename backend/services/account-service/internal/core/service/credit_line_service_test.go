@@ -0,0 +1,388 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// MockCreditLineRepository implements a mock credit line repository for testing.
+// Cards and accounts are resolved from the card and account mocks like the repository preloads them.
+type MockCreditLineRepository struct {
+	creditLines map[string]*entities.CreditLine
+	accountRepo *MockAccountRepository
+	cardRepo    *MockCardRepository
+}
+
+func NewMockCreditLineRepository(accountRepo *MockAccountRepository, cardRepo *MockCardRepository) *MockCreditLineRepository {
+	return &MockCreditLineRepository{
+		creditLines: make(map[string]*entities.CreditLine),
+		accountRepo: accountRepo,
+		cardRepo:    cardRepo,
+	}
+}
+
+func (m *MockCreditLineRepository) Create(creditLine *entities.CreditLine) (*entities.CreditLine, error) {
+	if creditLine.ID == "" {
+		creditLine.ID = uuid.NewString()
+	}
+	m.creditLines[creditLine.ID] = creditLine
+	return creditLine, nil
+}
+
+func (m *MockCreditLineRepository) GetByID(creditLineID string) (*entities.CreditLine, error) {
+	creditLine, exists := m.creditLines[creditLineID]
+	if !exists {
+		return nil, fmt.Errorf("credit line not found")
+	}
+	m.load(creditLine)
+	return creditLine, nil
+}
+
+func (m *MockCreditLineRepository) GetByAccount(accountID string) ([]*entities.CreditLine, error) {
+	var creditLines []*entities.CreditLine
+	for _, creditLine := range m.creditLines {
+		if creditLine.AccountID == accountID {
+			m.load(creditLine)
+			creditLines = append(creditLines, creditLine)
+		}
+	}
+	return creditLines, nil
+}
+
+func (m *MockCreditLineRepository) Update(creditLine *entities.CreditLine) (*entities.CreditLine, error) {
+	m.creditLines[creditLine.ID] = creditLine
+	return creditLine, nil
+}
+
+func (m *MockCreditLineRepository) load(creditLine *entities.CreditLine) {
+	if account, err := m.accountRepo.GetByID(creditLine.AccountID); err == nil {
+		creditLine.Account = *account
+	}
+	creditLine.Cards = nil
+	for _, card := range m.cardRepo.cards {
+		if card.CreditLineID != nil && *card.CreditLineID == creditLine.ID {
+			creditLine.Cards = append(creditLine.Cards, *card)
+		}
+	}
+}
+
+// Verify interface compliance
+var _ ports.CreditLineRepositoryInterface = (*MockCreditLineRepository)(nil)
+
+func newCreditCard(accountID string, balance float64) *entities.Card {
+	return &entities.Card{
+		ID:              uuid.NewString(),
+		AccountID:       accountID,
+		CardType:        entities.CardTypeCredit,
+		CardBrand:       entities.CardBrandVisa,
+		LastFourDigits:  "1234",
+		HolderName:      "Juan Perez",
+		ExpirationMonth: 12,
+		ExpirationYear:  time.Now().Year() + 3,
+		Status:          entities.CardStatusActive,
+		Balance:         balance,
+	}
+}
+
+func setupCreditLineService(t *testing.T) (*CreditLineService, *entities.Account, *MockCardRepository) {
+	accountRepo := NewMockAccountRepository()
+	account := &entities.Account{
+		UserID:      uuid.NewString(),
+		AccountType: entities.AccountTypeBankAccount,
+		Name:        "Banco Nación",
+		Currency:    entities.CurrencyARS,
+		IsActive:    true,
+	}
+	if err := accountRepo.Create(account); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	cardRepo := &MockCardRepository{cards: make(map[string]*entities.Card)}
+	membershipService := NewMembershipService(NewMockAccountMemberRepository(), accountRepo, cardRepo)
+	creditLineRepo := NewMockCreditLineRepository(accountRepo, cardRepo)
+
+	return NewCreditLineService(creditLineRepo, accountRepo, cardRepo, membershipService), account, cardRepo
+}
+
+func TestCreateCreditLine(t *testing.T) {
+	service, account, _ := setupCreditLineService(t)
+
+	tests := []struct {
+		name          string
+		userID        string
+		lineName      string
+		totalLimit    float64
+		expectedError error
+		expectError   bool
+	}{
+		{name: "owner creates line", userID: account.UserID, lineName: "Visa Banco Nación", totalLimit: 500000},
+		{name: "stranger cannot create line", userID: uuid.NewString(), lineName: "Visa", totalLimit: 500000, expectedError: errors.ErrInsufficientRights, expectError: true},
+		{name: "zero limit", userID: account.UserID, lineName: "Visa", totalLimit: 0, expectError: true},
+		{name: "missing name", userID: account.UserID, lineName: "", totalLimit: 1000, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.CreateCreditLine(account.ID, tt.userID, tt.lineName, tt.totalLimit)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("CreateCreditLine() expected error but got none")
+				}
+				if tt.expectedError != nil && err != tt.expectedError {
+					t.Errorf("CreateCreditLine() error = %v, want %v", err, tt.expectedError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CreateCreditLine() unexpected error: %v", err)
+			}
+			if result.TotalLimit != tt.totalLimit || result.AccountID != account.ID {
+				t.Errorf("CreateCreditLine() = %+v, want limit %v on account %v", result, tt.totalLimit, account.ID)
+			}
+		})
+	}
+}
+
+func TestAssignCard(t *testing.T) {
+	service, account, cardRepo := setupCreditLineService(t)
+
+	creditLine, err := service.CreateCreditLine(account.ID, account.UserID, "Visa", 1000)
+	if err != nil {
+		t.Fatalf("CreateCreditLine() unexpected error: %v", err)
+	}
+
+	primary := newCreditCard(account.ID, 600)
+	foreign := newCreditCard(uuid.NewString(), 0)
+	overLimit := newCreditCard(account.ID, 500)
+	debit := newCreditCard(account.ID, 0)
+	debit.CardType = entities.CardTypeDebit
+	for _, card := range []*entities.Card{primary, foreign, overLimit, debit} {
+		cardRepo.cards[card.ID] = card
+	}
+
+	bigSubLimit := 1500.0
+
+	tests := []struct {
+		name         string
+		cardID       string
+		isAdditional bool
+		subLimit     *float64
+		expectError  bool
+	}{
+		{name: "primary card joins line", cardID: primary.ID},
+		{name: "card from another account", cardID: foreign.ID, expectError: true},
+		{name: "debt exceeds line available credit", cardID: overLimit.ID, isAdditional: true, expectError: true},
+		{name: "debit card", cardID: debit.ID, expectError: true},
+		{name: "sub-limit above line limit", cardID: primary.ID, subLimit: &bigSubLimit, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card, err := service.AssignCard(creditLine.ID, tt.cardID, account.UserID, tt.isAdditional, tt.subLimit)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("AssignCard() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("AssignCard() unexpected error: %v", err)
+			}
+			if card.CreditLineID == nil || *card.CreditLineID != creditLine.ID {
+				t.Errorf("AssignCard() credit line = %v, want %v", card.CreditLineID, creditLine.ID)
+			}
+		})
+	}
+
+	if _, err := service.AssignCard(creditLine.ID, primary.ID, uuid.NewString(), false, nil); err != errors.ErrInsufficientRights {
+		t.Errorf("AssignCard() by stranger error = %v, want %v", err, errors.ErrInsufficientRights)
+	}
+}
+
+func TestCreditLineSharedLimit(t *testing.T) {
+	service, account, cardRepo := setupCreditLineService(t)
+
+	creditLine, err := service.CreateCreditLine(account.ID, account.UserID, "Visa", 1000)
+	if err != nil {
+		t.Fatalf("CreateCreditLine() unexpected error: %v", err)
+	}
+
+	primary := newCreditCard(account.ID, 600)
+	additional := newCreditCard(account.ID, 0)
+	additional.HolderName = "Maria Perez"
+	cardRepo.cards[primary.ID] = primary
+	cardRepo.cards[additional.ID] = additional
+
+	subLimit := 300.0
+	if _, err := service.AssignCard(creditLine.ID, primary.ID, account.UserID, false, nil); err != nil {
+		t.Fatalf("AssignCard() unexpected error: %v", err)
+	}
+	if _, err := service.AssignCard(creditLine.ID, additional.ID, account.UserID, true, &subLimit); err != nil {
+		t.Fatalf("AssignCard() unexpected error: %v", err)
+	}
+
+	// Reload the line with both cards as the repository would
+	line, err := service.GetCreditLine(creditLine.ID, account.UserID)
+	if err != nil {
+		t.Fatalf("GetCreditLine() unexpected error: %v", err)
+	}
+	primary.CreditLine = line
+	additional.CreditLine = line
+
+	tests := []struct {
+		name      string
+		card      *entities.Card
+		amount    float64
+		canCharge bool
+	}{
+		{name: "primary within line", card: primary, amount: 400, canCharge: true},
+		{name: "primary over line", card: primary, amount: 401, canCharge: false},
+		{name: "additional within sub-limit", card: additional, amount: 300, canCharge: true},
+		{name: "additional over sub-limit", card: additional, amount: 350, canCharge: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.card.CanCharge(tt.amount); got != tt.canCharge {
+				t.Errorf("CanCharge(%v) = %v, want %v", tt.amount, got, tt.canCharge)
+			}
+		})
+	}
+
+	t.Run("charges on one card reduce the other", func(t *testing.T) {
+		if err := additional.Charge(250); err != nil {
+			t.Fatalf("Charge() unexpected error: %v", err)
+		}
+		line.Cards = []entities.Card{*primary, *additional}
+
+		if got := primary.GetAvailableCreditWithInstallments(); got != 150 {
+			t.Errorf("GetAvailableCreditWithInstallments() = %v, want 150", got)
+		}
+		if got := line.GetUsedCredit(nil); got != 850 {
+			t.Errorf("GetUsedCredit() = %v, want 850", got)
+		}
+	})
+
+	t.Run("installment commitments above the debt reserve credit", func(t *testing.T) {
+		// The primary card paid its statement but still owes installments
+		primary.Balance = 100
+		primary.InstallmentPlans = []entities.InstallmentPlan{
+			{Status: entities.InstallmentPlanStatusActive, RemainingAmount: 500},
+			{Status: entities.InstallmentPlanStatusCompleted, RemainingAmount: 0},
+		}
+		line.Cards = []entities.Card{*primary, *additional}
+
+		if got := line.GetTotalInstallmentCommitments(nil); got != 500 {
+			t.Errorf("GetTotalInstallmentCommitments() = %v, want 500", got)
+		}
+		if got := primary.GetAvailableCreditWithInstallments(); got != 250 {
+			t.Errorf("GetAvailableCreditWithInstallments() = %v, want 250", got)
+		}
+		if primary.CanCharge(300) {
+			t.Error("CanCharge(300) = true, want false with installment commitments")
+		}
+	})
+}
+
+func TestUpdateCreditLine(t *testing.T) {
+	service, account, cardRepo := setupCreditLineService(t)
+
+	creditLine, err := service.CreateCreditLine(account.ID, account.UserID, "Visa", 1000)
+	if err != nil {
+		t.Fatalf("CreateCreditLine() unexpected error: %v", err)
+	}
+
+	card := newCreditCard(account.ID, 700)
+	cardRepo.cards[card.ID] = card
+	if _, err := service.AssignCard(creditLine.ID, card.ID, account.UserID, false, nil); err != nil {
+		t.Fatalf("AssignCard() unexpected error: %v", err)
+	}
+
+	belowUsed := 500.0
+	if _, err := service.UpdateCreditLine(creditLine.ID, account.UserID, nil, &belowUsed); err == nil {
+		t.Error("UpdateCreditLine() expected error for limit below used credit but got none")
+	}
+
+	newLimit := 2000.0
+	updated, err := service.UpdateCreditLine(creditLine.ID, account.UserID, nil, &newLimit)
+	if err != nil {
+		t.Fatalf("UpdateCreditLine() unexpected error: %v", err)
+	}
+	if updated.TotalLimit != newLimit {
+		t.Errorf("UpdateCreditLine() total limit = %v, want %v", updated.TotalLimit, newLimit)
+	}
+
+	if _, err := service.RemoveCard(creditLine.ID, card.ID, account.UserID, nil); err == nil {
+		t.Error("RemoveCard() expected error for card without own limit but got none")
+	}
+
+	ownLimit := 1000.0
+	removed, err := service.RemoveCard(creditLine.ID, card.ID, account.UserID, &ownLimit)
+	if err != nil {
+		t.Fatalf("RemoveCard() unexpected error: %v", err)
+	}
+	if removed.CreditLineID != nil || removed.CreditLimit == nil || *removed.CreditLimit != ownLimit {
+		t.Errorf("RemoveCard() = line %v limit %v, want no line and limit %v", removed.CreditLineID, removed.CreditLimit, ownLimit)
+	}
+}
+
+func TestUpdateCreditLineWithInstallmentCommitments(t *testing.T) {
+	service, account, cardRepo := setupCreditLineService(t)
+
+	creditLine, err := service.CreateCreditLine(account.ID, account.UserID, "Visa", 2000)
+	if err != nil {
+		t.Fatalf("CreateCreditLine() unexpected error: %v", err)
+	}
+
+	// Payments brought the debt of the card below what is still owed on its plan
+	card := newCreditCard(account.ID, 200)
+	card.InstallmentPlans = []entities.InstallmentPlan{{
+		ID:              uuid.NewString(),
+		CardID:          card.ID,
+		Status:          entities.InstallmentPlanStatusActive,
+		RemainingAmount: 800,
+	}}
+	cardRepo.cards[card.ID] = card
+	if _, err := service.AssignCard(creditLine.ID, card.ID, account.UserID, false, nil); err != nil {
+		t.Fatalf("AssignCard() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		totalLimit  float64
+		expectError bool
+	}{
+		{"above debt but below commitments", 500, true},
+		{"equal to commitments", 800, false},
+		{"above commitments", 900, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totalLimit := tt.totalLimit
+			updated, err := service.UpdateCreditLine(creditLine.ID, account.UserID, nil, &totalLimit)
+			if tt.expectError {
+				if !errors.IsValidationError(err) {
+					t.Errorf("UpdateCreditLine() error = %v, want validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateCreditLine() unexpected error: %v", err)
+			}
+			if updated.TotalLimit != totalLimit {
+				t.Errorf("UpdateCreditLine() total limit = %v, want %v", updated.TotalLimit, totalLimit)
+			}
+		})
+	}
+}
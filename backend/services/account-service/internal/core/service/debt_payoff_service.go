@@ -0,0 +1,608 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+)
+
+// maxPayoffMonths is the longest schedule a simulation builds; budgets that need more are rejected
+const maxPayoffMonths = 360
+
+// payoffTolerance is the share of the starting debt a plan can fall behind and still be on track
+const payoffTolerance = 0.01
+
+// DebtPayoffService simulates how to pay off the credit card debt and installment plans of a user
+// with a monthly budget, saves the chosen plan and tracks the progress against it
+type DebtPayoffService struct {
+	payoffRepo            ports.DebtPayoffRepositoryInterface
+	accountRepo           ports.AccountRepositoryInterface
+	cardRepo              ports.CardRepositoryInterface
+	cardMonthlyRate       float64 // Percent, for card balances when the simulation does not set one
+	minimumPaymentPercent float64 // Share of a card balance paid each month on top of its interest
+}
+
+// NewDebtPayoffService creates a new debt payoff service
+func NewDebtPayoffService(payoffRepo ports.DebtPayoffRepositoryInterface, accountRepo ports.AccountRepositoryInterface, cardRepo ports.CardRepositoryInterface, cardMonthlyRate, minimumPaymentPercent float64) *DebtPayoffService {
+	return &DebtPayoffService{
+		payoffRepo:            payoffRepo,
+		accountRepo:           accountRepo,
+		cardRepo:              cardRepo,
+		cardMonthlyRate:       cardMonthlyRate,
+		minimumPaymentPercent: minimumPaymentPercent,
+	}
+}
+
+// payoffBalance is a debt as the simulation pays it
+type payoffBalance struct {
+	debt             entities.PayoffDebt
+	balance          float64
+	rate             float64 // Cards: monthly interest as a fraction
+	flatRate         float64 // Installment plans: interest over the principal, embedded in the installments
+	minimumShare     float64 // Cards: share of the balance paid each month on top of the interest
+	installmentsLeft int
+}
+
+// minimumPayment is what the debt requires this month: the interest of a month plus a share of a
+// card balance, or the next installment of a plan
+func (b *payoffBalance) minimumPayment() float64 {
+	if b.debt.Type == entities.DebtTypeInstallmentPlan {
+		if b.installmentsLeft <= 1 {
+			return b.balance
+		}
+		return roundCents(b.balance / float64(b.installmentsLeft))
+	}
+	return math.Min(b.balance, roundCents(b.balance*(b.rate+b.minimumShare)))
+}
+
+// payoffAmount is what pays the debt off today. Paying installments ahead cancels the interest
+// embedded in them.
+func (b *payoffBalance) payoffAmount() float64 {
+	return roundCents(b.balance / (1 + b.flatRate))
+}
+
+// payMinimum pays the minimum of the month and returns the interest part of the payment
+func (b *payoffBalance) payMinimum(amount float64) float64 {
+	b.balance = roundCents(b.balance - amount)
+	if b.debt.Type != entities.DebtTypeInstallmentPlan {
+		return 0
+	}
+	b.installmentsLeft--
+	return roundCents(amount * b.flatRate / (1 + b.flatRate))
+}
+
+// prepay pays ahead of the minimum; on installment plans it cancels the installments it covers with
+// their interest
+func (b *payoffBalance) prepay(amount float64) {
+	if amount >= b.payoffAmount() {
+		b.balance = 0
+		return
+	}
+	b.balance = roundCents(b.balance - amount*(1+b.flatRate))
+}
+
+// accrue charges a month of interest on what is left of a card balance
+func (b *payoffBalance) accrue() float64 {
+	if b.debt.Type != entities.DebtTypeCard || b.balance <= 0 {
+		return 0
+	}
+	interest := roundCents(b.balance * b.rate)
+	b.balance = roundCents(b.balance + interest)
+	return interest
+}
+
+// Simulate builds the month by month schedule to pay off the debts of the user in the currency of
+// the budget with one strategy
+func (s *DebtPayoffService) Simulate(userID string, options entities.DebtPayoffOptions) (*entities.DebtPayoffSimulation, error) {
+	options, err := s.normalizeOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := s.loadDebts(userID, options.Currency, *options.CardMonthlyRate)
+	if err != nil {
+		return nil, err
+	}
+	return simulatePayoff(balances, options, time.Now())
+}
+
+// Compare simulates the avalanche and snowball strategies, and the custom one when an order is
+// given, for the same budget
+func (s *DebtPayoffService) Compare(userID string, options entities.DebtPayoffOptions) (*entities.DebtPayoffComparison, error) {
+	strategies := []entities.DebtPayoffStrategy{entities.DebtPayoffStrategyAvalanche, entities.DebtPayoffStrategySnowball}
+	if len(options.CustomOrder) > 0 {
+		strategies = append(strategies, entities.DebtPayoffStrategyCustom)
+	}
+	options.Strategy = entities.DebtPayoffStrategyAvalanche
+
+	options, err := s.normalizeOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := s.loadDebts(userID, options.Currency, *options.CardMonthlyRate)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	comparison := &entities.DebtPayoffComparison{}
+	var lowestInterest float64
+	var fewestMonths int
+	for _, strategy := range strategies {
+		options.Strategy = strategy
+		simulation, err := simulatePayoff(copyBalances(balances), options, now)
+		if err != nil {
+			return nil, err
+		}
+
+		// Ties keep the first strategy: avalanche, then snowball
+		if len(comparison.Simulations) == 0 || simulation.TotalInterest < lowestInterest {
+			comparison.Cheapest = strategy
+			lowestInterest = simulation.TotalInterest
+		}
+		if len(comparison.Simulations) == 0 || simulation.Months < fewestMonths {
+			comparison.Fastest = strategy
+			fewestMonths = simulation.Months
+		}
+		comparison.Simulations = append(comparison.Simulations, *simulation)
+	}
+
+	return comparison, nil
+}
+
+// SavePlan simulates a strategy and keeps it as the plan the user follows, abandoning the previous one
+func (s *DebtPayoffService) SavePlan(userID string, options entities.DebtPayoffOptions) (*entities.DebtPayoffPlan, error) {
+	simulation, err := s.Simulate(userID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &entities.DebtPayoffPlan{
+		UserID:               userID,
+		DebtPayoffSimulation: *simulation,
+		Status:               entities.DebtPayoffPlanStatusActive,
+	}
+	if err := s.payoffRepo.Create(plan); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("📉 Debt payoff plan %s saved for user %s (%s, %d months)\n", plan.ID, userID, plan.Strategy, plan.Months)
+	return plan, nil
+}
+
+// GetPlans lists the saved plans of the user, newest first
+func (s *DebtPayoffService) GetPlans(userID, status string) ([]*entities.DebtPayoffPlan, error) {
+	return s.payoffRepo.GetByUser(userID, status)
+}
+
+// GetPlan gets a saved plan of the user
+func (s *DebtPayoffService) GetPlan(planID, userID string) (*entities.DebtPayoffPlan, error) {
+	plan, err := s.payoffRepo.GetByID(planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.UserID != userID {
+		return nil, errors.ErrInsufficientRights
+	}
+	return plan, nil
+}
+
+// GetProgress compares the current balance of the debts of a plan with the balance its schedule
+// expected by now. An active plan whose debts are all paid off is completed.
+func (s *DebtPayoffService) GetProgress(planID, userID string) (*entities.DebtPayoffProgress, error) {
+	plan, err := s.GetPlan(planID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := s.loadDebts(userID, plan.Currency, plan.CardRate)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]float64, len(balances))
+	for _, balance := range balances {
+		current[balance.debt.ID] = balance.balance
+	}
+
+	now := time.Now()
+	progress := &entities.DebtPayoffProgress{
+		PlanID:       plan.ID,
+		Status:       plan.Status,
+		StartingDebt: plan.StartingDebt,
+		PayoffDate:   plan.PayoffDate,
+		Debts:        make([]entities.DebtProgress, 0, len(plan.Debts)),
+	}
+	for _, month := range plan.Schedule {
+		if month.Date.After(now) {
+			break
+		}
+		progress.MonthsElapsed = month.Month
+	}
+
+	for _, debt := range plan.Debts {
+		planned := debt.Balance
+		if progress.MonthsElapsed > 0 {
+			planned = plannedBalance(plan.Schedule[progress.MonthsElapsed-1], debt.ID)
+		}
+		actual := current[debt.ID]
+		delete(current, debt.ID)
+
+		progress.PlannedBalance += planned
+		progress.ActualBalance += actual
+		progress.Debts = append(progress.Debts, entities.DebtProgress{
+			DebtID:         debt.ID,
+			Type:           debt.Type,
+			Name:           debt.Name,
+			PlannedBalance: planned,
+			ActualBalance:  actual,
+			PaidOff:        actual <= 0,
+		})
+	}
+	for _, balance := range current {
+		progress.NewDebt += balance
+	}
+
+	progress.PlannedBalance = roundCents(progress.PlannedBalance)
+	progress.ActualBalance = roundCents(progress.ActualBalance)
+	progress.NewDebt = roundCents(progress.NewDebt)
+	progress.Difference = roundCents(progress.ActualBalance - progress.PlannedBalance)
+	progress.OnTrack = progress.Difference <= plan.StartingDebt*payoffTolerance
+	if plan.StartingDebt > 0 {
+		progress.ProgressPercent = roundCents(math.Max(plan.StartingDebt-progress.ActualBalance, 0) / plan.StartingDebt * 100)
+	}
+
+	if plan.IsActive() && progress.ActualBalance <= 0 {
+		plan.Status = entities.DebtPayoffPlanStatusCompleted
+		plan.CompletedAt = &now
+		if err := s.payoffRepo.Update(plan); err != nil {
+			return nil, err
+		}
+		progress.Status = plan.Status
+		fmt.Printf("🎉 Debt payoff plan %s completed\n", plan.ID)
+	}
+
+	return progress, nil
+}
+
+// AbandonPlan drops the active plan of the user
+func (s *DebtPayoffService) AbandonPlan(planID, userID string) (*entities.DebtPayoffPlan, error) {
+	plan, err := s.GetPlan(planID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.IsActive() {
+		return nil, errors.NewValidationError("status", fmt.Sprintf("plan is already %s", plan.Status))
+	}
+
+	now := time.Now()
+	plan.Status = entities.DebtPayoffPlanStatusAbandoned
+	plan.AbandonedAt = &now
+	if err := s.payoffRepo.Update(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// normalizeOptions validates the simulation options and fills in the defaults
+func (s *DebtPayoffService) normalizeOptions(options entities.DebtPayoffOptions) (entities.DebtPayoffOptions, error) {
+	if options.Strategy == "" {
+		options.Strategy = entities.DebtPayoffStrategyAvalanche
+	}
+	if !entities.IsValidDebtPayoffStrategy(options.Strategy) {
+		return options, errors.NewValidationError("strategy", "strategy must be avalanche, snowball or custom")
+	}
+	if options.Strategy == entities.DebtPayoffStrategyCustom && len(options.CustomOrder) == 0 {
+		return options, errors.NewValidationError("custom_order", "custom strategy requires the order of the debts")
+	}
+	if options.MonthlyBudget <= 0 {
+		return options, errors.NewValidationError("monthly_budget", "monthly budget must be greater than 0")
+	}
+	if options.Currency == "" {
+		options.Currency = entities.CurrencyARS
+	}
+	if !entities.IsValidCurrency(options.Currency) {
+		return options, errors.ErrInvalidCurrency
+	}
+	if options.CardMonthlyRate == nil {
+		rate := s.cardMonthlyRate
+		options.CardMonthlyRate = &rate
+	}
+	if *options.CardMonthlyRate < 0 || *options.CardMonthlyRate > 100 {
+		return options, errors.NewValidationError("card_monthly_rate", "card monthly rate must be between 0 and 100")
+	}
+	return options, nil
+}
+
+// loadDebts gets the debts of the credit cards of the user's accounts in a currency: the revolving
+// balance of each card and each of its active installment plans. Installment purchases are charged
+// in full to the card balance, so what is left of the plans is taken out of the card debt.
+func (s *DebtPayoffService) loadDebts(userID string, currency entities.Currency, cardMonthlyRate float64) ([]*payoffBalance, error) {
+	accounts, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	var balances []*payoffBalance
+	for _, account := range accounts {
+		if account.Currency != currency || account.IsClosed() {
+			continue
+		}
+
+		cards, _, err := s.cardRepo.GetByAccountWithInstallmentPlans(account.ID, 100, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cards: %w", err)
+		}
+
+		for _, card := range cards {
+			if card.CardType != entities.CardTypeCredit {
+				continue
+			}
+
+			name := card.Nickname
+			if name == "" {
+				name = fmt.Sprintf("%s *%s", card.CardBrand, card.LastFourDigits)
+			}
+
+			if revolving := roundCents(card.GetDebt() - card.GetTotalInstallmentCommitments()); revolving > 0 {
+				balances = append(balances, &payoffBalance{
+					debt: entities.PayoffDebt{
+						ID:          card.ID,
+						Type:        entities.DebtTypeCard,
+						CardID:      card.ID,
+						Name:        name,
+						Balance:     revolving,
+						MonthlyRate: cardMonthlyRate,
+					},
+					balance:      revolving,
+					rate:         cardMonthlyRate / 100,
+					minimumShare: s.minimumPaymentPercent / 100,
+				})
+			}
+
+			for _, plan := range card.GetActiveInstallmentPlans() {
+				if plan.RemainingAmount <= 0 {
+					continue
+				}
+
+				left := plan.InstallmentsCount - plan.PaidInstallments
+				if left < 1 {
+					left = 1
+				}
+				description := plan.Description
+				if description == "" {
+					description = plan.MerchantName
+				}
+				if description == "" {
+					description = name
+				}
+
+				// A flat rate over N installments costs about 2/(N+1) of it per month
+				flatRate := plan.InterestRate / 100
+				balances = append(balances, &payoffBalance{
+					debt: entities.PayoffDebt{
+						ID:          plan.ID,
+						Type:        entities.DebtTypeInstallmentPlan,
+						CardID:      card.ID,
+						Name:        fmt.Sprintf("%s (%d installments)", description, plan.InstallmentsCount),
+						Balance:     plan.RemainingAmount,
+						MonthlyRate: roundCents(2 * plan.InterestRate / float64(plan.InstallmentsCount+1)),
+					},
+					balance:          plan.RemainingAmount,
+					flatRate:         flatRate,
+					installmentsLeft: left,
+				})
+			}
+		}
+	}
+
+	return balances, nil
+}
+
+// simulatePayoff pays the debts month by month from start: first the minimum of every debt, then
+// the rest of the budget to the debts in the order of the strategy, and last the card interest on
+// what is left
+func simulatePayoff(balances []*payoffBalance, options entities.DebtPayoffOptions, start time.Time) (*entities.DebtPayoffSimulation, error) {
+	if len(balances) == 0 {
+		return nil, errors.NewValidationError("currency", fmt.Sprintf("there are no card debts or installment plans in %s to pay off", options.Currency))
+	}
+
+	order, err := payoffOrder(balances, options)
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := &entities.DebtPayoffSimulation{
+		Strategy:      options.Strategy,
+		MonthlyBudget: options.MonthlyBudget,
+		Currency:      options.Currency,
+		CustomOrder:   options.CustomOrder,
+		CardRate:      *options.CardMonthlyRate,
+		StartDate:     start,
+		PayoffDate:    start,
+		Debts:         make([]entities.PayoffDebt, 0, len(balances)),
+		Schedule:      []entities.PayoffMonth{},
+	}
+	for _, balance := range balances {
+		balance.debt.MinimumPayment = balance.minimumPayment()
+		simulation.StartingDebt += balance.balance
+	}
+	simulation.StartingDebt = roundCents(simulation.StartingDebt)
+
+	for month := 1; remainingDebt(balances) > 0; month++ {
+		if month > maxPayoffMonths {
+			return nil, errors.NewValidationError("monthly_budget", fmt.Sprintf("a monthly budget of %.2f does not pay off the debts in %d years", options.MonthlyBudget, maxPayoffMonths/12))
+		}
+
+		var minimums float64
+		for _, balance := range balances {
+			if balance.balance > 0 {
+				minimums += balance.minimumPayment()
+			}
+		}
+		if roundCents(minimums) > options.MonthlyBudget {
+			return nil, errors.NewValidationError("monthly_budget", fmt.Sprintf("monthly budget of %.2f does not cover the minimum payments of %.2f", options.MonthlyBudget, roundCents(minimums)))
+		}
+
+		paid := make(map[string]float64, len(balances))
+		interest := make(map[string]float64, len(balances))
+		available := options.MonthlyBudget
+
+		for _, balance := range balances {
+			if balance.balance <= 0 {
+				continue
+			}
+			minimum := balance.minimumPayment()
+			interest[balance.debt.ID] += balance.payMinimum(minimum)
+			paid[balance.debt.ID] += minimum
+			available = roundCents(available - minimum)
+		}
+
+		for _, balance := range order {
+			if available <= 0 {
+				break
+			}
+			if balance.balance <= 0 {
+				continue
+			}
+			extra := math.Min(available, balance.payoffAmount())
+			balance.prepay(extra)
+			paid[balance.debt.ID] += extra
+			available = roundCents(available - extra)
+		}
+
+		scheduled := entities.PayoffMonth{Month: month, Date: start.AddDate(0, month, 0)}
+		for _, balance := range balances {
+			amount, ok := paid[balance.debt.ID]
+			if !ok {
+				continue
+			}
+			interest[balance.debt.ID] += balance.accrue()
+
+			payment := entities.PayoffPayment{
+				DebtID:   balance.debt.ID,
+				Amount:   roundCents(amount),
+				Interest: interest[balance.debt.ID],
+				Balance:  balance.balance,
+			}
+			scheduled.Payments = append(scheduled.Payments, payment)
+			scheduled.Payment += payment.Amount
+			scheduled.Interest += payment.Interest
+			scheduled.RemainingBalance += balance.balance
+
+			if balance.balance <= 0 && balance.debt.PaidOffMonth == 0 {
+				balance.debt.PaidOffMonth = month
+			}
+		}
+		scheduled.Payment = roundCents(scheduled.Payment)
+		scheduled.Interest = roundCents(scheduled.Interest)
+		scheduled.RemainingBalance = roundCents(scheduled.RemainingBalance)
+
+		simulation.Schedule = append(simulation.Schedule, scheduled)
+		simulation.TotalPaid += scheduled.Payment
+		simulation.TotalInterest += scheduled.Interest
+		simulation.Months = month
+		simulation.PayoffDate = scheduled.Date
+	}
+
+	simulation.TotalPaid = roundCents(simulation.TotalPaid)
+	simulation.TotalInterest = roundCents(simulation.TotalInterest)
+	for _, balance := range balances {
+		simulation.Debts = append(simulation.Debts, balance.debt)
+	}
+
+	return simulation, nil
+}
+
+// payoffOrder sorts the debts that receive the budget left after the minimums. Avalanche goes from the
+// highest rate, snowball from the smallest balance, and custom follows the order of the user with the
+// debts it leaves out last, by rate.
+func payoffOrder(balances []*payoffBalance, options entities.DebtPayoffOptions) ([]*payoffBalance, error) {
+	order := make([]*payoffBalance, len(balances))
+	copy(order, balances)
+
+	byRate := func(i, j int) bool {
+		if order[i].debt.MonthlyRate != order[j].debt.MonthlyRate {
+			return order[i].debt.MonthlyRate > order[j].debt.MonthlyRate
+		}
+		return order[i].balance < order[j].balance
+	}
+
+	switch options.Strategy {
+	case entities.DebtPayoffStrategySnowball:
+		sort.SliceStable(order, func(i, j int) bool {
+			if order[i].balance != order[j].balance {
+				return order[i].balance < order[j].balance
+			}
+			return order[i].debt.MonthlyRate > order[j].debt.MonthlyRate
+		})
+
+	case entities.DebtPayoffStrategyCustom:
+		positions := make(map[string]int, len(options.CustomOrder))
+		for i, id := range options.CustomOrder {
+			positions[id] = i
+		}
+		known := 0
+		for _, balance := range balances {
+			if _, ok := positions[balance.debt.ID]; ok {
+				known++
+			}
+		}
+		if known != len(positions) {
+			return nil, errors.NewValidationError("custom_order", "custom order has debts that are not card balances or active installment plans of the user")
+		}
+
+		sort.SliceStable(order, func(i, j int) bool {
+			pi, iok := positions[order[i].debt.ID]
+			pj, jok := positions[order[j].debt.ID]
+			switch {
+			case iok && jok:
+				return pi < pj
+			case iok != jok:
+				return iok
+			}
+			return byRate(i, j)
+		})
+
+	default:
+		sort.SliceStable(order, byRate)
+	}
+
+	return order, nil
+}
+
+// plannedBalance is the balance the schedule expected for a debt after a month
+func plannedBalance(month entities.PayoffMonth, debtID string) float64 {
+	for _, payment := range month.Payments {
+		if payment.DebtID == debtID {
+			return payment.Balance
+		}
+	}
+	return 0 // Paid off in an earlier month
+}
+
+func remainingDebt(balances []*payoffBalance) float64 {
+	var total float64
+	for _, balance := range balances {
+		total += math.Max(balance.balance, 0)
+	}
+	return roundCents(total)
+}
+
+func copyBalances(balances []*payoffBalance) []*payoffBalance {
+	copies := make([]*payoffBalance, len(balances))
+	for i, balance := range balances {
+		clone := *balance
+		copies[i] = &clone
+	}
+	return copies
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
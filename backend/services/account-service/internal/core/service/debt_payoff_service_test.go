@@ -0,0 +1,360 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// MockDebtPayoffRepository implements a mock debt payoff repository for testing
+type MockDebtPayoffRepository struct {
+	plans map[string]*entities.DebtPayoffPlan
+}
+
+func NewMockDebtPayoffRepository() *MockDebtPayoffRepository {
+	return &MockDebtPayoffRepository{plans: make(map[string]*entities.DebtPayoffPlan)}
+}
+
+func (m *MockDebtPayoffRepository) Create(plan *entities.DebtPayoffPlan) error {
+	for _, existing := range m.plans {
+		if existing.UserID == plan.UserID && existing.IsActive() {
+			now := time.Now()
+			existing.Status = entities.DebtPayoffPlanStatusAbandoned
+			existing.AbandonedAt = &now
+		}
+	}
+	if plan.ID == "" {
+		plan.ID = uuid.NewString()
+	}
+	plan.CreatedAt = time.Now()
+	m.plans[plan.ID] = plan
+	return nil
+}
+
+func (m *MockDebtPayoffRepository) GetByID(planID string) (*entities.DebtPayoffPlan, error) {
+	plan, exists := m.plans[planID]
+	if !exists {
+		return nil, fmt.Errorf("debt payoff plan not found")
+	}
+	return plan, nil
+}
+
+func (m *MockDebtPayoffRepository) GetByUser(userID, status string) ([]*entities.DebtPayoffPlan, error) {
+	var plans []*entities.DebtPayoffPlan
+	for _, plan := range m.plans {
+		if plan.UserID == userID && (status == "" || string(plan.Status) == status) {
+			plans = append(plans, plan)
+		}
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].CreatedAt.After(plans[j].CreatedAt) })
+	return plans, nil
+}
+
+func (m *MockDebtPayoffRepository) Update(plan *entities.DebtPayoffPlan) error {
+	m.plans[plan.ID] = plan
+	return nil
+}
+
+// Verify interface compliance
+var _ ports.DebtPayoffRepositoryInterface = (*MockDebtPayoffRepository)(nil)
+
+func (m *MockCardRepository) GetByAccountWithInstallmentPlans(accountID string, limit, offset int) ([]*entities.Card, int64, error) {
+	var cards []*entities.Card
+	for _, card := range m.cards {
+		if card.AccountID == accountID {
+			cards = append(cards, card)
+		}
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].ID < cards[j].ID })
+	return cards, int64(len(cards)), nil
+}
+
+type debtPayoffFixture struct {
+	service *DebtPayoffService
+	userID  string
+	visa    *entities.Card // 10000 revolving plus a 6000 plan with a 20% flat rate
+	master  *entities.Card // 2000 revolving
+	plan    *entities.InstallmentPlan
+}
+
+func setupDebtPayoffService(t *testing.T) *debtPayoffFixture {
+	accountRepo := NewMockAccountRepository()
+	account := &entities.Account{
+		UserID:      uuid.NewString(),
+		AccountType: entities.AccountTypeCredit,
+		Name:        "Tarjetas",
+		Currency:    entities.CurrencyARS,
+		IsActive:    true,
+	}
+	if err := accountRepo.Create(account); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	dollars := &entities.Account{
+		UserID:      account.UserID,
+		AccountType: entities.AccountTypeCredit,
+		Name:        "Tarjetas USD",
+		Currency:    entities.CurrencyUSD,
+		IsActive:    true,
+	}
+	if err := accountRepo.Create(dollars); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	visa := newCreditCard(account.ID, 16000)
+	visa.Nickname = "Visa"
+	plan := entities.InstallmentPlan{
+		ID:                uuid.NewString(),
+		CardID:            visa.ID,
+		UserID:            account.UserID,
+		Description:       "Heladera",
+		Status:            entities.InstallmentPlanStatusActive,
+		InstallmentsCount: 6,
+		InstallmentAmount: 1000,
+		RemainingAmount:   6000,
+		InterestRate:      20,
+	}
+	visa.InstallmentPlans = []entities.InstallmentPlan{plan}
+	master := newCreditCard(account.ID, 2000)
+	master.Nickname = "Master"
+	debit := newCreditCard(account.ID, 0)
+	debit.CardType = entities.CardTypeDebit
+
+	cardRepo := &MockCardRepository{cards: map[string]*entities.Card{
+		visa.ID:   visa,
+		master.ID: master,
+		debit.ID:  debit,
+	}}
+	usd := newCreditCard(dollars.ID, 300)
+	cardRepo.cards[usd.ID] = usd
+
+	return &debtPayoffFixture{
+		service: NewDebtPayoffService(NewMockDebtPayoffRepository(), accountRepo, cardRepo, 6, 5),
+		userID:  account.UserID,
+		visa:    visa,
+		master:  master,
+		plan:    &visa.InstallmentPlans[0],
+	}
+}
+
+func payoffRate(rate float64) *float64 {
+	return &rate
+}
+
+func TestSimulateDebtPayoff(t *testing.T) {
+	t.Run("splits card debt from installment plans", func(t *testing.T) {
+		f := setupDebtPayoffService(t)
+
+		simulation, err := f.service.Simulate(f.userID, entities.DebtPayoffOptions{MonthlyBudget: 5000})
+		if err != nil {
+			t.Fatalf("Simulate() unexpected error: %v", err)
+		}
+		if simulation.Strategy != entities.DebtPayoffStrategyAvalanche || simulation.Currency != entities.CurrencyARS {
+			t.Errorf("Simulate() defaults = %v %v, want avalanche in ARS", simulation.Strategy, simulation.Currency)
+		}
+		if len(simulation.Debts) != 3 || simulation.StartingDebt != 18000 {
+			t.Fatalf("Simulate() debts = %d starting at %v, want 3 debts starting at 18000", len(simulation.Debts), simulation.StartingDebt)
+		}
+
+		last := simulation.Schedule[len(simulation.Schedule)-1]
+		if last.RemainingBalance != 0 || simulation.Months != len(simulation.Schedule) {
+			t.Errorf("Simulate() ends with %v left after %d months, want 0 after %d", last.RemainingBalance, simulation.Months, len(simulation.Schedule))
+		}
+		if !simulation.PayoffDate.Equal(simulation.StartDate.AddDate(0, simulation.Months, 0)) {
+			t.Errorf("Simulate() payoff date = %v, want %d months after the start", simulation.PayoffDate, simulation.Months)
+		}
+		for _, month := range simulation.Schedule {
+			if month.Payment > 5000.001 {
+				t.Errorf("Simulate() month %d pays %v, over the budget", month.Month, month.Payment)
+			}
+		}
+		for _, debt := range simulation.Debts {
+			if debt.PaidOffMonth == 0 {
+				t.Errorf("Simulate() debt %s never paid off", debt.Name)
+			}
+		}
+	})
+
+	t.Run("paying installments ahead cancels their interest", func(t *testing.T) {
+		f := setupDebtPayoffService(t)
+		f.visa.Balance = 6000 // Only the plan is left
+		f.master.Balance = 0
+
+		simulation, err := f.service.Simulate(f.userID, entities.DebtPayoffOptions{MonthlyBudget: 10000})
+		if err != nil {
+			t.Fatalf("Simulate() unexpected error: %v", err)
+		}
+		// 1000 installment (166.67 of interest) plus the other 5000 without their interest
+		if simulation.Months != 1 || simulation.TotalPaid != 5166.67 || simulation.TotalInterest != 166.67 {
+			t.Errorf("Simulate() = %d months, paid %v with %v interest, want 1 month, 5166.67 with 166.67", simulation.Months, simulation.TotalPaid, simulation.TotalInterest)
+		}
+	})
+
+	t.Run("budget below the minimum payments", func(t *testing.T) {
+		f := setupDebtPayoffService(t)
+
+		// 1000 installment plus 11% (6% interest and 5%) of 12000
+		if _, err := f.service.Simulate(f.userID, entities.DebtPayoffOptions{MonthlyBudget: 2000}); !errors.IsValidationError(err) {
+			t.Errorf("Simulate() error = %v, want validation error", err)
+		}
+	})
+
+	t.Run("custom order pays the chosen debt first", func(t *testing.T) {
+		f := setupDebtPayoffService(t)
+
+		simulation, err := f.service.Simulate(f.userID, entities.DebtPayoffOptions{
+			Strategy:      entities.DebtPayoffStrategyCustom,
+			MonthlyBudget: 5000,
+			CustomOrder:   []string{f.visa.ID},
+		})
+		if err != nil {
+			t.Fatalf("Simulate() unexpected error: %v", err)
+		}
+		for _, payment := range simulation.Schedule[0].Payments {
+			if payment.DebtID == f.visa.ID && payment.Amount <= 1100 {
+				t.Errorf("Simulate() first month pays %v to the chosen card, want more than its minimum", payment.Amount)
+			}
+			if payment.DebtID == f.master.ID && payment.Amount != 220 {
+				t.Errorf("Simulate() first month pays %v to the other card, want its 220 minimum", payment.Amount)
+			}
+		}
+
+		_, err = f.service.Simulate(f.userID, entities.DebtPayoffOptions{
+			Strategy:      entities.DebtPayoffStrategyCustom,
+			MonthlyBudget: 5000,
+			CustomOrder:   []string{uuid.NewString()},
+		})
+		if !errors.IsValidationError(err) {
+			t.Errorf("Simulate() with an unknown debt error = %v, want validation error", err)
+		}
+	})
+
+	t.Run("only debts in the budget currency", func(t *testing.T) {
+		f := setupDebtPayoffService(t)
+
+		simulation, err := f.service.Simulate(f.userID, entities.DebtPayoffOptions{MonthlyBudget: 100, Currency: entities.CurrencyUSD})
+		if err != nil {
+			t.Fatalf("Simulate() unexpected error: %v", err)
+		}
+		if len(simulation.Debts) != 1 || simulation.StartingDebt != 300 {
+			t.Errorf("Simulate() in USD = %d debts starting at %v, want the 300 card", len(simulation.Debts), simulation.StartingDebt)
+		}
+
+		if _, err := f.service.Simulate(uuid.NewString(), entities.DebtPayoffOptions{MonthlyBudget: 100}); !errors.IsValidationError(err) {
+			t.Errorf("Simulate() without debts error = %v, want validation error", err)
+		}
+	})
+}
+
+func TestCompareDebtPayoffStrategies(t *testing.T) {
+	f := setupDebtPayoffService(t)
+
+	// At 3% the cards are cheaper than the plan (about 5.71% a month), so avalanche prepays the plan
+	// while snowball goes for the smallest card
+	comparison, err := f.service.Compare(f.userID, entities.DebtPayoffOptions{MonthlyBudget: 4000, CardMonthlyRate: payoffRate(3)})
+	if err != nil {
+		t.Fatalf("Compare() unexpected error: %v", err)
+	}
+	if len(comparison.Simulations) != 2 {
+		t.Fatalf("Compare() simulations = %d, want avalanche and snowball", len(comparison.Simulations))
+	}
+
+	avalanche, snowball := comparison.Simulations[0], comparison.Simulations[1]
+	if avalanche.Strategy != entities.DebtPayoffStrategyAvalanche || snowball.Strategy != entities.DebtPayoffStrategySnowball {
+		t.Fatalf("Compare() strategies = %v, %v", avalanche.Strategy, snowball.Strategy)
+	}
+	if avalanche.TotalInterest >= snowball.TotalInterest || comparison.Cheapest != entities.DebtPayoffStrategyAvalanche {
+		t.Errorf("Compare() interest avalanche %v snowball %v, cheapest %v, want avalanche", avalanche.TotalInterest, snowball.TotalInterest, comparison.Cheapest)
+	}
+
+	paidOff := func(simulation entities.DebtPayoffSimulation, id string) int {
+		for _, debt := range simulation.Debts {
+			if debt.ID == id {
+				return debt.PaidOffMonth
+			}
+		}
+		return 0
+	}
+	if paidOff(snowball, f.master.ID) >= paidOff(avalanche, f.master.ID) {
+		t.Errorf("Compare() snowball pays the smallest card off in month %d, not before avalanche (%d)", paidOff(snowball, f.master.ID), paidOff(avalanche, f.master.ID))
+	}
+	if paidOff(avalanche, f.plan.ID) >= paidOff(snowball, f.plan.ID) {
+		t.Errorf("Compare() avalanche pays the plan off in month %d, not before snowball (%d)", paidOff(avalanche, f.plan.ID), paidOff(snowball, f.plan.ID))
+	}
+	if math.Abs(avalanche.TotalPaid-snowball.TotalPaid-(avalanche.TotalInterest-snowball.TotalInterest)) > 0.05 {
+		t.Errorf("Compare() paid %v and %v, want them to differ only by the interest", avalanche.TotalPaid, snowball.TotalPaid)
+	}
+}
+
+func TestDebtPayoffPlanProgress(t *testing.T) {
+	f := setupDebtPayoffService(t)
+	options := entities.DebtPayoffOptions{Strategy: entities.DebtPayoffStrategySnowball, MonthlyBudget: 5000}
+
+	first, err := f.service.SavePlan(f.userID, options)
+	if err != nil {
+		t.Fatalf("SavePlan() unexpected error: %v", err)
+	}
+	plan, err := f.service.SavePlan(f.userID, options)
+	if err != nil {
+		t.Fatalf("SavePlan() unexpected error: %v", err)
+	}
+	if first.Status != entities.DebtPayoffPlanStatusAbandoned || !plan.IsActive() {
+		t.Errorf("SavePlan() statuses = %v and %v, want the first plan abandoned", first.Status, plan.Status)
+	}
+
+	if _, err := f.service.GetPlan(plan.ID, uuid.NewString()); err != errors.ErrInsufficientRights {
+		t.Errorf("GetPlan() by another user error = %v, want %v", err, errors.ErrInsufficientRights)
+	}
+
+	progress, err := f.service.GetProgress(plan.ID, f.userID)
+	if err != nil {
+		t.Fatalf("GetProgress() unexpected error: %v", err)
+	}
+	if progress.MonthsElapsed != 0 || progress.ActualBalance != 18000 || !progress.OnTrack || progress.ProgressPercent != 0 {
+		t.Errorf("GetProgress() at the start = %+v, want on track with nothing paid", progress)
+	}
+
+	// The smallest card gets paid and a new card debt shows up
+	f.master.Balance = 0
+	extra := newCreditCard(f.visa.AccountID, 700)
+	f.service.cardRepo.(*MockCardRepository).cards[extra.ID] = extra
+
+	progress, err = f.service.GetProgress(plan.ID, f.userID)
+	if err != nil {
+		t.Fatalf("GetProgress() unexpected error: %v", err)
+	}
+	if progress.ActualBalance != 16000 || progress.NewDebt != 700 || progress.Difference != -2000 {
+		t.Errorf("GetProgress() = actual %v, new %v, difference %v, want 16000, 700 and -2000", progress.ActualBalance, progress.NewDebt, progress.Difference)
+	}
+	for _, debt := range progress.Debts {
+		if debt.PaidOff != (debt.DebtID == f.master.ID) {
+			t.Errorf("GetProgress() debt %s paid off = %v", debt.Name, debt.PaidOff)
+		}
+	}
+
+	// Everything in the plan gets paid
+	f.visa.Balance = 0
+	f.plan.Status = entities.InstallmentPlanStatusCompleted
+
+	progress, err = f.service.GetProgress(plan.ID, f.userID)
+	if err != nil {
+		t.Fatalf("GetProgress() unexpected error: %v", err)
+	}
+	if progress.Status != entities.DebtPayoffPlanStatusCompleted || progress.ProgressPercent != 100 || plan.CompletedAt == nil {
+		t.Errorf("GetProgress() after paying everything = %v at %v%%, want completed", progress.Status, progress.ProgressPercent)
+	}
+
+	if _, err := f.service.AbandonPlan(plan.ID, f.userID); !errors.IsValidationError(err) {
+		t.Errorf("AbandonPlan() on a completed plan error = %v, want validation error", err)
+	}
+	plans, _ := f.service.GetPlans(f.userID, string(entities.DebtPayoffPlanStatusAbandoned))
+	if len(plans) != 1 || plans[0].ID != first.ID {
+		t.Errorf("GetPlans() abandoned = %d plans, want the first one", len(plans))
+	}
+}
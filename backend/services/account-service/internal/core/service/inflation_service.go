@@ -0,0 +1,365 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+)
+
+// breakEvenMaxMonthlyRate is the highest monthly inflation, as a fraction, the break-even search tries
+const breakEvenMaxMonthlyRate = 1.0
+
+// breakEvenIterations is the number of bisection steps, enough for a rate accurate to far below 0.01%
+const breakEvenIterations = 60
+
+var inflationSeriesPattern = regexp.MustCompile(`^[a-z0-9_-]{1,30}$`)
+
+// InflationService compares paying a purchase in cash against paying it in installments in today's
+// money ("cuotas vs contado"), and keeps the monthly inflation series the comparison can use
+type InflationService struct {
+	inflationRepo      ports.InflationRepositoryInterface
+	installmentService ports.InstallmentServiceInterface
+	defaultMonthlyRate float64 // Percent, when the comparison gives neither a rate nor a series
+}
+
+// NewInflationService creates a new inflation service
+func NewInflationService(inflationRepo ports.InflationRepositoryInterface, installmentService ports.InstallmentServiceInterface, defaultMonthlyRate float64) *InflationService {
+	return &InflationService{
+		inflationRepo:      inflationRepo,
+		installmentService: installmentService,
+		defaultMonthlyRate: defaultMonthlyRate,
+	}
+}
+
+// CompareCashWithInstallments brings every installment of each option to today's money, discounting it
+// with the inflation of the months until it is due, and compares it with the cash price
+func (s *InflationService) CompareCashWithInstallments(options entities.CashComparisonOptions) (*entities.CashComparison, error) {
+	if err := validateCashComparison(options); err != nil {
+		return nil, err
+	}
+
+	today := dateOnly(time.Now())
+	firstDue := today.AddDate(0, 1, 0)
+	if options.FirstDueDate != nil {
+		firstDue = dateOnly(*options.FirstDueDate)
+		if firstDue.Before(today) {
+			return nil, errors.NewValidationError("first_due_date", "first due date cannot be in the past")
+		}
+	}
+
+	maxInstallments := 0
+	for _, option := range options.Installments {
+		if option.InstallmentsCount > maxInstallments {
+			maxInstallments = option.InstallmentsCount
+		}
+	}
+	startMonth := monthStart(today)
+	months := monthsBetween(startMonth, monthStart(firstDue.AddDate(0, maxInstallments-1, 0))) + 1
+
+	comparison := &entities.CashComparison{
+		Price:        options.Price,
+		CashDiscount: options.CashDiscount,
+		CashPrice:    roundCents(options.Price * (1 - options.CashDiscount/100)),
+	}
+	rates, err := s.monthlyInflation(options, comparison, startMonth, months)
+	if err != nil {
+		return nil, err
+	}
+	comparison.MonthlyInflation = rates
+
+	seriesRate := func(month int) float64 {
+		return rates[month].Rate / 100
+	}
+
+	comparison.Options = append(comparison.Options, entities.PaymentOption{
+		Name:         string(entities.PaymentOptionCash),
+		Type:         entities.PaymentOptionCash,
+		TotalToPay:   comparison.CashPrice,
+		PresentValue: comparison.CashPrice,
+		Payments: []entities.DiscountedPayment{{
+			Number:         1,
+			DueDate:        today,
+			Amount:         comparison.CashPrice,
+			DiscountFactor: 1,
+			PresentValue:   comparison.CashPrice,
+		}},
+	})
+
+	for _, installments := range options.Installments {
+		preview, err := s.installmentService.CalculateInstallmentPlan(options.Price, installments.InstallmentsCount, firstDue, installments.InterestRate)
+		if err != nil {
+			return nil, errors.NewValidationError("installments", err.Error())
+		}
+
+		option := entities.PaymentOption{
+			Name:              installmentOptionName(installments),
+			Type:              entities.PaymentOptionInstallments,
+			InstallmentsCount: installments.InstallmentsCount,
+			InterestRate:      installments.InterestRate,
+			InstallmentAmount: roundCents(preview.InstallmentAmount),
+			TotalToPay:        roundCents(preview.TotalAmount),
+			Payments:          installmentPayments(preview.InstallmentAmount, preview.TotalAmount, installments.InstallmentsCount, firstDue),
+		}
+
+		presentValue := 0.0
+		for i := range option.Payments {
+			payment := &option.Payments[i]
+			factor := discountFactor(today, payment.DueDate, seriesRate)
+			payment.DiscountFactor = math.Round(factor*10000) / 10000
+			payment.PresentValue = roundCents(payment.Amount / factor)
+			presentValue += payment.Amount / factor
+		}
+		option.PresentValue = roundCents(presentValue)
+		option.DifferenceVsCash = roundCents(option.PresentValue - comparison.CashPrice)
+		option.BreakEvenInflation = breakEvenInflation(today, option.Payments, comparison.CashPrice)
+
+		comparison.Options = append(comparison.Options, option)
+	}
+
+	// Cash wins ties: it is paid once and carries no risk
+	best := 0
+	for i, option := range comparison.Options {
+		if option.PresentValue < comparison.Options[best].PresentValue {
+			best = i
+		}
+	}
+	comparison.Options[best].Best = true
+	comparison.BestOption = comparison.Options[best].Name
+	comparison.SavingVsCash = roundCents(comparison.CashPrice - comparison.Options[best].PresentValue)
+
+	return comparison, nil
+}
+
+// GetSeries gets the monthly rates of a stored inflation series, oldest first
+func (s *InflationService) GetSeries(series string) ([]*entities.InflationRate, error) {
+	series, err := normalizeInflationSeries(series)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := s.inflationRepo.GetSeries(series)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("inflation series not found")
+	}
+	return rates, nil
+}
+
+// SaveSeries stores the monthly rates of an inflation series, replacing the months already stored
+func (s *InflationService) SaveSeries(series string, rates []entities.InflationRate, updatedBy string) ([]*entities.InflationRate, error) {
+	series, err := normalizeInflationSeries(series)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, errors.NewValidationError("rates", "at least one monthly rate is required")
+	}
+
+	periods := make(map[time.Time]bool, len(rates))
+	toSave := make([]*entities.InflationRate, 0, len(rates))
+	for _, rate := range rates {
+		period := monthStart(rate.Period)
+		if periods[period] {
+			return nil, errors.NewValidationError("rates", fmt.Sprintf("period %s is repeated", period.Format("2006-01")))
+		}
+		if rate.MonthlyRate <= -100 {
+			return nil, errors.NewValidationError("monthly_rate", "monthly rate must be greater than -100")
+		}
+		periods[period] = true
+
+		toSave = append(toSave, &entities.InflationRate{
+			Series:      series,
+			Period:      period,
+			MonthlyRate: rate.MonthlyRate,
+			UpdatedBy:   updatedBy,
+		})
+	}
+
+	if err := s.inflationRepo.SaveRates(toSave); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("📈 Inflation series %s updated with %d months by %s\n", series, len(toSave), updatedBy)
+	return s.inflationRepo.GetSeries(series)
+}
+
+// monthlyInflation gets the inflation of each month from the start month on: the rate of the
+// comparison, the stored series or the configured expectation
+func (s *InflationService) monthlyInflation(options entities.CashComparisonOptions, comparison *entities.CashComparison, start time.Time, months int) ([]entities.MonthlyInflation, error) {
+	rates := make([]entities.MonthlyInflation, months)
+	for i := range rates {
+		rates[i].Period = start.AddDate(0, i, 0)
+	}
+
+	if options.InflationSeries == "" {
+		rate := s.defaultMonthlyRate
+		comparison.InflationSource = entities.InflationSourceDefault
+		if options.MonthlyInflation != nil {
+			rate = *options.MonthlyInflation
+			comparison.InflationSource = entities.InflationSourceExpectation
+		}
+		for i := range rates {
+			rates[i].Rate = rate
+		}
+		return rates, nil
+	}
+
+	series, err := normalizeInflationSeries(options.InflationSeries)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.inflationRepo.GetSeriesSince(series, start)
+	if err != nil {
+		return nil, err
+	}
+	if len(stored) == 0 {
+		return nil, errors.NewValidationError("inflation_series", fmt.Sprintf("inflation series %s has no rates", series))
+	}
+	comparison.InflationSource = entities.InflationSourceSeries
+	comparison.InflationSeries = series
+
+	// Months the series does not reach keep its last known rate; months before it starts take its first one
+	next := 0
+	current := stored[0].MonthlyRate
+	for i := range rates {
+		for next < len(stored) && !monthStart(stored[next].Period).After(rates[i].Period) {
+			current = stored[next].MonthlyRate
+			next++
+		}
+		rates[i].Rate = current
+	}
+	return rates, nil
+}
+
+func validateCashComparison(options entities.CashComparisonOptions) error {
+	if options.Price <= 0 {
+		return errors.NewValidationError("price", "price must be greater than 0")
+	}
+	if options.CashDiscount < 0 || options.CashDiscount >= 100 {
+		return errors.NewValidationError("cash_discount", "cash discount must be between 0 and 100")
+	}
+	if len(options.Installments) == 0 {
+		return errors.NewValidationError("installments", "at least one installment option is required")
+	}
+	for _, option := range options.Installments {
+		if option.InstallmentsCount <= 0 {
+			return errors.NewValidationError("installments_count", "number of installments must be greater than 0")
+		}
+		if option.InterestRate < 0 {
+			return errors.NewValidationError("interest_rate", "interest rate cannot be negative")
+		}
+	}
+	if options.MonthlyInflation != nil && options.InflationSeries != "" {
+		return errors.NewValidationError("monthly_inflation", "use either a monthly inflation or an inflation series, not both")
+	}
+	if options.MonthlyInflation != nil && *options.MonthlyInflation <= -100 {
+		return errors.NewValidationError("monthly_inflation", "monthly inflation must be greater than -100")
+	}
+	return nil
+}
+
+func normalizeInflationSeries(series string) (string, error) {
+	series = strings.ToLower(strings.TrimSpace(series))
+	if !inflationSeriesPattern.MatchString(series) {
+		return "", errors.NewValidationError("series", "series must have up to 30 lowercase letters, digits, dashes or underscores")
+	}
+	return series, nil
+}
+
+func installmentOptionName(option entities.InstallmentOption) string {
+	if option.InterestRate == 0 {
+		return fmt.Sprintf("%d installments", option.InstallmentsCount)
+	}
+	return fmt.Sprintf("%d installments at %g%%", option.InstallmentsCount, option.InterestRate)
+}
+
+// installmentPayments splits the total in monthly installments from the first due date, the last
+// one taking the rounding difference
+func installmentPayments(installmentAmount, totalAmount float64, count int, firstDue time.Time) []entities.DiscountedPayment {
+	amount := roundCents(installmentAmount)
+	payments := make([]entities.DiscountedPayment, count)
+	for i := range payments {
+		payments[i] = entities.DiscountedPayment{
+			Number:  i + 1,
+			DueDate: firstDue.AddDate(0, i, 0),
+			Amount:  amount,
+		}
+	}
+	payments[count-1].Amount = roundCents(totalAmount - amount*float64(count-1))
+	return payments
+}
+
+// discountFactor is how much prices grow from one day to another. Each calendar month adds its
+// inflation in proportion to the days of it that elapse; rateFor gets the rate, as a fraction, of
+// the month that many months after the month of from.
+func discountFactor(from, to time.Time, rateFor func(month int) float64) float64 {
+	factor := 1.0
+	start := monthStart(from)
+	for cursor := from; cursor.Before(to); {
+		month := monthStart(cursor)
+		next := month.AddDate(0, 1, 0)
+		end := next
+		if to.Before(end) {
+			end = to
+		}
+		portion := end.Sub(cursor).Hours() / next.Sub(month).Hours()
+		factor *= math.Pow(1+rateFor(monthsBetween(start, month)), portion)
+		cursor = end
+	}
+	return factor
+}
+
+// breakEvenInflation finds the constant monthly inflation, in percent, that makes the payments
+// worth the cash price today. It is nil when the payments beat cash even without inflation or
+// never do within the search range.
+func breakEvenInflation(today time.Time, payments []entities.DiscountedPayment, cashPrice float64) *float64 {
+	presentValue := func(rate float64) float64 {
+		total := 0.0
+		for _, payment := range payments {
+			total += payment.Amount / discountFactor(today, payment.DueDate, func(int) float64 { return rate })
+		}
+		return total
+	}
+
+	withoutInflation := roundCents(presentValue(0))
+	if withoutInflation < cashPrice || presentValue(breakEvenMaxMonthlyRate) > cashPrice {
+		return nil
+	}
+	if withoutInflation == cashPrice {
+		zero := 0.0
+		return &zero
+	}
+
+	low, high := 0.0, breakEvenMaxMonthlyRate
+	for i := 0; i < breakEvenIterations; i++ {
+		mid := (low + high) / 2
+		if presentValue(mid) > cashPrice {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	rate := roundCents(high * 100)
+	return &rate
+}
+
+// dateOnly drops the time of day, in UTC so that day counts are not affected by daylight saving
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
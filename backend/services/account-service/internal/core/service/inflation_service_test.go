@@ -0,0 +1,277 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+)
+
+// MockInflationRepository implements a mock inflation repository for testing
+type MockInflationRepository struct {
+	rates map[string][]*entities.InflationRate
+}
+
+func NewMockInflationRepository() *MockInflationRepository {
+	return &MockInflationRepository{rates: make(map[string][]*entities.InflationRate)}
+}
+
+func (m *MockInflationRepository) GetSeries(series string) ([]*entities.InflationRate, error) {
+	return m.rates[series], nil
+}
+
+func (m *MockInflationRepository) GetSeriesSince(series string, from time.Time) ([]*entities.InflationRate, error) {
+	var rates []*entities.InflationRate
+	for _, rate := range m.rates[series] {
+		if rate.Period.Before(from) {
+			rates = []*entities.InflationRate{rate}
+			continue
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func (m *MockInflationRepository) SaveRates(rates []*entities.InflationRate) error {
+	for _, rate := range rates {
+		replaced := false
+		for i, existing := range m.rates[rate.Series] {
+			if existing.Period.Equal(rate.Period) {
+				m.rates[rate.Series][i] = rate
+				replaced = true
+			}
+		}
+		if !replaced {
+			m.rates[rate.Series] = append(m.rates[rate.Series], rate)
+		}
+		sort.Slice(m.rates[rate.Series], func(i, j int) bool {
+			return m.rates[rate.Series][i].Period.Before(m.rates[rate.Series][j].Period)
+		})
+	}
+	return nil
+}
+
+// Verify interface compliance
+var _ ports.InflationRepositoryInterface = (*MockInflationRepository)(nil)
+
+func setupInflationService() (*InflationService, *MockInflationRepository) {
+	repo := NewMockInflationRepository()
+	// The calculator only uses the installment plan math, which needs no repositories
+	return NewInflationService(repo, &InstallmentService{}, 2.5), repo
+}
+
+func monthlyInflation(rate float64) *float64 {
+	return &rate
+}
+
+func findPaymentOption(comparison *entities.CashComparison, name string) *entities.PaymentOption {
+	for i := range comparison.Options {
+		if comparison.Options[i].Name == name {
+			return &comparison.Options[i]
+		}
+	}
+	return nil
+}
+
+func TestCompareCashWithInstallments(t *testing.T) {
+	t.Run("installments without interest beat cash with inflation", func(t *testing.T) {
+		svc, _ := setupInflationService()
+
+		comparison, err := svc.CompareCashWithInstallments(entities.CashComparisonOptions{
+			Price:            120000,
+			Installments:     []entities.InstallmentOption{{InstallmentsCount: 12}},
+			MonthlyInflation: monthlyInflation(3),
+		})
+		if err != nil {
+			t.Fatalf("CompareCashWithInstallments() unexpected error: %v", err)
+		}
+		if comparison.InflationSource != entities.InflationSourceExpectation || comparison.CashPrice != 120000 {
+			t.Errorf("CompareCashWithInstallments() = %v at %v, want expectation at 120000", comparison.InflationSource, comparison.CashPrice)
+		}
+
+		option := findPaymentOption(comparison, "12 installments")
+		if option == nil {
+			t.Fatalf("CompareCashWithInstallments() options = %+v, want 12 installments", comparison.Options)
+		}
+		if option.TotalToPay != 120000 || option.InstallmentAmount != 10000 || len(option.Payments) != 12 {
+			t.Errorf("CompareCashWithInstallments() installments = %d of %v totalling %v, want 12 of 10000", len(option.Payments), option.InstallmentAmount, option.TotalToPay)
+		}
+		// Twelve payments of 10000 discounted at 3% a month, the first one a month away
+		if option.PresentValue < 99000 || option.PresentValue > 100500 {
+			t.Errorf("CompareCashWithInstallments() present value = %v, want about 99540", option.PresentValue)
+		}
+		if !option.Best || comparison.BestOption != option.Name || comparison.SavingVsCash != -option.DifferenceVsCash {
+			t.Errorf("CompareCashWithInstallments() best = %s saving %v, want %s saving %v", comparison.BestOption, comparison.SavingVsCash, option.Name, -option.DifferenceVsCash)
+		}
+		if option.BreakEvenInflation == nil || *option.BreakEvenInflation != 0 {
+			t.Errorf("CompareCashWithInstallments() break-even = %v, want 0", option.BreakEvenInflation)
+		}
+		for i := 1; i < len(option.Payments); i++ {
+			if option.Payments[i].PresentValue >= option.Payments[i-1].PresentValue {
+				t.Errorf("CompareCashWithInstallments() installment %d worth %v, not less than the previous one", i+1, option.Payments[i].PresentValue)
+			}
+		}
+	})
+
+	t.Run("cash discount beats installments with low inflation", func(t *testing.T) {
+		svc, _ := setupInflationService()
+		options := entities.CashComparisonOptions{
+			Price:            100000,
+			CashDiscount:     15,
+			Installments:     []entities.InstallmentOption{{InstallmentsCount: 6}, {InstallmentsCount: 12, InterestRate: 20}},
+			MonthlyInflation: monthlyInflation(1),
+		}
+
+		comparison, err := svc.CompareCashWithInstallments(options)
+		if err != nil {
+			t.Fatalf("CompareCashWithInstallments() unexpected error: %v", err)
+		}
+		if comparison.CashPrice != 85000 || comparison.BestOption != "cash" || comparison.SavingVsCash != 0 || !comparison.Options[0].Best {
+			t.Fatalf("CompareCashWithInstallments() best = %s at %v, want cash at 85000", comparison.BestOption, comparison.CashPrice)
+		}
+
+		withInterest := findPaymentOption(comparison, "12 installments at 20%")
+		if withInterest == nil || withInterest.TotalToPay != 120000 || withInterest.DifferenceVsCash <= 0 {
+			t.Fatalf("CompareCashWithInstallments() options = %+v, want 12 installments at 20%% totalling 120000", comparison.Options)
+		}
+
+		sixInstallments := findPaymentOption(comparison, "6 installments")
+		if sixInstallments == nil || sixInstallments.BreakEvenInflation == nil || *sixInstallments.BreakEvenInflation <= 1 {
+			t.Fatalf("CompareCashWithInstallments() 6 installments = %+v, want a break-even above 1%%", sixInstallments)
+		}
+
+		// Above the break-even inflation the installments win
+		options.MonthlyInflation = monthlyInflation(*sixInstallments.BreakEvenInflation + 0.1)
+		comparison, err = svc.CompareCashWithInstallments(options)
+		if err != nil {
+			t.Fatalf("CompareCashWithInstallments() unexpected error: %v", err)
+		}
+		if comparison.BestOption != "6 installments" {
+			t.Errorf("CompareCashWithInstallments() best = %s above the break-even, want 6 installments", comparison.BestOption)
+		}
+	})
+
+	t.Run("stored series carries its last rate", func(t *testing.T) {
+		svc, repo := setupInflationService()
+		start := monthStart(time.Now())
+		repo.rates["indec-cpi"] = []*entities.InflationRate{
+			{Series: "indec-cpi", Period: start.AddDate(0, -1, 0), MonthlyRate: 9},
+			{Series: "indec-cpi", Period: start, MonthlyRate: 4},
+			{Series: "indec-cpi", Period: start.AddDate(0, 1, 0), MonthlyRate: 3},
+		}
+
+		comparison, err := svc.CompareCashWithInstallments(entities.CashComparisonOptions{
+			Price:           60000,
+			Installments:    []entities.InstallmentOption{{InstallmentsCount: 3}},
+			InflationSeries: "INDEC-CPI",
+		})
+		if err != nil {
+			t.Fatalf("CompareCashWithInstallments() unexpected error: %v", err)
+		}
+		if comparison.InflationSource != entities.InflationSourceSeries || comparison.InflationSeries != "indec-cpi" {
+			t.Errorf("CompareCashWithInstallments() source = %v %q, want series indec-cpi", comparison.InflationSource, comparison.InflationSeries)
+		}
+
+		want := []float64{4, 3, 3, 3}
+		if len(comparison.MonthlyInflation) != len(want) {
+			t.Fatalf("CompareCashWithInstallments() monthly inflation = %+v, want %v", comparison.MonthlyInflation, want)
+		}
+		for i, rate := range comparison.MonthlyInflation {
+			if rate.Rate != want[i] || !rate.Period.Equal(start.AddDate(0, i, 0)) {
+				t.Errorf("CompareCashWithInstallments() month %d = %v at %v, want %v", i, rate.Rate, rate.Period, want[i])
+			}
+		}
+	})
+
+	t.Run("configured expectation by default", func(t *testing.T) {
+		svc, _ := setupInflationService()
+
+		comparison, err := svc.CompareCashWithInstallments(entities.CashComparisonOptions{
+			Price:        50000,
+			Installments: []entities.InstallmentOption{{InstallmentsCount: 3}},
+		})
+		if err != nil {
+			t.Fatalf("CompareCashWithInstallments() unexpected error: %v", err)
+		}
+		if comparison.InflationSource != entities.InflationSourceDefault || comparison.MonthlyInflation[0].Rate != 2.5 {
+			t.Errorf("CompareCashWithInstallments() source = %v at %v, want default at 2.5", comparison.InflationSource, comparison.MonthlyInflation[0].Rate)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		svc, _ := setupInflationService()
+		past := time.Now().AddDate(0, 0, -2)
+
+		tests := []struct {
+			name    string
+			options entities.CashComparisonOptions
+		}{
+			{"no installment options", entities.CashComparisonOptions{Price: 1000}},
+			{"full cash discount", entities.CashComparisonOptions{Price: 1000, CashDiscount: 100, Installments: []entities.InstallmentOption{{InstallmentsCount: 3}}}},
+			{"rate and series", entities.CashComparisonOptions{Price: 1000, Installments: []entities.InstallmentOption{{InstallmentsCount: 3}}, MonthlyInflation: monthlyInflation(2), InflationSeries: "indec-cpi"}},
+			{"series without rates", entities.CashComparisonOptions{Price: 1000, Installments: []entities.InstallmentOption{{InstallmentsCount: 3}}, InflationSeries: "indec-cpi"}},
+			{"first due date in the past", entities.CashComparisonOptions{Price: 1000, Installments: []entities.InstallmentOption{{InstallmentsCount: 3}}, FirstDueDate: &past}},
+		}
+		for _, tt := range tests {
+			if _, err := svc.CompareCashWithInstallments(tt.options); !errors.IsValidationError(err) {
+				t.Errorf("CompareCashWithInstallments() %s error = %v, want validation error", tt.name, err)
+			}
+		}
+	})
+}
+
+func TestDiscountFactor(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rates := func(month int) float64 { return []float64{0.02, 0.04}[month] }
+
+	if factor := discountFactor(from, from.AddDate(0, 1, 0), rates); math.Abs(factor-1.02) > 1e-9 {
+		t.Errorf("discountFactor() whole month = %v, want 1.02", factor)
+	}
+	if factor := discountFactor(from, from.AddDate(0, 2, 0), rates); math.Abs(factor-1.02*1.04) > 1e-9 {
+		t.Errorf("discountFactor() two months = %v, want %v", factor, 1.02*1.04)
+	}
+	// Half of February takes half of its inflation
+	want := 1.02 * math.Pow(1.04, 0.5)
+	if factor := discountFactor(from, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), rates); math.Abs(factor-want) > 1e-9 {
+		t.Errorf("discountFactor() month and a half = %v, want %v", factor, want)
+	}
+}
+
+func TestSaveInflationSeries(t *testing.T) {
+	svc, _ := setupInflationService()
+
+	rates := []entities.InflationRate{
+		{Period: time.Date(2026, 2, 17, 0, 0, 0, 0, time.UTC), MonthlyRate: 2.4},
+		{Period: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), MonthlyRate: 2.2},
+	}
+	saved, err := svc.SaveSeries(" REM ", rates, "admin-1")
+	if err != nil {
+		t.Fatalf("SaveSeries() unexpected error: %v", err)
+	}
+	if len(saved) != 2 || saved[0].Series != "rem" || saved[0].MonthlyRate != 2.2 || saved[1].Period.Day() != 1 || saved[1].UpdatedBy != "admin-1" {
+		t.Errorf("SaveSeries() = %+v, want January and February of rem", saved)
+	}
+
+	// Saving a month again replaces it
+	if _, err := svc.SaveSeries("rem", []entities.InflationRate{{Period: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), MonthlyRate: 2.6}}, "admin-1"); err != nil {
+		t.Fatalf("SaveSeries() unexpected error: %v", err)
+	}
+	series, err := svc.GetSeries("rem")
+	if err != nil || len(series) != 2 || series[1].MonthlyRate != 2.6 {
+		t.Errorf("GetSeries() = %+v, %v, want February replaced with 2.6", series, err)
+	}
+
+	if _, err := svc.SaveSeries("rem", append(rates, rates[0]), "admin-1"); !errors.IsValidationError(err) {
+		t.Errorf("SaveSeries() repeated period error = %v, want validation error", err)
+	}
+	if _, err := svc.SaveSeries("cpi/ba", rates, "admin-1"); !errors.IsValidationError(err) {
+		t.Errorf("SaveSeries() invalid name error = %v, want validation error", err)
+	}
+	if _, err := svc.GetSeries("unknown"); err == nil {
+		t.Error("GetSeries() unknown series expected error, got nil")
+	}
+}
@@ -17,16 +17,18 @@ type InstallmentService struct {
 	installmentAuditRepo ports.InstallmentPlanAuditRepositoryInterface
 	cardRepo             ports.CardRepositoryInterface
 	accountRepo          ports.AccountRepositoryInterface // Mantenemos para validaciones básicas
+	membershipService    ports.MembershipServiceInterface // Permisos de miembros en cuentas compartidas
 	transactionClient    *clients.TransactionClient
 }
 
-func NewInstallmentService(installmentRepo ports.InstallmentRepositoryInterface, installmentPlanRepo ports.InstallmentPlanRepositoryInterface, installmentAuditRepo ports.InstallmentPlanAuditRepositoryInterface, cardRepo ports.CardRepositoryInterface, accountRepo ports.AccountRepositoryInterface) *InstallmentService {
+func NewInstallmentService(installmentRepo ports.InstallmentRepositoryInterface, installmentPlanRepo ports.InstallmentPlanRepositoryInterface, installmentAuditRepo ports.InstallmentPlanAuditRepositoryInterface, cardRepo ports.CardRepositoryInterface, accountRepo ports.AccountRepositoryInterface, membershipService ports.MembershipServiceInterface) *InstallmentService {
 	return &InstallmentService{
 		installmentRepo:      installmentRepo,
 		installmentPlanRepo:  installmentPlanRepo,
 		installmentAuditRepo: installmentAuditRepo,
 		cardRepo:             cardRepo,
 		accountRepo:          accountRepo, // Mantenemos para validaciones básicas
+		membershipService:    membershipService,
 		transactionClient:    clients.NewTransactionClient(),
 	}
 }
@@ -140,8 +142,10 @@ func (s *InstallmentService) CreateInstallmentPlan(req *carddto.CreateInstallmen
 			return
 		}
 
+		// La transacción queda a nombre del titular de la cuenta; el miembro que compró es el iniciador
 		_, err = s.transactionClient.CreateInstallmentTransaction(
-			req.UserID,
+			cardWithAccount.Account.UserID,
+			req.InitiatedBy,
 			cardWithAccount.Account.ID,
 			req.CardID,
 			req.TotalAmount,
@@ -195,8 +199,12 @@ func (s *InstallmentService) PayInstallment(req *carddto.PayInstallmentRequest)
 		return nil, fmt.Errorf("payment account not found: %w", err)
 	}
 
-	// Verificar que la cuenta pertenece al usuario
-	if paymentAccount.UserID != req.UserID {
+	// Verificar que la cuenta pertenece al usuario o que es miembro con permiso para operarla
+	canOperate, err := s.membershipService.CanOperateAccount(paymentAccount, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify payment account access: %w", err)
+	}
+	if !canOperate {
 		return nil, fmt.Errorf("payment account does not belong to user")
 	}
 
@@ -236,8 +244,9 @@ func (s *InstallmentService) PayInstallment(req *carddto.PayInstallmentRequest)
 		},
 	}
 
-	// Llamar al transaction-service para procesar el pago
-	_, err = s.transactionClient.CreateTransaction(req.UserID, transactionReq)
+	// Llamar al transaction-service para procesar el pago a nombre del titular de la cuenta
+	transactionReq.InitiatedBy = req.UserID
+	_, err = s.transactionClient.CreateTransaction(paymentAccount.UserID, transactionReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process payment transaction: %w", err)
 	}
@@ -384,6 +393,20 @@ func (s *InstallmentService) GetInstallmentPlansByUser(userID string, status str
 	return s.installmentPlanRepo.GetByUser(userID, status, pageSize, offset)
 }
 
+// CanAccessInstallmentPlan verifica si el usuario puede ver un plan: quien lo creó o un miembro con acceso a la tarjeta
+func (s *InstallmentService) CanAccessInstallmentPlan(plan *entities.InstallmentPlan, userID string) (bool, error) {
+	if plan.UserID == userID {
+		return true, nil
+	}
+
+	card, err := s.cardRepo.GetByIDWithAccount(plan.CardID)
+	if err != nil {
+		return false, fmt.Errorf("card not found: %w", err)
+	}
+
+	return s.membershipService.CanViewCard(card, userID)
+}
+
 // CancelInstallmentPlan cancela un plan de cuotas activo
 func (s *InstallmentService) CancelInstallmentPlan(planID, reason string, cancelledBy string) (*entities.InstallmentPlan, error) {
 	// Obtener el plan
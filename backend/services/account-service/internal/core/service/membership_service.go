@@ -0,0 +1,319 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+)
+
+// MembershipService provides business logic for shared accounts
+type MembershipService struct {
+	memberRepo  ports.AccountMemberRepositoryInterface
+	accountRepo ports.AccountRepositoryInterface
+	cardRepo    ports.CardRepositoryInterface
+}
+
+// NewMembershipService creates a new membership service instance
+func NewMembershipService(memberRepo ports.AccountMemberRepositoryInterface, accountRepo ports.AccountRepositoryInterface, cardRepo ports.CardRepositoryInterface) *MembershipService {
+	return &MembershipService{
+		memberRepo:  memberRepo,
+		accountRepo: accountRepo,
+		cardRepo:    cardRepo,
+	}
+}
+
+// InviteMember invites a user to an account with the given role
+func (s *MembershipService) InviteMember(accountID, invitedBy, userID string, role entities.MemberRole, cardID *string) (*entities.AccountMember, error) {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+
+	canManage, err := s.canManageMembers(account, invitedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, errors.ErrInsufficientRights
+	}
+
+	if userID == account.UserID {
+		return nil, errors.NewValidationError("user_id", "user already owns this account")
+	}
+
+	existing, err := s.memberRepo.GetByAccountAndUser(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.NewValidationError("user_id", "user is already a member of this account")
+	}
+
+	member := &entities.AccountMember{
+		AccountID: accountID,
+		UserID:    userID,
+		Role:      role,
+		Status:    entities.MemberStatusPending,
+		CardID:    cardID,
+		InvitedBy: invitedBy,
+	}
+	if err := s.validateMember(member); err != nil {
+		return nil, err
+	}
+
+	return s.memberRepo.Create(member)
+}
+
+// AcceptInvitation accepts a pending invitation addressed to the user
+func (s *MembershipService) AcceptInvitation(memberID, userID string) (*entities.AccountMember, error) {
+	member, err := s.getInvitationForUser(memberID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := member.Accept(); err != nil {
+		return nil, errors.NewValidationError("status", err.Error())
+	}
+
+	return s.memberRepo.Update(member)
+}
+
+// DeclineInvitation declines a pending invitation addressed to the user
+func (s *MembershipService) DeclineInvitation(memberID, userID string) (*entities.AccountMember, error) {
+	member, err := s.getInvitationForUser(memberID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := member.Decline(); err != nil {
+		return nil, errors.NewValidationError("status", err.Error())
+	}
+
+	return s.memberRepo.Update(member)
+}
+
+// GetPendingInvitations retrieves the invitations waiting for the user's answer
+func (s *MembershipService) GetPendingInvitations(userID string) ([]*entities.AccountMember, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+	return s.memberRepo.GetPendingByUser(userID)
+}
+
+// GetAccountMembers retrieves the members of an account visible to the requester
+func (s *MembershipService) GetAccountMembers(accountID, requestedBy string) ([]*entities.AccountMember, error) {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+
+	canView, err := s.CanViewAccount(account, requestedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, errors.ErrInsufficientRights
+	}
+
+	return s.memberRepo.GetByAccount(accountID)
+}
+
+// UpdateMemberRole changes the role (and assigned card) of a member
+func (s *MembershipService) UpdateMemberRole(accountID, memberID, updatedBy string, role entities.MemberRole, cardID *string) (*entities.AccountMember, error) {
+	account, member, err := s.getManagedMember(accountID, memberID, updatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.UserID == updatedBy && account.UserID != updatedBy {
+		return nil, errors.NewValidationError("role", "members cannot change their own role")
+	}
+
+	member.Role = role
+	member.CardID = cardID
+	if err := s.validateMember(member); err != nil {
+		return nil, err
+	}
+
+	return s.memberRepo.Update(member)
+}
+
+// RemoveMember revokes a membership. Members can always remove themselves.
+func (s *MembershipService) RemoveMember(accountID, memberID, removedBy string) error {
+	member, err := s.memberRepo.GetByID(memberID)
+	if err != nil {
+		return err
+	}
+	if member.AccountID != accountID {
+		return fmt.Errorf("account member not found")
+	}
+
+	if member.UserID != removedBy {
+		if _, _, err := s.getManagedMember(accountID, memberID, removedBy); err != nil {
+			return err
+		}
+	}
+
+	if err := member.Revoke(); err != nil {
+		return errors.NewValidationError("status", err.Error())
+	}
+
+	_, err = s.memberRepo.Update(member)
+	return err
+}
+
+// CanViewAccount checks if the user owns the account or has an active membership in it
+func (s *MembershipService) CanViewAccount(account *entities.Account, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	if account.UserID == userID {
+		return true, nil
+	}
+
+	member, err := s.memberRepo.GetByAccountAndUser(account.ID, userID)
+	if err != nil {
+		return false, err
+	}
+	return member != nil && member.IsActive(), nil
+}
+
+// CanOperateAccount checks if the user can move money from the account
+func (s *MembershipService) CanOperateAccount(account *entities.Account, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	if account.UserID == userID {
+		return true, nil
+	}
+
+	member, err := s.memberRepo.GetByAccountAndUser(account.ID, userID)
+	if err != nil {
+		return false, err
+	}
+	return member != nil && member.CanOperateAccount(), nil
+}
+
+// CanViewCard checks if the user can see the card and its installment plans
+func (s *MembershipService) CanViewCard(card *entities.Card, userID string) (bool, error) {
+	member, isOwner, err := s.getCardAccess(card, userID)
+	if err != nil || isOwner {
+		return isOwner, err
+	}
+	if member == nil || !member.IsActive() {
+		return false, nil
+	}
+	return member.Role != entities.MemberRoleCardHolder || member.CanUseCard(card.ID), nil
+}
+
+// CanUseCard checks if the user can charge, pay or otherwise operate the card
+func (s *MembershipService) CanUseCard(card *entities.Card, userID string) (bool, error) {
+	member, isOwner, err := s.getCardAccess(card, userID)
+	if err != nil || isOwner {
+		return isOwner, err
+	}
+	return member != nil && member.CanUseCard(card.ID), nil
+}
+
+// getCardAccess resolves whether the user owns the card's account or else their membership in it
+func (s *MembershipService) getCardAccess(card *entities.Card, userID string) (*entities.AccountMember, bool, error) {
+	if userID == "" {
+		return nil, false, nil
+	}
+
+	account := &card.Account
+	if account.ID == "" {
+		var err error
+		account, err = s.accountRepo.GetByID(card.AccountID)
+		if err != nil {
+			return nil, false, fmt.Errorf("account not found: %w", err)
+		}
+	}
+	if account.UserID == userID {
+		return nil, true, nil
+	}
+
+	member, err := s.memberRepo.GetByAccountAndUser(account.ID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	return member, false, nil
+}
+
+// canManageMembers checks if the user can invite, update or remove members of the account
+func (s *MembershipService) canManageMembers(account *entities.Account, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	if account.UserID == userID {
+		return true, nil
+	}
+
+	member, err := s.memberRepo.GetByAccountAndUser(account.ID, userID)
+	if err != nil {
+		return false, err
+	}
+	return member != nil && member.CanManageMembers(), nil
+}
+
+// getManagedMember loads a membership of the account verifying the user can manage it
+func (s *MembershipService) getManagedMember(accountID, memberID, userID string) (*entities.Account, *entities.AccountMember, error) {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("account not found: %w", err)
+	}
+
+	canManage, err := s.canManageMembers(account, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !canManage {
+		return nil, nil, errors.ErrInsufficientRights
+	}
+
+	member, err := s.memberRepo.GetByID(memberID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if member.AccountID != accountID {
+		return nil, nil, fmt.Errorf("account member not found")
+	}
+
+	return account, member, nil
+}
+
+// getInvitationForUser loads an invitation verifying it is addressed to the user
+func (s *MembershipService) getInvitationForUser(memberID, userID string) (*entities.AccountMember, error) {
+	member, err := s.memberRepo.GetByID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member.UserID != userID {
+		return nil, errors.ErrUnauthorized
+	}
+	return member, nil
+}
+
+// validateMember validates the membership data and that the assigned card belongs to the account
+func (s *MembershipService) validateMember(member *entities.AccountMember) error {
+	if err := member.Validate(); err != nil {
+		if validationErr, ok := err.(*entities.ValidationError); ok {
+			return errors.NewValidationError(validationErr.Field, validationErr.Message)
+		}
+		return err
+	}
+
+	if member.CardID != nil {
+		card, err := s.cardRepo.GetByID(*member.CardID)
+		if err != nil {
+			return fmt.Errorf("card not found: %w", err)
+		}
+		if card.AccountID != member.AccountID {
+			return errors.NewValidationError("card_id", "card does not belong to this account")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// MockAccountMemberRepository implements a mock membership repository for testing
+type MockAccountMemberRepository struct {
+	members map[string]*entities.AccountMember
+}
+
+func NewMockAccountMemberRepository() *MockAccountMemberRepository {
+	return &MockAccountMemberRepository{
+		members: make(map[string]*entities.AccountMember),
+	}
+}
+
+func (m *MockAccountMemberRepository) Create(member *entities.AccountMember) (*entities.AccountMember, error) {
+	if member.ID == "" {
+		member.ID = uuid.NewString()
+	}
+	m.members[member.ID] = member
+	return member, nil
+}
+
+func (m *MockAccountMemberRepository) GetByID(memberID string) (*entities.AccountMember, error) {
+	member, exists := m.members[memberID]
+	if !exists {
+		return nil, fmt.Errorf("account member not found")
+	}
+	return member, nil
+}
+
+func (m *MockAccountMemberRepository) GetByAccount(accountID string) ([]*entities.AccountMember, error) {
+	var members []*entities.AccountMember
+	for _, member := range m.members {
+		if member.AccountID == accountID && (member.IsPending() || member.IsActive()) {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+func (m *MockAccountMemberRepository) GetByAccountAndUser(accountID, userID string) (*entities.AccountMember, error) {
+	for _, member := range m.members {
+		if member.AccountID == accountID && member.UserID == userID && (member.IsPending() || member.IsActive()) {
+			return member, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockAccountMemberRepository) GetPendingByUser(userID string) ([]*entities.AccountMember, error) {
+	var members []*entities.AccountMember
+	for _, member := range m.members {
+		if member.UserID == userID && member.IsPending() {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+func (m *MockAccountMemberRepository) Update(member *entities.AccountMember) (*entities.AccountMember, error) {
+	m.members[member.ID] = member
+	return member, nil
+}
+
+// Verify interface compliance
+var _ ports.AccountMemberRepositoryInterface = (*MockAccountMemberRepository)(nil)
+
+// MockCardRepository implements the card lookups needed by the membership and credit line services
+type MockCardRepository struct {
+	ports.CardRepositoryInterface
+	cards map[string]*entities.Card
+}
+
+func (m *MockCardRepository) GetByID(cardID string) (*entities.Card, error) {
+	card, exists := m.cards[cardID]
+	if !exists {
+		return nil, fmt.Errorf("card not found")
+	}
+	return card, nil
+}
+
+func (m *MockCardRepository) GetByIDWithAccount(cardID string) (*entities.Card, error) {
+	return m.GetByID(cardID)
+}
+
+func (m *MockCardRepository) Update(card *entities.Card) (*entities.Card, error) {
+	m.cards[card.ID] = card
+	return card, nil
+}
+
+func setupMembershipService(t *testing.T) (*MembershipService, *entities.Account, *entities.Card) {
+	accountRepo := NewMockAccountRepository()
+	account := &entities.Account{
+		UserID:      uuid.NewString(),
+		AccountType: entities.AccountTypeBankAccount,
+		Name:        "Family Account",
+		Currency:    entities.CurrencyARS,
+		IsActive:    true,
+	}
+	if err := accountRepo.Create(account); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	card := &entities.Card{ID: uuid.NewString(), AccountID: account.ID, CardType: entities.CardTypeCredit}
+	cardRepo := &MockCardRepository{cards: map[string]*entities.Card{card.ID: card}}
+
+	return NewMembershipService(NewMockAccountMemberRepository(), accountRepo, cardRepo), account, card
+}
+
+func TestInviteMember(t *testing.T) {
+	service, account, card := setupMembershipService(t)
+	otherCardID := uuid.NewString()
+
+	tests := []struct {
+		name        string
+		invitedBy   string
+		userID      string
+		role        entities.MemberRole
+		cardID      *string
+		expectError bool
+	}{
+		{
+			name:      "owner invites co-owner",
+			invitedBy: account.UserID,
+			userID:    uuid.NewString(),
+			role:      entities.MemberRoleCoOwner,
+		},
+		{
+			name:      "owner invites card holder",
+			invitedBy: account.UserID,
+			userID:    uuid.NewString(),
+			role:      entities.MemberRoleCardHolder,
+			cardID:    &card.ID,
+		},
+		{
+			name:        "card holder without card",
+			invitedBy:   account.UserID,
+			userID:      uuid.NewString(),
+			role:        entities.MemberRoleCardHolder,
+			expectError: true,
+		},
+		{
+			name:        "card holder with unknown card",
+			invitedBy:   account.UserID,
+			userID:      uuid.NewString(),
+			role:        entities.MemberRoleCardHolder,
+			cardID:      &otherCardID,
+			expectError: true,
+		},
+		{
+			name:        "invalid role",
+			invitedBy:   account.UserID,
+			userID:      uuid.NewString(),
+			role:        entities.MemberRole("admin"),
+			expectError: true,
+		},
+		{
+			name:        "owner invites themselves",
+			invitedBy:   account.UserID,
+			userID:      account.UserID,
+			role:        entities.MemberRoleViewer,
+			expectError: true,
+		},
+		{
+			name:        "stranger cannot invite",
+			invitedBy:   uuid.NewString(),
+			userID:      uuid.NewString(),
+			role:        entities.MemberRoleViewer,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.InviteMember(account.ID, tt.invitedBy, tt.userID, tt.role, tt.cardID)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("InviteMember() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("InviteMember() unexpected error: %v", err)
+			}
+			if result.Status != entities.MemberStatusPending {
+				t.Errorf("InviteMember() status = %v, want %v", result.Status, entities.MemberStatusPending)
+			}
+			if result.InvitedBy != tt.invitedBy {
+				t.Errorf("InviteMember() invitedBy = %v, want %v", result.InvitedBy, tt.invitedBy)
+			}
+		})
+	}
+
+	t.Run("duplicate invitation", func(t *testing.T) {
+		userID := uuid.NewString()
+		if _, err := service.InviteMember(account.ID, account.UserID, userID, entities.MemberRoleViewer, nil); err != nil {
+			t.Fatalf("InviteMember() unexpected error: %v", err)
+		}
+		if _, err := service.InviteMember(account.ID, account.UserID, userID, entities.MemberRoleViewer, nil); err == nil {
+			t.Error("InviteMember() expected error for duplicate invitation but got none")
+		}
+	})
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	service, account, _ := setupMembershipService(t)
+	userID := uuid.NewString()
+
+	member, err := service.InviteMember(account.ID, account.UserID, userID, entities.MemberRoleCoOwner, nil)
+	if err != nil {
+		t.Fatalf("InviteMember() unexpected error: %v", err)
+	}
+
+	if _, err := service.AcceptInvitation(member.ID, uuid.NewString()); err != errors.ErrUnauthorized {
+		t.Errorf("AcceptInvitation() by another user error = %v, want %v", err, errors.ErrUnauthorized)
+	}
+
+	canView, _ := service.CanViewAccount(account, userID)
+	if canView {
+		t.Error("CanViewAccount() pending member should not view the account")
+	}
+
+	accepted, err := service.AcceptInvitation(member.ID, userID)
+	if err != nil {
+		t.Fatalf("AcceptInvitation() unexpected error: %v", err)
+	}
+	if !accepted.IsActive() || accepted.AcceptedAt == nil {
+		t.Errorf("AcceptInvitation() status = %v, want active with accepted date", accepted.Status)
+	}
+
+	if _, err := service.AcceptInvitation(member.ID, userID); err == nil {
+		t.Error("AcceptInvitation() expected error accepting twice but got none")
+	}
+
+	canView, _ = service.CanViewAccount(account, userID)
+	if !canView {
+		t.Error("CanViewAccount() active member should view the account")
+	}
+}
+
+func TestCanUseCard(t *testing.T) {
+	service, account, card := setupMembershipService(t)
+	otherCard := &entities.Card{ID: uuid.NewString(), AccountID: account.ID}
+
+	addMember := func(role entities.MemberRole, cardID *string) string {
+		userID := uuid.NewString()
+		member, err := service.InviteMember(account.ID, account.UserID, userID, role, cardID)
+		if err != nil {
+			t.Fatalf("InviteMember() unexpected error: %v", err)
+		}
+		if _, err := service.AcceptInvitation(member.ID, userID); err != nil {
+			t.Fatalf("AcceptInvitation() unexpected error: %v", err)
+		}
+		return userID
+	}
+
+	coOwner := addMember(entities.MemberRoleCoOwner, nil)
+	viewer := addMember(entities.MemberRoleViewer, nil)
+	cardHolder := addMember(entities.MemberRoleCardHolder, &card.ID)
+
+	tests := []struct {
+		name    string
+		card    *entities.Card
+		userID  string
+		canUse  bool
+		canView bool
+	}{
+		{name: "account owner", card: card, userID: account.UserID, canUse: true, canView: true},
+		{name: "co-owner", card: otherCard, userID: coOwner, canUse: true, canView: true},
+		{name: "viewer", card: card, userID: viewer, canUse: false, canView: true},
+		{name: "card holder on assigned card", card: card, userID: cardHolder, canUse: true, canView: true},
+		{name: "card holder on another card", card: otherCard, userID: cardHolder, canUse: false, canView: false},
+		{name: "stranger", card: card, userID: uuid.NewString(), canUse: false, canView: false},
+		{name: "no user", card: card, userID: "", canUse: false, canView: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canUse, err := service.CanUseCard(tt.card, tt.userID)
+			if err != nil {
+				t.Fatalf("CanUseCard() unexpected error: %v", err)
+			}
+			if canUse != tt.canUse {
+				t.Errorf("CanUseCard() = %v, want %v", canUse, tt.canUse)
+			}
+
+			canView, err := service.CanViewCard(tt.card, tt.userID)
+			if err != nil {
+				t.Fatalf("CanViewCard() unexpected error: %v", err)
+			}
+			if canView != tt.canView {
+				t.Errorf("CanViewCard() = %v, want %v", canView, tt.canView)
+			}
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	service, account, _ := setupMembershipService(t)
+	userID := uuid.NewString()
+
+	member, err := service.InviteMember(account.ID, account.UserID, userID, entities.MemberRoleViewer, nil)
+	if err != nil {
+		t.Fatalf("InviteMember() unexpected error: %v", err)
+	}
+	if _, err := service.AcceptInvitation(member.ID, userID); err != nil {
+		t.Fatalf("AcceptInvitation() unexpected error: %v", err)
+	}
+
+	if err := service.RemoveMember(account.ID, member.ID, uuid.NewString()); err != errors.ErrInsufficientRights {
+		t.Errorf("RemoveMember() by stranger error = %v, want %v", err, errors.ErrInsufficientRights)
+	}
+
+	// Members can leave the account on their own
+	if err := service.RemoveMember(account.ID, member.ID, userID); err != nil {
+		t.Fatalf("RemoveMember() unexpected error: %v", err)
+	}
+	if member.Status != entities.MemberStatusRevoked || member.RevokedAt == nil {
+		t.Errorf("RemoveMember() status = %v, want revoked", member.Status)
+	}
+
+	canView, _ := service.CanViewAccount(account, userID)
+	if canView {
+		t.Error("CanViewAccount() removed member should not view the account")
+	}
+}
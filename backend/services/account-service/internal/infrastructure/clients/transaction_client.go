@@ -11,8 +11,9 @@ import (
 
 // TransactionClient handles communication with the transaction service
 type TransactionClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL       string
+	internalToken string // Shared secret that identifies the account service to the transaction service
+	httpClient    *http.Client
 }
 
 // NewTransactionClient creates a new transaction service client
@@ -23,7 +24,8 @@ func NewTransactionClient() *TransactionClient {
 	}
 
 	return &TransactionClient{
-		baseURL: baseURL,
+		baseURL:       baseURL,
+		internalToken: os.Getenv("INTERNAL_SERVICE_TOKEN"),
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -41,6 +43,7 @@ type CreateTransactionRequest struct {
 	PaymentMethod string                 `json:"paymentMethod,omitempty"`
 	MerchantName  string                 `json:"merchantName,omitempty"`
 	ReferenceID   string                 `json:"referenceId,omitempty"`
+	InitiatedBy   string                 `json:"initiatedBy,omitempty"` // Member that performed the operation on a shared account
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -80,7 +83,7 @@ func (c *TransactionClient) CreateTransaction(userID string, req CreateTransacti
 
 	// Set headers
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("X-User-ID", userID)
+	c.setHeaders(httpReq, userID)
 
 	// Make request
 	resp, err := c.httpClient.Do(httpReq)
@@ -103,8 +106,17 @@ func (c *TransactionClient) CreateTransaction(userID string, req CreateTransacti
 	return &response, nil
 }
 
+// setHeaders identifies the user the request acts for and the account service as its caller, which the
+// transaction service requires to record the member that initiated an operation on a shared account
+func (c *TransactionClient) setHeaders(httpReq *http.Request, userID string) {
+	httpReq.Header.Set("X-User-ID", userID)
+	if c.internalToken != "" {
+		httpReq.Header.Set("X-Internal-Service-Token", c.internalToken)
+	}
+}
+
 // CreateDebitCardTransaction creates a debit card transaction record
-func (c *TransactionClient) CreateDebitCardTransaction(userID, accountID, cardID string, amount float64, description, merchantName, reference string) error {
+func (c *TransactionClient) CreateDebitCardTransaction(userID, initiatedBy, accountID, cardID string, amount float64, description, merchantName, reference string) error {
 	req := CreateTransactionRequest{
 		Type:          "debit_purchase",
 		Amount:        amount,
@@ -114,6 +126,7 @@ func (c *TransactionClient) CreateDebitCardTransaction(userID, accountID, cardID
 		PaymentMethod: "debit_card",
 		MerchantName:  merchantName,
 		ReferenceID:   reference,
+		InitiatedBy:   initiatedBy,
 		Metadata: map[string]interface{}{
 			"cardId":     cardID,
 			"category":   "purchase",
@@ -126,7 +139,7 @@ func (c *TransactionClient) CreateDebitCardTransaction(userID, accountID, cardID
 }
 
 // CreateInstallmentTransaction creates a transaction record for installment plan creation
-func (c *TransactionClient) CreateInstallmentTransaction(userID, accountID, cardID string, amount float64, installmentsCount int, planID, description, merchantName, reference string) (*TransactionResponse, error) {
+func (c *TransactionClient) CreateInstallmentTransaction(userID, initiatedBy, accountID, cardID string, amount float64, installmentsCount int, planID, description, merchantName, reference string) (*TransactionResponse, error) {
 	req := CreateTransactionRequest{
 		Type:          "credit_purchase_installments",
 		Amount:        amount,
@@ -136,6 +149,7 @@ func (c *TransactionClient) CreateInstallmentTransaction(userID, accountID, card
 		PaymentMethod: "credit_card_installments",
 		MerchantName:  merchantName,
 		ReferenceID:   reference,
+		InitiatedBy:   initiatedBy,
 		Metadata: map[string]interface{}{
 			"cardId":            cardID,
 			"installmentPlanId": planID,
@@ -204,7 +218,7 @@ func (c *TransactionClient) GetTransactionsByInstallmentPlan(userID, planID stri
 	}
 
 	// Set headers
-	httpReq.Header.Set("X-User-ID", userID)
+	c.setHeaders(httpReq, userID)
 
 	// Make request
 	resp, err := c.httpClient.Do(httpReq)
@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateTransactionInitiatedByMember(t *testing.T) {
+	tests := []struct {
+		name          string
+		internalToken string
+		expectedToken string
+	}{
+		{name: "with internal token", internalToken: "secreto-interno", expectedToken: "secreto-interno"},
+		{name: "without internal token", internalToken: "", expectedToken: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *http.Request
+			var body CreateTransactionRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				w.WriteHeader(http.StatusCreated)
+				json.NewEncoder(w).Encode(TransactionResponse{ID: "tx-1", Status: "completed"})
+			}))
+			defer server.Close()
+
+			t.Setenv("TRANSACTION_SERVICE_URL", server.URL)
+			t.Setenv("INTERNAL_SERVICE_TOKEN", tt.internalToken)
+			client := NewTransactionClient()
+
+			// A co-owner buys with the debit card of an account owned by another user
+			err := client.CreateDebitCardTransaction("owner-1", "member-2", "acc-1", "card-1", 1500, "Compra", "Carrefour", "ref-1")
+			if err != nil {
+				t.Fatalf("CreateDebitCardTransaction() unexpected error: %v", err)
+			}
+
+			if received == nil {
+				t.Fatal("transaction service was not called")
+			}
+			if received.URL.Path != "/api/v1/transactions" || received.Method != http.MethodPost {
+				t.Errorf("request = %s %s, want POST /api/v1/transactions", received.Method, received.URL.Path)
+			}
+			if got := received.Header.Get("X-User-ID"); got != "owner-1" {
+				t.Errorf("X-User-ID = %q, want owner-1", got)
+			}
+			if got := received.Header.Get("X-Internal-Service-Token"); got != tt.expectedToken {
+				t.Errorf("X-Internal-Service-Token = %q, want %q", got, tt.expectedToken)
+			}
+			if body.InitiatedBy != "member-2" {
+				t.Errorf("initiatedBy = %q, want member-2", body.InitiatedBy)
+			}
+		})
+	}
+}
+
+func TestGetTransactionsByInstallmentPlanSendsInternalToken(t *testing.T) {
+	var token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("X-Internal-Service-Token")
+		json.NewEncoder(w).Encode([]*TransactionResponse{})
+	}))
+	defer server.Close()
+
+	t.Setenv("TRANSACTION_SERVICE_URL", server.URL)
+	t.Setenv("INTERNAL_SERVICE_TOKEN", "secreto-interno")
+
+	if _, err := NewTransactionClient().GetTransactionsByInstallmentPlan("owner-1", "plan-1"); err != nil {
+		t.Fatalf("GetTransactionsByInstallmentPlan() unexpected error: %v", err)
+	}
+	if token != "secreto-interno" {
+		t.Errorf("X-Internal-Service-Token = %q, want secreto-interno", token)
+	}
+}
@@ -0,0 +1,173 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// sealedPrefix marks values sealed with envelope encryption (format version 1):
+// v1.<wrapped data key>.<ciphertext>, both parts being nonce + AES-256-GCM output in base64
+const sealedPrefix = "v1."
+
+// dataKeySize is the size of the per-record AES-256 data keys
+const dataKeySize = 32
+
+// Seal encrypts a value with a fresh data key and wraps that key with the active master key.
+// It returns the sealed value and the fingerprint of the master key that wrapped it.
+func (k *Keyring) Seal(plaintext []byte) (string, string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	ciphertext, err := encrypt(dataKey, plaintext)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encrypt value: %w", err)
+	}
+
+	wrapped, err := k.wrap(dataKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	return sealedPrefix + wrapped + "." + base64.RawURLEncoding.EncodeToString(ciphertext), k.active, nil
+}
+
+// Open decrypts a sealed value using the master key with the given fingerprint
+func (k *Keyring) Open(sealed, fingerprint string) ([]byte, error) {
+	wrapped, ciphertext, err := splitSealed(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := k.unwrap(wrapped, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("malformed sealed value: %w", err)
+	}
+	plaintext, err := decrypt(dataKey, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+// Rewrap wraps the data key of a sealed value with the active master key. The encrypted
+// value itself is not touched, so rotation never handles the plaintext.
+func (k *Keyring) Rewrap(sealed, fingerprint string) (string, string, error) {
+	if fingerprint == k.active {
+		return sealed, fingerprint, nil
+	}
+
+	wrapped, ciphertext, err := splitSealed(sealed)
+	if err != nil {
+		return "", "", err
+	}
+
+	dataKey, err := k.unwrap(wrapped, fingerprint)
+	if err != nil {
+		return "", "", err
+	}
+
+	rewrapped, err := k.wrap(dataKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	return sealedPrefix + rewrapped + "." + ciphertext, k.active, nil
+}
+
+// IsSealed checks if the value was sealed by the keyring (as opposed to legacy client-encrypted values)
+func IsSealed(value string) bool {
+	_, _, err := splitSealed(value)
+	return err == nil
+}
+
+// IsSealed checks if the value was sealed by a keyring
+func (k *Keyring) IsSealed(value string) bool {
+	return IsSealed(value)
+}
+
+// wrap encrypts a data key with the active master key
+func (k *Keyring) wrap(dataKey []byte) (string, error) {
+	masterKey, err := k.key(k.active)
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := encrypt(masterKey, dataKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(wrapped), nil
+}
+
+// unwrap decrypts a data key with the master key with the given fingerprint
+func (k *Keyring) unwrap(wrapped, fingerprint string) ([]byte, error) {
+	masterKey, err := k.key(fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.RawURLEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("malformed wrapped data key: %w", err)
+	}
+	dataKey, err := decrypt(masterKey, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with master key %s: %w", shortFingerprint(fingerprint), err)
+	}
+	return dataKey, nil
+}
+
+// splitSealed separates the wrapped data key and the ciphertext of a sealed value
+func splitSealed(sealed string) (string, string, error) {
+	if !strings.HasPrefix(sealed, sealedPrefix) {
+		return "", "", fmt.Errorf("value is not sealed")
+	}
+	parts := strings.Split(strings.TrimPrefix(sealed, sealedPrefix), ".")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("malformed sealed value")
+	}
+	return parts[0], parts[1], nil
+}
+
+// encrypt encrypts with AES-GCM, prefixing the random nonce to the output
+func encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// decrypt reverses encrypt
+func decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,134 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func newKey(t *testing.T) []byte {
+	key := make([]byte, MasterKeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+func TestSealAndOpen(t *testing.T) {
+	keyring, err := NewKeyring(newKey(t))
+	if err != nil {
+		t.Fatalf("NewKeyring() unexpected error: %v", err)
+	}
+
+	sealed, fingerprint, err := keyring.Seal([]byte("4111111111111111"))
+	if err != nil {
+		t.Fatalf("Seal() unexpected error: %v", err)
+	}
+	if fingerprint != keyring.ActiveFingerprint() || len(fingerprint) != 64 {
+		t.Errorf("Seal() fingerprint = %v, want active fingerprint", fingerprint)
+	}
+	if !IsSealed(sealed) || strings.Contains(sealed, "4111111111111111") {
+		t.Errorf("Seal() = %v, want a sealed value", sealed)
+	}
+
+	// Every record gets its own data key
+	other, _, _ := keyring.Seal([]byte("4111111111111111"))
+	if other == sealed {
+		t.Error("Seal() produced the same value twice")
+	}
+
+	plaintext, err := keyring.Open(sealed, fingerprint)
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if string(plaintext) != "4111111111111111" {
+		t.Errorf("Open() = %s, want 4111111111111111", plaintext)
+	}
+
+	tampered := sealed[:len(sealed)-2] + "AA"
+	if _, err := keyring.Open(tampered, fingerprint); err == nil {
+		t.Error("Open() of tampered value expected error but got none")
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	oldKey, newKey := newKey(t), newKey(t)
+
+	oldKeyring, _ := NewKeyring(oldKey)
+	sealed, oldFingerprint, err := oldKeyring.Seal([]byte("5500000000000004"))
+	if err != nil {
+		t.Fatalf("Seal() unexpected error: %v", err)
+	}
+
+	// New key first, old key kept to open the records not rotated yet
+	keyring, _ := NewKeyring(newKey, oldKey)
+	if plaintext, err := keyring.Open(sealed, oldFingerprint); err != nil || string(plaintext) != "5500000000000004" {
+		t.Fatalf("Open() with rotated keyring = %s, %v", plaintext, err)
+	}
+
+	rewrapped, fingerprint, err := keyring.Rewrap(sealed, oldFingerprint)
+	if err != nil {
+		t.Fatalf("Rewrap() unexpected error: %v", err)
+	}
+	if fingerprint != Fingerprint(newKey) {
+		t.Errorf("Rewrap() fingerprint = %v, want new key", fingerprint)
+	}
+
+	// Once rewrapped, the old key can be retired
+	newOnly, _ := NewKeyring(newKey)
+	if plaintext, err := newOnly.Open(rewrapped, fingerprint); err != nil || string(plaintext) != "5500000000000004" {
+		t.Errorf("Open() after rewrap = %s, %v", plaintext, err)
+	}
+	if _, err := newOnly.Open(sealed, oldFingerprint); err == nil {
+		t.Error("Open() with retired key expected error but got none")
+	}
+}
+
+func TestParseKeyring(t *testing.T) {
+	first, second := newKey(t), newKey(t)
+	encoded := "# active key\n" + base64.StdEncoding.EncodeToString(first) + "\n\n" + base64.StdEncoding.EncodeToString(second) + "\n"
+
+	keyring, err := ParseKeyring(encoded)
+	if err != nil {
+		t.Fatalf("ParseKeyring() unexpected error: %v", err)
+	}
+	if keyring.ActiveFingerprint() != Fingerprint(first) || !keyring.Has(Fingerprint(second)) {
+		t.Errorf("ParseKeyring() did not load both keys with the first one active")
+	}
+
+	envKeyring, err := ParseKeyring(base64.StdEncoding.EncodeToString(second) + "," + base64.StdEncoding.EncodeToString(first))
+	if err != nil || envKeyring.ActiveFingerprint() != Fingerprint(second) {
+		t.Errorf("ParseKeyring() of comma separated keys = %v", err)
+	}
+
+	invalid := []string{"", "not base64!", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 16))}
+	for _, value := range invalid {
+		if _, err := ParseKeyring(value); err == nil {
+			t.Errorf("ParseKeyring(%q) expected error but got none", value)
+		}
+	}
+}
+
+func TestLoadKeyring(t *testing.T) {
+	if _, err := LoadKeyring("", " ", false); err == nil {
+		t.Error("LoadKeyring() without keyring expected error but got none")
+	}
+
+	devKeyring, err := LoadKeyring("", "", true)
+	if err != nil {
+		t.Fatalf("LoadKeyring() with development key unexpected error: %v", err)
+	}
+	devKey := sha256.Sum256([]byte(developmentKeySeed))
+	if devKeyring.ActiveFingerprint() != Fingerprint(devKey[:]) {
+		t.Error("LoadKeyring() with development key did not use it")
+	}
+
+	key := newKey(t)
+	keyring, err := LoadKeyring("", base64.StdEncoding.EncodeToString(key), true)
+	if err != nil || keyring.ActiveFingerprint() != Fingerprint(key) {
+		t.Errorf("LoadKeyring() with configured keys = %v, want them over the development key", err)
+	}
+}
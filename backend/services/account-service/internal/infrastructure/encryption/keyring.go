@@ -0,0 +1,124 @@
+package encryption
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MasterKeySize is the size of the AES-256 master keys that wrap the data keys
+const MasterKeySize = 32
+
+// Keyring holds the master keys used to wrap card data keys, indexed by fingerprint.
+// The active key wraps new records; the others are kept to open records not yet rotated.
+type Keyring struct {
+	keys   map[string][]byte
+	active string
+}
+
+// NewKeyring creates a keyring from raw master keys. The first key is the active one.
+func NewKeyring(keys ...[]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("keyring requires at least one master key")
+	}
+
+	keyring := &Keyring{keys: make(map[string][]byte, len(keys))}
+	for i, key := range keys {
+		if len(key) != MasterKeySize {
+			return nil, fmt.Errorf("master key %d must be %d bytes, got %d", i+1, MasterKeySize, len(key))
+		}
+		fingerprint := Fingerprint(key)
+		keyring.keys[fingerprint] = key
+		if i == 0 {
+			keyring.active = fingerprint
+		}
+	}
+	return keyring, nil
+}
+
+// ParseKeyring creates a keyring from base64 encoded master keys separated by commas or new lines.
+// Empty lines and lines starting with # are ignored. The first key is the active one.
+func ParseKeyring(encoded string) (*Keyring, error) {
+	var keys [][]byte
+
+	scanner := bufio.NewScanner(strings.NewReader(strings.ReplaceAll(encoded, ",", "\n")))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("master key %d is not valid base64: %w", len(keys)+1, err)
+		}
+		keys = append(keys, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read master keys: %w", err)
+	}
+
+	return NewKeyring(keys...)
+}
+
+// developmentKeySeed derives the master key of local environments that opt in to it. It is
+// public knowledge, so anything sealed with it is readable by anyone.
+const developmentKeySeed = "fintrack-development-card-master-key"
+
+// LoadKeyring loads the master keys from a local file, falling back to the given
+// environment keyring (comma separated keys) when no file is configured. When neither
+// is, it fails unless the development key is explicitly allowed.
+func LoadKeyring(file, envKeys string, allowDevelopmentKey bool) (*Keyring, error) {
+	if file == "" && strings.TrimSpace(envKeys) == "" {
+		if !allowDevelopmentKey {
+			return nil, fmt.Errorf("no card keyring configured: set CARD_KEYRING_FILE or CARD_MASTER_KEYS")
+		}
+		key := sha256.Sum256([]byte(developmentKeySeed))
+		return NewKeyring(key[:])
+	}
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keyring file: %w", err)
+		}
+		return ParseKeyring(string(content))
+	}
+	return ParseKeyring(envKeys)
+}
+
+// Fingerprint identifies a master key without revealing it
+func Fingerprint(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:])
+}
+
+// ActiveFingerprint returns the fingerprint of the key that wraps new records
+func (k *Keyring) ActiveFingerprint() string {
+	return k.active
+}
+
+// Has checks if the keyring holds the master key with the given fingerprint
+func (k *Keyring) Has(fingerprint string) bool {
+	_, ok := k.keys[fingerprint]
+	return ok
+}
+
+// key returns the master key with the given fingerprint
+func (k *Keyring) key(fingerprint string) ([]byte, error) {
+	key, ok := k.keys[fingerprint]
+	if !ok {
+		return nil, fmt.Errorf("master key %s is not in the keyring", shortFingerprint(fingerprint))
+	}
+	return key, nil
+}
+
+// shortFingerprint abbreviates a fingerprint for error messages and logs
+func shortFingerprint(fingerprint string) string {
+	if len(fingerprint) > 12 {
+		return fingerprint[:12]
+	}
+	return fingerprint
+}
@@ -1022,117 +1022,6 @@ func TestWithdrawFunds(t *testing.T) {
 	}
 }
 
-func TestUpdateCreditLimit(t *testing.T) {
-	service := NewMockAccountService()
-	handler := New(service)
-
-	// Create test credit card account
-	creditLimit := 5000.0
-	account := &entities.Account{
-		UserID:      uuid.NewString(),
-		AccountType: entities.AccountTypeCredit,
-		Name:        "Test Credit Card",
-		Currency:    entities.CurrencyUSD,
-		Balance:     -500.0, // Used 500 of credit
-		CreditLimit: &creditLimit,
-		IsActive:    true,
-	}
-	createdAccount, _ := service.CreateAccount(account)
-
-	tests := []struct {
-		name           string
-		accountID      string
-		requestBody    dto.UpdateCreditLimitRequest
-		expectedStatus int
-		expectError    bool
-		expectedLimit  float64
-	}{
-		{
-			name:      "successful credit limit update",
-			accountID: createdAccount.ID,
-			requestBody: dto.UpdateCreditLimitRequest{
-				CreditLimit: 7500.0,
-			},
-			expectedStatus: http.StatusOK,
-			expectError:    false,
-			expectedLimit:  7500.0,
-		},
-		{
-			name:      "zero credit limit",
-			accountID: createdAccount.ID,
-			requestBody: dto.UpdateCreditLimitRequest{
-				CreditLimit: 0.0,
-			},
-			expectedStatus: http.StatusOK,
-			expectError:    false,
-			expectedLimit:  0.0,
-		},
-		{
-			name:      "negative credit limit",
-			accountID: createdAccount.ID,
-			requestBody: dto.UpdateCreditLimitRequest{
-				CreditLimit: -1000.0,
-			},
-			expectedStatus: http.StatusBadRequest,
-			expectError:    true,
-		},
-		{
-			name:      "non-existing account",
-			accountID: uuid.NewString(),
-			requestBody: dto.UpdateCreditLimitRequest{
-				CreditLimit: 5000.0,
-			},
-			expectedStatus: http.StatusNotFound,
-			expectError:    true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			c, w := createTestContext()
-
-			// Set URL parameter
-			c.Params = gin.Params{
-				{Key: "id", Value: tt.accountID},
-			}
-
-			// Create request body
-			body, _ := json.Marshal(tt.requestBody)
-			c.Request = httptest.NewRequest(http.MethodPut, "/api/accounts/"+tt.accountID+"/credit-limit", bytes.NewBuffer(body))
-			c.Request.Header.Set("Content-Type", "application/json")
-
-			// Call handler
-			handler.UpdateCreditLimit(c)
-
-			// Check status code
-			if w.Code != tt.expectedStatus {
-				t.Errorf("UpdateCreditLimit() status = %v, want %v", w.Code, tt.expectedStatus)
-			}
-
-			// Parse response
-			var response map[string]interface{}
-			json.Unmarshal(w.Body.Bytes(), &response)
-
-			// Check if error is expected
-			if tt.expectError {
-				if _, hasError := response["error"]; !hasError {
-					t.Error("UpdateCreditLimit() expected error but got none")
-				}
-			} else {
-				if _, hasData := response["id"]; !hasData {
-					t.Error("UpdateCreditLimit() expected account data but got none")
-				} else {
-					if limit, ok := response["credit_limit"].(float64); ok {
-						if limit != tt.expectedLimit {
-							t.Errorf("UpdateCreditLimit() credit_limit = %v, want %v", limit, tt.expectedLimit)
-						}
-					}
-				}
-			}
-		})
-	}
-}
-
 func TestUpdateCreditDates(t *testing.T) {
 	service := NewMockAccountService()
 	handler := New(service)
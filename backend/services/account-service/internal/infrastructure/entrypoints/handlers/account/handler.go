@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
 	"github.com/fintrack/account-service/internal/core/service"
 	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/account/dto"
 )
@@ -321,6 +322,10 @@ func (h *Handler) UpdateBalance(c *gin.Context) {
 	}
 
 	newBalance, err := h.accountService.UpdateAccountBalance(accountID, req.Amount)
+	if err == errors.ErrAccountClosed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -362,6 +367,10 @@ func (h *Handler) UpdateStatus(c *gin.Context) {
 	}
 
 	updatedAccount, err := h.accountService.UpdateAccountStatus(accountID, req.IsActive)
+	if err == errors.ErrAccountClosed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -541,66 +550,6 @@ func (h *Handler) WithdrawFunds(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// UpdateCreditLimit updates the credit limit for a credit card account
-// @Summary Update credit limit
-// @Description Update the credit limit for a credit card account
-// @Tags Credit
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path string true "Account ID"
-// @Param request body dto.UpdateCreditLimitRequest true "Credit limit update data"
-// @Success 200 {object} dto.AccountResponse "Credit limit updated successfully"
-// @Failure 400 {object} map[string]string "Invalid request data"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Account not found"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/accounts/{id}/credit-limit [put]
-func (h *Handler) UpdateCreditLimit(c *gin.Context) {
-	accountID := c.Param("id")
-	if accountID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "account ID is required"})
-		return
-	}
-
-	var req dto.UpdateCreditLimitRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// First verify it's a credit card account
-	account, err := h.accountService.GetAccountByID(accountID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
-		return
-	}
-
-	if account.AccountType != entities.AccountTypeCredit {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "credit limit can only be set for credit card accounts"})
-		return
-	}
-
-	// Update credit limit (this would need to be implemented in the service)
-	account.CreditLimit = &req.CreditLimit
-
-	// Create a proper update request
-	updateReq := &dto.UpdateAccountRequest{
-		Name:        account.Name,
-		Description: account.Description,
-		CreditLimit: &req.CreditLimit,
-	}
-
-	updatedAccount, err := h.accountService.UpdateAccount(accountID, updateReq)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	response := dto.ToAccountResponse(updatedAccount)
-	c.JSON(http.StatusOK, response)
-}
-
 // UpdateCreditDates updates the closing and due dates for a credit card account
 // @Summary Update credit card dates
 // @Description Update the closing and due dates for a credit card account
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// RevealCardNumberRequest represents the request of an administrator to decrypt a card number
+type RevealCardNumberRequest struct {
+	Reason string `json:"reason" binding:"required,min=5,max=255"` // Recorded in the access log
+}
+
+// RevealCardNumberResponse represents a decrypted card number
+type RevealCardNumberResponse struct {
+	CardID string `json:"card_id"`
+	Number string `json:"number"`
+}
+
+// CardNumberAccessResponse represents an entry of the card number access log
+type CardNumberAccessResponse struct {
+	ID             string    `json:"id"`
+	AccessedBy     string    `json:"accessed_by"`
+	Reason         string    `json:"reason"`
+	Granted        bool      `json:"granted"`
+	FailureReason  string    `json:"failure_reason,omitempty"`
+	KeyFingerprint string    `json:"key_fingerprint,omitempty"`
+	ClientIP       string    `json:"client_ip,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PaginatedCardNumberAccessResponse represents a paginated card number access log
+type PaginatedCardNumberAccessResponse struct {
+	Data       []CardNumberAccessResponse `json:"data"`
+	Pagination PaginationMeta             `json:"pagination"`
+}
+
+// ToPaginatedCardNumberAccessResponse converts access log entries with pagination info to response
+func ToPaginatedCardNumberAccessResponse(logs []*entities.CardNumberAccessLog, total int64, page, pageSize int) PaginatedCardNumberAccessResponse {
+	data := make([]CardNumberAccessResponse, len(logs))
+	for i, log := range logs {
+		data[i] = CardNumberAccessResponse{
+			ID:             log.ID,
+			AccessedBy:     log.AccessedBy,
+			Reason:         log.Reason,
+			Granted:        log.Granted,
+			FailureReason:  log.FailureReason,
+			KeyFingerprint: log.KeyFingerprint,
+			ClientIP:       log.ClientIP,
+			CreatedAt:      log.CreatedAt,
+		}
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return PaginatedCardNumberAccessResponse{
+		Data: data,
+		Pagination: PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  total,
+			TotalPages:  totalPages,
+		},
+	}
+}
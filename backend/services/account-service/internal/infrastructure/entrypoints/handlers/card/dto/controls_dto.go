@@ -0,0 +1,117 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// UpdateCardControlsRequest represents the request to update the spending controls of a card.
+// Omitted fields keep their current value; empty lists clear them.
+type UpdateCardControlsRequest struct {
+	BlockedMerchants       []string `json:"blocked_merchants,omitempty" binding:"omitempty,max=100,dive,min=2,max=100"`
+	BlockedCategories      []string `json:"blocked_categories,omitempty" binding:"omitempty,max=50,dive,min=2,max=100"`
+	OnlinePurchasesEnabled *bool    `json:"online_purchases_enabled,omitempty"`
+	MaxTransactionAmount   *float64 `json:"max_transaction_amount,omitempty" binding:"omitempty,min=0"` // 0 removes the cap
+	AllowedCurrencies      []string `json:"allowed_currencies,omitempty" binding:"omitempty,dive,len=3"`
+}
+
+// FreezeCardRequest represents the request to temporarily freeze a card
+type FreezeCardRequest struct {
+	Until  time.Time `json:"until" binding:"required"` // The card is unfrozen automatically after this time
+	Reason string    `json:"reason,omitempty" binding:"max=255"`
+}
+
+// CardControlsResponse represents the spending controls of a card
+type CardControlsResponse struct {
+	CardID                 string     `json:"card_id"`
+	BlockedMerchants       []string   `json:"blocked_merchants"`
+	BlockedCategories      []string   `json:"blocked_categories"`
+	OnlinePurchasesEnabled bool       `json:"online_purchases_enabled"`
+	MaxTransactionAmount   *float64   `json:"max_transaction_amount,omitempty"`
+	AllowedCurrencies      []string   `json:"allowed_currencies"`
+	IsFrozen               bool       `json:"is_frozen"`
+	FrozenUntil            *time.Time `json:"frozen_until,omitempty"`
+	FreezeReason           string     `json:"freeze_reason,omitempty"`
+	UpdatedAt              *time.Time `json:"updated_at,omitempty"`
+}
+
+// CardControlRejectionResponse represents an operation rejected by the card controls
+type CardControlRejectionResponse struct {
+	ID           string    `json:"id"`
+	Reason       string    `json:"reason"`
+	Message      string    `json:"message"`
+	Amount       float64   `json:"amount"`
+	Currency     string    `json:"currency,omitempty"`
+	MerchantName string    `json:"merchant_name,omitempty"`
+	Category     string    `json:"category,omitempty"`
+	IsOnline     bool      `json:"is_online"`
+	AttemptedBy  string    `json:"attempted_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// PaginatedCardControlRejectionResponse represents a paginated list of rejections
+type PaginatedCardControlRejectionResponse struct {
+	Data       []CardControlRejectionResponse `json:"data"`
+	Pagination PaginationMeta                 `json:"pagination"`
+}
+
+// ToCardControlsResponse converts a CardControls entity to CardControlsResponse
+func ToCardControlsResponse(controls *entities.CardControls) CardControlsResponse {
+	response := CardControlsResponse{
+		CardID:                 controls.CardID,
+		BlockedMerchants:       nonNilStrings(controls.BlockedMerchants),
+		BlockedCategories:      nonNilStrings(controls.BlockedCategories),
+		OnlinePurchasesEnabled: !controls.OnlinePurchasesBlocked,
+		MaxTransactionAmount:   controls.MaxTransactionAmount,
+		AllowedCurrencies:      nonNilStrings(controls.AllowedCurrencies),
+		IsFrozen:               controls.IsFrozen(time.Now()),
+		FreezeReason:           controls.FreezeReason,
+	}
+	if response.IsFrozen {
+		response.FrozenUntil = controls.FrozenUntil
+	}
+	if !controls.UpdatedAt.IsZero() {
+		response.UpdatedAt = &controls.UpdatedAt
+	}
+	return response
+}
+
+// ToPaginatedCardControlRejectionResponse converts rejections with pagination info to response
+func ToPaginatedCardControlRejectionResponse(rejections []*entities.CardControlRejection, total int64, page, pageSize int) PaginatedCardControlRejectionResponse {
+	data := make([]CardControlRejectionResponse, len(rejections))
+	for i, rejection := range rejections {
+		data[i] = CardControlRejectionResponse{
+			ID:           rejection.ID,
+			Reason:       string(rejection.Reason),
+			Message:      rejection.Message,
+			Amount:       rejection.Amount,
+			Currency:     string(rejection.Currency),
+			MerchantName: rejection.MerchantName,
+			Category:     rejection.Category,
+			IsOnline:     rejection.IsOnline,
+			AttemptedBy:  rejection.AttemptedBy,
+			CreatedAt:    rejection.CreatedAt,
+		}
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return PaginatedCardControlRejectionResponse{
+		Data: data,
+		Pagination: PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  total,
+			TotalPages:  totalPages,
+		},
+	}
+}
+
+// nonNilStrings returns an empty list instead of nil so JSON responses always carry arrays
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
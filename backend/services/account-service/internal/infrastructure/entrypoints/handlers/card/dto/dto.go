@@ -68,25 +68,25 @@ func (cd *CustomDate) ToTimePointer() *time.Time {
 
 // CreateCardRequest represents the request to create a new card
 type CreateCardRequest struct {
-	AccountID       string `json:"account_id,omitempty"`         // Set from URL parameter, not required in JSON
-	CardType        string `json:"card_type" binding:"required"` // "credit" or "debit"
-	CardBrand       string `json:"card_brand" binding:"required"`
-	LastFourDigits  string `json:"last_four_digits" binding:"required,len=4"`
-	MaskedNumber    string `json:"masked_number" binding:"required"`
-	HolderName      string `json:"holder_name" binding:"required,min=2,max=100"`
-	ExpirationMonth int    `json:"expiration_month" binding:"required,min=1,max=12"`
-	ExpirationYear  int    `json:"expiration_year" binding:"required"`
-	Nickname        string `json:"nickname,omitempty" binding:"max=50"`
-	IsDefault       bool   `json:"is_default,omitempty"`
+	AccountID       string       `json:"account_id,omitempty"`           // Set from URL parameter, not required in JSON
+	CardNumber      entities.PAN `json:"card_number" binding:"required"` // Full number; brand, type, masked number and last four digits are derived from it
+	CardType        string       `json:"card_type,omitempty"`            // "credit" or "debit"; required when the BIN does not tell
+	CardBrand       string       `json:"card_brand,omitempty"`           // Optional, must match the brand of the number
+	HolderName      string       `json:"holder_name" binding:"required,min=2,max=100"`
+	ExpirationMonth int          `json:"expiration_month" binding:"required,min=1,max=12"`
+	ExpirationYear  int          `json:"expiration_year" binding:"required"`
+	Nickname        string       `json:"nickname,omitempty" binding:"max=50"`
+	IsDefault       bool         `json:"is_default,omitempty"`
 
 	// Credit card specific fields
 	CreditLimit *float64    `json:"credit_limit,omitempty" binding:"omitempty,min=0"`
 	ClosingDate *CustomDate `json:"closing_date,omitempty"`
 	DueDate     *CustomDate `json:"due_date,omitempty"`
 
-	// Security fields (encrypted data)
-	EncryptedNumber string `json:"encrypted_number" binding:"required"`
-	KeyFingerprint  string `json:"key_fingerprint" binding:"required"`
+	// Shared credit line fields (credit limit not required when set)
+	CreditLineID *string  `json:"credit_line_id,omitempty"`
+	IsAdditional bool     `json:"is_additional,omitempty"`
+	SubLimit     *float64 `json:"sub_limit,omitempty" binding:"omitempty,gt=0"`
 }
 
 // UpdateCardRequest represents the request to update a card
@@ -99,6 +99,14 @@ type UpdateCardRequest struct {
 	CreditLimit     *float64 `json:"credit_limit,omitempty" binding:"omitempty,min=0,max=1000000"` // Allow credit limit updates
 }
 
+// RenewCardRequest represents the request to issue the replacement of an expiring card.
+// The replacement inherits the holder, balance, credit settings, controls and installment plans.
+type RenewCardRequest struct {
+	CardNumber      entities.PAN `json:"card_number" binding:"required"` // Must be of the same brand as the renewed card
+	ExpirationMonth int          `json:"expiration_month" binding:"required,min=1,max=12"`
+	ExpirationYear  int          `json:"expiration_year" binding:"required"`
+}
+
 // CardResponse represents the response for card operations
 type CardResponse struct {
 	ID              string    `json:"id"`
@@ -122,6 +130,14 @@ type CardResponse struct {
 	ClosingDate *CustomDate `json:"closing_date,omitempty"`
 	DueDate     *CustomDate `json:"due_date,omitempty"`
 
+	// Shared credit line fields
+	CreditLineID *string  `json:"credit_line_id,omitempty"`
+	IsAdditional bool     `json:"is_additional"`
+	SubLimit     *float64 `json:"sub_limit,omitempty"`
+
+	// Renewal
+	ReplacesCardID *string `json:"replaces_card_id,omitempty"` // Card renewed by this one
+
 	// Installment plans summary (optional, when requested)
 	InstallmentPlans *InstallmentPlansSummary `json:"installment_plans,omitempty"`
 
@@ -141,9 +157,13 @@ type InstallmentPlansSummary struct {
 
 // CreditCardChargeRequest represents a charge to a credit card
 type CreditCardChargeRequest struct {
-	Amount      float64 `json:"amount" binding:"required,min=0.01"`
-	Description string  `json:"description" binding:"required,min=3,max=255"`
-	Reference   string  `json:"reference,omitempty" binding:"max=50"`
+	Amount       float64 `json:"amount" binding:"required,min=0.01"`
+	Description  string  `json:"description" binding:"required,min=3,max=255"`
+	Reference    string  `json:"reference,omitempty" binding:"max=50"`
+	MerchantName string  `json:"merchant_name,omitempty" binding:"max=100"`
+	Category     string  `json:"category,omitempty" binding:"max=100"`
+	Currency     string  `json:"currency,omitempty" binding:"omitempty,len=3"` // Defaults to the account currency
+	IsOnline     bool    `json:"is_online,omitempty"`
 }
 
 // CreditCardPaymentRequest represents a payment to a credit card
@@ -164,16 +184,30 @@ type CreditCardChargeWithInstallmentsRequest struct {
 	InterestRate      float64   `json:"interest_rate,omitempty" binding:"min=0,max=100"`
 	AdminFee          float64   `json:"admin_fee,omitempty" binding:"min=0"`
 	Reference         string    `json:"reference,omitempty" binding:"max=50"`
+	Category          string    `json:"category,omitempty" binding:"max=100"`
+	Currency          string    `json:"currency,omitempty" binding:"omitempty,len=3"` // Defaults to the account currency
+	IsOnline          bool      `json:"is_online,omitempty"`
 }
 
 // CreditCardBalanceResponse represents the balance information for a credit card
 type CreditCardBalanceResponse struct {
-	CardID          string      `json:"card_id"`
-	Balance         float64     `json:"balance"`            // Current debt
-	CreditLimit     float64     `json:"credit_limit"`       // Total credit limit
-	AvailableCredit float64     `json:"available_credit"`   // Remaining credit
-	MinimumPayment  float64     `json:"minimum_payment"`    // Minimum payment due
-	DueDate         *CustomDate `json:"due_date,omitempty"` // Next payment due date
+	CardID                 string           `json:"card_id"`
+	Balance                float64          `json:"balance"`                 // Current debt
+	CreditLimit            float64          `json:"credit_limit"`            // Total credit limit (sub-limit or line limit for shared lines)
+	AvailableCredit        float64          `json:"available_credit"`        // Remaining credit
+	InstallmentCommitments float64          `json:"installment_commitments"` // Still owed on active installment plans
+	MinimumPayment         float64          `json:"minimum_payment"`         // Minimum payment due
+	DueDate                *CustomDate      `json:"due_date,omitempty"`      // Next payment due date
+	CreditLine             *CreditLineUsage `json:"credit_line,omitempty"`   // Shared credit line usage, when the card belongs to one
+}
+
+// CreditLineUsage represents the usage of a credit line shared by several cards
+type CreditLineUsage struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	TotalLimit      float64 `json:"total_limit"`
+	UsedCredit      float64 `json:"used_credit"`      // Debt of all the cards of the line
+	AvailableCredit float64 `json:"available_credit"` // Remaining credit considering installment commitments
 }
 
 // Debit Card Operations DTOs
@@ -184,6 +218,9 @@ type DebitCardTransactionRequest struct {
 	Description  string  `json:"description" binding:"required,min=3,max=255"`
 	MerchantName string  `json:"merchant_name,omitempty" binding:"max=100"`
 	Reference    string  `json:"reference,omitempty" binding:"max=50"`
+	Category     string  `json:"category,omitempty" binding:"max=100"`
+	Currency     string  `json:"currency,omitempty" binding:"omitempty,len=3"` // Defaults to the account currency
+	IsOnline     bool    `json:"is_online,omitempty"`
 }
 
 // DebitCardBalanceResponse represents the balance information for a debit card
@@ -228,6 +265,10 @@ func ToCardResponse(card *entities.Card) CardResponse {
 		CreditLimit:     card.CreditLimit,
 		ClosingDate:     ToCustomDate(card.ClosingDate),
 		DueDate:         ToCustomDate(card.DueDate),
+		CreditLineID:    card.CreditLineID,
+		IsAdditional:    card.IsAdditional,
+		SubLimit:        card.SubLimit,
+		ReplacesCardID:  card.ReplacesCardID,
 	}
 }
 
@@ -253,12 +294,9 @@ func ToPaginatedCardResponse(cards []*entities.Card, total int64, page, pageSize
 
 // ToCreditCardBalanceResponse converts card entity to credit balance response
 func ToCreditCardBalanceResponse(card *entities.Card) CreditCardBalanceResponse {
-	var creditLimit, availableCredit, minimumPayment float64
-
-	if card.CreditLimit != nil {
-		creditLimit = *card.CreditLimit
-		availableCredit = creditLimit - card.Balance
-	}
+	var minimumPayment float64
+	creditLimit := card.GetEffectiveCreditLimit()
+	availableCredit := card.GetAvailableCreditWithInstallments()
 
 	// Calculate minimum payment (5% of balance or minimum $500)
 	if card.Balance > 0 {
@@ -272,14 +310,27 @@ func ToCreditCardBalanceResponse(card *entities.Card) CreditCardBalanceResponse
 		}
 	}
 
-	return CreditCardBalanceResponse{
-		CardID:          card.ID,
-		Balance:         card.Balance,
-		CreditLimit:     creditLimit,
-		AvailableCredit: availableCredit,
-		MinimumPayment:  minimumPayment,
-		DueDate:         ToCustomDate(card.DueDate),
+	response := CreditCardBalanceResponse{
+		CardID:                 card.ID,
+		Balance:                card.Balance,
+		CreditLimit:            creditLimit,
+		AvailableCredit:        availableCredit,
+		InstallmentCommitments: card.GetTotalInstallmentCommitments(),
+		MinimumPayment:         minimumPayment,
+		DueDate:                ToCustomDate(card.DueDate),
 	}
+
+	if card.CreditLine != nil {
+		response.CreditLine = &CreditLineUsage{
+			ID:              card.CreditLine.ID,
+			Name:            card.CreditLine.Name,
+			TotalLimit:      card.CreditLine.TotalLimit,
+			UsedCredit:      card.CreditLine.GetUsedCredit(card),
+			AvailableCredit: card.CreditLine.GetAvailableCreditWithInstallments(card),
+		}
+	}
+
+	return response
 }
 
 // ToDebitCardBalanceResponse converts card entity to debit balance response
@@ -19,6 +19,11 @@ type CreateInstallmentPlanRequest struct {
 	AdminFee          float64   `json:"adminFee,omitempty"`
 	Reference         string    `json:"reference"`
 
+	// Purchase details evaluated against the card spending controls
+	Category string `json:"category,omitempty"`
+	Currency string `json:"currency,omitempty"` // Defaults to the account currency
+	IsOnline bool   `json:"isOnline,omitempty"`
+
 	// User context (usually from authentication)
 	UserID      string `json:"-"` // Set by middleware, not from request body
 	InitiatedBy string `json:"-"` // Set by middleware, not from request body
@@ -343,6 +343,56 @@ func (h *Handler) SetDefaultCard(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// RenewCard issues the replacement of an expiring card
+// @Summary Renew card
+// @Description Issue a replacement for an expiring or expired card. The new card inherits the balance, credit settings, spending controls, card holders and active installment plans; the old card becomes inactive.
+// @Tags Cards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param cardId path string true "Card ID"
+// @Param request body dto.RenewCardRequest true "Replacement card data"
+// @Success 201 {object} dto.CardResponse "Replacement card issued"
+// @Failure 400 {object} map[string]string "Invalid request data or card cannot be renewed"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this account"
+// @Failure 404 {object} map[string]string "Card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/cards/{cardId}/renew [post]
+func (h *Handler) RenewCard(c *gin.Context) {
+	cardID := c.Param("cardId")
+	if cardID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "card ID is required"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		userID = c.GetHeader("X-User-ID")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return
+		}
+	}
+
+	var req dto.RenewCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	card, err := h.cardService.RenewCard(cardID, userID, &req)
+	if err != nil {
+		status := h.getErrorStatus(err)
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dto.ToCardResponse(card)
+	c.JSON(http.StatusCreated, response)
+}
+
 // CREDIT CARD FINANCIAL OPERATIONS
 
 // ChargeCard processes a charge to a credit card
@@ -356,6 +406,9 @@ func (h *Handler) SetDefaultCard(c *gin.Context) {
 // @Param charge body dto.CreditCardChargeRequest true "Charge data"
 // @Success 200 {object} dto.CreditCardBalanceResponse "Charge processed successfully"
 // @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this card"
+// @Failure 422 {object} map[string]string "Rejected by the card spending controls (includes reason code)"
 // @Failure 404 {object} map[string]string "Card not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/cards/{cardId}/charge [post]
@@ -372,10 +425,22 @@ func (h *Handler) ChargeCard(c *gin.Context) {
 		return
 	}
 
-	card, err := h.cardService.ChargeCard(cardID, req.Amount, req.Description, req.Reference)
+	performedBy, ok := h.requirePerformer(c)
+	if !ok {
+		return
+	}
+
+	purchase := entities.PurchaseDetails{
+		MerchantName: req.MerchantName,
+		Category:     req.Category,
+		Currency:     entities.Currency(strings.ToUpper(req.Currency)),
+		IsOnline:     req.IsOnline,
+	}
+
+	card, err := h.cardService.ChargeCard(cardID, req.Amount, req.Description, req.Reference, performedBy, purchase)
 	if err != nil {
 		status := h.getErrorStatus(err)
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, h.errorResponse(err))
 		return
 	}
 
@@ -394,6 +459,8 @@ func (h *Handler) ChargeCard(c *gin.Context) {
 // @Param payment body dto.CreditCardPaymentRequest true "Payment data"
 // @Success 200 {object} dto.CreditCardBalanceResponse "Payment processed successfully"
 // @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this card"
 // @Failure 404 {object} map[string]string "Card not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/cards/{cardId}/payment [post]
@@ -410,7 +477,12 @@ func (h *Handler) PaymentCard(c *gin.Context) {
 		return
 	}
 
-	card, err := h.cardService.PaymentCard(cardID, req.Amount, req.PaymentMethod, req.Reference)
+	performedBy, ok := h.requirePerformer(c)
+	if !ok {
+		return
+	}
+
+	card, err := h.cardService.PaymentCard(cardID, req.Amount, req.PaymentMethod, req.Reference, performedBy)
 	if err != nil {
 		status := h.getErrorStatus(err)
 		c.JSON(status, gin.H{"error": err.Error()})
@@ -472,6 +544,9 @@ func (h *Handler) GetCardBalance(c *gin.Context) {
 // @Param transaction body dto.DebitCardTransactionRequest true "Transaction data"
 // @Success 200 {object} dto.DebitCardBalanceResponse "Transaction processed successfully"
 // @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this card"
+// @Failure 422 {object} map[string]string "Rejected by the card spending controls (includes reason code)"
 // @Failure 404 {object} map[string]string "Card not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/cards/{cardId}/transaction [post]
@@ -488,10 +563,22 @@ func (h *Handler) ProcessDebitTransaction(c *gin.Context) {
 		return
 	}
 
-	card, err := h.cardService.ProcessDebitTransaction(cardID, req.Amount, req.Description, req.MerchantName, req.Reference)
+	performedBy, ok := h.requirePerformer(c)
+	if !ok {
+		return
+	}
+
+	purchase := entities.PurchaseDetails{
+		MerchantName: req.MerchantName,
+		Category:     req.Category,
+		Currency:     entities.Currency(strings.ToUpper(req.Currency)),
+		IsOnline:     req.IsOnline,
+	}
+
+	card, err := h.cardService.ProcessDebitTransaction(cardID, req.Amount, req.Description, req.Reference, performedBy, purchase)
 	if err != nil {
 		status := h.getErrorStatus(err)
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, h.errorResponse(err))
 		return
 	}
 
@@ -514,7 +601,38 @@ func (h *Handler) getPaginationParams(c *gin.Context) (int, int) {
 	return page, pageSize
 }
 
+// requirePerformer gets who performs a card operation: the authenticated user or, for requests of
+// other services without a user, the internal service. It writes a 401 response when there is neither.
+func (h *Handler) requirePerformer(c *gin.Context) (string, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		userID = c.GetHeader("X-User-ID")
+	}
+	if userID != "" {
+		return userID, true
+	}
+	if c.GetBool("internal_service") {
+		return entities.InternalServicePerformer, true
+	}
+
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+	return "", false
+}
+
+// errorResponse builds the error body, including the reason code of card control rejections
+func (h *Handler) errorResponse(err error) gin.H {
+	if controlErr, ok := err.(*errors.CardControlError); ok {
+		return gin.H{"error": controlErr.Message, "reason": controlErr.Reason}
+	}
+	return gin.H{"error": err.Error()}
+}
+
 func (h *Handler) getErrorStatus(err error) int {
+	// Operations rejected by the card spending controls
+	if errors.IsCardControlError(err) {
+		return http.StatusUnprocessableEntity
+	}
+
 	// Handle specific domain errors
 	if errors.IsNotFoundError(err) {
 		return http.StatusNotFound
@@ -565,6 +683,8 @@ func (h *Handler) getErrorStatus(err error) int {
 // @Param charge body dto.CreditCardChargeWithInstallmentsRequest true "Charge with installments data"
 // @Success 201 {object} dto.ChargeWithInstallmentsResponse "Purchase created with installments"
 // @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 403 {object} map[string]string "User cannot operate this card"
+// @Failure 422 {object} map[string]string "Rejected by the card spending controls (includes reason code)"
 // @Failure 404 {object} map[string]string "Card not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/cards/{id}/charge-installments [post]
@@ -593,6 +713,9 @@ func (h *Handler) ChargeCardWithInstallments(c *gin.Context) {
 		InterestRate:      req.InterestRate,
 		AdminFee:          req.AdminFee,
 		Reference:         req.Reference,
+		Category:          req.Category,
+		Currency:          strings.ToUpper(req.Currency),
+		IsOnline:          req.IsOnline,
 	}
 
 	// Set user context from middleware
@@ -610,6 +733,10 @@ func (h *Handler) ChargeCardWithInstallments(c *gin.Context) {
 	// Create charge with installments
 	response, err := h.cardService.ChargeCardWithInstallments(installmentReq)
 	if err != nil {
+		if errors.IsCardControlError(err) || errors.IsPermissionError(err) {
+			c.JSON(h.getErrorStatus(err), h.errorResponse(err))
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
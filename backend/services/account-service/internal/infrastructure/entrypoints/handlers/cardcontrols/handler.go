@@ -0,0 +1,223 @@
+package cardcontrols
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/card/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for the spending controls of cards
+type Handler struct {
+	controlsService ports.CardControlsServiceInterface
+}
+
+// New creates a new card controls handler
+func New(controlsService ports.CardControlsServiceInterface) *Handler {
+	return &Handler{
+		controlsService: controlsService,
+	}
+}
+
+// GetControls gets the spending controls of a card
+// @Summary Get card controls
+// @Description Get the merchant and category blocks, online toggle, per-transaction cap, allowed currencies and freeze of a card
+// @Tags Card Controls
+// @Produce json
+// @Security BearerAuth
+// @Param cardId path string true "Card ID"
+// @Success 200 {object} dto.CardControlsResponse "Card controls"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot view this card"
+// @Failure 404 {object} map[string]string "Card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/cards/{cardId}/controls [get]
+func (h *Handler) GetControls(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	controls, err := h.controlsService.GetControls(c.Param("cardId"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCardControlsResponse(controls))
+}
+
+// UpdateControls updates the spending controls of a card
+// @Summary Update card controls
+// @Description Change the spending controls of a card. Omitted fields keep their current value.
+// @Tags Card Controls
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cardId path string true "Card ID"
+// @Param request body dto.UpdateCardControlsRequest true "Card controls"
+// @Success 200 {object} dto.CardControlsResponse "Card controls updated"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this account"
+// @Failure 404 {object} map[string]string "Card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/cards/{cardId}/controls [put]
+func (h *Handler) UpdateControls(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCardControlsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	controls, err := h.controlsService.UpdateControls(c.Param("cardId"), userID, &req)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCardControlsResponse(controls))
+}
+
+// FreezeCard temporarily freezes a card
+// @Summary Freeze card
+// @Description Reject every operation of the card until the given time, after which it is unfrozen automatically
+// @Tags Card Controls
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cardId path string true "Card ID"
+// @Param request body dto.FreezeCardRequest true "Freeze period"
+// @Success 200 {object} dto.CardControlsResponse "Card frozen"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this account"
+// @Failure 404 {object} map[string]string "Card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/cards/{cardId}/freeze [post]
+func (h *Handler) FreezeCard(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.FreezeCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	controls, err := h.controlsService.FreezeCard(c.Param("cardId"), userID, req.Until, req.Reason)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCardControlsResponse(controls))
+}
+
+// UnfreezeCard lifts the freeze of a card
+// @Summary Unfreeze card
+// @Description Lift the temporary freeze of a card before it expires
+// @Tags Card Controls
+// @Produce json
+// @Security BearerAuth
+// @Param cardId path string true "Card ID"
+// @Success 200 {object} dto.CardControlsResponse "Card unfrozen"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this account"
+// @Failure 404 {object} map[string]string "Card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/cards/{cardId}/unfreeze [post]
+func (h *Handler) UnfreezeCard(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	controls, err := h.controlsService.UnfreezeCard(c.Param("cardId"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCardControlsResponse(controls))
+}
+
+// GetRejections lists the operations rejected by the controls of a card
+// @Summary Get card control rejections
+// @Description List the operations rejected by the spending controls of a card, newest first, with their reason code
+// @Tags Card Controls
+// @Produce json
+// @Security BearerAuth
+// @Param cardId path string true "Card ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} dto.PaginatedCardControlRejectionResponse "Rejected operations"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot view this card"
+// @Failure 404 {object} map[string]string "Card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/cards/{cardId}/controls/rejections [get]
+func (h *Handler) GetRejections(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	rejections, total, err := h.controlsService.GetRejections(c.Param("cardId"), userID, page, pageSize)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPaginatedCardControlRejectionResponse(rejections, total, page, pageSize))
+}
+
+// requireUserID gets the authenticated user, writing a 401 response when missing
+func (h *Handler) requireUserID(c *gin.Context) (string, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		userID = c.GetHeader("X-User-ID")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return "", false
+		}
+	}
+	return userID, true
+}
+
+func (h *Handler) getErrorStatus(err error) int {
+	if errors.IsPermissionError(err) {
+		return http.StatusForbidden
+	}
+
+	if errors.IsValidationError(err) {
+		return http.StatusBadRequest
+	}
+
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
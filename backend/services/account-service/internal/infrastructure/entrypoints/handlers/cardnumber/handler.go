@@ -0,0 +1,104 @@
+package cardnumber
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/card/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles the administrative HTTP requests for card numbers
+type Handler struct {
+	numberService ports.CardNumberServiceInterface
+}
+
+// New creates a new card number handler
+func New(numberService ports.CardNumberServiceInterface) *Handler {
+	return &Handler{
+		numberService: numberService,
+	}
+}
+
+// RevealCardNumber decrypts the number of a card
+// @Summary Reveal card number
+// @Description Decrypt the number of a card. Restricted to administrators; every attempt is recorded in the access log with its reason.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cardId path string true "Card ID"
+// @Param request body dto.RevealCardNumberRequest true "Reason for the access"
+// @Success 200 {object} dto.RevealCardNumberResponse "Card number"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User is not an administrator"
+// @Failure 404 {object} map[string]string "Card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/admin/cards/{cardId}/reveal-number [post]
+func (h *Handler) RevealCardNumber(c *gin.Context) {
+	var req dto.RevealCardNumberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cardID := c.Param("cardId")
+	number, err := h.numberService.RevealCardNumber(cardID, c.GetString("user_id"), req.Reason, c.ClientIP())
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, dto.RevealCardNumberResponse{CardID: cardID, Number: number})
+}
+
+// GetAccessLog lists the accesses to the number of a card
+// @Summary Get card number access log
+// @Description List the attempts to reveal the number of a card, newest first. Restricted to administrators.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param cardId path string true "Card ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} dto.PaginatedCardNumberAccessResponse "Access log"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User is not an administrator"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/admin/cards/{cardId}/number-access-log [get]
+func (h *Handler) GetAccessLog(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	logs, total, err := h.numberService.GetAccessLog(c.Param("cardId"), page, pageSize)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPaginatedCardNumberAccessResponse(logs, total, page, pageSize))
+}
+
+func (h *Handler) getErrorStatus(err error) int {
+	if errors.IsValidationError(err) {
+		return http.StatusBadRequest
+	}
+
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,16 @@
+package dto
+
+// StartClosureRequest represents the request to start closing an account
+type StartClosureRequest struct {
+	Reason string `json:"reason,omitempty" binding:"omitempty,max=255"`
+}
+
+// TransferBalanceRequest represents the request to move the remaining balance to another account
+type TransferBalanceRequest struct {
+	TargetAccountID string `json:"target_account_id" binding:"required"`
+}
+
+// TransferInstallmentPlanRequest represents the request to move an installment plan to a card of another account
+type TransferInstallmentPlanRequest struct {
+	TargetCardID string `json:"target_card_id" binding:"required"`
+}
@@ -0,0 +1,320 @@
+package closure
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/closure/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for the account closure process
+type Handler struct {
+	closureService ports.AccountClosureServiceInterface
+}
+
+// New creates a new account closure handler
+func New(closureService ports.AccountClosureServiceInterface) *Handler {
+	return &Handler{
+		closureService: closureService,
+	}
+}
+
+// CheckClosure runs the pre-checks of closing an account
+// @Summary Check account closure
+// @Description List what prevents closing the account: remaining balance, card debts or credit balances and open installment plans
+// @Tags Account Closure
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Success 200 {object} entities.ClosureCheck "Closure pre-checks"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Only the owner can close the account"
+// @Failure 404 {object} map[string]string "Account not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/closure/check [get]
+func (h *Handler) CheckClosure(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	check, err := h.closureService.CheckClosure(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
+
+// StartClosure starts the closure process of an account
+// @Summary Start account closure
+// @Description Open the closure process of an account. Settlement steps are accepted while it is pending.
+// @Tags Account Closure
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param request body dto.StartClosureRequest false "Closure reason"
+// @Success 201 {object} entities.AccountClosure "Closure started"
+// @Failure 400 {object} map[string]string "Account already closed or closure in progress"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Only the owner can close the account"
+// @Failure 404 {object} map[string]string "Account not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/closure [post]
+func (h *Handler) StartClosure(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.StartClosureRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	closure, err := h.closureService.StartClosure(c.Param("id"), userID, req.Reason)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, closure)
+}
+
+// GetClosure gets the latest closure of an account
+// @Summary Get account closure
+// @Description Get the latest closure of an account with its audit trail and, once completed, its closing statement
+// @Tags Account Closure
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Success 200 {object} entities.AccountClosure "Account closure"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Only the owner can see the closure"
+// @Failure 404 {object} map[string]string "Account or closure not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/closure [get]
+func (h *Handler) GetClosure(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	closure, err := h.closureService.GetClosure(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, closure)
+}
+
+// TransferBalance moves the remaining balance to another account
+// @Summary Transfer closing balance
+// @Description Move the whole balance of the account to another active account of the owner in the same currency. A negative balance is covered from that account.
+// @Tags Account Closure
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param request body dto.TransferBalanceRequest true "Target account"
+// @Success 200 {object} entities.AccountClosure "Balance transferred"
+// @Failure 400 {object} map[string]string "Invalid request data or no closure in progress"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Only the owner can close the account"
+// @Failure 404 {object} map[string]string "Account not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/closure/transfer-balance [post]
+func (h *Handler) TransferBalance(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.TransferBalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	closure, err := h.closureService.TransferBalance(c.Param("id"), userID, req.TargetAccountID)
+	h.respond(c, closure, err)
+}
+
+// SettleCard settles a credit card of the account
+// @Summary Settle card
+// @Description Pay the debt of a credit card from the account balance, or refund its credit balance to the account
+// @Tags Account Closure
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param cardId path string true "Card ID"
+// @Success 200 {object} entities.AccountClosure "Card settled"
+// @Failure 400 {object} map[string]string "Nothing to settle, insufficient balance or open installment plans"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Only the owner can close the account"
+// @Failure 404 {object} map[string]string "Account or card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/closure/cards/{cardId}/settle [post]
+func (h *Handler) SettleCard(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	closure, err := h.closureService.SettleCard(c.Param("id"), userID, c.Param("cardId"))
+	h.respond(c, closure, err)
+}
+
+// CancelInstallmentPlan cancels an open installment plan of the account
+// @Summary Cancel installment plan on closure
+// @Description Cancel an active installment plan on a card of the account
+// @Tags Account Closure
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param planId path string true "Installment plan ID"
+// @Success 200 {object} entities.AccountClosure "Plan cancelled"
+// @Failure 400 {object} map[string]string "Plan cannot be cancelled or no closure in progress"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Only the owner can close the account"
+// @Failure 404 {object} map[string]string "Account or plan not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/closure/installment-plans/{planId}/cancel [post]
+func (h *Handler) CancelInstallmentPlan(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	closure, err := h.closureService.CancelInstallmentPlan(c.Param("id"), userID, c.Param("planId"))
+	h.respond(c, closure, err)
+}
+
+// TransferInstallmentPlan moves an open installment plan to a card of another account
+// @Summary Transfer installment plan on closure
+// @Description Move an open installment plan and its remaining amount to an active credit card of another account of the owner
+// @Tags Account Closure
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param planId path string true "Installment plan ID"
+// @Param request body dto.TransferInstallmentPlanRequest true "Target card"
+// @Success 200 {object} entities.AccountClosure "Plan transferred"
+// @Failure 400 {object} map[string]string "Invalid target card or no closure in progress"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Only the owner can close the account"
+// @Failure 404 {object} map[string]string "Account, plan or card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/closure/installment-plans/{planId}/transfer [post]
+func (h *Handler) TransferInstallmentPlan(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.TransferInstallmentPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	closure, err := h.closureService.TransferInstallmentPlan(c.Param("id"), userID, c.Param("planId"), req.TargetCardID)
+	h.respond(c, closure, err)
+}
+
+// CompleteClosure closes the account
+// @Summary Complete account closure
+// @Description Re-run the pre-checks and, when nothing is pending, generate the closing statement, deactivate the cards and mark the account closed
+// @Tags Account Closure
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Success 200 {object} entities.AccountClosure "Account closed with its closing statement"
+// @Failure 400 {object} map[string]string "Pending items or no closure in progress"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Only the owner can close the account"
+// @Failure 404 {object} map[string]string "Account not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/closure/complete [post]
+func (h *Handler) CompleteClosure(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	closure, err := h.closureService.CompleteClosure(c.Param("id"), userID)
+	h.respond(c, closure, err)
+}
+
+// CancelClosure cancels the closure in progress
+// @Summary Cancel account closure
+// @Description Stop the closure in progress. Settlement steps already done are not reverted.
+// @Tags Account Closure
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Success 200 {object} entities.AccountClosure "Closure cancelled"
+// @Failure 400 {object} map[string]string "No closure in progress"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Only the owner can close the account"
+// @Failure 404 {object} map[string]string "Account not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/closure/cancel [post]
+func (h *Handler) CancelClosure(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	closure, err := h.closureService.CancelClosure(c.Param("id"), userID)
+	h.respond(c, closure, err)
+}
+
+// respond writes the closure after a settlement step, or the error it failed with
+func (h *Handler) respond(c *gin.Context, closure *entities.AccountClosure, err error) {
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, closure)
+}
+
+// requireUserID gets the authenticated user, writing a 401 response when missing
+func (h *Handler) requireUserID(c *gin.Context) (string, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		userID = c.GetHeader("X-User-ID")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return "", false
+		}
+	}
+	return userID, true
+}
+
+func (h *Handler) getErrorStatus(err error) int {
+	if errors.IsPermissionError(err) {
+		return http.StatusForbidden
+	}
+
+	if errors.IsValidationError(err) || errors.IsBusinessLogicError(err) {
+		return http.StatusBadRequest
+	}
+
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
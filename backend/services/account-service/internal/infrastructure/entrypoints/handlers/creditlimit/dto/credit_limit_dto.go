@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	carddto "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/card/dto"
+)
+
+// CreditLimitChangeRequestBody represents the request to change the limit of a credit card account or card
+type CreditLimitChangeRequestBody struct {
+	CardID      *string `json:"card_id,omitempty"` // Empty to change the limit of the account
+	CreditLimit float64 `json:"credit_limit" binding:"min=0"`
+	Reason      string  `json:"reason,omitempty" binding:"omitempty,max=255"`
+}
+
+// ReviewCreditLimitChangeRequest represents the decision of a treasurer on a pending change
+type ReviewCreditLimitChangeRequest struct {
+	Note string `json:"note,omitempty" binding:"omitempty,max=255"` // Required to reject
+}
+
+// PaginatedCreditLimitChangeResponse represents a paginated list of change requests
+type PaginatedCreditLimitChangeResponse struct {
+	Data       []*entities.CreditLimitChangeRequest `json:"data"`
+	Pagination carddto.PaginationMeta               `json:"pagination"`
+}
+
+// CreditLimitHistoryResponse represents the history of limits of an account or card
+type CreditLimitHistoryResponse struct {
+	AccountID string                         `json:"account_id"`
+	CardID    *string                        `json:"card_id,omitempty"`
+	History   []*entities.CreditLimitHistory `json:"history"`
+}
+
+// CreditLimitAtResponse represents the limit that applied at a date
+type CreditLimitAtResponse struct {
+	AccountID     string     `json:"account_id"`
+	CardID        *string    `json:"card_id,omitempty"`
+	At            time.Time  `json:"at"`
+	CreditLimit   *float64   `json:"credit_limit"` // Null when the account or card did not exist yet
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+}
+
+// ToPaginatedCreditLimitChangeResponse converts change requests with pagination info to response
+func ToPaginatedCreditLimitChangeResponse(requests []*entities.CreditLimitChangeRequest, total int64, page, pageSize int) PaginatedCreditLimitChangeResponse {
+	if requests == nil {
+		requests = []*entities.CreditLimitChangeRequest{}
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return PaginatedCreditLimitChangeResponse{
+		Data: requests,
+		Pagination: carddto.PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  total,
+			TotalPages:  totalPages,
+		},
+	}
+}
+
+// ToCreditLimitAtResponse converts the history entry in effect at a date to response
+func ToCreditLimitAtResponse(accountID string, cardID *string, at time.Time, entry *entities.CreditLimitHistory) CreditLimitAtResponse {
+	response := CreditLimitAtResponse{AccountID: accountID, CardID: cardID, At: at}
+	if entry != nil {
+		response.CreditLimit = &entry.CreditLimit
+		response.EffectiveFrom = &entry.EffectiveFrom
+		response.EffectiveTo = entry.EffectiveTo
+	}
+	return response
+}
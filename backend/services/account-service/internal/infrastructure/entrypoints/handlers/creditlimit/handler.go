@@ -0,0 +1,332 @@
+package creditlimit
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	accountdto "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/account/dto"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/creditlimit/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for credit limit changes and their history
+type Handler struct {
+	limitService ports.CreditLimitServiceInterface
+}
+
+// New creates a new credit limit handler
+func New(limitService ports.CreditLimitServiceInterface) *Handler {
+	return &Handler{
+		limitService: limitService,
+	}
+}
+
+// RequestChange requests a new credit limit for an account or card
+// @Summary Request credit limit change
+// @Description Request a new limit for a credit card account or, with card_id, for a credit card. Decreases below the debt plus installment commitments are rejected; increases above the approval threshold wait for a treasurer, the rest apply right away.
+// @Tags Credit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param request body dto.CreditLimitChangeRequestBody true "New credit limit"
+// @Success 200 {object} entities.CreditLimitChangeRequest "Limit changed"
+// @Success 202 {object} entities.CreditLimitChangeRequest "Increase waiting for treasury approval"
+// @Failure 400 {object} map[string]string "Invalid request data or limit below the credit held"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this account"
+// @Failure 404 {object} map[string]string "Account or card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/credit-limit-requests [post]
+func (h *Handler) RequestChange(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreditLimitChangeRequestBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := h.limitService.RequestChange(c.Param("id"), userID, req.CardID, req.CreditLimit, req.Reason)
+	h.respondChange(c, request, err)
+}
+
+// UpdateAccountCreditLimit changes the limit of a credit card account through a change request
+// @Summary Update credit limit
+// @Description Request a new limit for a credit card account. Kept for existing clients; it follows the rules of the credit limit requests.
+// @Tags Credit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param request body accountdto.UpdateCreditLimitRequest true "Credit limit update data"
+// @Success 200 {object} entities.CreditLimitChangeRequest "Limit changed"
+// @Success 202 {object} entities.CreditLimitChangeRequest "Increase waiting for treasury approval"
+// @Failure 400 {object} map[string]string "Invalid request data or limit below the credit used"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this account"
+// @Failure 404 {object} map[string]string "Account not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/credit-limit [put]
+func (h *Handler) UpdateAccountCreditLimit(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req accountdto.UpdateCreditLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := h.limitService.RequestChange(c.Param("id"), userID, nil, req.CreditLimit, "")
+	h.respondChange(c, request, err)
+}
+
+// GetRequestsByAccount lists the credit limit change requests of an account
+// @Summary List credit limit change requests
+// @Description List the limit change requests of an account and its cards, newest first
+// @Tags Credit
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param status query string false "Filter by status (pending, applied, rejected)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} dto.PaginatedCreditLimitChangeResponse "Change requests"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot view this account"
+// @Failure 404 {object} map[string]string "Account not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/credit-limit-requests [get]
+func (h *Handler) GetRequestsByAccount(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	page, pageSize := getPagination(c)
+	requests, total, err := h.limitService.GetRequestsByAccount(c.Param("id"), userID, c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPaginatedCreditLimitChangeResponse(requests, total, page, pageSize))
+}
+
+// GetHistory lists the limits of an account or card, or the one that applied at a date
+// @Summary Get credit limit history
+// @Description List the limits of a credit card account or, with card_id, of a credit card, newest first. With at, return the limit that applied at that date.
+// @Tags Credit
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param card_id query string false "Card ID"
+// @Param at query string false "Date (YYYY-MM-DD or RFC3339)"
+// @Success 200 {object} dto.CreditLimitHistoryResponse "Limit history"
+// @Success 200 {object} dto.CreditLimitAtResponse "Limit at the date"
+// @Failure 400 {object} map[string]string "Invalid date"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot view this account"
+// @Failure 404 {object} map[string]string "Account or card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/credit-limit-history [get]
+func (h *Handler) GetHistory(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	accountID := c.Param("id")
+	var cardID *string
+	if id := c.Query("card_id"); id != "" {
+		cardID = &id
+	}
+
+	if value := c.Query("at"); value != "" {
+		at, err := parseDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid at date, use YYYY-MM-DD or RFC3339"})
+			return
+		}
+
+		entry, err := h.limitService.GetLimitAt(accountID, userID, cardID, at)
+		if err != nil {
+			c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.ToCreditLimitAtResponse(accountID, cardID, at, entry))
+		return
+	}
+
+	history, err := h.limitService.GetHistory(accountID, userID, cardID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreditLimitHistoryResponse{AccountID: accountID, CardID: cardID, History: history})
+}
+
+// GetPendingRequests lists the increases waiting for approval
+// @Summary List pending credit limit increases
+// @Description List the limit increases above the approval threshold waiting for a treasurer, oldest first. Restricted to treasurers.
+// @Tags Treasury
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} dto.PaginatedCreditLimitChangeResponse "Pending requests"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User is not a treasurer"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/treasury/credit-limit-requests [get]
+func (h *Handler) GetPendingRequests(c *gin.Context) {
+	page, pageSize := getPagination(c)
+	requests, total, err := h.limitService.GetPendingRequests(page, pageSize)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPaginatedCreditLimitChangeResponse(requests, total, page, pageSize))
+}
+
+// ApproveRequest approves a pending increase
+// @Summary Approve credit limit increase
+// @Description Apply a pending limit increase. Restricted to treasurers other than the requester.
+// @Tags Treasury
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param requestId path string true "Change request ID"
+// @Param request body dto.ReviewCreditLimitChangeRequest false "Review note"
+// @Success 200 {object} entities.CreditLimitChangeRequest "Limit changed"
+// @Failure 400 {object} map[string]string "Request not pending or limit below the credit held"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User is not a treasurer or made the request"
+// @Failure 404 {object} map[string]string "Change request not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/treasury/credit-limit-requests/{requestId}/approve [post]
+func (h *Handler) ApproveRequest(c *gin.Context) {
+	h.review(c, h.limitService.ApproveRequest)
+}
+
+// RejectRequest rejects a pending increase
+// @Summary Reject credit limit increase
+// @Description Reject a pending limit increase with a note. Restricted to treasurers other than the requester.
+// @Tags Treasury
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param requestId path string true "Change request ID"
+// @Param request body dto.ReviewCreditLimitChangeRequest true "Rejection note"
+// @Success 200 {object} entities.CreditLimitChangeRequest "Request rejected"
+// @Failure 400 {object} map[string]string "Request not pending or missing note"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User is not a treasurer or made the request"
+// @Failure 404 {object} map[string]string "Change request not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/treasury/credit-limit-requests/{requestId}/reject [post]
+func (h *Handler) RejectRequest(c *gin.Context) {
+	h.review(c, h.limitService.RejectRequest)
+}
+
+// review runs a treasury decision on a change request
+func (h *Handler) review(c *gin.Context, decide func(requestID, treasurerID, note string) (*entities.CreditLimitChangeRequest, error)) {
+	treasurerID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReviewCreditLimitChangeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	request, err := decide(c.Param("requestId"), treasurerID, req.Note)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// respondChange writes a change request: 200 when applied, 202 when waiting for approval
+func (h *Handler) respondChange(c *gin.Context, request *entities.CreditLimitChangeRequest, err error) {
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if request.IsPending() {
+		c.JSON(http.StatusAccepted, request)
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// requireUserID gets the authenticated user, writing a 401 response when missing
+func (h *Handler) requireUserID(c *gin.Context) (string, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		userID = c.GetHeader("X-User-ID")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return "", false
+		}
+	}
+	return userID, true
+}
+
+func (h *Handler) getErrorStatus(err error) int {
+	if errors.IsPermissionError(err) {
+		return http.StatusForbidden
+	}
+
+	if errors.IsValidationError(err) || errors.IsBusinessLogicError(err) {
+		return http.StatusBadRequest
+	}
+
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
+
+// getPagination reads the page and page size query parameters
+func getPagination(c *gin.Context) (int, int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	return page, pageSize
+}
+
+// parseDate parses a date or timestamp; plain dates refer to the end of that day
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
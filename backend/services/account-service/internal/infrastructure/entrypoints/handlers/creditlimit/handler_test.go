@@ -0,0 +1,272 @@
+package creditlimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/fintrack/account-service/internal/core/service"
+)
+
+// MockAccountRepository implements the account lookups of the credit limit service for testing
+type MockAccountRepository struct {
+	ports.AccountRepositoryInterface
+	accounts map[string]*entities.Account
+}
+
+func (m *MockAccountRepository) GetByID(id string) (*entities.Account, error) {
+	account, exists := m.accounts[id]
+	if !exists {
+		return nil, fmt.Errorf("account not found")
+	}
+	return account, nil
+}
+
+// MockCreditLimitRepository implements CreditLimitRepositoryInterface for testing
+type MockCreditLimitRepository struct {
+	requests []*entities.CreditLimitChangeRequest
+	history  []*entities.CreditLimitHistory
+	accounts *MockAccountRepository
+}
+
+func (m *MockCreditLimitRepository) CreateRequest(request *entities.CreditLimitChangeRequest) error {
+	if request.ID == "" {
+		request.ID = uuid.NewString()
+	}
+	m.requests = append(m.requests, request)
+	return nil
+}
+
+func (m *MockCreditLimitRepository) GetRequestByID(requestID string) (*entities.CreditLimitChangeRequest, error) {
+	for _, request := range m.requests {
+		if request.ID == requestID {
+			return request, nil
+		}
+	}
+	return nil, fmt.Errorf("credit limit change request not found")
+}
+
+func (m *MockCreditLimitRepository) UpdateRequest(request *entities.CreditLimitChangeRequest) error {
+	return nil
+}
+
+func (m *MockCreditLimitRepository) GetPendingByTarget(accountID string, cardID *string) (*entities.CreditLimitChangeRequest, error) {
+	for _, request := range m.requests {
+		if request.AccountID == accountID && request.CardID == nil && request.IsPending() {
+			return request, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockCreditLimitRepository) GetRequestsByAccount(accountID, status string, limit, offset int) ([]*entities.CreditLimitChangeRequest, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockCreditLimitRepository) GetRequestsByStatus(status string, limit, offset int) ([]*entities.CreditLimitChangeRequest, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockCreditLimitRepository) GetHistory(accountID string, cardID *string) ([]*entities.CreditLimitHistory, error) {
+	var history []*entities.CreditLimitHistory
+	for _, entry := range m.history {
+		if entry.AccountID == accountID {
+			history = append(history, entry)
+		}
+	}
+	return history, nil
+}
+
+func (m *MockCreditLimitRepository) ApplyChange(request *entities.CreditLimitChangeRequest, entries ...*entities.CreditLimitHistory) error {
+	m.history = append(m.history, entries...)
+	if account, exists := m.accounts.accounts[request.AccountID]; exists {
+		account.CreditLimit = &request.RequestedLimit
+	}
+	return nil
+}
+
+// MockMembershipService lets only the owner of an account operate it
+type MockMembershipService struct {
+	ports.MembershipServiceInterface
+}
+
+func (m *MockMembershipService) CanViewAccount(account *entities.Account, userID string) (bool, error) {
+	return account.UserID == userID, nil
+}
+
+func (m *MockMembershipService) CanOperateAccount(account *entities.Account, userID string) (bool, error) {
+	return account.UserID == userID, nil
+}
+
+// MockCardRepository holds accounts without cards
+type MockCardRepository struct {
+	ports.CardRepositoryInterface
+}
+
+func (m *MockCardRepository) GetByAccountWithInstallmentPlans(accountID string, limit, offset int) ([]*entities.Card, int64, error) {
+	return nil, 0, nil
+}
+
+// Verify interface compliance
+var _ ports.CreditLimitRepositoryInterface = (*MockCreditLimitRepository)(nil)
+
+// creditLimitApprovalThreshold is the increase that needs a treasurer in these tests
+const creditLimitApprovalThreshold = 100000
+
+// setupCreditLimitRouter serves the credit limit route with the real service over mock repositories
+func setupCreditLimitRouter() (*gin.Engine, *MockAccountRepository, *MockCreditLimitRepository) {
+	gin.SetMode(gin.TestMode)
+
+	accountRepo := &MockAccountRepository{accounts: make(map[string]*entities.Account)}
+	limitRepo := &MockCreditLimitRepository{accounts: accountRepo}
+	limitService := service.NewCreditLimitService(limitRepo, accountRepo, &MockCardRepository{}, &MockMembershipService{}, creditLimitApprovalThreshold)
+
+	router := gin.New()
+	router.PUT("/api/accounts/:id/credit-limit", New(limitService).UpdateAccountCreditLimit)
+	return router, accountRepo, limitRepo
+}
+
+// newCreditAccount adds a credit card account with a limit of 5000 and the given debt
+func newCreditAccount(accountRepo *MockAccountRepository, debt float64) *entities.Account {
+	creditLimit := 5000.0
+	account := &entities.Account{
+		ID:          uuid.NewString(),
+		UserID:      uuid.NewString(),
+		AccountType: entities.AccountTypeCredit,
+		Name:        "Visa Galicia",
+		Currency:    entities.CurrencyARS,
+		Balance:     -debt,
+		CreditLimit: &creditLimit,
+		IsActive:    true,
+		CreatedAt:   time.Now().AddDate(-1, 0, 0),
+	}
+	accountRepo.accounts[account.ID] = account
+	return account
+}
+
+func TestUpdateAccountCreditLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		debt           float64
+		creditLimit    float64
+		otherUser      bool
+		expectedStatus int
+		expectedLimit  float64 // Limit of the account after the request
+		expectedState  entities.CreditLimitChangeStatus
+	}{
+		{
+			name:           "limit applies immediately",
+			debt:           1500,
+			creditLimit:    8000,
+			expectedStatus: http.StatusOK,
+			expectedLimit:  8000,
+			expectedState:  entities.CreditLimitChangeStatusApplied,
+		},
+		{
+			name:           "decrease above the debt applies immediately",
+			debt:           1500,
+			creditLimit:    2000,
+			expectedStatus: http.StatusOK,
+			expectedLimit:  2000,
+			expectedState:  entities.CreditLimitChangeStatusApplied,
+		},
+		{
+			name:           "increase above the threshold waits for approval",
+			debt:           1500,
+			creditLimit:    5000 + creditLimitApprovalThreshold + 1,
+			expectedStatus: http.StatusAccepted,
+			expectedLimit:  5000,
+			expectedState:  entities.CreditLimitChangeStatusPending,
+		},
+		{
+			name:           "limit below the debt is rejected",
+			debt:           3000,
+			creditLimit:    2999,
+			expectedStatus: http.StatusBadRequest,
+			expectedLimit:  5000,
+			expectedState:  entities.CreditLimitChangeStatusRejected,
+		},
+		{
+			name:           "user without access to the account",
+			debt:           1500,
+			creditLimit:    8000,
+			otherUser:      true,
+			expectedStatus: http.StatusForbidden,
+			expectedLimit:  5000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, accountRepo, limitRepo := setupCreditLimitRouter()
+			account := newCreditAccount(accountRepo, tt.debt)
+
+			userID := account.UserID
+			if tt.otherUser {
+				userID = uuid.NewString()
+			}
+
+			body, _ := json.Marshal(map[string]float64{"credit_limit": tt.creditLimit})
+			req := httptest.NewRequest(http.MethodPut, "/api/accounts/"+account.ID+"/credit-limit", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-User-ID", userID)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("UpdateAccountCreditLimit() status = %v, want %v: %s", w.Code, tt.expectedStatus, w.Body.String())
+			}
+			if got := *account.CreditLimit; got != tt.expectedLimit {
+				t.Errorf("UpdateAccountCreditLimit() account limit = %v, want %v", got, tt.expectedLimit)
+			}
+
+			if tt.expectedState == "" {
+				if len(limitRepo.requests) != 0 {
+					t.Errorf("UpdateAccountCreditLimit() recorded %d requests, want none", len(limitRepo.requests))
+				}
+				return
+			}
+			if len(limitRepo.requests) != 1 {
+				t.Fatalf("UpdateAccountCreditLimit() recorded %d requests, want 1", len(limitRepo.requests))
+			}
+			if got := limitRepo.requests[0].Status; got != tt.expectedState {
+				t.Errorf("UpdateAccountCreditLimit() request status = %v, want %v", got, tt.expectedState)
+			}
+
+			if w.Code == http.StatusBadRequest {
+				return
+			}
+			var response entities.CreditLimitChangeRequest
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if response.RequestedLimit != tt.creditLimit || response.Status != tt.expectedState {
+				t.Errorf("UpdateAccountCreditLimit() response = %v %v, want %v %v", response.RequestedLimit, response.Status, tt.creditLimit, tt.expectedState)
+			}
+		})
+	}
+}
+
+func TestUpdateAccountCreditLimitUnauthenticated(t *testing.T) {
+	router, accountRepo, _ := setupCreditLimitRouter()
+	account := newCreditAccount(accountRepo, 0)
+
+	body, _ := json.Marshal(map[string]float64{"credit_limit": 8000})
+	req := httptest.NewRequest(http.MethodPut, "/api/accounts/"+account.ID+"/credit-limit", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("UpdateAccountCreditLimit() status = %v, want %v", w.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// CreateCreditLineRequest represents the request to create a shared credit line
+type CreateCreditLineRequest struct {
+	Name       string  `json:"name" binding:"required,min=2,max=100"`
+	TotalLimit float64 `json:"total_limit" binding:"required,gt=0"`
+}
+
+// UpdateCreditLineRequest represents the request to update a credit line
+type UpdateCreditLineRequest struct {
+	Name       *string  `json:"name,omitempty" binding:"omitempty,min=2,max=100"`
+	TotalLimit *float64 `json:"total_limit,omitempty" binding:"omitempty,gt=0"`
+}
+
+// AssignCardRequest represents the request to add a card to a credit line
+type AssignCardRequest struct {
+	IsAdditional bool     `json:"is_additional"`
+	SubLimit     *float64 `json:"sub_limit,omitempty" binding:"omitempty,gt=0"` // Optional cap within the line limit
+}
+
+// RemoveCardRequest represents the request to take a card out of a credit line
+type RemoveCardRequest struct {
+	CreditLimit *float64 `json:"credit_limit,omitempty" binding:"omitempty,min=0"` // Required when the card has no own limit
+}
+
+// CreditLineCardResponse represents a card of a credit line
+type CreditLineCardResponse struct {
+	ID              string   `json:"id"`
+	HolderName      string   `json:"holder_name"`
+	MaskedNumber    string   `json:"masked_number"`
+	LastFourDigits  string   `json:"last_four_digits"`
+	Status          string   `json:"status"`
+	IsAdditional    bool     `json:"is_additional"`
+	SubLimit        *float64 `json:"sub_limit,omitempty"`
+	Balance         float64  `json:"balance"`
+	AvailableCredit float64  `json:"available_credit"`
+}
+
+// CreditLineResponse represents a credit line in API responses
+type CreditLineResponse struct {
+	ID                     string                   `json:"id"`
+	AccountID              string                   `json:"account_id"`
+	Name                   string                   `json:"name"`
+	TotalLimit             float64                  `json:"total_limit"`
+	UsedCredit             float64                  `json:"used_credit"`             // Debt of all the cards of the line
+	InstallmentCommitments float64                  `json:"installment_commitments"` // Still owed on active installment plans
+	AvailableCredit        float64                  `json:"available_credit"`        // Remaining credit considering installment commitments
+	Cards                  []CreditLineCardResponse `json:"cards"`
+	CreatedAt              time.Time                `json:"created_at"`
+	UpdatedAt              time.Time                `json:"updated_at"`
+}
+
+// CreditLineListResponse represents a list of credit lines
+type CreditLineListResponse struct {
+	CreditLines []CreditLineResponse `json:"credit_lines"`
+	Total       int                  `json:"total"`
+}
+
+// ToCreditLineResponse converts a CreditLine entity to CreditLineResponse
+func ToCreditLineResponse(creditLine *entities.CreditLine) CreditLineResponse {
+	cards := make([]CreditLineCardResponse, len(creditLine.Cards))
+	for i, card := range creditLine.Cards {
+		card.CreditLine = creditLine
+		cards[i] = CreditLineCardResponse{
+			ID:              card.ID,
+			HolderName:      card.HolderName,
+			MaskedNumber:    card.MaskedNumber,
+			LastFourDigits:  card.LastFourDigits,
+			Status:          string(card.Status),
+			IsAdditional:    card.IsAdditional,
+			SubLimit:        card.SubLimit,
+			Balance:         card.Balance,
+			AvailableCredit: card.GetAvailableCreditWithInstallments(),
+		}
+	}
+
+	return CreditLineResponse{
+		ID:                     creditLine.ID,
+		AccountID:              creditLine.AccountID,
+		Name:                   creditLine.Name,
+		TotalLimit:             creditLine.TotalLimit,
+		UsedCredit:             creditLine.GetUsedCredit(nil),
+		InstallmentCommitments: creditLine.GetTotalInstallmentCommitments(nil),
+		AvailableCredit:        creditLine.GetAvailableCreditWithInstallments(nil),
+		Cards:                  cards,
+		CreatedAt:              creditLine.CreatedAt,
+		UpdatedAt:              creditLine.UpdatedAt,
+	}
+}
+
+// ToCreditLineListResponse converts a list of CreditLine entities to CreditLineListResponse
+func ToCreditLineListResponse(creditLines []*entities.CreditLine) CreditLineListResponse {
+	responses := make([]CreditLineResponse, len(creditLines))
+	for i, creditLine := range creditLines {
+		responses[i] = ToCreditLineResponse(creditLine)
+	}
+	return CreditLineListResponse{
+		CreditLines: responses,
+		Total:       len(creditLines),
+	}
+}
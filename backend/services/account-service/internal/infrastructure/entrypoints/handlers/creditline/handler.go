@@ -0,0 +1,265 @@
+package creditline
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	carddto "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/card/dto"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/creditline/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for credit lines shared by several cards
+type Handler struct {
+	creditLineService ports.CreditLineServiceInterface
+}
+
+// New creates a new credit line handler
+func New(creditLineService ports.CreditLineServiceInterface) *Handler {
+	return &Handler{
+		creditLineService: creditLineService,
+	}
+}
+
+// CreateCreditLine creates a credit line for an account
+// @Summary Create credit line
+// @Description Create a credit limit shared by several credit cards of the account (primary and additional cards)
+// @Tags Credit Lines
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param request body dto.CreateCreditLineRequest true "Credit line data"
+// @Success 201 {object} dto.CreditLineResponse "Credit line created"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this account"
+// @Failure 404 {object} map[string]string "Account not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/credit-lines [post]
+func (h *Handler) CreateCreditLine(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateCreditLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	creditLine, err := h.creditLineService.CreateCreditLine(c.Param("id"), userID, req.Name, req.TotalLimit)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToCreditLineResponse(creditLine))
+}
+
+// GetCreditLinesByAccount lists the credit lines of an account
+// @Summary List account credit lines
+// @Description List the credit lines of an account with their cards and usage
+// @Tags Credit Lines
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Success 200 {object} dto.CreditLineListResponse "Credit lines"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot view this account"
+// @Failure 404 {object} map[string]string "Account not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/credit-lines [get]
+func (h *Handler) GetCreditLinesByAccount(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	creditLines, err := h.creditLineService.GetCreditLinesByAccount(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCreditLineListResponse(creditLines))
+}
+
+// GetCreditLine gets a credit line
+// @Summary Get credit line
+// @Description Get a credit line with its cards, used and available credit
+// @Tags Credit Lines
+// @Produce json
+// @Security BearerAuth
+// @Param creditLineId path string true "Credit line ID"
+// @Success 200 {object} dto.CreditLineResponse "Credit line"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot view this account"
+// @Failure 404 {object} map[string]string "Credit line not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/credit-lines/{creditLineId} [get]
+func (h *Handler) GetCreditLine(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	creditLine, err := h.creditLineService.GetCreditLine(c.Param("creditLineId"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCreditLineResponse(creditLine))
+}
+
+// UpdateCreditLine updates a credit line
+// @Summary Update credit line
+// @Description Change the name or total limit of a credit line. The limit cannot be lower than the credit used.
+// @Tags Credit Lines
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param creditLineId path string true "Credit line ID"
+// @Param request body dto.UpdateCreditLineRequest true "Credit line data"
+// @Success 200 {object} dto.CreditLineResponse "Credit line updated"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this account"
+// @Failure 404 {object} map[string]string "Credit line not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/credit-lines/{creditLineId} [put]
+func (h *Handler) UpdateCreditLine(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCreditLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Name == nil && req.TotalLimit == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at least one field must be provided for update"})
+		return
+	}
+
+	creditLine, err := h.creditLineService.UpdateCreditLine(c.Param("creditLineId"), userID, req.Name, req.TotalLimit)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCreditLineResponse(creditLine))
+}
+
+// AssignCard adds a card to a credit line
+// @Summary Assign card to credit line
+// @Description Add a credit card of the account to the line, or update its additional flag and sub-limit
+// @Tags Credit Lines
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param creditLineId path string true "Credit line ID"
+// @Param cardId path string true "Card ID"
+// @Param request body dto.AssignCardRequest true "Card settings within the line"
+// @Success 200 {object} carddto.CardResponse "Card assigned"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this account"
+// @Failure 404 {object} map[string]string "Credit line or card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/credit-lines/{creditLineId}/cards/{cardId} [put]
+func (h *Handler) AssignCard(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AssignCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	card, err := h.creditLineService.AssignCard(c.Param("creditLineId"), c.Param("cardId"), userID, req.IsAdditional, req.SubLimit)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, carddto.ToCardResponse(card))
+}
+
+// RemoveCard takes a card out of a credit line
+// @Summary Remove card from credit line
+// @Description Take a card out of the line. A credit limit must be given when the card has no own limit.
+// @Tags Credit Lines
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param creditLineId path string true "Credit line ID"
+// @Param cardId path string true "Card ID"
+// @Param request body dto.RemoveCardRequest false "Own credit limit for the card"
+// @Success 200 {object} carddto.CardResponse "Card removed from the line"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot operate this account"
+// @Failure 404 {object} map[string]string "Credit line or card not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/credit-lines/{creditLineId}/cards/{cardId} [delete]
+func (h *Handler) RemoveCard(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.RemoveCardRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	card, err := h.creditLineService.RemoveCard(c.Param("creditLineId"), c.Param("cardId"), userID, req.CreditLimit)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, carddto.ToCardResponse(card))
+}
+
+// requireUserID gets the authenticated user, writing a 401 response when missing
+func (h *Handler) requireUserID(c *gin.Context) (string, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		userID = c.GetHeader("X-User-ID")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return "", false
+		}
+	}
+	return userID, true
+}
+
+func (h *Handler) getErrorStatus(err error) int {
+	if errors.IsPermissionError(err) {
+		return http.StatusForbidden
+	}
+
+	if errors.IsValidationError(err) {
+		return http.StatusBadRequest
+	}
+
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
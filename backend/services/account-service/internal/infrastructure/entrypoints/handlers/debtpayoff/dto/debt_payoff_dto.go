@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// DebtPayoffRequest represents the budget and strategy of a payoff simulation
+type DebtPayoffRequest struct {
+	Strategy        string   `json:"strategy,omitempty" binding:"omitempty,oneof=avalanche snowball custom"` // Avalanche by default
+	MonthlyBudget   float64  `json:"monthly_budget" binding:"required,gt=0"`
+	Currency        string   `json:"currency,omitempty" binding:"omitempty,len=3"`                    // ARS by default
+	CustomOrder     []string `json:"custom_order,omitempty" binding:"omitempty,max=50,dive,required"` // Card or installment plan IDs, for the custom strategy
+	CardMonthlyRate *float64 `json:"card_monthly_rate,omitempty" binding:"omitempty,min=0,max=100"`   // Percent; the configured rate when missing
+}
+
+// DebtPayoffPlansResponse represents the saved payoff plans of a user
+type DebtPayoffPlansResponse struct {
+	Data []*entities.DebtPayoffPlan `json:"data"`
+}
+
+// ToOptions converts the request to simulation options
+func (r DebtPayoffRequest) ToOptions() entities.DebtPayoffOptions {
+	return entities.DebtPayoffOptions{
+		Strategy:        entities.DebtPayoffStrategy(r.Strategy),
+		MonthlyBudget:   r.MonthlyBudget,
+		Currency:        entities.Currency(r.Currency),
+		CustomOrder:     r.CustomOrder,
+		CardMonthlyRate: r.CardMonthlyRate,
+	}
+}
+
+// ToDebtPayoffPlansResponse converts payoff plans to response
+func ToDebtPayoffPlansResponse(plans []*entities.DebtPayoffPlan) DebtPayoffPlansResponse {
+	if plans == nil {
+		plans = []*entities.DebtPayoffPlan{}
+	}
+	return DebtPayoffPlansResponse{Data: plans}
+}
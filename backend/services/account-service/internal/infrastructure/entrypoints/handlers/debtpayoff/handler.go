@@ -0,0 +1,263 @@
+package debtpayoff
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/debtpayoff/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for the debt payoff planner
+type Handler struct {
+	payoffService ports.DebtPayoffServiceInterface
+}
+
+// New creates a new debt payoff handler
+func New(payoffService ports.DebtPayoffServiceInterface) *Handler {
+	return &Handler{
+		payoffService: payoffService,
+	}
+}
+
+// Simulate simulates a payoff strategy
+// @Summary Simulate debt payoff
+// @Description Simulate paying off the credit card balances and installment plans of the user in the budget currency with a monthly budget. Every month pays the minimums first and the rest goes to the debts in the strategy order: avalanche (highest rate first), snowball (smallest balance first) or custom.
+// @Tags Debt Payoff
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.DebtPayoffRequest true "Budget and strategy"
+// @Success 200 {object} entities.DebtPayoffSimulation "Month by month schedule"
+// @Failure 400 {object} map[string]string "Invalid request data or budget below the minimum payments"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/debt-payoff/simulate [post]
+func (h *Handler) Simulate(c *gin.Context) {
+	userID, req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	simulation, err := h.payoffService.Simulate(userID, req.ToOptions())
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, simulation)
+}
+
+// Compare compares the payoff strategies
+// @Summary Compare debt payoff strategies
+// @Description Simulate the avalanche and snowball strategies, and the custom one when custom_order is given, for the same budget, pointing out the cheapest and the fastest
+// @Tags Debt Payoff
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.DebtPayoffRequest true "Budget"
+// @Success 200 {object} entities.DebtPayoffComparison "Simulations by strategy"
+// @Failure 400 {object} map[string]string "Invalid request data or budget below the minimum payments"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/debt-payoff/compare [post]
+func (h *Handler) Compare(c *gin.Context) {
+	userID, req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	comparison, err := h.payoffService.Compare(userID, req.ToOptions())
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// SavePlan saves a payoff plan
+// @Summary Save debt payoff plan
+// @Description Simulate a strategy and save it as the plan the user follows. The previous active plan is abandoned.
+// @Tags Debt Payoff
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.DebtPayoffRequest true "Budget and strategy"
+// @Success 201 {object} entities.DebtPayoffPlan "Saved plan"
+// @Failure 400 {object} map[string]string "Invalid request data or budget below the minimum payments"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/debt-payoff/plans [post]
+func (h *Handler) SavePlan(c *gin.Context) {
+	userID, req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.payoffService.SavePlan(userID, req.ToOptions())
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, plan)
+}
+
+// GetPlans lists the saved payoff plans
+// @Summary List debt payoff plans
+// @Description List the saved payoff plans of the user, newest first
+// @Tags Debt Payoff
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status (active, completed, abandoned)"
+// @Success 200 {object} dto.DebtPayoffPlansResponse "Saved plans"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/debt-payoff/plans [get]
+func (h *Handler) GetPlans(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	plans, err := h.payoffService.GetPlans(userID, c.Query("status"))
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToDebtPayoffPlansResponse(plans))
+}
+
+// GetPlan gets a saved payoff plan
+// @Summary Get debt payoff plan
+// @Description Get a saved payoff plan with its schedule
+// @Tags Debt Payoff
+// @Produce json
+// @Security BearerAuth
+// @Param planId path string true "Payoff plan ID"
+// @Success 200 {object} entities.DebtPayoffPlan "Payoff plan"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Plan of another user"
+// @Failure 404 {object} map[string]string "Payoff plan not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/debt-payoff/plans/{planId} [get]
+func (h *Handler) GetPlan(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.payoffService.GetPlan(c.Param("planId"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// GetProgress tracks a payoff plan
+// @Summary Get debt payoff progress
+// @Description Compare the current balance of the debts of a plan with the balance its schedule expected by now. A plan whose debts are all paid off is completed.
+// @Tags Debt Payoff
+// @Produce json
+// @Security BearerAuth
+// @Param planId path string true "Payoff plan ID"
+// @Success 200 {object} entities.DebtPayoffProgress "Progress against the plan"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Plan of another user"
+// @Failure 404 {object} map[string]string "Payoff plan not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/debt-payoff/plans/{planId}/progress [get]
+func (h *Handler) GetProgress(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.payoffService.GetProgress(c.Param("planId"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// AbandonPlan drops a payoff plan
+// @Summary Abandon debt payoff plan
+// @Description Stop following the active payoff plan
+// @Tags Debt Payoff
+// @Produce json
+// @Security BearerAuth
+// @Param planId path string true "Payoff plan ID"
+// @Success 200 {object} entities.DebtPayoffPlan "Abandoned plan"
+// @Failure 400 {object} map[string]string "Plan not active"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Plan of another user"
+// @Failure 404 {object} map[string]string "Payoff plan not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/debt-payoff/plans/{planId}/abandon [post]
+func (h *Handler) AbandonPlan(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.payoffService.AbandonPlan(c.Param("planId"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// bindRequest gets the authenticated user and the simulation request, writing the error response when invalid
+func (h *Handler) bindRequest(c *gin.Context) (string, dto.DebtPayoffRequest, bool) {
+	var req dto.DebtPayoffRequest
+
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return "", req, false
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", req, false
+	}
+	return userID, req, true
+}
+
+// requireUserID gets the authenticated user, writing a 401 response when missing
+func (h *Handler) requireUserID(c *gin.Context) (string, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		userID = c.GetHeader("X-User-ID")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return "", false
+		}
+	}
+	return userID, true
+}
+
+func (h *Handler) getErrorStatus(err error) int {
+	if errors.IsPermissionError(err) {
+		return http.StatusForbidden
+	}
+
+	if errors.IsValidationError(err) || errors.IsBusinessLogicError(err) {
+		return http.StatusBadRequest
+	}
+
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
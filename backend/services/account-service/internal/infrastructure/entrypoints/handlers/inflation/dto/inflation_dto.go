@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// CashComparisonRequest represents a purchase to compare paying in cash against paying in installments
+type CashComparisonRequest struct {
+	Price            float64                    `json:"price" binding:"required,gt=0"`
+	CashDiscount     float64                    `json:"cash_discount" binding:"gte=0,lt=100"` // Percent off the price for paying cash
+	Installments     []InstallmentOptionRequest `json:"installments" binding:"required,min=1,max=10,dive"`
+	FirstDueDate     *time.Time                 `json:"first_due_date,omitempty"`                                // A month from now when missing
+	MonthlyInflation *float64                   `json:"monthly_inflation,omitempty" binding:"omitempty,gt=-100"` // Percent; the configured expectation when missing
+	InflationSeries  string                     `json:"inflation_series,omitempty" binding:"omitempty,max=30"`   // Stored series instead of a single rate
+}
+
+// InstallmentOptionRequest represents a number of installments offered for the purchase
+type InstallmentOptionRequest struct {
+	InstallmentsCount int     `json:"installments_count" binding:"required,min=1,max=24"`
+	InterestRate      float64 `json:"interest_rate" binding:"gte=0"` // Percent over the price; 0 for "sin interés"
+}
+
+// SaveInflationSeriesRequest represents the monthly rates to store in an inflation series
+type SaveInflationSeriesRequest struct {
+	Rates []InflationRateRequest `json:"rates" binding:"required,min=1,max=600,dive"`
+}
+
+// InflationRateRequest represents the inflation of a month
+type InflationRateRequest struct {
+	Period      string   `json:"period" binding:"required"`       // YYYY-MM
+	MonthlyRate *float64 `json:"monthly_rate" binding:"required"` // Percent
+}
+
+// InflationSeriesResponse represents the monthly rates of an inflation series
+type InflationSeriesResponse struct {
+	Series string                    `json:"series"`
+	Data   []*entities.InflationRate `json:"data"`
+}
+
+// ToOptions converts the request to comparison options
+func (r CashComparisonRequest) ToOptions() entities.CashComparisonOptions {
+	installments := make([]entities.InstallmentOption, len(r.Installments))
+	for i, option := range r.Installments {
+		installments[i] = entities.InstallmentOption{
+			InstallmentsCount: option.InstallmentsCount,
+			InterestRate:      option.InterestRate,
+		}
+	}
+
+	return entities.CashComparisonOptions{
+		Price:            r.Price,
+		CashDiscount:     r.CashDiscount,
+		Installments:     installments,
+		FirstDueDate:     r.FirstDueDate,
+		MonthlyInflation: r.MonthlyInflation,
+		InflationSeries:  r.InflationSeries,
+	}
+}
+
+// ToRates converts the request to monthly rates
+func (r SaveInflationSeriesRequest) ToRates() ([]entities.InflationRate, error) {
+	rates := make([]entities.InflationRate, len(r.Rates))
+	for i, rate := range r.Rates {
+		period, err := time.Parse("2006-01", rate.Period)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period %q, expected YYYY-MM", rate.Period)
+		}
+		rates[i] = entities.InflationRate{
+			Period:      period,
+			MonthlyRate: *rate.MonthlyRate,
+		}
+	}
+	return rates, nil
+}
+
+// ToInflationSeriesResponse converts the rates of a series to response
+func ToInflationSeriesResponse(series string, rates []*entities.InflationRate) InflationSeriesResponse {
+	if rates == nil {
+		rates = []*entities.InflationRate{}
+	}
+	return InflationSeriesResponse{Series: series, Data: rates}
+}
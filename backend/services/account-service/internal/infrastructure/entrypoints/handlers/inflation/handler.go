@@ -0,0 +1,144 @@
+package inflation
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/inflation/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for the cash versus installments calculator and the inflation series
+type Handler struct {
+	inflationService ports.InflationServiceInterface
+}
+
+// New creates a new inflation handler
+func New(inflationService ports.InflationServiceInterface) *Handler {
+	return &Handler{
+		inflationService: inflationService,
+	}
+}
+
+// CompareCashWithInstallments compares paying a purchase in cash against paying it in installments
+// @Summary Compare cash with installments
+// @Description Compare paying a purchase in cash, with its cash discount, against paying it in installments with or without interest. Every installment is brought to today's money with the monthly inflation given in the request, a stored inflation series or the configured expectation, and the option with the lowest present value is the best one.
+// @Tags Installments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CashComparisonRequest true "Purchase, installment options and inflation"
+// @Success 200 {object} entities.CashComparison "Present value of each option"
+// @Failure 400 {object} map[string]string "Invalid request data or inflation series without rates"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/installments/cash-comparison [post]
+func (h *Handler) CompareCashWithInstallments(c *gin.Context) {
+	var req dto.CashComparisonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comparison, err := h.inflationService.CompareCashWithInstallments(req.ToOptions())
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// GetSeries gets an inflation series
+// @Summary Get inflation series
+// @Description Get the monthly rates of a stored inflation series, oldest first
+// @Tags Inflation
+// @Produce json
+// @Security BearerAuth
+// @Param series path string true "Series name (e.g. indec-cpi)"
+// @Success 200 {object} dto.InflationSeriesResponse "Monthly rates"
+// @Failure 400 {object} map[string]string "Invalid series name"
+// @Failure 404 {object} map[string]string "Inflation series not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/inflation-series/{series} [get]
+func (h *Handler) GetSeries(c *gin.Context) {
+	rates, err := h.inflationService.GetSeries(c.Param("series"))
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToInflationSeriesResponse(strings.ToLower(strings.TrimSpace(c.Param("series"))), rates))
+}
+
+// SaveSeries stores the monthly rates of an inflation series
+// @Summary Save inflation series
+// @Description Store the monthly rates of an inflation series. Months already stored are replaced.
+// @Tags Inflation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param series path string true "Series name (e.g. indec-cpi)"
+// @Param request body dto.SaveInflationSeriesRequest true "Monthly rates"
+// @Success 200 {object} dto.InflationSeriesResponse "Stored series"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Admin role required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/admin/inflation-series/{series} [put]
+func (h *Handler) SaveSeries(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SaveInflationSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rates, err := req.ToRates()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	saved, err := h.inflationService.SaveSeries(c.Param("series"), rates, userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToInflationSeriesResponse(strings.ToLower(strings.TrimSpace(c.Param("series"))), saved))
+}
+
+// requireUserID gets the authenticated user, writing a 401 response when missing
+func (h *Handler) requireUserID(c *gin.Context) (string, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		userID = c.GetHeader("X-User-ID")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return "", false
+		}
+	}
+	return userID, true
+}
+
+func (h *Handler) getErrorStatus(err error) int {
+	if errors.IsPermissionError(err) {
+		return http.StatusForbidden
+	}
+
+	if errors.IsValidationError(err) || errors.IsBusinessLogicError(err) {
+		return http.StatusBadRequest
+	}
+
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/fintrack/account-service/internal/core/errors"
 	"github.com/fintrack/account-service/internal/core/ports"
 	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/card/dto"
 	"github.com/gin-gonic/gin"
@@ -93,6 +94,7 @@ func (h *Handler) PreviewInstallmentPlan(c *gin.Context) {
 // @Success 201 {object} dto.ChargeWithInstallmentsResponse "Purchase created with installments"
 // @Failure 400 {object} map[string]string "Invalid request data"
 // @Failure 404 {object} map[string]string "Card not found"
+// @Failure 422 {object} map[string]string "Rejected by the card spending controls (includes reason code)"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/cards/{cardId}/charge-installments [post]
 func (h *Handler) ChargeCardWithInstallments(c *gin.Context) {
@@ -151,6 +153,10 @@ func (h *Handler) ChargeCardWithInstallments(c *gin.Context) {
 	response, err := h.cardService.ChargeCardWithInstallments(&req)
 	if err != nil {
 		fmt.Printf("🎯🎯🎯 HANDLER - ERROR from cardService: %v 🎯🎯🎯\n", err)
+		if controlErr, ok := err.(*errors.CardControlError); ok {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": controlErr.Message, "reason": controlErr.Reason})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -617,14 +623,19 @@ func (h *Handler) GetInstallmentsByPlan(c *gin.Context) {
 		}
 	}
 
-	// First verify that the plan belongs to the user
+	// First verify that the plan belongs to the user or to a card shared with them
 	plan, err := h.installmentService.GetInstallmentPlan(planID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "installment plan not found"})
 		return
 	}
 
-	if plan.UserID != userID {
+	canAccess, err := h.installmentService.CanAccessInstallmentPlan(plan, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !canAccess {
 		c.JSON(http.StatusForbidden, gin.H{"error": "access denied to this installment plan"})
 		return
 	}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+)
+
+// InviteMemberRequest represents the request to invite a user to a shared account
+type InviteMemberRequest struct {
+	UserID string  `json:"user_id" binding:"required"`
+	Role   string  `json:"role" binding:"required,oneof=owner co_owner viewer card_holder"`
+	CardID *string `json:"card_id,omitempty"` // Required for card holders
+}
+
+// UpdateMemberRoleRequest represents the request to change the role of a member
+type UpdateMemberRoleRequest struct {
+	Role   string  `json:"role" binding:"required,oneof=owner co_owner viewer card_holder"`
+	CardID *string `json:"card_id,omitempty"` // Required for card holders
+}
+
+// MemberResponse represents a membership in API responses
+type MemberResponse struct {
+	ID          string     `json:"id"`
+	AccountID   string     `json:"account_id"`
+	AccountName string     `json:"account_name,omitempty"`
+	UserID      string     `json:"user_id"`
+	Role        string     `json:"role"`
+	Status      string     `json:"status"`
+	CardID      *string    `json:"card_id,omitempty"`
+	InvitedBy   string     `json:"invited_by"`
+	InvitedAt   time.Time  `json:"invited_at"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// MemberListResponse represents a list of memberships
+type MemberListResponse struct {
+	Members []MemberResponse `json:"members"`
+	Total   int              `json:"total"`
+}
+
+// ToMemberResponse converts an AccountMember entity to MemberResponse
+func ToMemberResponse(member *entities.AccountMember) MemberResponse {
+	return MemberResponse{
+		ID:          member.ID,
+		AccountID:   member.AccountID,
+		AccountName: member.Account.Name,
+		UserID:      member.UserID,
+		Role:        string(member.Role),
+		Status:      string(member.Status),
+		CardID:      member.CardID,
+		InvitedBy:   member.InvitedBy,
+		InvitedAt:   member.InvitedAt,
+		AcceptedAt:  member.AcceptedAt,
+		RevokedAt:   member.RevokedAt,
+	}
+}
+
+// ToMemberListResponse converts a list of AccountMember entities to MemberListResponse
+func ToMemberListResponse(members []*entities.AccountMember) MemberListResponse {
+	responses := make([]MemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = ToMemberResponse(member)
+	}
+	return MemberListResponse{
+		Members: responses,
+		Total:   len(members),
+	}
+}
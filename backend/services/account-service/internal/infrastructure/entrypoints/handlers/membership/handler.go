@@ -0,0 +1,267 @@
+package membership
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/errors"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/membership/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for shared account memberships
+type Handler struct {
+	membershipService ports.MembershipServiceInterface
+}
+
+// New creates a new membership handler
+func New(membershipService ports.MembershipServiceInterface) *Handler {
+	return &Handler{
+		membershipService: membershipService,
+	}
+}
+
+// InviteMember invites a user to a shared account
+// @Summary Invite account member
+// @Description Invite a user to an account as owner, co-owner, viewer or card holder
+// @Tags Account Members
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param request body dto.InviteMemberRequest true "Invitation data"
+// @Success 201 {object} dto.MemberResponse "Invitation created"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot manage members"
+// @Failure 404 {object} map[string]string "Account not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/members [post]
+func (h *Handler) InviteMember(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.membershipService.InviteMember(c.Param("id"), userID, req.UserID, entities.MemberRole(req.Role), req.CardID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToMemberResponse(member))
+}
+
+// GetAccountMembers lists the members of an account
+// @Summary List account members
+// @Description List pending and active members of an account
+// @Tags Account Members
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Success 200 {object} dto.MemberListResponse "Account members"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot view this account"
+// @Failure 404 {object} map[string]string "Account not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/members [get]
+func (h *Handler) GetAccountMembers(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.membershipService.GetAccountMembers(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToMemberListResponse(members))
+}
+
+// UpdateMemberRole changes the role of an account member
+// @Summary Update member role
+// @Description Change the role (and assigned card for card holders) of an account member
+// @Tags Account Members
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param memberId path string true "Member ID"
+// @Param request body dto.UpdateMemberRoleRequest true "Role data"
+// @Success 200 {object} dto.MemberResponse "Member updated"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot manage members"
+// @Failure 404 {object} map[string]string "Member not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/members/{memberId} [put]
+func (h *Handler) UpdateMemberRole(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.membershipService.UpdateMemberRole(c.Param("id"), c.Param("memberId"), userID, entities.MemberRole(req.Role), req.CardID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToMemberResponse(member))
+}
+
+// RemoveMember revokes the access of a member to an account
+// @Summary Remove account member
+// @Description Revoke a membership. Members can remove themselves from an account.
+// @Tags Account Members
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param memberId path string true "Member ID"
+// @Success 200 {object} map[string]string "Member removed"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "User cannot manage members"
+// @Failure 404 {object} map[string]string "Member not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/accounts/{id}/members/{memberId} [delete]
+func (h *Handler) RemoveMember(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.membershipService.RemoveMember(c.Param("id"), c.Param("memberId"), userID); err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member removed successfully"})
+}
+
+// GetPendingInvitations lists the invitations waiting for the user's answer
+// @Summary List pending invitations
+// @Description List the shared account invitations addressed to the authenticated user
+// @Tags Account Members
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.MemberListResponse "Pending invitations"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/memberships/invitations [get]
+func (h *Handler) GetPendingInvitations(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.membershipService.GetPendingInvitations(userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToMemberListResponse(members))
+}
+
+// AcceptInvitation accepts a shared account invitation
+// @Summary Accept invitation
+// @Description Accept a pending invitation to a shared account
+// @Tags Account Members
+// @Produce json
+// @Security BearerAuth
+// @Param memberId path string true "Member ID"
+// @Success 200 {object} dto.MemberResponse "Invitation accepted"
+// @Failure 400 {object} map[string]string "Invitation is not pending"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Invitation belongs to another user"
+// @Failure 404 {object} map[string]string "Invitation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/memberships/{memberId}/accept [post]
+func (h *Handler) AcceptInvitation(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.membershipService.AcceptInvitation(c.Param("memberId"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToMemberResponse(member))
+}
+
+// DeclineInvitation declines a shared account invitation
+// @Summary Decline invitation
+// @Description Decline a pending invitation to a shared account
+// @Tags Account Members
+// @Produce json
+// @Security BearerAuth
+// @Param memberId path string true "Member ID"
+// @Success 200 {object} dto.MemberResponse "Invitation declined"
+// @Failure 400 {object} map[string]string "Invitation is not pending"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Invitation belongs to another user"
+// @Failure 404 {object} map[string]string "Invitation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/memberships/{memberId}/decline [post]
+func (h *Handler) DeclineInvitation(c *gin.Context) {
+	userID, ok := h.requireUserID(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.membershipService.DeclineInvitation(c.Param("memberId"), userID)
+	if err != nil {
+		c.JSON(h.getErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToMemberResponse(member))
+}
+
+// requireUserID gets the authenticated user, writing a 401 response when missing
+func (h *Handler) requireUserID(c *gin.Context) (string, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		userID = c.GetHeader("X-User-ID")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return "", false
+		}
+	}
+	return userID, true
+}
+
+func (h *Handler) getErrorStatus(err error) int {
+	if errors.IsPermissionError(err) {
+		return http.StatusForbidden
+	}
+
+	if errors.IsValidationError(err) {
+		return http.StatusBadRequest
+	}
+
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
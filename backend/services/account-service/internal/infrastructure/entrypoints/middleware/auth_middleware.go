@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RequireRole validates the JWT issued by the user service and only lets through
+// users with one of the given roles. It sets user_id and user_role in the context.
+func RequireRole(secret string, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth := c.GetHeader("Authorization")
+		if auth == "" || !strings.HasPrefix(auth, "Bearer ") {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+			return
+		}
+		tokenStr := strings.TrimPrefix(auth, "Bearer ")
+		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, jwt.ErrTokenMalformed
+			}
+			return []byte(secret), nil
+		})
+		if err != nil || !token.Valid {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
+			return
+		}
+
+		userID, ok := claims["sub"].(string)
+		if !ok || userID == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid user ID in token"})
+			return
+		}
+
+		role, _ := claims["role"].(string)
+		if !hasRole(role, roles) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient rights"})
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Set("user_role", role)
+
+		c.Next()
+	}
+}
+
+func hasRole(role string, roles []string) bool {
+	for _, allowed := range roles {
+		if role == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// InternalService marks the requests of other FinTrack services, which carry the shared internal
+// token, setting internal_service in the context. The internal performer is reserved, so a client
+// cannot claim it as its user ID. Without a configured token no request is internal.
+func InternalService(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-User-ID") == entities.InternalServicePerformer {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid user ID"})
+			return
+		}
+
+		provided := c.GetHeader("X-Internal-Service-Token")
+		if token != "" && provided != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+			c.Set("internal_service", true)
+		}
+
+		c.Next()
+	}
+}
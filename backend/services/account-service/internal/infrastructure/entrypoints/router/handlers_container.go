@@ -4,19 +4,43 @@ import (
 	"github.com/fintrack/account-service/internal/app"
 	accounthandler "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/account"
 	cardhandler "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/card"
+	cardcontrolshandler "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/cardcontrols"
+	cardnumberhandler "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/cardnumber"
+	closurehandler "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/closure"
+	creditlimithandler "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/creditlimit"
+	creditlinehandler "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/creditline"
+	debtpayoffhandler "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/debtpayoff"
+	inflationhandler "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/inflation"
 	installmenthandler "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/installment"
+	membershiphandler "github.com/fintrack/account-service/internal/infrastructure/entrypoints/handlers/membership"
 )
 
 type Handlers struct {
-	Account     *accounthandler.Handler
-	Card        *cardhandler.Handler
-	Installment *installmenthandler.Handler
+	Account      *accounthandler.Handler
+	Card         *cardhandler.Handler
+	Installment  *installmenthandler.Handler
+	Membership   *membershiphandler.Handler
+	CreditLine   *creditlinehandler.Handler
+	CardControls *cardcontrolshandler.Handler
+	CardNumber   *cardnumberhandler.Handler
+	Closure      *closurehandler.Handler
+	CreditLimit  *creditlimithandler.Handler
+	DebtPayoff   *debtpayoffhandler.Handler
+	Inflation    *inflationhandler.Handler
 }
 
 func NewHandlers(a *app.Application) *Handlers {
 	return &Handlers{
-		Account:     accounthandler.New(a.AccountService),
-		Card:        cardhandler.New(a.CardService),
-		Installment: installmenthandler.New(a.InstallmentService, a.CardService),
+		Account:      accounthandler.New(a.AccountService),
+		Card:         cardhandler.New(a.CardService),
+		Installment:  installmenthandler.New(a.InstallmentService, a.CardService),
+		Membership:   membershiphandler.New(a.MembershipService),
+		CreditLine:   creditlinehandler.New(a.CreditLineService),
+		CardControls: cardcontrolshandler.New(a.CardControlsService),
+		CardNumber:   cardnumberhandler.New(a.CardNumberService),
+		Closure:      closurehandler.New(a.ClosureService),
+		CreditLimit:  creditlimithandler.New(a.CreditLimitService),
+		DebtPayoff:   debtpayoffhandler.New(a.DebtPayoffService),
+		Inflation:    inflationhandler.New(a.InflationService),
 	}
 }
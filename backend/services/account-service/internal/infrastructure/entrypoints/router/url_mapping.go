@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/fintrack/account-service/internal/app"
 	"github.com/fintrack/account-service/internal/config"
+	"github.com/fintrack/account-service/internal/infrastructure/entrypoints/middleware"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -22,7 +23,7 @@ func MapRoutes(r *gin.Engine, h *Handlers, cfg *config.Config, application *app.
 	// Swagger documentation
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	api := r.Group("/api")
+	api := r.Group("/api", middleware.InternalService(cfg.InternalServiceToken))
 	{
 		// Account management routes
 		accounts := api.Group("/accounts")
@@ -52,9 +53,9 @@ func MapRoutes(r *gin.Engine, h *Handlers, cfg *config.Config, application *app.
 			accounts.POST("/:id/withdraw-funds", h.Account.WithdrawFunds) // POST /api/accounts/:id/withdraw-funds
 
 			// Credit card operations
-			accounts.PUT("/:id/credit-limit", h.Account.UpdateCreditLimit)      // PUT /api/accounts/:id/credit-limit
-			accounts.PUT("/:id/credit-dates", h.Account.UpdateCreditDates)      // PUT /api/accounts/:id/credit-dates
-			accounts.GET("/:id/available-credit", h.Account.GetAvailableCredit) // GET /api/accounts/:id/available-credit
+			accounts.PUT("/:id/credit-limit", h.CreditLimit.UpdateAccountCreditLimit) // PUT /api/accounts/:id/credit-limit
+			accounts.PUT("/:id/credit-dates", h.Account.UpdateCreditDates)            // PUT /api/accounts/:id/credit-dates
+			accounts.GET("/:id/available-credit", h.Account.GetAvailableCredit)       // GET /api/accounts/:id/available-credit
 
 			// Card management routes (usar :id ya que no hay conflicto con esta estructura)
 			accounts.POST("/:id/cards", h.Card.CreateCard)           // POST /api/accounts/:id/cards
@@ -67,6 +68,50 @@ func MapRoutes(r *gin.Engine, h *Handlers, cfg *config.Config, application *app.
 			accounts.PUT("/:id/cards/:cardId/block", h.Card.BlockCard)            // PUT /api/accounts/:id/cards/:cardId/block
 			accounts.PUT("/:id/cards/:cardId/unblock", h.Card.UnblockCard)        // PUT /api/accounts/:id/cards/:cardId/unblock
 			accounts.PUT("/:id/cards/:cardId/set-default", h.Card.SetDefaultCard) // PUT /api/accounts/:id/cards/:cardId/set-default
+			accounts.POST("/:id/cards/:cardId/renew", h.Card.RenewCard)           // POST /api/accounts/:id/cards/:cardId/renew
+
+			// Shared account members
+			accounts.POST("/:id/members", h.Membership.InviteMember)              // POST /api/accounts/:id/members
+			accounts.GET("/:id/members", h.Membership.GetAccountMembers)          // GET /api/accounts/:id/members
+			accounts.PUT("/:id/members/:memberId", h.Membership.UpdateMemberRole) // PUT /api/accounts/:id/members/:memberId
+			accounts.DELETE("/:id/members/:memberId", h.Membership.RemoveMember)  // DELETE /api/accounts/:id/members/:memberId
+
+			// Shared credit lines (primary and additional cards under one limit)
+			accounts.POST("/:id/credit-lines", h.CreditLine.CreateCreditLine)       // POST /api/accounts/:id/credit-lines
+			accounts.GET("/:id/credit-lines", h.CreditLine.GetCreditLinesByAccount) // GET /api/accounts/:id/credit-lines
+
+			// Credit limit changes (increases above the threshold wait for treasury approval)
+			accounts.POST("/:id/credit-limit-requests", h.CreditLimit.RequestChange)       // POST /api/accounts/:id/credit-limit-requests
+			accounts.GET("/:id/credit-limit-requests", h.CreditLimit.GetRequestsByAccount) // GET /api/accounts/:id/credit-limit-requests?status=pending
+			accounts.GET("/:id/credit-limit-history", h.CreditLimit.GetHistory)            // GET /api/accounts/:id/credit-limit-history?card_id=&at=2024-01-31
+
+			// Account closure (pre-checks, final settlement and closing statement)
+			accounts.GET("/:id/closure/check", h.Closure.CheckClosure)                                          // GET /api/accounts/:id/closure/check
+			accounts.POST("/:id/closure", h.Closure.StartClosure)                                               // POST /api/accounts/:id/closure
+			accounts.GET("/:id/closure", h.Closure.GetClosure)                                                  // GET /api/accounts/:id/closure
+			accounts.POST("/:id/closure/transfer-balance", h.Closure.TransferBalance)                           // POST /api/accounts/:id/closure/transfer-balance
+			accounts.POST("/:id/closure/cards/:cardId/settle", h.Closure.SettleCard)                            // POST /api/accounts/:id/closure/cards/:cardId/settle
+			accounts.POST("/:id/closure/installment-plans/:planId/cancel", h.Closure.CancelInstallmentPlan)     // POST /api/accounts/:id/closure/installment-plans/:planId/cancel
+			accounts.POST("/:id/closure/installment-plans/:planId/transfer", h.Closure.TransferInstallmentPlan) // POST /api/accounts/:id/closure/installment-plans/:planId/transfer
+			accounts.POST("/:id/closure/complete", h.Closure.CompleteClosure)                                   // POST /api/accounts/:id/closure/complete
+			accounts.POST("/:id/closure/cancel", h.Closure.CancelClosure)                                       // POST /api/accounts/:id/closure/cancel
+		}
+
+		// Shared account invitations for the authenticated user
+		memberships := api.Group("/memberships")
+		{
+			memberships.GET("/invitations", h.Membership.GetPendingInvitations)    // GET /api/memberships/invitations
+			memberships.POST("/:memberId/accept", h.Membership.AcceptInvitation)   // POST /api/memberships/:memberId/accept
+			memberships.POST("/:memberId/decline", h.Membership.DeclineInvitation) // POST /api/memberships/:memberId/decline
+		}
+
+		// Direct credit line operations
+		creditLines := api.Group("/credit-lines")
+		{
+			creditLines.GET("/:creditLineId", h.CreditLine.GetCreditLine)               // GET /api/credit-lines/:creditLineId
+			creditLines.PUT("/:creditLineId", h.CreditLine.UpdateCreditLine)            // PUT /api/credit-lines/:creditLineId
+			creditLines.PUT("/:creditLineId/cards/:cardId", h.CreditLine.AssignCard)    // PUT /api/credit-lines/:creditLineId/cards/:cardId
+			creditLines.DELETE("/:creditLineId/cards/:cardId", h.CreditLine.RemoveCard) // DELETE /api/credit-lines/:creditLineId/cards/:cardId
 		}
 
 		// Direct card operations (financial transactions)
@@ -86,6 +131,47 @@ func MapRoutes(r *gin.Engine, h *Handlers, cfg *config.Config, application *app.
 			cards.POST("/:cardId/installments/preview", h.Installment.PreviewInstallmentPlan)    // POST /api/cards/:cardId/installments/preview
 			cards.POST("/:cardId/charge-installments", h.Installment.ChargeCardWithInstallments) // POST /api/cards/:cardId/charge-installments
 			cards.GET("/:cardId/installment-plans", h.Installment.GetInstallmentPlansByCard)     // GET /api/cards/:cardId/installment-plans
+
+			// Spending controls
+			cards.GET("/:cardId/controls", h.CardControls.GetControls)              // GET /api/cards/:cardId/controls
+			cards.PUT("/:cardId/controls", h.CardControls.UpdateControls)           // PUT /api/cards/:cardId/controls
+			cards.GET("/:cardId/controls/rejections", h.CardControls.GetRejections) // GET /api/cards/:cardId/controls/rejections
+			cards.POST("/:cardId/freeze", h.CardControls.FreezeCard)                // POST /api/cards/:cardId/freeze
+			cards.POST("/:cardId/unfreeze", h.CardControls.UnfreezeCard)            // POST /api/cards/:cardId/unfreeze
+		}
+
+		// Debt payoff planner over the card debt and installment plans of the user
+		debtPayoff := api.Group("/debt-payoff")
+		{
+			debtPayoff.POST("/simulate", h.DebtPayoff.Simulate)                 // POST /api/debt-payoff/simulate
+			debtPayoff.POST("/compare", h.DebtPayoff.Compare)                   // POST /api/debt-payoff/compare
+			debtPayoff.POST("/plans", h.DebtPayoff.SavePlan)                    // POST /api/debt-payoff/plans
+			debtPayoff.GET("/plans", h.DebtPayoff.GetPlans)                     // GET /api/debt-payoff/plans?status=active
+			debtPayoff.GET("/plans/:planId", h.DebtPayoff.GetPlan)              // GET /api/debt-payoff/plans/:planId
+			debtPayoff.GET("/plans/:planId/progress", h.DebtPayoff.GetProgress) // GET /api/debt-payoff/plans/:planId/progress
+			debtPayoff.POST("/plans/:planId/abandon", h.DebtPayoff.AbandonPlan) // POST /api/debt-payoff/plans/:planId/abandon
+		}
+
+		// Monthly inflation series for the cash versus installments calculator
+		inflationSeries := api.Group("/inflation-series")
+		{
+			inflationSeries.GET("/:series", h.Inflation.GetSeries) // GET /api/inflation-series/:series
+		}
+
+		// Administrative operations (JWT with admin role required)
+		admin := api.Group("/admin", middleware.RequireRole(cfg.JWTSecret, "admin"))
+		{
+			admin.POST("/cards/:cardId/reveal-number", h.CardNumber.RevealCardNumber) // POST /api/admin/cards/:cardId/reveal-number
+			admin.GET("/cards/:cardId/number-access-log", h.CardNumber.GetAccessLog)  // GET /api/admin/cards/:cardId/number-access-log
+			admin.PUT("/inflation-series/:series", h.Inflation.SaveSeries)            // PUT /api/admin/inflation-series/:series
+		}
+
+		// Treasury operations (JWT with treasurer role required)
+		treasury := api.Group("/treasury", middleware.RequireRole(cfg.JWTSecret, "treasurer"))
+		{
+			treasury.GET("/credit-limit-requests", h.CreditLimit.GetPendingRequests)                 // GET /api/treasury/credit-limit-requests
+			treasury.POST("/credit-limit-requests/:requestId/approve", h.CreditLimit.ApproveRequest) // POST /api/treasury/credit-limit-requests/:requestId/approve
+			treasury.POST("/credit-limit-requests/:requestId/reject", h.CreditLimit.RejectRequest)   // POST /api/treasury/credit-limit-requests/:requestId/reject
 		}
 
 		// Direct installment operations
@@ -100,11 +186,12 @@ func MapRoutes(r *gin.Engine, h *Handlers, cfg *config.Config, application *app.
 		// Individual installment operations
 		installmentItems := api.Group("/installments")
 		{
-			installmentItems.POST("/:installmentId/pay", h.Installment.PayInstallment)     // POST /api/installments/:installmentId/pay
-			installmentItems.GET("/overdue", h.Installment.GetOverdueInstallments)         // GET /api/installments/overdue
-			installmentItems.GET("/upcoming", h.Installment.GetUpcomingInstallments)       // GET /api/installments/upcoming
-			installmentItems.GET("/summary", h.Installment.GetInstallmentSummary)          // GET /api/installments/summary
-			installmentItems.GET("/monthly-load", h.Installment.GetMonthlyInstallmentLoad) // GET /api/installments/monthly-load
+			installmentItems.POST("/:installmentId/pay", h.Installment.PayInstallment)         // POST /api/installments/:installmentId/pay
+			installmentItems.GET("/overdue", h.Installment.GetOverdueInstallments)             // GET /api/installments/overdue
+			installmentItems.GET("/upcoming", h.Installment.GetUpcomingInstallments)           // GET /api/installments/upcoming
+			installmentItems.GET("/summary", h.Installment.GetInstallmentSummary)              // GET /api/installments/summary
+			installmentItems.GET("/monthly-load", h.Installment.GetMonthlyInstallmentLoad)     // GET /api/installments/monthly-load
+			installmentItems.POST("/cash-comparison", h.Inflation.CompareCashWithInstallments) // POST /api/installments/cash-comparison
 		}
 	}
 }
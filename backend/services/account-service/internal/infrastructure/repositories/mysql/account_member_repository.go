@@ -0,0 +1,123 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// accessibleAccountIDsSQL selects the accounts a user owns or has an active membership in
+const accessibleAccountIDsSQL = `SELECT id FROM accounts WHERE user_id = ? AND deleted_at IS NULL
+	UNION SELECT account_id FROM account_members WHERE user_id = ? AND status = 'active'`
+
+// accessibleCardIDsSQL selects the cards a user can see: every card of the accounts they own or
+// share with full visibility, plus the card assigned to them as card holder
+const accessibleCardIDsSQL = `SELECT cards.id FROM cards
+		INNER JOIN accounts ON accounts.id = cards.account_id
+		WHERE accounts.user_id = ? AND accounts.deleted_at IS NULL
+	UNION SELECT cards.id FROM cards
+		INNER JOIN account_members ON account_members.account_id = cards.account_id
+		WHERE account_members.user_id = ? AND account_members.status = 'active' AND account_members.role <> 'card_holder'
+	UNION SELECT card_id FROM account_members
+		WHERE user_id = ? AND status = 'active' AND role = 'card_holder'`
+
+// accessibleAccountsCondition builds a condition matching the accounts visible to a user
+func accessibleAccountsCondition(column, userID string) (string, []interface{}) {
+	return fmt.Sprintf("%s IN (%s)", column, accessibleAccountIDsSQL), []interface{}{userID, userID}
+}
+
+// accessibleCardsCondition builds a condition matching the cards visible to a user
+func accessibleCardsCondition(column, userID string) (string, []interface{}) {
+	return fmt.Sprintf("%s IN (%s)", column, accessibleCardIDsSQL), []interface{}{userID, userID, userID}
+}
+
+// accessiblePlansCondition builds a condition matching the installment plans visible to a user:
+// the ones they created plus the ones charged to cards shared with them
+func accessiblePlansCondition(userID string) (string, []interface{}) {
+	cardCondition, cardArgs := accessibleCardsCondition("installment_plans.card_id", userID)
+	return fmt.Sprintf("(installment_plans.user_id = ? OR %s)", cardCondition), append([]interface{}{userID}, cardArgs...)
+}
+
+// AccountMemberRepository implements AccountMemberRepositoryInterface
+type AccountMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountMemberRepository creates a new account member repository
+func NewAccountMemberRepository(db *gorm.DB) ports.AccountMemberRepositoryInterface {
+	return &AccountMemberRepository{db: db}
+}
+
+// Create saves a new membership
+func (r *AccountMemberRepository) Create(member *entities.AccountMember) (*entities.AccountMember, error) {
+	if err := r.db.Omit(clause.Associations).Create(member).Error; err != nil {
+		return nil, fmt.Errorf("failed to create account member: %w", err)
+	}
+	return member, nil
+}
+
+// GetByID retrieves a membership by ID with its account preloaded
+func (r *AccountMemberRepository) GetByID(memberID string) (*entities.AccountMember, error) {
+	var member entities.AccountMember
+	err := r.db.Preload("Account").Where("id = ?", memberID).First(&member).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("account member not found")
+		}
+		return nil, fmt.Errorf("failed to get account member: %w", err)
+	}
+	return &member, nil
+}
+
+// GetByAccount retrieves all pending and active memberships of an account
+func (r *AccountMemberRepository) GetByAccount(accountID string) ([]*entities.AccountMember, error) {
+	var members []*entities.AccountMember
+	err := r.db.Where("account_id = ? AND status IN ?", accountID,
+		[]entities.MemberStatus{entities.MemberStatusPending, entities.MemberStatusActive}).
+		Order("invited_at ASC").
+		Find(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account members: %w", err)
+	}
+	return members, nil
+}
+
+// GetByAccountAndUser retrieves the current (pending or active) membership of a user in an account.
+// It returns nil without error when the user is not a member.
+func (r *AccountMemberRepository) GetByAccountAndUser(accountID, userID string) (*entities.AccountMember, error) {
+	var member entities.AccountMember
+	err := r.db.Where("account_id = ? AND user_id = ? AND status IN ?", accountID, userID,
+		[]entities.MemberStatus{entities.MemberStatusPending, entities.MemberStatusActive}).
+		First(&member).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get account member: %w", err)
+	}
+	return &member, nil
+}
+
+// GetPendingByUser retrieves the invitations waiting for a user's answer
+func (r *AccountMemberRepository) GetPendingByUser(userID string) ([]*entities.AccountMember, error) {
+	var members []*entities.AccountMember
+	err := r.db.Preload("Account").
+		Where("user_id = ? AND status = ?", userID, entities.MemberStatusPending).
+		Order("invited_at DESC").
+		Find(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending invitations: %w", err)
+	}
+	return members, nil
+}
+
+// Update updates an existing membership
+func (r *AccountMemberRepository) Update(member *entities.AccountMember) (*entities.AccountMember, error) {
+	if err := r.db.Omit(clause.Associations).Save(member).Error; err != nil {
+		return nil, fmt.Errorf("failed to update account member: %w", err)
+	}
+	return member, nil
+}
@@ -31,10 +31,16 @@ func (r *AccountRepository) GetByID(id string) (*entities.Account, error) {
 	return &account, nil
 }
 
-// GetByUserID retrieves all accounts for a user
+// GetByUserID retrieves all accounts a user owns or has been granted access to.
+// Card holders only get the card assigned to them preloaded.
 func (r *AccountRepository) GetByUserID(userID string) ([]*entities.Account, error) {
 	var accounts []*entities.Account
-	err := r.db.Preload("Cards").Where("user_id = ? AND deleted_at IS NULL", userID).Find(&accounts).Error
+	accountCondition, accountArgs := accessibleAccountsCondition("id", userID)
+	cardCondition, cardArgs := accessibleCardsCondition("id", userID)
+	err := r.db.Preload("Cards", append([]interface{}{cardCondition}, cardArgs...)...).
+		Where("deleted_at IS NULL").
+		Where(accountCondition, accountArgs...).
+		Find(&accounts).Error
 	return accounts, err
 }
 
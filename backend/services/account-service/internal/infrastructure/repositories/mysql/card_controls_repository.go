@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// CardControlsRepository implements CardControlsRepositoryInterface
+type CardControlsRepository struct {
+	db *gorm.DB
+}
+
+// NewCardControlsRepository creates a new card controls repository
+func NewCardControlsRepository(db *gorm.DB) ports.CardControlsRepositoryInterface {
+	return &CardControlsRepository{db: db}
+}
+
+// GetByCard retrieves the controls of a card. It returns nil without error when none were set.
+func (r *CardControlsRepository) GetByCard(cardID string) (*entities.CardControls, error) {
+	var controls entities.CardControls
+	err := r.db.Where("card_id = ?", cardID).First(&controls).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get card controls: %w", err)
+	}
+	return &controls, nil
+}
+
+// Save creates or updates the controls of a card
+func (r *CardControlsRepository) Save(controls *entities.CardControls) (*entities.CardControls, error) {
+	var err error
+	if controls.ID == "" {
+		err = r.db.Create(controls).Error
+	} else {
+		err = r.db.Save(controls).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save card controls: %w", err)
+	}
+	return controls, nil
+}
+
+// CreateRejection records an operation rejected by the controls of a card
+func (r *CardControlsRepository) CreateRejection(rejection *entities.CardControlRejection) error {
+	if err := r.db.Create(rejection).Error; err != nil {
+		return fmt.Errorf("failed to record card control rejection: %w", err)
+	}
+	return nil
+}
+
+// GetRejectionsByCard retrieves the rejections of a card with pagination, newest first
+func (r *CardControlsRepository) GetRejectionsByCard(cardID string, limit, offset int) ([]*entities.CardControlRejection, int64, error) {
+	var rejections []*entities.CardControlRejection
+	var total int64
+
+	query := r.db.Model(&entities.CardControlRejection{}).Where("card_id = ?", cardID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count card control rejections: %w", err)
+	}
+
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&rejections).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get card control rejections: %w", err)
+	}
+	return rejections, total, nil
+}
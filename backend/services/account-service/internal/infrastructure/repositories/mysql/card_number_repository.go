@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// CardNumberRepository implements CardNumberRepositoryInterface
+type CardNumberRepository struct {
+	db *gorm.DB
+}
+
+// NewCardNumberRepository creates a new card number repository
+func NewCardNumberRepository(db *gorm.DB) ports.CardNumberRepositoryInterface {
+	return &CardNumberRepository{db: db}
+}
+
+// GetCardsToRotate retrieves a batch of cards whose number is not wrapped by the active key.
+// Deleted cards are included so that no record is left under a retired key.
+func (r *CardNumberRepository) GetCardsToRotate(activeFingerprint, afterID string, limit int) ([]*entities.Card, error) {
+	var cards []*entities.Card
+	err := r.db.Unscoped().
+		Select("id", "encrypted_number", "key_fingerprint").
+		Where("key_fingerprint <> ? AND id > ?", activeFingerprint, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&cards).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cards to rotate: %w", err)
+	}
+	return cards, nil
+}
+
+// UpdateEncryptedNumber replaces the encrypted number of a card. It reports false without error
+// when the card changed since it was read, so concurrent writes are never overwritten.
+func (r *CardNumberRepository) UpdateEncryptedNumber(cardID, previousNumber, encryptedNumber, fingerprint string) (bool, error) {
+	result := r.db.Unscoped().Model(&entities.Card{}).
+		Where("id = ? AND encrypted_number = ?", cardID, previousNumber).
+		UpdateColumns(map[string]interface{}{
+			"encrypted_number": encryptedNumber,
+			"key_fingerprint":  fingerprint,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update card number: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CreateAccessLog records an attempt to decrypt a card number
+func (r *CardNumberRepository) CreateAccessLog(log *entities.CardNumberAccessLog) error {
+	if err := r.db.Create(log).Error; err != nil {
+		return fmt.Errorf("failed to record card number access: %w", err)
+	}
+	return nil
+}
+
+// GetAccessLogsByCard retrieves the number access log of a card with pagination, newest first
+func (r *CardNumberRepository) GetAccessLogsByCard(cardID string, limit, offset int) ([]*entities.CardNumberAccessLog, int64, error) {
+	var logs []*entities.CardNumberAccessLog
+	var total int64
+
+	query := r.db.Model(&entities.CardNumberAccessLog{}).Where("card_id = ?", cardID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count card number accesses: %w", err)
+	}
+
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&logs).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get card number accesses: %w", err)
+	}
+	return logs, total, nil
+}
@@ -57,15 +57,18 @@ func (r *CardRepository) GetByAccount(accountID string, limit, offset int) ([]*e
 	return cards, total, err
 }
 
-// GetByUser retrieves all cards for a specific user across all accounts with pagination
+// GetByUser retrieves all cards visible to a user (own accounts and shared ones) with pagination
 func (r *CardRepository) GetByUser(userID string, limit, offset int) ([]*entities.Card, int64, error) {
 	var cards []*entities.Card
 	var total int64
 
+	cardCondition, cardArgs := accessibleCardsCondition("cards.id", userID)
+
 	// Count total cards for the user
 	err := r.db.Model(&entities.Card{}).
 		Joins("INNER JOIN accounts ON accounts.id = cards.account_id").
-		Where("accounts.user_id = ? AND cards.deleted_at IS NULL AND accounts.deleted_at IS NULL", userID).
+		Where("cards.deleted_at IS NULL AND accounts.deleted_at IS NULL").
+		Where(cardCondition, cardArgs...).
 		Count(&total).Error
 	if err != nil {
 		return nil, 0, err
@@ -74,7 +77,8 @@ func (r *CardRepository) GetByUser(userID string, limit, offset int) ([]*entitie
 	// Get cards with pagination
 	err = r.db.
 		Joins("INNER JOIN accounts ON accounts.id = cards.account_id").
-		Where("accounts.user_id = ? AND cards.deleted_at IS NULL AND accounts.deleted_at IS NULL", userID).
+		Where("cards.deleted_at IS NULL AND accounts.deleted_at IS NULL").
+		Where(cardCondition, cardArgs...).
 		Limit(limit).
 		Offset(offset).
 		Order("cards.created_at DESC").
@@ -85,7 +89,8 @@ func (r *CardRepository) GetByUser(userID string, limit, offset int) ([]*entitie
 
 // Update updates an existing card
 func (r *CardRepository) Update(card *entities.Card) (*entities.Card, error) {
-	err := r.db.Save(card).Error
+	// The credit line and installment plans are only loaded to compute available credit
+	err := r.db.Omit("CreditLine", "InstallmentPlans").Save(card).Error
 	if err != nil {
 		return nil, err
 	}
@@ -136,10 +141,10 @@ func (r *CardRepository) SetDefaultByAccount(accountID, cardID string) error {
 	return tx.Commit().Error
 }
 
-// GetByIDWithAccount retrieves a card by its ID with account data preloaded
+// GetByIDWithAccount retrieves a card by its ID with account data and credit line preloaded
 func (r *CardRepository) GetByIDWithAccount(cardID string) (*entities.Card, error) {
 	var card entities.Card
-	err := r.db.Preload("Account").Where("id = ? AND deleted_at IS NULL", cardID).First(&card).Error
+	err := withCreditLine(r.db).Preload("Account").Where("id = ? AND deleted_at IS NULL", cardID).First(&card).Error
 	if err != nil {
 		return nil, err
 	}
@@ -186,3 +191,34 @@ func (r *CardRepository) GetByAccountWithInstallmentPlans(accountID string, limi
 
 	return cards, total, err
 }
+
+// Replace stores a renewed card and its replacement in a single transaction, moving the
+// active installment plans, spending controls and card holder memberships to the new card
+func (r *CardRepository) Replace(oldCard, newCard *entities.Card) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Account", "CreditLine", "InstallmentPlans").Create(newCard).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Account", "CreditLine", "InstallmentPlans").Save(oldCard).Error; err != nil {
+			return err
+		}
+
+		err := tx.Model(&entities.InstallmentPlan{}).
+			Where("card_id = ? AND status IN (?, ?, ?)", oldCard.ID, "active", "suspended", "pending").
+			Update("card_id", newCard.ID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&entities.CardControls{}).
+			Where("card_id = ?", oldCard.ID).
+			Update("card_id", newCard.ID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.AccountMember{}).
+			Where("card_id = ?", oldCard.ID).
+			Update("card_id", newCard.ID).Error
+	})
+}
@@ -0,0 +1,181 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// AccountClosureRepository implements AccountClosureRepositoryInterface
+type AccountClosureRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountClosureRepository creates a new account closure repository
+func NewAccountClosureRepository(db *gorm.DB) ports.AccountClosureRepositoryInterface {
+	return &AccountClosureRepository{db: db}
+}
+
+// Create starts a closure process, recording its first event once the closure has an ID
+func (r *AccountClosureRepository) Create(closure *entities.AccountClosure, event *entities.AccountClosureEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Events").Create(closure).Error; err != nil {
+			return err
+		}
+		event.ClosureID = closure.ID
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create account closure: %w", err)
+	}
+	return nil
+}
+
+// GetPendingByAccount retrieves the closure in progress of an account
+func (r *AccountClosureRepository) GetPendingByAccount(accountID string) (*entities.AccountClosure, error) {
+	var closure entities.AccountClosure
+	err := r.db.Where("account_id = ? AND status = ?", accountID, entities.AccountClosureStatusPending).First(&closure).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get account closure: %w", err)
+	}
+	return &closure, nil
+}
+
+// GetLatestByAccount retrieves the most recent closure of an account with its audit trail
+func (r *AccountClosureRepository) GetLatestByAccount(accountID string) (*entities.AccountClosure, error) {
+	var closure entities.AccountClosure
+	err := r.db.Preload("Events", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("account_id = ?", accountID).Order("created_at DESC").First(&closure).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get account closure: %w", err)
+	}
+	return &closure, nil
+}
+
+// CreateEvent records a step of a closure
+func (r *AccountClosureRepository) CreateEvent(event *entities.AccountClosureEvent) error {
+	if err := r.db.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to record closure event: %w", err)
+	}
+	return nil
+}
+
+// GetOpenPlansByAccount retrieves the installment plans not yet finished on the cards of an account
+func (r *AccountClosureRepository) GetOpenPlansByAccount(accountID string) ([]*entities.InstallmentPlan, error) {
+	var plans []*entities.InstallmentPlan
+	err := r.db.
+		Where("card_id IN (?)", r.db.Model(&entities.Card{}).Select("id").Where("account_id = ?", accountID)).
+		Where("status IN (?, ?)", entities.InstallmentPlanStatusActive, entities.InstallmentPlanStatusSuspended).
+		Order("created_at ASC").
+		Find(&plans).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open installment plans: %w", err)
+	}
+	return plans, nil
+}
+
+// TransferBalance moves the balance between two accounts
+func (r *AccountClosureRepository) TransferBalance(from, to *entities.Account, event *entities.AccountClosureEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(from).Update("balance", from.Balance).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(to).Update("balance", to.Balance).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to transfer balance: %w", err)
+	}
+	return nil
+}
+
+// SettleCard saves a card settled against the balance of its account
+func (r *AccountClosureRepository) SettleCard(account *entities.Account, card *entities.Card, event *entities.AccountClosureEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(account).Update("balance", account.Balance).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(card).Update("balance", card.Balance).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to settle card: %w", err)
+	}
+	return nil
+}
+
+// TransferInstallmentPlan moves a plan and its remaining debt to another card
+func (r *AccountClosureRepository) TransferInstallmentPlan(plan *entities.InstallmentPlan, source, target *entities.Card, event *entities.AccountClosureEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(plan).Update("card_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(source).Update("balance", source.Balance).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(target).Update("balance", target.Balance).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to transfer installment plan: %w", err)
+	}
+	return nil
+}
+
+// Close marks the account closed, deactivates its cards and completes the closure
+func (r *AccountClosureRepository) Close(closure *entities.AccountClosure, account *entities.Account, event *entities.AccountClosureEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities.Card{}).
+			Where("account_id = ? AND status <> ?", account.ID, entities.CardStatusInactive).
+			Updates(map[string]interface{}{"status": entities.CardStatusInactive, "is_default": false}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(account).Updates(map[string]interface{}{
+			"is_active": false,
+			"closed_at": account.ClosedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Events").Save(closure).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to close account: %w", err)
+	}
+	return nil
+}
+
+// Cancel stops a closure in progress
+func (r *AccountClosureRepository) Cancel(closure *entities.AccountClosure, event *entities.AccountClosureEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Events").Save(closure).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel account closure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// CreditLimitRepository implements CreditLimitRepositoryInterface
+type CreditLimitRepository struct {
+	db *gorm.DB
+}
+
+// NewCreditLimitRepository creates a new credit limit repository
+func NewCreditLimitRepository(db *gorm.DB) ports.CreditLimitRepositoryInterface {
+	return &CreditLimitRepository{db: db}
+}
+
+// CreateRequest saves a credit limit change request
+func (r *CreditLimitRepository) CreateRequest(request *entities.CreditLimitChangeRequest) error {
+	if err := r.db.Create(request).Error; err != nil {
+		return fmt.Errorf("failed to create credit limit change request: %w", err)
+	}
+	return nil
+}
+
+// GetRequestByID retrieves a credit limit change request
+func (r *CreditLimitRepository) GetRequestByID(requestID string) (*entities.CreditLimitChangeRequest, error) {
+	var request entities.CreditLimitChangeRequest
+	if err := r.db.Where("id = ?", requestID).First(&request).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("credit limit change request not found")
+		}
+		return nil, fmt.Errorf("failed to get credit limit change request: %w", err)
+	}
+	return &request, nil
+}
+
+// UpdateRequest saves the review of a credit limit change request
+func (r *CreditLimitRepository) UpdateRequest(request *entities.CreditLimitChangeRequest) error {
+	if err := r.db.Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update credit limit change request: %w", err)
+	}
+	return nil
+}
+
+// GetPendingByTarget retrieves the request waiting for approval on an account or card
+func (r *CreditLimitRepository) GetPendingByTarget(accountID string, cardID *string) (*entities.CreditLimitChangeRequest, error) {
+	var request entities.CreditLimitChangeRequest
+	err := whereTarget(r.db, accountID, cardID).
+		Where("status = ?", entities.CreditLimitChangeStatusPending).
+		First(&request).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pending credit limit change request: %w", err)
+	}
+	return &request, nil
+}
+
+// GetRequestsByAccount retrieves the change requests of an account and its cards, newest first
+func (r *CreditLimitRepository) GetRequestsByAccount(accountID, status string, limit, offset int) ([]*entities.CreditLimitChangeRequest, int64, error) {
+	query := r.db.Model(&entities.CreditLimitChangeRequest{}).Where("account_id = ?", accountID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return findRequests(query, limit, offset)
+}
+
+// GetRequestsByStatus retrieves the change requests with a status, oldest first
+func (r *CreditLimitRepository) GetRequestsByStatus(status string, limit, offset int) ([]*entities.CreditLimitChangeRequest, int64, error) {
+	var requests []*entities.CreditLimitChangeRequest
+	var total int64
+
+	query := r.db.Model(&entities.CreditLimitChangeRequest{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count credit limit change requests: %w", err)
+	}
+	if err := query.Order("created_at ASC").Limit(limit).Offset(offset).Find(&requests).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get credit limit change requests: %w", err)
+	}
+	return requests, total, nil
+}
+
+// GetHistory retrieves the limits of an account or card, newest first
+func (r *CreditLimitRepository) GetHistory(accountID string, cardID *string) ([]*entities.CreditLimitHistory, error) {
+	var history []*entities.CreditLimitHistory
+	err := whereTarget(r.db, accountID, cardID).Order("effective_from DESC").Find(&history).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit limit history: %w", err)
+	}
+	return history, nil
+}
+
+// ApplyChange puts the requested limit in effect
+func (r *CreditLimitRepository) ApplyChange(request *entities.CreditLimitChangeRequest, entries ...*entities.CreditLimitHistory) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if len(entries) == 0 {
+			return fmt.Errorf("no credit limit history entry to record")
+		}
+		current := entries[len(entries)-1]
+
+		err := whereTarget(tx.Model(&entities.CreditLimitHistory{}), request.AccountID, request.CardID).
+			Where("effective_to IS NULL").
+			Update("effective_to", current.EffectiveFrom).Error
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := tx.Create(entry).Error; err != nil {
+				return err
+			}
+		}
+
+		if request.CardID != nil {
+			err = tx.Model(&entities.Card{}).Where("id = ?", *request.CardID).Update("credit_limit", request.RequestedLimit).Error
+		} else {
+			err = tx.Model(&entities.Account{}).Where("id = ?", request.AccountID).Update("credit_limit", request.RequestedLimit).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Save(request).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply credit limit change: %w", err)
+	}
+	return nil
+}
+
+// whereTarget filters credit limit records by account or card
+func whereTarget(db *gorm.DB, accountID string, cardID *string) *gorm.DB {
+	if cardID != nil {
+		return db.Where("account_id = ? AND card_id = ?", accountID, *cardID)
+	}
+	return db.Where("account_id = ? AND card_id IS NULL", accountID)
+}
+
+// findRequests counts and pages a change request query, newest first
+func findRequests(query *gorm.DB, limit, offset int) ([]*entities.CreditLimitChangeRequest, int64, error) {
+	var requests []*entities.CreditLimitChangeRequest
+	var total int64
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count credit limit change requests: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&requests).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get credit limit change requests: %w", err)
+	}
+	return requests, total, nil
+}
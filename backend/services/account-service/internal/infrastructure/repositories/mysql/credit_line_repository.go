@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// withCreditLine preloads what is needed to compute the credit available to a card:
+// its active installment plans and, for cards in a shared line, every card of the line with theirs
+func withCreditLine(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("InstallmentPlans", "status = ?", entities.InstallmentPlanStatusActive).
+		Preload("CreditLine.Cards.InstallmentPlans", "status = ?", entities.InstallmentPlanStatusActive)
+}
+
+// CreditLineRepository implements CreditLineRepositoryInterface
+type CreditLineRepository struct {
+	db *gorm.DB
+}
+
+// NewCreditLineRepository creates a new credit line repository
+func NewCreditLineRepository(db *gorm.DB) ports.CreditLineRepositoryInterface {
+	return &CreditLineRepository{db: db}
+}
+
+// Create saves a new credit line
+func (r *CreditLineRepository) Create(creditLine *entities.CreditLine) (*entities.CreditLine, error) {
+	if err := r.db.Omit(clause.Associations).Create(creditLine).Error; err != nil {
+		return nil, fmt.Errorf("failed to create credit line: %w", err)
+	}
+	return creditLine, nil
+}
+
+// GetByID retrieves a credit line with its cards and their active installment plans
+func (r *CreditLineRepository) GetByID(creditLineID string) (*entities.CreditLine, error) {
+	var creditLine entities.CreditLine
+	err := r.db.
+		Preload("Account").
+		Preload("Cards", func(db *gorm.DB) *gorm.DB {
+			return db.Order("is_additional ASC, created_at ASC")
+		}).
+		Preload("Cards.InstallmentPlans", "status = ?", entities.InstallmentPlanStatusActive).
+		Where("id = ?", creditLineID).
+		First(&creditLine).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("credit line not found")
+		}
+		return nil, fmt.Errorf("failed to get credit line: %w", err)
+	}
+	return &creditLine, nil
+}
+
+// GetByAccount retrieves the credit lines of an account with their cards and active installment plans
+func (r *CreditLineRepository) GetByAccount(accountID string) ([]*entities.CreditLine, error) {
+	var creditLines []*entities.CreditLine
+	err := r.db.
+		Preload("Cards", func(db *gorm.DB) *gorm.DB {
+			return db.Order("is_additional ASC, created_at ASC")
+		}).
+		Preload("Cards.InstallmentPlans", "status = ?", entities.InstallmentPlanStatusActive).
+		Where("account_id = ?", accountID).
+		Order("created_at ASC").
+		Find(&creditLines).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit lines: %w", err)
+	}
+	return creditLines, nil
+}
+
+// Update updates an existing credit line
+func (r *CreditLineRepository) Update(creditLine *entities.CreditLine) (*entities.CreditLine, error) {
+	if err := r.db.Omit(clause.Associations).Save(creditLine).Error; err != nil {
+		return nil, fmt.Errorf("failed to update credit line: %w", err)
+	}
+	return creditLine, nil
+}
@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// DebtPayoffRepository implements DebtPayoffRepositoryInterface
+type DebtPayoffRepository struct {
+	db *gorm.DB
+}
+
+// NewDebtPayoffRepository creates a new debt payoff repository
+func NewDebtPayoffRepository(db *gorm.DB) ports.DebtPayoffRepositoryInterface {
+	return &DebtPayoffRepository{db: db}
+}
+
+// Create saves a payoff plan and abandons the plan the user was following, in a single transaction
+func (r *DebtPayoffRepository) Create(plan *entities.DebtPayoffPlan) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities.DebtPayoffPlan{}).
+			Where("user_id = ? AND status = ?", plan.UserID, entities.DebtPayoffPlanStatusActive).
+			Updates(map[string]interface{}{
+				"status":       entities.DebtPayoffPlanStatusAbandoned,
+				"abandoned_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(plan).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create debt payoff plan: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a payoff plan
+func (r *DebtPayoffRepository) GetByID(planID string) (*entities.DebtPayoffPlan, error) {
+	var plan entities.DebtPayoffPlan
+	if err := r.db.Where("id = ?", planID).First(&plan).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("debt payoff plan not found")
+		}
+		return nil, fmt.Errorf("failed to get debt payoff plan: %w", err)
+	}
+	return &plan, nil
+}
+
+// GetByUser retrieves the payoff plans of a user, newest first
+func (r *DebtPayoffRepository) GetByUser(userID, status string) ([]*entities.DebtPayoffPlan, error) {
+	var plans []*entities.DebtPayoffPlan
+	query := r.db.Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Find(&plans).Error; err != nil {
+		return nil, fmt.Errorf("failed to get debt payoff plans: %w", err)
+	}
+	return plans, nil
+}
+
+// Update saves the status of a payoff plan
+func (r *DebtPayoffRepository) Update(plan *entities.DebtPayoffPlan) error {
+	if err := r.db.Save(plan).Error; err != nil {
+		return fmt.Errorf("failed to update debt payoff plan: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fintrack/account-service/internal/core/domain/entities"
+	"github.com/fintrack/account-service/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// InflationRepository implements InflationRepositoryInterface
+type InflationRepository struct {
+	db *gorm.DB
+}
+
+// NewInflationRepository creates a new inflation repository
+func NewInflationRepository(db *gorm.DB) ports.InflationRepositoryInterface {
+	return &InflationRepository{db: db}
+}
+
+// GetSeries retrieves the monthly rates of a series, oldest first
+func (r *InflationRepository) GetSeries(series string) ([]*entities.InflationRate, error) {
+	var rates []*entities.InflationRate
+	if err := r.db.Where("series = ?", series).Order("period ASC").Find(&rates).Error; err != nil {
+		return nil, fmt.Errorf("failed to get inflation series: %w", err)
+	}
+	return rates, nil
+}
+
+// GetSeriesSince retrieves the monthly rates of a series from a month on, oldest first, preceded by
+// the last rate before that month so that the months the series does not cover can carry it
+func (r *InflationRepository) GetSeriesSince(series string, from time.Time) ([]*entities.InflationRate, error) {
+	var previous []*entities.InflationRate
+	if err := r.db.Where("series = ? AND period < ?", series, from.Format("2006-01-02")).Order("period DESC").Limit(1).Find(&previous).Error; err != nil {
+		return nil, fmt.Errorf("failed to get inflation series: %w", err)
+	}
+
+	var rates []*entities.InflationRate
+	if err := r.db.Where("series = ? AND period >= ?", series, from.Format("2006-01-02")).Order("period ASC").Find(&rates).Error; err != nil {
+		return nil, fmt.Errorf("failed to get inflation series: %w", err)
+	}
+	return append(previous, rates...), nil
+}
+
+// SaveRates creates the monthly rates of a series, replacing the rates already stored for the same months
+func (r *InflationRepository) SaveRates(rates []*entities.InflationRate) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, rate := range rates {
+			// The connection converts times to its location, so the month is kept there to store the right date
+			rate.Period = time.Date(rate.Period.Year(), rate.Period.Month(), 1, 0, 0, 0, 0, time.Local)
+
+			var existing entities.InflationRate
+			err := tx.Where("series = ? AND period = ?", rate.Series, rate.Period.Format("2006-01-02")).First(&existing).Error
+			if err == gorm.ErrRecordNotFound {
+				if err := tx.Create(rate).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			rate.ID = existing.ID
+			rate.CreatedAt = existing.CreatedAt
+			if err := tx.Save(rate).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save inflation rates: %w", err)
+	}
+	return nil
+}
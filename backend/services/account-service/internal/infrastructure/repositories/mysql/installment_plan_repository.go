@@ -86,17 +86,18 @@ func (r *InstallmentPlanRepository) GetByCard(cardID string, status string, limi
 	return plans, total, nil
 }
 
-// GetByUser retrieves installment plans by user ID with optional status filter
+// GetByUser retrieves installment plans visible to a user (including shared cards) with optional status filter
 func (r *InstallmentPlanRepository) GetByUser(userID string, status string, limit, offset int) ([]*entities.InstallmentPlan, int64, error) {
 	var plans []*entities.InstallmentPlan
 	var total int64
 
+	planCondition, planArgs := accessiblePlansCondition(userID)
 	query := r.db.Model(&entities.InstallmentPlan{}).
 		Preload("Card").
-		Where("user_id = ?", userID)
+		Where(planCondition, planArgs...)
 
 	if status != "" {
-		query = query.Where("status = ?", status)
+		query = query.Where("installment_plans.status = ?", status)
 	}
 
 	// Get total count
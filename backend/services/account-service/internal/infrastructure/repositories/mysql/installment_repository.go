@@ -94,9 +94,11 @@ func (r *InstallmentRepository) GetOverdue(userID string, limit, offset int) ([]
 	var installments []*entities.Installment
 	var total int64
 
+	planCondition, planArgs := accessiblePlansCondition(userID)
 	query := r.db.Model(&entities.Installment{}).
 		Joins("JOIN installment_plans ON installments.plan_id = installment_plans.id").
-		Where("installment_plans.user_id = ? AND installments.status = ?", userID, entities.InstallmentStatusOverdue).
+		Where(planCondition, planArgs...).
+		Where("installments.status = ?", entities.InstallmentStatusOverdue).
 		Preload("Plan").
 		Preload("Plan.Card")
 
@@ -125,10 +127,11 @@ func (r *InstallmentRepository) GetUpcoming(userID string, days int, limit, offs
 
 	cutoffDate := time.Now().AddDate(0, 0, days)
 
+	planCondition, planArgs := accessiblePlansCondition(userID)
 	query := r.db.Model(&entities.Installment{}).
 		Joins("JOIN installment_plans ON installments.plan_id = installment_plans.id").
-		Where("installment_plans.user_id = ? AND installments.status IN ? AND installments.due_date <= ?",
-			userID,
+		Where(planCondition, planArgs...).
+		Where("installments.status IN ? AND installments.due_date <= ?",
 			[]string{string(entities.InstallmentStatusPending), string(entities.InstallmentStatusOverdue)},
 			cutoffDate).
 		Preload("Plan").
@@ -156,9 +159,10 @@ func (r *InstallmentRepository) GetUpcoming(userID string, days int, limit, offs
 func (r *InstallmentRepository) GetByDueDateRange(userID string, startDate, endDate time.Time) ([]*entities.Installment, error) {
 	var installments []*entities.Installment
 
+	planCondition, planArgs := accessiblePlansCondition(userID)
 	err := r.db.Joins("JOIN installment_plans ON installments.plan_id = installment_plans.id").
-		Where("installment_plans.user_id = ? AND installments.due_date BETWEEN ? AND ?",
-			userID, startDate, endDate).
+		Where(planCondition, planArgs...).
+		Where("installments.due_date BETWEEN ? AND ?", startDate, endDate).
 		Preload("Plan").
 		Preload("Plan.Card").
 		Order("installments.due_date ASC").
@@ -16,6 +16,7 @@ type ChatbotService interface {
 type DataProvider interface {
 	GetTotals(ctx context.Context, userID string, from, to time.Time) (Totals, error)
 	GetByType(ctx context.Context, userID string, from, to time.Time) (map[string]float64, error)
+	GetByCategory(ctx context.Context, userID string, from, to time.Time) (map[string]float64, error)
 	GetTopMerchants(ctx context.Context, userID string, from, to time.Time, limit int) ([]MerchantTotal, error)
 	GetByAccountType(ctx context.Context, userID string, from, to time.Time) (map[string]float64, error)
 	GetByCard(ctx context.Context, userID string, from, to time.Time) ([]CardTotal, error)
@@ -28,6 +29,10 @@ type DataProvider interface {
 	GetAccountsInfo(ctx context.Context, userID string) ([]AccountInfo, error)
 	GetCardsInfo(ctx context.Context, userID string) ([]CardInfo, error)
 	GetExchangeRates(ctx context.Context, userID string, from, to time.Time) ([]ExchangeRateInfo, error)
+	// Suscripciones detectadas por transaction-service
+	GetSubscriptions(ctx context.Context, userID string) ([]SubscriptionInfo, error)
+	// Presupuestos mensuales calculados por transaction-service
+	GetBudgets(ctx context.Context, userID string, from, to time.Time) ([]BudgetInfo, error)
 }
 
 // LLMProvider interfaz al motor LLM (compatible con Ollama, Groq, etc.)
@@ -187,11 +192,41 @@ type ExchangeRateInfo struct {
 	CreatedAt    time.Time `json:"createdAt"`
 }
 
+// Suscripción detectada a partir de cobros recurrentes
+type SubscriptionInfo struct {
+	MerchantName   string     `json:"merchantName"`
+	Period         string     `json:"period"` // weekly, biweekly, monthly, quarterly, yearly
+	Amount         float64    `json:"amount"`
+	Currency       string     `json:"currency"`
+	AnnualizedCost float64    `json:"annualizedCost"`
+	Status         string     `json:"status"` // active, missed, cancelled
+	LastChargeAt   time.Time  `json:"lastChargeAt"`
+	NextExpectedAt time.Time  `json:"nextExpectedAt"`
+	PreviousAmount *float64   `json:"previousAmount"`
+	PriceChangedAt *time.Time `json:"priceChangedAt"`
+}
+
+// Presupuesto de un mes contra el gasto real (incluye las cuotas que vencen en el mes)
+type BudgetInfo struct {
+	Name         string  `json:"name"`
+	Scope        string  `json:"scope"`  // category, merchant, card
+	Target       string  `json:"target"` // Categoría, comercio o tarjeta
+	Month        string  `json:"month"`  // Format: "2025-11"
+	Currency     string  `json:"currency"`
+	Limit        float64 `json:"limit"`
+	Actual       float64 `json:"actual"`
+	Installments float64 `json:"installments"`
+	Remaining    float64 `json:"remaining"`
+	PercentUsed  float64 `json:"percentUsed"`
+	Status       string  `json:"status"` // on_track, warning, exceeded
+}
+
 type ReportData struct {
 	Title         string
 	Period        Period
 	Totals        Totals
 	ByType        map[string]float64
+	ByCategory    map[string]float64
 	TopMerchants  []MerchantTotal
 	ByAccountType map[string]float64
 	ByCard        []CardTotal
@@ -96,14 +96,27 @@ GASTOS: $%.2f | INGRESOS: $%.2f`,
 		creditCharges := getVal(byType, "credit_charge")
 		debitPurchases := getVal(byType, "debit_purchase")
 		allExpenses := totals.Expenses + installmentPayments
+		byCategory, _ := s.data.GetByCategory(ctx, req.UserID, req.Period.From, req.Period.To)
 
 		ctxText = fmt.Sprintf(`GASTOS DETALLADOS:
 - Gastos directos: $%.2f
 - Pagos de cuotas: $%.2f
 - Cargos tarjetas: $%.2f  
 - Compras débito: $%.2f
+GASTOS POR CATEGORÍA: %s
 TOTAL GASTOS: $%.2f | INGRESOS: $%.2f`,
-			totals.Expenses, installmentPayments, creditCharges, debitPurchases, allExpenses, totals.Incomes)
+			totals.Expenses, installmentPayments, creditCharges, debitPurchases, formatByCategory(byCategory), allExpenses, totals.Incomes)
+	case "subscriptions":
+		subscriptions, _ := s.data.GetSubscriptions(ctx, req.UserID)
+		ctxText = fmt.Sprintf(`SUSCRIPCIONES: %s
+COSTO ANUAL DE LAS QUE SIGUEN COBRANDO: %s
+GASTOS: $%.2f | INGRESOS: $%.2f`,
+			formatSubscriptions(subscriptions), formatSubscriptionsCost(subscriptions), totals.Expenses, totals.Incomes)
+	case "budgets":
+		budgets, _ := s.data.GetBudgets(ctx, req.UserID, req.Period.From, req.Period.To)
+		ctxText = fmt.Sprintf(`PRESUPUESTOS: %s
+GASTOS: $%.2f | INGRESOS: $%.2f`,
+			formatBudgets(budgets), totals.Expenses, totals.Incomes)
 	case "income":
 		ctxText = fmt.Sprintf(`INGRESOS TOTALES: $%.2f | GASTOS: $%.2f
 PLANES ACTIVOS: %d`,
@@ -150,6 +163,7 @@ DETALLE PLANES: %s`,
 func (s *ChatbotServiceImpl) GeneratePDF(ctx context.Context, req ports.ReportRequest) ([]byte, error) {
 	totals, _ := s.data.GetTotals(ctx, req.UserID, req.Period.From, req.Period.To)
 	byType, _ := s.data.GetByType(ctx, req.UserID, req.Period.From, req.Period.To)
+	byCategory, _ := s.data.GetByCategory(ctx, req.UserID, req.Period.From, req.Period.To)
 	topMerchants, _ := s.data.GetTopMerchants(ctx, req.UserID, req.Period.From, req.Period.To, 10)
 	byAccount, _ := s.data.GetByAccountType(ctx, req.UserID, req.Period.From, req.Period.To)
 	byCard, _ := s.data.GetByCard(ctx, req.UserID, req.Period.From, req.Period.To)
@@ -159,6 +173,7 @@ func (s *ChatbotServiceImpl) GeneratePDF(ctx context.Context, req ports.ReportRe
 		Period:        req.Period,
 		Totals:        totals,
 		ByType:        byType,
+		ByCategory:    byCategory,
 		TopMerchants:  topMerchants,
 		ByAccountType: byAccount,
 		ByCard:        byCard,
@@ -194,8 +209,20 @@ func (s *ChatbotServiceImpl) GenerateChartData(ctx context.Context, req ports.Ch
 			values = append(values, c.Total)
 		}
 		return ports.ChartResponse{Labels: labels, Datasets: []ports.ChartDataset{{Label: "Por tarjeta", Data: values, BackgroundColor: []string{"#10b981"}}}, Meta: map[string]any{"currency": req.Currency}}, nil
+	case "category", "categories":
+		byCat, err := s.data.GetByCategory(ctx, req.UserID, req.Period.From, req.Period.To)
+		if err != nil {
+			return ports.ChartResponse{}, err
+		}
+		labels := make([]string, 0, len(byCat))
+		values := make([]float64, 0, len(byCat))
+		for k, v := range byCat {
+			labels = append(labels, k)
+			values = append(values, v)
+		}
+		return ports.ChartResponse{Labels: labels, Datasets: []ports.ChartDataset{{Label: "Por categoría", Data: values, BackgroundColor: []string{"#f59e0b"}}}, Meta: map[string]any{"currency": req.Currency}}, nil
 	default:
-		return ports.ChartResponse{}, fmt.Errorf("groupBy inválido: use 'account' o 'card'")
+		return ports.ChartResponse{}, fmt.Errorf("groupBy inválido: use 'account', 'card' o 'category'")
 	}
 }
 
@@ -234,6 +261,25 @@ func formatMerchants(ms []ports.MerchantTotal) string {
 	return strings.Join(out, ", ")
 }
 
+func formatByCategory(m map[string]float64) string {
+	if len(m) == 0 {
+		return "(sin datos)"
+	}
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Slice(names, func(i, j int) bool { return m[names[i]] > m[names[j]] })
+	if len(names) > 5 {
+		names = names[:5]
+	}
+	out := make([]string, 0, len(names))
+	for _, k := range names {
+		out = append(out, fmt.Sprintf("%s (%.2f)", k, m[k]))
+	}
+	return strings.Join(out, ", ")
+}
+
 func formatByCard(cs []ports.CardTotal) string {
 	if len(cs) == 0 {
 		return "(sin datos)"
@@ -286,6 +332,91 @@ func formatPlans(plans []ports.InstallmentPlanInfo) string {
 	return strings.Join(out, " | ")
 }
 
+func formatSubscriptions(subscriptions []ports.SubscriptionInfo) string {
+	if len(subscriptions) == 0 {
+		return "(sin suscripciones detectadas)"
+	}
+	n := len(subscriptions)
+	if n > 10 {
+		n = 10
+	}
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		sub := subscriptions[i]
+		status := sub.Status
+		switch sub.Status {
+		case "active":
+			status = "activa"
+		case "missed":
+			status = "sin cobro"
+		case "cancelled":
+			status = "cancelada"
+		}
+
+		line := fmt.Sprintf("%s: %s %.2f %s (anual %.2f), último cobro %s, próximo %s, estado %s",
+			sub.MerchantName, sub.Currency, sub.Amount, sub.Period, sub.AnnualizedCost,
+			sub.LastChargeAt.Format("2006-01-02"), sub.NextExpectedAt.Format("2006-01-02"), status)
+		if sub.PreviousAmount != nil {
+			line += fmt.Sprintf(", cambió de %.2f el %s", *sub.PreviousAmount, formatDate(sub.PriceChangedAt))
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, " | ")
+}
+
+func formatSubscriptionsCost(subscriptions []ports.SubscriptionInfo) string {
+	byCurrency := map[string]float64{}
+	var currencies []string
+	for _, sub := range subscriptions {
+		if sub.Status == "cancelled" {
+			continue
+		}
+		if _, ok := byCurrency[sub.Currency]; !ok {
+			currencies = append(currencies, sub.Currency)
+		}
+		byCurrency[sub.Currency] += sub.AnnualizedCost
+	}
+	if len(currencies) == 0 {
+		return "$0.00"
+	}
+	out := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		out = append(out, fmt.Sprintf("%s %.2f (mensual %.2f)", currency, byCurrency[currency], byCurrency[currency]/12))
+	}
+	return strings.Join(out, " | ")
+}
+
+func formatBudgets(budgets []ports.BudgetInfo) string {
+	if len(budgets) == 0 {
+		return "(sin presupuestos en el período)"
+	}
+	n := len(budgets)
+	if n > 15 {
+		n = 15
+	}
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		b := budgets[i]
+		status := b.Status
+		switch b.Status {
+		case "on_track":
+			status = "en camino"
+		case "warning":
+			status = "en alerta"
+		case "exceeded":
+			status = "excedido"
+		}
+
+		line := fmt.Sprintf("%s (%s %s) %s: gastado %s %.2f de %.2f (%.0f%%), disponible %.2f, estado %s",
+			b.Name, b.Scope, b.Target, b.Month, b.Currency, b.Actual, b.Limit, b.PercentUsed, b.Remaining, status)
+		if b.Installments > 0 {
+			line += fmt.Sprintf(", incluye cuotas %.2f", b.Installments)
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, " | ")
+}
+
 func formatTransactions(transactions []ports.TransactionDetail) string {
 	if len(transactions) == 0 {
 		return "(sin transacciones)"
@@ -409,6 +540,10 @@ func buildContextualPrompt(contextFocus string) string {
 		return base + " ENFOQUE: Analiza TARJETAS (incluye cuotas y consumos). Lee información de límites, deudas y próximos vencimientos. Ejemplo: 'Gastos con tarjetas: $X'"
 	case "installments":
 		return base + " ENFOQUE: Analiza CUOTAS Y PLANES detalladamente. DEBES interpretar fechas de vencimiento, montos pendientes, estados. Si preguntan por vencimientos futuros, analiza las fechas 'próximo vencimiento' de cada plan. Ejemplo: 'Plan X vence el Y con monto Z'"
+	case "subscriptions":
+		return base + " ENFOQUE: Analiza SUSCRIPCIONES (cobros recurrentes detectados). Interpreta frecuencia, costo anual, aumentos de precio y estado: 'sin cobro' es un cobro esperado que no llegó, 'cancelada' dejó de cobrar. Ejemplo: 'Pagás $X al año en suscripciones; Netflix aumentó de $Y a $Z'"
+	case "budgets":
+		return base + " ENFOQUE: Analiza PRESUPUESTOS mensuales contra el gasto real. Interpreta límite, gastado (incluye cuotas del mes), disponible y estado: 'en alerta' pasó el 50% o el 80%, 'excedido' superó el límite. Ejemplo: 'En Supermercado gastaste $X de $Y (Z%), te quedan $W'"
 	case "merchants":
 		return base + " ENFOQUE: Analiza COMERCIOS y patrones de gasto. Interpreta nombres de comercios y montos. Ejemplo: 'Gastaste más en: Comercio X ($Y)'"
 	default:
@@ -104,8 +104,15 @@ func InferContextFromMessage(message string, prevContext *ports.InferredContext)
 	// === DETECT CONTEXT FOCUS ===
 	contextDetected := false
 
+	// Budgets context (before cards and merchants: a budget can be set on either)
+	if containsAny(msgLower, "presupuest", "budget", "me pasé", "límite de gasto") {
+		result.ContextFocus = "budgets"
+		result.DetectedKeywords = append(result.DetectedKeywords, "context:budgets")
+		contextDetected = true
+	}
+
 	// Cards context
-	if containsAny(msgLower, "tarjeta", "card", "crédito", "débito", "credit", "debit", "plástico") {
+	if !contextDetected && containsAny(msgLower, "tarjeta", "card", "crédito", "débito", "credit", "debit", "plástico") {
 		result.ContextFocus = "cards"
 		result.DetectedKeywords = append(result.DetectedKeywords, "context:cards")
 		contextDetected = true
@@ -27,8 +27,15 @@ func (p *DataProvider) GetTotals(ctx context.Context, userID string, from, to ti
 	return ports.Totals{Expenses: exp.Float64, Incomes: inc.Float64}, nil
 }
 
+// GetByType suma por tipo de transacción. En las transacciones divididas solo cuentan las partes
+// del usuario; las asignadas a otra persona (counterpart) no son gasto propio
 func (p *DataProvider) GetByType(ctx context.Context, userID string, from, to time.Time) (map[string]float64, error) {
-	q := `SELECT type, SUM(amount) AS total FROM transactions WHERE user_id=? AND status='completed' AND created_at BETWEEN ? AND ? GROUP BY type`
+	q := `SELECT t.type, SUM(COALESCE(a.amount, t.amount)) AS total
+          FROM transactions t
+          LEFT JOIN transaction_allocations a ON a.transaction_id = t.id
+          WHERE t.user_id=? AND t.status='completed' AND t.created_at BETWEEN ? AND ?
+            AND a.counterpart_user_id IS NULL
+          GROUP BY t.type`
 	rows, err := p.db.QueryContext(ctx, q, userID, from, to)
 	if err != nil {
 		return nil, err
@@ -46,6 +53,37 @@ func (p *DataProvider) GetByType(ctx context.Context, userID string, from, to ti
 	return res, rows.Err()
 }
 
+// GetByCategory suma los gastos por categoría principal (las subcategorías se agrupan en su padre).
+// Las transacciones divididas aportan cada parte propia a su categoría
+func (p *DataProvider) GetByCategory(ctx context.Context, userID string, from, to time.Time) (map[string]float64, error) {
+	q := `SELECT COALESCE(pc.name, c.name, 'Sin categoría') AS category, SUM(COALESCE(a.amount, t.amount)) AS total
+          FROM transactions t
+          LEFT JOIN transaction_allocations a ON a.transaction_id = t.id
+          LEFT JOIN transaction_categories c ON c.id = COALESCE(a.category_id, t.category_id)
+          LEFT JOIN transaction_categories pc ON pc.id = c.parent_id
+          WHERE t.user_id=? 
+            AND t.status='completed' 
+            AND t.type IN ('debit_purchase','credit_charge','wallet_withdrawal','account_withdraw') 
+            AND t.created_at BETWEEN ? AND ? 
+            AND a.counterpart_user_id IS NULL
+          GROUP BY category`
+	rows, err := p.db.QueryContext(ctx, q, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := map[string]float64{}
+	for rows.Next() {
+		var c string
+		var total float64
+		if err := rows.Scan(&c, &total); err != nil {
+			return nil, err
+		}
+		res[c] = total
+	}
+	return res, rows.Err()
+}
+
 func (p *DataProvider) GetTopMerchants(ctx context.Context, userID string, from, to time.Time, limit int) ([]ports.MerchantTotal, error) {
 	q := `SELECT merchant_name, SUM(amount) AS total 
           FROM transactions 
@@ -393,3 +431,75 @@ func (p *DataProvider) GetExchangeRates(ctx context.Context, userID string, from
 	}
 	return res, rows.Err()
 }
+
+// GetSubscriptions obtiene las suscripciones detectadas, primero las que siguen cobrando
+func (p *DataProvider) GetSubscriptions(ctx context.Context, userID string) ([]ports.SubscriptionInfo, error) {
+	q := `SELECT 
+        merchant_name, period, amount, currency, annualized_cost, status,
+        last_charge_at, next_expected_at, previous_amount, price_changed_at
+      FROM detected_subscriptions
+      WHERE user_id = ?
+      ORDER BY status = 'cancelled', annualized_cost DESC`
+
+	rows, err := p.db.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []ports.SubscriptionInfo
+	for rows.Next() {
+		var (
+			s              ports.SubscriptionInfo
+			previousAmount sql.NullFloat64
+			priceChangedAt sql.NullTime
+		)
+		if err := rows.Scan(&s.MerchantName, &s.Period, &s.Amount, &s.Currency, &s.AnnualizedCost, &s.Status,
+			&s.LastChargeAt, &s.NextExpectedAt, &previousAmount, &priceChangedAt); err != nil {
+			return nil, err
+		}
+		if previousAmount.Valid && priceChangedAt.Valid {
+			amount, changedAt := previousAmount.Float64, priceChangedAt.Time
+			s.PreviousAmount = &amount
+			s.PriceChangedAt = &changedAt
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+// GetBudgets obtiene los presupuestos de los meses del período contra el gasto real, tal como los dejó
+// calculados transaction-service
+func (p *DataProvider) GetBudgets(ctx context.Context, userID string, from, to time.Time) ([]ports.BudgetInfo, error) {
+	q := `SELECT 
+        b.name, b.scope,
+        CASE b.scope
+          WHEN 'category' THEN COALESCE(tc.name, '')
+          WHEN 'merchant' THEN COALESCE(b.merchant_name, '')
+          ELSE COALESCE(CONCAT(UPPER(cd.card_brand), ' ', cd.last_four_digits), '')
+        END AS target,
+        bp.month, b.currency, bp.limit_amount, bp.actual, bp.installments, bp.remaining, bp.percent_used, bp.status
+      FROM budget_periods bp
+      JOIN budgets b ON b.id = bp.budget_id
+      LEFT JOIN transaction_categories tc ON tc.id = b.category_id
+      LEFT JOIN cards cd ON cd.id = b.card_id
+      WHERE bp.user_id = ? AND bp.month BETWEEN ? AND ?
+      ORDER BY bp.month DESC, bp.percent_used DESC`
+
+	rows, err := p.db.QueryContext(ctx, q, userID, from.Format("2006-01"), to.Format("2006-01"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []ports.BudgetInfo
+	for rows.Next() {
+		var b ports.BudgetInfo
+		if err := rows.Scan(&b.Name, &b.Scope, &b.Target, &b.Month, &b.Currency, &b.Limit, &b.Actual,
+			&b.Installments, &b.Remaining, &b.PercentUsed, &b.Status); err != nil {
+			return nil, err
+		}
+		res = append(res, b)
+	}
+	return res, rows.Err()
+}
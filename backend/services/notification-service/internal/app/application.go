@@ -54,6 +54,7 @@ func NewApplication() *Application {
 	cardRepo := database.NewCardRepository(dbConnection.DB)
 	installmentRepo := database.NewInstallmentRepository(dbConnection.DB)
 	notificationRepo := database.NewNotificationRepository(dbConnection.DB)
+	userRepo := database.NewUserRepository(dbConnection.DB)
 	log.Println("✅ Repositorios creados")
 
 	// Crear cliente EmailJS
@@ -65,6 +66,7 @@ func NewApplication() *Application {
 		cardRepo,
 		installmentRepo,
 		notificationRepo,
+		userRepo,
 		emailClient,
 	)
 	log.Println("✅ Servicio de notificaciones creado")
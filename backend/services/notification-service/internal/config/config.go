@@ -47,6 +47,10 @@ type JobConfig struct {
 	Enabled  bool
 	Schedule string
 	Timezone string
+
+	// Job de expiración de tarjetas
+	CardExpirySchedule   string
+	CardExpiryNoticeDays int
 }
 
 // LoggingConfig configuración de logging
@@ -82,6 +86,9 @@ func LoadConfig() *Config {
 			Enabled:  getBoolEnv("JOB_ENABLED", true),
 			Schedule: getEnv("JOB_SCHEDULE", "0 8 * * *"), // 8:00 AM daily
 			Timezone: getEnv("JOB_TIMEZONE", "America/Argentina/Buenos_Aires"),
+
+			CardExpirySchedule:   getEnv("CARD_EXPIRY_JOB_SCHEDULE", "0 7 * * *"), // 7:00 AM daily
+			CardExpiryNoticeDays: getIntEnv("CARD_EXPIRY_NOTICE_DAYS", 30),
 		},
 		Logging: LoggingConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
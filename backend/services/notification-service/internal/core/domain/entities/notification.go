@@ -12,6 +12,8 @@ type Card struct {
 	DueDate  time.Time `json:"due_date" db:"due_date"`
 	IsActive bool      `json:"is_active" db:"is_active"`
 	CardType string    `json:"card_type" db:"card_type"`
+	// Último día de validez de la tarjeta (solo para el job de expiración)
+	ExpirationDate time.Time `json:"expiration_date" db:"expiration_date"`
 	// Campos del usuario (obtenidos via JOIN)
 	UserEmail     string `json:"user_email" db:"user_email"`
 	UserFirstName string `json:"user_first_name" db:"user_first_name"`
@@ -69,6 +71,96 @@ type CardDueNotification struct {
 	InstallmentDetails  []InstallmentSummary `json:"installment_details"`
 }
 
+// CardExpiryNotification contiene datos para el aviso de expiración de tarjeta
+type CardExpiryNotification struct {
+	CardID                 string    `json:"card_id"`
+	UserID                 string    `json:"user_id"`
+	UserEmail              string    `json:"user_email"`
+	UserName               string    `json:"user_name"`
+	CardName               string    `json:"card_name"`
+	BankName               string    `json:"bank_name"`
+	LastFour               string    `json:"last_four"`
+	ExpirationDate         time.Time `json:"expiration_date"`
+	DaysUntilExpiry        int       `json:"days_until_expiry"`
+	ActiveInstallmentPlans int       `json:"active_installment_plans"`
+}
+
+// UserContact contiene los datos de contacto de un usuario
+type UserContact struct {
+	ID        string `json:"id" db:"id"`
+	Email     string `json:"email" db:"email"`
+	FirstName string `json:"first_name" db:"first_name"`
+	LastName  string `json:"last_name" db:"last_name"`
+}
+
+// GetFullName retorna el nombre completo del usuario
+func (u *UserContact) GetFullName() string {
+	return u.FirstName + " " + u.LastName
+}
+
+// ScheduledTransactionFailure es el aviso de transaction-service cuando una transacción programada no se pudo ejecutar
+type ScheduledTransactionFailure struct {
+	UserID       string    `json:"userId" binding:"required"`
+	ScheduleID   string    `json:"scheduleId" binding:"required"`
+	Description  string    `json:"description"`
+	Amount       float64   `json:"amount"`
+	Currency     string    `json:"currency"`
+	ScheduledFor time.Time `json:"scheduledFor"`
+	Reason       string    `json:"reason"`
+	Paused       bool      `json:"paused"` // La programación quedó pausada por fallas consecutivas
+}
+
+// ScheduledTransactionFailureNotification contiene datos para el aviso de falla de una transacción programada
+type ScheduledTransactionFailureNotification struct {
+	ScheduledTransactionFailure
+	UserEmail string `json:"user_email"`
+	UserName  string `json:"user_name"`
+}
+
+// FraudAlert es el aviso de transaction-service cuando una transacción alcanza el umbral de alerta de riesgo
+type FraudAlert struct {
+	UserID        string    `json:"userId" binding:"required"`
+	TransactionID string    `json:"transactionId" binding:"required"`
+	Description   string    `json:"description"`
+	MerchantName  string    `json:"merchantName"`
+	Amount        float64   `json:"amount"`
+	Currency      string    `json:"currency"`
+	Score         int       `json:"score"`
+	Action        string    `json:"action" binding:"required,oneof=alert hold block"` // Lo que se hizo con la transacción
+	Reasons       []string  `json:"reasons"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// FraudAlertNotification contiene datos para el aviso de una transacción sospechosa
+type FraudAlertNotification struct {
+	FraudAlert
+	UserEmail string `json:"user_email"`
+	UserName  string `json:"user_name"`
+}
+
+// BudgetAlert es el aviso de transaction-service cuando un presupuesto mensual alcanza un umbral de alerta
+type BudgetAlert struct {
+	UserID       string  `json:"userId" binding:"required"`
+	BudgetID     string  `json:"budgetId" binding:"required"`
+	BudgetName   string  `json:"budgetName"`
+	Scope        string  `json:"scope"` // category, merchant o card
+	Month        string  `json:"month" binding:"required"`
+	Threshold    int     `json:"threshold" binding:"required,oneof=50 80 100"`
+	Limit        float64 `json:"limit"`
+	Actual       float64 `json:"actual"`
+	Remaining    float64 `json:"remaining"`
+	PercentUsed  float64 `json:"percentUsed"`
+	Installments float64 `json:"installments"` // Parte del gasto que son cuotas del mes
+	Currency     string  `json:"currency"`
+}
+
+// BudgetAlertNotification contiene datos para el aviso de un presupuesto que alcanzó un umbral
+type BudgetAlertNotification struct {
+	BudgetAlert
+	UserEmail string `json:"user_email"`
+	UserName  string `json:"user_name"`
+}
+
 // NotificationLog para auditoría
 type NotificationLog struct {
 	ID           string    `json:"id" db:"id"`
@@ -10,11 +10,21 @@ import (
 type CardRepository interface {
 	GetCardsDueTomorrow() ([]*entities.Card, error)
 	UpdateExpiredDueDates() (int, error)
+	GetCardsExpiringIn(days int) ([]*entities.Card, error)
+	ClaimExpiryNotice(cardID string) (bool, error)
+	ReleaseExpiryNotice(cardID string) error
+	ExpireCards() (int, error)
 }
 
 // InstallmentRepository define las operaciones de repositorio para cuotas
 type InstallmentRepository interface {
 	GetPendingInstallmentsByCard(cardID string, maxDueDate time.Time) ([]*entities.Installment, error)
+	CountActivePlansByCard(cardID string) (int, error)
+}
+
+// UserRepository define las operaciones de repositorio para usuarios
+type UserRepository interface {
+	GetUserContact(userID string) (*entities.UserContact, error)
 }
 
 // NotificationRepository define las operaciones de repositorio para notificaciones
@@ -29,7 +39,11 @@ type NotificationRepository interface {
 // EmailService define las operaciones de servicio de email
 type EmailService interface {
 	SendCardDueNotification(notification *entities.CardDueNotification) error
+	SendCardExpiryNotification(notification *entities.CardExpiryNotification) error
 	SendSupportEmail(name, email, subject, message string) error
+	SendScheduledTransactionFailure(notification *entities.ScheduledTransactionFailureNotification) error
+	SendFraudAlert(notification *entities.FraudAlertNotification) error
+	SendBudgetAlert(notification *entities.BudgetAlertNotification) error
 }
 
 // NotificationService define las operaciones del servicio de notificaciones
@@ -37,7 +51,11 @@ type NotificationService interface {
 	ProcessCardDueNotifications() error
 	TriggerManualJob() error
 	UpdateExpiredDueDates() error
+	ProcessCardExpiry(noticeDays int) error
 	GetJobHistory(limit int) ([]*entities.JobRun, error)
 	GetNotificationLogs(jobRunID string, limit int) ([]*entities.NotificationLog, error)
 	SendSupportEmail(name, email, subject, message string) error
+	NotifyScheduledTransactionFailure(failure *entities.ScheduledTransactionFailure) error
+	NotifyFraudAlert(alert *entities.FraudAlert) error
+	NotifyBudgetAlert(alert *entities.BudgetAlert) error
 }
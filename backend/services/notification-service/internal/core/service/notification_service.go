@@ -15,6 +15,7 @@ type NotificationService struct {
 	cardRepo         ports.CardRepository
 	installmentRepo  ports.InstallmentRepository
 	notificationRepo ports.NotificationRepository
+	userRepo         ports.UserRepository
 	emailService     ports.EmailService
 }
 
@@ -23,12 +24,14 @@ func NewNotificationService(
 	cardRepo ports.CardRepository,
 	installmentRepo ports.InstallmentRepository,
 	notificationRepo ports.NotificationRepository,
+	userRepo ports.UserRepository,
 	emailService ports.EmailService,
 ) *NotificationService {
 	return &NotificationService{
 		cardRepo:         cardRepo,
 		installmentRepo:  installmentRepo,
 		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
 		emailService:     emailService,
 	}
 }
@@ -212,6 +215,159 @@ func (s *NotificationService) UpdateExpiredDueDates() error {
 	return nil
 }
 
+// ProcessCardExpiry marca como expiradas las tarjetas vencidas y avisa a los dueños de las
+// tarjetas que expiran dentro de noticeDays días para que soliciten la renovación
+func (s *NotificationService) ProcessCardExpiry(noticeDays int) error {
+	jobRunID := uuid.New().String()
+	jobRun := &entities.JobRun{
+		ID:        jobRunID,
+		StartedAt: time.Now(),
+		Status:    "running",
+	}
+
+	// Guardar el inicio del job
+	if err := s.notificationRepo.SaveJobRun(jobRun); err != nil {
+		log.Printf("Error saving job run: %v", err)
+		return fmt.Errorf("error saving job run: %w", err)
+	}
+
+	log.Printf("🚀 Starting card expiry job: %s", jobRunID)
+
+	// 1. Expirar las tarjetas cuyo mes de expiración ya terminó
+	cardsExpired, err := s.cardRepo.ExpireCards()
+	if err != nil {
+		jobRun.Status = "failed"
+		jobRun.ErrorMessage = err.Error()
+		jobRun.CompletedAt = &[]time.Time{time.Now()}[0]
+		s.notificationRepo.UpdateJobRun(jobRun)
+		return fmt.Errorf("error expiring cards: %w", err)
+	}
+	log.Printf("⌛ %d cards marked as expired", cardsExpired)
+
+	// 2. Obtener tarjetas que expiran dentro del período de aviso
+	cards, err := s.cardRepo.GetCardsExpiringIn(noticeDays)
+	if err != nil {
+		jobRun.Status = "failed"
+		jobRun.ErrorMessage = err.Error()
+		jobRun.CompletedAt = &[]time.Time{time.Now()}[0]
+		s.notificationRepo.UpdateJobRun(jobRun)
+		return fmt.Errorf("error getting expiring cards: %w", err)
+	}
+
+	jobRun.CardsFound = len(cards)
+	log.Printf("📅 Found %d cards expiring within %d days without notice", len(cards), noticeDays)
+
+	// 3. Avisar a cada dueño
+	emailsSent := 0
+	errors := 0
+
+	now := time.Now()
+	for _, card := range cards {
+		sent, err := s.processCardExpiryNotification(card, daysUntil(card.ExpirationDate, now), jobRunID)
+		switch {
+		case err != nil:
+			log.Printf("❌ Error processing expiry notice for card %s (%s): %v", card.CardName, card.ID, err)
+			errors++
+		case sent:
+			log.Printf("✅ Expiry notice sent for card %s (%s) to %s", card.CardName, card.ID, card.UserEmail)
+			emailsSent++
+		default:
+			log.Printf("ℹ️  Expiry notice for card %s (%s) already sent by another run", card.CardName, card.ID)
+		}
+	}
+
+	// 4. Actualizar el job run con los resultados
+	jobRun.EmailsSent = emailsSent
+	jobRun.Errors = errors
+	jobRun.Status = "completed"
+	if errors > 0 && emailsSent == 0 {
+		jobRun.Status = "failed"
+		jobRun.ErrorMessage = fmt.Sprintf("All %d notifications failed", errors)
+	}
+	jobRun.CompletedAt = &[]time.Time{time.Now()}[0]
+
+	if err := s.notificationRepo.UpdateJobRun(jobRun); err != nil {
+		log.Printf("Error updating job run: %v", err)
+	}
+
+	log.Printf("🎉 Card expiry job completed: %d cards expired, %d emails sent, %d errors", cardsExpired, emailsSent, errors)
+	return nil
+}
+
+// daysUntil cuenta los días que faltan hasta una fecha, sin contar hoy
+func daysUntil(date, now time.Time) int {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return int(day.Sub(today).Hours() / 24)
+}
+
+// processCardExpiryNotification envía el aviso de expiración para una tarjeta específica. La tarjeta queda marcada
+// antes del envío para no avisar dos veces y se desmarca si el envío falla, para que la próxima corrida lo reintente.
+// Devuelve false si otra corrida ya había marcado el aviso.
+func (s *NotificationService) processCardExpiryNotification(card *entities.Card, daysUntilExpiry int, jobRunID string) (bool, error) {
+	claimed, err := s.cardRepo.ClaimExpiryNotice(card.ID)
+	if err != nil {
+		return false, fmt.Errorf("error claiming expiry notice for card %s: %w", card.ID, err)
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	if err := s.sendCardExpiryNotification(card, daysUntilExpiry, jobRunID); err != nil {
+		if releaseErr := s.cardRepo.ReleaseExpiryNotice(card.ID); releaseErr != nil {
+			log.Printf("Error releasing expiry notice of card %s: %v", card.ID, releaseErr)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// sendCardExpiryNotification arma y envía el email de aviso de expiración y registra el resultado
+func (s *NotificationService) sendCardExpiryNotification(card *entities.Card, daysUntilExpiry int, jobRunID string) error {
+	notificationLog := &entities.NotificationLog{
+		ID:       uuid.New().String(),
+		JobRunID: jobRunID,
+		CardID:   card.ID,
+		UserID:   card.UserID,
+		Email:    card.UserEmail,
+		SentAt:   time.Now(),
+	}
+
+	// Los planes de cuotas activos pasan a la tarjeta renovada
+	activePlans, err := s.installmentRepo.CountActivePlansByCard(card.ID)
+	if err != nil {
+		notificationLog.Status = "failed"
+		notificationLog.ErrorMessage = fmt.Sprintf("Error counting installment plans: %v", err)
+		s.notificationRepo.SaveNotificationLog(notificationLog)
+		return fmt.Errorf("error counting installment plans for card %s: %w", card.ID, err)
+	}
+
+	notification := &entities.CardExpiryNotification{
+		CardID:                 card.ID,
+		UserID:                 card.UserID,
+		UserEmail:              card.UserEmail,
+		UserName:               card.GetUserFullName(),
+		CardName:               card.CardName,
+		BankName:               card.BankName,
+		LastFour:               card.LastFour,
+		ExpirationDate:         card.ExpirationDate,
+		DaysUntilExpiry:        daysUntilExpiry,
+		ActiveInstallmentPlans: activePlans,
+	}
+
+	if err := s.emailService.SendCardExpiryNotification(notification); err != nil {
+		notificationLog.Status = "failed"
+		notificationLog.ErrorMessage = fmt.Sprintf("Error sending email: %v", err)
+		s.notificationRepo.SaveNotificationLog(notificationLog)
+		return fmt.Errorf("error sending email for card %s: %w", card.ID, err)
+	}
+
+	notificationLog.Status = "sent"
+	s.notificationRepo.SaveNotificationLog(notificationLog)
+
+	return nil
+}
+
 // GetJobHistory obtiene el historial de ejecuciones del job
 func (s *NotificationService) GetJobHistory(limit int) ([]*entities.JobRun, error) {
 	return s.notificationRepo.GetJobRunHistory(limit)
@@ -234,3 +390,78 @@ func (s *NotificationService) SendSupportEmail(name, email, subject, message str
 	log.Printf("✅ Support email sent successfully")
 	return nil
 }
+
+// NotifyScheduledTransactionFailure avisa por email que una transacción programada no se pudo ejecutar
+func (s *NotificationService) NotifyScheduledTransactionFailure(failure *entities.ScheduledTransactionFailure) error {
+	log.Printf("📧 Sending scheduled transaction failure for schedule %s (user %s)", failure.ScheduleID, failure.UserID)
+
+	user, err := s.userRepo.GetUserContact(failure.UserID)
+	if err != nil {
+		log.Printf("❌ Error getting user for scheduled transaction failure: %v", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	notification := &entities.ScheduledTransactionFailureNotification{
+		ScheduledTransactionFailure: *failure,
+		UserEmail:                   user.Email,
+		UserName:                    user.GetFullName(),
+	}
+
+	if err := s.emailService.SendScheduledTransactionFailure(notification); err != nil {
+		log.Printf("❌ Error sending scheduled transaction failure email: %v", err)
+		return fmt.Errorf("failed to send scheduled transaction failure email: %w", err)
+	}
+
+	log.Printf("✅ Scheduled transaction failure email sent to %s", user.Email)
+	return nil
+}
+
+// NotifyFraudAlert avisa por email de una transacción sospechosa y de si fue retenida o bloqueada
+func (s *NotificationService) NotifyFraudAlert(alert *entities.FraudAlert) error {
+	log.Printf("📧 Sending fraud alert for transaction %s (user %s, score %d)", alert.TransactionID, alert.UserID, alert.Score)
+
+	user, err := s.userRepo.GetUserContact(alert.UserID)
+	if err != nil {
+		log.Printf("❌ Error getting user for fraud alert: %v", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	notification := &entities.FraudAlertNotification{
+		FraudAlert: *alert,
+		UserEmail:  user.Email,
+		UserName:   user.GetFullName(),
+	}
+
+	if err := s.emailService.SendFraudAlert(notification); err != nil {
+		log.Printf("❌ Error sending fraud alert email: %v", err)
+		return fmt.Errorf("failed to send fraud alert email: %w", err)
+	}
+
+	log.Printf("✅ Fraud alert email sent to %s", user.Email)
+	return nil
+}
+
+// NotifyBudgetAlert avisa por email que un presupuesto mensual alcanzó el 50%, el 80% o el 100% del límite
+func (s *NotificationService) NotifyBudgetAlert(alert *entities.BudgetAlert) error {
+	log.Printf("📧 Sending budget alert for budget %s (user %s, %d%% of %s)", alert.BudgetID, alert.UserID, alert.Threshold, alert.Month)
+
+	user, err := s.userRepo.GetUserContact(alert.UserID)
+	if err != nil {
+		log.Printf("❌ Error getting user for budget alert: %v", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	notification := &entities.BudgetAlertNotification{
+		BudgetAlert: *alert,
+		UserEmail:   user.Email,
+		UserName:    user.GetFullName(),
+	}
+
+	if err := s.emailService.SendBudgetAlert(notification); err != nil {
+		log.Printf("❌ Error sending budget alert email: %v", err)
+		return fmt.Errorf("failed to send budget alert email: %w", err)
+	}
+
+	log.Printf("✅ Budget alert email sent to %s", user.Email)
+	return nil
+}
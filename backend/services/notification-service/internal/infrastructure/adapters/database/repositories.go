@@ -100,6 +100,116 @@ func (r *CardRepository) UpdateExpiredDueDates() (int, error) {
 	return int(rowsAffected), nil
 }
 
+// cardExpirationDateSQL calcula el último día de validez de una tarjeta (fin del mes de expiración)
+const cardExpirationDateSQL = `LAST_DAY(STR_TO_DATE(CONCAT(c.expiration_year, '-', c.expiration_month, '-01'), '%Y-%m-%d'))`
+
+// GetCardsExpiringIn obtiene tarjetas activas cuyo último día de validez cae dentro de la cantidad de días indicada
+// y que todavía no recibieron el aviso. Al tomar toda la ventana, una corrida diaria perdida se recupera en la siguiente.
+func (r *CardRepository) GetCardsExpiringIn(days int) ([]*entities.Card, error) {
+	query := `
+		SELECT 
+			c.id, a.user_id, c.nickname as card_name, 
+			c.card_brand as bank_name, c.last_four_digits, c.card_type,
+			` + cardExpirationDateSQL + ` as expiration_date,
+			u.email, u.first_name, u.last_name
+		FROM cards c
+		JOIN accounts a ON c.account_id = a.id
+		JOIN users u ON a.user_id = u.id
+		WHERE ` + cardExpirationDateSQL + ` <= DATE(NOW() + INTERVAL ? DAY)
+		  AND c.expiry_notice_sent_at IS NULL
+		  AND c.status = 'active'
+		  AND c.deleted_at IS NULL
+		  AND u.is_active = 1
+		ORDER BY c.id ASC
+	`
+
+	rows, err := r.db.Query(query, days)
+	if err != nil {
+		return nil, fmt.Errorf("error querying expiring cards: %w", err)
+	}
+	defer rows.Close()
+
+	var cards []*entities.Card
+	for rows.Next() {
+		card := &entities.Card{}
+		err := rows.Scan(
+			&card.ID,
+			&card.UserID,
+			&card.CardName,
+			&card.BankName,
+			&card.LastFour,
+			&card.CardType,
+			&card.ExpirationDate,
+			&card.UserEmail,
+			&card.UserFirstName,
+			&card.UserLastName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning expiring card row: %w", err)
+		}
+
+		card.IsActive = true
+
+		cards = append(cards, card)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expiring card rows: %w", err)
+	}
+
+	return cards, nil
+}
+
+// ClaimExpiryNotice marca el aviso de expiración de una tarjeta como enviado. Devuelve false si ya estaba marcado,
+// así dos corridas simultáneas no avisan dos veces.
+func (r *CardRepository) ClaimExpiryNotice(cardID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE cards
+		SET expiry_notice_sent_at = NOW()
+		WHERE id = ? AND expiry_notice_sent_at IS NULL
+	`, cardID)
+	if err != nil {
+		return false, fmt.Errorf("error claiming expiry notice: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error getting rows affected: %w", err)
+	}
+	return rowsAffected == 1, nil
+}
+
+// ReleaseExpiryNotice desmarca el aviso de una tarjeta cuyo envío falló para que la próxima corrida lo reintente
+func (r *CardRepository) ReleaseExpiryNotice(cardID string) error {
+	if _, err := r.db.Exec(`UPDATE cards SET expiry_notice_sent_at = NULL WHERE id = ?`, cardID); err != nil {
+		return fmt.Errorf("error releasing expiry notice: %w", err)
+	}
+	return nil
+}
+
+// ExpireCards marca como expiradas las tarjetas cuyo mes de expiración ya terminó
+func (r *CardRepository) ExpireCards() (int, error) {
+	query := `
+		UPDATE cards c
+		SET c.status = 'expired'
+		WHERE c.status IN ('active', 'blocked')
+		  AND c.deleted_at IS NULL
+		  AND ` + cardExpirationDateSQL + ` < CURDATE()
+	`
+
+	result, err := r.db.Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("error expiring cards: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
 // InstallmentRepository implementa las operaciones de repositorio para cuotas
 type InstallmentRepository struct {
 	db *sql.DB
@@ -159,6 +269,58 @@ func (r *InstallmentRepository) GetPendingInstallmentsByCard(cardID string, maxD
 	return installments, nil
 }
 
+// CountActivePlansByCard cuenta los planes de cuotas activos de una tarjeta
+func (r *InstallmentRepository) CountActivePlansByCard(cardID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM installment_plans
+		WHERE card_id = ?
+		  AND status IN ('active', 'suspended')
+	`
+
+	var count int
+	if err := r.db.QueryRow(query, cardID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting active installment plans: %w", err)
+	}
+
+	return count, nil
+}
+
+// UserRepository implementa las operaciones de repositorio para usuarios
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository crea un nuevo repositorio de usuarios
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// GetUserContact obtiene el email y el nombre de un usuario activo
+func (r *UserRepository) GetUserContact(userID string) (*entities.UserContact, error) {
+	query := `
+		SELECT id, email, first_name, last_name
+		FROM users
+		WHERE id = ? AND is_active = 1
+	`
+
+	contact := &entities.UserContact{}
+	err := r.db.QueryRow(query, userID).Scan(
+		&contact.ID,
+		&contact.Email,
+		&contact.FirstName,
+		&contact.LastName,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found: %s", userID)
+		}
+		return nil, fmt.Errorf("error getting user contact: %w", err)
+	}
+
+	return contact, nil
+}
+
 // NotificationRepository implementa las operaciones de repositorio para notificaciones
 type NotificationRepository struct {
 	db *sql.DB
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"time"
@@ -69,6 +70,308 @@ func (c *EmailJSClient) SendCardDueNotification(notification *entities.CardDueNo
 	return c.sendRequest(request)
 }
 
+// SendCardExpiryNotification envía el aviso de expiración de una tarjeta
+func (c *EmailJSClient) SendCardExpiryNotification(notification *entities.CardExpiryNotification) error {
+	htmlContent := c.buildExpiryEmailHTML(notification)
+
+	templateParams := map[string]string{
+		"from_name":         c.config.FromName,
+		"subject":           fmt.Sprintf("Tu tarjeta %s expira en %d días 💳", notification.CardName, notification.DaysUntilExpiry),
+		"to_email":          notification.UserEmail,
+		"reply_to":          c.config.ReplyTo,
+		"html_content":      htmlContent,
+		"user_name":         notification.UserName,
+		"card_name":         notification.CardName,
+		"bank_name":         notification.BankName,
+		"last_four":         notification.LastFour,
+		"due_date":          notification.ExpirationDate.Format("01/2006"),
+		"installment_count": fmt.Sprintf("%d", notification.ActiveInstallmentPlans),
+	}
+
+	request := EmailJSRequest{
+		ServiceID:      c.config.ServiceID,
+		TemplateID:     c.config.TemplateID,
+		UserID:         c.config.PublicKey,
+		TemplateParams: templateParams,
+	}
+
+	return c.sendRequest(request)
+}
+
+// buildExpiryEmailHTML construye el HTML del aviso de expiración
+func (c *EmailJSClient) buildExpiryEmailHTML(notification *entities.CardExpiryNotification) string {
+	// Los planes de cuotas activos se transfieren a la tarjeta renovada
+	installmentsMessage := `<p style="color: #666;">La tarjeta no tiene planes de cuotas activos.</p>`
+	if notification.ActiveInstallmentPlans > 0 {
+		installmentsMessage = fmt.Sprintf(`
+		<div style="background: #d1ecf1; padding: 15px; border-radius: 5px; border-left: 4px solid #17a2b8; margin: 20px 0;">
+			<p style="color: #0c5460; margin: 0;">Tienes %d planes de cuotas activos. Al renovar la tarjeta se transfieren a la nueva junto con el saldo y los controles de gasto.</p>
+		</div>`, notification.ActiveInstallmentPlans)
+	}
+
+	html := fmt.Sprintf(`
+		<h2 style="color: #333;">Hola %s, tu tarjeta está por expirar 💳</h2>
+		<div style="background: white; padding: 20px; border-radius: 8px; box-shadow: 0 2px 4px rgba(0,0,0,0.1);">
+			<h3 style="color: #667eea; margin-top: 0;">%s - %s (****%s)</h3>
+			<p style="font-size: 16px; color: #333; margin: 15px 0;">
+				<strong>Válida hasta:</strong> %s
+			</p>
+			<div style="background: #fff3cd; padding: 15px; border-radius: 5px; border-left: 4px solid #ffc107; margin: 20px 0;">
+				<p style="color: #856404; margin: 0;">Después de esa fecha la tarjeta dejará de funcionar. Cuando recibas el plástico nuevo, renuévala en FinTrack para seguir usándola.</p>
+			</div>
+			%s
+		</div>`,
+		notification.UserName,
+		notification.CardName,
+		notification.BankName,
+		notification.LastFour,
+		notification.ExpirationDate.Format("02/01/2006"),
+		installmentsMessage,
+	)
+
+	return html
+}
+
+// SendScheduledTransactionFailure envía el aviso de una transacción programada que no se pudo ejecutar
+func (c *EmailJSClient) SendScheduledTransactionFailure(notification *entities.ScheduledTransactionFailureNotification) error {
+	htmlContent := c.buildScheduledFailureEmailHTML(notification)
+
+	subject := fmt.Sprintf("No pudimos ejecutar tu transacción programada: %s ⚠️", notification.Description)
+	if notification.Paused {
+		subject = fmt.Sprintf("Pausamos tu transacción programada: %s ⚠️", notification.Description)
+	}
+
+	templateParams := map[string]string{
+		"from_name":    c.config.FromName,
+		"subject":      subject,
+		"to_email":     notification.UserEmail,
+		"reply_to":     c.config.ReplyTo,
+		"html_content": htmlContent,
+		"user_name":    notification.UserName,
+		"due_date":     notification.ScheduledFor.Format("02/01/2006"),
+		"total_amount": fmt.Sprintf("$%.2f", notification.Amount),
+	}
+
+	request := EmailJSRequest{
+		ServiceID:      c.config.ServiceID,
+		TemplateID:     c.config.TemplateID,
+		UserID:         c.config.PublicKey,
+		TemplateParams: templateParams,
+	}
+
+	return c.sendRequest(request)
+}
+
+// buildScheduledFailureEmailHTML construye el HTML del aviso de falla de una transacción programada
+func (c *EmailJSClient) buildScheduledFailureEmailHTML(notification *entities.ScheduledTransactionFailureNotification) string {
+	// Después de varias fallas seguidas la programación se pausa hasta que el usuario la reanude
+	pausedMessage := `<p style="color: #666;">La próxima ocurrencia se intentará en la fecha programada.</p>`
+	if notification.Paused {
+		pausedMessage = `
+		<div style="background: #f8d7da; padding: 15px; border-radius: 5px; border-left: 4px solid #dc3545; margin: 20px 0;">
+			<p style="color: #721c24; margin: 0;">La transacción falló varias veces seguidas, por lo que pausamos la programación. Revisa la cuenta o tarjeta de origen y reanúdala desde FinTrack.</p>
+		</div>`
+	}
+
+	body := fmt.Sprintf(`
+		<h2 style="color: #333;">Hola %s, no pudimos ejecutar una transacción programada ⚠️</h2>
+		<div style="background: white; padding: 20px; border-radius: 8px; box-shadow: 0 2px 4px rgba(0,0,0,0.1);">
+			<h3 style="color: #667eea; margin-top: 0;">%s</h3>
+			<p style="font-size: 16px; color: #333; margin: 15px 0;">
+				<strong>Monto:</strong> $%.2f %s<br>
+				<strong>Fecha programada:</strong> %s
+			</p>
+			<div style="background: #fff3cd; padding: 15px; border-radius: 5px; border-left: 4px solid #ffc107; margin: 20px 0;">
+				<p style="color: #856404; margin: 0;"><strong>Motivo:</strong> %s</p>
+			</div>
+			%s
+		</div>`,
+		html.EscapeString(notification.UserName),
+		html.EscapeString(notification.Description),
+		notification.Amount,
+		notification.Currency,
+		notification.ScheduledFor.Format("02/01/2006"),
+		html.EscapeString(notification.Reason),
+		pausedMessage,
+	)
+
+	return body
+}
+
+// SendFraudAlert envía el aviso de una transacción sospechosa
+func (c *EmailJSClient) SendFraudAlert(notification *entities.FraudAlertNotification) error {
+	htmlContent := c.buildFraudAlertEmailHTML(notification)
+
+	subject := "Detectamos una transacción inusual en tu cuenta 🔒"
+	switch notification.Action {
+	case "hold":
+		subject = "Retuvimos una transacción inusual hasta que la confirmes 🔒"
+	case "block":
+		subject = "Bloqueamos una transacción sospechosa 🔒"
+	}
+
+	templateParams := map[string]string{
+		"from_name":    c.config.FromName,
+		"subject":      subject,
+		"to_email":     notification.UserEmail,
+		"reply_to":     c.config.ReplyTo,
+		"html_content": htmlContent,
+		"user_name":    notification.UserName,
+		"due_date":     notification.CreatedAt.Format("02/01/2006 15:04"),
+		"total_amount": fmt.Sprintf("$%.2f", notification.Amount),
+	}
+
+	request := EmailJSRequest{
+		ServiceID:      c.config.ServiceID,
+		TemplateID:     c.config.TemplateID,
+		UserID:         c.config.PublicKey,
+		TemplateParams: templateParams,
+	}
+
+	return c.sendRequest(request)
+}
+
+// buildFraudAlertEmailHTML construye el HTML del aviso de una transacción sospechosa
+func (c *EmailJSClient) buildFraudAlertEmailHTML(notification *entities.FraudAlertNotification) string {
+	reasonsHTML := ""
+	for _, reason := range notification.Reasons {
+		reasonsHTML += fmt.Sprintf(`<li style="color: #856404;">%s</li>`, html.EscapeString(reason))
+	}
+
+	// El mensaje depende de lo que se hizo con la transacción
+	actionMessage := `<p style="color: #666;">La transacción se realizó. Si no la reconoces, contacta a soporte y revisa tus tarjetas.</p>`
+	switch notification.Action {
+	case "hold":
+		actionMessage = `
+		<div style="background: #d1ecf1; padding: 15px; border-radius: 5px; border-left: 4px solid #17a2b8; margin: 20px 0;">
+			<p style="color: #0c5460; margin: 0;">La transacción quedó retenida y no movió dinero. Si la hiciste tú, libérala desde FinTrack; si no, cancélala.</p>
+		</div>`
+	case "block":
+		actionMessage = `
+		<div style="background: #f8d7da; padding: 15px; border-radius: 5px; border-left: 4px solid #dc3545; margin: 20px 0;">
+			<p style="color: #721c24; margin: 0;">Bloqueamos la transacción y no movió dinero. Si la hiciste tú, puedes ajustar los umbrales de seguridad desde FinTrack e intentarla de nuevo.</p>
+		</div>`
+	}
+
+	merchant := notification.MerchantName
+	if merchant == "" {
+		merchant = notification.Description
+	}
+
+	body := fmt.Sprintf(`
+		<h2 style="color: #333;">Hola %s, detectamos una transacción inusual 🔒</h2>
+		<div style="background: white; padding: 20px; border-radius: 8px; box-shadow: 0 2px 4px rgba(0,0,0,0.1);">
+			<h3 style="color: #667eea; margin-top: 0;">%s</h3>
+			<p style="font-size: 16px; color: #333; margin: 15px 0;">
+				<strong>Monto:</strong> $%.2f %s<br>
+				<strong>Fecha:</strong> %s<br>
+				<strong>Puntaje de riesgo:</strong> %d/100
+			</p>
+			<div style="background: #fff3cd; padding: 15px; border-radius: 5px; border-left: 4px solid #ffc107; margin: 20px 0;">
+				<p style="color: #856404; margin: 0;"><strong>Por qué nos llamó la atención:</strong></p>
+				<ul style="margin: 10px 0 0 0;">%s</ul>
+			</div>
+			%s
+		</div>`,
+		html.EscapeString(notification.UserName),
+		html.EscapeString(merchant),
+		notification.Amount,
+		notification.Currency,
+		notification.CreatedAt.Format("02/01/2006 15:04"),
+		notification.Score,
+		reasonsHTML,
+		actionMessage,
+	)
+
+	return body
+}
+
+// SendBudgetAlert envía el aviso de un presupuesto mensual que alcanzó un umbral de alerta
+func (c *EmailJSClient) SendBudgetAlert(notification *entities.BudgetAlertNotification) error {
+	htmlContent := c.buildBudgetAlertEmailHTML(notification)
+
+	subject := fmt.Sprintf("Usaste el %d%% de tu presupuesto %s 📊", notification.Threshold, notification.BudgetName)
+	if notification.Threshold >= 100 {
+		subject = fmt.Sprintf("Agotaste tu presupuesto %s 📊", notification.BudgetName)
+	}
+
+	templateParams := map[string]string{
+		"from_name":    c.config.FromName,
+		"subject":      subject,
+		"to_email":     notification.UserEmail,
+		"reply_to":     c.config.ReplyTo,
+		"html_content": htmlContent,
+		"user_name":    notification.UserName,
+		"due_date":     notification.Month,
+		"total_amount": fmt.Sprintf("$%.2f", notification.Actual),
+	}
+
+	request := EmailJSRequest{
+		ServiceID:      c.config.ServiceID,
+		TemplateID:     c.config.TemplateID,
+		UserID:         c.config.PublicKey,
+		TemplateParams: templateParams,
+	}
+
+	return c.sendRequest(request)
+}
+
+// buildBudgetAlertEmailHTML construye el HTML del aviso de un presupuesto que alcanzó un umbral
+func (c *EmailJSClient) buildBudgetAlertEmailHTML(notification *entities.BudgetAlertNotification) string {
+	// La barra se llena hasta el 100% aunque el gasto lo supere
+	barWidth := notification.PercentUsed
+	if barWidth > 100 {
+		barWidth = 100
+	}
+	barColor := "#ffc107"
+	message := `<p style="color: #666;">Todavía estás dentro del límite. Revisa tus próximos gastos para no superarlo.</p>`
+	if notification.Threshold >= 100 {
+		barColor = "#dc3545"
+		message = `
+		<div style="background: #f8d7da; padding: 15px; border-radius: 5px; border-left: 4px solid #dc3545; margin: 20px 0;">
+			<p style="color: #721c24; margin: 0;">Llegaste al límite de este presupuesto. Los gastos que sigas sumando este mes lo van a superar.</p>
+		</div>`
+	}
+
+	installmentsHTML := ""
+	if notification.Installments > 0 {
+		installmentsHTML = fmt.Sprintf(`<br><strong>De los cuales son cuotas del mes:</strong> $%.2f %s`, notification.Installments, notification.Currency)
+	}
+
+	body := fmt.Sprintf(`
+		<h2 style="color: #333;">Hola %s, tu presupuesto llegó al %d%% 📊</h2>
+		<div style="background: white; padding: 20px; border-radius: 8px; box-shadow: 0 2px 4px rgba(0,0,0,0.1);">
+			<h3 style="color: #667eea; margin-top: 0;">%s · %s</h3>
+			<div style="background: #e9ecef; border-radius: 5px; height: 12px; margin: 15px 0;">
+				<div style="background: %s; border-radius: 5px; height: 12px; width: %.0f%%;"></div>
+			</div>
+			<p style="font-size: 16px; color: #333; margin: 15px 0;">
+				<strong>Gastado:</strong> $%.2f %s (%.2f%%)<br>
+				<strong>Límite:</strong> $%.2f %s<br>
+				<strong>Disponible:</strong> $%.2f %s%s
+			</p>
+			%s
+		</div>`,
+		html.EscapeString(notification.UserName),
+		notification.Threshold,
+		html.EscapeString(notification.BudgetName),
+		html.EscapeString(notification.Month),
+		barColor,
+		barWidth,
+		notification.Actual,
+		notification.Currency,
+		notification.PercentUsed,
+		notification.Limit,
+		notification.Currency,
+		notification.Remaining,
+		notification.Currency,
+		installmentsHTML,
+		message,
+	)
+
+	return body
+}
+
 // buildEmailHTML construye el HTML del email con los datos de la notificación
 func (c *EmailJSClient) buildEmailHTML(notification *entities.CardDueNotification) string {
 	installmentsHTML := c.buildInstallmentsHTML(notification.InstallmentDetails)
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/fintrack/notification-service/internal/core/domain/entities"
 	"github.com/fintrack/notification-service/internal/core/ports"
 	"github.com/fintrack/notification-service/internal/infrastructure/jobs"
 	"github.com/gin-gonic/gin"
@@ -94,6 +95,24 @@ func (h *Handler) TriggerUpdateDueDatesJob(c *gin.Context) {
 	})
 }
 
+// TriggerCardExpiryJob ejecuta manualmente el job de expiración de tarjetas
+// POST /api/notifications/trigger-card-expiry-job
+func (h *Handler) TriggerCardExpiryJob(c *gin.Context) {
+	// Ejecutar el job en una goroutine para no bloquear la respuesta
+	go func() {
+		if err := h.jobScheduler.TriggerCardExpiryJob(); err != nil {
+			// Log error pero no retornar al cliente ya que es asíncrono
+			// El error se registrará en los logs del job
+		}
+	}()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Card expiry job triggered successfully",
+		"timestamp": time.Now(),
+		"async":     true,
+	})
+}
+
 // GetJobHistory obtiene el historial de ejecuciones del job
 // GET /api/notifications/job-history?limit=10
 func (h *Handler) GetJobHistory(c *gin.Context) {
@@ -232,6 +251,84 @@ func (h *Handler) GetSchedulerStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// NotifyScheduledTransactionFailure avisa al usuario que una transacción programada no se pudo ejecutar
+// POST /api/notifications/scheduled-transaction-failure
+func (h *Handler) NotifyScheduledTransactionFailure(c *gin.Context) {
+	var request entities.ScheduledTransactionFailure
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.notificationService.NotifyScheduledTransactionFailure(&request); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to send scheduled transaction failure notification",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Scheduled transaction failure notification sent successfully",
+		"timestamp": time.Now(),
+	})
+}
+
+// NotifyFraudAlert avisa al usuario de una transacción sospechosa
+// POST /api/notifications/fraud-alert
+func (h *Handler) NotifyFraudAlert(c *gin.Context) {
+	var request entities.FraudAlert
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.notificationService.NotifyFraudAlert(&request); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to send fraud alert notification",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Fraud alert notification sent successfully",
+		"timestamp": time.Now(),
+	})
+}
+
+// NotifyBudgetAlert avisa al usuario que un presupuesto mensual alcanzó un umbral de alerta
+// POST /api/notifications/budget-alert
+func (h *Handler) NotifyBudgetAlert(c *gin.Context) {
+	var request entities.BudgetAlert
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.notificationService.NotifyBudgetAlert(&request); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to send budget alert notification",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Budget alert notification sent successfully",
+		"timestamp": time.Now(),
+	})
+}
+
 // SupportEmailRequest representa la solicitud de email de soporte
 type SupportEmailRequest struct {
 	Name    string `json:"name" binding:"required"`
@@ -33,6 +33,7 @@ func SetupRoutes(notificationHandler *notification.Handler) *gin.Engine {
 		// Job management
 		api.POST("/trigger-card-due-job", notificationHandler.TriggerCardDueJob)
 		api.POST("/trigger-update-due-dates-job", notificationHandler.TriggerUpdateDueDatesJob)
+		api.POST("/trigger-card-expiry-job", notificationHandler.TriggerCardExpiryJob)
 		api.GET("/job-history", notificationHandler.GetJobHistory)
 
 		// Notification logs
@@ -41,6 +42,15 @@ func SetupRoutes(notificationHandler *notification.Handler) *gin.Engine {
 		// Support email
 		api.POST("/support", notificationHandler.SendSupportEmail)
 
+		// Scheduled transactions (transaction-service)
+		api.POST("/scheduled-transaction-failure", notificationHandler.NotifyScheduledTransactionFailure)
+
+		// Fraud and anomaly alerts (transaction-service)
+		api.POST("/fraud-alert", notificationHandler.NotifyFraudAlert)
+
+		// Monthly budget alerts (transaction-service)
+		api.POST("/budget-alert", notificationHandler.NotifyBudgetAlert)
+
 		// Scheduler status
 		api.GET("/scheduler/status", notificationHandler.GetSchedulerStatus)
 
@@ -61,9 +71,13 @@ func SetupRoutes(notificationHandler *notification.Handler) *gin.Engine {
 				"GET /health",
 				"POST /api/notifications/trigger-card-due-job",
 				"POST /api/notifications/trigger-update-due-dates-job",
+				"POST /api/notifications/trigger-card-expiry-job",
 				"GET /api/notifications/job-history",
 				"GET /api/notifications/logs",
 				"POST /api/notifications/support",
+				"POST /api/notifications/scheduled-transaction-failure",
+				"POST /api/notifications/fraud-alert",
+				"POST /api/notifications/budget-alert",
 				"GET /api/notifications/scheduler/status",
 				"GET /api/notifications/health",
 			},
@@ -58,6 +58,23 @@ func (j *JobScheduler) Start() error {
 		return err
 	}
 
+	// Programar el job de expiración de tarjetas
+	_, err = j.cron.AddFunc(j.config.CardExpirySchedule, func() {
+		log.Println("⌛ Starting scheduled card expiry job")
+
+		startTime := time.Now()
+		if err := j.notificationService.ProcessCardExpiry(j.config.CardExpiryNoticeDays); err != nil {
+			log.Printf("❌ Scheduled card expiry job failed: %v", err)
+		} else {
+			duration := time.Since(startTime)
+			log.Printf("✅ Scheduled card expiry job completed successfully in %v", duration)
+		}
+	})
+
+	if err != nil {
+		return err
+	}
+
 	// Iniciar el cron scheduler
 	j.cron.Start()
 	log.Printf("🚀 Job scheduler started - next run will be according to cron: %s", j.config.Schedule)
@@ -87,6 +104,12 @@ func (j *JobScheduler) TriggerUpdateDueDatesJob() error {
 	return j.notificationService.UpdateExpiredDueDates()
 }
 
+// TriggerCardExpiryJob ejecuta manualmente el job de expiración de tarjetas
+func (j *JobScheduler) TriggerCardExpiryJob() error {
+	log.Println("🔧 Manual trigger for card expiry job")
+	return j.notificationService.ProcessCardExpiry(j.config.CardExpiryNoticeDays)
+}
+
 // GetNextScheduledRun obtiene la próxima ejecución programada
 func (j *JobScheduler) GetNextScheduledRun() time.Time {
 	entries := j.cron.Entries()
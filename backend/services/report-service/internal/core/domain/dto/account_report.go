@@ -1,13 +1,17 @@
 package dto
 
+import "time"
+
 // AccountReportRequest request para reporte de cuentas
 type AccountReportRequest struct {
-	UserID string `json:"user_id" binding:"required"`
+	UserID string     `json:"user_id" binding:"required"`
+	AsOf   *time.Time `json:"as_of,omitempty"` // Límites de crédito vigentes y consumos hasta esta fecha
 }
 
 // AccountReportResponse respuesta del reporte de cuentas
 type AccountReportResponse struct {
 	UserID       string                `json:"user_id"`
+	AsOf         *time.Time            `json:"as_of,omitempty"`
 	Summary      AccountSummary        `json:"summary"`
 	Accounts     []AccountDetail       `json:"accounts"`
 	Cards        []CardDetail          `json:"cards"`
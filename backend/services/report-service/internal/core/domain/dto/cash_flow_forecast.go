@@ -0,0 +1,127 @@
+package dto
+
+import "time"
+
+// CashFlowForecastRequest request para la proyección de flujo de caja
+type CashFlowForecastRequest struct {
+	UserID    string `json:"user_id" binding:"required"`
+	Days      int    `json:"days"`       // 30, 60 o 90
+	AccountID string `json:"account_id"` // opcional, una sola cuenta
+}
+
+// CashFlowForecastResponse respuesta de la proyección de flujo de caja
+type CashFlowForecastResponse struct {
+	UserID      string            `json:"user_id"`
+	Days        int               `json:"days"`
+	StartDate   time.Time         `json:"start_date"`
+	EndDate     time.Time         `json:"end_date"`
+	HistoryDays int               `json:"history_days"` // días usados para los promedios históricos
+	Summary     ForecastSummary   `json:"summary"`
+	Accounts    []AccountForecast `json:"accounts"`
+}
+
+// ForecastSummary resumen de la proyección de todas las cuentas
+type ForecastSummary struct {
+	TotalAccounts     int        `json:"total_accounts"`
+	AccountsAtRisk    int        `json:"accounts_at_risk"` // cuentas que quedan en negativo algún día
+	CurrentBalance    float64    `json:"current_balance"`
+	ProjectedBalance  float64    `json:"projected_balance"`
+	ProjectedIncome   float64    `json:"projected_income"`
+	ProjectedExpenses float64    `json:"projected_expenses"`
+	FirstNegativeDate *time.Time `json:"first_negative_date,omitempty"`
+}
+
+// AccountForecast proyección diaria del saldo de una cuenta
+type AccountForecast struct {
+	AccountID         string          `json:"account_id"`
+	Name              string          `json:"name"`
+	AccountType       string          `json:"account_type"`
+	Currency          string          `json:"currency"`
+	CurrentBalance    float64         `json:"current_balance"`
+	ProjectedBalance  float64         `json:"projected_balance"` // saldo al final del horizonte
+	LowestBalance     float64         `json:"lowest_balance"`
+	LowestBalanceDate time.Time       `json:"lowest_balance_date"`
+	ProjectedIncome   float64         `json:"projected_income"`
+	ProjectedExpenses float64         `json:"projected_expenses"`
+	AvgDailyIncome    float64         `json:"avg_daily_income"`  // promedio histórico sin movimientos ya proyectados
+	AvgDailyExpense   float64         `json:"avg_daily_expense"` // promedio histórico sin movimientos ya proyectados
+	NegativeDays      int             `json:"negative_days"`
+	FirstNegativeDate *time.Time      `json:"first_negative_date,omitempty"`
+	Events            []ForecastEvent `json:"events"`
+	Daily             []ForecastDay   `json:"daily"`
+}
+
+// ForecastEvent movimiento conocido que entra en la proyección
+type ForecastEvent struct {
+	Date        time.Time `json:"date"`
+	Source      string    `json:"source"` // scheduled, installment, card_payment
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"` // positivo ingresa, negativo egresa
+}
+
+// ForecastDay saldo proyectado al final de un día
+type ForecastDay struct {
+	Date     time.Time `json:"date"`
+	Income   float64   `json:"income"`
+	Expenses float64   `json:"expenses"`
+	Balance  float64   `json:"balance"`
+	Negative bool      `json:"negative"`
+}
+
+// CashFlowInputs datos que alimentan la proyección de flujo de caja
+type CashFlowInputs struct {
+	Accounts     []ForecastAccount
+	Schedules    []ForecastSchedule
+	Installments []ForecastInstallment
+	Cards        []ForecastCard
+}
+
+// ForecastAccount cuenta de dinero con su saldo actual y sus promedios históricos
+type ForecastAccount struct {
+	ID              string
+	Name            string
+	AccountType     string
+	Currency        string
+	Balance         float64
+	AvgDailyIncome  float64
+	AvgDailyExpense float64
+}
+
+// ForecastSchedule transacción programada activa, con las cuentas resueltas: un débito con tarjeta de
+// débito sale de la cuenta de la tarjeta.
+type ForecastSchedule struct {
+	ID               string
+	Type             string
+	Amount           float64
+	NextAmount       *float64
+	Description      string
+	FromAccountID    string
+	ToAccountID      string
+	Recurrence       string
+	StartAt          time.Time
+	NextOccurrenceAt time.Time
+	NextRunAt        time.Time
+	OccurrenceCount  int
+}
+
+// ForecastInstallment cuota pendiente que vence dentro del horizonte, o ya vencida
+type ForecastInstallment struct {
+	ID          string
+	CardID      string
+	Currency    string
+	Description string
+	Number      int
+	Amount      float64
+	DueDate     time.Time
+}
+
+// ForecastCard tarjeta de crédito con su deuda, su vencimiento y la cuenta que suele pagarla
+type ForecastCard struct {
+	ID                string
+	Name              string
+	Currency          string
+	Debt              float64 // deuda del resumen, sin las cuotas pendientes
+	DueDate           time.Time
+	AvgMonthlyCharges float64
+	PaymentAccountID  string // cuenta del último pago, vacía si nunca se pagó
+}
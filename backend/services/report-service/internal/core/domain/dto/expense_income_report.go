@@ -18,6 +18,9 @@ type ExpenseIncomeReportResponse struct {
 	ByPeriod   []ExpenseIncomeByPeriod   `json:"by_period"`
 	ByCategory []ExpenseIncomeByCategory `json:"by_category"`
 	Trend      TrendAnalysis             `json:"trend"`
+
+	Subscriptions SubscriptionsSummary `json:"subscriptions"`
+	Budgets       BudgetsSummary       `json:"budgets"`
 }
 
 // ExpenseIncomeSummary resumen de gastos vs ingresos
@@ -50,6 +53,59 @@ type ExpenseIncomeByCategory struct {
 	Percentage float64 `json:"percentage"`
 }
 
+// SubscriptionsSummary suscripciones detectadas que cobraron en el período o siguen cobrando
+type SubscriptionsSummary struct {
+	Items          []SubscriptionItem `json:"items"`
+	ActiveCount    int                `json:"active_count"`
+	MissedCount    int                `json:"missed_count"`
+	CancelledCount int                `json:"cancelled_count"`
+	PriceIncreases int                `json:"price_increases"` // aumentos de precio dentro del período
+	MonthlyCost    map[string]float64 `json:"monthly_cost"`    // por moneda, solo las que siguen cobrando
+	AnnualizedCost map[string]float64 `json:"annualized_cost"` // por moneda, solo las que siguen cobrando
+}
+
+// SubscriptionItem suscripción detectada a partir del historial de transacciones
+type SubscriptionItem struct {
+	MerchantName   string     `json:"merchant_name"`
+	Period         string     `json:"period"` // weekly, biweekly, monthly, quarterly, yearly
+	Amount         float64    `json:"amount"`
+	Currency       string     `json:"currency"`
+	AnnualizedCost float64    `json:"annualized_cost"`
+	Status         string     `json:"status"` // active, missed, cancelled
+	LastChargeAt   time.Time  `json:"last_charge_at"`
+	NextExpectedAt time.Time  `json:"next_expected_at"`
+	PreviousAmount *float64   `json:"previous_amount,omitempty"`
+	PriceChangedAt *time.Time `json:"price_changed_at,omitempty"`
+}
+
+// BudgetsSummary presupuestos mensuales de los meses del período contra el gasto real
+type BudgetsSummary struct {
+	Items         []BudgetItem       `json:"items"`
+	OnTrackCount  int                `json:"on_track_count"`
+	WarningCount  int                `json:"warning_count"`
+	ExceededCount int                `json:"exceeded_count"`
+	TotalLimit    map[string]float64 `json:"total_limit"`  // por moneda
+	TotalActual   map[string]float64 `json:"total_actual"` // por moneda
+}
+
+// BudgetItem un mes de un presupuesto calculado por transaction-service
+type BudgetItem struct {
+	BudgetID     string  `json:"budget_id"`
+	Name         string  `json:"name"`
+	Scope        string  `json:"scope"`  // category, merchant, card
+	Target       string  `json:"target"` // categoría, comercio o tarjeta
+	Month        string  `json:"month"`  // YYYY-MM
+	Currency     string  `json:"currency"`
+	Limit        float64 `json:"limit"`
+	CarriedOver  float64 `json:"carried_over"`
+	Spent        float64 `json:"spent"`
+	Installments float64 `json:"installments"` // cuotas que vencen en el mes
+	Actual       float64 `json:"actual"`
+	Remaining    float64 `json:"remaining"`
+	PercentUsed  float64 `json:"percent_used"`
+	Status       string  `json:"status"` // on_track, warning, exceeded
+}
+
 // TrendAnalysis análisis de tendencias
 type TrendAnalysis struct {
 	IncomesTrend  string        `json:"incomes_trend"`  // increasing, decreasing, stable
@@ -16,11 +16,14 @@ type ReportRepository interface {
 	GetInstallmentReport(ctx context.Context, userID string, status string) (*dto.InstallmentReportResponse, error)
 
 	// Reportes de cuentas
-	GetAccountReport(ctx context.Context, userID string) (*dto.AccountReportResponse, error)
+	GetAccountReport(ctx context.Context, userID string, asOf *time.Time) (*dto.AccountReportResponse, error)
 
 	// Reportes de gastos vs ingresos
 	GetExpenseIncomeReport(ctx context.Context, userID string, startDate, endDate time.Time) (*dto.ExpenseIncomeReportResponse, error)
 
 	// Reportes de notificaciones
 	GetNotificationReport(ctx context.Context, startDate, endDate time.Time) (*dto.NotificationReportResponse, error)
+
+	// Datos para la proyección de flujo de caja
+	GetCashFlowInputs(ctx context.Context, userID string, until time.Time, historyDays int) (*dto.CashFlowInputs, error)
 }
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/fintrack/report-service/internal/core/domain/dto"
+)
+
+// DefaultForecastDays es el horizonte de la proyección cuando no se indica uno
+const DefaultForecastDays = 30
+
+// ForecastHistoryDays son los días de historial que se promedian para los movimientos sin programar
+const ForecastHistoryDays = 90
+
+// ForecastHorizons son los horizontes de proyección permitidos
+var ForecastHorizons = []int{30, 60, 90}
+
+// GetCashFlowForecast proyecta el saldo diario de las cuentas de dinero del usuario. Parte del saldo
+// actual y suma las transacciones programadas, las cuotas y los resúmenes de tarjeta que vencen, y el
+// promedio diario histórico de lo que no está programado.
+func (s *reportService) GetCashFlowForecast(ctx context.Context, req *dto.CashFlowForecastRequest) (*dto.CashFlowForecastResponse, error) {
+	days := req.Days
+	if days == 0 {
+		days = DefaultForecastDays
+	}
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	end := start.AddDate(0, 0, days)
+
+	inputs, err := s.repo.GetCashFlowInputs(ctx, req.UserID, end, ForecastHistoryDays)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.CashFlowForecastResponse{
+		UserID:      req.UserID,
+		Days:        days,
+		StartDate:   start,
+		EndDate:     end.AddDate(0, 0, -1),
+		HistoryDays: ForecastHistoryDays,
+		Accounts:    []dto.AccountForecast{},
+	}
+
+	for _, forecast := range projectCashFlow(inputs, start, days) {
+		if req.AccountID != "" && forecast.AccountID != req.AccountID {
+			continue
+		}
+		response.Accounts = append(response.Accounts, forecast)
+	}
+	if req.AccountID != "" && len(response.Accounts) == 0 {
+		return nil, fmt.Errorf("cuenta no encontrada: %s", req.AccountID)
+	}
+
+	summary := dto.ForecastSummary{TotalAccounts: len(response.Accounts)}
+	for _, forecast := range response.Accounts {
+		summary.CurrentBalance += forecast.CurrentBalance
+		summary.ProjectedBalance += forecast.ProjectedBalance
+		summary.ProjectedIncome += forecast.ProjectedIncome
+		summary.ProjectedExpenses += forecast.ProjectedExpenses
+		if forecast.FirstNegativeDate != nil {
+			summary.AccountsAtRisk++
+			if summary.FirstNegativeDate == nil || forecast.FirstNegativeDate.Before(*summary.FirstNegativeDate) {
+				summary.FirstNegativeDate = forecast.FirstNegativeDate
+			}
+		}
+	}
+	summary.CurrentBalance = roundCents(summary.CurrentBalance)
+	summary.ProjectedBalance = roundCents(summary.ProjectedBalance)
+	summary.ProjectedIncome = roundCents(summary.ProjectedIncome)
+	summary.ProjectedExpenses = roundCents(summary.ProjectedExpenses)
+	response.Summary = summary
+
+	return response, nil
+}
+
+// accountProjection acumula los movimientos proyectados de una cuenta, por día
+type accountProjection struct {
+	income   []float64
+	expenses []float64
+	events   []dto.ForecastEvent
+}
+
+// projectCashFlow arma la proyección diaria de cada cuenta desde start por days días. Lo vencido y no
+// pagado se proyecta en el primer día.
+func projectCashFlow(inputs *dto.CashFlowInputs, start time.Time, days int) []dto.AccountForecast {
+	end := start.AddDate(0, 0, days)
+
+	projections := make(map[string]*accountProjection, len(inputs.Accounts))
+	for _, account := range inputs.Accounts {
+		projections[account.ID] = &accountProjection{
+			income:   make([]float64, days),
+			expenses: make([]float64, days),
+			events:   []dto.ForecastEvent{},
+		}
+	}
+
+	addEvent := func(accountID string, date time.Time, source, description string, amount float64) {
+		projection, ok := projections[accountID]
+		if !ok || amount == 0 {
+			return
+		}
+		day := int(math.Round(dayOf(date).Sub(start).Hours() / 24))
+		if day < 0 {
+			day = 0
+			date = start
+		}
+		if day >= days {
+			return
+		}
+		if amount > 0 {
+			projection.income[day] += amount
+		} else {
+			projection.expenses[day] -= amount
+		}
+		projection.events = append(projection.events, dto.ForecastEvent{
+			Date:        dayOf(date),
+			Source:      source,
+			Description: description,
+			Amount:      roundCents(amount),
+		})
+	}
+
+	// Transacciones programadas: salen de la cuenta de origen y entran en la de destino
+	for _, schedule := range inputs.Schedules {
+		for _, occurrence := range scheduleOccurrences(schedule, end) {
+			description := schedule.Description
+			if description == "" {
+				description = schedule.Type
+			}
+			if schedule.FromAccountID != "" {
+				addEvent(schedule.FromAccountID, occurrence.date, "scheduled", description, -occurrence.amount)
+			}
+			if schedule.ToAccountID != "" {
+				addEvent(schedule.ToAccountID, occurrence.date, "scheduled", description, occurrence.amount)
+			}
+		}
+	}
+
+	// Las cuotas y los resúmenes de una tarjeta los paga la cuenta del último pago de la tarjeta, o la
+	// cuenta con más saldo en la moneda de la tarjeta
+	paymentAccounts := make(map[string]string, len(inputs.Cards))
+	for _, card := range inputs.Cards {
+		paymentAccounts[card.ID] = paymentAccount(inputs.Accounts, card.PaymentAccountID, card.Currency)
+	}
+
+	for _, installment := range inputs.Installments {
+		accountID, ok := paymentAccounts[installment.CardID]
+		if !ok {
+			accountID = paymentAccount(inputs.Accounts, "", installment.Currency)
+		}
+		description := fmt.Sprintf("Cuota %d", installment.Number)
+		if installment.Description != "" {
+			description += " - " + installment.Description
+		}
+		addEvent(accountID, installment.DueDate, "installment", description, -installment.Amount)
+	}
+
+	// El primer vencimiento de cada tarjeta paga su deuda actual; los siguientes, el consumo mensual
+	// promedio
+	for _, card := range inputs.Cards {
+		for i, due := range cardDueDates(card.DueDate, start, end) {
+			amount := card.AvgMonthlyCharges
+			if i == 0 {
+				amount = math.Max(card.Debt, 0)
+			}
+			addEvent(paymentAccounts[card.ID], due, "card_payment", "Resumen "+card.Name, -amount)
+		}
+	}
+
+	forecasts := make([]dto.AccountForecast, 0, len(inputs.Accounts))
+	for _, account := range inputs.Accounts {
+		projection := projections[account.ID]
+		forecast := dto.AccountForecast{
+			AccountID:         account.ID,
+			Name:              account.Name,
+			AccountType:       account.AccountType,
+			Currency:          account.Currency,
+			CurrentBalance:    roundCents(account.Balance),
+			LowestBalance:     roundCents(account.Balance),
+			LowestBalanceDate: start,
+			AvgDailyIncome:    roundCents(account.AvgDailyIncome),
+			AvgDailyExpense:   roundCents(account.AvgDailyExpense),
+			Events:            projection.events,
+			Daily:             make([]dto.ForecastDay, 0, days),
+		}
+		sort.SliceStable(forecast.Events, func(i, j int) bool { return forecast.Events[i].Date.Before(forecast.Events[j].Date) })
+
+		balance := account.Balance
+		for day := 0; day < days; day++ {
+			date := start.AddDate(0, 0, day)
+			income := account.AvgDailyIncome + projection.income[day]
+			expenses := account.AvgDailyExpense + projection.expenses[day]
+			balance += income - expenses
+
+			point := dto.ForecastDay{
+				Date:     date,
+				Income:   roundCents(income),
+				Expenses: roundCents(expenses),
+				Balance:  roundCents(balance),
+			}
+			point.Negative = point.Balance < 0
+			forecast.Daily = append(forecast.Daily, point)
+
+			forecast.ProjectedIncome += income
+			forecast.ProjectedExpenses += expenses
+			if point.Balance < forecast.LowestBalance {
+				forecast.LowestBalance = point.Balance
+				forecast.LowestBalanceDate = date
+			}
+			if point.Negative {
+				forecast.NegativeDays++
+				if forecast.FirstNegativeDate == nil {
+					negativeDate := date
+					forecast.FirstNegativeDate = &negativeDate
+				}
+			}
+		}
+
+		forecast.ProjectedBalance = roundCents(balance)
+		forecast.ProjectedIncome = roundCents(forecast.ProjectedIncome)
+		forecast.ProjectedExpenses = roundCents(forecast.ProjectedExpenses)
+		forecasts = append(forecasts, forecast)
+	}
+
+	return forecasts
+}
+
+// scheduledOccurrence ocurrencia proyectada de una transacción programada
+type scheduledOccurrence struct {
+	date   time.Time
+	amount float64
+}
+
+// scheduleOccurrences devuelve las ocurrencias de una transacción programada anteriores a end. La
+// próxima usa su fecha de ejecución y su monto puntual; las siguientes salen de la recurrencia.
+func scheduleOccurrences(schedule dto.ForecastSchedule, end time.Time) []scheduledOccurrence {
+	if !schedule.NextRunAt.Before(end) {
+		return nil
+	}
+
+	amount := schedule.Amount
+	if schedule.NextAmount != nil {
+		amount = *schedule.NextAmount
+	}
+	occurrences := []scheduledOccurrence{{date: schedule.NextRunAt, amount: amount}}
+
+	rule, err := parseRecurrence(schedule.Recurrence)
+	if err != nil {
+		log.Printf("⚠️ Recurrencia inválida en la transacción programada %s: %v", schedule.ID, err)
+		return occurrences
+	}
+	if rule == nil {
+		return occurrences
+	}
+
+	remaining := -1
+	if rule.count > 0 {
+		remaining = rule.count - schedule.OccurrenceCount - 1
+	}
+
+	after := schedule.NextOccurrenceAt
+	for remaining != 0 {
+		next, ok := rule.next(schedule.StartAt, after)
+		if !ok || !next.Before(end) {
+			break
+		}
+		occurrences = append(occurrences, scheduledOccurrence{date: next, amount: schedule.Amount})
+		after = next
+		remaining--
+	}
+
+	return occurrences
+}
+
+// paymentAccount elige la cuenta que paga una tarjeta: la preferida si está entre las cuentas de
+// dinero, o la de más saldo en la moneda indicada
+func paymentAccount(accounts []dto.ForecastAccount, preferred, currency string) string {
+	best := ""
+	bestBalance := math.Inf(-1)
+	for _, account := range accounts {
+		if preferred != "" && account.ID == preferred {
+			return account.ID
+		}
+		if account.Currency == currency && account.Balance > bestBalance {
+			best = account.ID
+			bestBalance = account.Balance
+		}
+	}
+	return best
+}
+
+// cardDueDates devuelve los vencimientos mensuales de una tarjeta entre start y end, conservando el
+// día de su vencimiento (ajustado en los meses más cortos)
+func cardDueDates(due, start, end time.Time) []time.Time {
+	due = dayOf(due)
+	dates := []time.Time{}
+	for months := 0; ; months++ {
+		date := dayInMonth(due, due.Year(), due.Month()+time.Month(months), due.Day())
+		if !date.Before(end) {
+			break
+		}
+		if !date.Before(start) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// forecastSummary calcula la proyección del próximo mes del reporte de gastos vs ingresos
+func (s *reportService) forecastSummary(ctx context.Context, userID string) (*dto.ForecastData, error) {
+	forecast, err := s.GetCashFlowForecast(ctx, &dto.CashFlowForecastRequest{UserID: userID, Days: DefaultForecastDays})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ForecastData{
+		NextMonthIncome:   forecast.Summary.ProjectedIncome,
+		NextMonthExpenses: forecast.Summary.ProjectedExpenses,
+		NextMonthNet:      roundCents(forecast.Summary.ProjectedIncome - forecast.Summary.ProjectedExpenses),
+	}, nil
+}
+
+// dayOf devuelve el comienzo del día de una fecha, en la zona horaria local
+func dayOf(date time.Time) time.Time {
+	local := date.In(time.Local)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fintrack/report-service/internal/core/domain/dto"
+)
+
+// forecastStart es el primer día de las proyecciones de los tests
+var forecastStart = time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+
+func forecastDay(day int) time.Time {
+	return forecastStart.AddDate(0, 0, day)
+}
+
+func float64Ptr(value float64) *float64 {
+	return &value
+}
+
+// forecastInputs arma un usuario con una cuenta corriente en pesos que cobra el sueldo y paga el
+// alquiler, una caja de ahorro en pesos que paga la Visa y una cuenta en dólares que paga la Master
+func forecastInputs() *dto.CashFlowInputs {
+	at := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.Local)
+	}
+
+	return &dto.CashFlowInputs{
+		Accounts: []dto.ForecastAccount{
+			{ID: "corriente", Name: "Cuenta corriente", AccountType: "checking", Currency: "ARS", Balance: 50000, AvgDailyIncome: 500, AvgDailyExpense: 1500},
+			{ID: "dolares", Name: "Caja en dólares", AccountType: "savings", Currency: "USD", Balance: 1000},
+			{ID: "ahorro", Name: "Caja de ahorro", AccountType: "savings", Currency: "ARS", Balance: 200000},
+		},
+		Schedules: []dto.ForecastSchedule{
+			{
+				ID: "sueldo", Type: "account_deposit", Amount: 300000, Description: "Sueldo", ToAccountID: "corriente",
+				Recurrence: "FREQ=MONTHLY;BYMONTHDAY=1", StartAt: at(2025, 1, 1, 9),
+				NextOccurrenceAt: at(2025, 4, 1, 9), NextRunAt: at(2025, 4, 1, 9), OccurrenceCount: 2,
+			},
+			{
+				// El próximo alquiler ya tiene el aumento cargado
+				ID: "alquiler", Type: "account_withdraw", Amount: 55000, NextAmount: float64Ptr(60000), Description: "Alquiler",
+				FromAccountID: "corriente", Recurrence: "FREQ=MONTHLY;BYMONTHDAY=15", StartAt: at(2025, 1, 15, 10),
+				NextOccurrenceAt: at(2025, 3, 15, 10), NextRunAt: at(2025, 3, 15, 10), OccurrenceCount: 2,
+			},
+			{
+				// Quedan dos de tres transferencias semanales
+				ID: "transferencia", Type: "account_transfer", Amount: 10000, FromAccountID: "ahorro", ToAccountID: "corriente",
+				Recurrence: "FREQ=WEEKLY;COUNT=3", StartAt: at(2025, 3, 5, 8),
+				NextOccurrenceAt: at(2025, 3, 12, 8), NextRunAt: at(2025, 3, 12, 8), OccurrenceCount: 1,
+			},
+			{
+				// Vencida sin ejecutar: se proyecta en el primer día
+				ID: "suscripcion", Type: "account_withdraw", Amount: 50, Description: "Suscripción", FromAccountID: "dolares",
+				NextOccurrenceAt: at(2025, 3, 8, 12), NextRunAt: at(2025, 3, 8, 12),
+			},
+		},
+		Installments: []dto.ForecastInstallment{
+			{ID: "cuota-heladera", CardID: "visa", Currency: "ARS", Description: "Heladera", Number: 3, Amount: 15000, DueDate: at(2025, 3, 15, 0)},
+			{ID: "cuota-vencida", CardID: "master", Currency: "USD", Number: 1, Amount: 100, DueDate: at(2025, 3, 1, 0)},
+			// Cuota de una tarjeta que no está entre las del usuario: la paga la cuenta con más saldo en su moneda
+			{ID: "cuota-otra", CardID: "tarjeta-adicional", Currency: "ARS", Number: 2, Amount: 5000, DueDate: at(2025, 3, 25, 0)},
+			{ID: "cuota-futura", CardID: "visa", Currency: "ARS", Number: 4, Amount: 15000, DueDate: at(2025, 4, 15, 0)},
+		},
+		Cards: []dto.ForecastCard{
+			{ID: "visa", Name: "Visa", Currency: "ARS", Debt: 80000, DueDate: at(2025, 2, 15, 0), AvgMonthlyCharges: 50000},
+			{ID: "master", Name: "Master", Currency: "USD", Debt: 200, DueDate: at(2025, 3, 20, 0), PaymentAccountID: "dolares"},
+		},
+	}
+}
+
+func findForecast(t *testing.T, forecasts []dto.AccountForecast, accountID string) dto.AccountForecast {
+	t.Helper()
+	for _, forecast := range forecasts {
+		if forecast.AccountID == accountID {
+			return forecast
+		}
+	}
+	t.Fatalf("no hay proyección de la cuenta %s", accountID)
+	return dto.AccountForecast{}
+}
+
+func TestProjectCashFlow(t *testing.T) {
+	forecasts := projectCashFlow(forecastInputs(), forecastStart, 30)
+
+	type event struct {
+		day         int
+		source      string
+		description string
+		amount      float64
+	}
+	tests := []struct {
+		accountID                string
+		expectedBalance          float64
+		expectedIncome           float64
+		expectedExpenses         float64
+		expectedLowest           float64
+		expectedLowestDay        int
+		expectedFirstNegativeDay int // -1 si nunca queda en negativo
+		expectedNegativeDays     int
+		expectedEvents           []event
+		expectedBalancesByDay    map[int]float64
+		expectedNegativeByDay    map[int]bool
+	}{
+		{
+			// Pierde 1000 por día en promedio; el alquiler la deja en negativo hasta que cobra el sueldo
+			accountID:                "corriente",
+			expectedBalance:          280000,
+			expectedIncome:           335000,
+			expectedExpenses:         105000,
+			expectedLowest:           -12000,
+			expectedLowestDay:        21,
+			expectedFirstNegativeDay: 5,
+			expectedNegativeDays:     16,
+			expectedEvents: []event{
+				{2, "scheduled", "account_transfer", 10000},
+				{5, "scheduled", "Alquiler", -60000},
+				{9, "scheduled", "account_transfer", 10000},
+				{22, "scheduled", "Sueldo", 300000},
+			},
+			expectedBalancesByDay: map[int]float64{0: 49000, 2: 57000, 5: -6000, 9: 0, 10: -1000, 21: -12000, 22: 287000},
+			expectedNegativeByDay: map[int]bool{5: true, 9: false, 10: true, 22: false},
+		},
+		{
+			// Lo vencido se proyecta el primer día
+			accountID:                "dolares",
+			expectedBalance:          650,
+			expectedExpenses:         350,
+			expectedLowest:           650,
+			expectedLowestDay:        10,
+			expectedFirstNegativeDay: -1,
+			expectedEvents: []event{
+				{0, "scheduled", "Suscripción", -50},
+				{0, "installment", "Cuota 1", -100},
+				{10, "card_payment", "Resumen Master", -200},
+			},
+			expectedBalancesByDay: map[int]float64{0: 850, 9: 850, 10: 650, 29: 650},
+		},
+		{
+			// Paga la Visa por tener más saldo en pesos que la cuenta corriente
+			accountID:                "ahorro",
+			expectedBalance:          80000,
+			expectedExpenses:         120000,
+			expectedLowest:           80000,
+			expectedLowestDay:        15,
+			expectedFirstNegativeDay: -1,
+			expectedEvents: []event{
+				{2, "scheduled", "account_transfer", -10000},
+				{5, "installment", "Cuota 3 - Heladera", -15000},
+				{5, "card_payment", "Resumen Visa", -80000},
+				{9, "scheduled", "account_transfer", -10000},
+				{15, "installment", "Cuota 2", -5000},
+			},
+			expectedBalancesByDay: map[int]float64{1: 200000, 2: 190000, 5: 95000, 29: 80000},
+		},
+	}
+
+	if len(forecasts) != len(tests) {
+		t.Fatalf("projectCashFlow() = %d cuentas, se esperaban %d", len(forecasts), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.accountID, func(t *testing.T) {
+			forecast := findForecast(t, forecasts, tt.accountID)
+
+			if forecast.ProjectedBalance != tt.expectedBalance || forecast.ProjectedIncome != tt.expectedIncome || forecast.ProjectedExpenses != tt.expectedExpenses {
+				t.Errorf("saldo proyectado %v con ingresos %v y gastos %v, se esperaba %v con %v y %v",
+					forecast.ProjectedBalance, forecast.ProjectedIncome, forecast.ProjectedExpenses,
+					tt.expectedBalance, tt.expectedIncome, tt.expectedExpenses)
+			}
+			if forecast.CurrentBalance+forecast.ProjectedIncome-forecast.ProjectedExpenses != forecast.ProjectedBalance {
+				t.Errorf("el saldo actual más ingresos menos gastos no da el saldo proyectado %v", forecast.ProjectedBalance)
+			}
+			if forecast.LowestBalance != tt.expectedLowest || !forecast.LowestBalanceDate.Equal(forecastDay(tt.expectedLowestDay)) {
+				t.Errorf("saldo mínimo %v el %v, se esperaba %v el %v", forecast.LowestBalance, forecast.LowestBalanceDate,
+					tt.expectedLowest, forecastDay(tt.expectedLowestDay))
+			}
+
+			if tt.expectedFirstNegativeDay < 0 {
+				if forecast.FirstNegativeDate != nil || forecast.NegativeDays != 0 {
+					t.Errorf("queda en negativo el %v por %d días, se esperaba que no", forecast.FirstNegativeDate, forecast.NegativeDays)
+				}
+			} else if forecast.FirstNegativeDate == nil || !forecast.FirstNegativeDate.Equal(forecastDay(tt.expectedFirstNegativeDay)) || forecast.NegativeDays != tt.expectedNegativeDays {
+				t.Errorf("queda en negativo el %v por %d días, se esperaba el %v por %d días", forecast.FirstNegativeDate,
+					forecast.NegativeDays, forecastDay(tt.expectedFirstNegativeDay), tt.expectedNegativeDays)
+			}
+
+			if len(forecast.Daily) != 30 {
+				t.Fatalf("%d días proyectados, se esperaban 30", len(forecast.Daily))
+			}
+			for day, expected := range tt.expectedBalancesByDay {
+				point := forecast.Daily[day]
+				if point.Balance != expected || !point.Date.Equal(forecastDay(day)) {
+					t.Errorf("día %d: saldo %v el %v, se esperaba %v", day, point.Balance, point.Date, expected)
+				}
+			}
+			for day, expected := range tt.expectedNegativeByDay {
+				if forecast.Daily[day].Negative != expected {
+					t.Errorf("día %d: negativo %v, se esperaba %v", day, forecast.Daily[day].Negative, expected)
+				}
+			}
+
+			if len(forecast.Events) != len(tt.expectedEvents) {
+				t.Fatalf("%d movimientos proyectados %+v, se esperaban %d", len(forecast.Events), forecast.Events, len(tt.expectedEvents))
+			}
+			for i, expected := range tt.expectedEvents {
+				got := forecast.Events[i]
+				if !got.Date.Equal(forecastDay(expected.day)) || got.Source != expected.source || got.Description != expected.description || got.Amount != expected.amount {
+					t.Errorf("movimiento %d = %+v, se esperaba %+v", i, got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestProjectCashFlowCardPayments(t *testing.T) {
+	inputs := forecastInputs()
+	inputs.Schedules, inputs.Installments = nil, nil
+	inputs.Cards = []dto.ForecastCard{
+		// El primer vencimiento paga la deuda y los siguientes el consumo promedio
+		{ID: "visa", Name: "Visa", Currency: "ARS", Debt: 80000, DueDate: time.Date(2025, 2, 15, 0, 0, 0, 0, time.Local), AvgMonthlyCharges: 50000, PaymentAccountID: "corriente"},
+		// Saldo a favor: el primer vencimiento no paga nada
+		{ID: "amex", Name: "Amex", Currency: "ARS", Debt: -3000, DueDate: time.Date(2025, 3, 31, 0, 0, 0, 0, time.Local), AvgMonthlyCharges: 20000},
+	}
+
+	forecasts := projectCashFlow(inputs, forecastStart, 60)
+
+	checking := findForecast(t, forecasts, "corriente")
+	var payments []dto.ForecastEvent
+	for _, event := range checking.Events {
+		if event.Source == "card_payment" {
+			payments = append(payments, event)
+		}
+	}
+	if len(payments) != 2 || payments[0].Amount != -80000 || !payments[0].Date.Equal(forecastDay(5)) ||
+		payments[1].Amount != -50000 || !payments[1].Date.Equal(forecastDay(36)) {
+		t.Errorf("pagos de la Visa = %+v, se esperaban 80000 el 15/03 y 50000 el 15/04", payments)
+	}
+
+	// La Amex vence el 31: en abril vence el 30
+	savings := findForecast(t, forecasts, "ahorro")
+	if len(savings.Events) != 1 || savings.Events[0].Amount != -20000 || !savings.Events[0].Date.Equal(time.Date(2025, 4, 30, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("pagos de la Amex = %+v, se esperaban 20000 el 30/04", savings.Events)
+	}
+}
+
+func TestProjectCashFlowWithoutInputs(t *testing.T) {
+	inputs := &dto.CashFlowInputs{
+		Accounts: []dto.ForecastAccount{{ID: "billetera", Currency: "ARS", Balance: 1234.567}},
+		// Movimientos de cuentas que no son de dinero no se proyectan
+		Schedules: []dto.ForecastSchedule{{ID: "inversion", Amount: 100, FromAccountID: "plazo-fijo", NextRunAt: forecastDay(1)}},
+	}
+
+	forecasts := projectCashFlow(inputs, forecastStart, 30)
+
+	if len(forecasts) != 1 {
+		t.Fatalf("projectCashFlow() = %d cuentas, se esperaba 1", len(forecasts))
+	}
+	forecast := forecasts[0]
+	if forecast.CurrentBalance != 1234.57 || forecast.ProjectedBalance != 1234.57 || len(forecast.Events) != 0 {
+		t.Errorf("projectCashFlow() = %+v, se esperaba el saldo sin cambios", forecast)
+	}
+	if forecast.LowestBalance != 1234.57 || !forecast.LowestBalanceDate.Equal(forecastStart) {
+		t.Errorf("saldo mínimo %v el %v, se esperaba el saldo actual el primer día", forecast.LowestBalance, forecast.LowestBalanceDate)
+	}
+}
+
+func TestScheduleOccurrences(t *testing.T) {
+	end := forecastDay(30)
+	monthly := dto.ForecastSchedule{
+		ID: "expensas", Amount: 40000, Recurrence: "FREQ=MONTHLY;BYMONTHDAY=31",
+		StartAt:          time.Date(2025, 1, 31, 9, 0, 0, 0, time.Local),
+		NextOccurrenceAt: time.Date(2025, 3, 31, 9, 0, 0, 0, time.Local),
+		// Se ejecuta el día hábil siguiente
+		NextRunAt: time.Date(2025, 4, 1, 9, 0, 0, 0, time.Local),
+	}
+
+	tests := []struct {
+		name     string
+		schedule dto.ForecastSchedule
+		end      time.Time
+		expected []string
+	}{
+		{"mensual dentro del horizonte", monthly, end, []string{"2025-04-01"}},
+		{"mensual en un horizonte más largo", monthly, forecastDay(90), []string{"2025-04-01", "2025-04-30", "2025-05-31"}},
+		{"próxima ejecución fuera del horizonte", dto.ForecastSchedule{Amount: 10, NextRunAt: end}, end, nil},
+		{"recurrencia inválida", dto.ForecastSchedule{Amount: 10, Recurrence: "FREQ=MONTHLY;BYHOUR=3", NextRunAt: forecastDay(1)}, end, []string{"2025-03-11"}},
+		{"termina por UNTIL", dto.ForecastSchedule{
+			Amount: 10, Recurrence: "FREQ=DAILY;UNTIL=20250313", StartAt: forecastDay(0).Add(8 * time.Hour),
+			NextOccurrenceAt: forecastDay(0).Add(8 * time.Hour), NextRunAt: forecastDay(0).Add(8 * time.Hour),
+		}, end, []string{"2025-03-10", "2025-03-11", "2025-03-12", "2025-03-13"}},
+		{"última ocurrencia de COUNT", dto.ForecastSchedule{
+			Amount: 10, Recurrence: "FREQ=WEEKLY;COUNT=4", StartAt: forecastDay(-21), OccurrenceCount: 3,
+			NextOccurrenceAt: forecastDay(0), NextRunAt: forecastDay(0),
+		}, end, []string{"2025-03-10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occurrences := scheduleOccurrences(tt.schedule, tt.end)
+
+			var dates []string
+			for _, occurrence := range occurrences {
+				dates = append(dates, occurrence.date.Format("2006-01-02"))
+			}
+			if len(dates) != len(tt.expected) {
+				t.Fatalf("scheduleOccurrences() = %v, se esperaba %v", dates, tt.expected)
+			}
+			for i := range dates {
+				if dates[i] != tt.expected[i] {
+					t.Errorf("scheduleOccurrences() = %v, se esperaba %v", dates, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestPaymentAccount(t *testing.T) {
+	accounts := forecastInputs().Accounts
+
+	tests := []struct {
+		preferred string
+		currency  string
+		expected  string
+	}{
+		{"corriente", "ARS", "corriente"},
+		{"", "ARS", "ahorro"},
+		{"cuenta-cerrada", "USD", "dolares"},
+		{"", "EUR", ""},
+	}
+
+	for _, tt := range tests {
+		if got := paymentAccount(accounts, tt.preferred, tt.currency); got != tt.expected {
+			t.Errorf("paymentAccount(%q, %s) = %q, se esperaba %q", tt.preferred, tt.currency, got, tt.expected)
+		}
+	}
+}
+
+// MockReportRepository devuelve las entradas de la proyección armadas desde hoy
+type MockReportRepository struct {
+	ReportRepository
+	inputs      *dto.CashFlowInputs
+	until       time.Time
+	historyDays int
+}
+
+func (m *MockReportRepository) GetCashFlowInputs(ctx context.Context, userID string, until time.Time, historyDays int) (*dto.CashFlowInputs, error) {
+	m.until, m.historyDays = until, historyDays
+	return m.inputs, nil
+}
+
+func TestGetCashFlowForecast(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	repo := &MockReportRepository{inputs: &dto.CashFlowInputs{
+		Accounts: []dto.ForecastAccount{
+			// Queda en negativo al tercer día
+			{ID: "corriente", Currency: "ARS", Balance: 2500, AvgDailyExpense: 1000},
+			{ID: "ahorro", Currency: "ARS", Balance: 10000, AvgDailyIncome: 100},
+			{ID: "dolares", Currency: "USD", Balance: 500},
+		},
+	}}
+	reports := &reportService{repo: repo}
+
+	tests := []struct {
+		name                     string
+		request                  dto.CashFlowForecastRequest
+		expectedDays             int
+		expectedAccounts         int
+		expectedCurrent          float64
+		expectedProjected        float64
+		expectedAtRisk           int
+		expectedFirstNegativeDay int // -1 si ninguna cuenta queda en negativo
+		expectError              bool
+	}{
+		{
+			name:                     "todas las cuentas a 30 días",
+			request:                  dto.CashFlowForecastRequest{UserID: "user-1"},
+			expectedDays:             DefaultForecastDays,
+			expectedAccounts:         3,
+			expectedCurrent:          13000,
+			expectedProjected:        13000 - 30000 + 3000,
+			expectedAtRisk:           1,
+			expectedFirstNegativeDay: 2,
+		},
+		{
+			name:                     "una cuenta a 60 días",
+			request:                  dto.CashFlowForecastRequest{UserID: "user-1", AccountID: "ahorro", Days: 60},
+			expectedDays:             60,
+			expectedAccounts:         1,
+			expectedCurrent:          10000,
+			expectedProjected:        16000,
+			expectedFirstNegativeDay: -1,
+		},
+		{
+			name:        "cuenta que no es del usuario",
+			request:     dto.CashFlowForecastRequest{UserID: "user-1", AccountID: "cuenta-de-otro"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := reports.GetCashFlowForecast(context.Background(), &tt.request)
+			if tt.expectError {
+				if err == nil {
+					t.Error("GetCashFlowForecast() se esperaba un error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetCashFlowForecast() error inesperado: %v", err)
+			}
+
+			if !repo.until.Equal(today.AddDate(0, 0, tt.expectedDays)) || repo.historyDays != ForecastHistoryDays {
+				t.Errorf("GetCashFlowInputs() hasta %v con %d días de historial, se esperaba hasta %v con %d",
+					repo.until, repo.historyDays, today.AddDate(0, 0, tt.expectedDays), ForecastHistoryDays)
+			}
+			if response.Days != tt.expectedDays || !response.StartDate.Equal(today) || !response.EndDate.Equal(today.AddDate(0, 0, tt.expectedDays-1)) {
+				t.Errorf("GetCashFlowForecast() = %d días del %v al %v", response.Days, response.StartDate, response.EndDate)
+			}
+
+			summary := response.Summary
+			if len(response.Accounts) != tt.expectedAccounts || summary.TotalAccounts != tt.expectedAccounts {
+				t.Errorf("GetCashFlowForecast() = %d cuentas, se esperaban %d", len(response.Accounts), tt.expectedAccounts)
+			}
+			if summary.CurrentBalance != tt.expectedCurrent || summary.ProjectedBalance != tt.expectedProjected || summary.AccountsAtRisk != tt.expectedAtRisk {
+				t.Errorf("resumen %v a %v con %d cuentas en riesgo, se esperaba %v a %v con %d", summary.CurrentBalance,
+					summary.ProjectedBalance, summary.AccountsAtRisk, tt.expectedCurrent, tt.expectedProjected, tt.expectedAtRisk)
+			}
+			if tt.expectedFirstNegativeDay < 0 {
+				if summary.FirstNegativeDate != nil {
+					t.Errorf("resumen en negativo el %v, se esperaba que no", summary.FirstNegativeDate)
+				}
+			} else if summary.FirstNegativeDate == nil || !summary.FirstNegativeDate.Equal(today.AddDate(0, 0, tt.expectedFirstNegativeDay)) {
+				t.Errorf("resumen en negativo el %v, se esperaba el %v", summary.FirstNegativeDate, today.AddDate(0, 0, tt.expectedFirstNegativeDay))
+			}
+		})
+	}
+}
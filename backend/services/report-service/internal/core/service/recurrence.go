@@ -0,0 +1,228 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRecurrencePeriods limita la búsqueda de la próxima ocurrencia de una recurrencia
+const maxRecurrencePeriods = 10000
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// recurrence es el subconjunto de RRULE que guardan las transacciones programadas de
+// transaction-service (FREQ, INTERVAL, BYMONTHDAY, BYDAY, BYSETPOS, COUNT y UNTIL). Las reglas ya
+// fueron validadas al programarlas, acá solo se interpretan para proyectar las ocurrencias.
+type recurrence struct {
+	frequency string
+	interval  int
+	monthDay  int            // 1..31 o -1 para el último día; 0 usa el día del inicio
+	weekdays  []time.Weekday // lunes primero
+	setPos    int            // 1 (primero) o -1 (último) de los días BYDAY del mes
+	count     int            // 0 es ilimitada
+	until     *time.Time
+}
+
+// parseRecurrence interpreta una regla RRULE. Una regla vacía es una transacción única y devuelve nil.
+func parseRecurrence(rule string) (*recurrence, error) {
+	rule = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"))
+	if rule == "" {
+		return nil, nil
+	}
+
+	r := &recurrence{interval: 1}
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("parte de recurrencia inválida %q", part)
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.frequency = value
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+		case "BYMONTHDAY":
+			r.monthDay, err = strconv.Atoi(value)
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				weekday, ok := weekdayCodes[strings.TrimSpace(code)]
+				if !ok {
+					return nil, fmt.Errorf("día de recurrencia inválido %q", code)
+				}
+				r.weekdays = append(r.weekdays, weekday)
+			}
+		case "BYSETPOS":
+			r.setPos, err = strconv.Atoi(value)
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(value)
+			r.until = &until
+		default:
+			return nil, fmt.Errorf("parte de recurrencia no soportada %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("valor de recurrencia inválido en %s: %w", key, err)
+		}
+	}
+
+	switch r.frequency {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("frecuencia de recurrencia no soportada %q", r.frequency)
+	}
+	if r.interval < 1 {
+		r.interval = 1
+	}
+
+	sort.Slice(r.weekdays, func(i, j int) bool { return mondayIndex(r.weekdays[i]) < mondayIndex(r.weekdays[j]) })
+	return r, nil
+}
+
+// next devuelve la primera ocurrencia de la serie que empieza en start posterior a after. Devuelve
+// false cuando la serie terminó por UNTIL; COUNT lo controla quien llama.
+func (r *recurrence) next(start, after time.Time) (time.Time, bool) {
+	for period := r.firstPeriod(start, after); period < maxRecurrencePeriods; period++ {
+		for _, candidate := range r.occurrencesInPeriod(start, period*r.interval) {
+			if candidate.Before(start) {
+				continue
+			}
+			if r.until != nil && candidate.After(*r.until) {
+				return time.Time{}, false
+			}
+			if candidate.After(after) {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// firstPeriod estima el primer período que puede tener una ocurrencia posterior a after, para no
+// recorrer las series largas desde el inicio
+func (r *recurrence) firstPeriod(start, after time.Time) int {
+	if !after.After(start) {
+		return 0
+	}
+
+	var elapsed int
+	switch r.frequency {
+	case "DAILY":
+		elapsed = int(after.Sub(start).Hours() / 24)
+	case "WEEKLY":
+		elapsed = int(after.Sub(start).Hours() / (24 * 7))
+	case "MONTHLY":
+		elapsed = (after.Year()-start.Year())*12 + int(after.Month()) - int(start.Month())
+	case "YEARLY":
+		elapsed = after.Year() - start.Year()
+	}
+
+	period := elapsed/r.interval - 1
+	if period < 0 {
+		return 0
+	}
+	return period
+}
+
+// occurrencesInPeriod devuelve en orden las ocurrencias del período que está offset unidades después
+// del inicio
+func (r *recurrence) occurrencesInPeriod(start time.Time, offset int) []time.Time {
+	switch r.frequency {
+	case "DAILY":
+		return []time.Time{start.AddDate(0, 0, offset)}
+
+	case "WEEKLY":
+		if len(r.weekdays) == 0 {
+			return []time.Time{start.AddDate(0, 0, 7*offset)}
+		}
+		weekStart := start.AddDate(0, 0, 7*offset-mondayIndex(start.Weekday()))
+		occurrences := make([]time.Time, 0, len(r.weekdays))
+		for _, weekday := range r.weekdays {
+			occurrences = append(occurrences, weekStart.AddDate(0, 0, mondayIndex(weekday)))
+		}
+		return occurrences
+
+	case "MONTHLY":
+		year, month := start.Year(), start.Month()+time.Month(offset)
+		if len(r.weekdays) > 0 {
+			return []time.Time{r.setPosDay(start, year, month)}
+		}
+		return []time.Time{dayInMonth(start, year, month, r.dayOfMonth(start))}
+
+	case "YEARLY":
+		return []time.Time{dayInMonth(start, start.Year()+offset, start.Month(), r.dayOfMonth(start))}
+	}
+	return nil
+}
+
+func (r *recurrence) dayOfMonth(start time.Time) int {
+	if r.monthDay != 0 {
+		return r.monthDay
+	}
+	return start.Day()
+}
+
+// setPosDay devuelve el primer o el último día del mes que cae en uno de los días BYDAY
+func (r *recurrence) setPosDay(start time.Time, year int, month time.Month) time.Time {
+	last := daysIn(year, month)
+	for i := 0; i < last; i++ {
+		day := 1 + i
+		if r.setPos < 0 {
+			day = last - i
+		}
+		candidate := dayInMonth(start, year, month, day)
+		for _, weekday := range r.weekdays {
+			if candidate.Weekday() == weekday {
+				return candidate
+			}
+		}
+	}
+	return dayInMonth(start, year, month, last)
+}
+
+// dayInMonth arma el día del mes a la hora del inicio. Los días que pasan el fin del mes (y -1) se
+// ajustan al último día.
+func dayInMonth(start time.Time, year int, month time.Month, day int) time.Time {
+	normalized := time.Date(year, month, 1, 0, 0, 0, 0, start.Location())
+	last := daysIn(normalized.Year(), normalized.Month())
+	if day < 0 || day > last {
+		day = last
+	}
+	return time.Date(normalized.Year(), normalized.Month(), day,
+		start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func mondayIndex(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102", "2006-01-02", time.RFC3339} {
+		if until, err := time.Parse(layout, value); err == nil {
+			if len(value) == 8 || len(value) == 10 {
+				// Un UNTIL con solo fecha incluye el día completo
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("fecha UNTIL inválida %q", value)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"github.com/fintrack/report-service/internal/core/domain/dto"
@@ -23,6 +24,9 @@ type ReportService interface {
 
 	// Reportes de notificaciones
 	GetNotificationReport(ctx context.Context, req *dto.NotificationReportRequest) (*dto.NotificationReportResponse, error)
+
+	// Proyección de flujo de caja
+	GetCashFlowForecast(ctx context.Context, req *dto.CashFlowForecastRequest) (*dto.CashFlowForecastResponse, error)
 }
 
 // reportService implementación del servicio
@@ -34,9 +38,10 @@ type reportService struct {
 type ReportRepository interface {
 	GetTransactionReport(ctx context.Context, userID string, startDate, endDate time.Time, txType string) (*dto.TransactionReportResponse, error)
 	GetInstallmentReport(ctx context.Context, userID string, status string) (*dto.InstallmentReportResponse, error)
-	GetAccountReport(ctx context.Context, userID string) (*dto.AccountReportResponse, error)
+	GetAccountReport(ctx context.Context, userID string, asOf *time.Time) (*dto.AccountReportResponse, error)
 	GetExpenseIncomeReport(ctx context.Context, userID string, startDate, endDate time.Time) (*dto.ExpenseIncomeReportResponse, error)
 	GetNotificationReport(ctx context.Context, startDate, endDate time.Time) (*dto.NotificationReportResponse, error)
+	GetCashFlowInputs(ctx context.Context, userID string, until time.Time, historyDays int) (*dto.CashFlowInputs, error)
 }
 
 // NewReportService crea una nueva instancia del servicio
@@ -71,12 +76,25 @@ func (s *reportService) GetInstallmentReport(ctx context.Context, req *dto.Insta
 
 // GetAccountReport obtiene el reporte de cuentas
 func (s *reportService) GetAccountReport(ctx context.Context, req *dto.AccountReportRequest) (*dto.AccountReportResponse, error) {
-	return s.repo.GetAccountReport(ctx, req.UserID)
+	return s.repo.GetAccountReport(ctx, req.UserID, req.AsOf)
 }
 
 // GetExpenseIncomeReport obtiene el reporte de gastos vs ingresos
 func (s *reportService) GetExpenseIncomeReport(ctx context.Context, req *dto.ExpenseIncomeReportRequest) (*dto.ExpenseIncomeReportResponse, error) {
-	return s.repo.GetExpenseIncomeReport(ctx, req.UserID, req.StartDate, req.EndDate)
+	report, err := s.repo.GetExpenseIncomeReport(ctx, req.UserID, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// La proyección del próximo mes sale de la proyección de flujo de caja de las cuentas
+	forecast, err := s.forecastSummary(ctx, req.UserID)
+	if err != nil {
+		log.Printf("⚠️ No se pudo calcular la proyección del próximo mes: %v", err)
+	} else {
+		report.Trend.Forecast = forecast
+	}
+
+	return report, nil
 }
 
 // GetNotificationReport obtiene el reporte de notificaciones
@@ -4,29 +4,35 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/fintrack/report-service/internal/core/domain/dto"
 )
 
-// GetAccountReport obtiene el reporte de cuentas
-func (r *ReportRepository) GetAccountReport(ctx context.Context, userID string) (*dto.AccountReportResponse, error) {
+// GetAccountReport obtiene el reporte de cuentas. Con asOf, los límites de crédito son los
+// vigentes a esa fecha según el historial de límites y el crédito usado cuenta los consumos hasta ella.
+func (r *ReportRepository) GetAccountReport(ctx context.Context, userID string, asOf *time.Time) (*dto.AccountReportResponse, error) {
 	response := &dto.AccountReportResponse{
 		UserID: userID,
+		AsOf:   asOf,
 	}
 
+	accountLimit, accountLimitArgs := creditLimitAt(asOf, "accounts.id", "accounts.credit_limit", false)
+	chargesUntil, chargesUntilArgs := createdUntil(asOf, "t.created_at")
+
 	// Query para resumen
 	summaryQuery := `
 		SELECT 
 			COALESCE(SUM(balance), 0) as total_balance,
 			COUNT(*) as total_accounts,
-			COALESCE(SUM(credit_limit), 0) as total_credit_limit
+			COALESCE(SUM(` + accountLimit + `), 0) as total_credit_limit
 		FROM accounts
 		WHERE BINARY user_id = BINARY ? AND is_active = 1 AND deleted_at IS NULL
 	`
 
 	var summary dto.AccountSummary
-	err := r.db.QueryRowContext(ctx, summaryQuery, userID).Scan(
+	err := r.db.QueryRowContext(ctx, summaryQuery, append(accountLimitArgs, userID)...).Scan(
 		&summary.TotalBalance,
 		&summary.TotalAccounts,
 		&summary.TotalCreditLimit,
@@ -57,9 +63,9 @@ func (r *ReportRepository) GetAccountReport(ctx context.Context, userID string)
 			AND a.account_type = 'credit'
 			AND c.card_type = 'credit'
 			AND t.status IN ('pending', 'completed')
-			AND t.type = 'credit_charge'
+			AND t.type = 'credit_charge'` + chargesUntil + `
 	`
-	err = r.db.QueryRowContext(ctx, creditUsedQuery, userID).Scan(&summary.TotalCreditUsed)
+	err = r.db.QueryRowContext(ctx, creditUsedQuery, append([]interface{}{userID}, chargesUntilArgs...)...).Scan(&summary.TotalCreditUsed)
 	if err != nil {
 		return nil, fmt.Errorf("error calculando crédito usado: %w", err)
 	}
@@ -76,13 +82,13 @@ func (r *ReportRepository) GetAccountReport(ctx context.Context, userID string)
 	accountsQuery := `
 		SELECT 
 			id, account_type, name, currency, balance, 
-			COALESCE(credit_limit, 0) as credit_limit, is_active
+			` + accountLimit + ` as credit_limit, is_active
 		FROM accounts
 		WHERE BINARY user_id = BINARY ? AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, accountsQuery, userID)
+	rows, err := r.db.QueryContext(ctx, accountsQuery, append(accountLimitArgs, userID)...)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo cuentas: %w", err)
 	}
@@ -103,10 +109,11 @@ func (r *ReportRepository) GetAccountReport(ctx context.Context, userID string)
 	response.Accounts = accounts
 
 	// Query para detalle de tarjetas
+	cardLimit, cardLimitArgs := creditLimitAt(asOf, "c.id", "c.credit_limit", true)
 	cardsQuery := `
 		SELECT 
 			c.id, c.account_id, c.card_type, c.card_brand, c.last_four_digits,
-			c.holder_name, c.status, COALESCE(c.credit_limit, 0) as credit_limit,
+			c.holder_name, c.status, ` + cardLimit + ` as credit_limit,
 			COALESCE(c.nickname, '') as nickname
 		FROM cards c
 		JOIN accounts a ON BINARY c.account_id = BINARY a.id
@@ -114,7 +121,7 @@ func (r *ReportRepository) GetAccountReport(ctx context.Context, userID string)
 		ORDER BY c.created_at DESC
 	`
 
-	cardRows, err := r.db.QueryContext(ctx, cardsQuery, userID)
+	cardRows, err := r.db.QueryContext(ctx, cardsQuery, append(cardLimitArgs, userID)...)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo tarjetas: %w", err)
 	}
@@ -136,12 +143,12 @@ func (r *ReportRepository) GetAccountReport(ctx context.Context, userID string)
 		if card.CardType == "credit" {
 			balanceQuery := `
 				SELECT COALESCE(SUM(amount), 0)
-				FROM transactions
-				WHERE from_card_id = ? 
-					AND status IN ('pending', 'completed')
-					AND type = 'credit_charge'
+				FROM transactions t
+				WHERE t.from_card_id = ? 
+					AND t.status IN ('pending', 'completed')
+					AND t.type = 'credit_charge'` + chargesUntil + `
 			`
-			err = r.db.QueryRowContext(ctx, balanceQuery, card.ID).Scan(&card.CurrentBalance)
+			err = r.db.QueryRowContext(ctx, balanceQuery, append([]interface{}{card.ID}, chargesUntilArgs...)...).Scan(&card.CurrentBalance)
 			if err != nil {
 				card.CurrentBalance = 0
 			}
@@ -186,6 +193,37 @@ func (r *ReportRepository) GetAccountReport(ctx context.Context, userID string)
 	return response, nil
 }
 
+// creditLimitAt arma la expresión del límite de crédito de una cuenta o tarjeta. Sin asOf es el límite
+// actual; con asOf es el vigente a esa fecha según credit_limit_history, o el actual si no hay historial.
+func creditLimitAt(asOf *time.Time, idColumn, limitColumn string, card bool) (string, []interface{}) {
+	if asOf == nil {
+		return "COALESCE(" + limitColumn + ", 0)", nil
+	}
+
+	target := "h.account_id = " + idColumn + " AND h.card_id IS NULL"
+	if card {
+		target = "h.card_id = " + idColumn
+	}
+	expression := `COALESCE((
+		SELECT h.credit_limit
+		FROM credit_limit_history h
+		WHERE ` + target + `
+			AND h.effective_from <= ?
+			AND (h.effective_to IS NULL OR h.effective_to > ?)
+		ORDER BY h.effective_from DESC
+		LIMIT 1
+	), ` + limitColumn + `, 0)`
+	return expression, []interface{}{*asOf, *asOf}
+}
+
+// createdUntil arma el filtro de movimientos creados hasta asOf
+func createdUntil(asOf *time.Time, column string) (string, []interface{}) {
+	if asOf == nil {
+		return "", nil
+	}
+	return "\n\t\t\tAND " + column + " <= ?", []interface{}{*asOf}
+}
+
 // GetExpenseIncomeReport obtiene el reporte de gastos vs ingresos
 func (r *ReportRepository) GetExpenseIncomeReport(ctx context.Context, userID string, startDate, endDate time.Time) (*dto.ExpenseIncomeReportResponse, error) {
 	response := &dto.ExpenseIncomeReportResponse{
@@ -275,21 +313,27 @@ func (r *ReportRepository) GetExpenseIncomeReport(ctx context.Context, userID st
 	}
 	response.ByPeriod = byPeriod
 
-	// Query para gastos/ingresos por categoría (tipo de transacción)
+	// Query para gastos/ingresos por categoría. Las subcategorías se agrupan en su categoría
+	// principal y las transacciones sin categoría quedan en "Sin categoría". Las transacciones
+	// divididas aportan cada parte a su categoría, sin contar las partes de otras personas
 	byCategoryQuery := `
 		SELECT 
-			type,
+			COALESCE(p.name, c.name, 'Sin categoría') as category,
 			CASE 
-				WHEN type IN ('wallet_deposit', 'account_deposit', 'credit_payment', 'debit_refund', 'credit_refund') 
+				WHEN t.type IN ('wallet_deposit', 'account_deposit', 'credit_payment', 'debit_refund', 'credit_refund') 
 				THEN 'income' ELSE 'expense' 
 			END as category_type,
-			COUNT(*) as count,
-			COALESCE(SUM(amount), 0) as amount
-		FROM transactions
-		WHERE user_id = ? 
-			AND created_at BETWEEN ? AND ?
-			AND status = 'completed'
-		GROUP BY type
+			COUNT(DISTINCT t.id) as count,
+			COALESCE(SUM(COALESCE(a.amount, t.amount)), 0) as amount
+		FROM transactions t
+		LEFT JOIN transaction_allocations a ON a.transaction_id = t.id
+		LEFT JOIN transaction_categories c ON c.id = COALESCE(a.category_id, t.category_id)
+		LEFT JOIN transaction_categories p ON p.id = c.parent_id
+		WHERE t.user_id = ? 
+			AND t.created_at BETWEEN ? AND ?
+			AND t.status = 'completed'
+			AND a.counterpart_user_id IS NULL
+		GROUP BY category, category_type
 		ORDER BY amount DESC
 	`
 
@@ -370,9 +414,148 @@ func (r *ReportRepository) GetExpenseIncomeReport(ctx context.Context, userID st
 
 	response.Trend = trend
 
+	subscriptions, err := r.getSubscriptionsSummary(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	response.Subscriptions = *subscriptions
+
+	budgets, err := r.getBudgetsSummary(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	response.Budgets = *budgets
+
 	return response, nil
 }
 
+// getBudgetsSummary obtiene los presupuestos de los meses del período contra el gasto real, tal como
+// los calculó transaction-service (tabla budget_periods)
+func (r *ReportRepository) getBudgetsSummary(ctx context.Context, userID string, startDate, endDate time.Time) (*dto.BudgetsSummary, error) {
+	query := `
+		SELECT b.id, b.name, b.scope,
+			CASE b.scope
+				WHEN 'category' THEN COALESCE(tc.name, '')
+				WHEN 'merchant' THEN COALESCE(b.merchant_name, '')
+				ELSE COALESCE(CONCAT(UPPER(cd.card_brand), ' ', cd.last_four_digits), '')
+			END AS target,
+			bp.month, b.currency, bp.limit_amount, bp.carried_over, bp.spent, bp.installments,
+			bp.actual, bp.remaining, bp.percent_used, bp.status
+		FROM budget_periods bp
+		JOIN budgets b ON b.id = bp.budget_id
+		LEFT JOIN transaction_categories tc ON tc.id = b.category_id
+		LEFT JOIN cards cd ON cd.id = b.card_id
+		WHERE bp.user_id = ?
+			AND bp.month BETWEEN ? AND ?
+		ORDER BY bp.month ASC, b.name ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, startDate.Format("2006-01"), endDate.Format("2006-01"))
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo presupuestos: %w", err)
+	}
+	defer rows.Close()
+
+	summary := &dto.BudgetsSummary{
+		Items:       []dto.BudgetItem{},
+		TotalLimit:  map[string]float64{},
+		TotalActual: map[string]float64{},
+	}
+	for rows.Next() {
+		var item dto.BudgetItem
+		err := rows.Scan(&item.BudgetID, &item.Name, &item.Scope, &item.Target, &item.Month, &item.Currency,
+			&item.Limit, &item.CarriedOver, &item.Spent, &item.Installments, &item.Actual, &item.Remaining,
+			&item.PercentUsed, &item.Status)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando presupuesto: %w", err)
+		}
+
+		switch item.Status {
+		case "on_track":
+			summary.OnTrackCount++
+		case "warning":
+			summary.WarningCount++
+		case "exceeded":
+			summary.ExceededCount++
+		}
+		summary.TotalLimit[item.Currency] += item.Limit
+		summary.TotalActual[item.Currency] += item.Actual
+
+		summary.Items = append(summary.Items, item)
+	}
+
+	for currency := range summary.TotalLimit {
+		summary.TotalLimit[currency] = math.Round(summary.TotalLimit[currency]*100) / 100
+		summary.TotalActual[currency] = math.Round(summary.TotalActual[currency]*100) / 100
+	}
+
+	return summary, rows.Err()
+}
+
+// getSubscriptionsSummary obtiene las suscripciones detectadas por transaction-service que cobraron
+// en el período o después; los costos solo suman las que siguen cobrando
+func (r *ReportRepository) getSubscriptionsSummary(ctx context.Context, userID string, startDate, endDate time.Time) (*dto.SubscriptionsSummary, error) {
+	query := `
+		SELECT merchant_name, period, amount, currency, annualized_cost, status,
+			last_charge_at, next_expected_at, previous_amount, price_changed_at
+		FROM detected_subscriptions
+		WHERE user_id = ?
+			AND first_charge_at <= ?
+			AND last_charge_at >= ?
+		ORDER BY status = 'cancelled', annualized_cost DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, endDate, startDate)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo suscripciones: %w", err)
+	}
+	defer rows.Close()
+
+	summary := &dto.SubscriptionsSummary{
+		Items:          []dto.SubscriptionItem{},
+		MonthlyCost:    map[string]float64{},
+		AnnualizedCost: map[string]float64{},
+	}
+	for rows.Next() {
+		var item dto.SubscriptionItem
+		var previousAmount sql.NullFloat64
+		var priceChangedAt sql.NullTime
+		err := rows.Scan(&item.MerchantName, &item.Period, &item.Amount, &item.Currency, &item.AnnualizedCost,
+			&item.Status, &item.LastChargeAt, &item.NextExpectedAt, &previousAmount, &priceChangedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando suscripción: %w", err)
+		}
+
+		if previousAmount.Valid && priceChangedAt.Valid {
+			item.PreviousAmount = &previousAmount.Float64
+			item.PriceChangedAt = &priceChangedAt.Time
+			if item.Amount > previousAmount.Float64 && !priceChangedAt.Time.Before(startDate) && !priceChangedAt.Time.After(endDate) {
+				summary.PriceIncreases++
+			}
+		}
+
+		switch item.Status {
+		case "active":
+			summary.ActiveCount++
+		case "missed":
+			summary.MissedCount++
+		case "cancelled":
+			summary.CancelledCount++
+		}
+		if item.Status != "cancelled" {
+			summary.AnnualizedCost[item.Currency] += item.AnnualizedCost
+		}
+
+		summary.Items = append(summary.Items, item)
+	}
+
+	for currency, annualized := range summary.AnnualizedCost {
+		summary.MonthlyCost[currency] = math.Round(annualized/12*100) / 100
+	}
+
+	return summary, nil
+}
+
 // GetNotificationReport obtiene el reporte de notificaciones
 func (r *ReportRepository) GetNotificationReport(ctx context.Context, startDate, endDate time.Time) (*dto.NotificationReportResponse, error) {
 	response := &dto.NotificationReportResponse{
@@ -0,0 +1,250 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/fintrack/report-service/internal/core/domain/dto"
+)
+
+// GetCashFlowInputs obtiene lo que alimenta la proyección de flujo de caja: las cuentas de dinero con
+// sus promedios históricos, las transacciones programadas activas, las cuotas pendientes que vencen
+// antes de until y las tarjetas de crédito con su próximo vencimiento. Los promedios no cuentan las
+// transacciones que generaron las programaciones ni los pagos de cuotas y tarjetas, que se proyectan
+// aparte.
+func (r *ReportRepository) GetCashFlowInputs(ctx context.Context, userID string, until time.Time, historyDays int) (*dto.CashFlowInputs, error) {
+	inputs := &dto.CashFlowInputs{}
+	historyStart := time.Now().AddDate(0, 0, -historyDays)
+
+	// Cuentas de dinero (las cuentas de crédito se pagan a través de sus tarjetas)
+	accountsQuery := `
+		SELECT id, name, account_type, currency, balance
+		FROM accounts
+		WHERE BINARY user_id = BINARY ? AND is_active = 1 AND deleted_at IS NULL AND account_type <> 'credit'
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, accountsQuery, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo cuentas: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var account dto.ForecastAccount
+		if err := rows.Scan(&account.ID, &account.Name, &account.AccountType, &account.Currency, &account.Balance); err != nil {
+			return nil, fmt.Errorf("error escaneando cuenta: %w", err)
+		}
+		inputs.Accounts = append(inputs.Accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo cuentas: %w", err)
+	}
+
+	// Ingresos y egresos históricos por cuenta. Los débitos con tarjeta de débito salen de la cuenta
+	// de la tarjeta
+	historyQuery := `
+		SELECT a.id, 'income' as flow, COALESCE(SUM(t.amount), 0) as amount
+		FROM transactions t
+		JOIN accounts a ON BINARY a.id = BINARY t.to_account_id
+		WHERE BINARY a.user_id = BINARY ?
+			AND t.status = 'completed'
+			AND t.created_at >= ?
+			AND t.type IN ('account_deposit', 'wallet_deposit', 'account_transfer', 'wallet_transfer', 'debit_refund')
+			AND NOT EXISTS (SELECT 1 FROM scheduled_transaction_runs sr WHERE sr.transaction_id = t.id)
+		GROUP BY a.id
+		UNION ALL
+		SELECT a.id, 'expense' as flow, COALESCE(SUM(t.amount), 0) as amount
+		FROM transactions t
+		LEFT JOIN cards c ON BINARY c.id = BINARY t.from_card_id AND c.card_type = 'debit'
+		JOIN accounts a ON BINARY a.id = BINARY COALESCE(t.from_account_id, c.account_id)
+		WHERE BINARY a.user_id = BINARY ?
+			AND t.status = 'completed'
+			AND t.created_at >= ?
+			AND t.type IN ('account_withdraw', 'wallet_withdrawal', 'debit_purchase', 'account_transfer', 'wallet_transfer')
+			AND NOT EXISTS (SELECT 1 FROM scheduled_transaction_runs sr WHERE sr.transaction_id = t.id)
+		GROUP BY a.id
+	`
+
+	historyRows, err := r.db.QueryContext(ctx, historyQuery, userID, historyStart, userID, historyStart)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo promedios históricos: %w", err)
+	}
+	defer historyRows.Close()
+
+	for historyRows.Next() {
+		var accountID, flow string
+		var amount float64
+		if err := historyRows.Scan(&accountID, &flow, &amount); err != nil {
+			return nil, fmt.Errorf("error escaneando promedio histórico: %w", err)
+		}
+		for i := range inputs.Accounts {
+			if inputs.Accounts[i].ID != accountID {
+				continue
+			}
+			if flow == "income" {
+				inputs.Accounts[i].AvgDailyIncome = amount / float64(historyDays)
+			} else {
+				inputs.Accounts[i].AvgDailyExpense = amount / float64(historyDays)
+			}
+		}
+	}
+	if err := historyRows.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo promedios históricos: %w", err)
+	}
+
+	// Transacciones programadas cuya próxima ejecución cae antes del final del horizonte
+	schedulesQuery := `
+		SELECT
+			s.id, s.type, s.amount, s.next_amount,
+			COALESCE(s.description, s.merchant_name, '') as description,
+			COALESCE(s.from_account_id, c.account_id, '') as from_account_id,
+			COALESCE(s.to_account_id, '') as to_account_id,
+			COALESCE(s.recurrence, '') as recurrence,
+			s.start_at, s.next_occurrence_at,
+			COALESCE(s.next_run_at, s.next_occurrence_at) as next_run_at,
+			s.occurrence_count
+		FROM scheduled_transactions s
+		LEFT JOIN cards c ON BINARY c.id = BINARY s.from_card_id AND c.card_type = 'debit'
+		WHERE BINARY s.user_id = BINARY ?
+			AND s.status = 'active'
+			AND s.next_occurrence_at IS NOT NULL
+			AND COALESCE(s.next_run_at, s.next_occurrence_at) < ?
+		ORDER BY next_run_at ASC
+	`
+
+	scheduleRows, err := r.db.QueryContext(ctx, schedulesQuery, userID, until)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo transacciones programadas: %w", err)
+	}
+	defer scheduleRows.Close()
+
+	for scheduleRows.Next() {
+		var schedule dto.ForecastSchedule
+		var nextAmount sql.NullFloat64
+		err := scheduleRows.Scan(
+			&schedule.ID, &schedule.Type, &schedule.Amount, &nextAmount, &schedule.Description,
+			&schedule.FromAccountID, &schedule.ToAccountID, &schedule.Recurrence,
+			&schedule.StartAt, &schedule.NextOccurrenceAt, &schedule.NextRunAt, &schedule.OccurrenceCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando transacción programada: %w", err)
+		}
+		if nextAmount.Valid {
+			schedule.NextAmount = &nextAmount.Float64
+		}
+		inputs.Schedules = append(inputs.Schedules, schedule)
+	}
+	if err := scheduleRows.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo transacciones programadas: %w", err)
+	}
+
+	// Cuotas pendientes, vencidas o con pago parcial que vencen antes del final del horizonte
+	installmentsQuery := `
+		SELECT
+			i.id, ip.card_id, a.currency,
+			COALESCE(ip.description, ip.merchant_name, '') as description,
+			i.installment_number,
+			CASE WHEN i.status = 'partial' THEN i.remaining_amount ELSE i.amount END as amount,
+			i.due_date
+		FROM installments i
+		JOIN installment_plans ip ON BINARY i.plan_id = BINARY ip.id
+		JOIN cards c ON BINARY c.id = BINARY ip.card_id
+		JOIN accounts a ON BINARY a.id = BINARY c.account_id
+		WHERE BINARY ip.user_id = BINARY ?
+			AND ip.status = 'active'
+			AND i.status IN ('pending', 'overdue', 'partial')
+			AND i.due_date < ?
+		ORDER BY i.due_date ASC, i.installment_number ASC
+	`
+
+	installmentRows, err := r.db.QueryContext(ctx, installmentsQuery, userID, until)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo cuotas pendientes: %w", err)
+	}
+	defer installmentRows.Close()
+
+	for installmentRows.Next() {
+		var installment dto.ForecastInstallment
+		err := installmentRows.Scan(
+			&installment.ID, &installment.CardID, &installment.Currency, &installment.Description,
+			&installment.Number, &installment.Amount, &installment.DueDate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando cuota: %w", err)
+		}
+		inputs.Installments = append(inputs.Installments, installment)
+	}
+	if err := installmentRows.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo cuotas: %w", err)
+	}
+
+	// Tarjetas de crédito con vencimiento. El saldo de la tarjeta incluye el total de las compras en
+	// cuotas hasta que el plan termina, así que lo que resta de los planes activos se descuenta: esas
+	// cuotas ya se proyectan una por una
+	cardsQuery := `
+		SELECT
+			c.id,
+			COALESCE(c.nickname, CONCAT(c.card_brand, ' *', c.last_four_digits)) as name,
+			a.currency,
+			c.balance - COALESCE((
+				SELECT SUM(ip.remaining_amount)
+				FROM installment_plans ip
+				WHERE BINARY ip.card_id = BINARY c.id AND ip.status = 'active'
+			), 0) as debt,
+			c.due_date,
+			COALESCE((
+				SELECT SUM(t.amount)
+				FROM transactions t
+				WHERE BINARY t.from_card_id = BINARY c.id
+					AND t.type = 'credit_charge'
+					AND t.status = 'completed'
+					AND t.created_at >= ?
+					AND NOT EXISTS (SELECT 1 FROM installment_plans ip WHERE ip.transaction_id = t.id)
+			), 0) as charges,
+			COALESCE((
+				SELECT t.from_account_id
+				FROM transactions t
+				WHERE BINARY t.to_card_id = BINARY c.id
+					AND t.type = 'credit_payment'
+					AND t.status = 'completed'
+					AND t.from_account_id IS NOT NULL
+				ORDER BY t.created_at DESC
+				LIMIT 1
+			), '') as payment_account_id
+		FROM cards c
+		JOIN accounts a ON BINARY c.account_id = BINARY a.id
+		WHERE BINARY a.user_id = BINARY ?
+			AND c.card_type = 'credit'
+			AND c.status = 'active'
+			AND c.deleted_at IS NULL
+			AND c.due_date IS NOT NULL
+		ORDER BY c.due_date ASC
+	`
+
+	cardRows, err := r.db.QueryContext(ctx, cardsQuery, historyStart, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo tarjetas: %w", err)
+	}
+	defer cardRows.Close()
+
+	for cardRows.Next() {
+		var card dto.ForecastCard
+		var charges float64
+		err := cardRows.Scan(
+			&card.ID, &card.Name, &card.Currency, &card.Debt, &card.DueDate, &charges, &card.PaymentAccountID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando tarjeta: %w", err)
+		}
+		card.AvgMonthlyCharges = charges / float64(historyDays) * 30
+		inputs.Cards = append(inputs.Cards, card)
+	}
+	if err := cardRows.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo tarjetas: %w", err)
+	}
+
+	return inputs, nil
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fintrack/report-service/internal/core/domain/dto"
@@ -118,6 +121,7 @@ func (h *ReportHandler) GetInstallmentReport(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param user_id query string true "ID del usuario"
+// @Param as_of query string false "Fecha (YYYY-MM-DD) de los límites de crédito y consumos a mostrar"
 // @Success 200 {object} dto.AccountReportResponse
 // @Router /api/v1/reports/accounts [get]
 func (h *ReportHandler) GetAccountReport(c *gin.Context) {
@@ -127,8 +131,15 @@ func (h *ReportHandler) GetAccountReport(c *gin.Context) {
 		return
 	}
 
+	asOf, err := parseAsOf(c.Query("as_of"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de as_of inválido (usar YYYY-MM-DD)"})
+		return
+	}
+
 	req := &dto.AccountReportRequest{
 		UserID: userID,
+		AsOf:   asOf,
 	}
 
 	report, err := h.reportService.GetAccountReport(c.Request.Context(), req)
@@ -287,6 +298,7 @@ func (h *ReportHandler) GetInstallmentReportPDF(c *gin.Context) {
 // @Tags reports
 // @Produce application/pdf
 // @Param user_id query string true "ID del usuario"
+// @Param as_of query string false "Fecha (YYYY-MM-DD) de los límites de crédito y consumos a mostrar"
 // @Success 200 {file} binary
 // @Router /api/v1/reports/accounts/pdf [get]
 func (h *ReportHandler) GetAccountReportPDF(c *gin.Context) {
@@ -296,8 +308,15 @@ func (h *ReportHandler) GetAccountReportPDF(c *gin.Context) {
 		return
 	}
 
+	asOf, err := parseAsOf(c.Query("as_of"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de as_of inválido (usar YYYY-MM-DD)"})
+		return
+	}
+
 	req := &dto.AccountReportRequest{
 		UserID: userID,
+		AsOf:   asOf,
 	}
 
 	report, err := h.reportService.GetAccountReport(c.Request.Context(), req)
@@ -449,3 +468,113 @@ func (h *ReportHandler) GetExpenseIncomeReportPDF(c *gin.Context) {
 	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
 	c.Data(http.StatusOK, "application/pdf", pdfBytes)
 }
+
+// GetCashFlowForecast obtiene la proyección de flujo de caja
+// @Summary Obtener proyección de flujo de caja
+// @Description Proyecta el saldo diario de cada cuenta con las transacciones programadas, las cuotas, los vencimientos de tarjeta y los promedios históricos, y marca los días en negativo
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param user_id query string true "ID del usuario"
+// @Param days query int false "Horizonte en días (30, 60 o 90)"
+// @Param account_id query string false "ID de una cuenta"
+// @Success 200 {object} dto.CashFlowForecastResponse
+// @Router /api/v1/reports/forecast [get]
+func (h *ReportHandler) GetCashFlowForecast(c *gin.Context) {
+	req, ok := parseForecastRequest(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetCashFlowForecast(c.Request.Context(), req)
+	if err != nil {
+		log.Printf("❌ Error en GetCashFlowForecast: %v", err)
+		c.JSON(forecastErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetCashFlowForecastPDF obtiene la proyección de flujo de caja en PDF
+// @Summary Obtener proyección de flujo de caja en PDF
+// @Description Genera y descarga un PDF de la proyección de flujo de caja
+// @Tags reports
+// @Produce application/pdf
+// @Param user_id query string true "ID del usuario"
+// @Param days query int false "Horizonte en días (30, 60 o 90)"
+// @Param account_id query string false "ID de una cuenta"
+// @Success 200 {file} binary
+// @Router /api/v1/reports/forecast/pdf [get]
+func (h *ReportHandler) GetCashFlowForecastPDF(c *gin.Context) {
+	req, ok := parseForecastRequest(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetCashFlowForecast(c.Request.Context(), req)
+	if err != nil {
+		log.Printf("❌ Error en GetCashFlowForecast (PDF): %v", err)
+		c.JSON(forecastErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	pdfBytes, err := pdf.GenerateCashFlowForecastPDF(report)
+	if err != nil {
+		log.Printf("❌ Error generando PDF de proyección de flujo de caja: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generando PDF"})
+		return
+	}
+
+	filename := fmt.Sprintf("proyeccion-flujo-caja-%s.pdf", time.Now().Format("2006-01-02"))
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
+
+// parseForecastRequest lee los parámetros de la proyección de flujo de caja; si son inválidos responde
+// el error y devuelve false
+func parseForecastRequest(c *gin.Context) (*dto.CashFlowForecastRequest, bool) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id es requerido"})
+		return nil, false
+	}
+
+	days := service.DefaultForecastDays
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || !slices.Contains(service.ForecastHorizons, parsed) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days debe ser 30, 60 o 90"})
+			return nil, false
+		}
+		days = parsed
+	}
+
+	return &dto.CashFlowForecastRequest{
+		UserID:    userID,
+		Days:      days,
+		AccountID: c.Query("account_id"),
+	}, true
+}
+
+// forecastErrorStatus devuelve 404 cuando la cuenta pedida no es del usuario
+func forecastErrorStatus(err error) int {
+	if strings.Contains(err.Error(), "no encontrada") {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// parseAsOf interpreta la fecha de corte de un reporte como el final de ese día
+func parseAsOf(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	asOf := date.Add(24*time.Hour - time.Nanosecond)
+	return &asOf, nil
+}
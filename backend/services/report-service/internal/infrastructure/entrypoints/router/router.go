@@ -40,12 +40,14 @@ func SetupRouter(cfg *config.Config, reportService service.ReportService) *gin.E
 			reports.GET("/accounts", reportHandler.GetAccountReport)
 			reports.GET("/expenses-income", reportHandler.GetExpenseIncomeReport)
 			reports.GET("/notifications", reportHandler.GetNotificationReport)
+			reports.GET("/forecast", reportHandler.GetCashFlowForecast)
 
 			// Reportes PDF
 			reports.GET("/transactions/pdf", reportHandler.GetTransactionReportPDF)
 			reports.GET("/installments/pdf", reportHandler.GetInstallmentReportPDF)
 			reports.GET("/accounts/pdf", reportHandler.GetAccountReportPDF)
 			reports.GET("/expenses-income/pdf", reportHandler.GetExpenseIncomeReportPDF)
+			reports.GET("/forecast/pdf", reportHandler.GetCashFlowForecastPDF)
 		}
 	}
 
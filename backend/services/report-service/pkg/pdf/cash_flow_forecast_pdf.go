@@ -0,0 +1,122 @@
+package pdf
+
+import (
+	"fmt"
+
+	"github.com/fintrack/report-service/internal/core/domain/dto"
+)
+
+// GenerateCashFlowForecastPDF genera el PDF de la proyección de flujo de caja
+func GenerateCashFlowForecastPDF(report *dto.CashFlowForecastResponse) ([]byte, error) {
+	gen := NewGenerator()
+	gen.AddFooter()
+
+	// Encabezado
+	subtitle := fmt.Sprintf("Próximos %d días, del %s al %s",
+		report.Days,
+		FormatDate(report.StartDate),
+		FormatDate(report.EndDate))
+	gen.AddHeader("Proyección de Flujo de Caja", subtitle)
+
+	// Resumen
+	gen.AddSection("Resumen General")
+	summaryData := map[string]string{
+		"Cuentas":            fmt.Sprintf("%d", report.Summary.TotalAccounts),
+		"Cuentas en Riesgo":  fmt.Sprintf("%d", report.Summary.AccountsAtRisk),
+		"Saldo Actual":       FormatCurrency(report.Summary.CurrentBalance, "ARS"),
+		"Saldo Proyectado":   FormatCurrency(report.Summary.ProjectedBalance, "ARS"),
+		"Ingresos Previstos": FormatCurrency(report.Summary.ProjectedIncome, "ARS"),
+		"Egresos Previstos":  FormatCurrency(report.Summary.ProjectedExpenses, "ARS"),
+	}
+	if report.Summary.FirstNegativeDate != nil {
+		summaryData["Primer Saldo Negativo"] = FormatDate(*report.Summary.FirstNegativeDate)
+	}
+	gen.AddSummaryBox(summaryData)
+
+	for _, account := range report.Accounts {
+		gen.AddSection(fmt.Sprintf("%s (%s)", account.Name, account.Currency))
+
+		gen.AddKeyValue("Saldo Actual", FormatCurrency(account.CurrentBalance, account.Currency))
+		gen.AddKeyValue("Saldo Proyectado", FormatCurrency(account.ProjectedBalance, account.Currency))
+		gen.AddKeyValue("Saldo Mínimo", fmt.Sprintf("%s el %s",
+			FormatCurrency(account.LowestBalance, account.Currency),
+			FormatDate(account.LowestBalanceDate)))
+		gen.AddKeyValue("Promedio Diario", fmt.Sprintf("+%s / -%s",
+			FormatCurrency(account.AvgDailyIncome, account.Currency),
+			FormatCurrency(account.AvgDailyExpense, account.Currency)))
+		if account.FirstNegativeDate != nil {
+			gen.AddKeyValue("Saldo Negativo", fmt.Sprintf("%d días desde el %s",
+				account.NegativeDays, FormatDate(*account.FirstNegativeDate)))
+		}
+		gen.pdf.Ln(4)
+
+		// Movimientos conocidos
+		if len(account.Events) > 0 {
+			headers := []string{"Fecha", "Origen", "Descripción", "Monto"}
+			widths := []float64{25, 30, 80, 35}
+
+			var tableData [][]string
+			for _, event := range account.Events {
+				description := event.Description
+				if len(description) > 45 {
+					description = description[:42] + "..."
+				}
+
+				row := []string{
+					FormatDate(event.Date),
+					translateForecastSource(event.Source),
+					description,
+					FormatCurrency(event.Amount, account.Currency),
+				}
+				tableData = append(tableData, row)
+			}
+
+			gen.AddTable(headers, widths, tableData)
+		}
+
+		// Saldo diario: los días con movimientos conocidos, los días en negativo y el último día
+		moves := make(map[string]bool, len(account.Events))
+		for _, event := range account.Events {
+			moves[event.Date.Format("2006-01-02")] = true
+		}
+
+		headers := []string{"Fecha", "Ingresos", "Egresos", "Saldo", "Alerta"}
+		widths := []float64{25, 40, 40, 40, 25}
+
+		var tableData [][]string
+		for i, day := range account.Daily {
+			if !moves[day.Date.Format("2006-01-02")] && !day.Negative && i != len(account.Daily)-1 {
+				continue
+			}
+			alert := ""
+			if day.Negative {
+				alert = "Negativo"
+			}
+			row := []string{
+				FormatDate(day.Date),
+				FormatCurrency(day.Income, account.Currency),
+				FormatCurrency(day.Expenses, account.Currency),
+				FormatCurrency(day.Balance, account.Currency),
+				alert,
+			}
+			tableData = append(tableData, row)
+		}
+
+		gen.AddTable(headers, widths, tableData)
+	}
+
+	return gen.Output()
+}
+
+func translateForecastSource(source string) string {
+	switch source {
+	case "scheduled":
+		return "Programada"
+	case "installment":
+		return "Cuota"
+	case "card_payment":
+		return "Tarjeta"
+	default:
+		return source
+	}
+}
@@ -123,9 +123,124 @@ func GenerateExpenseIncomeReportPDF(report *dto.ExpenseIncomeReportResponse) ([]
 
 	gen.AddSummaryBox(trendData)
 
+	// Suscripciones detectadas
+	if len(report.Subscriptions.Items) > 0 {
+		gen.AddSection("Suscripciones")
+
+		headers := []string{"Comercio", "Frecuencia", "Monto", "Costo Anual", "Estado"}
+		widths := []float64{50, 25, 35, 35, 25}
+
+		var tableData [][]string
+		for _, sub := range report.Subscriptions.Items {
+			amount := FormatCurrency(sub.Amount, sub.Currency)
+			if sub.PreviousAmount != nil && sub.Amount > *sub.PreviousAmount {
+				amount += " ↑"
+			}
+			row := []string{
+				sub.MerchantName,
+				translateSubscriptionPeriod(sub.Period),
+				amount,
+				FormatCurrency(sub.AnnualizedCost, sub.Currency),
+				translateSubscriptionStatus(sub.Status),
+			}
+			tableData = append(tableData, row)
+		}
+
+		gen.AddTable(headers, widths, tableData)
+
+		subscriptionData := map[string]string{
+			"Activas":            fmt.Sprintf("%d", report.Subscriptions.ActiveCount),
+			"Sin Cobro":          fmt.Sprintf("%d", report.Subscriptions.MissedCount),
+			"Canceladas":         fmt.Sprintf("%d", report.Subscriptions.CancelledCount),
+			"Aumentos de Precio": fmt.Sprintf("%d", report.Subscriptions.PriceIncreases),
+		}
+		for currency, annualized := range report.Subscriptions.AnnualizedCost {
+			subscriptionData["Costo Mensual "+currency] = FormatCurrency(report.Subscriptions.MonthlyCost[currency], currency)
+			subscriptionData["Costo Anual "+currency] = FormatCurrency(annualized, currency)
+		}
+		gen.AddSummaryBox(subscriptionData)
+	}
+
+	// Presupuestos contra gasto real
+	if len(report.Budgets.Items) > 0 {
+		gen.AddSection("Presupuestos")
+
+		headers := []string{"Presupuesto", "Mes", "Límite", "Gastado", "Uso", "Estado"}
+		widths := []float64{45, 20, 35, 35, 15, 20}
+
+		var tableData [][]string
+		for _, budget := range report.Budgets.Items {
+			row := []string{
+				budget.Name,
+				budget.Month,
+				FormatCurrency(budget.Limit, budget.Currency),
+				FormatCurrency(budget.Actual, budget.Currency),
+				fmt.Sprintf("%.0f%%", budget.PercentUsed),
+				translateBudgetStatus(budget.Status),
+			}
+			tableData = append(tableData, row)
+		}
+
+		gen.AddTable(headers, widths, tableData)
+
+		budgetData := map[string]string{
+			"En Camino": fmt.Sprintf("%d", report.Budgets.OnTrackCount),
+			"En Alerta": fmt.Sprintf("%d", report.Budgets.WarningCount),
+			"Excedidos": fmt.Sprintf("%d", report.Budgets.ExceededCount),
+		}
+		for currency, limit := range report.Budgets.TotalLimit {
+			budgetData["Límite Total "+currency] = FormatCurrency(limit, currency)
+			budgetData["Gastado Total "+currency] = FormatCurrency(report.Budgets.TotalActual[currency], currency)
+		}
+		gen.AddSummaryBox(budgetData)
+	}
+
 	return gen.Output()
 }
 
+func translateBudgetStatus(status string) string {
+	switch status {
+	case "on_track":
+		return "En camino"
+	case "warning":
+		return "En alerta"
+	case "exceeded":
+		return "Excedido"
+	default:
+		return status
+	}
+}
+
+func translateSubscriptionPeriod(period string) string {
+	switch period {
+	case "weekly":
+		return "Semanal"
+	case "biweekly":
+		return "Quincenal"
+	case "monthly":
+		return "Mensual"
+	case "quarterly":
+		return "Trimestral"
+	case "yearly":
+		return "Anual"
+	default:
+		return period
+	}
+}
+
+func translateSubscriptionStatus(status string) string {
+	switch status {
+	case "active":
+		return "Activa"
+	case "missed":
+		return "Sin cobro"
+	case "cancelled":
+		return "Cancelada"
+	default:
+		return status
+	}
+}
+
 func translateTrend(trend string) string {
 	switch trend {
 	case "increasing":
@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/fintrack/transaction-service/internal/infrastructure/entrypoints/router"
+	"github.com/fintrack/transaction-service/internal/infrastructure/jobs"
 	_ "github.com/go-sql-driver/mysql"
 )
 
@@ -20,6 +22,26 @@ type Config struct {
 	DBUser     string
 	DBPassword string
 	DBName     string
+
+	// Scheduler of recurring and future-dated transactions
+	SchedulerEnabled  bool
+	SchedulerInterval time.Duration
+
+	// Refresh of the subscriptions detected from the transaction history
+	SubscriptionRefreshEnabled  bool
+	SubscriptionRefreshInterval time.Duration
+
+	// Cleanup of the attachments of purged transactions and installment plans
+	AttachmentCleanupEnabled  bool
+	AttachmentCleanupInterval time.Duration
+
+	// Expiry of the transfers between users nobody answered in time
+	P2PExpiryEnabled  bool
+	P2PExpiryInterval time.Duration
+
+	// Refresh of the monthly budgets against actual spending and their threshold alerts
+	BudgetRefreshEnabled  bool
+	BudgetRefreshInterval time.Duration
 }
 
 // LoadConfig loads configuration from environment variables
@@ -31,6 +53,21 @@ func LoadConfig() *Config {
 		DBUser:     "root",
 		DBPassword: "password",
 		DBName:     "fintrack",
+
+		SchedulerEnabled:  true,
+		SchedulerInterval: 60 * time.Second,
+
+		SubscriptionRefreshEnabled:  true,
+		SubscriptionRefreshInterval: 24 * time.Hour,
+
+		AttachmentCleanupEnabled:  true,
+		AttachmentCleanupInterval: 6 * time.Hour,
+
+		P2PExpiryEnabled:  true,
+		P2PExpiryInterval: 15 * time.Minute,
+
+		BudgetRefreshEnabled:  true,
+		BudgetRefreshInterval: time.Hour,
 	}
 
 	// Load from environment variables
@@ -67,6 +104,66 @@ func LoadConfig() *Config {
 		config.DBName = dbName
 	}
 
+	if enabled := os.Getenv("SCHEDULER_ENABLED"); enabled != "" {
+		if e, err := strconv.ParseBool(enabled); err == nil {
+			config.SchedulerEnabled = e
+		}
+	}
+
+	if interval := os.Getenv("SCHEDULER_INTERVAL_SECONDS"); interval != "" {
+		if i, err := strconv.Atoi(interval); err == nil && i > 0 {
+			config.SchedulerInterval = time.Duration(i) * time.Second
+		}
+	}
+
+	if enabled := os.Getenv("SUBSCRIPTION_REFRESH_ENABLED"); enabled != "" {
+		if e, err := strconv.ParseBool(enabled); err == nil {
+			config.SubscriptionRefreshEnabled = e
+		}
+	}
+
+	if interval := os.Getenv("SUBSCRIPTION_REFRESH_HOURS"); interval != "" {
+		if i, err := strconv.Atoi(interval); err == nil && i > 0 {
+			config.SubscriptionRefreshInterval = time.Duration(i) * time.Hour
+		}
+	}
+
+	if enabled := os.Getenv("ATTACHMENT_CLEANUP_ENABLED"); enabled != "" {
+		if e, err := strconv.ParseBool(enabled); err == nil {
+			config.AttachmentCleanupEnabled = e
+		}
+	}
+
+	if interval := os.Getenv("ATTACHMENT_CLEANUP_HOURS"); interval != "" {
+		if i, err := strconv.Atoi(interval); err == nil && i > 0 {
+			config.AttachmentCleanupInterval = time.Duration(i) * time.Hour
+		}
+	}
+
+	if enabled := os.Getenv("P2P_EXPIRY_ENABLED"); enabled != "" {
+		if e, err := strconv.ParseBool(enabled); err == nil {
+			config.P2PExpiryEnabled = e
+		}
+	}
+
+	if interval := os.Getenv("P2P_EXPIRY_MINUTES"); interval != "" {
+		if i, err := strconv.Atoi(interval); err == nil && i > 0 {
+			config.P2PExpiryInterval = time.Duration(i) * time.Minute
+		}
+	}
+
+	if enabled := os.Getenv("BUDGET_REFRESH_ENABLED"); enabled != "" {
+		if e, err := strconv.ParseBool(enabled); err == nil {
+			config.BudgetRefreshEnabled = e
+		}
+	}
+
+	if interval := os.Getenv("BUDGET_REFRESH_MINUTES"); interval != "" {
+		if i, err := strconv.Atoi(interval); err == nil && i > 0 {
+			config.BudgetRefreshInterval = time.Duration(i) * time.Minute
+		}
+	}
+
 	return config
 }
 
@@ -129,6 +226,46 @@ func main() {
 	appRouter := router.NewRouter(db)
 	mux := appRouter.SetupRoutes()
 
+	// Start the scheduler of recurring and future-dated transactions
+	if config.SchedulerEnabled {
+		scheduleRunner := jobs.NewScheduleRunner(appRouter.ScheduleService(), config.SchedulerInterval)
+		scheduleRunner.Start()
+		defer scheduleRunner.Stop()
+		log.Printf("Transaction scheduler started (every %s)", config.SchedulerInterval)
+	}
+
+	// Start the refresher of detected subscriptions used by reports and the chatbot
+	if config.SubscriptionRefreshEnabled {
+		subscriptionRefresher := jobs.NewSubscriptionRefresher(appRouter.SubscriptionService(), config.SubscriptionRefreshInterval)
+		subscriptionRefresher.Start()
+		defer subscriptionRefresher.Stop()
+		log.Printf("Subscription refresher started (every %s)", config.SubscriptionRefreshInterval)
+	}
+
+	// Start the cleanup of attachments left behind by purged transactions and installment plans
+	if config.AttachmentCleanupEnabled {
+		attachmentCleaner := jobs.NewAttachmentCleaner(appRouter.AttachmentService(), config.AttachmentCleanupInterval)
+		attachmentCleaner.Start()
+		defer attachmentCleaner.Stop()
+		log.Printf("Attachment cleaner started (every %s)", config.AttachmentCleanupInterval)
+	}
+
+	// Start the expiry of transfers between users, refunding the ones nobody accepted in time
+	if config.P2PExpiryEnabled {
+		p2pExpirer := jobs.NewP2PExpirer(appRouter.P2PService(), config.P2PExpiryInterval)
+		p2pExpirer.Start()
+		defer p2pExpirer.Stop()
+		log.Printf("Transfer expirer started (every %s)", config.P2PExpiryInterval)
+	}
+
+	// Start the refresh of budgets used by reports and the chatbot, alerting at 50%, 80% and 100%
+	if config.BudgetRefreshEnabled {
+		budgetRefresher := jobs.NewBudgetRefresher(appRouter.BudgetService(), config.BudgetRefreshInterval)
+		budgetRefresher.Start()
+		defer budgetRefresher.Stop()
+		log.Printf("Budget refresher started (every %s)", config.BudgetRefreshInterval)
+	}
+
 	// Add CORS middleware
 	handler := corsMiddleware(mux)
 
@@ -0,0 +1,105 @@
+package attachment
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// OwnerType is the kind of record an attachment belongs to
+type OwnerType string
+
+const (
+	OwnerTransaction     OwnerType = "transaction"
+	OwnerInstallmentPlan OwnerType = "installment_plan"
+)
+
+// sniffLength is how much of a file http.DetectContentType reads
+const sniffLength = 512
+
+// allowedContentTypes are the receipts and invoices accepted, by sniffed content type, with their extension
+var allowedContentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"image/gif":       ".gif",
+}
+
+// Attachment is a receipt or invoice (factura) stored for a transaction or an installment plan
+type Attachment struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"userId"` // Who uploaded it; the file counts against their quota
+	OwnerType   OwnerType `json:"ownerType"`
+	OwnerID     string    `json:"ownerId"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"` // Sniffed from the content, not the one declared by the client
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// IsValidOwnerType checks if attachments can be stored for the owner type
+func IsValidOwnerType(ownerType OwnerType) bool {
+	return ownerType == OwnerTransaction || ownerType == OwnerInstallmentPlan
+}
+
+// DetectContentType sniffs the content type from the first bytes of a file and checks that it is a
+// supported receipt format
+func DetectContentType(head []byte) (string, error) {
+	if len(head) > sniffLength {
+		head = head[:sniffLength]
+	}
+
+	contentType := http.DetectContentType(head)
+	if separator := strings.Index(contentType, ";"); separator >= 0 {
+		contentType = contentType[:separator]
+	}
+
+	if _, allowed := allowedContentTypes[contentType]; !allowed {
+		return "", fmt.Errorf("unsupported file type %s: attach a PDF or an image (JPEG, PNG, WebP or GIF)", contentType)
+	}
+	return contentType, nil
+}
+
+// SanitizeFileName keeps the base name of an uploaded file without control characters, so it is safe to
+// send back in a Content-Disposition header. Files without a usable name get one from their type.
+func SanitizeFileName(fileName string, contentType string) string {
+	name := path.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		name = "attachment" + allowedContentTypes[contentType]
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[len(runes)-255:])
+	}
+	return name
+}
+
+// StorageKey returns where the content of an attachment is stored: grouped by user, named by ID, so the
+// name given by the client never reaches the storage
+func StorageKey(userID, attachmentID, contentType string) string {
+	return fmt.Sprintf("attachments/%s/%s%s", userID, attachmentID, allowedContentTypes[contentType])
+}
+
+// Usage is how much storage a user takes with attachments
+type Usage struct {
+	UsedBytes  int64 `json:"usedBytes"`
+	QuotaBytes int64 `json:"quotaBytes"`
+	Files      int   `json:"files"`
+}
+
+// Fits checks if a file of the given size fits in the remaining quota
+func (u *Usage) Fits(size int64) bool {
+	return u.UsedBytes+size <= u.QuotaBytes
+}
@@ -0,0 +1,245 @@
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Scope is what the spending of a budget is measured on
+type Scope string
+
+const (
+	ScopeCategory Scope = "category" // A category and its subcategories
+	ScopeMerchant Scope = "merchant" // A merchant, by name
+	ScopeCard     Scope = "card"     // A card
+)
+
+// Rollover is what happens with the difference between the limit and the actual spending of a month
+type Rollover string
+
+const (
+	RolloverNone    Rollover = "none"    // Every month starts from the amount
+	RolloverUnspent Rollover = "unspent" // What was left unspent is added to the next month
+	RolloverFull    Rollover = "full"    // What was left is added and what was overspent is taken from the next month
+)
+
+// Status is where the spending of a month stands against its limit
+type Status string
+
+const (
+	StatusOnTrack  Status = "on_track" // Below the first alert threshold
+	StatusWarning  Status = "warning"  // Past an alert threshold but within the limit
+	StatusExceeded Status = "exceeded" // Past the limit
+)
+
+// Thresholds are the percentages of the limit that fire an alert, once per budget and month
+var Thresholds = []int{50, 80, 100}
+
+// MonthLayout is the format of the months of a budget
+const MonthLayout = "2006-01"
+
+// MaxRolloverMonths bounds how many previous months are carried into a month
+const MaxRolloverMonths = 12
+
+// Budget is a monthly spending plan of a user for a category, a merchant or a card. It applies to every
+// month from its start month until its end month, or with no end.
+type Budget struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"userId"`
+	Name          string    `json:"name"`
+	Scope         Scope     `json:"scope"`
+	CategoryID    *string   `json:"categoryId"`
+	MerchantName  *string   `json:"merchantName"`
+	CardID        *string   `json:"cardId"`
+	Amount        float64   `json:"amount"`
+	Currency      string    `json:"currency"`
+	Rollover      Rollover  `json:"rollover"`
+	StartMonth    string    `json:"startMonth"`
+	EndMonth      *string   `json:"endMonth"`
+	AlertsEnabled bool      `json:"alertsEnabled"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// Validate checks the scope, amount, rollover and months of the budget
+func (b *Budget) Validate() error {
+	if b.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(b.Name) > 100 {
+		return errors.New("name cannot exceed 100 characters")
+	}
+
+	switch b.Scope {
+	case ScopeCategory:
+		if b.CategoryID == nil || *b.CategoryID == "" {
+			return errors.New("categoryId is required for a category budget")
+		}
+	case ScopeMerchant:
+		if b.MerchantName == nil || *b.MerchantName == "" {
+			return errors.New("merchantName is required for a merchant budget")
+		}
+	case ScopeCard:
+		if b.CardID == nil || *b.CardID == "" {
+			return errors.New("cardId is required for a card budget")
+		}
+	default:
+		return fmt.Errorf("invalid scope: %s", b.Scope)
+	}
+
+	if b.Amount <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	if len(b.Currency) != 3 {
+		return errors.New("currency must be a 3-letter code")
+	}
+
+	switch b.Rollover {
+	case RolloverNone, RolloverUnspent, RolloverFull:
+	default:
+		return fmt.Errorf("invalid rollover: %s", b.Rollover)
+	}
+
+	if _, err := ParseMonth(b.StartMonth); err != nil {
+		return fmt.Errorf("invalid startMonth: %w", err)
+	}
+	if b.EndMonth != nil {
+		if _, err := ParseMonth(*b.EndMonth); err != nil {
+			return fmt.Errorf("invalid endMonth: %w", err)
+		}
+		if *b.EndMonth < b.StartMonth {
+			return errors.New("endMonth cannot be before startMonth")
+		}
+	}
+
+	return nil
+}
+
+// ActiveIn checks if the budget applies to a month
+func (b *Budget) ActiveIn(month string) bool {
+	if month < b.StartMonth {
+		return false
+	}
+	return b.EndMonth == nil || month <= *b.EndMonth
+}
+
+// Period is the budget against the actual spending of one month. Actual adds the transactions of the
+// month and the installments due in it; purchases in installments only count through their installments.
+type Period struct {
+	BudgetID     string    `json:"budgetId"`
+	UserID       string    `json:"userId"`
+	Month        string    `json:"month"`
+	Amount       float64   `json:"amount"`
+	CarriedOver  float64   `json:"carriedOver"` // Rolled over from the previous month, negative when it was overspent
+	Limit        float64   `json:"limit"`
+	Spent        float64   `json:"spent"`        // Transactions of the month
+	Installments float64   `json:"installments"` // Installments due in the month
+	Actual       float64   `json:"actual"`
+	Remaining    float64   `json:"remaining"`
+	PercentUsed  float64   `json:"percentUsed"`
+	Status       Status    `json:"status"`
+	Thresholds   []int     `json:"thresholdsReached"`
+	ComputedAt   time.Time `json:"computedAt"`
+}
+
+// NewPeriod computes a month of a budget from what was carried over and the actual spending
+func NewPeriod(budget *Budget, month string, carriedOver, spent, installments float64, now time.Time) *Period {
+	period := &Period{
+		BudgetID:     budget.ID,
+		UserID:       budget.UserID,
+		Month:        month,
+		Amount:       budget.Amount,
+		CarriedOver:  roundCents(carriedOver),
+		Limit:        roundCents(budget.Amount + carriedOver),
+		Spent:        roundCents(spent),
+		Installments: roundCents(installments),
+		ComputedAt:   now,
+	}
+	period.Actual = roundCents(period.Spent + period.Installments)
+	period.Remaining = roundCents(period.Limit - period.Actual)
+
+	// The thresholds compare the exact percentage, a cent short of one does not round up to it
+	var percentUsed float64
+	switch {
+	case period.Limit > 0:
+		percentUsed = period.Actual * 100 / period.Limit
+	case period.Actual > 0:
+		// Everything was overspent in previous months
+		percentUsed = 100
+	}
+	period.PercentUsed = math.Round(percentUsed*100) / 100
+
+	period.Thresholds = ReachedThresholds(percentUsed)
+	switch {
+	case percentUsed > 100:
+		period.Status = StatusExceeded
+	case len(period.Thresholds) > 0:
+		period.Status = StatusWarning
+	default:
+		period.Status = StatusOnTrack
+	}
+
+	return period
+}
+
+// CarryOver returns what a month passes on to the next one under a rollover option
+func CarryOver(rollover Rollover, period *Period) float64 {
+	switch rollover {
+	case RolloverUnspent:
+		if period.Remaining > 0 {
+			return period.Remaining
+		}
+	case RolloverFull:
+		return period.Remaining
+	}
+	return 0
+}
+
+// ReachedThresholds returns the alert thresholds a percentage of use reached
+func ReachedThresholds(percentUsed float64) []int {
+	reached := []int{}
+	for _, threshold := range Thresholds {
+		if percentUsed >= float64(threshold) {
+			reached = append(reached, threshold)
+		}
+	}
+	return reached
+}
+
+// ParseMonth parses a month in the YYYY-MM format
+func ParseMonth(month string) (time.Time, error) {
+	parsed, err := time.ParseInLocation(MonthLayout, month, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("month must have the YYYY-MM format")
+	}
+	return parsed, nil
+}
+
+// MonthOf returns the month of a date in the YYYY-MM format
+func MonthOf(date time.Time) string {
+	return date.Format(MonthLayout)
+}
+
+// MonthRange returns the first instant of a month and of the next one
+func MonthRange(month string) (time.Time, time.Time, error) {
+	from, err := ParseMonth(month)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, from.AddDate(0, 1, 0), nil
+}
+
+// AddMonths moves a month in the YYYY-MM format
+func AddMonths(month string, months int) string {
+	parsed, err := ParseMonth(month)
+	if err != nil {
+		return month
+	}
+	return MonthOf(parsed.AddDate(0, months, 0))
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,346 @@
+package budget
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+// groceries is a category budget of 1000 ARS a month
+func groceries(rollover Rollover) *Budget {
+	return &Budget{
+		ID:         "bdg-1",
+		UserID:     "user-1",
+		Name:       "Supermercado",
+		Scope:      ScopeCategory,
+		CategoryID: stringPtr("category-groceries"),
+		Amount:     1000,
+		Currency:   "ARS",
+		Rollover:   rollover,
+		StartMonth: "2025-01",
+	}
+}
+
+func TestNewPeriod(t *testing.T) {
+	now := time.Date(2025, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                string
+		carriedOver         float64
+		spent               float64
+		installments        float64
+		expectedLimit       float64
+		expectedActual      float64
+		expectedRemaining   float64
+		expectedPercentUsed float64
+		expectedStatus      Status
+		expectedThresholds  string
+	}{
+		{
+			name:                "nothing spent",
+			expectedLimit:       1000,
+			expectedRemaining:   1000,
+			expectedStatus:      StatusOnTrack,
+			expectedThresholds:  "[]",
+			expectedPercentUsed: 0,
+		},
+		{
+			// Shown rounded to 50%, but the alert waits for the threshold itself
+			name:                "a cent below the first threshold",
+			spent:               499.99,
+			expectedLimit:       1000,
+			expectedActual:      499.99,
+			expectedRemaining:   500.01,
+			expectedPercentUsed: 50,
+			expectedStatus:      StatusOnTrack,
+			expectedThresholds:  "[]",
+		},
+		{
+			name:                "at the first threshold",
+			spent:               500,
+			expectedLimit:       1000,
+			expectedActual:      500,
+			expectedRemaining:   500,
+			expectedPercentUsed: 50,
+			expectedStatus:      StatusWarning,
+			expectedThresholds:  "[50]",
+		},
+		{
+			name:                "a cent past the limit",
+			spent:               1000.01,
+			expectedLimit:       1000,
+			expectedActual:      1000.01,
+			expectedRemaining:   -0.01,
+			expectedPercentUsed: 100,
+			expectedStatus:      StatusExceeded,
+			expectedThresholds:  "[50 80 100]",
+		},
+		{
+			name:                "transactions and installments",
+			spent:               300,
+			installments:        250,
+			expectedLimit:       1000,
+			expectedActual:      550,
+			expectedRemaining:   450,
+			expectedPercentUsed: 55,
+			expectedStatus:      StatusWarning,
+			expectedThresholds:  "[50]",
+		},
+		{
+			name:                "only installments past the second threshold",
+			installments:        800,
+			expectedLimit:       1000,
+			expectedActual:      800,
+			expectedRemaining:   200,
+			expectedPercentUsed: 80,
+			expectedStatus:      StatusWarning,
+			expectedThresholds:  "[50 80]",
+		},
+		{
+			name:                "exactly the limit",
+			spent:               1000,
+			expectedLimit:       1000,
+			expectedActual:      1000,
+			expectedPercentUsed: 100,
+			expectedStatus:      StatusWarning,
+			expectedThresholds:  "[50 80 100]",
+		},
+		{
+			name:                "past the limit",
+			spent:               900,
+			installments:        300.5,
+			expectedLimit:       1000,
+			expectedActual:      1200.5,
+			expectedRemaining:   -200.5,
+			expectedPercentUsed: 120.05,
+			expectedStatus:      StatusExceeded,
+			expectedThresholds:  "[50 80 100]",
+		},
+		{
+			name:                "unspent carried over",
+			carriedOver:         400,
+			spent:               700,
+			expectedLimit:       1400,
+			expectedActual:      700,
+			expectedRemaining:   700,
+			expectedPercentUsed: 50,
+			expectedStatus:      StatusWarning,
+			expectedThresholds:  "[50]",
+		},
+		{
+			name:                "overspending carried over",
+			carriedOver:         -200,
+			spent:               700,
+			expectedLimit:       800,
+			expectedActual:      700,
+			expectedRemaining:   100,
+			expectedPercentUsed: 87.5,
+			expectedStatus:      StatusWarning,
+			expectedThresholds:  "[50 80]",
+		},
+		{
+			name:                "everything overspent in previous months",
+			carriedOver:         -1500,
+			spent:               10,
+			expectedLimit:       -500,
+			expectedActual:      10,
+			expectedRemaining:   -510,
+			expectedPercentUsed: 100,
+			expectedStatus:      StatusWarning,
+			expectedThresholds:  "[50 80 100]",
+		},
+		{
+			name:                "everything overspent in previous months and nothing spent",
+			carriedOver:         -1000,
+			expectedLimit:       0,
+			expectedStatus:      StatusOnTrack,
+			expectedThresholds:  "[]",
+			expectedPercentUsed: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period := NewPeriod(groceries(RolloverFull), "2025-03", tt.carriedOver, tt.spent, tt.installments, now)
+
+			if period.Limit != tt.expectedLimit || period.Actual != tt.expectedActual || period.Remaining != tt.expectedRemaining {
+				t.Errorf("NewPeriod() limit %v, actual %v, remaining %v, want %v, %v, %v",
+					period.Limit, period.Actual, period.Remaining, tt.expectedLimit, tt.expectedActual, tt.expectedRemaining)
+			}
+			if period.PercentUsed != tt.expectedPercentUsed || period.Status != tt.expectedStatus {
+				t.Errorf("NewPeriod() = %v%% %s, want %v%% %s", period.PercentUsed, period.Status, tt.expectedPercentUsed, tt.expectedStatus)
+			}
+			if got := fmt.Sprint(period.Thresholds); got != tt.expectedThresholds {
+				t.Errorf("NewPeriod() thresholds = %s, want %s", got, tt.expectedThresholds)
+			}
+			if period.Amount != 1000 || period.CarriedOver != tt.carriedOver || period.Month != "2025-03" || !period.ComputedAt.Equal(now) {
+				t.Errorf("NewPeriod() = %+v", *period)
+			}
+		})
+	}
+}
+
+func TestCarryOver(t *testing.T) {
+	tests := []struct {
+		rollover  Rollover
+		remaining float64
+		expected  float64
+	}{
+		{RolloverNone, 400, 0},
+		{RolloverNone, -200, 0},
+		{RolloverUnspent, 400, 400},
+		{RolloverUnspent, -200, 0},
+		{RolloverUnspent, 0, 0},
+		{RolloverFull, 400, 400},
+		{RolloverFull, -200, -200},
+	}
+
+	for _, tt := range tests {
+		if got := CarryOver(tt.rollover, &Period{Remaining: tt.remaining}); got != tt.expected {
+			t.Errorf("CarryOver(%s, %v) = %v, want %v", tt.rollover, tt.remaining, got, tt.expected)
+		}
+	}
+}
+
+// TestRolloverAcrossMonths chains months as the budget service does, each one starting from what the
+// previous one carried over
+func TestRolloverAcrossMonths(t *testing.T) {
+	spending := []float64{600, 1600, 200, 1000}
+
+	tests := []struct {
+		rollover       Rollover
+		expectedLimits []float64
+	}{
+		{RolloverNone, []float64{1000, 1000, 1000, 1000}},
+		// 400 left in January; February is 200 over, which is not taken from March
+		{RolloverUnspent, []float64{1000, 1400, 1000, 1800}},
+		// The 200 February was over comes out of March
+		{RolloverFull, []float64{1000, 1400, 800, 1600}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.rollover), func(t *testing.T) {
+			budget := groceries(tt.rollover)
+			var carriedOver float64
+			month := budget.StartMonth
+			for i, spent := range spending {
+				period := NewPeriod(budget, month, carriedOver, spent, 0, time.Now())
+				if period.Limit != tt.expectedLimits[i] {
+					t.Errorf("%s limit = %v, want %v", month, period.Limit, tt.expectedLimits[i])
+				}
+				carriedOver = CarryOver(budget.Rollover, period)
+				month = AddMonths(month, 1)
+			}
+		})
+	}
+}
+
+func TestReachedThresholds(t *testing.T) {
+	tests := []struct {
+		percentUsed float64
+		expected    string
+	}{
+		{0, "[]"},
+		{49.99, "[]"},
+		{50, "[50]"},
+		{79.99, "[50]"},
+		{80, "[50 80]"},
+		{100, "[50 80 100]"},
+		{250, "[50 80 100]"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprint(ReachedThresholds(tt.percentUsed)); got != tt.expected {
+			t.Errorf("ReachedThresholds(%v) = %s, want %s", tt.percentUsed, got, tt.expected)
+		}
+	}
+}
+
+func TestBudgetValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(budget *Budget)
+		expectError bool
+	}{
+		{name: "valid category budget", change: func(budget *Budget) {}},
+		{name: "merchant budget", change: func(budget *Budget) {
+			budget.Scope, budget.CategoryID, budget.MerchantName = ScopeMerchant, nil, stringPtr("Mercado Libre")
+		}},
+		{name: "card budget", change: func(budget *Budget) {
+			budget.Scope, budget.CategoryID, budget.CardID = ScopeCard, nil, stringPtr("card-1")
+		}},
+		{name: "one month", change: func(budget *Budget) { budget.EndMonth = stringPtr("2025-01") }},
+		{name: "without name", change: func(budget *Budget) { budget.Name = "" }, expectError: true},
+		{name: "category budget without category", change: func(budget *Budget) { budget.CategoryID = nil }, expectError: true},
+		{name: "merchant budget without merchant", change: func(budget *Budget) { budget.Scope = ScopeMerchant }, expectError: true},
+		{name: "card budget without card", change: func(budget *Budget) { budget.Scope = ScopeCard }, expectError: true},
+		{name: "unknown scope", change: func(budget *Budget) { budget.Scope = "account" }, expectError: true},
+		{name: "zero amount", change: func(budget *Budget) { budget.Amount = 0 }, expectError: true},
+		{name: "invalid currency", change: func(budget *Budget) { budget.Currency = "PESOS" }, expectError: true},
+		{name: "unknown rollover", change: func(budget *Budget) { budget.Rollover = "half" }, expectError: true},
+		{name: "invalid start month", change: func(budget *Budget) { budget.StartMonth = "2025-13" }, expectError: true},
+		{name: "start month as a date", change: func(budget *Budget) { budget.StartMonth = "2025-01-01" }, expectError: true},
+		{name: "end before start", change: func(budget *Budget) { budget.EndMonth = stringPtr("2024-12") }, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := groceries(RolloverNone)
+			tt.change(budget)
+
+			err := budget.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Validate() expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Validate() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestActiveIn(t *testing.T) {
+	budget := groceries(RolloverNone)
+	budget.EndMonth = stringPtr("2025-06")
+
+	for month, expected := range map[string]bool{
+		"2024-12": false,
+		"2025-01": true,
+		"2025-06": true,
+		"2025-07": false,
+	} {
+		if got := budget.ActiveIn(month); got != expected {
+			t.Errorf("ActiveIn(%s) = %v, want %v", month, got, expected)
+		}
+	}
+
+	budget.EndMonth = nil
+	if !budget.ActiveIn("2030-01") {
+		t.Error("ActiveIn() of a budget without end expected true")
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		month    string
+		months   int
+		expected string
+	}{
+		{"2025-01", 1, "2025-02"},
+		{"2025-12", 1, "2026-01"},
+		{"2025-03", -12, "2024-03"},
+		{"2025-01", -13, "2023-12"},
+		{"2025-01", 0, "2025-01"},
+		{"enero", 1, "enero"},
+	}
+
+	for _, tt := range tests {
+		if got := AddMonths(tt.month, tt.months); got != tt.expected {
+			t.Errorf("AddMonths(%s, %d) = %s, want %s", tt.month, tt.months, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package category
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// CategoryKind tells whether a category groups expenses or incomes
+type CategoryKind string
+
+const (
+	CategoryKindExpense CategoryKind = "expense"
+	CategoryKindIncome  CategoryKind = "income"
+)
+
+// MaxCategoryDepth is the maximum depth of the category tree (category > subcategory)
+const MaxCategoryDepth = 2
+
+// Category is a node of the category tree. Default categories have no user and are shared by everyone;
+// custom categories belong to one user and can hang from a default category.
+type Category struct {
+	ID        string       `json:"id"`
+	UserID    *string      `json:"userId"`
+	ParentID  *string      `json:"parentId"`
+	Name      string       `json:"name"`
+	Kind      CategoryKind `json:"kind"`
+	Icon      string       `json:"icon"`
+	IsDefault bool         `json:"isDefault"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// CategoryNode is a category with its subcategories
+type CategoryNode struct {
+	*Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// CategorizationRule assigns a category to the transactions of a user that match all its conditions.
+// Rules are evaluated by priority (highest first) and the first match wins.
+type CategorizationRule struct {
+	ID               string    `json:"id"`
+	UserID           string    `json:"userId"`
+	CategoryID       string    `json:"categoryId"`
+	Name             string    `json:"name"`
+	MerchantPattern  string    `json:"merchantPattern"`  // Case insensitive substring of the merchant name
+	DescriptionRegex string    `json:"descriptionRegex"` // Case insensitive regular expression over the description
+	MinAmount        *float64  `json:"minAmount"`
+	MaxAmount        *float64  `json:"maxAmount"`
+	CardID           *string   `json:"cardId"`
+	Priority         int       `json:"priority"`
+	IsActive         bool      `json:"isActive"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+
+	descriptionRegexp *regexp.Regexp
+}
+
+// IsCustom checks if the category was created by a user
+func (c *Category) IsCustom() bool {
+	return c.UserID != nil
+}
+
+// IsVisibleTo checks if the user can use the category: default categories and their own
+func (c *Category) IsVisibleTo(userID string) bool {
+	return c.UserID == nil || *c.UserID == userID
+}
+
+// IsOwnedBy checks if the category is a custom category of the user
+func (c *Category) IsOwnedBy(userID string) bool {
+	return c.UserID != nil && *c.UserID == userID
+}
+
+// Validate validates the category fields
+func (c *Category) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return errors.New("category name is required")
+	}
+	if len(c.Name) > 100 {
+		return errors.New("category name cannot exceed 100 characters")
+	}
+	if c.Kind != CategoryKindExpense && c.Kind != CategoryKindIncome {
+		return errors.New("category kind must be expense or income")
+	}
+	return nil
+}
+
+// BuildCategoryTree arranges categories as a tree sorted by name. Categories whose parent
+// is not in the list are returned as roots.
+func BuildCategoryTree(categories []*Category) []*CategoryNode {
+	nodes := make(map[string]*CategoryNode, len(categories))
+	for _, c := range categories {
+		nodes[c.ID] = &CategoryNode{Category: c, Children: []*CategoryNode{}}
+	}
+
+	var roots []*CategoryNode
+	for _, c := range categories {
+		node := nodes[c.ID]
+		if c.ParentID != nil {
+			if parent, exists := nodes[*c.ParentID]; exists {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	sortNodes(roots)
+	return roots
+}
+
+func sortNodes(nodes []*CategoryNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return strings.ToLower(nodes[i].Name) < strings.ToLower(nodes[j].Name)
+	})
+	for _, node := range nodes {
+		sortNodes(node.Children)
+	}
+}
+
+// Validate validates the rule conditions and compiles the description expression
+func (r *CategorizationRule) Validate() error {
+	if r.CategoryID == "" {
+		return errors.New("rule category is required")
+	}
+	r.MerchantPattern = strings.TrimSpace(r.MerchantPattern)
+	r.DescriptionRegex = strings.TrimSpace(r.DescriptionRegex)
+
+	if r.MerchantPattern == "" && r.DescriptionRegex == "" && r.MinAmount == nil && r.MaxAmount == nil && r.CardID == nil {
+		return errors.New("rule needs at least one condition: merchant, description, amount range or card")
+	}
+	if r.MinAmount != nil && *r.MinAmount < 0 {
+		return errors.New("rule minimum amount cannot be negative")
+	}
+	if r.MinAmount != nil && r.MaxAmount != nil && *r.MinAmount > *r.MaxAmount {
+		return errors.New("rule minimum amount cannot be greater than the maximum amount")
+	}
+
+	return r.CompileDescriptionRegex()
+}
+
+// CompileDescriptionRegex compiles the description expression of the rule, as Matches needs it compiled
+func (r *CategorizationRule) CompileDescriptionRegex() error {
+	r.descriptionRegexp = nil
+	if r.DescriptionRegex == "" {
+		return nil
+	}
+	expression, err := regexp.Compile("(?i)" + r.DescriptionRegex)
+	if err != nil {
+		return errors.New("invalid description regular expression: " + err.Error())
+	}
+	r.descriptionRegexp = expression
+	return nil
+}
+
+// Matches checks if the transaction meets every condition of the rule. It does not modify the rule, so
+// rules loaded once can be matched from several goroutines; the description expression is compiled by
+// Validate or CompileDescriptionRegex beforehand.
+func (r *CategorizationRule) Matches(transaction *domaintransaction.Transaction) bool {
+	if !r.IsActive || transaction.UserID != r.UserID {
+		return false
+	}
+
+	if r.MerchantPattern != "" &&
+		!strings.Contains(strings.ToLower(transaction.MerchantName), strings.ToLower(r.MerchantPattern)) {
+		return false
+	}
+
+	// An expression that was not compiled, or does not compile, matches nothing
+	if r.DescriptionRegex != "" &&
+		(r.descriptionRegexp == nil || !r.descriptionRegexp.MatchString(transaction.Description)) {
+		return false
+	}
+
+	if r.MinAmount != nil && transaction.Amount < *r.MinAmount {
+		return false
+	}
+	if r.MaxAmount != nil && transaction.Amount > *r.MaxAmount {
+		return false
+	}
+
+	if r.CardID != nil && !sameID(r.CardID, transaction.FromCardID) && !sameID(r.CardID, transaction.ToCardID) {
+		return false
+	}
+
+	return true
+}
+
+// FindMatchingRule returns the first active rule matching the transaction by priority, or nil
+func FindMatchingRule(rules []*CategorizationRule, transaction *domaintransaction.Transaction) *CategorizationRule {
+	sorted := make([]*CategorizationRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+
+	for _, rule := range sorted {
+		if rule.Matches(transaction) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// NewRuleFromTransaction builds a rule that assigns the category to transactions like the given one:
+// same merchant when it has one, otherwise the same description
+func NewRuleFromTransaction(transaction *domaintransaction.Transaction, categoryID string) (*CategorizationRule, error) {
+	rule := &CategorizationRule{
+		UserID:     transaction.UserID,
+		CategoryID: categoryID,
+		IsActive:   true,
+	}
+
+	switch {
+	case strings.TrimSpace(transaction.MerchantName) != "":
+		rule.MerchantPattern = strings.TrimSpace(transaction.MerchantName)
+		rule.Name = rule.MerchantPattern
+	case strings.TrimSpace(transaction.Description) != "":
+		description := strings.TrimSpace(transaction.Description)
+		// Surrounding spaces are ignored, as the description is trimmed to build the rule
+		rule.DescriptionRegex = `^\s*` + regexp.QuoteMeta(description) + `\s*$`
+		rule.Name = description
+	default:
+		return nil, errors.New("transaction has no merchant or description to create a rule from")
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func sameID(a, b *string) bool {
+	return a != nil && b != nil && *a == *b
+}
@@ -0,0 +1,322 @@
+package category
+
+import (
+	"testing"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
+// purchase is a payment of 1500 ARS with the card card-1 at a supermarket
+func purchase() *domaintransaction.Transaction {
+	return &domaintransaction.Transaction{
+		ID:           "tx-1",
+		UserID:       "user-1",
+		Type:         domaintransaction.TransactionTypeCreditCharge,
+		Amount:       1500,
+		Currency:     "ARS",
+		FromCardID:   stringPtr("card-1"),
+		MerchantName: "Supermercado Día",
+		Description:  "Compra semanal",
+	}
+}
+
+// newRule returns an active rule of user-1 after validating it, as the service stores them
+func newRule(t *testing.T, rule CategorizationRule) *CategorizationRule {
+	t.Helper()
+	rule.UserID = "user-1"
+	rule.CategoryID = "cat-supermercado"
+	rule.IsActive = true
+	if err := rule.Validate(); err != nil {
+		t.Fatalf("Validate() unexpected error: %v", err)
+	}
+	return &rule
+}
+
+func TestCategorizationRuleMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     CategorizationRule
+		change   func(transaction *domaintransaction.Transaction)
+		expected bool
+	}{
+		{
+			name:     "merchant in another case",
+			rule:     CategorizationRule{MerchantPattern: "SUPERMERCADO"},
+			expected: true,
+		},
+		{
+			name:     "merchant not contained",
+			rule:     CategorizationRule{MerchantPattern: "Carrefour"},
+			expected: false,
+		},
+		{
+			name:     "description expression in another case",
+			rule:     CategorizationRule{DescriptionRegex: "^compra\\s+SEMANAL$"},
+			expected: true,
+		},
+		{
+			name:     "description expression not matching",
+			rule:     CategorizationRule{DescriptionRegex: "^Netflix"},
+			expected: false,
+		},
+		{
+			name:     "amount at the minimum",
+			rule:     CategorizationRule{MinAmount: floatPtr(1500)},
+			expected: true,
+		},
+		{
+			name:     "amount below the minimum",
+			rule:     CategorizationRule{MinAmount: floatPtr(1500.01)},
+			expected: false,
+		},
+		{
+			name:     "amount at the maximum",
+			rule:     CategorizationRule{MaxAmount: floatPtr(1500)},
+			expected: true,
+		},
+		{
+			name:     "amount above the maximum",
+			rule:     CategorizationRule{MinAmount: floatPtr(100), MaxAmount: floatPtr(1499.99)},
+			expected: false,
+		},
+		{
+			name:     "card paying the transaction",
+			rule:     CategorizationRule{CardID: stringPtr("card-1")},
+			expected: true,
+		},
+		{
+			name: "card receiving the transaction",
+			rule: CategorizationRule{CardID: stringPtr("card-1")},
+			change: func(transaction *domaintransaction.Transaction) {
+				transaction.FromCardID = nil
+				transaction.ToCardID = stringPtr("card-1")
+			},
+			expected: true,
+		},
+		{
+			name:     "another card",
+			rule:     CategorizationRule{CardID: stringPtr("card-2")},
+			expected: false,
+		},
+		{
+			name:     "transaction without card",
+			rule:     CategorizationRule{CardID: stringPtr("card-1")},
+			change:   func(transaction *domaintransaction.Transaction) { transaction.FromCardID = nil },
+			expected: false,
+		},
+		{
+			name:     "every condition met",
+			rule:     CategorizationRule{MerchantPattern: "día", DescriptionRegex: "semanal", MinAmount: floatPtr(1000), MaxAmount: floatPtr(2000), CardID: stringPtr("card-1")},
+			expected: true,
+		},
+		{
+			name:     "one condition not met",
+			rule:     CategorizationRule{MerchantPattern: "día", DescriptionRegex: "semanal", MaxAmount: floatPtr(1000)},
+			expected: false,
+		},
+		{
+			name:     "transaction of another user",
+			rule:     CategorizationRule{MerchantPattern: "Supermercado"},
+			change:   func(transaction *domaintransaction.Transaction) { transaction.UserID = "user-2" },
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := newRule(t, tt.rule)
+			transaction := purchase()
+			if tt.change != nil {
+				tt.change(transaction)
+			}
+
+			if got := rule.Matches(transaction); got != tt.expected {
+				t.Errorf("Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCategorizationRuleMatchesInactive(t *testing.T) {
+	rule := newRule(t, CategorizationRule{MerchantPattern: "Supermercado"})
+	rule.IsActive = false
+
+	if rule.Matches(purchase()) {
+		t.Error("Matches() of an inactive rule = true, want false")
+	}
+}
+
+func TestCategorizationRuleMatchesDoesNotModifyRule(t *testing.T) {
+	// Loaded from the database: the expression is compiled on load, the rest is kept as stored
+	rule := &CategorizationRule{
+		UserID:           "user-1",
+		CategoryID:       "cat-supermercado",
+		MerchantPattern:  "  Supermercado  ",
+		DescriptionRegex: "semanal",
+		IsActive:         true,
+	}
+	if err := rule.CompileDescriptionRegex(); err != nil {
+		t.Fatalf("CompileDescriptionRegex() unexpected error: %v", err)
+	}
+
+	if !rule.Matches(&domaintransaction.Transaction{UserID: "user-1", MerchantName: "  Supermercado  Día", Description: "Compra semanal"}) {
+		t.Error("Matches() = false, want true")
+	}
+	if rule.MerchantPattern != "  Supermercado  " {
+		t.Errorf("Matches() changed the merchant pattern to %q", rule.MerchantPattern)
+	}
+
+	// Not compiled, or not compiling, the expression matches nothing
+	uncompiled := &CategorizationRule{UserID: "user-1", DescriptionRegex: "semanal", IsActive: true}
+	if uncompiled.Matches(purchase()) {
+		t.Error("Matches() with an uncompiled expression = true, want false")
+	}
+	invalid := &CategorizationRule{UserID: "user-1", DescriptionRegex: "(semanal", IsActive: true}
+	if err := invalid.CompileDescriptionRegex(); err == nil {
+		t.Error("CompileDescriptionRegex() of an invalid expression expected error but got none")
+	}
+	if invalid.Matches(purchase()) {
+		t.Error("Matches() with an invalid expression = true, want false")
+	}
+}
+
+func TestFindMatchingRule(t *testing.T) {
+	merchant := newRule(t, CategorizationRule{ID: "merchant", MerchantPattern: "Supermercado", Priority: 1})
+	amount := newRule(t, CategorizationRule{ID: "amount", MinAmount: floatPtr(1000), Priority: 5})
+	card := newRule(t, CategorizationRule{ID: "card", CardID: stringPtr("card-1"), Priority: 5})
+	other := newRule(t, CategorizationRule{ID: "other", MerchantPattern: "Netflix", Priority: 10})
+	inactive := newRule(t, CategorizationRule{ID: "inactive", MerchantPattern: "Supermercado", Priority: 20})
+	inactive.IsActive = false
+
+	tests := []struct {
+		name     string
+		rules    []*CategorizationRule
+		expected string // ID of the rule found, empty for none
+	}{
+		{
+			name:     "highest priority among the matching rules",
+			rules:    []*CategorizationRule{merchant, other, amount},
+			expected: "amount",
+		},
+		{
+			name:     "same priority keeps the order of the list",
+			rules:    []*CategorizationRule{merchant, card, amount},
+			expected: "card",
+		},
+		{
+			name:     "inactive rules are skipped",
+			rules:    []*CategorizationRule{inactive, merchant},
+			expected: "merchant",
+		},
+		{
+			name:     "no rule matches",
+			rules:    []*CategorizationRule{other, inactive},
+			expected: "",
+		},
+		{
+			name:     "no rules",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := FindMatchingRule(tt.rules, purchase())
+
+			got := ""
+			if rule != nil {
+				got = rule.ID
+			}
+			if got != tt.expected {
+				t.Errorf("FindMatchingRule() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if tests[0].rules[0] != merchant {
+		t.Error("FindMatchingRule() reordered the rules it was given")
+	}
+}
+
+func TestNewRuleFromTransaction(t *testing.T) {
+	tests := []struct {
+		name             string
+		merchant         string
+		description      string
+		expectedMerchant string
+		expectedRegex    string
+		expectedName     string
+		expectError      bool
+	}{
+		{
+			name:             "from the merchant",
+			merchant:         "  Supermercado Día ",
+			description:      "Compra semanal",
+			expectedMerchant: "Supermercado Día",
+			expectedName:     "Supermercado Día",
+		},
+		{
+			name:          "from the description",
+			description:   " Compra semanal ",
+			expectedRegex: `^\s*Compra semanal\s*$`,
+			expectedName:  "Compra semanal",
+		},
+		{
+			name:          "description with special characters",
+			description:   "Pago (cuota 1/3) $1.500 + IVA*",
+			expectedRegex: `^\s*Pago \(cuota 1/3\) \$1\.500 \+ IVA\*\s*$`,
+			expectedName:  "Pago (cuota 1/3) $1.500 + IVA*",
+		},
+		{
+			name:        "without merchant or description",
+			merchant:    " ",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := purchase()
+			transaction.MerchantName = tt.merchant
+			transaction.Description = tt.description
+
+			rule, err := NewRuleFromTransaction(transaction, "cat-supermercado")
+			if tt.expectError {
+				if err == nil {
+					t.Error("NewRuleFromTransaction() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewRuleFromTransaction() unexpected error: %v", err)
+			}
+
+			if rule.MerchantPattern != tt.expectedMerchant || rule.DescriptionRegex != tt.expectedRegex || rule.Name != tt.expectedName {
+				t.Errorf("NewRuleFromTransaction() = %q %q %q, want %q %q %q", rule.MerchantPattern, rule.DescriptionRegex, rule.Name,
+					tt.expectedMerchant, tt.expectedRegex, tt.expectedName)
+			}
+			if rule.UserID != "user-1" || rule.CategoryID != "cat-supermercado" || !rule.IsActive {
+				t.Errorf("NewRuleFromTransaction() = %v %v active %v, want user-1 cat-supermercado active", rule.UserID, rule.CategoryID, rule.IsActive)
+			}
+
+			// The rule matches the transaction it was built from, and only the exact description
+			if !rule.Matches(transaction) {
+				t.Error("NewRuleFromTransaction() rule does not match its own transaction")
+			}
+			if tt.expectedRegex != "" {
+				transaction.Description = "Otra " + transaction.Description
+				if rule.Matches(transaction) {
+					t.Error("NewRuleFromTransaction() rule matches a longer description")
+				}
+			}
+		})
+	}
+}
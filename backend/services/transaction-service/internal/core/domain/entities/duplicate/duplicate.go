@@ -0,0 +1,333 @@
+package duplicate
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	domainreconciliation "github.com/fintrack/transaction-service/internal/core/domain/entities/reconciliation"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// Config tunes when two transactions are considered duplicates
+type Config struct {
+	Window        time.Duration // How far apart two duplicates can be created
+	MinSimilarity float64       // Minimum merchant similarity when both transactions describe one
+}
+
+// DefaultConfig returns the configuration used on create and when the user does not tune the scan
+func DefaultConfig() Config {
+	return Config{
+		Window:        10 * time.Minute,
+		MinSimilarity: 0.5,
+	}
+}
+
+// MaxWindow bounds the window of a scan, beyond it repeated purchases would look like duplicates
+const MaxWindow = 24 * time.Hour
+
+// Candidate is a transaction that looks like a duplicate of another one
+type Candidate struct {
+	TransactionID string    `json:"transactionId"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Description   string    `json:"description"`
+	MerchantName  string    `json:"merchantName,omitempty"`
+	Score         float64   `json:"score"` // 0 to 1: closeness in time and merchant similarity
+}
+
+// Group is a set of transactions that look like the same movement recorded more than once
+type Group struct {
+	Transactions    []*domaintransaction.Transaction `json:"transactions"`
+	SuggestedKeepID string                           `json:"suggestedKeepId"`
+	Score           float64                          `json:"score"` // Lowest score between the transactions of the group
+}
+
+// Weights of the duplicate score, the amount and currency are required to match
+const (
+	timeWeight     = 0.5
+	merchantWeight = 0.5
+)
+
+// Match checks if two transactions look like duplicates: same amount, currency, card or account and
+// direction, created within the window, and a similar merchant when both describe one. It returns the
+// score of the pair.
+func Match(a, b *domaintransaction.Transaction, config Config) (float64, bool) {
+	if a.ID == b.ID || !IsActive(a) || !IsActive(b) {
+		return 0, false
+	}
+	if math.Abs(a.Amount-b.Amount) >= 0.005 || a.Currency != b.Currency {
+		return 0, false
+	}
+	if source(a) != source(b) || destination(a) != destination(b) {
+		return 0, false
+	}
+
+	gap := a.CreatedAt.Sub(b.CreatedAt)
+	if gap < 0 {
+		gap = -gap
+	}
+	if gap > config.Window {
+		return 0, false
+	}
+	timeScore := 1.0
+	if config.Window > 0 {
+		timeScore = 1 - float64(gap)/float64(config.Window)
+	}
+
+	// Without a merchant on one side the amount, instrument and time have to be enough
+	merchantScore := 0.5
+	merchantA, merchantB := merchant(a), merchant(b)
+	if merchantA != "" && merchantB != "" {
+		merchantScore = domainreconciliation.Similarity(merchantA, merchantB)
+		if merchantScore < config.MinSimilarity {
+			return 0, false
+		}
+	}
+
+	score := timeWeight*timeScore + merchantWeight*merchantScore
+	return math.Round(score*100) / 100, true
+}
+
+// FindCandidates returns the transactions that look like duplicates of a transaction, best first
+func FindCandidates(transaction *domaintransaction.Transaction, others []*domaintransaction.Transaction, config Config) []*Candidate {
+	candidates := []*Candidate{}
+	for _, other := range others {
+		score, matches := Match(transaction, other, config)
+		if !matches {
+			continue
+		}
+		candidates = append(candidates, &Candidate{
+			TransactionID: other.ID,
+			CreatedAt:     other.CreatedAt,
+			Description:   other.Description,
+			MerchantName:  other.MerchantName,
+			Score:         score,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// FindGroups groups the transactions that look like duplicates of each other. Transactions must be
+// sorted by creation date; a transaction joins the group of any earlier one it matches.
+func FindGroups(transactions []*domaintransaction.Transaction, config Config) []*Group {
+	parent := make([]int, len(transactions))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	scores := make(map[int]float64)
+	for i := range transactions {
+		for j := i + 1; j < len(transactions); j++ {
+			if transactions[j].CreatedAt.Sub(transactions[i].CreatedAt) > config.Window {
+				break
+			}
+			score, matches := Match(transactions[i], transactions[j], config)
+			if !matches {
+				continue
+			}
+
+			rootI, rootJ := find(i), find(j)
+			groupScore := score
+			for _, root := range []int{rootI, rootJ} {
+				if existing, exists := scores[root]; exists {
+					groupScore = math.Min(groupScore, existing)
+				}
+			}
+			parent[rootJ] = rootI
+			delete(scores, rootJ)
+			scores[rootI] = groupScore
+		}
+	}
+
+	members := make(map[int][]*domaintransaction.Transaction)
+	var roots []int
+	for i, transaction := range transactions {
+		root := find(i)
+		if _, exists := scores[root]; !exists {
+			continue
+		}
+		if _, exists := members[root]; !exists {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], transaction)
+	}
+
+	groups := make([]*Group, 0, len(roots))
+	for _, root := range roots {
+		groups = append(groups, &Group{
+			Transactions:    members[root],
+			SuggestedKeepID: SuggestKeep(members[root]).ID,
+			Score:           scores[root],
+		})
+	}
+	return groups
+}
+
+// SuggestKeep picks the transaction to keep from a group: the one linked to an installment plan, then the
+// one with more details filled in, then the oldest
+func SuggestKeep(transactions []*domaintransaction.Transaction) *domaintransaction.Transaction {
+	best := transactions[0]
+	for _, transaction := range transactions[1:] {
+		if rank(transaction) > rank(best) || (rank(transaction) == rank(best) && transaction.CreatedAt.Before(best.CreatedAt)) {
+			best = transaction
+		}
+	}
+	return best
+}
+
+// mergeSkippedMetadata are metadata keys that describe how a single record was created. importBatchId in
+// particular would make the rollback of the import of a duplicate delete the kept transaction.
+var mergeSkippedMetadata = map[string]bool{
+	"importBatchId": true,
+	"recordOnly":    true,
+}
+
+// Merge completes the kept transaction with what only the duplicates have: category, merchant, notes,
+// bank and reference identifiers, tags and metadata such as the installment plan. It returns the fields
+// that changed.
+func Merge(keep *domaintransaction.Transaction, duplicates []*domaintransaction.Transaction) map[string]interface{} {
+	changed := make(map[string]interface{})
+
+	for _, duplicate := range duplicates {
+		if keep.CategoryID == nil && duplicate.CategoryID != nil {
+			keep.SetCategory(duplicate.CategoryID, duplicate.CategorySource)
+			changed["categoryId"] = *duplicate.CategoryID
+		}
+		if keep.MerchantName == "" && duplicate.MerchantName != "" {
+			keep.MerchantName = duplicate.MerchantName
+			changed["merchantName"] = duplicate.MerchantName
+		}
+		if keep.MerchantID == "" && duplicate.MerchantID != "" {
+			keep.MerchantID = duplicate.MerchantID
+			changed["merchantId"] = duplicate.MerchantID
+		}
+		if duplicate.Notes != "" && !containsNote(keep.Notes, duplicate.Notes) {
+			if keep.Notes != "" {
+				keep.Notes += "\n"
+			}
+			keep.Notes += duplicate.Notes
+			changed["notes"] = keep.Notes
+		}
+		if keep.ExternalID == "" && duplicate.ExternalID != "" {
+			keep.ExternalID = duplicate.ExternalID
+			changed["externalId"] = duplicate.ExternalID
+		}
+		if keep.ReferenceID == "" && duplicate.ReferenceID != "" {
+			keep.ReferenceID = duplicate.ReferenceID
+			changed["referenceId"] = duplicate.ReferenceID
+		}
+
+		for _, tag := range duplicate.Tags {
+			if !containsTag(keep.Tags, tag) {
+				keep.Tags = append(keep.Tags, tag)
+				changed["tags"] = keep.Tags
+			}
+		}
+
+		for key, value := range duplicate.Metadata {
+			if mergeSkippedMetadata[key] {
+				continue
+			}
+			if _, exists := keep.Metadata[key]; exists {
+				continue
+			}
+			if keep.Metadata == nil {
+				keep.Metadata = make(map[string]interface{})
+			}
+			keep.Metadata[key] = value
+			changed["metadata."+key] = value
+		}
+	}
+
+	return changed
+}
+
+// IsActive checks if a transaction counts as a movement: failed, canceled and reversed ones are not duplicates
+func IsActive(transaction *domaintransaction.Transaction) bool {
+	return transaction.Status == domaintransaction.TransactionStatusPending ||
+		transaction.Status == domaintransaction.TransactionStatusCompleted
+}
+
+// source identifies where the money came from. The account service records card operations on the
+// account of the card with the card in the metadata, while manual entries use the card itself.
+func source(transaction *domaintransaction.Transaction) string {
+	if transaction.FromCardID != nil {
+		return "card:" + *transaction.FromCardID
+	}
+	if cardID, ok := transaction.Metadata["cardId"].(string); ok && cardID != "" && transaction.ToAccountID == nil {
+		return "card:" + cardID
+	}
+	if transaction.FromAccountID != nil {
+		return "account:" + *transaction.FromAccountID
+	}
+	return ""
+}
+
+// destination identifies where the money went to
+func destination(transaction *domaintransaction.Transaction) string {
+	if transaction.ToCardID != nil {
+		return "card:" + *transaction.ToCardID
+	}
+	if cardID, ok := transaction.Metadata["cardId"].(string); ok && cardID != "" && transaction.ToAccountID != nil {
+		return "card:" + cardID
+	}
+	if transaction.ToAccountID != nil {
+		return "account:" + *transaction.ToAccountID
+	}
+	return ""
+}
+
+func merchant(transaction *domaintransaction.Transaction) string {
+	if transaction.MerchantName != "" {
+		return transaction.MerchantName
+	}
+	return transaction.Description
+}
+
+// rank scores how much a transaction is worth keeping
+func rank(transaction *domaintransaction.Transaction) int {
+	rank := 0
+	if _, linked := transaction.Metadata["installmentPlanId"]; linked {
+		rank += 10
+	}
+	for _, filled := range []bool{
+		transaction.CategoryID != nil, transaction.MerchantName != "", transaction.Notes != "",
+		transaction.ExternalID != "", transaction.ReferenceID != "", len(transaction.Tags) > 0,
+	} {
+		if filled {
+			rank++
+		}
+	}
+	return rank
+}
+
+// containsNote checks if the notes already hold a note, merged from another duplicate or written the same
+func containsNote(notes, note string) bool {
+	for _, existing := range strings.Split(notes, "\n") {
+		if existing == note {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, existing := range tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
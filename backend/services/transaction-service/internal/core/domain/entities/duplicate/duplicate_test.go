@@ -0,0 +1,333 @@
+package duplicate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+var createdAt = time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+// purchase is a completed payment of 1500 ARS from an account at a supermarket
+func purchase(id string, minutes int) *domaintransaction.Transaction {
+	return &domaintransaction.Transaction{
+		ID:            id,
+		UserID:        "user-1",
+		Type:          domaintransaction.TransactionTypeAccountWithdraw,
+		Status:        domaintransaction.TransactionStatusCompleted,
+		Amount:        1500,
+		Currency:      "ARS",
+		FromAccountID: stringPtr("account-1"),
+		MerchantName:  "Supermercado Día",
+		CreatedAt:     createdAt.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(other *domaintransaction.Transaction)
+		config   func(config *Config)
+		expected float64
+		matches  bool
+	}{
+		{
+			name:     "same movement at the same time",
+			change:   func(other *domaintransaction.Transaction) {},
+			expected: 1,
+			matches:  true,
+		},
+		{
+			name:     "half the window apart",
+			change:   func(other *domaintransaction.Transaction) { other.CreatedAt = createdAt.Add(-5 * time.Minute) },
+			expected: 0.75,
+			matches:  true,
+		},
+		{
+			name:     "at the edge of the window",
+			change:   func(other *domaintransaction.Transaction) { other.CreatedAt = createdAt.Add(10 * time.Minute) },
+			expected: 0.5,
+			matches:  true,
+		},
+		{
+			name:   "outside the window",
+			change: func(other *domaintransaction.Transaction) { other.CreatedAt = createdAt.Add(11 * time.Minute) },
+		},
+		{
+			name:     "outside the default window but within a wider one",
+			change:   func(other *domaintransaction.Transaction) { other.CreatedAt = createdAt.Add(3 * time.Hour) },
+			config:   func(config *Config) { config.Window = 12 * time.Hour },
+			expected: 0.88,
+			matches:  true,
+		},
+		{
+			name:     "amount within half a cent",
+			change:   func(other *domaintransaction.Transaction) { other.Amount = 1500.004 },
+			expected: 1,
+			matches:  true,
+		},
+		{
+			name:   "another amount",
+			change: func(other *domaintransaction.Transaction) { other.Amount = 1500.01 },
+		},
+		{
+			name:   "another currency",
+			change: func(other *domaintransaction.Transaction) { other.Currency = "USD" },
+		},
+		{
+			name:   "another account",
+			change: func(other *domaintransaction.Transaction) { other.FromAccountID = stringPtr("account-2") },
+		},
+		{
+			name: "opposite direction",
+			change: func(other *domaintransaction.Transaction) {
+				other.Type = domaintransaction.TransactionTypeAccountDeposit
+				other.FromAccountID = nil
+				other.ToAccountID = stringPtr("account-1")
+			},
+		},
+		{
+			name: "merchant in the description",
+			change: func(other *domaintransaction.Transaction) {
+				other.MerchantName, other.Description = "", "COMPRA DEB SUPERMERCADO DIA 0042"
+			},
+			expected: 1,
+			matches:  true,
+		},
+		{
+			name:     "no merchant on one side",
+			change:   func(other *domaintransaction.Transaction) { other.MerchantName = "" },
+			expected: 0.75,
+			matches:  true,
+		},
+		{
+			name:   "different merchants",
+			change: func(other *domaintransaction.Transaction) { other.MerchantName = "YPF" },
+		},
+		{
+			name:     "different merchants without a minimum similarity",
+			change:   func(other *domaintransaction.Transaction) { other.MerchantName = "YPF" },
+			config:   func(config *Config) { config.MinSimilarity = 0 },
+			expected: 0.5,
+			matches:  true,
+		},
+		{
+			name:   "failed transaction",
+			change: func(other *domaintransaction.Transaction) { other.Status = domaintransaction.TransactionStatusFailed },
+		},
+		{
+			name:     "pending transaction",
+			change:   func(other *domaintransaction.Transaction) { other.Status = domaintransaction.TransactionStatusPending },
+			expected: 1,
+			matches:  true,
+		},
+		{
+			name:   "same transaction",
+			change: func(other *domaintransaction.Transaction) { other.ID = "tx-1" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			if tt.config != nil {
+				tt.config(&config)
+			}
+			transaction, other := purchase("tx-1", 0), purchase("tx-2", 0)
+			tt.change(other)
+
+			score, matches := Match(transaction, other, config)
+			if matches != tt.matches || score != tt.expected {
+				t.Errorf("Match() = %v, %v, want %v, %v", score, matches, tt.expected, tt.matches)
+			}
+			if reverse, reverseMatches := Match(other, transaction, config); reverse != score || reverseMatches != matches {
+				t.Errorf("Match() is not symmetric: %v, %v", reverse, reverseMatches)
+			}
+		})
+	}
+}
+
+func TestMatchCardOperations(t *testing.T) {
+	// The account service records a card purchase on the account of the card with the card in the metadata
+	recorded := purchase("tx-1", 0)
+	recorded.Metadata = map[string]interface{}{"cardId": "card-1"}
+	manual := purchase("tx-2", 1)
+	manual.FromAccountID = nil
+	manual.FromCardID = stringPtr("card-1")
+
+	if _, matches := Match(recorded, manual, DefaultConfig()); !matches {
+		t.Error("Match() of a card purchase recorded on its account and on the card expected a match")
+	}
+
+	manual.FromCardID = stringPtr("card-2")
+	if _, matches := Match(recorded, manual, DefaultConfig()); matches {
+		t.Error("Match() of purchases with different cards expected no match")
+	}
+}
+
+func TestFindCandidates(t *testing.T) {
+	transaction := purchase("tx-new", 0)
+	others := []*domaintransaction.Transaction{
+		purchase("tx-far", -8),
+		purchase("tx-other-amount", -1),
+		purchase("tx-close", -2),
+		purchase("tx-new", 0),
+	}
+	others[1].Amount = 2000
+
+	candidates := FindCandidates(transaction, others, DefaultConfig())
+
+	var ids []string
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.TransactionID)
+	}
+	if got := strings.Join(ids, ","); got != "tx-close,tx-far" {
+		t.Errorf("FindCandidates() = %s, want tx-close,tx-far", got)
+	}
+	if candidates[0].Score != 0.9 || candidates[0].MerchantName != "Supermercado Día" {
+		t.Errorf("FindCandidates() best = %+v", *candidates[0])
+	}
+
+	if candidates := FindCandidates(transaction, nil, DefaultConfig()); candidates == nil || len(candidates) != 0 {
+		t.Errorf("FindCandidates() without others = %v, want an empty list", candidates)
+	}
+}
+
+func TestFindGroups(t *testing.T) {
+	// a, b and c chain within the window although a and c are too far apart; d is another movement
+	a, b, c := purchase("a", 0), purchase("b", 8), purchase("c", 16)
+	b.CategoryID = stringPtr("category-food")
+	d := purchase("d", 17)
+	d.Amount = 320
+	d.MerchantName = "Kiosco"
+	e := purchase("e", 40)
+	e.Amount = 320
+	e.MerchantName = "Kiosco"
+	f := purchase("f", 41)
+	f.Amount = 320
+	f.MerchantName = "Kiosco"
+	f.Status = domaintransaction.TransactionStatusReversed
+	g := purchase("g", 42)
+	g.Amount = 320
+	g.MerchantName = "Kiosco"
+
+	groups := FindGroups([]*domaintransaction.Transaction{a, b, c, d, e, f, g}, DefaultConfig())
+
+	if len(groups) != 2 {
+		t.Fatalf("FindGroups() = %d groups, want 2", len(groups))
+	}
+	expected := []struct {
+		ids   string
+		keep  string
+		score float64
+	}{
+		{"a,b,c", "b", 0.6},
+		{"e,g", "e", 0.9},
+	}
+	for i, want := range expected {
+		var ids []string
+		for _, transaction := range groups[i].Transactions {
+			ids = append(ids, transaction.ID)
+		}
+		if got := strings.Join(ids, ","); got != want.ids || groups[i].SuggestedKeepID != want.keep || groups[i].Score != want.score {
+			t.Errorf("group %d = %s keeping %s with score %v, want %s keeping %s with score %v",
+				i, got, groups[i].SuggestedKeepID, groups[i].Score, want.ids, want.keep, want.score)
+		}
+	}
+}
+
+func TestSuggestKeep(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(older, newer *domaintransaction.Transaction)
+		expected string
+	}{
+		{
+			name:     "oldest when they are alike",
+			change:   func(older, newer *domaintransaction.Transaction) {},
+			expected: "older",
+		},
+		{
+			name: "more details filled in",
+			change: func(older, newer *domaintransaction.Transaction) {
+				newer.Notes, newer.Tags = "Compra del mes", []string{"hogar"}
+			},
+			expected: "newer",
+		},
+		{
+			name: "linked to an installment plan",
+			change: func(older, newer *domaintransaction.Transaction) {
+				older.Notes, older.ExternalID, older.CategoryID = "Compra del mes", "OP-1", stringPtr("category-food")
+				newer.Metadata = map[string]interface{}{"installmentPlanId": "plan-1"}
+			},
+			expected: "newer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			older, newer := purchase("older", 0), purchase("newer", 1)
+			tt.change(older, newer)
+
+			if got := SuggestKeep([]*domaintransaction.Transaction{newer, older}).ID; got != tt.expected {
+				t.Errorf("SuggestKeep() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	keep := purchase("keep", 0)
+	keep.Notes = "Compra del mes"
+	keep.Tags = []string{"hogar"}
+	keep.Metadata = map[string]interface{}{"source": "manual"}
+
+	imported := purchase("imported", 1)
+	imported.MerchantName = ""
+	imported.ExternalID = "000123"
+	imported.Notes = "Importado del resumen"
+	imported.Tags = []string{"hogar", "super"}
+	imported.Metadata = map[string]interface{}{"importBatchId": "batch-1", "recordOnly": true, "source": "import", "importRow": 3}
+
+	categorized := purchase("categorized", 2)
+	categorized.CategoryID = stringPtr("category-food")
+	categorized.CategorySource = domaintransaction.CategorySourceRule
+	categorized.Notes = "Compra del mes"
+
+	changed := Merge(keep, []*domaintransaction.Transaction{imported, categorized})
+
+	if keep.ExternalID != "000123" || keep.CategoryID == nil || *keep.CategoryID != "category-food" {
+		t.Errorf("Merge() kept %q with category %v", keep.ExternalID, keep.CategoryID)
+	}
+	if keep.Notes != "Compra del mes\nImportado del resumen" {
+		t.Errorf("Merge() notes = %q", keep.Notes)
+	}
+	if strings.Join(keep.Tags, ",") != "hogar,super" {
+		t.Errorf("Merge() tags = %v", keep.Tags)
+	}
+	if keep.Metadata["source"] != "manual" || keep.Metadata["importRow"] != 3 {
+		t.Errorf("Merge() metadata = %v", keep.Metadata)
+	}
+	if _, exists := keep.Metadata["importBatchId"]; exists {
+		t.Error("Merge() must not copy the import batch, its rollback would delete the kept transaction")
+	}
+
+	var fields []string
+	for field := range changed {
+		fields = append(fields, field)
+	}
+	for _, field := range []string{"categoryId", "externalId", "notes", "tags", "metadata.importRow"} {
+		if _, exists := changed[field]; !exists {
+			t.Errorf("Merge() changed fields %v, want %s", fields, field)
+		}
+	}
+	if len(changed) != 5 {
+		t.Errorf("Merge() changed fields %v, want 5", fields)
+	}
+}
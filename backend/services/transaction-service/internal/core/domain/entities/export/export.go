@@ -0,0 +1,104 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Formats of the transaction export
+const (
+	FormatCSV   = "csv"
+	FormatOFX   = "ofx"
+	FormatJSONL = "jsonl"
+)
+
+// Row is a transaction as exported: flattened, signed and with the labels of its category, accounts and cards
+type Row struct {
+	ID            string     `json:"id"`
+	Date          time.Time  `json:"date"`
+	ProcessedAt   *time.Time `json:"processedAt,omitempty"`
+	Type          string     `json:"type"`
+	TypeLabel     string     `json:"typeLabel"`
+	Status        string     `json:"status"`
+	Amount        float64    `json:"amount"` // Negative when money left the user
+	Currency      string     `json:"currency"`
+	Description   string     `json:"description"`
+	MerchantName  string     `json:"merchantName,omitempty"`
+	PaymentMethod string     `json:"paymentMethod,omitempty"`
+	CategoryID    *string    `json:"categoryId,omitempty"`
+	Category      string     `json:"category,omitempty"` // "Parent / Child" for subcategories
+	Tags          []string   `json:"tags"`
+
+	// Currency conversion, when the transaction was made in another currency
+	AccountCurrency  string   `json:"accountCurrency,omitempty"` // Currency of the account or card that moved the money
+	OriginalAmount   *float64 `json:"originalAmount,omitempty"`
+	OriginalCurrency string   `json:"originalCurrency,omitempty"`
+	ExchangeRate     *float64 `json:"exchangeRate,omitempty"`
+
+	FromAccountID *string `json:"fromAccountId,omitempty"`
+	FromAccount   string  `json:"fromAccount,omitempty"`
+	ToAccountID   *string `json:"toAccountId,omitempty"`
+	ToAccount     string  `json:"toAccount,omitempty"`
+	FromCardID    *string `json:"fromCardId,omitempty"`
+	FromCard      string  `json:"fromCard,omitempty"`
+	ToCardID      *string `json:"toCardId,omitempty"`
+	ToCard        string  `json:"toCard,omitempty"`
+
+	ReferenceID string `json:"referenceId,omitempty"`
+	ExternalID  string `json:"externalId,omitempty"`
+
+	Attachments []AttachmentRef `json:"attachments,omitempty"` // Receipts and invoices; the content is downloaded apart
+}
+
+// AttachmentRef identifies a file attached to an exported transaction
+type AttachmentRef struct {
+	ID          string `json:"id"`
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+}
+
+// Writer encodes rows one at a time, so exports do not hold every transaction in memory
+type Writer interface {
+	Write(row *Row) error
+	// Close writes what the format needs after the last row and flushes the output
+	Close() error
+}
+
+// Options describe the export for the formats that need it up front
+type Options struct {
+	FromDate        *time.Time
+	ToDate          *time.Time
+	DefaultCurrency string // Currency of the OFX statement; rows in other currencies carry their own
+	GeneratedAt     time.Time
+}
+
+// NewWriter creates a writer of the given format
+func NewWriter(format string, w io.Writer, options Options) (Writer, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatOFX:
+		return newOFXWriter(w, options)
+	case FormatJSONL:
+		return newJSONLWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// ContentType returns the media type and file extension of a format
+func ContentType(format string) (string, string, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return "text/csv; charset=utf-8", "csv", nil
+	case FormatOFX:
+		return "application/x-ofx", "ofx", nil
+	case FormatJSONL:
+		return "application/x-ndjson", "jsonl", nil
+	default:
+		return "", "", fmt.Errorf("unsupported export format: %s", format)
+	}
+}
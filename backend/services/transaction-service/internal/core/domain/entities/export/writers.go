@@ -0,0 +1,242 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvHeader are the columns of the CSV export
+var csvHeader = []string{
+	"id", "date", "processed_at", "type", "type_label", "status", "amount", "currency", "description",
+	"merchant", "payment_method", "category", "tags", "account_currency", "original_amount",
+	"original_currency", "exchange_rate", "from_account", "to_account", "from_card", "to_card",
+	"reference_id", "external_id", "attachments",
+}
+
+// CSVWriter writes comma separated rows with a header. The UTF-8 BOM lets spreadsheets read the accents.
+type CSVWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*CSVWriter, error) {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	return &CSVWriter{writer: writer}, nil
+}
+
+// Write writes a row
+func (c *CSVWriter) Write(row *Row) error {
+	processedAt := ""
+	if row.ProcessedAt != nil {
+		processedAt = row.ProcessedAt.Format(time.RFC3339)
+	}
+
+	return c.writer.Write([]string{
+		row.ID, row.Date.Format(time.RFC3339), processedAt, row.Type, row.TypeLabel, row.Status,
+		formatAmount(row.Amount), row.Currency, row.Description, row.MerchantName, row.PaymentMethod,
+		row.Category, strings.Join(row.Tags, ";"), row.AccountCurrency, formatOptionalAmount(row.OriginalAmount),
+		row.OriginalCurrency, formatOptionalRate(row.ExchangeRate), row.FromAccount, row.ToAccount,
+		row.FromCard, row.ToCard, row.ReferenceID, row.ExternalID, attachmentNames(row.Attachments),
+	})
+}
+
+// attachmentNames joins the file names of the attachments of a row
+func attachmentNames(attachments []AttachmentRef) string {
+	names := make([]string, len(attachments))
+	for i, attachment := range attachments {
+		names[i] = attachment.FileName
+	}
+	return strings.Join(names, ";")
+}
+
+// Close flushes the buffered rows
+func (c *CSVWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// JSONLWriter writes a JSON object per line
+type JSONLWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *JSONLWriter {
+	buffer := bufio.NewWriter(w)
+	return &JSONLWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+}
+
+// Write writes a row
+func (j *JSONLWriter) Write(row *Row) error {
+	if row.Tags == nil {
+		row.Tags = []string{}
+	}
+	return j.encoder.Encode(row)
+}
+
+// Close flushes the buffered rows
+func (j *JSONLWriter) Close() error {
+	return j.buffer.Flush()
+}
+
+// OFXWriter writes an OFX 2 bank statement. The period comes from the date filters, since the
+// transactions are written as they are read.
+type OFXWriter struct {
+	buffer *bufio.Writer
+}
+
+func newOFXWriter(w io.Writer, options Options) (*OFXWriter, error) {
+	currency := options.DefaultCurrency
+	if currency == "" {
+		currency = "ARS"
+	}
+	generatedAt := options.GeneratedAt
+	if generatedAt.IsZero() {
+		generatedAt = time.Now()
+	}
+	start := time.Unix(0, 0)
+	if options.FromDate != nil {
+		start = *options.FromDate
+	}
+	end := generatedAt
+	if options.ToDate != nil {
+		end = *options.ToDate
+	}
+
+	buffer := bufio.NewWriter(w)
+	_, err := fmt.Fprintf(buffer, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><DTSERVER>%s</DTSERVER><LANGUAGE>SPA</LANGUAGE></SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>%d</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS><CURDEF>%s</CURDEF>
+<BANKACCTFROM><BANKID>FINTRACK</BANKID><ACCTID>FINTRACK</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>
+`, ofxDate(generatedAt), generatedAt.UnixNano(), escapeOFX(currency), ofxDate(start), ofxDate(end))
+	if err != nil {
+		return nil, err
+	}
+	return &OFXWriter{buffer: buffer}, nil
+}
+
+// Write writes a row as a STMTTRN entry
+func (o *OFXWriter) Write(row *Row) error {
+	name := row.MerchantName
+	if name == "" {
+		name = row.Description
+	}
+	if name == "" {
+		name = row.TypeLabel
+	}
+	name = truncateRunes(name, 32)
+
+	// The memo keeps the whole description when the name does not
+	var memo []string
+	if row.Description != "" && row.Description != name {
+		memo = append(memo, row.Description)
+	}
+	for _, label := range []string{row.FromAccount, row.FromCard, row.ToAccount, row.ToCard} {
+		if label != "" {
+			memo = append(memo, label)
+			break
+		}
+	}
+	if row.Category != "" {
+		memo = append(memo, row.Category)
+	}
+	if len(row.Tags) > 0 {
+		memo = append(memo, "#"+strings.Join(row.Tags, " #"))
+	}
+
+	entry := fmt.Sprintf("<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%s</FITID>",
+		ofxTransactionType(row), ofxDate(row.Date), formatAmount(row.Amount), escapeOFX(row.ID))
+	if row.ReferenceID != "" {
+		entry += "<REFNUM>" + escapeOFX(truncateRunes(row.ReferenceID, 32)) + "</REFNUM>"
+	}
+	entry += "<NAME>" + escapeOFX(name) + "</NAME>"
+	if len(memo) > 0 {
+		entry += "<MEMO>" + escapeOFX(truncateRunes(strings.Join(memo, " | "), 255)) + "</MEMO>"
+	}
+	if row.ExchangeRate != nil {
+		entry += fmt.Sprintf("<CURRENCY><CURRATE>%s</CURRATE><CURSYM>%s</CURSYM></CURRENCY>",
+			formatOptionalRate(row.ExchangeRate), escapeOFX(row.Currency))
+	}
+	entry += "</STMTTRN>\n"
+
+	_, err := o.buffer.WriteString(entry)
+	return err
+}
+
+// Close ends the statement and flushes the output
+func (o *OFXWriter) Close() error {
+	if _, err := o.buffer.WriteString("</BANKTRANLIST>\n</STMTRS></STMTTRNRS></BANKMSGSRSV1>\n</OFX>\n"); err != nil {
+		return err
+	}
+	return o.buffer.Flush()
+}
+
+// ofxTransactionType maps the sign and kind of a row to an OFX transaction type
+func ofxTransactionType(row *Row) string {
+	switch {
+	case strings.Contains(row.Type, "transfer"):
+		return "XFER"
+	case strings.Contains(row.Type, "withdraw"):
+		return "ATM"
+	case row.PaymentMethod == "credit_card" || row.PaymentMethod == "debit_card":
+		if row.Amount < 0 {
+			return "POS"
+		}
+		return "CREDIT"
+	case row.Amount < 0:
+		return "DEBIT"
+	default:
+		return "CREDIT"
+	}
+}
+
+var ofxEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func escapeOFX(value string) string {
+	return ofxEscaper.Replace(value)
+}
+
+func ofxDate(date time.Time) string {
+	return date.Format("20060102150405")
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func formatOptionalAmount(amount *float64) string {
+	if amount == nil {
+		return ""
+	}
+	return formatAmount(*amount)
+}
+
+func formatOptionalRate(rate *float64) string {
+	if rate == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*rate, 'f', -1, 64)
+}
+
+func truncateRunes(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
@@ -0,0 +1,213 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+var argentina = time.FixedZone("ART", -3*60*60)
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+// exportRows are a card purchase with characters to escape, a purchase in dollars, a salary and a
+// transfer between own accounts
+func exportRows() []*Row {
+	processedAt := time.Date(2025, 3, 11, 9, 0, 0, 0, argentina)
+	return []*Row{
+		{
+			ID:              "tx-1",
+			Date:            time.Date(2025, 3, 10, 14, 30, 0, 0, argentina),
+			Type:            "credit_charge",
+			TypeLabel:       "Credit Card Charge",
+			Status:          "completed",
+			Amount:          -1500.5,
+			Currency:        "ARS",
+			Description:     `Compra "semanal", con coma`,
+			MerchantName:    "Día & Cía <Centro>",
+			PaymentMethod:   "credit_card",
+			CategoryID:      stringPtr("cat-almacen"),
+			Category:        "Supermercado / Almacén",
+			Tags:            []string{"super", "hogar"},
+			AccountCurrency: "ARS",
+			FromCardID:      stringPtr("card-1"),
+			FromCard:        "Visa Galicia",
+			ReferenceID:     "ref-1",
+			Attachments:     []AttachmentRef{{ID: "att-1", FileName: "ticket.jpg", ContentType: "image/jpeg", Size: 2048}},
+		},
+		{
+			ID:               "tx-2",
+			Date:             time.Date(2025, 3, 12, 23, 5, 0, 0, argentina),
+			Type:             "debit_purchase",
+			TypeLabel:        "Debit Card Purchase",
+			Status:           "completed",
+			Amount:           -10,
+			Currency:         "USD",
+			Description:      "Suscripción anual",
+			MerchantName:     "GitHub",
+			AccountCurrency:  "ARS",
+			OriginalAmount:   floatPtr(10),
+			OriginalCurrency: "USD",
+			ExchangeRate:     floatPtr(1050.5),
+			FromAccountID:    stringPtr("acc-1"),
+			FromAccount:      "Banco Galicia",
+			ExternalID:       "ext-2",
+		},
+		{
+			ID:              "tx-3",
+			Date:            time.Date(2025, 3, 31, 10, 0, 0, 0, argentina),
+			ProcessedAt:     &processedAt,
+			Type:            "account_deposit",
+			TypeLabel:       "Account Deposit",
+			Status:          "completed",
+			Amount:          250000,
+			Currency:        "ARS",
+			Description:     "Sueldo marzo",
+			PaymentMethod:   "bank_transfer",
+			AccountCurrency: "ARS",
+			ToAccountID:     stringPtr("acc-1"),
+			ToAccount:       "Banco Galicia",
+		},
+		{
+			ID:              "tx-4",
+			Date:            time.Date(2025, 3, 31, 18, 0, 0, 0, argentina),
+			Type:            "account_transfer",
+			TypeLabel:       "Account Transfer",
+			Status:          "pending",
+			Amount:          -5000,
+			Currency:        "ARS",
+			Description:     "Ahorro para las vacaciones de invierno en Bariloche",
+			AccountCurrency: "ARS",
+			FromAccountID:   stringPtr("acc-1"),
+			FromAccount:     "Banco Galicia",
+			ToAccountID:     stringPtr("acc-2"),
+			ToAccount:       "Caja de ahorro",
+		},
+	}
+}
+
+// writeRows exports the rows in a format and returns the output
+func writeRows(t *testing.T, format string) string {
+	t.Helper()
+	fromDate := time.Date(2025, 3, 1, 0, 0, 0, 0, argentina)
+	toDate := time.Date(2025, 3, 31, 23, 59, 59, 0, argentina)
+
+	var output bytes.Buffer
+	writer, err := NewWriter(format, &output, Options{
+		FromDate:        &fromDate,
+		ToDate:          &toDate,
+		DefaultCurrency: "ARS",
+		GeneratedAt:     time.Date(2025, 4, 1, 8, 0, 0, 0, argentina),
+	})
+	if err != nil {
+		t.Fatalf("NewWriter(%q) unexpected error: %v", format, err)
+	}
+	for _, row := range exportRows() {
+		if err := writer.Write(row); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+	return output.String()
+}
+
+// assertGolden compares an export line by line, so a difference points to the row that changed
+func assertGolden(t *testing.T, got, expected string) {
+	t.Helper()
+	gotLines := bytes.Split([]byte(got), []byte("\n"))
+	expectedLines := bytes.Split([]byte(expected), []byte("\n"))
+	for i := 0; i < len(gotLines) || i < len(expectedLines); i++ {
+		var gotLine, expectedLine []byte
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if i < len(expectedLines) {
+			expectedLine = expectedLines[i]
+		}
+		if !bytes.Equal(gotLine, expectedLine) {
+			t.Errorf("line %d:\n got: %s\nwant: %s", i+1, gotLine, expectedLine)
+		}
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	expected := "\ufeff" +
+		"id,date,processed_at,type,type_label,status,amount,currency,description,merchant,payment_method,category,tags,account_currency,original_amount,original_currency,exchange_rate,from_account,to_account,from_card,to_card,reference_id,external_id,attachments\n" +
+		`tx-1,2025-03-10T14:30:00-03:00,,credit_charge,Credit Card Charge,completed,-1500.50,ARS,"Compra ""semanal"", con coma",Día & Cía <Centro>,credit_card,Supermercado / Almacén,super;hogar,ARS,,,,,,Visa Galicia,,ref-1,,ticket.jpg` + "\n" +
+		`tx-2,2025-03-12T23:05:00-03:00,,debit_purchase,Debit Card Purchase,completed,-10.00,USD,Suscripción anual,GitHub,,,,ARS,10.00,USD,1050.5,Banco Galicia,,,,,ext-2,` + "\n" +
+		`tx-3,2025-03-31T10:00:00-03:00,2025-03-11T09:00:00-03:00,account_deposit,Account Deposit,completed,250000.00,ARS,Sueldo marzo,,bank_transfer,,,ARS,,,,,Banco Galicia,,,,,` + "\n" +
+		`tx-4,2025-03-31T18:00:00-03:00,,account_transfer,Account Transfer,pending,-5000.00,ARS,Ahorro para las vacaciones de invierno en Bariloche,,,,,ARS,,,,Banco Galicia,Caja de ahorro,,,,,` + "\n"
+
+	assertGolden(t, writeRows(t, FormatCSV), expected)
+}
+
+func TestJSONLWriter(t *testing.T) {
+	expected := `{"id":"tx-1","date":"2025-03-10T14:30:00-03:00","type":"credit_charge","typeLabel":"Credit Card Charge","status":"completed","amount":-1500.5,"currency":"ARS","description":"Compra \"semanal\", con coma","merchantName":"Día \u0026 Cía \u003cCentro\u003e","paymentMethod":"credit_card","categoryId":"cat-almacen","category":"Supermercado / Almacén","tags":["super","hogar"],"accountCurrency":"ARS","fromCardId":"card-1","fromCard":"Visa Galicia","referenceId":"ref-1","attachments":[{"id":"att-1","fileName":"ticket.jpg","contentType":"image/jpeg","size":2048}]}
+{"id":"tx-2","date":"2025-03-12T23:05:00-03:00","type":"debit_purchase","typeLabel":"Debit Card Purchase","status":"completed","amount":-10,"currency":"USD","description":"Suscripción anual","merchantName":"GitHub","tags":[],"accountCurrency":"ARS","originalAmount":10,"originalCurrency":"USD","exchangeRate":1050.5,"fromAccountId":"acc-1","fromAccount":"Banco Galicia","externalId":"ext-2"}
+{"id":"tx-3","date":"2025-03-31T10:00:00-03:00","processedAt":"2025-03-11T09:00:00-03:00","type":"account_deposit","typeLabel":"Account Deposit","status":"completed","amount":250000,"currency":"ARS","description":"Sueldo marzo","paymentMethod":"bank_transfer","tags":[],"accountCurrency":"ARS","toAccountId":"acc-1","toAccount":"Banco Galicia"}
+{"id":"tx-4","date":"2025-03-31T18:00:00-03:00","type":"account_transfer","typeLabel":"Account Transfer","status":"pending","amount":-5000,"currency":"ARS","description":"Ahorro para las vacaciones de invierno en Bariloche","tags":[],"accountCurrency":"ARS","fromAccountId":"acc-1","fromAccount":"Banco Galicia","toAccountId":"acc-2","toAccount":"Caja de ahorro"}
+`
+
+	assertGolden(t, writeRows(t, FormatJSONL), expected)
+}
+
+func TestOFXWriter(t *testing.T) {
+	expected := `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><DTSERVER>20250401080000</DTSERVER><LANGUAGE>SPA</LANGUAGE></SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>1743505200000000000</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS><CURDEF>ARS</CURDEF>
+<BANKACCTFROM><BANKID>FINTRACK</BANKID><ACCTID>FINTRACK</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST><DTSTART>20250301000000</DTSTART><DTEND>20250331235959</DTEND>
+<STMTTRN><TRNTYPE>POS</TRNTYPE><DTPOSTED>20250310143000</DTPOSTED><TRNAMT>-1500.50</TRNAMT><FITID>tx-1</FITID><REFNUM>ref-1</REFNUM><NAME>Día &amp; Cía &lt;Centro&gt;</NAME><MEMO>Compra "semanal", con coma | Visa Galicia | Supermercado / Almacén | #super #hogar</MEMO></STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20250312230500</DTPOSTED><TRNAMT>-10.00</TRNAMT><FITID>tx-2</FITID><NAME>GitHub</NAME><MEMO>Suscripción anual | Banco Galicia</MEMO><CURRENCY><CURRATE>1050.5</CURRATE><CURSYM>USD</CURSYM></CURRENCY></STMTTRN>
+<STMTTRN><TRNTYPE>CREDIT</TRNTYPE><DTPOSTED>20250331100000</DTPOSTED><TRNAMT>250000.00</TRNAMT><FITID>tx-3</FITID><NAME>Sueldo marzo</NAME><MEMO>Banco Galicia</MEMO></STMTTRN>
+<STMTTRN><TRNTYPE>XFER</TRNTYPE><DTPOSTED>20250331180000</DTPOSTED><TRNAMT>-5000.00</TRNAMT><FITID>tx-4</FITID><NAME>Ahorro para las vacaciones de in</NAME><MEMO>Ahorro para las vacaciones de invierno en Bariloche | Banco Galicia</MEMO></STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+	assertGolden(t, writeRows(t, FormatOFX), expected)
+}
+
+func TestOFXTransactionType(t *testing.T) {
+	tests := []struct {
+		name     string
+		row      Row
+		expected string
+	}{
+		{name: "transfer", row: Row{Type: "wallet_transfer", Amount: 100}, expected: "XFER"},
+		{name: "withdrawal", row: Row{Type: "account_withdraw", Amount: -100}, expected: "ATM"},
+		{name: "card purchase", row: Row{Type: "debit_purchase", PaymentMethod: "debit_card", Amount: -100}, expected: "POS"},
+		{name: "card refund", row: Row{Type: "credit_refund", PaymentMethod: "credit_card", Amount: 100}, expected: "CREDIT"},
+		{name: "money out", row: Row{Type: "installment_payment", Amount: -100}, expected: "DEBIT"},
+		{name: "money in", row: Row{Type: "wallet_deposit", Amount: 100}, expected: "CREDIT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ofxTransactionType(&tt.row); got != tt.expected {
+				t.Errorf("ofxTransactionType() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewWriterUnsupportedFormat(t *testing.T) {
+	if _, err := NewWriter("xlsx", &bytes.Buffer{}, Options{}); err == nil {
+		t.Error("NewWriter(xlsx) expected error but got none")
+	}
+	if _, _, err := ContentType("xlsx"); err == nil {
+		t.Error("ContentType(xlsx) expected error but got none")
+	}
+}
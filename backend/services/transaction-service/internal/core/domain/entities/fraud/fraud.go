@@ -0,0 +1,344 @@
+package fraud
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// Action is what happens to an incoming transaction given its risk score
+type Action string
+
+const (
+	ActionAllow Action = "allow" // Executed normally
+	ActionAlert Action = "alert" // Executed, and the user is notified
+	ActionHold  Action = "hold"  // Saved as pending without moving money until the user releases it
+	ActionBlock Action = "block" // Saved as failed
+)
+
+// Signal is a rule that adds points to the risk score of a transaction
+type Signal string
+
+const (
+	SignalAmountOutlier    Signal = "amount_outlier"    // Far above the amounts the user usually moves
+	SignalNewMerchant      Signal = "new_merchant"      // First transaction with the merchant
+	SignalUnusualHour      Signal = "unusual_hour"      // In the quiet hours, when the user does not usually operate
+	SignalVelocity         Signal = "velocity"          // Too many transactions in a few minutes
+	SignalNewCard          Signal = "new_card"          // First use of the card
+	SignalCurrencyMismatch Signal = "currency_mismatch" // Currency other than the one of the account or card
+)
+
+// Points of each signal; the score is their sum, capped at MaxScore
+const (
+	amountOutlierPoints       = 25
+	amountOutlierSeverePoints = 35 // Twice the configured deviation
+	newMerchantPoints         = 15
+	unusualHourPoints         = 15
+	velocityPoints            = 30
+	newCardPoints             = 20
+	currencyMismatchPoints    = 20
+
+	// MaxScore is the highest risk score
+	MaxScore = 100
+)
+
+const (
+	// MinHistory is the number of previous transactions needed before the amount, merchant and hour
+	// of a transaction are compared against the habits of the user
+	MinHistory = 10
+	// usualHourShare is the share of the previous transactions in an hour (or the next to it) from
+	// which the user is considered to usually operate at that time, even in the quiet hours
+	usualHourShare = 0.05
+	// minDeviationRatio floors the standard deviation of the amounts as a ratio of their average, so
+	// a user who always pays the same amount is not flagged for a few cents more
+	minDeviationRatio = 0.1
+)
+
+// Settings are the thresholds of the risk scoring of a user
+type Settings struct {
+	UserID                string    `json:"userId"`
+	Enabled               bool      `json:"enabled"`
+	AlertThreshold        int       `json:"alertThreshold"`        // Score from which the user is notified, 0 disables it
+	HoldThreshold         int       `json:"holdThreshold"`         // Score from which the transaction is held, 0 disables it
+	BlockThreshold        int       `json:"blockThreshold"`        // Score from which the transaction is blocked, 0 disables it
+	AmountDeviation       float64   `json:"amountDeviation"`       // Standard deviations above the average amount that count as an outlier
+	VelocityCount         int       `json:"velocityCount"`         // Transactions within the velocity window that count as too many
+	VelocityWindowMinutes int       `json:"velocityWindowMinutes"` // Window of the velocity signal
+	QuietHoursStart       int       `json:"quietHoursStart"`       // First quiet hour (0-23)
+	QuietHoursEnd         int       `json:"quietHoursEnd"`         // Hour the quiet hours end (0-23, exclusive); equal to the start disables them
+	CreatedAt             time.Time `json:"createdAt"`
+	UpdatedAt             time.Time `json:"updatedAt"`
+}
+
+// DefaultSettings returns the settings of a user who did not configure them: alert from 40 points,
+// hold from 70 and block from 90
+func DefaultSettings(userID string) *Settings {
+	return &Settings{
+		UserID:                userID,
+		Enabled:               true,
+		AlertThreshold:        40,
+		HoldThreshold:         70,
+		BlockThreshold:        90,
+		AmountDeviation:       3,
+		VelocityCount:         5,
+		VelocityWindowMinutes: 10,
+		QuietHoursStart:       0,
+		QuietHoursEnd:         6,
+	}
+}
+
+// Validate checks the thresholds are within range and in order
+func (s *Settings) Validate() error {
+	thresholds := []struct {
+		name  string
+		value int
+	}{
+		{"alertThreshold", s.AlertThreshold},
+		{"holdThreshold", s.HoldThreshold},
+		{"blockThreshold", s.BlockThreshold},
+	}
+	previous := 0
+	for _, threshold := range thresholds {
+		if threshold.value < 0 || threshold.value > MaxScore {
+			return fmt.Errorf("%s must be between 0 and %d", threshold.name, MaxScore)
+		}
+		if threshold.value == 0 {
+			continue
+		}
+		if threshold.value < previous {
+			return errors.New("thresholds must be in order: alert, hold and block")
+		}
+		previous = threshold.value
+	}
+
+	if s.AmountDeviation <= 0 {
+		return errors.New("amountDeviation must be greater than 0")
+	}
+	if s.VelocityCount < 2 {
+		return errors.New("velocityCount must be at least 2")
+	}
+	if s.VelocityWindowMinutes <= 0 || s.VelocityWindowMinutes > 24*60 {
+		return errors.New("velocityWindowMinutes must be between 1 and 1440")
+	}
+	if s.QuietHoursStart < 0 || s.QuietHoursStart > 23 || s.QuietHoursEnd < 0 || s.QuietHoursEnd > 23 {
+		return errors.New("quiet hours must be between 0 and 23")
+	}
+	return nil
+}
+
+// ActionFor returns the action of the highest threshold a score reaches
+func (s *Settings) ActionFor(score int) Action {
+	switch {
+	case s.BlockThreshold > 0 && score >= s.BlockThreshold:
+		return ActionBlock
+	case s.HoldThreshold > 0 && score >= s.HoldThreshold:
+		return ActionHold
+	case s.AlertThreshold > 0 && score >= s.AlertThreshold:
+		return ActionAlert
+	default:
+		return ActionAllow
+	}
+}
+
+// isQuietHour checks if an hour is within the quiet hours, which can wrap around midnight
+func (s *Settings) isQuietHour(hour int) bool {
+	if s.QuietHoursStart == s.QuietHoursEnd {
+		return false
+	}
+	if s.QuietHoursStart < s.QuietHoursEnd {
+		return hour >= s.QuietHoursStart && hour < s.QuietHoursEnd
+	}
+	return hour >= s.QuietHoursStart || hour < s.QuietHoursEnd
+}
+
+// Reason is a signal that contributed to a risk score
+type Reason struct {
+	Signal Signal `json:"signal"`
+	Points int    `json:"points"`
+	Detail string `json:"detail"`
+}
+
+// Score is the risk assessment of a transaction, persisted with the reasons that made it up
+type Score struct {
+	ID            string    `json:"id"`
+	TransactionID string    `json:"transactionId"`
+	UserID        string    `json:"userId"`
+	Score         int       `json:"score"`
+	Action        Action    `json:"action"`
+	Reasons       []Reason  `json:"reasons"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// Profile is what is known about the user and the instrument used when a transaction comes in
+type Profile struct {
+	Transactions       int             // Previous completed transactions
+	Amounts            []float64       // Amounts of the previous completed transactions in the currency of the transaction
+	Merchants          map[string]bool // Merchant IDs and normalized names of the previous completed transactions
+	Cards              map[string]bool // Cards used in the previous completed transactions
+	HourCounts         [24]int         // Previous completed transactions by hour of the day
+	Recent             int             // Previous transactions of any status within the velocity window
+	InstrumentCurrency string          // Currency of the account or card used, empty when unknown
+}
+
+// BuildProfile summarizes the previous transactions of the user for the transaction being scored
+func BuildProfile(transaction *domaintransaction.Transaction, previous []*domaintransaction.Transaction, settings *Settings, now time.Time) *Profile {
+	profile := &Profile{
+		Merchants: make(map[string]bool),
+		Cards:     make(map[string]bool),
+	}
+	velocitySince := now.Add(-time.Duration(settings.VelocityWindowMinutes) * time.Minute)
+
+	for _, other := range previous {
+		if other.ID == transaction.ID && transaction.ID != "" {
+			continue
+		}
+		if !other.CreatedAt.Before(velocitySince) {
+			profile.Recent++
+		}
+		if other.Status != domaintransaction.TransactionStatusCompleted {
+			continue
+		}
+
+		profile.Transactions++
+		profile.HourCounts[other.CreatedAt.Hour()]++
+		if other.Currency == transaction.Currency {
+			profile.Amounts = append(profile.Amounts, other.Amount)
+		}
+		for _, key := range merchantKeys(other) {
+			profile.Merchants[key] = true
+		}
+		if cardID := CardID(other); cardID != "" {
+			profile.Cards[cardID] = true
+		}
+	}
+
+	return profile
+}
+
+// Evaluate scores a transaction against the profile of the user. The hour is the one of now in the
+// local time of the service.
+func Evaluate(transaction *domaintransaction.Transaction, profile *Profile, settings *Settings, now time.Time) *Score {
+	score := &Score{
+		TransactionID: transaction.ID,
+		UserID:        transaction.UserID,
+		Reasons:       []Reason{},
+		CreatedAt:     now,
+	}
+	add := func(signal Signal, points int, detail string) {
+		score.Reasons = append(score.Reasons, Reason{Signal: signal, Points: points, Detail: detail})
+		score.Score += points
+	}
+
+	if profile.Transactions >= MinHistory && len(profile.Amounts) >= MinHistory {
+		average, deviation := meanAndDeviation(profile.Amounts)
+		deviation = math.Max(deviation, average*minDeviationRatio)
+		if deviation > 0 {
+			deviations := (transaction.Amount - average) / deviation
+			if deviations >= settings.AmountDeviation {
+				points := amountOutlierPoints
+				if deviations >= 2*settings.AmountDeviation {
+					points = amountOutlierSeverePoints
+				}
+				add(SignalAmountOutlier, points, fmt.Sprintf("%.2f %s is %.1f standard deviations above the average of %.2f",
+					transaction.Amount, transaction.Currency, deviations, average))
+			}
+		}
+	}
+
+	if keys := merchantKeys(transaction); len(keys) > 0 && profile.Transactions >= MinHistory && !profile.knowsMerchant(keys) {
+		add(SignalNewMerchant, newMerchantPoints, fmt.Sprintf("First transaction with %s", displayMerchant(transaction)))
+	}
+
+	if hour := now.Hour(); settings.isQuietHour(hour) && !profile.usuallyOperatesAt(hour) {
+		add(SignalUnusualHour, unusualHourPoints, fmt.Sprintf("Made at %02d:%02d, within the quiet hours", hour, now.Minute()))
+	}
+
+	if count := profile.Recent + 1; count >= settings.VelocityCount {
+		add(SignalVelocity, velocityPoints, fmt.Sprintf("%d transactions in the last %d minutes", count, settings.VelocityWindowMinutes))
+	}
+
+	if cardID := CardID(transaction); cardID != "" && profile.Transactions > 0 && !profile.Cards[cardID] {
+		add(SignalNewCard, newCardPoints, fmt.Sprintf("First use of card %s", cardID))
+	}
+
+	if profile.InstrumentCurrency != "" && !strings.EqualFold(profile.InstrumentCurrency, transaction.Currency) {
+		add(SignalCurrencyMismatch, currencyMismatchPoints, fmt.Sprintf("Currency %s on an account in %s", transaction.Currency, profile.InstrumentCurrency))
+	}
+
+	if score.Score > MaxScore {
+		score.Score = MaxScore
+	}
+	score.Action = settings.ActionFor(score.Score)
+	return score
+}
+
+// usuallyOperatesAt checks if enough of the previous transactions were made at an hour or next to it
+func (p *Profile) usuallyOperatesAt(hour int) bool {
+	if p.Transactions < MinHistory {
+		return false
+	}
+	count := p.HourCounts[(hour+23)%24] + p.HourCounts[hour] + p.HourCounts[(hour+1)%24]
+	return float64(count)/float64(p.Transactions) >= usualHourShare
+}
+
+// CardID returns the card a transaction was made with. The account service records card operations on
+// the account of the card with the card in the metadata.
+func CardID(transaction *domaintransaction.Transaction) string {
+	if transaction.FromCardID != nil {
+		return *transaction.FromCardID
+	}
+	if transaction.ToCardID != nil {
+		return *transaction.ToCardID
+	}
+	if cardID, ok := transaction.Metadata["cardId"].(string); ok {
+		return cardID
+	}
+	return ""
+}
+
+// knowsMerchant checks if any of the keys of a merchant was seen before
+func (p *Profile) knowsMerchant(keys []string) bool {
+	for _, key := range keys {
+		if p.Merchants[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// merchantKeys identifies the merchant of a transaction by its ID and its normalized name
+func merchantKeys(transaction *domaintransaction.Transaction) []string {
+	var keys []string
+	if transaction.MerchantID != "" {
+		keys = append(keys, "id:"+transaction.MerchantID)
+	}
+	if name := strings.ToLower(strings.Join(strings.Fields(transaction.MerchantName), " ")); name != "" {
+		keys = append(keys, "name:"+name)
+	}
+	return keys
+}
+
+func displayMerchant(transaction *domaintransaction.Transaction) string {
+	if transaction.MerchantName != "" {
+		return transaction.MerchantName
+	}
+	return transaction.MerchantID
+}
+
+func meanAndDeviation(values []float64) (float64, float64) {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
@@ -0,0 +1,425 @@
+package fraud
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// now is the time the transactions of the tests come in, outside the default quiet hours
+var now = time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+// payment is a completed debit card purchase at a supermarket
+func payment(id string, amount float64, createdAt time.Time) *domaintransaction.Transaction {
+	return &domaintransaction.Transaction{
+		ID:           id,
+		UserID:       "user-1",
+		Type:         domaintransaction.TransactionTypeDebitPurchase,
+		Status:       domaintransaction.TransactionStatusCompleted,
+		Amount:       amount,
+		Currency:     "ARS",
+		FromCardID:   stringPtr("card-1"),
+		MerchantName: "Supermercado Día",
+		CreatedAt:    createdAt,
+	}
+}
+
+// history is a user who paid 900 or 1100 ARS at 14:00 on each of the previous days: an average of 1000
+// with a standard deviation of 100
+func history(days int) []*domaintransaction.Transaction {
+	var transactions []*domaintransaction.Transaction
+	for i := 1; i <= days; i++ {
+		amount := 900.0
+		if i%2 == 0 {
+			amount = 1100
+		}
+		transactions = append(transactions, payment(fmt.Sprintf("tx-%d", i), amount, now.AddDate(0, 0, -i).Add(-time.Hour)))
+	}
+	return transactions
+}
+
+func signals(score *Score) string {
+	var names []string
+	for _, reason := range score.Reasons {
+		names = append(names, string(reason.Signal))
+	}
+	return strings.Join(names, ",")
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name            string
+		change          func(transaction *domaintransaction.Transaction)
+		previous        func(previous []*domaintransaction.Transaction) []*domaintransaction.Transaction
+		profile         func(profile *Profile)
+		at              time.Time
+		expectedScore   int
+		expectedSignals string
+		expectedAction  Action
+	}{
+		{
+			name:           "usual transaction",
+			change:         func(transaction *domaintransaction.Transaction) {},
+			expectedAction: ActionAllow,
+		},
+		{
+			name:           "just below the amount deviation",
+			change:         func(transaction *domaintransaction.Transaction) { transaction.Amount = 1299 },
+			expectedAction: ActionAllow,
+		},
+		{
+			name:            "amount outlier",
+			change:          func(transaction *domaintransaction.Transaction) { transaction.Amount = 1300 },
+			expectedScore:   25,
+			expectedSignals: "amount_outlier",
+			expectedAction:  ActionAllow,
+		},
+		{
+			name:            "amount outlier at twice the deviation",
+			change:          func(transaction *domaintransaction.Transaction) { transaction.Amount = 1600 },
+			expectedScore:   35,
+			expectedSignals: "amount_outlier",
+			expectedAction:  ActionAllow,
+		},
+		{
+			name:   "user who always pays the same amount",
+			change: func(transaction *domaintransaction.Transaction) { transaction.Amount = 1300 },
+			previous: func(previous []*domaintransaction.Transaction) []*domaintransaction.Transaction {
+				for _, transaction := range previous {
+					transaction.Amount = 1000
+				}
+				return previous
+			},
+			expectedScore:   25,
+			expectedSignals: "amount_outlier",
+			expectedAction:  ActionAllow,
+		},
+		{
+			name: "amounts in another currency are not compared",
+			change: func(transaction *domaintransaction.Transaction) {
+				transaction.Amount, transaction.Currency = 1600, "USD"
+			},
+			expectedAction: ActionAllow,
+		},
+		{
+			name:            "new merchant",
+			change:          func(transaction *domaintransaction.Transaction) { transaction.MerchantName = "YPF" },
+			expectedScore:   15,
+			expectedSignals: "new_merchant",
+			expectedAction:  ActionAllow,
+		},
+		{
+			name:           "known merchant written otherwise",
+			change:         func(transaction *domaintransaction.Transaction) { transaction.MerchantName = "  SUPERMERCADO   DÍA " },
+			expectedAction: ActionAllow,
+		},
+		{
+			name: "known merchant ID under another name",
+			change: func(transaction *domaintransaction.Transaction) {
+				transaction.MerchantName, transaction.MerchantID = "DIA %Argentina", "merchant-dia"
+			},
+			previous: func(previous []*domaintransaction.Transaction) []*domaintransaction.Transaction {
+				previous[0].MerchantID = "merchant-dia"
+				return previous
+			},
+			expectedAction: ActionAllow,
+		},
+		{
+			name: "short history is not compared",
+			change: func(transaction *domaintransaction.Transaction) {
+				transaction.Amount, transaction.MerchantName = 5000, "YPF"
+			},
+			previous: func(previous []*domaintransaction.Transaction) []*domaintransaction.Transaction {
+				return previous[:MinHistory-1]
+			},
+			expectedAction: ActionAllow,
+		},
+		{
+			name:            "quiet hours",
+			change:          func(transaction *domaintransaction.Transaction) {},
+			at:              time.Date(2025, 3, 10, 3, 30, 0, 0, time.UTC),
+			expectedScore:   15,
+			expectedSignals: "unusual_hour",
+			expectedAction:  ActionAllow,
+		},
+		{
+			name:   "quiet hours the user usually operates at",
+			change: func(transaction *domaintransaction.Transaction) {},
+			previous: func(previous []*domaintransaction.Transaction) []*domaintransaction.Transaction {
+				previous[0].CreatedAt = time.Date(2025, 3, 9, 2, 10, 0, 0, time.UTC)
+				return previous
+			},
+			at:             time.Date(2025, 3, 10, 3, 30, 0, 0, time.UTC),
+			expectedAction: ActionAllow,
+		},
+		{
+			name:   "velocity",
+			change: func(transaction *domaintransaction.Transaction) {},
+			previous: func(previous []*domaintransaction.Transaction) []*domaintransaction.Transaction {
+				for i := 1; i <= 4; i++ {
+					recent := payment(fmt.Sprintf("recent-%d", i), 1000, now.Add(-time.Duration(i*2)*time.Minute))
+					if i == 4 {
+						recent.Status = domaintransaction.TransactionStatusFailed
+					}
+					previous = append(previous, recent)
+				}
+				return previous
+			},
+			expectedScore:   30,
+			expectedSignals: "velocity",
+			expectedAction:  ActionAllow,
+		},
+		{
+			name:   "below the velocity count",
+			change: func(transaction *domaintransaction.Transaction) {},
+			previous: func(previous []*domaintransaction.Transaction) []*domaintransaction.Transaction {
+				for i := 1; i <= 3; i++ {
+					previous = append(previous, payment(fmt.Sprintf("recent-%d", i), 1000, now.Add(-time.Duration(i*2)*time.Minute)))
+				}
+				return append(previous, payment("earlier", 1000, now.Add(-11*time.Minute)))
+			},
+			expectedAction: ActionAllow,
+		},
+		{
+			name:            "new card",
+			change:          func(transaction *domaintransaction.Transaction) { transaction.FromCardID = stringPtr("card-2") },
+			expectedScore:   20,
+			expectedSignals: "new_card",
+			expectedAction:  ActionAllow,
+		},
+		{
+			name:           "first transaction of the user with a card",
+			change:         func(transaction *domaintransaction.Transaction) { transaction.FromCardID = stringPtr("card-2") },
+			previous:       func(previous []*domaintransaction.Transaction) []*domaintransaction.Transaction { return nil },
+			expectedAction: ActionAllow,
+		},
+		{
+			name:            "currency other than the one of the card",
+			change:          func(transaction *domaintransaction.Transaction) {},
+			profile:         func(profile *Profile) { profile.InstrumentCurrency = "USD" },
+			expectedScore:   20,
+			expectedSignals: "currency_mismatch",
+			expectedAction:  ActionAllow,
+		},
+		{
+			name:           "currency of the card in another case",
+			change:         func(transaction *domaintransaction.Transaction) {},
+			profile:        func(profile *Profile) { profile.InstrumentCurrency = "ars" },
+			expectedAction: ActionAllow,
+		},
+		{
+			name: "new merchant and card reach the alert",
+			change: func(transaction *domaintransaction.Transaction) {
+				transaction.MerchantName, transaction.FromCardID = "YPF", stringPtr("card-2")
+			},
+			profile:         func(profile *Profile) { profile.InstrumentCurrency = "USD" },
+			expectedScore:   55,
+			expectedSignals: "new_merchant,new_card,currency_mismatch",
+			expectedAction:  ActionAlert,
+		},
+		{
+			name: "outlier at night with a new merchant and card is held",
+			change: func(transaction *domaintransaction.Transaction) {
+				transaction.Amount, transaction.MerchantName, transaction.FromCardID = 1600, "YPF", stringPtr("card-2")
+			},
+			at:              time.Date(2025, 3, 10, 3, 30, 0, 0, time.UTC),
+			expectedScore:   85,
+			expectedSignals: "amount_outlier,new_merchant,unusual_hour,new_card",
+			expectedAction:  ActionHold,
+		},
+		{
+			name: "every signal is blocked at the maximum score",
+			change: func(transaction *domaintransaction.Transaction) {
+				transaction.Amount, transaction.MerchantName, transaction.FromCardID = 1600, "YPF", stringPtr("card-2")
+			},
+			previous: func(previous []*domaintransaction.Transaction) []*domaintransaction.Transaction {
+				// Declined attempts, so they do not make the hour a usual one
+				for i := 1; i <= 4; i++ {
+					recent := payment(fmt.Sprintf("recent-%d", i), 1000, time.Date(2025, 3, 10, 3, 30-i, 0, 0, time.UTC))
+					recent.Status = domaintransaction.TransactionStatusFailed
+					previous = append(previous, recent)
+				}
+				return previous
+			},
+			profile:         func(profile *Profile) { profile.InstrumentCurrency = "USD" },
+			at:              time.Date(2025, 3, 10, 3, 30, 0, 0, time.UTC),
+			expectedScore:   MaxScore,
+			expectedSignals: "amount_outlier,new_merchant,unusual_hour,velocity,new_card,currency_mismatch",
+			expectedAction:  ActionBlock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := now
+			if !tt.at.IsZero() {
+				at = tt.at
+			}
+			settings := DefaultSettings("user-1")
+			previous := history(12)
+			if tt.previous != nil {
+				previous = tt.previous(previous)
+			}
+			transaction := payment("tx-new", 1000, at)
+			tt.change(transaction)
+
+			profile := BuildProfile(transaction, previous, settings, at)
+			if tt.profile != nil {
+				tt.profile(profile)
+			}
+			score := Evaluate(transaction, profile, settings, at)
+
+			if score.Score != tt.expectedScore || signals(score) != tt.expectedSignals || score.Action != tt.expectedAction {
+				t.Errorf("Evaluate() = %d %s with %q, want %d %s with %q",
+					score.Score, score.Action, signals(score), tt.expectedScore, tt.expectedAction, tt.expectedSignals)
+			}
+			if score.TransactionID != "tx-new" || score.UserID != "user-1" || score.Reasons == nil {
+				t.Errorf("Evaluate() = %+v", *score)
+			}
+		})
+	}
+}
+
+func TestBuildProfile(t *testing.T) {
+	transaction := payment("tx-new", 1000, now)
+	recordedOnAccount := payment("account-card", 500, now.AddDate(0, 0, -2))
+	recordedOnAccount.FromCardID = nil
+	recordedOnAccount.FromAccountID = stringPtr("account-1")
+	recordedOnAccount.Metadata = map[string]interface{}{"cardId": "card-3"}
+	dollars := payment("dollars", 20, now.AddDate(0, 0, -3))
+	dollars.Currency = "USD"
+	failed := payment("failed", 1000, now.Add(-5*time.Minute))
+	failed.Status = domaintransaction.TransactionStatusFailed
+	failed.FromCardID = stringPtr("card-4")
+	pending := payment("pending", 1000, now.Add(-10*time.Minute))
+	pending.Status = domaintransaction.TransactionStatusPending
+
+	profile := BuildProfile(transaction, []*domaintransaction.Transaction{
+		payment("tx-new", 1000, now), // The transaction itself, when it was saved before scoring
+		payment("old", 800, now.AddDate(0, 0, -1)),
+		recordedOnAccount,
+		dollars,
+		failed,
+		pending,
+	}, DefaultSettings("user-1"), now)
+
+	if profile.Transactions != 3 {
+		t.Errorf("BuildProfile() transactions = %d, want 3", profile.Transactions)
+	}
+	if len(profile.Amounts) != 2 || profile.Amounts[0] != 800 || profile.Amounts[1] != 500 {
+		t.Errorf("BuildProfile() amounts = %v, want [800 500]", profile.Amounts)
+	}
+	if !profile.Cards["card-1"] || !profile.Cards["card-3"] || profile.Cards["card-4"] {
+		t.Errorf("BuildProfile() cards = %v, want card-1 and card-3", profile.Cards)
+	}
+	if !profile.Merchants["name:supermercado día"] || len(profile.Merchants) != 1 {
+		t.Errorf("BuildProfile() merchants = %v", profile.Merchants)
+	}
+	if profile.HourCounts[15] != 3 {
+		t.Errorf("BuildProfile() transactions at 15:00 = %d, want 3", profile.HourCounts[15])
+	}
+	if profile.Recent != 2 {
+		t.Errorf("BuildProfile() recent = %d, want 2", profile.Recent)
+	}
+}
+
+func TestSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(settings *Settings)
+		expectError bool
+	}{
+		{name: "default settings", change: func(settings *Settings) {}},
+		{name: "alert disabled", change: func(settings *Settings) { settings.AlertThreshold = 0 }},
+		{name: "only block", change: func(settings *Settings) { settings.AlertThreshold, settings.HoldThreshold = 0, 0 }},
+		{name: "equal thresholds", change: func(settings *Settings) { settings.HoldThreshold = 90 }},
+		{name: "quiet hours wrapping midnight", change: func(settings *Settings) { settings.QuietHoursStart, settings.QuietHoursEnd = 22, 6 }},
+		{name: "hold below alert", change: func(settings *Settings) { settings.HoldThreshold = 30 }, expectError: true},
+		{name: "block below hold with alert disabled", change: func(settings *Settings) {
+			settings.AlertThreshold, settings.BlockThreshold = 0, 60
+		}, expectError: true},
+		{name: "negative threshold", change: func(settings *Settings) { settings.AlertThreshold = -1 }, expectError: true},
+		{name: "threshold above the maximum", change: func(settings *Settings) { settings.BlockThreshold = MaxScore + 1 }, expectError: true},
+		{name: "no amount deviation", change: func(settings *Settings) { settings.AmountDeviation = 0 }, expectError: true},
+		{name: "velocity of one transaction", change: func(settings *Settings) { settings.VelocityCount = 1 }, expectError: true},
+		{name: "no velocity window", change: func(settings *Settings) { settings.VelocityWindowMinutes = 0 }, expectError: true},
+		{name: "velocity window over a day", change: func(settings *Settings) { settings.VelocityWindowMinutes = 24*60 + 1 }, expectError: true},
+		{name: "quiet hour out of range", change: func(settings *Settings) { settings.QuietHoursEnd = 24 }, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := DefaultSettings("user-1")
+			tt.change(settings)
+
+			err := settings.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Validate() expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Validate() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestActionFor(t *testing.T) {
+	settings := DefaultSettings("user-1")
+	withoutHold := DefaultSettings("user-1")
+	withoutHold.HoldThreshold = 0
+
+	tests := []struct {
+		settings *Settings
+		score    int
+		expected Action
+	}{
+		{settings, 0, ActionAllow},
+		{settings, 39, ActionAllow},
+		{settings, 40, ActionAlert},
+		{settings, 69, ActionAlert},
+		{settings, 70, ActionHold},
+		{settings, 89, ActionHold},
+		{settings, 90, ActionBlock},
+		{settings, MaxScore, ActionBlock},
+		{withoutHold, 80, ActionAlert},
+		{withoutHold, 90, ActionBlock},
+	}
+
+	for _, tt := range tests {
+		if got := tt.settings.ActionFor(tt.score); got != tt.expected {
+			t.Errorf("ActionFor(%d) with hold at %d = %s, want %s", tt.score, tt.settings.HoldThreshold, got, tt.expected)
+		}
+	}
+}
+
+func TestIsQuietHour(t *testing.T) {
+	tests := []struct {
+		start, end int
+		quiet      []int
+	}{
+		{0, 6, []int{0, 1, 2, 3, 4, 5}},
+		{22, 6, []int{22, 23, 0, 1, 2, 3, 4, 5}},
+		{3, 3, nil},
+	}
+
+	for _, tt := range tests {
+		settings := DefaultSettings("user-1")
+		settings.QuietHoursStart, settings.QuietHoursEnd = tt.start, tt.end
+		quiet := make(map[int]bool)
+		for _, hour := range tt.quiet {
+			quiet[hour] = true
+		}
+		for hour := 0; hour < 24; hour++ {
+			if got := settings.isQuietHour(hour); got != quiet[hour] {
+				t.Errorf("isQuietHour(%d) from %d to %d = %v, want %v", hour, tt.start, tt.end, got, quiet[hour])
+			}
+		}
+	}
+}
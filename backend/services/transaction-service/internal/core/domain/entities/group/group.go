@@ -0,0 +1,336 @@
+package group
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// MemberRole is the role of a user in a group
+type MemberRole string
+
+const (
+	MemberRoleOwner  MemberRole = "owner"  // Created the group; manages its members
+	MemberRoleMember MemberRole = "member" // Adds expenses and settles up
+)
+
+// MaxMembers is the maximum number of users in a group
+const MaxMembers = 50
+
+// Group is a set of users who share expenses (a trip, a flat, a team dinner), all in one currency
+type Group struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Currency  string    `json:"currency"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	Members []*Member `json:"members,omitempty"`
+}
+
+// Member returns the membership of a user, nil when the user is not in the group
+func (g *Group) Member(userID string) *Member {
+	for _, member := range g.Members {
+		if member.UserID == userID {
+			return member
+		}
+	}
+	return nil
+}
+
+// IsMember checks if a user is in the group
+func (g *Group) IsMember(userID string) bool {
+	return g.Member(userID) != nil
+}
+
+// Member is a user of a group
+type Member struct {
+	GroupID  string     `json:"groupId"`
+	UserID   string     `json:"userId"`
+	Name     string     `json:"name"`
+	Email    string     `json:"-"` // Used to settle up through a transfer, not shown to other members
+	Role     MemberRole `json:"role"`
+	JoinedAt time.Time  `json:"joinedAt"`
+}
+
+// SplitMethod is how an expense is divided among its participants
+type SplitMethod string
+
+const (
+	SplitEqual      SplitMethod = "equal"      // Same amount for everyone, the leftover cents to the first ones
+	SplitPercentage SplitMethod = "percentage" // Percentages adding up to 100
+	SplitExact      SplitMethod = "exact"      // Amounts adding up to the expense
+)
+
+// Expense is paid by one member and owed by the members it is split among
+type Expense struct {
+	ID            string      `json:"id"`
+	GroupID       string      `json:"groupId"`
+	PaidBy        string      `json:"paidBy"`
+	Amount        float64     `json:"amount"`
+	Description   string      `json:"description"`
+	SplitMethod   SplitMethod `json:"splitMethod"`
+	TransactionID *string     `json:"transactionId"` // Transaction of the payer it comes from, split into allocations
+	ExpenseDate   time.Time   `json:"expenseDate"`
+	CreatedBy     string      `json:"createdBy"`
+	CreatedAt     time.Time   `json:"createdAt"`
+
+	Shares []*Share `json:"shares"`
+}
+
+// Share is the part of an expense a member owes
+type Share struct {
+	ExpenseID  string   `json:"expenseId"`
+	UserID     string   `json:"userId"`
+	Amount     float64  `json:"amount"`
+	Percentage *float64 `json:"percentage,omitempty"` // Only in percentage splits
+}
+
+// ShareInput is a participant of an expense as requested: only the user with an equal split,
+// with a percentage or an exact amount otherwise
+type ShareInput struct {
+	UserID     string   `json:"userId"`
+	Percentage *float64 `json:"percentage"`
+	Amount     *float64 `json:"amount"`
+}
+
+// Settlement is money a member paid back to another one through a transfer between users
+type Settlement struct {
+	ID             string    `json:"id"`
+	GroupID        string    `json:"groupId"`
+	FromUserID     string    `json:"fromUserId"`
+	ToUserID       string    `json:"toUserId"`
+	Amount         float64   `json:"amount"`
+	TransferID     string    `json:"transferId"`
+	TransferStatus string    `json:"transferStatus"` // Status of the transfer, read from it
+	CreatedBy      string    `json:"createdBy"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// Counts checks if the settlement pays debt back: a transfer declined, cancelled, expired or failed returned
+// the money to the sender
+func (s *Settlement) Counts() bool {
+	switch s.TransferStatus {
+	case "declined", "cancelled", "expired", "failed":
+		return false
+	}
+	return true
+}
+
+// Balance is where a member stands in a group. A positive net is owed to the member, a negative one the
+// member owes.
+type Balance struct {
+	UserID string  `json:"userId"`
+	Name   string  `json:"name"`
+	Paid   float64 `json:"paid"`  // Expenses the member paid
+	Share  float64 `json:"share"` // Shares of expenses the member owes
+	Sent   float64 `json:"sent"`  // Settlements the member paid
+	Got    float64 `json:"got"`   // Settlements the member received
+	Net    float64 `json:"net"`
+}
+
+// Debt is what a member has to pay another one to settle the group
+type Debt struct {
+	FromUserID string  `json:"fromUserId"`
+	ToUserID   string  `json:"toUserId"`
+	Amount     float64 `json:"amount"`
+}
+
+// ComputeShares divides an expense among its participants. Amounts are rounded to cents and always add
+// up to the expense.
+func ComputeShares(method SplitMethod, amount float64, participants []ShareInput) ([]*Share, error) {
+	if amount <= 0 {
+		return nil, errors.New("amount must be greater than 0")
+	}
+	if len(participants) == 0 {
+		return nil, errors.New("an expense needs at least one participant")
+	}
+	seen := make(map[string]bool, len(participants))
+	for _, participant := range participants {
+		if participant.UserID == "" {
+			return nil, errors.New("every participant needs a userId")
+		}
+		if seen[participant.UserID] {
+			return nil, fmt.Errorf("user %s appears more than once in the split", participant.UserID)
+		}
+		seen[participant.UserID] = true
+	}
+
+	totalCents := toCents(amount)
+	shares := make([]*Share, len(participants))
+
+	switch method {
+	case SplitEqual:
+		count := int64(len(participants))
+		for i, participant := range participants {
+			cents := totalCents / count
+			if int64(i) < totalCents%count {
+				cents++
+			}
+			shares[i] = &Share{UserID: participant.UserID, Amount: fromCents(cents)}
+		}
+
+	case SplitPercentage:
+		var totalPercentage float64
+		for _, participant := range participants {
+			if participant.Percentage == nil || *participant.Percentage <= 0 {
+				return nil, fmt.Errorf("user %s needs a percentage greater than 0", participant.UserID)
+			}
+			totalPercentage += *participant.Percentage
+		}
+		if math.Abs(totalPercentage-100) > 0.01 {
+			return nil, fmt.Errorf("percentages add up to %.2f instead of 100", totalPercentage)
+		}
+
+		// Round each share down and hand the leftover cents to the largest remainders
+		type remainder struct {
+			index int
+			value float64
+		}
+		remainders := make([]remainder, len(participants))
+		var assigned int64
+		for i, participant := range participants {
+			exact := float64(totalCents) * *participant.Percentage / 100
+			cents := int64(math.Floor(exact))
+			percentage := *participant.Percentage
+			shares[i] = &Share{UserID: participant.UserID, Amount: fromCents(cents), Percentage: &percentage}
+			remainders[i] = remainder{index: i, value: exact - float64(cents)}
+			assigned += cents
+		}
+		sort.SliceStable(remainders, func(a, b int) bool { return remainders[a].value > remainders[b].value })
+		for i := int64(0); i < totalCents-assigned; i++ {
+			share := shares[remainders[i%int64(len(remainders))].index]
+			share.Amount = fromCents(toCents(share.Amount) + 1)
+		}
+
+	case SplitExact:
+		var sum int64
+		for i, participant := range participants {
+			if participant.Amount == nil || *participant.Amount < 0 {
+				return nil, fmt.Errorf("user %s needs an amount", participant.UserID)
+			}
+			cents := toCents(*participant.Amount)
+			shares[i] = &Share{UserID: participant.UserID, Amount: fromCents(cents)}
+			sum += cents
+		}
+		if sum != totalCents {
+			return nil, fmt.Errorf("amounts add up to %.2f but the expense is %.2f", fromCents(sum), amount)
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid split method: %s", method)
+	}
+
+	return shares, nil
+}
+
+// ComputeBalances returns where each member stands after the expenses and the settlements that count,
+// in the order of the members
+func ComputeBalances(members []*Member, expenses []*Expense, settlements []*Settlement) []*Balance {
+	balances := make([]*Balance, 0, len(members))
+	byUser := make(map[string]*Balance, len(members))
+	balanceOf := func(userID string) *Balance {
+		if balance, exists := byUser[userID]; exists {
+			return balance
+		}
+		// A former member with amounts still pending
+		balance := &Balance{UserID: userID}
+		byUser[userID] = balance
+		balances = append(balances, balance)
+		return balance
+	}
+	for _, member := range members {
+		balance := &Balance{UserID: member.UserID, Name: member.Name}
+		byUser[member.UserID] = balance
+		balances = append(balances, balance)
+	}
+
+	for _, expense := range expenses {
+		balanceOf(expense.PaidBy).Paid += expense.Amount
+		for _, share := range expense.Shares {
+			balanceOf(share.UserID).Share += share.Amount
+		}
+	}
+	for _, settlement := range settlements {
+		if !settlement.Counts() {
+			continue
+		}
+		balanceOf(settlement.FromUserID).Sent += settlement.Amount
+		balanceOf(settlement.ToUserID).Got += settlement.Amount
+	}
+
+	for _, balance := range balances {
+		balance.Paid = roundCents(balance.Paid)
+		balance.Share = roundCents(balance.Share)
+		balance.Sent = roundCents(balance.Sent)
+		balance.Got = roundCents(balance.Got)
+		balance.Net = fromCents(toCents(balance.Paid) - toCents(balance.Share) + toCents(balance.Sent) - toCents(balance.Got))
+	}
+	return balances
+}
+
+// SimplifyDebts turns the balances into the fewest payments that settle the group, matching the largest
+// debtor with the largest creditor each time
+func SimplifyDebts(balances []*Balance) []*Debt {
+	type position struct {
+		userID string
+		cents  int64
+	}
+	var debtors, creditors []*position
+	for _, balance := range balances {
+		cents := toCents(balance.Net)
+		switch {
+		case cents < 0:
+			debtors = append(debtors, &position{userID: balance.UserID, cents: -cents})
+		case cents > 0:
+			creditors = append(creditors, &position{userID: balance.UserID, cents: cents})
+		}
+	}
+
+	largestFirst := func(positions []*position) {
+		sort.SliceStable(positions, func(a, b int) bool {
+			if positions[a].cents != positions[b].cents {
+				return positions[a].cents > positions[b].cents
+			}
+			return positions[a].userID < positions[b].userID
+		})
+	}
+
+	debts := []*Debt{}
+	for len(debtors) > 0 && len(creditors) > 0 {
+		largestFirst(debtors)
+		largestFirst(creditors)
+		debtor, creditor := debtors[0], creditors[0]
+
+		cents := debtor.cents
+		if creditor.cents < cents {
+			cents = creditor.cents
+		}
+		debts = append(debts, &Debt{FromUserID: debtor.userID, ToUserID: creditor.userID, Amount: fromCents(cents)})
+
+		debtor.cents -= cents
+		creditor.cents -= cents
+		if debtor.cents == 0 {
+			debtors = debtors[1:]
+		}
+		if creditor.cents == 0 {
+			creditors = creditors[1:]
+		}
+	}
+	return debts
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+func roundCents(amount float64) float64 {
+	return fromCents(toCents(amount))
+}
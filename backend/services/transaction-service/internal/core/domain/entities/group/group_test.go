@@ -0,0 +1,390 @@
+package group
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func percentage(value float64) *float64 {
+	return &value
+}
+
+func amount(value float64) *float64 {
+	return &value
+}
+
+// sharesTotal adds up the shares in cents so rounding errors do not hide
+func sharesTotal(shares []*Share) int64 {
+	var total int64
+	for _, share := range shares {
+		total += toCents(share.Amount)
+	}
+	return total
+}
+
+func TestComputeShares(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       SplitMethod
+		amount       float64
+		participants []ShareInput
+		expected     []float64
+		expectError  bool
+	}{
+		{
+			name:         "equal split with leftover cents to the first ones",
+			method:       SplitEqual,
+			amount:       100,
+			participants: []ShareInput{{UserID: "ana"}, {UserID: "bruno"}, {UserID: "carla"}},
+			expected:     []float64{33.34, 33.33, 33.33},
+		},
+		{
+			name:         "equal split of cents",
+			method:       SplitEqual,
+			amount:       0.05,
+			participants: []ShareInput{{UserID: "ana"}, {UserID: "bruno"}, {UserID: "carla"}},
+			expected:     []float64{0.02, 0.02, 0.01},
+		},
+		{
+			name:         "equal split among one",
+			method:       SplitEqual,
+			amount:       1234.56,
+			participants: []ShareInput{{UserID: "ana"}},
+			expected:     []float64{1234.56},
+		},
+		{
+			name:   "percentage split",
+			method: SplitPercentage,
+			amount: 15000,
+			participants: []ShareInput{
+				{UserID: "ana", Percentage: percentage(50)},
+				{UserID: "bruno", Percentage: percentage(30)},
+				{UserID: "carla", Percentage: percentage(20)},
+			},
+			expected: []float64{7500, 4500, 3000},
+		},
+		{
+			name:   "percentage split with leftover cents to the largest remainders",
+			method: SplitPercentage,
+			amount: 100,
+			participants: []ShareInput{
+				{UserID: "ana", Percentage: percentage(33.333)},
+				{UserID: "bruno", Percentage: percentage(33.333)},
+				{UserID: "carla", Percentage: percentage(33.334)},
+			},
+			expected: []float64{33.33, 33.33, 33.34},
+		},
+		{
+			name:   "percentage split of an odd amount",
+			method: SplitPercentage,
+			amount: 10.01,
+			participants: []ShareInput{
+				{UserID: "ana", Percentage: percentage(50)},
+				{UserID: "bruno", Percentage: percentage(50)},
+			},
+			expected: []float64{5.01, 5},
+		},
+		{
+			name:   "exact split",
+			method: SplitExact,
+			amount: 250.75,
+			participants: []ShareInput{
+				{UserID: "ana", Amount: amount(200.5)},
+				{UserID: "bruno", Amount: amount(50.25)},
+				{UserID: "carla", Amount: amount(0)},
+			},
+			expected: []float64{200.5, 50.25, 0},
+		},
+		{
+			name:   "percentages not adding up to 100",
+			method: SplitPercentage,
+			amount: 100,
+			participants: []ShareInput{
+				{UserID: "ana", Percentage: percentage(50)},
+				{UserID: "bruno", Percentage: percentage(40)},
+			},
+			expectError: true,
+		},
+		{
+			name:         "missing percentage",
+			method:       SplitPercentage,
+			amount:       100,
+			participants: []ShareInput{{UserID: "ana", Percentage: percentage(100)}, {UserID: "bruno"}},
+			expectError:  true,
+		},
+		{
+			name:   "exact amounts not adding up to the expense",
+			method: SplitExact,
+			amount: 100,
+			participants: []ShareInput{
+				{UserID: "ana", Amount: amount(60)},
+				{UserID: "bruno", Amount: amount(39.99)},
+			},
+			expectError: true,
+		},
+		{
+			name:         "negative exact amount",
+			method:       SplitExact,
+			amount:       100,
+			participants: []ShareInput{{UserID: "ana", Amount: amount(110)}, {UserID: "bruno", Amount: amount(-10)}},
+			expectError:  true,
+		},
+		{
+			name:         "repeated participant",
+			method:       SplitEqual,
+			amount:       100,
+			participants: []ShareInput{{UserID: "ana"}, {UserID: "ana"}},
+			expectError:  true,
+		},
+		{
+			name:         "participant without user",
+			method:       SplitEqual,
+			amount:       100,
+			participants: []ShareInput{{UserID: ""}},
+			expectError:  true,
+		},
+		{
+			name:        "no participants",
+			method:      SplitEqual,
+			amount:      100,
+			expectError: true,
+		},
+		{
+			name:         "zero amount",
+			method:       SplitEqual,
+			amount:       0,
+			participants: []ShareInput{{UserID: "ana"}},
+			expectError:  true,
+		},
+		{
+			name:         "invalid method",
+			method:       "shares",
+			amount:       100,
+			participants: []ShareInput{{UserID: "ana"}},
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := ComputeShares(tt.method, tt.amount, tt.participants)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ComputeShares() expected error but got %v", shares)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ComputeShares() unexpected error: %v", err)
+			}
+
+			if len(shares) != len(tt.expected) {
+				t.Fatalf("ComputeShares() shares = %d, want %d", len(shares), len(tt.expected))
+			}
+			for i, share := range shares {
+				if share.UserID != tt.participants[i].UserID || share.Amount != tt.expected[i] {
+					t.Errorf("share %d = %s %v, want %s %v", i, share.UserID, share.Amount, tt.participants[i].UserID, tt.expected[i])
+				}
+				if (share.Percentage != nil) != (tt.method == SplitPercentage) {
+					t.Errorf("share %d percentage = %v for a %s split", i, share.Percentage, tt.method)
+				}
+			}
+			if total := sharesTotal(shares); total != toCents(tt.amount) {
+				t.Errorf("ComputeShares() shares add up to %d cents, want %d", total, toCents(tt.amount))
+			}
+		})
+	}
+}
+
+func TestComputeSharesAddUpToTheExpense(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 500; i++ {
+		expense := fromCents(1 + random.Int63n(10000000))
+		count := 1 + random.Intn(12)
+
+		equal := make([]ShareInput, count)
+		byPercentage := make([]ShareInput, count)
+		remaining := 100.0
+		for j := 0; j < count; j++ {
+			userID := fmt.Sprintf("user-%d", j)
+			equal[j] = ShareInput{UserID: userID}
+
+			// Percentages with up to 3 decimals adding up to 100
+			value := remaining
+			if j < count-1 {
+				value = float64(1+random.Intn(int(remaining*1000)/(count-j))) / 1000
+			}
+			remaining -= value
+			byPercentage[j] = ShareInput{UserID: userID, Percentage: percentage(value)}
+		}
+
+		for _, split := range []struct {
+			method       SplitMethod
+			participants []ShareInput
+		}{
+			{SplitEqual, equal},
+			{SplitPercentage, byPercentage},
+		} {
+			shares, err := ComputeShares(split.method, expense, split.participants)
+			if err != nil {
+				t.Fatalf("ComputeShares(%s, %v) unexpected error: %v", split.method, expense, err)
+			}
+			if total := sharesTotal(shares); total != toCents(expense) {
+				t.Fatalf("ComputeShares(%s, %v) shares add up to %d cents", split.method, expense, total)
+			}
+			for _, share := range shares {
+				if share.Amount < 0 {
+					t.Fatalf("ComputeShares(%s, %v) negative share %v", split.method, expense, share.Amount)
+				}
+			}
+		}
+	}
+}
+
+func TestComputeBalances(t *testing.T) {
+	members := []*Member{
+		{UserID: "ana", Name: "Ana"},
+		{UserID: "bruno", Name: "Bruno"},
+		{UserID: "carla", Name: "Carla"},
+	}
+	dinner, _ := ComputeShares(SplitEqual, 90, []ShareInput{{UserID: "ana"}, {UserID: "bruno"}, {UserID: "carla"}})
+	flat, _ := ComputeShares(SplitPercentage, 100, []ShareInput{
+		{UserID: "ana", Percentage: percentage(25)},
+		{UserID: "bruno", Percentage: percentage(50)},
+		{UserID: "carla", Percentage: percentage(25)},
+	})
+	taxi, _ := ComputeShares(SplitExact, 12, []ShareInput{{UserID: "ana", Amount: amount(12)}})
+	expenses := []*Expense{
+		{PaidBy: "ana", Amount: 90, Shares: dinner},
+		{PaidBy: "bruno", Amount: 100, Shares: flat},
+		{PaidBy: "diego", Amount: 12, Shares: taxi}, // Diego left the group
+	}
+	settlements := []*Settlement{
+		{FromUserID: "carla", ToUserID: "ana", Amount: 20, TransferStatus: "completed"},
+		{FromUserID: "bruno", ToUserID: "ana", Amount: 10, TransferStatus: "declined"},
+		{FromUserID: "carla", ToUserID: "bruno", Amount: 5, TransferStatus: "pending"},
+	}
+
+	balances := ComputeBalances(members, expenses, settlements)
+
+	expected := []Balance{
+		{UserID: "ana", Name: "Ana", Paid: 90, Share: 67, Got: 20, Net: 3},
+		{UserID: "bruno", Name: "Bruno", Paid: 100, Share: 80, Got: 5, Net: 15},
+		{UserID: "carla", Name: "Carla", Share: 55, Sent: 25, Net: -30},
+		{UserID: "diego", Paid: 12, Net: 12},
+	}
+	if len(balances) != len(expected) {
+		t.Fatalf("ComputeBalances() balances = %d, want %d", len(balances), len(expected))
+	}
+	var net int64
+	for i, want := range expected {
+		if *balances[i] != want {
+			t.Errorf("balance %d = %+v, want %+v", i, *balances[i], want)
+		}
+		net += toCents(balances[i].Net)
+	}
+	if net != 0 {
+		t.Errorf("ComputeBalances() nets add up to %d cents, want 0", net)
+	}
+}
+
+func TestSimplifyDebts(t *testing.T) {
+	tests := []struct {
+		name     string
+		nets     map[string]float64
+		expected []Debt
+	}{
+		{
+			name:     "settled group",
+			nets:     map[string]float64{"ana": 0, "bruno": 0},
+			expected: []Debt{},
+		},
+		{
+			name:     "one debtor pays every creditor",
+			nets:     map[string]float64{"ana": 3, "bruno": 15, "carla": -30, "diego": 12},
+			expected: []Debt{{"carla", "bruno", 15}, {"carla", "diego", 12}, {"carla", "ana", 3}},
+		},
+		{
+			name:     "largest debtor with largest creditor",
+			nets:     map[string]float64{"ana": 100, "bruno": -60, "carla": -40.5, "diego": 0.5},
+			expected: []Debt{{"bruno", "ana", 60}, {"carla", "ana", 40}, {"carla", "diego", 0.5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var balances []*Balance
+			for _, userID := range []string{"ana", "bruno", "carla", "diego"} {
+				if net, exists := tt.nets[userID]; exists {
+					balances = append(balances, &Balance{UserID: userID, Net: net})
+				}
+			}
+
+			debts := SimplifyDebts(balances)
+			if len(debts) != len(tt.expected) {
+				t.Fatalf("SimplifyDebts() = %d debts, want %d", len(debts), len(tt.expected))
+			}
+			for i, want := range tt.expected {
+				if *debts[i] != want {
+					t.Errorf("debt %d = %+v, want %+v", i, *debts[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSimplifyDebtsSettlesTheGroup(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+
+	for i := 0; i < 200; i++ {
+		var members []*Member
+		var participants []ShareInput
+		for j := 0; j < 2+random.Intn(8); j++ {
+			userID := fmt.Sprintf("user-%d", j)
+			members = append(members, &Member{UserID: userID})
+			participants = append(participants, ShareInput{UserID: userID})
+		}
+
+		var expenses []*Expense
+		for j := 0; j < 1+random.Intn(15); j++ {
+			expense := fromCents(1 + random.Int63n(5000000))
+			shares, err := ComputeShares(SplitEqual, expense, participants[:1+random.Intn(len(participants))])
+			if err != nil {
+				t.Fatalf("ComputeShares() unexpected error: %v", err)
+			}
+			payer := members[random.Intn(len(members))].UserID
+			expenses = append(expenses, &Expense{PaidBy: payer, Amount: expense, Shares: shares})
+		}
+
+		balances := ComputeBalances(members, expenses, nil)
+		remaining := make(map[string]int64, len(balances))
+		var net int64
+		for _, balance := range balances {
+			remaining[balance.UserID] = toCents(balance.Net)
+			net += toCents(balance.Net)
+		}
+		if net != 0 {
+			t.Fatalf("ComputeBalances() nets add up to %d cents, want 0", net)
+		}
+
+		// Paying the simplified debts leaves every member even
+		debts := SimplifyDebts(balances)
+		if len(debts) > len(members)-1 {
+			t.Errorf("SimplifyDebts() = %d debts for %d members", len(debts), len(members))
+		}
+		for _, debt := range debts {
+			if debt.Amount <= 0 || debt.FromUserID == debt.ToUserID {
+				t.Fatalf("SimplifyDebts() invalid debt %+v", *debt)
+			}
+			remaining[debt.FromUserID] += toCents(debt.Amount)
+			remaining[debt.ToUserID] -= toCents(debt.Amount)
+		}
+		for userID, cents := range remaining {
+			if cents != 0 {
+				t.Fatalf("after paying the debts %s is at %d cents, want 0", userID, cents)
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package p2p
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TransferKind tells who started a transfer between users
+type TransferKind string
+
+const (
+	TransferKindSend    TransferKind = "send"    // The sender pays and the recipient accepts
+	TransferKindRequest TransferKind = "request" // The recipient asks for money and the sender accepts paying it
+)
+
+// TransferStatus is the lifecycle of a transfer between users
+type TransferStatus string
+
+const (
+	TransferStatusPending    TransferStatus = "pending"    // Waiting for the other party
+	TransferStatusProcessing TransferStatus = "processing" // Claimed by an acceptance, decline, cancellation or expiry
+	TransferStatusCompleted  TransferStatus = "completed"
+	TransferStatusDeclined   TransferStatus = "declined"
+	TransferStatusCancelled  TransferStatus = "cancelled"
+	TransferStatusExpired    TransferStatus = "expired"
+	TransferStatusFailed     TransferStatus = "failed"
+)
+
+// DefaultExpiry is how long a transfer waits for the other party. A sent transfer not accepted in time
+// is refunded to the sender.
+const DefaultExpiry = 7 * 24 * time.Hour
+
+// Metadata keys of the transactions created by a transfer, linking both sides of it
+const (
+	MetadataTransferID     = "p2pTransferId"
+	MetadataCounterpartyID = "p2pCounterpartyId"
+	MetadataDirection      = "p2pDirection" // sent, received or refund
+)
+
+// Transfer moves money between the accounts of two FinTrack users. The sender is always the user the
+// money comes from, whoever started it.
+type Transfer struct {
+	ID                     string         `json:"id"`
+	Kind                   TransferKind   `json:"kind"`
+	SenderUserID           string         `json:"senderUserId"`
+	RecipientUserID        string         `json:"recipientUserId"`
+	SenderAccountID        *string        `json:"senderAccountId"`    // Chosen on send, or when a request is paid
+	RecipientAccountID     *string        `json:"recipientAccountId"` // Chosen on request, or when a transfer is accepted
+	Amount                 float64        `json:"amount"`
+	Currency               string         `json:"currency"`
+	Description            string         `json:"description"`
+	Status                 TransferStatus `json:"status"`
+	Reason                 string         `json:"reason,omitempty"` // Why it was declined, cancelled or failed
+	SenderTransactionID    *string        `json:"senderTransactionId"`
+	RecipientTransactionID *string        `json:"recipientTransactionId"`
+	RefundTransactionID    *string        `json:"refundTransactionId"`
+	DebitedAt              *time.Time     `json:"debitedAt"` // When the money left the sender; counts against the daily limit
+	ExpiresAt              time.Time      `json:"expiresAt"`
+	RespondedAt            *time.Time     `json:"respondedAt"`
+	CompletedAt            *time.Time     `json:"completedAt"`
+	CreatedAt              time.Time      `json:"createdAt"`
+	UpdatedAt              time.Time      `json:"updatedAt"`
+
+	// Parties as shown to each other
+	Sender    *Party `json:"sender,omitempty"`
+	Recipient *Party `json:"recipient,omitempty"`
+}
+
+// InitiatorID returns the user who started the transfer
+func (t *Transfer) InitiatorID() string {
+	if t.Kind == TransferKindRequest {
+		return t.RecipientUserID
+	}
+	return t.SenderUserID
+}
+
+// ResponderID returns the user who accepts or declines the transfer
+func (t *Transfer) ResponderID() string {
+	if t.Kind == TransferKindRequest {
+		return t.SenderUserID
+	}
+	return t.RecipientUserID
+}
+
+// IsParty checks if a user is the sender or the recipient of the transfer
+func (t *Transfer) IsParty(userID string) bool {
+	return t.SenderUserID == userID || t.RecipientUserID == userID
+}
+
+// IsDebited checks if the money already left the sender and has to be refunded when the transfer does not complete
+func (t *Transfer) IsDebited() bool {
+	return t.SenderTransactionID != nil && t.RefundTransactionID == nil
+}
+
+// IsExpired checks if a pending transfer waited longer than its expiry
+func (t *Transfer) IsExpired(now time.Time) bool {
+	return t.Status == TransferStatusPending && !now.Before(t.ExpiresAt)
+}
+
+// Party is a FinTrack user as shown to the other side of a transfer
+type Party struct {
+	UserID string `json:"userId"`
+	Name   string `json:"name"`
+	Email  string `json:"email,omitempty"`
+	Alias  string `json:"alias,omitempty"`
+}
+
+// Account is an account a transfer can be sent from or received into
+type Account struct {
+	ID          string `json:"id"`
+	UserID      string `json:"userId"`
+	Name        string `json:"name"`
+	AccountType string `json:"accountType"`
+	Currency    string `json:"currency"`
+}
+
+// IsWallet checks if the account is a virtual wallet
+func (a *Account) IsWallet() bool {
+	return a.AccountType == "wallet"
+}
+
+// CanTransfer checks if money can be moved in and out of the account: credit accounts have no balance to send
+func (a *Account) CanTransfer() bool {
+	return a.AccountType != "credit"
+}
+
+// Profile is how a user is found and paid by other users
+type Profile struct {
+	UserID           string    `json:"userId"`
+	Alias            string    `json:"alias"`            // CVU-style alias, e.g. "sol.gato.mesa"
+	DefaultAccountID *string   `json:"defaultAccountId"` // Where accepted transfers go; the oldest wallet otherwise
+	AutoAccept       bool      `json:"autoAccept"`       // Credit transfers into the default account without waiting
+	DailyAmountLimit *float64  `json:"dailyAmountLimit"` // Own limit, only lower than the one of the platform
+	DailyCountLimit  *int      `json:"dailyCountLimit"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// Limits bound what a user can send to other users per day and currency
+type Limits struct {
+	DailyAmount float64 `json:"dailyAmount"`
+	DailyCount  int     `json:"dailyCount"`
+}
+
+// Usage is what a user sent to other users in a day and currency
+type Usage struct {
+	Amount float64 `json:"amount"`
+	Count  int     `json:"count"`
+}
+
+// EffectiveLimits returns the limits of the platform lowered by the own limits of the profile
+func EffectiveLimits(platform Limits, profile *Profile) Limits {
+	limits := platform
+	if profile == nil {
+		return limits
+	}
+	if profile.DailyAmountLimit != nil && *profile.DailyAmountLimit < limits.DailyAmount {
+		limits.DailyAmount = *profile.DailyAmountLimit
+	}
+	if profile.DailyCountLimit != nil && *profile.DailyCountLimit < limits.DailyCount {
+		limits.DailyCount = *profile.DailyCountLimit
+	}
+	return limits
+}
+
+// CheckLimits validates that a new transfer fits in what is left of the limits of the day
+func CheckLimits(limits Limits, usage *Usage, amount float64) error {
+	if usage.Count+1 > limits.DailyCount {
+		return errors.New("daily limit of transfers to other users reached")
+	}
+	if usage.Amount+amount > limits.DailyAmount+0.005 {
+		return errors.New("daily amount limit of transfers to other users exceeded")
+	}
+	return nil
+}
+
+// aliasPattern is the format of an alias: 6 to 20 lowercase letters, digits and single dots, not at the ends
+var aliasPattern = regexp.MustCompile(`^[a-z0-9]+(\.[a-z0-9]+)*$`)
+
+// NormalizeAlias lowercases and trims an alias
+func NormalizeAlias(alias string) string {
+	return strings.ToLower(strings.TrimSpace(alias))
+}
+
+// ValidateAlias checks the format of a normalized alias
+func ValidateAlias(alias string) error {
+	if len(alias) < 6 || len(alias) > 20 {
+		return errors.New("alias must be between 6 and 20 characters")
+	}
+	if !aliasPattern.MatchString(alias) {
+		return errors.New("alias can only have letters, numbers and dots between words")
+	}
+	return nil
+}
+
+// IsEmail checks if a recipient identifier is an email rather than an alias
+func IsEmail(identifier string) bool {
+	return strings.Contains(identifier, "@")
+}
@@ -0,0 +1,300 @@
+package reconciliation
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// BankEntry is a statement movement to reconcile
+type BankEntry struct {
+	ImportRowID string
+	Date        time.Time
+	Amount      float64 // Debits negative
+	Description string
+	ExternalID  string
+	// TransactionID is the transaction created from the movement when it was imported
+	TransactionID string
+}
+
+// BookEntry is a transaction tracked in FinTrack, signed like the statement of its account or card
+type BookEntry struct {
+	TransactionID string
+	Date          time.Time
+	Amount        float64
+	Description   string
+	ExternalID    string
+}
+
+// MatchConfig tunes the fuzzy matching
+type MatchConfig struct {
+	DateWindowDays  int     // Days a transaction can be away from the statement date
+	AmountTolerance float64 // Accepted difference between amounts (0 requires the same cents)
+	MinScore        float64 // Minimum score of a suggested match
+}
+
+// DefaultMatchConfig returns the configuration used when the user does not tune it
+func DefaultMatchConfig() MatchConfig {
+	return MatchConfig{
+		DateWindowDays:  3,
+		AmountTolerance: 0,
+		MinScore:        0.6,
+	}
+}
+
+// Weights of the match score
+const (
+	amountWeight   = 0.5
+	dateWeight     = 0.3
+	merchantWeight = 0.2
+)
+
+// Match compares the statement movements with the tracked transactions. Movements created by an import
+// and movements with the same bank identifier are matched and confirmed; the rest are paired by best
+// score, each transaction with one movement at most, and wait for the user to confirm them.
+// Transactions that match nothing are only reported as missing in the bank when they fall in the
+// statement period.
+func Match(bank []BankEntry, book []BookEntry, from, to time.Time, config MatchConfig) []*Item {
+	var items []*Item
+	bankUsed := make([]bool, len(bank))
+	bookUsed := make([]bool, len(book))
+
+	bookIndex := make(map[string]int, len(book))
+	for j, entry := range book {
+		bookIndex[entry.TransactionID] = j
+	}
+
+	// Movements imported as transactions
+	for i, entry := range bank {
+		if entry.TransactionID == "" {
+			continue
+		}
+		j, exists := bookIndex[entry.TransactionID]
+		if !exists || bookUsed[j] {
+			continue
+		}
+		bankUsed[i], bookUsed[j] = true, true
+		items = append(items, newMatch(entry, book[j], 1, MatchMethodImport, true))
+	}
+
+	// Same bank identifier
+	for i, entry := range bank {
+		if bankUsed[i] || entry.ExternalID == "" {
+			continue
+		}
+		for j, candidate := range book {
+			if bookUsed[j] || candidate.ExternalID != entry.ExternalID || !amountMatches(entry.Amount, candidate.Amount, config) {
+				continue
+			}
+			bankUsed[i], bookUsed[j] = true, true
+			items = append(items, newMatch(entry, candidate, 1, MatchMethodExternalID, true))
+			break
+		}
+	}
+
+	// Best scores first
+	type pair struct {
+		bank, book int
+		score      float64
+		gap        time.Duration
+	}
+	var pairs []pair
+	for i, entry := range bank {
+		if bankUsed[i] {
+			continue
+		}
+		for j, candidate := range book {
+			if bookUsed[j] || !isCandidate(entry, candidate, config) {
+				continue
+			}
+			if score := Score(entry, candidate, config); score >= config.MinScore {
+				pairs = append(pairs, pair{i, j, score, absDuration(entry.Date.Sub(candidate.Date))})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool {
+		if pairs[a].score != pairs[b].score {
+			return pairs[a].score > pairs[b].score
+		}
+		return pairs[a].gap < pairs[b].gap
+	})
+	for _, p := range pairs {
+		if bankUsed[p.bank] || bookUsed[p.book] {
+			continue
+		}
+		bankUsed[p.bank], bookUsed[p.book] = true, true
+		items = append(items, newMatch(bank[p.bank], book[p.book], p.score, MatchMethodFuzzy, false))
+	}
+
+	for i, entry := range bank {
+		if !bankUsed[i] {
+			item := &Item{Status: ItemStatusMissingInFinTrack}
+			item.setBank(entry)
+			items = append(items, item)
+		}
+	}
+
+	periodEnd := to.AddDate(0, 0, 1)
+	for j, entry := range book {
+		if bookUsed[j] || entry.Date.Before(from) || !entry.Date.Before(periodEnd) {
+			continue
+		}
+		item := &Item{Status: ItemStatusMissingInBank}
+		item.setTransaction(entry)
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(a, b int) bool { return items[a].date().Before(items[b].date()) })
+	return items
+}
+
+// Score rates how likely a statement movement and a transaction are the same movement, from 0 to 1
+func Score(bank BankEntry, book BookEntry, config MatchConfig) float64 {
+	amountScore := 1.0
+	if difference := math.Abs(bank.Amount - book.Amount); difference >= 0.005 {
+		// Within the tolerance, the score halves as the difference grows
+		amountScore = math.Max(0, 1-difference/(2*math.Max(config.AmountTolerance, 0.01)))
+	}
+
+	days := math.Abs(bank.Date.Sub(book.Date).Hours()) / 24
+	dateScore := math.Max(0, 1-days/float64(config.DateWindowDays+1))
+
+	score := amountWeight*amountScore + dateWeight*dateScore + merchantWeight*Similarity(bank.Description, book.Description)
+	return math.Round(score*100) / 100
+}
+
+// Similarity compares two merchant descriptions, from 0 to 1. Bank descriptions carry prefixes and
+// codes ("COMPRA DEB NETFLIX.COM 12345"), so a shared word counts as much as similar spelling.
+func Similarity(a, b string) float64 {
+	tokensA, tokensB := merchantTokens(a), merchantTokens(b)
+	if len(tokensA) == 0 || len(tokensB) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for _, tokenA := range tokensA {
+		for _, tokenB := range tokensB {
+			if tokensMatch(tokenA, tokenB) {
+				shared++
+				break
+			}
+		}
+	}
+	tokenScore := float64(shared) / float64(min(len(tokensA), len(tokensB)))
+
+	return math.Max(tokenScore, bigramSimilarity(strings.Join(tokensA, ""), strings.Join(tokensB, "")))
+}
+
+// descriptionNoise are words of bank descriptions that do not identify the merchant
+var descriptionNoise = map[string]bool{
+	"compra": true, "debito": true, "deb": true, "credito": true, "cred": true, "pago": true, "trf": true,
+	"transferencia": true, "tarjeta": true, "visa": true, "master": true, "mastercard": true, "com": true,
+	"www": true, "con": true, "por": true, "del": true, "los": true, "las": true,
+}
+
+var descriptionAccents = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+
+func merchantTokens(description string) []string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return r
+		}
+		return ' '
+	}, descriptionAccents.Replace(strings.ToLower(description)))
+
+	var tokens []string
+	for _, token := range strings.Fields(cleaned) {
+		if len(token) >= 3 && !descriptionNoise[token] {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func tokensMatch(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len(a) >= 4 && len(b) >= 4 {
+		return strings.Contains(a, b) || strings.Contains(b, a)
+	}
+	return false
+}
+
+// bigramSimilarity is the Dice coefficient of the letter pairs of both texts
+func bigramSimilarity(a, b string) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return 0
+	}
+	bigrams := make(map[string]int, len(a))
+	for i := 0; i+1 < len(a); i++ {
+		bigrams[a[i:i+2]]++
+	}
+	shared := 0
+	for i := 0; i+1 < len(b); i++ {
+		if bigrams[b[i:i+2]] > 0 {
+			bigrams[b[i:i+2]]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)-1+len(b)-1)
+}
+
+func isCandidate(bank BankEntry, book BookEntry, config MatchConfig) bool {
+	if (bank.Amount < 0) != (book.Amount < 0) || !amountMatches(bank.Amount, book.Amount, config) {
+		return false
+	}
+	return absDuration(bank.Date.Sub(book.Date)) <= time.Duration(config.DateWindowDays)*24*time.Hour+12*time.Hour
+}
+
+func amountMatches(a, b float64, config MatchConfig) bool {
+	return math.Abs(a-b) < config.AmountTolerance+0.005
+}
+
+func newMatch(bank BankEntry, book BookEntry, score float64, method MatchMethod, confirmed bool) *Item {
+	item := &Item{
+		Status:      ItemStatusMatched,
+		MatchMethod: method,
+		Score:       score,
+		Confirmed:   confirmed,
+	}
+	item.setBank(bank)
+	item.setTransaction(book)
+	if confirmed {
+		now := time.Now()
+		item.ConfirmedAt = &now
+	}
+	return item
+}
+
+func (i *Item) setBank(entry BankEntry) {
+	date, amount := entry.Date, entry.Amount
+	i.BankDate = &date
+	i.BankAmount = &amount
+	i.BankDescription = entry.Description
+	i.BankExternalID = entry.ExternalID
+	if entry.ImportRowID != "" {
+		id := entry.ImportRowID
+		i.ImportRowID = &id
+	}
+}
+
+// date is the statement date of the item, or the transaction date when it is missing in the bank
+func (i *Item) date() time.Time {
+	if i.BankDate != nil {
+		return *i.BankDate
+	}
+	if i.TransactionDate != nil {
+		return *i.TransactionDate
+	}
+	return time.Time{}
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,248 @@
+package reconciliation
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// statementDay is the day of the movements of the tests
+var statementDay = time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+func day(offset int) time.Time {
+	return statementDay.AddDate(0, 0, offset)
+}
+
+// expectedItem is what a test checks of a reconciliation item
+type expectedItem struct {
+	status      ItemStatus
+	method      MatchMethod
+	bank        string // Description of the statement movement
+	transaction string // ID of the transaction
+	score       float64
+	confirmed   bool
+}
+
+func describe(item *Item) expectedItem {
+	described := expectedItem{
+		status:    item.Status,
+		method:    item.MatchMethod,
+		bank:      item.BankDescription,
+		score:     item.Score,
+		confirmed: item.Confirmed,
+	}
+	if item.TransactionID != nil {
+		described.transaction = *item.TransactionID
+	}
+	return described
+}
+
+func TestMatch(t *testing.T) {
+	from, to := day(-5), day(10)
+	tolerance := DefaultMatchConfig()
+	tolerance.AmountTolerance = 1
+
+	tests := []struct {
+		name     string
+		bank     []BankEntry
+		book     []BookEntry
+		config   *MatchConfig
+		expected []expectedItem
+	}{
+		{
+			name: "movement imported as the transaction",
+			bank: []BankEntry{{ImportRowID: "r1", Date: day(0), Amount: -100, Description: "ALQUILER", TransactionID: "t1"}},
+			book: []BookEntry{{TransactionID: "t1", Date: day(0), Amount: -100, Description: "Alquiler"}},
+			expected: []expectedItem{
+				{ItemStatusMatched, MatchMethodImport, "ALQUILER", "t1", 1, true},
+			},
+		},
+		{
+			name: "same bank identifier outside the date window",
+			bank: []BankEntry{{Date: day(0), Amount: -250, Description: "TRF 998877", ExternalID: "OP-1"}},
+			book: []BookEntry{{TransactionID: "t1", Date: day(6), Amount: -250, Description: "Transferencia a Juan", ExternalID: "OP-1"}},
+			expected: []expectedItem{
+				{ItemStatusMatched, MatchMethodExternalID, "TRF 998877", "t1", 1, true},
+			},
+		},
+		{
+			name: "same bank identifier with another amount",
+			bank: []BankEntry{{Date: day(0), Amount: -250, Description: "TRF 998877", ExternalID: "OP-1"}},
+			book: []BookEntry{{TransactionID: "t1", Date: day(0), Amount: -260, Description: "Transferencia", ExternalID: "OP-1"}},
+			expected: []expectedItem{
+				{ItemStatusMissingInFinTrack, "", "TRF 998877", "", 0, false},
+				{ItemStatusMissingInBank, "", "", "t1", 0, false},
+			},
+		},
+		{
+			name: "exact match waits for confirmation",
+			bank: []BankEntry{{Date: day(0), Amount: -1500, Description: "COMPRA DEB NETFLIX.COM 12345"}},
+			book: []BookEntry{{TransactionID: "t1", Date: day(0), Amount: -1500, Description: "Netflix"}},
+			expected: []expectedItem{
+				{ItemStatusMatched, MatchMethodFuzzy, "COMPRA DEB NETFLIX.COM 12345", "t1", 1, false},
+			},
+		},
+		{
+			name: "near match two days apart with another description",
+			bank: []BankEntry{{Date: day(0), Amount: -800, Description: "SUPERMERCADO DIA"}},
+			book: []BookEntry{{TransactionID: "t1", Date: day(2), Amount: -800, Description: "Compras semana"}},
+			expected: []expectedItem{
+				{ItemStatusMatched, MatchMethodFuzzy, "SUPERMERCADO DIA", "t1", 0.65, false},
+			},
+		},
+		{
+			name: "near match at the edge of the date window",
+			bank: []BankEntry{{Date: day(0), Amount: -500, Description: "SUBE RECARGA"}},
+			book: []BookEntry{{TransactionID: "t1", Date: day(3).Add(12 * time.Hour), Amount: -500, Description: "Sube"}},
+			expected: []expectedItem{
+				{ItemStatusMatched, MatchMethodFuzzy, "SUBE RECARGA", "t1", 0.74, false},
+			},
+		},
+		{
+			name: "outside the date window",
+			bank: []BankEntry{{Date: day(0), Amount: -500, Description: "SUBE RECARGA"}},
+			book: []BookEntry{{TransactionID: "t1", Date: day(4), Amount: -500, Description: "Sube"}},
+			expected: []expectedItem{
+				{ItemStatusMissingInFinTrack, "", "SUBE RECARGA", "", 0, false},
+				{ItemStatusMissingInBank, "", "", "t1", 0, false},
+			},
+		},
+		{
+			name: "amount difference without tolerance",
+			bank: []BankEntry{{Date: day(0), Amount: -1000.40, Description: "EDESUR"}},
+			book: []BookEntry{{TransactionID: "t1", Date: day(0), Amount: -1000, Description: "Edesur"}},
+			expected: []expectedItem{
+				{ItemStatusMissingInFinTrack, "", "EDESUR", "", 0, false},
+				{ItemStatusMissingInBank, "", "", "t1", 0, false},
+			},
+		},
+		{
+			name:   "amount difference within the tolerance",
+			bank:   []BankEntry{{Date: day(0), Amount: -1000.40, Description: "EDESUR"}},
+			book:   []BookEntry{{TransactionID: "t1", Date: day(0), Amount: -1000, Description: "Edesur"}},
+			config: &tolerance,
+			expected: []expectedItem{
+				{ItemStatusMatched, MatchMethodFuzzy, "EDESUR", "t1", 0.9, false},
+			},
+		},
+		{
+			name: "opposite directions",
+			bank: []BankEntry{{Date: day(0), Amount: 300, Description: "DEVOLUCION TIENDA"}},
+			book: []BookEntry{{TransactionID: "t1", Date: day(0), Amount: -300, Description: "Tienda"}},
+			expected: []expectedItem{
+				{ItemStatusMissingInFinTrack, "", "DEVOLUCION TIENDA", "", 0, false},
+				{ItemStatusMissingInBank, "", "", "t1", 0, false},
+			},
+		},
+		{
+			name: "candidate below the minimum score",
+			bank: []BankEntry{{Date: day(0), Amount: -700, Description: "YPF"}},
+			book: []BookEntry{{TransactionID: "t1", Date: day(3), Amount: -700, Description: "Shell"}},
+			expected: []expectedItem{
+				{ItemStatusMissingInFinTrack, "", "YPF", "", 0, false},
+				{ItemStatusMissingInBank, "", "", "t1", 0, false},
+			},
+		},
+		{
+			name: "ambiguous candidates: the merchant beats a closer date",
+			bank: []BankEntry{{Date: day(0), Amount: -2000, Description: "FARMACITY"}},
+			book: []BookEntry{
+				{TransactionID: "kiosco", Date: day(0), Amount: -2000, Description: "Kiosco"},
+				{TransactionID: "farmacia", Date: day(1), Amount: -2000, Description: "Farmacity"},
+			},
+			expected: []expectedItem{
+				{ItemStatusMatched, MatchMethodFuzzy, "FARMACITY", "farmacia", 0.93, false},
+				{ItemStatusMissingInBank, "", "", "kiosco", 0, false},
+			},
+		},
+		{
+			name: "ambiguous candidates: each movement with its closest transaction",
+			bank: []BankEntry{
+				{Date: day(0), Amount: -1200, Description: "UBER TRIP PALERMO"},
+				{Date: day(1), Amount: -1200, Description: "UBER TRIP BELGRANO"},
+			},
+			book: []BookEntry{
+				{TransactionID: "second", Date: day(1), Amount: -1200, Description: "Uber"},
+				{TransactionID: "first", Date: day(0), Amount: -1200, Description: "Uber"},
+			},
+			expected: []expectedItem{
+				{ItemStatusMatched, MatchMethodFuzzy, "UBER TRIP PALERMO", "first", 1, false},
+				{ItemStatusMatched, MatchMethodFuzzy, "UBER TRIP BELGRANO", "second", 1, false},
+			},
+		},
+		{
+			name: "ambiguous candidates: a transaction matches one movement only",
+			bank: []BankEntry{
+				{Date: day(0), Amount: -900, Description: "MOSTAZA"},
+				{Date: day(0), Amount: -900, Description: "MOSTAZA CABALLITO"},
+			},
+			book: []BookEntry{{TransactionID: "t1", Date: day(0), Amount: -900, Description: "Mostaza"}},
+			expected: []expectedItem{
+				{ItemStatusMatched, MatchMethodFuzzy, "MOSTAZA", "t1", 1, false},
+				{ItemStatusMissingInFinTrack, "", "MOSTAZA CABALLITO", "", 0, false},
+			},
+		},
+		{
+			name: "only transactions of the period are missing in the bank",
+			book: []BookEntry{
+				{TransactionID: "before", Date: day(-30), Amount: -50},
+				{TransactionID: "last-day", Date: to.Add(23 * time.Hour), Amount: -50},
+				{TransactionID: "after", Date: day(11), Amount: -50},
+				{TransactionID: "first-day", Date: from, Amount: -50},
+			},
+			expected: []expectedItem{
+				{ItemStatusMissingInBank, "", "", "first-day", 0, false},
+				{ItemStatusMissingInBank, "", "", "last-day", 0, false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultMatchConfig()
+			if tt.config != nil {
+				config = *tt.config
+			}
+
+			items := Match(tt.bank, tt.book, from, to, config)
+
+			if len(items) != len(tt.expected) {
+				for _, item := range items {
+					t.Logf("item %+v", describe(item))
+				}
+				t.Fatalf("Match() = %d items, want %d", len(items), len(tt.expected))
+			}
+			for i, want := range tt.expected {
+				if got := describe(items[i]); got != want {
+					t.Errorf("item %d = %+v, want %+v", i, got, want)
+				}
+				if items[i].Confirmed != (items[i].ConfirmedAt != nil) {
+					t.Errorf("item %d confirmed %v at %v", i, items[i].Confirmed, items[i].ConfirmedAt)
+				}
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"COMPRA DEB NETFLIX.COM 12345", "Netflix", 1},
+		{"MERCADOPAGO*KIOSCO", "Mercado Pago", 1},
+		{"Café Martínez", "CAFE MARTINEZ", 1},
+		{"CARREFOUR", "Carrefur", 0.8},
+		{"YPF", "Shell", 0},
+		{"COMPRA DEBITO", "Netflix", 0},
+		{"", "Netflix", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := Similarity(tt.a, tt.b); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package reconciliation
+
+import (
+	"errors"
+	"time"
+)
+
+// ItemStatus is the outcome of comparing a statement movement with the tracked transactions
+type ItemStatus string
+
+const (
+	ItemStatusMatched           ItemStatus = "matched"             // In the statement and in FinTrack
+	ItemStatusMissingInFinTrack ItemStatus = "missing_in_fintrack" // In the statement only
+	ItemStatusMissingInBank     ItemStatus = "missing_in_bank"     // In FinTrack only
+)
+
+// Source tells where the statement movements of a reconciliation came from
+type Source string
+
+const (
+	SourceImport Source = "import" // Rows of an import batch
+	SourceUpload Source = "upload" // A statement uploaded only to reconcile it
+)
+
+// MatchMethod tells how a match was made
+type MatchMethod string
+
+const (
+	MatchMethodImport     MatchMethod = "import"      // The transaction was created from the statement row
+	MatchMethodExternalID MatchMethod = "external_id" // Same bank identifier
+	MatchMethodFuzzy      MatchMethod = "fuzzy"       // Amount, date window and merchant similarity
+	MatchMethodManual     MatchMethod = "manual"      // Linked by the user
+)
+
+// Reconciliation compares one statement of an account or card with the transactions tracked in FinTrack
+type Reconciliation struct {
+	ID                     string     `json:"id"`
+	UserID                 string     `json:"userId"`
+	AccountID              *string    `json:"accountId"`
+	CardID                 *string    `json:"cardId"`
+	Currency               string     `json:"currency"`
+	Source                 Source     `json:"source"`
+	ImportBatchID          *string    `json:"importBatchId"`
+	FileName               string     `json:"fileName"`
+	StatementFrom          time.Time  `json:"statementFrom"`
+	StatementTo            time.Time  `json:"statementTo"`
+	DateWindowDays         int        `json:"dateWindowDays"`
+	AmountTolerance        float64    `json:"amountTolerance"`
+	MatchedCount           int        `json:"matchedCount"`
+	PendingCount           int        `json:"pendingCount"` // Matches waiting for the user to confirm them
+	MissingInFinTrackCount int        `json:"missingInFinTrackCount"`
+	MissingInBankCount     int        `json:"missingInBankCount"`
+	CompletedAt            *time.Time `json:"completedAt"` // When every item got confirmed
+	CreatedAt              time.Time  `json:"createdAt"`
+	UpdatedAt              time.Time  `json:"updatedAt"`
+}
+
+// Item is a statement movement, a tracked transaction, or both when they match
+type Item struct {
+	ID               string      `json:"id"`
+	ReconciliationID string      `json:"reconciliationId"`
+	Status           ItemStatus  `json:"status"`
+	MatchMethod      MatchMethod `json:"matchMethod,omitempty"`
+	Score            float64     `json:"score"` // Confidence of the match, from 0 to 1
+	Confirmed        bool        `json:"confirmed"`
+	ConfirmedAt      *time.Time  `json:"confirmedAt"`
+
+	// Statement side
+	ImportRowID     *string    `json:"importRowId"`
+	BankDate        *time.Time `json:"bankDate"`
+	BankAmount      *float64   `json:"bankAmount"` // Debits negative
+	BankDescription string     `json:"bankDescription"`
+	BankExternalID  string     `json:"bankExternalId"`
+
+	// FinTrack side
+	TransactionID          *string    `json:"transactionId"`
+	TransactionDate        *time.Time `json:"transactionDate"`
+	TransactionAmount      *float64   `json:"transactionAmount"` // Signed like the statement
+	TransactionDescription string     `json:"transactionDescription"`
+}
+
+// HasBankSide checks if the item has a statement movement
+func (i *Item) HasBankSide() bool {
+	return i.Status != ItemStatusMissingInBank
+}
+
+// IsPending checks if the item is a suggested match the user has not confirmed yet
+func (i *Item) IsPending() bool {
+	return i.Status == ItemStatusMatched && !i.Confirmed
+}
+
+// Confirm accepts a suggested match
+func (i *Item) Confirm(now time.Time) error {
+	if i.Status != ItemStatusMatched {
+		return errors.New("only matched items can be confirmed")
+	}
+	if i.Confirmed {
+		return errors.New("the match is already confirmed")
+	}
+	i.Confirmed = true
+	i.ConfirmedAt = &now
+	return nil
+}
+
+// Link matches a statement movement missing in FinTrack with a transaction chosen by the user
+func (i *Item) Link(transaction BookEntry, now time.Time) error {
+	if i.Status != ItemStatusMissingInFinTrack {
+		return errors.New("only statement movements missing in FinTrack can be linked")
+	}
+	if (*i.BankAmount < 0) != (transaction.Amount < 0) {
+		return errors.New("the transaction and the statement movement go in opposite directions")
+	}
+	i.Status = ItemStatusMatched
+	i.MatchMethod = MatchMethodManual
+	i.Score = Score(i.bankEntry(), transaction, DefaultMatchConfig())
+	i.setTransaction(transaction)
+	i.Confirmed = true
+	i.ConfirmedAt = &now
+	return nil
+}
+
+// Unlink rejects a match, returning the statement movement of the item as missing in FinTrack and
+// a new item for the transaction missing in the bank
+func (i *Item) Unlink() (*Item, error) {
+	if i.Status != ItemStatusMatched {
+		return nil, errors.New("only matched items can be unlinked")
+	}
+
+	missingInBank := &Item{
+		ReconciliationID:       i.ReconciliationID,
+		Status:                 ItemStatusMissingInBank,
+		TransactionID:          i.TransactionID,
+		TransactionDate:        i.TransactionDate,
+		TransactionAmount:      i.TransactionAmount,
+		TransactionDescription: i.TransactionDescription,
+	}
+
+	i.Status = ItemStatusMissingInFinTrack
+	i.MatchMethod = ""
+	i.Score = 0
+	i.Confirmed = false
+	i.ConfirmedAt = nil
+	i.TransactionID = nil
+	i.TransactionDate = nil
+	i.TransactionAmount = nil
+	i.TransactionDescription = ""
+
+	return missingInBank, nil
+}
+
+func (i *Item) setTransaction(transaction BookEntry) {
+	id, date, amount := transaction.TransactionID, transaction.Date, transaction.Amount
+	i.TransactionID = &id
+	i.TransactionDate = &date
+	i.TransactionAmount = &amount
+	i.TransactionDescription = transaction.Description
+}
+
+func (i *Item) bankEntry() BankEntry {
+	entry := BankEntry{Description: i.BankDescription, ExternalID: i.BankExternalID}
+	if i.BankDate != nil {
+		entry.Date = *i.BankDate
+	}
+	if i.BankAmount != nil {
+		entry.Amount = *i.BankAmount
+	}
+	return entry
+}
+
+// Summarize refreshes the counters of the reconciliation from its items. It is completed once every
+// item is a confirmed match.
+func (r *Reconciliation) Summarize(items []*Item, now time.Time) {
+	r.MatchedCount, r.PendingCount, r.MissingInFinTrackCount, r.MissingInBankCount = 0, 0, 0, 0
+	for _, item := range items {
+		switch item.Status {
+		case ItemStatusMatched:
+			r.MatchedCount++
+			if item.IsPending() {
+				r.PendingCount++
+			}
+		case ItemStatusMissingInFinTrack:
+			r.MissingInFinTrackCount++
+		case ItemStatusMissingInBank:
+			r.MissingInBankCount++
+		}
+	}
+
+	balanced := r.PendingCount == 0 && r.MissingInFinTrackCount == 0 && r.MissingInBankCount == 0
+	switch {
+	case balanced && r.CompletedAt == nil:
+		r.CompletedAt = &now
+	case !balanced:
+		r.CompletedAt = nil
+	}
+}
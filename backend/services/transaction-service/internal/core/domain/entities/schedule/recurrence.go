@@ -0,0 +1,310 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the base period of a recurrence
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+	FrequencyYearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds the search for the next occurrence of a recurrence
+const maxPeriods = 10000
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Recurrence is the subset of RFC 5545 RRULE supported by scheduled transactions:
+//
+//	FREQ=MONTHLY;BYMONTHDAY=5                         monthly on day 5 (clamped to shorter months)
+//	FREQ=MONTHLY;BYMONTHDAY=-1                        last day of the month
+//	FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1     last business day of the month
+//	FREQ=WEEKLY;INTERVAL=2                            every 2 weeks
+//	FREQ=WEEKLY;BYDAY=MO,TH                           every Monday and Thursday
+//
+// COUNT and UNTIL limit the series. Holidays are not taken into account.
+type Recurrence struct {
+	Frequency Frequency
+	Interval  int
+	MonthDay  int            // BYMONTHDAY: 1..31 or -1 for the last day; 0 uses the day of the start date
+	Weekdays  []time.Weekday // BYDAY, Monday first
+	SetPos    int            // BYSETPOS: 1 (first) or -1 (last) of the BYDAY days of the month
+	Count     int            // COUNT: 0 means unlimited
+	Until     *time.Time     // UNTIL: inclusive end of the series
+}
+
+// ParseRecurrence parses an RRULE string. An empty rule means a one-off schedule and returns nil.
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	rule = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"))
+	if rule == "" {
+		return nil, nil
+	}
+
+	r := &Recurrence{Interval: 1}
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid recurrence part %q", part)
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+
+		switch key {
+		case "FREQ":
+			r.Frequency = Frequency(value)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > 366 {
+				return nil, errors.New("recurrence INTERVAL must be between 1 and 366")
+			}
+			r.Interval = interval
+		case "BYMONTHDAY":
+			day, err := strconv.Atoi(value)
+			if err != nil || day == 0 || day < -1 || day > 31 {
+				return nil, errors.New("recurrence BYMONTHDAY must be between 1 and 31, or -1 for the last day")
+			}
+			r.MonthDay = day
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				weekday, ok := weekdayCodes[strings.TrimSpace(code)]
+				if !ok {
+					return nil, fmt.Errorf("invalid recurrence BYDAY value %q", code)
+				}
+				r.Weekdays = append(r.Weekdays, weekday)
+			}
+		case "BYSETPOS":
+			pos, err := strconv.Atoi(value)
+			if err != nil || (pos != 1 && pos != -1) {
+				return nil, errors.New("recurrence BYSETPOS must be 1 or -1")
+			}
+			r.SetPos = pos
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, errors.New("recurrence COUNT must be a positive number")
+			}
+			r.Count = count
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = &until
+		default:
+			return nil, fmt.Errorf("unsupported recurrence part %s", key)
+		}
+	}
+
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(r.Weekdays, func(i, j int) bool { return mondayIndex(r.Weekdays[i]) < mondayIndex(r.Weekdays[j]) })
+	return r, nil
+}
+
+func (r *Recurrence) validate() error {
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	case "":
+		return errors.New("recurrence FREQ is required")
+	default:
+		return fmt.Errorf("unsupported recurrence FREQ %s", r.Frequency)
+	}
+
+	if r.MonthDay != 0 && r.Frequency != FrequencyMonthly && r.Frequency != FrequencyYearly {
+		return errors.New("recurrence BYMONTHDAY is only supported with MONTHLY or YEARLY")
+	}
+	if len(r.Weekdays) > 0 {
+		switch {
+		case r.Frequency == FrequencyWeekly && r.SetPos == 0:
+		case r.Frequency == FrequencyMonthly && r.SetPos != 0 && r.MonthDay == 0:
+		default:
+			return errors.New("recurrence BYDAY is only supported with WEEKLY, or MONTHLY with BYSETPOS")
+		}
+	}
+	if r.SetPos != 0 && len(r.Weekdays) == 0 {
+		return errors.New("recurrence BYSETPOS requires BYDAY")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return errors.New("recurrence cannot have both COUNT and UNTIL")
+	}
+	return nil
+}
+
+// String renders the recurrence as an RRULE string
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if r.MonthDay != 0 {
+		parts = append(parts, fmt.Sprintf("BYMONTHDAY=%d", r.MonthDay))
+	}
+	if len(r.Weekdays) > 0 {
+		codes := make([]string, len(r.Weekdays))
+		for i, weekday := range r.Weekdays {
+			codes[i] = strings.ToUpper(weekday.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.SetPos != 0 {
+		parts = append(parts, fmt.Sprintf("BYSETPOS=%d", r.SetPos))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence of the series started at start that is strictly after the given
+// time. It returns false when the series ended (UNTIL); COUNT is enforced by the caller.
+func (r *Recurrence) Next(start, after time.Time) (time.Time, bool) {
+	for period := r.firstPeriod(start, after); period < maxPeriods; period++ {
+		for _, candidate := range r.occurrencesInPeriod(start, period*r.Interval) {
+			if candidate.Before(start) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return time.Time{}, false
+			}
+			if candidate.After(after) {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// firstPeriod estimates the first period that can hold an occurrence after the given time,
+// so long running series do not iterate from the start date
+func (r *Recurrence) firstPeriod(start, after time.Time) int {
+	if !after.After(start) {
+		return 0
+	}
+
+	var elapsed int
+	switch r.Frequency {
+	case FrequencyDaily:
+		elapsed = int(after.Sub(start).Hours() / 24)
+	case FrequencyWeekly:
+		elapsed = int(after.Sub(start).Hours() / (24 * 7))
+	case FrequencyMonthly:
+		elapsed = (after.Year()-start.Year())*12 + int(after.Month()) - int(start.Month())
+	case FrequencyYearly:
+		elapsed = after.Year() - start.Year()
+	}
+
+	period := elapsed/r.Interval - 1
+	if period < 0 {
+		return 0
+	}
+	return period
+}
+
+// occurrencesInPeriod returns the occurrences of the period offset units after the start, in order
+func (r *Recurrence) occurrencesInPeriod(start time.Time, offset int) []time.Time {
+	switch r.Frequency {
+	case FrequencyDaily:
+		return []time.Time{start.AddDate(0, 0, offset)}
+
+	case FrequencyWeekly:
+		if len(r.Weekdays) == 0 {
+			return []time.Time{start.AddDate(0, 0, 7*offset)}
+		}
+		weekStart := start.AddDate(0, 0, 7*offset-mondayIndex(start.Weekday()))
+		occurrences := make([]time.Time, 0, len(r.Weekdays))
+		for _, weekday := range r.Weekdays {
+			occurrences = append(occurrences, weekStart.AddDate(0, 0, mondayIndex(weekday)))
+		}
+		return occurrences
+
+	case FrequencyMonthly:
+		year, month := start.Year(), start.Month()+time.Month(offset)
+		if len(r.Weekdays) > 0 {
+			return []time.Time{r.setPosDay(start, year, month)}
+		}
+		return []time.Time{dayInMonth(start, year, month, r.monthDay(start))}
+
+	case FrequencyYearly:
+		return []time.Time{dayInMonth(start, start.Year()+offset, start.Month(), r.monthDay(start))}
+	}
+	return nil
+}
+
+func (r *Recurrence) monthDay(start time.Time) int {
+	if r.MonthDay != 0 {
+		return r.MonthDay
+	}
+	return start.Day()
+}
+
+// setPosDay returns the first or last day of the month that falls on one of the BYDAY weekdays
+func (r *Recurrence) setPosDay(start time.Time, year int, month time.Month) time.Time {
+	last := daysIn(year, month)
+	for i := 0; i < last; i++ {
+		day := 1 + i
+		if r.SetPos < 0 {
+			day = last - i
+		}
+		candidate := dayInMonth(start, year, month, day)
+		for _, weekday := range r.Weekdays {
+			if candidate.Weekday() == weekday {
+				return candidate
+			}
+		}
+	}
+	return dayInMonth(start, year, month, last)
+}
+
+// dayInMonth builds the given day of the month at the time of day of the start. Days past the end
+// of the month (and -1) are clamped to the last day.
+func dayInMonth(start time.Time, year int, month time.Month, day int) time.Time {
+	normalized := time.Date(year, month, 1, 0, 0, 0, 0, start.Location())
+	last := daysIn(normalized.Year(), normalized.Month())
+	if day < 0 || day > last {
+		day = last
+	}
+	return time.Date(normalized.Year(), normalized.Month(), day,
+		start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func mondayIndex(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102", "2006-01-02", time.RFC3339} {
+		if until, err := time.Parse(layout, value); err == nil {
+			if len(value) == 8 || len(value) == 10 {
+				// A date UNTIL includes the whole day
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid recurrence UNTIL %q", value)
+}
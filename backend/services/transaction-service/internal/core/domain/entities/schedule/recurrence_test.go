@@ -0,0 +1,350 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// date builds a time of the test series at 09:00 UTC
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+}
+
+// expand returns up to n occurrences of the rule from the start date, like the scheduler walks them
+func expand(t *testing.T, rule string, start time.Time, n int) []string {
+	t.Helper()
+	recurrence, err := ParseRecurrence(rule)
+	if err != nil {
+		t.Fatalf("ParseRecurrence(%q) unexpected error: %v", rule, err)
+	}
+
+	var dates []string
+	after := start.Add(-time.Nanosecond)
+	for len(dates) < n {
+		next, ok := recurrence.Next(start, after)
+		if !ok {
+			break
+		}
+		if next.Hour() != 9 || next.Minute() != 0 {
+			t.Errorf("Next() = %v, want the time of day of the start date", next)
+		}
+		dates = append(dates, next.Format("2006-01-02"))
+		after = next
+	}
+	return dates
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		n        int
+		expected string
+	}{
+		{
+			name:     "rent on day 10",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=10",
+			start:    date(2025, 1, 1),
+			n:        4,
+			expected: "2025-01-10,2025-02-10,2025-03-10,2025-04-10",
+		},
+		{
+			name:     "day of the start date",
+			rule:     "FREQ=MONTHLY",
+			start:    date(2025, 1, 15),
+			n:        3,
+			expected: "2025-01-15,2025-02-15,2025-03-15",
+		},
+		{
+			name:     "day 31 clamped to short months",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=31",
+			start:    date(2025, 1, 1),
+			n:        5,
+			expected: "2025-01-31,2025-02-28,2025-03-31,2025-04-30,2025-05-31",
+		},
+		{
+			name:     "day 30 in a leap year",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=30",
+			start:    date(2024, 1, 30),
+			n:        3,
+			expected: "2024-01-30,2024-02-29,2024-03-30",
+		},
+		{
+			name:     "last day of the month",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start:    date(2023, 12, 15),
+			n:        5,
+			expected: "2023-12-31,2024-01-31,2024-02-29,2024-03-31,2024-04-30",
+		},
+		{
+			name:     "salary on the last business day",
+			rule:     "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			start:    date(2025, 1, 1),
+			n:        8,
+			expected: "2025-01-31,2025-02-28,2025-03-31,2025-04-30,2025-05-30,2025-06-30,2025-07-31,2025-08-29",
+		},
+		{
+			name:     "first business day",
+			rule:     "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=1",
+			start:    date(2025, 5, 2),
+			n:        3,
+			expected: "2025-06-02,2025-07-01,2025-08-01",
+		},
+		{
+			name:     "last business day skips the start month when it already passed",
+			rule:     "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			start:    date(2025, 5, 31),
+			n:        2,
+			expected: "2025-06-30,2025-07-31",
+		},
+		{
+			name:     "every 2 weeks",
+			rule:     "FREQ=WEEKLY;INTERVAL=2",
+			start:    date(2025, 1, 3),
+			n:        5,
+			expected: "2025-01-03,2025-01-17,2025-01-31,2025-02-14,2025-02-28",
+		},
+		{
+			name:     "every Monday and Thursday",
+			rule:     "FREQ=WEEKLY;BYDAY=TH,MO",
+			start:    date(2025, 1, 1),
+			n:        4,
+			expected: "2025-01-02,2025-01-06,2025-01-09,2025-01-13",
+		},
+		{
+			name:     "Monday and Thursday every 2 weeks",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start:    date(2025, 1, 6),
+			n:        4,
+			expected: "2025-01-06,2025-01-09,2025-01-20,2025-01-23",
+		},
+		{
+			name:     "every 3 days across a month",
+			rule:     "FREQ=DAILY;INTERVAL=3",
+			start:    date(2025, 2, 25),
+			n:        3,
+			expected: "2025-02-25,2025-02-28,2025-03-03",
+		},
+		{
+			name:     "quarterly subscription",
+			rule:     "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=31",
+			start:    date(2025, 1, 31),
+			n:        4,
+			expected: "2025-01-31,2025-04-30,2025-07-31,2025-10-31",
+		},
+		{
+			name:     "yearly on February 29",
+			rule:     "FREQ=YEARLY",
+			start:    date(2024, 2, 29),
+			n:        5,
+			expected: "2024-02-29,2025-02-28,2026-02-28,2027-02-28,2028-02-29",
+		},
+		{
+			name:     "until a date includes the whole day",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=5;UNTIL=20250305",
+			start:    date(2025, 1, 1),
+			n:        10,
+			expected: "2025-01-05,2025-02-05,2025-03-05",
+		},
+		{
+			name:     "until a time excludes later occurrences that day",
+			rule:     "FREQ=DAILY;UNTIL=20250103T080000Z",
+			start:    date(2025, 1, 1),
+			n:        10,
+			expected: "2025-01-01,2025-01-02",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(expand(t, tt.rule, tt.start, tt.n), ",")
+			if got != tt.expected {
+				t.Errorf("occurrences of %s = %s, want %s", tt.rule, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRecurrenceNextAfterManyPeriods(t *testing.T) {
+	tests := []struct {
+		rule     string
+		start    time.Time
+		after    time.Time
+		expected time.Time
+	}{
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", date(2015, 1, 31), date(2025, 2, 10), date(2025, 2, 28)},
+		{"FREQ=WEEKLY;INTERVAL=2", date(2015, 1, 2), date(2025, 1, 3), date(2025, 1, 17)},
+		{"FREQ=DAILY", date(2015, 1, 1), date(2025, 6, 30), date(2025, 7, 1)},
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", date(2015, 1, 1), date(2025, 5, 30), date(2025, 6, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			recurrence, err := ParseRecurrence(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrence() unexpected error: %v", err)
+			}
+			next, ok := recurrence.Next(tt.start, tt.after)
+			if !ok || !next.Equal(tt.expected) {
+				t.Errorf("Next() = %v, %v, want %v", next, ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        string
+		expected    string
+		expectError bool
+	}{
+		{"empty rule is one-off", "  ", "", false},
+		{"normalized", "RRULE:freq=weekly;interval=2;byday=th,mo", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", false},
+		{"interval of 1 omitted", "FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=-1", "FREQ=MONTHLY;BYMONTHDAY=-1", false},
+		{"count", "FREQ=MONTHLY;BYMONTHDAY=5;COUNT=12", "FREQ=MONTHLY;BYMONTHDAY=5;COUNT=12", false},
+		{"until date", "FREQ=DAILY;UNTIL=2025-03-05", "FREQ=DAILY;UNTIL=20250305T235959Z", false},
+		{"last business day", "FREQ=MONTHLY;BYDAY=FR,MO,TU,WE,TH;BYSETPOS=-1", "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", false},
+		{"missing frequency", "INTERVAL=2", "", true},
+		{"unsupported frequency", "FREQ=HOURLY", "", true},
+		{"count and until", "FREQ=DAILY;COUNT=3;UNTIL=20250305", "", true},
+		{"zero interval", "FREQ=DAILY;INTERVAL=0", "", true},
+		{"month day 0", "FREQ=MONTHLY;BYMONTHDAY=0", "", true},
+		{"month day -2", "FREQ=MONTHLY;BYMONTHDAY=-2", "", true},
+		{"month day on weekly", "FREQ=WEEKLY;BYMONTHDAY=5", "", true},
+		{"weekdays on monthly without set position", "FREQ=MONTHLY;BYDAY=MO", "", true},
+		{"set position without weekdays", "FREQ=MONTHLY;BYSETPOS=-1", "", true},
+		{"set position -2", "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=-2", "", true},
+		{"invalid weekday", "FREQ=WEEKLY;BYDAY=XX", "", true},
+		{"invalid until", "FREQ=DAILY;UNTIL=tomorrow", "", true},
+		{"unsupported part", "FREQ=DAILY;BYHOUR=9", "", true},
+		{"part without value", "FREQ=DAILY;COUNT", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurrence, err := ParseRecurrence(tt.rule)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseRecurrence(%q) expected error but got %v", tt.rule, recurrence)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRecurrence(%q) unexpected error: %v", tt.rule, err)
+			}
+			if tt.expected == "" {
+				if recurrence != nil {
+					t.Errorf("ParseRecurrence(%q) = %v, want nil", tt.rule, recurrence)
+				}
+				return
+			}
+			if got := recurrence.String(); got != tt.expected {
+				t.Errorf("ParseRecurrence(%q) = %s, want %s", tt.rule, got, tt.expected)
+			}
+		})
+	}
+}
+
+// runSchedule initializes a schedule and advances it until it completes, returning the occurrences
+func runSchedule(t *testing.T, rule string, start time.Time) []string {
+	t.Helper()
+	schedule := &ScheduledTransaction{UserID: "user-1", Recurrence: rule, StartAt: start}
+	if err := schedule.Initialize(); err != nil {
+		t.Fatalf("Initialize() unexpected error: %v", err)
+	}
+
+	var dates []string
+	for i := 0; schedule.Status == ScheduleStatusActive && i < 50; i++ {
+		dates = append(dates, schedule.NextOccurrenceAt.Format("2006-01-02"))
+		if err := schedule.Advance(); err != nil {
+			t.Fatalf("Advance() unexpected error: %v", err)
+		}
+	}
+	if schedule.Status != ScheduleStatusCompleted || schedule.NextRunAt != nil {
+		t.Errorf("schedule = %s with next run %v, want completed", schedule.Status, schedule.NextRunAt)
+	}
+	if schedule.OccurrenceCount != len(dates) {
+		t.Errorf("OccurrenceCount = %d, want %d", schedule.OccurrenceCount, len(dates))
+	}
+	return dates
+}
+
+func TestScheduleSeriesEnd(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		expected string
+	}{
+		{
+			name:     "one-off future-dated transfer",
+			start:    date(2025, 7, 15),
+			expected: "2025-07-15",
+		},
+		{
+			name:     "count limits the occurrences",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;COUNT=3",
+			start:    date(2025, 1, 3),
+			expected: "2025-01-03,2025-01-17,2025-01-31",
+		},
+		{
+			name:     "count of the last day of the month",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			start:    date(2024, 1, 1),
+			expected: "2024-01-31,2024-02-29,2024-03-31",
+		},
+		{
+			name:     "until ends the series",
+			rule:     "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;UNTIL=20250331",
+			start:    date(2025, 1, 1),
+			expected: "2025-01-31,2025-02-28,2025-03-31",
+		},
+		{
+			name:     "until before the next occurrence",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=10;UNTIL=20250309",
+			start:    date(2025, 1, 1),
+			expected: "2025-01-10,2025-02-10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(runSchedule(t, tt.rule, tt.start), ",")
+			if got != tt.expected {
+				t.Errorf("occurrences of %q = %s, want %s", tt.rule, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScheduleInitializeWithoutOccurrences(t *testing.T) {
+	schedule := &ScheduledTransaction{
+		UserID:     "user-1",
+		Recurrence: "FREQ=MONTHLY;BYMONTHDAY=10;UNTIL=20250105",
+		StartAt:    date(2025, 1, 1),
+	}
+	if err := schedule.Initialize(); err == nil {
+		t.Error("Initialize() of a series ending before its first occurrence expected error but got none")
+	}
+}
+
+func TestScheduleUpcoming(t *testing.T) {
+	schedule := &ScheduledTransaction{UserID: "user-1", Recurrence: "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=4", StartAt: date(2025, 1, 1)}
+	if err := schedule.Initialize(); err != nil {
+		t.Fatalf("Initialize() unexpected error: %v", err)
+	}
+	if err := schedule.Advance(); err != nil {
+		t.Fatalf("Advance() unexpected error: %v", err)
+	}
+
+	var dates []string
+	for _, upcoming := range schedule.Upcoming(10) {
+		dates = append(dates, upcoming.Format("2006-01-02"))
+	}
+	if got := strings.Join(dates, ","); got != "2025-02-28,2025-03-31,2025-04-30" {
+		t.Errorf("Upcoming() = %s, want the 3 remaining occurrences", got)
+	}
+}
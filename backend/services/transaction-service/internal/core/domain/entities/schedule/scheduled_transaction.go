@@ -0,0 +1,346 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// ScheduleStatus represents the lifecycle of a scheduled transaction
+type ScheduleStatus string
+
+const (
+	ScheduleStatusActive    ScheduleStatus = "active"
+	ScheduleStatusPaused    ScheduleStatus = "paused"
+	ScheduleStatusCompleted ScheduleStatus = "completed" // One-off executed or series ended
+	ScheduleStatusCanceled  ScheduleStatus = "canceled"
+)
+
+// RunStatus is the outcome of one occurrence of a scheduled transaction
+type RunStatus string
+
+const (
+	RunStatusProcessing RunStatus = "processing"
+	RunStatusExecuted   RunStatus = "executed"
+	RunStatusSkipped    RunStatus = "skipped"
+	RunStatusFailed     RunStatus = "failed"
+)
+
+// MaxConsecutiveFailures pauses a schedule after this many failed occurrences in a row
+const MaxConsecutiveFailures = 3
+
+// ScheduledTransaction is a template that materializes transactions on a schedule: once at a future
+// date when Recurrence is empty, or repeatedly following an RRULE.
+type ScheduledTransaction struct {
+	ID     string `json:"id"`
+	UserID string `json:"userId"`
+
+	// Template of the transactions to create
+	Type          domaintransaction.TransactionType `json:"type"`
+	Amount        float64                           `json:"amount"`
+	Currency      string                            `json:"currency"`
+	FromAccountID *string                           `json:"fromAccountId"`
+	ToAccountID   *string                           `json:"toAccountId"`
+	FromCardID    *string                           `json:"fromCardId"`
+	ToCardID      *string                           `json:"toCardId"`
+	Description   string                            `json:"description"`
+	MerchantName  string                            `json:"merchantName"`
+	CategoryID    *string                           `json:"categoryId"`
+
+	// Schedule
+	Recurrence string         `json:"recurrence"` // RRULE subset, empty for one-off transactions
+	StartAt    time.Time      `json:"startAt"`
+	Status     ScheduleStatus `json:"status"`
+
+	// Next occurrence. NextOccurrenceAt follows the schedule; NextRunAt is when it will actually run
+	// and only differs when the next occurrence was moved.
+	NextOccurrenceAt *time.Time `json:"nextOccurrenceAt"`
+	NextRunAt        *time.Time `json:"nextRunAt"`
+	NextAmount       *float64   `json:"nextAmount"`      // Overrides the amount of the next occurrence only
+	NextDescription  *string    `json:"nextDescription"` // Overrides the description of the next occurrence only
+
+	// Execution tracking
+	OccurrenceCount     int        `json:"occurrenceCount"` // Occurrences consumed: executed, skipped or failed
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastRunAt           *time.Time `json:"lastRunAt"`
+	LastError           string     `json:"lastError"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ScheduledRun records what happened with one occurrence of a scheduled transaction
+type ScheduledRun struct {
+	ID            string    `json:"id"`
+	ScheduleID    string    `json:"scheduleId"`
+	ScheduledFor  time.Time `json:"scheduledFor"` // Date of the occurrence in the schedule
+	Status        RunStatus `json:"status"`
+	TransactionID *string   `json:"transactionId"`
+	Amount        float64   `json:"amount"`
+	Error         string    `json:"error"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// IsRecurring checks if the schedule repeats
+func (s *ScheduledTransaction) IsRecurring() bool {
+	return s.Recurrence != ""
+}
+
+// IsFinished checks if the schedule will not run anymore
+func (s *ScheduledTransaction) IsFinished() bool {
+	return s.Status == ScheduleStatusCompleted || s.Status == ScheduleStatusCanceled
+}
+
+// IsDue checks if the next occurrence has to run at the given time
+func (s *ScheduledTransaction) IsDue(now time.Time) bool {
+	return s.Status == ScheduleStatusActive && s.NextRunAt != nil && !s.NextRunAt.After(now)
+}
+
+// ParsedRecurrence parses the recurrence rule (nil for one-off schedules)
+func (s *ScheduledTransaction) ParsedRecurrence() (*Recurrence, error) {
+	return ParseRecurrence(s.Recurrence)
+}
+
+// Validate validates the template and the schedule
+func (s *ScheduledTransaction) Validate() error {
+	if s.UserID == "" {
+		return errors.New("user ID is required")
+	}
+	if s.StartAt.IsZero() {
+		return errors.New("start date is required")
+	}
+
+	recurrence, err := s.ParsedRecurrence()
+	if err != nil {
+		return err
+	}
+	if recurrence != nil {
+		s.Recurrence = recurrence.String()
+	}
+
+	// The template must be a valid transaction
+	template := s.NextTransactionTemplate()
+	template.Status = domaintransaction.TransactionStatusPending
+	template.InitiatedBy = s.UserID
+	return template.Validate()
+}
+
+// Initialize sets the first occurrence: the start date for one-off schedules, or the first date of the series
+func (s *ScheduledTransaction) Initialize() error {
+	s.Status = ScheduleStatusActive
+	s.OccurrenceCount = 0
+	s.ConsecutiveFailures = 0
+	s.NextAmount = nil
+	s.NextDescription = nil
+
+	first := s.StartAt
+	recurrence, err := s.ParsedRecurrence()
+	if err != nil {
+		return err
+	}
+	if recurrence != nil {
+		next, ok := recurrence.Next(s.StartAt, s.StartAt.Add(-time.Nanosecond))
+		if !ok {
+			return errors.New("recurrence has no occurrences after the start date")
+		}
+		first = next
+	}
+
+	s.NextOccurrenceAt = &first
+	s.NextRunAt = &first
+	return nil
+}
+
+// Advance consumes the next occurrence and moves to the following one, completing the schedule when
+// the series ends. Overrides of the consumed occurrence are discarded.
+func (s *ScheduledTransaction) Advance() error {
+	if s.NextOccurrenceAt == nil {
+		return errors.New("schedule has no pending occurrences")
+	}
+
+	s.OccurrenceCount++
+	s.NextAmount = nil
+	s.NextDescription = nil
+
+	recurrence, err := s.ParsedRecurrence()
+	if err != nil {
+		return err
+	}
+	if recurrence == nil || (recurrence.Count > 0 && s.OccurrenceCount >= recurrence.Count) {
+		s.complete()
+		return nil
+	}
+
+	next, ok := recurrence.Next(s.StartAt, *s.NextOccurrenceAt)
+	if !ok {
+		s.complete()
+		return nil
+	}
+	s.NextOccurrenceAt = &next
+	s.NextRunAt = &next
+	return nil
+}
+
+// RecordSuccess registers an executed occurrence
+func (s *ScheduledTransaction) RecordSuccess(at time.Time) {
+	s.LastRunAt = &at
+	s.LastError = ""
+	s.ConsecutiveFailures = 0
+}
+
+// RecordFailure registers a failed occurrence and pauses the schedule after too many failures in a
+// row. It returns true when the schedule was paused.
+func (s *ScheduledTransaction) RecordFailure(at time.Time, reason string) bool {
+	s.LastRunAt = &at
+	s.LastError = reason
+	s.ConsecutiveFailures++
+	if s.ConsecutiveFailures >= MaxConsecutiveFailures && s.Status == ScheduleStatusActive {
+		s.Status = ScheduleStatusPaused
+		return true
+	}
+	return false
+}
+
+// Pause stops materializing occurrences until the schedule is resumed
+func (s *ScheduledTransaction) Pause() error {
+	if s.Status != ScheduleStatusActive {
+		return fmt.Errorf("only active schedules can be paused (status: %s)", s.Status)
+	}
+	s.Status = ScheduleStatusPaused
+	return nil
+}
+
+// Resume reactivates a paused schedule. Occurrences that fell due while it was paused are
+// skipped and returned so they can be recorded.
+func (s *ScheduledTransaction) Resume(now time.Time) ([]time.Time, error) {
+	if s.Status != ScheduleStatusPaused {
+		return nil, fmt.Errorf("only paused schedules can be resumed (status: %s)", s.Status)
+	}
+	s.Status = ScheduleStatusActive
+	s.ConsecutiveFailures = 0
+
+	var skipped []time.Time
+	for s.Status == ScheduleStatusActive && s.IsRecurring() && s.NextRunAt != nil && s.NextRunAt.Before(now) {
+		skipped = append(skipped, *s.NextOccurrenceAt)
+		if err := s.Advance(); err != nil {
+			return nil, err
+		}
+	}
+	return skipped, nil
+}
+
+// Cancel stops the schedule permanently
+func (s *ScheduledTransaction) Cancel() error {
+	if s.IsFinished() {
+		return fmt.Errorf("schedule is already %s", s.Status)
+	}
+	s.Status = ScheduleStatusCanceled
+	s.NextOccurrenceAt = nil
+	s.NextRunAt = nil
+	return nil
+}
+
+// EditNextOccurrence changes the amount, description or date of the next occurrence only
+func (s *ScheduledTransaction) EditNextOccurrence(amount *float64, description *string, runAt *time.Time, now time.Time) error {
+	if s.IsFinished() || s.NextOccurrenceAt == nil {
+		return errors.New("schedule has no pending occurrences")
+	}
+	if amount != nil {
+		if *amount <= 0 {
+			return errors.New("amount must be greater than 0")
+		}
+		s.NextAmount = amount
+	}
+	if description != nil {
+		s.NextDescription = description
+	}
+	if runAt != nil {
+		if runAt.Before(now) {
+			return errors.New("the next occurrence cannot be moved to the past")
+		}
+		if s.IsRecurring() {
+			// It cannot jump over the occurrence that follows it
+			if following, ok := s.followingOccurrence(); ok && !runAt.Before(following) {
+				return fmt.Errorf("the next occurrence must run before the following one (%s)", following.Format("2006-01-02"))
+			}
+		}
+		s.NextRunAt = runAt
+		if !s.IsRecurring() {
+			s.NextOccurrenceAt = runAt
+		}
+	}
+	return nil
+}
+
+// Upcoming returns up to n next run dates of the schedule
+func (s *ScheduledTransaction) Upcoming(n int) []time.Time {
+	if s.IsFinished() || s.NextRunAt == nil || n <= 0 {
+		return []time.Time{}
+	}
+
+	dates := []time.Time{*s.NextRunAt}
+	recurrence, err := s.ParsedRecurrence()
+	if err != nil || recurrence == nil {
+		return dates
+	}
+
+	current := *s.NextOccurrenceAt
+	for count := s.OccurrenceCount + 1; len(dates) < n; count++ {
+		if recurrence.Count > 0 && count >= recurrence.Count {
+			break
+		}
+		next, ok := recurrence.Next(s.StartAt, current)
+		if !ok {
+			break
+		}
+		dates = append(dates, next)
+		current = next
+	}
+	return dates
+}
+
+// NextTransactionTemplate builds the transaction of the next occurrence with its overrides applied
+func (s *ScheduledTransaction) NextTransactionTemplate() *domaintransaction.Transaction {
+	amount := s.Amount
+	if s.NextAmount != nil {
+		amount = *s.NextAmount
+	}
+	description := s.Description
+	if s.NextDescription != nil {
+		description = *s.NextDescription
+	}
+
+	return &domaintransaction.Transaction{
+		Type:          s.Type,
+		UserID:        s.UserID,
+		Amount:        amount,
+		Currency:      s.Currency,
+		FromAccountID: s.FromAccountID,
+		ToAccountID:   s.ToAccountID,
+		FromCardID:    s.FromCardID,
+		ToCardID:      s.ToCardID,
+		Description:   description,
+		MerchantName:  s.MerchantName,
+		CategoryID:    s.CategoryID,
+	}
+}
+
+func (s *ScheduledTransaction) followingOccurrence() (time.Time, bool) {
+	recurrence, err := s.ParsedRecurrence()
+	if err != nil || recurrence == nil || s.NextOccurrenceAt == nil {
+		return time.Time{}, false
+	}
+	if recurrence.Count > 0 && s.OccurrenceCount+1 >= recurrence.Count {
+		return time.Time{}, false
+	}
+	return recurrence.Next(s.StartAt, *s.NextOccurrenceAt)
+}
+
+func (s *ScheduledTransaction) complete() {
+	s.Status = ScheduleStatusCompleted
+	s.NextOccurrenceAt = nil
+	s.NextRunAt = nil
+}
@@ -0,0 +1,70 @@
+package statement
+
+// Column mappings of the CSV exports of common Argentine banks and Mercado Pago. The header of the
+// movements is found after the preamble of each export; alternative headers cover the variants of
+// the home banking and the mobile app.
+var bankMappings = map[string]CSVMapping{
+	"galicia": {
+		Name:              "Banco Galicia",
+		DateColumn:        "Fecha",
+		DescriptionColumn: "Descripción|Concepto",
+		DebitColumn:       "Débitos|Débito",
+		CreditColumn:      "Créditos|Crédito",
+		BalanceColumn:     "Saldo",
+		ReferenceColumn:   "Número de Comprobante|Comprobante",
+		DecimalSeparator:  ",",
+		Currency:          "ARS",
+	},
+	"santander": {
+		Name:              "Santander Argentina",
+		DateColumn:        "Fecha",
+		DescriptionColumn: "Descripción|Concepto",
+		DebitColumn:       "Débito|Débitos",
+		CreditColumn:      "Crédito|Créditos",
+		BalanceColumn:     "Saldo",
+		ReferenceColumn:   "Referencia|Comprobante",
+		DecimalSeparator:  ",",
+		Currency:          "ARS",
+	},
+	"bbva": {
+		Name:              "BBVA Argentina",
+		DateColumn:        "Fecha|Fecha Operación",
+		DescriptionColumn: "Concepto|Descripción",
+		DebitColumn:       "Débito|Débitos",
+		CreditColumn:      "Crédito|Créditos",
+		BalanceColumn:     "Saldo",
+		ReferenceColumn:   "Número de Comprobante|Comprobante",
+		DecimalSeparator:  ",",
+		Currency:          "ARS",
+	},
+	"nacion": {
+		Name:              "Banco de la Nación Argentina",
+		DateColumn:        "Fecha",
+		DescriptionColumn: "Concepto|Descripción",
+		AmountColumn:      "Importe|Monto",
+		BalanceColumn:     "Saldo",
+		ReferenceColumn:   "Comprobante|Nro. Comprobante",
+		DecimalSeparator:  ",",
+		Currency:          "ARS",
+	},
+	"mercadopago": {
+		Name:              "Mercado Pago",
+		DateColumn:        "RELEASE_DATE|Fecha de liberación|Fecha",
+		DateFormat:        "DD-MM-YYYY",
+		DescriptionColumn: "TRANSACTION_TYPE|Descripción|Tipo de operación",
+		AmountColumn:      "TRANSACTION_NET_AMOUNT|Monto neto|Monto",
+		BalanceColumn:     "PARTIAL_BALANCE|Saldo parcial|Saldo",
+		ReferenceColumn:   "REFERENCE_ID|ID de operación|Número de operación",
+		Currency:          "ARS",
+	},
+}
+
+func init() {
+	for name, mapping := range bankMappings {
+		parser, err := NewCSVParser(mapping)
+		if err != nil {
+			panic("invalid mapping of bank parser " + name + ": " + err.Error())
+		}
+		RegisterBankParser(name, parser)
+	}
+}
@@ -0,0 +1,240 @@
+package statement
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSVParser reads CSV exports with a column mapping
+type CSVParser struct {
+	mapping CSVMapping
+	layout  string
+}
+
+// NewCSVParser creates a parser for the given column mapping
+func NewCSVParser(mapping CSVMapping) (*CSVParser, error) {
+	if err := mapping.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid column mapping: %w", err)
+	}
+	layout, err := dateLayout(mapping.DateFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &CSVParser{mapping: mapping, layout: layout}, nil
+}
+
+// csvColumns are the positions of the mapped columns in the header, -1 when not mapped
+type csvColumns struct {
+	date, amount, debit, credit, description, merchant, reference, balance, currency int
+}
+
+// Parse reads the movements of the file. The header is the first line after the skipped rows that has
+// the date column, so bank preambles (account holder, period) are skipped.
+func (p *CSVParser) Parse(data []byte) (*ParseResult, error) {
+	lines := strings.Split(strings.ReplaceAll(decodeText(data), "\r\n", "\n"), "\n")
+	if p.mapping.SkipRows >= len(lines) {
+		return nil, errors.New("the file has no rows after the skipped ones")
+	}
+	lines = lines[p.mapping.SkipRows:]
+
+	delimiter := p.delimiter(lines)
+	reader := csv.NewReader(strings.NewReader(strings.Join(lines, "\n")))
+	reader.Comma = delimiter
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	result := &ParseResult{}
+	var columns *csvColumns
+	rowNumber := 0
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV file: %w", err)
+		}
+
+		if columns == nil {
+			columns = p.findColumns(fields)
+			if columns != nil {
+				if err := p.checkColumns(columns); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if !p.hasAmount(fields, columns) {
+			continue
+		}
+
+		rowNumber++
+		record, err := p.parseRow(fields, columns)
+		if err != nil {
+			result.Errors = append(result.Errors, RowError{RowNumber: rowNumber, Message: err.Error()})
+			continue
+		}
+		record.RowNumber = rowNumber
+		result.Records = append(result.Records, record)
+	}
+
+	if columns == nil {
+		return nil, fmt.Errorf("no header with column %q in the file", p.mapping.DateColumn)
+	}
+	return result, nil
+}
+
+// delimiter returns the mapped delimiter or the most frequent of ";", "," and tab in the first lines
+func (p *CSVParser) delimiter(lines []string) rune {
+	if p.mapping.Delimiter != "" {
+		r, _ := utf8.DecodeRuneInString(p.mapping.Delimiter)
+		return r
+	}
+
+	sample := strings.Join(lines[:min(len(lines), 20)], "\n")
+	best, bestCount := ',', 0
+	for _, candidate := range []rune{';', ',', '\t'} {
+		if count := strings.Count(sample, string(candidate)); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best
+}
+
+// findColumns returns the mapped columns when the row is the header, nil otherwise
+func (p *CSVParser) findColumns(fields []string) *csvColumns {
+	headers := make(map[string]int, len(fields))
+	for i, field := range fields {
+		if _, exists := headers[normalizeHeader(field)]; !exists {
+			headers[normalizeHeader(field)] = i
+		}
+	}
+
+	find := func(column string) int {
+		if strings.TrimSpace(column) == "" {
+			return -1
+		}
+		for _, alternative := range strings.Split(column, "|") {
+			if index, exists := headers[normalizeHeader(alternative)]; exists {
+				return index
+			}
+		}
+		return -2 // Mapped but missing
+	}
+
+	columns := &csvColumns{
+		date:        find(p.mapping.DateColumn),
+		amount:      find(p.mapping.AmountColumn),
+		debit:       find(p.mapping.DebitColumn),
+		credit:      find(p.mapping.CreditColumn),
+		description: find(p.mapping.DescriptionColumn),
+		merchant:    find(p.mapping.MerchantColumn),
+		reference:   find(p.mapping.ReferenceColumn),
+		balance:     find(p.mapping.BalanceColumn),
+		currency:    find(p.mapping.CurrencyColumn),
+	}
+	if columns.date < 0 {
+		return nil
+	}
+	return columns
+}
+
+// checkColumns requires the amount columns; missing optional columns are left empty, since exports of
+// the same bank do not always have all of them
+func (p *CSVParser) checkColumns(columns *csvColumns) error {
+	mapped := []struct {
+		index  int
+		column string
+	}{
+		{columns.amount, p.mapping.AmountColumn},
+		{columns.debit, p.mapping.DebitColumn},
+		{columns.credit, p.mapping.CreditColumn},
+	}
+	for _, m := range mapped {
+		if m.index == -2 {
+			return fmt.Errorf("column %q is missing from the header", m.column)
+		}
+	}
+	return nil
+}
+
+// hasAmount checks if the row has an amount; rows without one are blank lines, totals or separators
+func (p *CSVParser) hasAmount(fields []string, columns *csvColumns) bool {
+	for _, index := range []int{columns.amount, columns.debit, columns.credit} {
+		if index >= 0 && index < len(fields) && strings.TrimSpace(fields[index]) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *CSVParser) parseRow(fields []string, columns *csvColumns) (Record, error) {
+	value := func(index int) string {
+		if index < 0 || index >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[index])
+	}
+
+	date, err := parseDate(value(columns.date), p.layout)
+	if err != nil {
+		return Record{}, err
+	}
+
+	var amount float64
+	if columns.amount >= 0 {
+		amount, err = parseAmount(value(columns.amount), p.mapping.DecimalSeparator)
+		if err != nil {
+			return Record{}, err
+		}
+		if p.mapping.InvertSign {
+			amount = -amount
+		}
+	} else {
+		debit, credit := value(columns.debit), value(columns.credit)
+		if debit != "" {
+			parsed, err := parseAmount(debit, p.mapping.DecimalSeparator)
+			if err != nil {
+				return Record{}, err
+			}
+			amount -= abs(parsed)
+		}
+		if credit != "" {
+			parsed, err := parseAmount(credit, p.mapping.DecimalSeparator)
+			if err != nil {
+				return Record{}, err
+			}
+			amount += abs(parsed)
+		}
+	}
+
+	record := Record{
+		PostedAt:    date,
+		Amount:      amount,
+		Currency:    strings.ToUpper(value(columns.currency)),
+		Description: value(columns.description),
+		Merchant:    value(columns.merchant),
+		ExternalID:  value(columns.reference),
+	}
+	if record.Currency == "" {
+		record.Currency = p.mapping.Currency
+	}
+	if balance := value(columns.balance); balance != "" {
+		if parsed, err := parseAmount(balance, p.mapping.DecimalSeparator); err == nil {
+			record.Balance = &parsed
+		}
+	}
+	return record, record.Validate()
+}
+
+func abs(value float64) float64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
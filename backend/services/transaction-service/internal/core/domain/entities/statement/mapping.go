@@ -0,0 +1,91 @@
+package statement
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// DefaultDateFormat is the date format of Argentine bank exports
+const DefaultDateFormat = "DD/MM/YYYY"
+
+// CSVMapping tells which columns of a CSV export hold each field. Columns are identified by their header,
+// ignoring case and accents; alternative headers can be separated by "|" (e.g. "Débito|Débitos").
+// The amount comes from a signed amount column or from separate debit and credit columns.
+type CSVMapping struct {
+	ID                string    `json:"id"`
+	UserID            string    `json:"userId"`
+	Name              string    `json:"name"`
+	Delimiter         string    `json:"delimiter"` // Detected from the header when empty
+	SkipRows          int       `json:"skipRows"`  // Lines before the header
+	DateColumn        string    `json:"dateColumn"`
+	DateFormat        string    `json:"dateFormat"` // DD/MM/YYYY by default; YYYY, YY, MM, DD, HH, mm and ss are supported
+	AmountColumn      string    `json:"amountColumn"`
+	DebitColumn       string    `json:"debitColumn"`
+	CreditColumn      string    `json:"creditColumn"`
+	DescriptionColumn string    `json:"descriptionColumn"`
+	MerchantColumn    string    `json:"merchantColumn"`
+	ReferenceColumn   string    `json:"referenceColumn"`
+	BalanceColumn     string    `json:"balanceColumn"`
+	CurrencyColumn    string    `json:"currencyColumn"`
+	DecimalSeparator  string    `json:"decimalSeparator"` // "," for 1.234,56 and "." for 1,234.56; detected when empty
+	InvertSign        bool      `json:"invertSign"`       // The amount column has debits as positive numbers
+	Currency          string    `json:"currency"`         // Currency of the export when it has no currency column
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// Validate checks that the mapping can read a file
+func (m *CSVMapping) Validate() error {
+	if strings.TrimSpace(m.DateColumn) == "" {
+		return errors.New("date column is required")
+	}
+	hasAmount := strings.TrimSpace(m.AmountColumn) != ""
+	hasDebitCredit := strings.TrimSpace(m.DebitColumn) != "" || strings.TrimSpace(m.CreditColumn) != ""
+	if hasAmount == hasDebitCredit {
+		return errors.New("either an amount column or debit and credit columns are required")
+	}
+	if len([]rune(m.Delimiter)) > 1 {
+		return errors.New("delimiter must be a single character")
+	}
+	if m.DecimalSeparator != "" && m.DecimalSeparator != "," && m.DecimalSeparator != "." {
+		return errors.New("decimal separator must be ',' or '.'")
+	}
+	if m.SkipRows < 0 {
+		return errors.New("skip rows cannot be negative")
+	}
+	if m.Currency != "" && len(m.Currency) != 3 {
+		return errors.New("currency must be a 3 letter code")
+	}
+	if _, err := dateLayout(m.DateFormat); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateSaved checks a mapping before saving it for the user
+func (m *CSVMapping) ValidateSaved() error {
+	name := strings.TrimSpace(m.Name)
+	if name == "" {
+		return errors.New("mapping name is required")
+	}
+	if len(name) > 100 {
+		return errors.New("mapping name cannot exceed 100 characters")
+	}
+	return m.Validate()
+}
+
+// dateTokens accept days and months with or without leading zero
+var dateTokens = strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "1", "DD", "2", "HH", "15", "mm", "04", "ss", "05")
+
+// dateLayout turns a format like DD/MM/YYYY into a Go layout
+func dateLayout(format string) (string, error) {
+	if strings.TrimSpace(format) == "" {
+		format = DefaultDateFormat
+	}
+	layout := dateTokens.Replace(format)
+	if !strings.Contains(layout, "2006") && !strings.Contains(layout, "06") {
+		return "", errors.New("date format must include the year (YYYY or YY)")
+	}
+	return layout, nil
+}
@@ -0,0 +1,138 @@
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OFXParser reads OFX statements, both SGML (1.x) and XML (2.x)
+type OFXParser struct{}
+
+// Parse reads the STMTTRN entries of the statement
+func (p *OFXParser) Parse(data []byte) (*ParseResult, error) {
+	text := decodeText(data)
+	upper := asciiUpper(text)
+
+	result := &ParseResult{Currency: strings.ToUpper(ofxValue(text, upper, "CURDEF"))}
+
+	rowNumber := 0
+	for offset := 0; ; {
+		start := strings.Index(upper[offset:], "<STMTTRN>")
+		if start < 0 {
+			break
+		}
+		start += offset + len("<STMTTRN>")
+
+		// SGML files may not close the entry, so it also ends where the next one starts
+		end := len(upper)
+		for _, closing := range []string{"</STMTTRN>", "<STMTTRN>", "</BANKTRANLIST>"} {
+			if index := strings.Index(upper[start:], closing); index >= 0 && start+index < end {
+				end = start + index
+			}
+		}
+		offset = end
+
+		rowNumber++
+		record, err := parseOFXTransaction(text[start:end], upper[start:end])
+		if err != nil {
+			result.Errors = append(result.Errors, RowError{RowNumber: rowNumber, Message: err.Error()})
+			continue
+		}
+		record.RowNumber = rowNumber
+		record.Currency = result.Currency
+		result.Records = append(result.Records, record)
+	}
+
+	if rowNumber == 0 {
+		return nil, errors.New("no transactions found in the OFX file")
+	}
+	return result, nil
+}
+
+func parseOFXTransaction(text, upper string) (Record, error) {
+	date, err := parseOFXDate(ofxValue(text, upper, "DTPOSTED"))
+	if err != nil {
+		return Record{}, err
+	}
+
+	// OFX amounts use a dot as decimal separator, although some banks write a comma
+	rawAmount := ofxValue(text, upper, "TRNAMT")
+	decimalSeparator := "."
+	if strings.Contains(rawAmount, ",") && !strings.Contains(rawAmount, ".") {
+		decimalSeparator = ","
+	}
+	amount, err := parseAmount(rawAmount, decimalSeparator)
+	if err != nil {
+		return Record{}, err
+	}
+
+	name := ofxValue(text, upper, "NAME")
+	memo := ofxValue(text, upper, "MEMO")
+	description := name
+	if memo != "" && !strings.EqualFold(memo, name) {
+		description = strings.TrimSpace(name + " " + memo)
+	}
+
+	externalID := ofxValue(text, upper, "FITID")
+	if externalID == "" {
+		externalID = ofxValue(text, upper, "CHECKNUM")
+	}
+
+	record := Record{
+		PostedAt:    date,
+		Amount:      amount,
+		Description: description,
+		Merchant:    name,
+		ExternalID:  externalID,
+	}
+	return record, record.Validate()
+}
+
+// ofxValue returns the value of a tag: the text up to the next tag or line break
+func ofxValue(text, upper, tag string) string {
+	index := strings.Index(upper, "<"+tag+">")
+	if index < 0 {
+		return ""
+	}
+	value := text[index+len(tag)+2:]
+	if end := strings.IndexAny(value, "<\r\n"); end >= 0 {
+		value = value[:end]
+	}
+	return unescapeOFX(strings.TrimSpace(value))
+}
+
+// asciiUpper upper-cases ASCII letters only, so positions in the result match the ones in the text
+func asciiUpper(text string) string {
+	upper := []byte(text)
+	for i, b := range upper {
+		if b >= 'a' && b <= 'z' {
+			upper[i] = b - ('a' - 'A')
+		}
+	}
+	return string(upper)
+}
+
+var ofxEntities = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", "\"", "&apos;", "'")
+
+func unescapeOFX(value string) string {
+	return ofxEntities.Replace(value)
+}
+
+// parseOFXDate reads dates like 20250115, 20250115120000 or 20250115120000.000[-3:ART]
+func parseOFXDate(value string) (time.Time, error) {
+	digits := value
+	if end := strings.IndexAny(digits, ".["); end >= 0 {
+		digits = digits[:end]
+	}
+
+	switch {
+	case len(digits) >= 14:
+		return time.ParseInLocation("20060102150405", digits[:14], time.Local)
+	case len(digits) >= 8:
+		return time.ParseInLocation("20060102", digits[:8], time.Local)
+	default:
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+}
@@ -0,0 +1,169 @@
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Parser reads the movements of a statement file
+type Parser interface {
+	Parse(data []byte) (*ParseResult, error)
+}
+
+// bankParsers are the parsers of known bank exports, by name
+var bankParsers = map[string]Parser{}
+
+// RegisterBankParser makes the parser of a bank export available by name
+func RegisterBankParser(name string, parser Parser) {
+	bankParsers[strings.ToLower(name)] = parser
+}
+
+// BankParserNames returns the names of the registered bank parsers, sorted
+func BankParserNames() []string {
+	names := make([]string, 0, len(bankParsers))
+	for name := range bankParsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewParser returns the parser of a format: csv (which needs a mapping), ofx, qif or a bank parser name.
+// The date format of the mapping, if any, is also used by QIF files.
+func NewParser(format string, mapping *CSVMapping) (Parser, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case FormatCSV:
+		if mapping == nil {
+			return nil, errors.New("csv imports require a column mapping")
+		}
+		return NewCSVParser(*mapping)
+	case FormatOFX:
+		return &OFXParser{}, nil
+	case FormatQIF:
+		dateFormat := ""
+		if mapping != nil {
+			dateFormat = mapping.DateFormat
+		}
+		return NewQIFParser(dateFormat)
+	}
+
+	if parser, exists := bankParsers[strings.ToLower(strings.TrimSpace(format))]; exists {
+		return parser, nil
+	}
+	return nil, fmt.Errorf("unsupported import format: %s", format)
+}
+
+// decodeText returns the file as UTF-8 text. Bank exports are often Latin-1 (Windows-1252) encoded.
+func decodeText(data []byte) string {
+	data = []byte(strings.TrimPrefix(string(data), "\ufeff"))
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+var accentReplacer = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+
+// normalizeHeader makes header names comparable ignoring case, accents and extra spaces
+func normalizeHeader(header string) string {
+	return strings.Join(strings.Fields(accentReplacer.Replace(strings.ToLower(header))), " ")
+}
+
+var amountCleaner = strings.NewReplacer("U$S", "", "US$", "", "USD", "", "ARS", "", "$", "", " ", "", "\u00a0", "")
+
+// parseAmount reads amounts like "-1.234,56", "1,234.56", "$ 1.234,56", "(150,00)" or "150,00-".
+// With an empty decimal separator it is the last of "," and "." when both appear, and a single
+// separator followed by one or two digits.
+func parseAmount(value string, decimalSeparator string) (float64, error) {
+	cleaned := amountCleaner.Replace(strings.TrimSpace(value))
+	if cleaned == "" {
+		return 0, errors.New("empty amount")
+	}
+
+	negative := false
+	if strings.HasPrefix(cleaned, "(") && strings.HasSuffix(cleaned, ")") {
+		negative = true
+		cleaned = strings.Trim(cleaned, "()")
+	}
+	if strings.HasSuffix(cleaned, "-") {
+		negative = !negative
+		cleaned = strings.TrimSuffix(cleaned, "-")
+	}
+
+	if decimalSeparator == "" {
+		decimalSeparator = detectDecimalSeparator(cleaned)
+	}
+	thousandsSeparator := ","
+	if decimalSeparator == "," {
+		thousandsSeparator = "."
+	}
+	cleaned = strings.ReplaceAll(cleaned, thousandsSeparator, "")
+	cleaned = strings.ReplaceAll(cleaned, decimalSeparator, ".")
+
+	amount, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func detectDecimalSeparator(value string) string {
+	lastComma := strings.LastIndex(value, ",")
+	lastDot := strings.LastIndex(value, ".")
+	switch {
+	case lastComma >= 0 && lastDot >= 0:
+		if lastComma > lastDot {
+			return ","
+		}
+		return "."
+	case lastComma >= 0:
+		if strings.Count(value, ",") == 1 && len(value)-lastComma-1 <= 2 {
+			return ","
+		}
+		return "."
+	case lastDot >= 0:
+		if strings.Count(value, ".") == 1 && len(value)-lastDot-1 != 3 {
+			return "."
+		}
+		return ","
+	default:
+		return "."
+	}
+}
+
+// parseDate reads a date with the given layout, ignoring a time part the layout does not have and
+// accepting two digit years
+func parseDate(value string, layout string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	candidates := []string{value}
+	if datePart, _, found := strings.Cut(strings.Replace(value, "T", " ", 1), " "); found {
+		candidates = append(candidates, datePart)
+	}
+
+	layouts := []string{layout}
+	if strings.Contains(layout, "2006") {
+		layouts = append(layouts, strings.Replace(layout, "2006", "06", 1))
+	}
+
+	for _, candidate := range candidates {
+		for _, l := range layouts {
+			if date, err := time.ParseInLocation(l, candidate, time.Local); err == nil {
+				return date, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
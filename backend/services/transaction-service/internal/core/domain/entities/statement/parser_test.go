@@ -0,0 +1,544 @@
+package statement
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// galiciaStatement is a home banking export of Banco Galicia: a preamble with the account and period,
+// ";" as delimiter, separate debit and credit columns and amounts like 12.345,67
+const galiciaStatement = `Banco Galicia - Movimientos de cuenta
+Cuenta: CA $ 4023-1234567-8
+Período: 01/03/2025 al 31/03/2025
+
+Fecha;Descripción;Débitos;Créditos;Saldo;Número de Comprobante
+03/03/2025;Compra con débito SUPERMERCADO DIA;12.345,67;;87.654,33;000123
+05/03/2025;Transferencia recibida;;150.000,00;237.654,33;000124
+10/03/2025;Pago de servicios EDENOR;1.234,50;;236.419,83;000125
+
+`
+
+// mercadoPagoStatement is a settlement report of Mercado Pago: a balance summary before the header,
+// "," as delimiter, signed net amounts with a dot as decimal separator and DD-MM-YYYY dates
+const mercadoPagoStatement = `INITIAL_BALANCE,CREDITS,DEBITS,FINAL_BALANCE
+10000.00,25000.50,-4500.25,30500.25
+
+RELEASE_DATE,TRANSACTION_TYPE,REFERENCE_ID,TRANSACTION_NET_AMOUNT,PARTIAL_BALANCE
+02-04-2025 10:15:00,Dinero recibido,81234567890,25000.50,35000.50
+03-04-2025 18:40:12,Pago con QR Kiosco,81234567891,-1500.00,33500.50
+04-04-2025,Extracción,81234567892,-3000.25,30500.25
+`
+
+// ofxSGMLStatement is an OFX 1.x file: SGML without closing tags, a comma decimal and escaped entities
+const ofxSGMLStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>ars
+<BANKTRANLIST>
+<DTSTART>20250101
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250115120000.000[-3:ART]
+<TRNAMT>-1500,50
+<FITID>202501150001
+<NAME>FARMACITY S.A.
+<MEMO>Compra en farmacia
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20250120
+<TRNAMT>250000.00
+<FITID>202501200002
+<NAME>Sueldos &amp; Jornales
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+// ofxXMLStatement is an OFX 2.x file with closed tags on a single line
+const ofxXMLStatement = `<?xml version="1.0" encoding="UTF-8"?><?OFX OFXHEADER="200" VERSION="220"?>` +
+	`<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>USD</CURDEF><BANKTRANLIST>` +
+	`<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20250301</DTPOSTED><TRNAMT>-42.10</TRNAMT><FITID>A1</FITID><NAME>Netflix</NAME><MEMO>Netflix</MEMO></STMTTRN>` +
+	`<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20250302</DTPOSTED><TRNAMT>-10.00</TRNAMT><CHECKNUM>778</CHECKNUM><NAME>Cheque</NAME></STMTTRN>` +
+	`</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`
+
+// qifStatement is a QIF export of a bank account with Quicken dates, both decimal separators and split lines
+const qifStatement = `!Type:Bank
+D15/01'25
+T-1,234.56
+PCOTO Supermercados
+MCompra semanal
+N1001
+SAlimentos
+$-1,000.00
+SLimpieza
+$-234.56
+^
+D20/01/2025
+T45.000,00
+PTransferencia recibida
+^
+D31/02/2025
+T-100.00
+PFecha inválida
+^
+`
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		name             string
+		value            string
+		decimalSeparator string
+		expected         float64
+		expectError      bool
+	}{
+		{"decimal comma with thousands", "-1.234,56", ",", -1234.56, false},
+		{"decimal dot with thousands", "1,234.56", ".", 1234.56, false},
+		{"detected decimal comma", "1.234.567,89", "", 1234567.89, false},
+		{"detected decimal dot", "1,234,567.89", "", 1234567.89, false},
+		{"detected thousands dot without decimals", "1.234", "", 1234, false},
+		{"detected decimal comma with one digit", "12,5", "", 12.5, false},
+		{"detected decimal dot with two digits", "99.90", "", 99.9, false},
+		{"currency symbol", "$ 1.234,56", "", 1234.56, false},
+		{"dollar symbol", "U$S 99.90", "", 99.9, false},
+		{"negative in parentheses", "(150,00)", ",", -150, false},
+		{"trailing minus", "150,00-", ",", -150, false},
+		{"non-breaking space thousands", "1 234,56", ",", 1234.56, false},
+		{"empty", "  ", "", 0, true},
+		{"not a number", "abc", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := parseAmount(tt.value, tt.decimalSeparator)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("parseAmount(%q) expected error but got %v", tt.value, amount)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAmount(%q) unexpected error: %v", tt.value, err)
+			}
+			if math.Abs(amount-tt.expected) > 1e-9 {
+				t.Errorf("parseAmount(%q) = %v, want %v", tt.value, amount, tt.expected)
+			}
+		})
+	}
+}
+
+// expectedRecord is what a test checks of a parsed record
+type expectedRecord struct {
+	date        string // YYYY-MM-DD
+	amount      float64
+	description string
+	externalID  string
+}
+
+func checkRecords(t *testing.T, result *ParseResult, expected []expectedRecord) {
+	t.Helper()
+	if len(result.Records) != len(expected) {
+		t.Fatalf("Parse() records = %d, want %d (errors: %v)", len(result.Records), len(expected), result.Errors)
+	}
+	for i, want := range expected {
+		record := result.Records[i]
+		if got := record.PostedAt.Format("2006-01-02"); got != want.date {
+			t.Errorf("record %d date = %s, want %s", i+1, got, want.date)
+		}
+		if math.Abs(record.Amount-want.amount) > 1e-9 {
+			t.Errorf("record %d amount = %v, want %v", i+1, record.Amount, want.amount)
+		}
+		if record.Description != want.description {
+			t.Errorf("record %d description = %q, want %q", i+1, record.Description, want.description)
+		}
+		if record.ExternalID != want.externalID {
+			t.Errorf("record %d external ID = %q, want %q", i+1, record.ExternalID, want.externalID)
+		}
+		if record.RowNumber != i+1 {
+			t.Errorf("record %d row number = %d, want %d", i+1, record.RowNumber, i+1)
+		}
+	}
+}
+
+func TestBankParsers(t *testing.T) {
+	// Galicia exports from the home banking are Windows-1252 encoded
+	latin1 := strings.NewReplacer("í", "\xed", "é", "\xe9", "ó", "\xf3", "ú", "\xfa").Replace(galiciaStatement)
+
+	tests := []struct {
+		name     string
+		format   string
+		content  string
+		currency string
+		expected []expectedRecord
+	}{
+		{
+			name:     "galicia",
+			format:   "galicia",
+			content:  galiciaStatement,
+			currency: "ARS",
+			expected: []expectedRecord{
+				{"2025-03-03", -12345.67, "Compra con débito SUPERMERCADO DIA", "000123"},
+				{"2025-03-05", 150000, "Transferencia recibida", "000124"},
+				{"2025-03-10", -1234.50, "Pago de servicios EDENOR", "000125"},
+			},
+		},
+		{
+			name:     "galicia latin-1",
+			format:   "GALICIA",
+			content:  latin1,
+			currency: "ARS",
+			expected: []expectedRecord{
+				{"2025-03-03", -12345.67, "Compra con débito SUPERMERCADO DIA", "000123"},
+				{"2025-03-05", 150000, "Transferencia recibida", "000124"},
+				{"2025-03-10", -1234.50, "Pago de servicios EDENOR", "000125"},
+			},
+		},
+		{
+			name:     "mercado pago",
+			format:   "mercadopago",
+			content:  mercadoPagoStatement,
+			currency: "ARS",
+			expected: []expectedRecord{
+				{"2025-04-02", 25000.50, "Dinero recibido", "81234567890"},
+				{"2025-04-03", -1500, "Pago con QR Kiosco", "81234567891"},
+				{"2025-04-04", -3000.25, "Extracción", "81234567892"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewParser(tt.format, nil)
+			if err != nil {
+				t.Fatalf("NewParser(%q) unexpected error: %v", tt.format, err)
+			}
+			result, err := parser.Parse([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if len(result.Errors) != 0 {
+				t.Errorf("Parse() errors = %v, want none", result.Errors)
+			}
+			checkRecords(t, result, tt.expected)
+			for _, record := range result.Records {
+				if record.Currency != tt.currency {
+					t.Errorf("record %d currency = %q, want %q", record.RowNumber, record.Currency, tt.currency)
+				}
+			}
+		})
+	}
+
+	// The balance column is read too
+	parser, _ := NewParser("galicia", nil)
+	result, _ := parser.Parse([]byte(galiciaStatement))
+	if balance := result.Records[1].Balance; balance == nil || *balance != 237654.33 {
+		t.Errorf("record 2 balance = %v, want 237654.33", balance)
+	}
+}
+
+func TestCSVParser(t *testing.T) {
+	tests := []struct {
+		name        string
+		mapping     CSVMapping
+		content     string
+		expected    []expectedRecord
+		rowErrors   int
+		expectError string
+	}{
+		{
+			name: "signed amount with decimal comma and skipped rows",
+			mapping: CSVMapping{
+				SkipRows:          2,
+				DateColumn:        "Fecha",
+				AmountColumn:      "Importe",
+				DescriptionColumn: "Concepto",
+				DecimalSeparator:  ",",
+			},
+			content: "Resumen de cuenta\nFecha;Importe;Concepto\n" +
+				"Fecha;Importe;Concepto\n01/02/2025;-1.500,75;Alquiler\n02/02/2025;2.000.000,00;Venta auto\n",
+			expected: []expectedRecord{
+				{"2025-02-01", -1500.75, "Alquiler", ""},
+				{"2025-02-02", 2000000, "Venta auto", ""},
+			},
+		},
+		{
+			name: "inverted sign with explicit delimiter and date format",
+			mapping: CSVMapping{
+				Delimiter:         "|",
+				DateColumn:        "date",
+				DateFormat:        "YYYY-MM-DD",
+				AmountColumn:      "amount",
+				DescriptionColumn: "description",
+				ReferenceColumn:   "id",
+				InvertSign:        true,
+			},
+			content: "date|amount|description|id\n2025-02-03|1,234.56|Card purchase|X1\n2025-02-04|-50.00|Refund|X2\n",
+			expected: []expectedRecord{
+				{"2025-02-03", -1234.56, "Card purchase", "X1"},
+				{"2025-02-04", 50, "Refund", "X2"},
+			},
+		},
+		{
+			name: "debit and credit columns with blank and invalid rows",
+			mapping: CSVMapping{
+				DateColumn:        "Fecha",
+				DebitColumn:       "Debe",
+				CreditColumn:      "Haber",
+				DescriptionColumn: "Detalle",
+			},
+			content: "Fecha,Detalle,Debe,Haber\n" +
+				"05/02/2025,Débito automático,\"1.000,00\",\n" +
+				",,,\n" +
+				"06/02/2025,Acreditación,,\"3.500,10\"\n" +
+				"31/02/2025,Fecha inválida,10,\n" +
+				"07/02/2025,Importe cero,\"0,00\",\n",
+			expected: []expectedRecord{
+				{"2025-02-05", -1000, "Débito automático", ""},
+				{"2025-02-06", 3500.10, "Acreditación", ""},
+			},
+			rowErrors: 2,
+		},
+		{
+			name:        "header not found",
+			mapping:     CSVMapping{DateColumn: "Fecha", AmountColumn: "Importe"},
+			content:     "Date,Amount\n01/02/2025,10\n",
+			expectError: `no header with column "Fecha"`,
+		},
+		{
+			name:        "amount column missing",
+			mapping:     CSVMapping{DateColumn: "Fecha", AmountColumn: "Importe"},
+			content:     "Fecha,Monto\n01/02/2025,10\n",
+			expectError: `column "Importe" is missing`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewCSVParser(tt.mapping)
+			if err != nil {
+				t.Fatalf("NewCSVParser() unexpected error: %v", err)
+			}
+			result, err := parser.Parse([]byte(tt.content))
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.expectError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			checkRecords(t, result, tt.expected)
+			if len(result.Errors) != tt.rowErrors {
+				t.Errorf("Parse() errors = %v, want %d", result.Errors, tt.rowErrors)
+			}
+		})
+	}
+}
+
+func TestCSVMappingValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		mapping     CSVMapping
+		expectError bool
+	}{
+		{"amount column", CSVMapping{DateColumn: "Fecha", AmountColumn: "Importe"}, false},
+		{"debit and credit columns", CSVMapping{DateColumn: "Fecha", DebitColumn: "Debe", CreditColumn: "Haber"}, false},
+		{"missing date column", CSVMapping{AmountColumn: "Importe"}, true},
+		{"amount and debit columns", CSVMapping{DateColumn: "Fecha", AmountColumn: "Importe", DebitColumn: "Debe"}, true},
+		{"no amount columns", CSVMapping{DateColumn: "Fecha"}, true},
+		{"long delimiter", CSVMapping{DateColumn: "Fecha", AmountColumn: "Importe", Delimiter: ";;"}, true},
+		{"invalid decimal separator", CSVMapping{DateColumn: "Fecha", AmountColumn: "Importe", DecimalSeparator: "'"}, true},
+		{"date format without year", CSVMapping{DateColumn: "Fecha", AmountColumn: "Importe", DateFormat: "DD/MM"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.mapping.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Validate() expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Validate() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestOFXParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		currency string
+		expected []expectedRecord
+	}{
+		{
+			name:     "sgml",
+			content:  ofxSGMLStatement,
+			currency: "ARS",
+			expected: []expectedRecord{
+				{"2025-01-15", -1500.50, "FARMACITY S.A. Compra en farmacia", "202501150001"},
+				{"2025-01-20", 250000, "Sueldos & Jornales", "202501200002"},
+			},
+		},
+		{
+			name:     "xml",
+			content:  ofxXMLStatement,
+			currency: "USD",
+			expected: []expectedRecord{
+				{"2025-03-01", -42.10, "Netflix", "A1"},
+				{"2025-03-02", -10, "Cheque", "778"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewParser(FormatOFX, nil)
+			if err != nil {
+				t.Fatalf("NewParser() unexpected error: %v", err)
+			}
+			result, err := parser.Parse([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if result.Currency != tt.currency {
+				t.Errorf("Parse() currency = %q, want %q", result.Currency, tt.currency)
+			}
+			checkRecords(t, result, tt.expected)
+		})
+	}
+
+	if _, err := (&OFXParser{}).Parse([]byte("<OFX></OFX>")); err == nil {
+		t.Error("Parse() of a file without transactions expected error but got none")
+	}
+}
+
+func TestParseOFXDate(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{"20250115", time.Date(2025, 1, 15, 0, 0, 0, 0, time.Local), false},
+		{"20250115123045", time.Date(2025, 1, 15, 12, 30, 45, 0, time.Local), false},
+		{"20250115123045.000[-3:ART]", time.Date(2025, 1, 15, 12, 30, 45, 0, time.Local), false},
+		{"2025", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			date, err := parseOFXDate(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("parseOFXDate(%q) expected error but got %v", tt.value, date)
+				}
+				return
+			}
+			if err != nil || !date.Equal(tt.expected) {
+				t.Errorf("parseOFXDate(%q) = %v, %v, want %v", tt.value, date, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQIFParser(t *testing.T) {
+	parser, err := NewParser(FormatQIF, nil)
+	if err != nil {
+		t.Fatalf("NewParser() unexpected error: %v", err)
+	}
+	result, err := parser.Parse([]byte(qifStatement))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	checkRecords(t, result, []expectedRecord{
+		{"2025-01-15", -1234.56, "COTO Supermercados Compra semanal", "1001"},
+		{"2025-01-20", 45000, "Transferencia recibida", ""},
+	})
+	if len(result.Errors) != 1 || result.Errors[0].RowNumber != 3 {
+		t.Errorf("Parse() errors = %v, want row 3", result.Errors)
+	}
+
+	// The date format of a mapping applies to QIF files too
+	parser, _ = NewParser(FormatQIF, &CSVMapping{DateFormat: "MM/DD/YYYY"})
+	result, err = parser.Parse([]byte("!Type:CCard\nD01/31/2025\nT-20.00\nPUber\n^\n"))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	checkRecords(t, result, []expectedRecord{{"2025-01-31", -20, "Uber", ""}})
+
+	if _, err := parser.Parse([]byte("!Type:Invst\nD01/31/2025\nT-20.00\n^\n")); err == nil {
+		t.Error("Parse() of an investment file expected error but got none")
+	}
+	if _, err := parser.Parse([]byte("!Type:Bank\n")); err == nil {
+		t.Error("Parse() of a file without entries expected error but got none")
+	}
+}
+
+func TestNewParser(t *testing.T) {
+	if _, err := NewParser(FormatCSV, nil); err == nil {
+		t.Error("NewParser(csv) without mapping expected error but got none")
+	}
+	if _, err := NewParser("banco-inexistente", nil); err == nil {
+		t.Error("NewParser() of an unknown format expected error but got none")
+	}
+
+	names := strings.Join(BankParserNames(), ",")
+	if names != "bbva,galicia,mercadopago,nacion,santander" {
+		t.Errorf("BankParserNames() = %s", names)
+	}
+}
+
+func TestMarkDuplicates(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+	newRow := func(rowNumber int, postedAt time.Time, amount float64, externalID string) *ImportRow {
+		return &ImportRow{
+			Record: Record{RowNumber: rowNumber, PostedAt: postedAt, Amount: amount, ExternalID: externalID},
+			Status: RowStatusNew,
+		}
+	}
+
+	rows := []*ImportRow{
+		newRow(1, day, -500, "OP-1"),                             // Same bank identifier, different amount
+		newRow(2, day, -1200, ""),                                // Same amount the next day
+		newRow(3, day, -1200, ""),                                // Same amount again: only tracked once
+		newRow(4, day, -300, ""),                                 // Same amount three days apart
+		newRow(5, day, 300, ""),                                  // Opposite sign
+		{Record: Record{RowNumber: 6}, Status: RowStatusInvalid}, // Invalid rows are left alone
+	}
+	tracked := []Movement{
+		{TransactionID: "t1", Date: day, Amount: -499, ExternalID: "OP-1"},
+		{TransactionID: "t2", Date: day.Add(20 * time.Hour), Amount: -1200},
+		{TransactionID: "t3", Date: day.AddDate(0, 0, 3), Amount: -300},
+		{TransactionID: "t4", Date: day, Amount: -300},
+	}
+
+	MarkDuplicates(rows, tracked)
+
+	expected := []struct {
+		status      RowStatus
+		duplicateOf string
+	}{
+		{RowStatusDuplicate, "t1"},
+		{RowStatusDuplicate, "t2"},
+		{RowStatusNew, ""},
+		{RowStatusDuplicate, "t4"},
+		{RowStatusNew, ""},
+		{RowStatusInvalid, ""},
+	}
+	for i, want := range expected {
+		row := rows[i]
+		duplicateOf := ""
+		if row.DuplicateOfID != nil {
+			duplicateOf = *row.DuplicateOfID
+		}
+		if row.Status != want.status || duplicateOf != want.duplicateOf {
+			t.Errorf("row %d = %s of %q, want %s of %q", row.RowNumber, row.Status, duplicateOf, want.status, want.duplicateOf)
+		}
+	}
+}
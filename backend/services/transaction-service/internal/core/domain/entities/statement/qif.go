@@ -0,0 +1,101 @@
+package statement
+
+import (
+	"errors"
+	"strings"
+)
+
+// QIFParser reads QIF files of bank, cash and credit card accounts
+type QIFParser struct {
+	layout string
+}
+
+// NewQIFParser creates a parser for QIF files with the given date format (DD/MM/YYYY by default)
+func NewQIFParser(dateFormat string) (*QIFParser, error) {
+	layout, err := dateLayout(dateFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &QIFParser{layout: layout}, nil
+}
+
+// Parse reads the entries of the file. Each entry is a set of lines starting with a field code and
+// ending with "^": D date, T amount, P payee, M memo and N check or reference number.
+func (p *QIFParser) Parse(data []byte) (*ParseResult, error) {
+	result := &ParseResult{}
+
+	rowNumber := 0
+	entry := map[byte]string{}
+	flush := func() {
+		if len(entry) == 0 {
+			return
+		}
+		rowNumber++
+		record, err := p.parseEntry(entry)
+		if err != nil {
+			result.Errors = append(result.Errors, RowError{RowNumber: rowNumber, Message: err.Error()})
+		} else {
+			record.RowNumber = rowNumber
+			result.Records = append(result.Records, record)
+		}
+		entry = map[byte]string{}
+	}
+
+	for _, line := range strings.Split(decodeText(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "!") {
+			if header := strings.ToLower(line); strings.HasPrefix(header, "!type:invst") {
+				return nil, errors.New("investment QIF files are not supported")
+			}
+			continue
+		}
+		if line[0] == '^' {
+			flush()
+			continue
+		}
+		// Split lines (S, E, $) belong to the categories of the entry and are ignored
+		if _, exists := entry[line[0]]; !exists {
+			entry[line[0]] = strings.TrimSpace(line[1:])
+		}
+	}
+	flush()
+
+	if rowNumber == 0 {
+		return nil, errors.New("no transactions found in the QIF file")
+	}
+	return result, nil
+}
+
+func (p *QIFParser) parseEntry(entry map[byte]string) (Record, error) {
+	// Quicken writes years after 2000 as 01/15'25
+	date, err := parseDate(strings.ReplaceAll(entry['D'], "'", "/"), p.layout)
+	if err != nil {
+		return Record{}, err
+	}
+
+	rawAmount := entry['T']
+	if rawAmount == "" {
+		rawAmount = entry['U']
+	}
+	amount, err := parseAmount(rawAmount, "")
+	if err != nil {
+		return Record{}, err
+	}
+
+	description := entry['P']
+	if memo := entry['M']; memo != "" && !strings.EqualFold(memo, description) {
+		description = strings.TrimSpace(description + " " + memo)
+	}
+
+	record := Record{
+		PostedAt:    date,
+		Amount:      amount,
+		Description: description,
+		Merchant:    entry['P'],
+		ExternalID:  entry['N'],
+	}
+	return record, record.Validate()
+}
@@ -0,0 +1,176 @@
+package statement
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Formats supported by the importer besides the named bank parsers
+const (
+	FormatCSV = "csv" // Generic CSV with a column mapping
+	FormatOFX = "ofx"
+	FormatQIF = "qif"
+)
+
+// Record is a movement read from a bank statement. Debits have a negative amount and credits a positive one.
+type Record struct {
+	RowNumber   int       `json:"rowNumber"` // Position of the movement in the file, starting at 1
+	PostedAt    time.Time `json:"postedAt"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"` // Empty when the statement does not tell it
+	Description string    `json:"description"`
+	Merchant    string    `json:"merchant"`
+	ExternalID  string    `json:"externalId"` // Identifier of the movement in the bank (OFX FITID, operation number)
+	Balance     *float64  `json:"balance"`    // Balance after the movement, when the statement has it
+}
+
+// IsDebit checks if the movement took money out of the account or card
+func (r Record) IsDebit() bool {
+	return r.Amount < 0
+}
+
+// AbsAmount returns the amount of the movement without sign
+func (r Record) AbsAmount() float64 {
+	return math.Abs(r.Amount)
+}
+
+// Validate checks that the record can become a transaction
+func (r Record) Validate() error {
+	if r.PostedAt.IsZero() {
+		return errors.New("missing date")
+	}
+	if math.Round(r.Amount*100) == 0 {
+		return errors.New("amount is zero")
+	}
+	return nil
+}
+
+// RowError is a line of the file that could not be read
+type RowError struct {
+	RowNumber int    `json:"rowNumber"`
+	Message   string `json:"message"`
+}
+
+// ParseResult is the content of a statement file
+type ParseResult struct {
+	Records  []Record
+	Errors   []RowError
+	Currency string // Currency declared by the statement (OFX CURDEF), if any
+}
+
+// BatchStatus is the state of an import
+type BatchStatus string
+
+const (
+	BatchStatusProcessing BatchStatus = "processing"
+	BatchStatusCompleted  BatchStatus = "completed"
+	BatchStatusFailed     BatchStatus = "failed"
+	BatchStatusRolledBack BatchStatus = "rolled_back"
+)
+
+// ImportBatch is one import of a statement file. Every transaction it creates carries its ID so the
+// whole import can be rolled back.
+type ImportBatch struct {
+	ID             string      `json:"id"`
+	UserID         string      `json:"userId"`
+	Format         string      `json:"format"`
+	FileName       string      `json:"fileName"`
+	AccountID      *string     `json:"accountId"`
+	CardID         *string     `json:"cardId"`
+	Currency       string      `json:"currency"`
+	Status         BatchStatus `json:"status"`
+	TotalRows      int         `json:"totalRows"`
+	ImportedCount  int         `json:"importedCount"`
+	DuplicateCount int         `json:"duplicateCount"`
+	InvalidCount   int         `json:"invalidCount"`
+	FromDate       *time.Time  `json:"fromDate"`
+	ToDate         *time.Time  `json:"toDate"`
+	CreatedAt      time.Time   `json:"createdAt"`
+	RolledBackAt   *time.Time  `json:"rolledBackAt"`
+}
+
+// CanBeRolledBack checks if the transactions of the import can still be removed
+func (b *ImportBatch) CanBeRolledBack() bool {
+	return b.Status != BatchStatusRolledBack
+}
+
+// RowStatus is the outcome of a statement row
+type RowStatus string
+
+const (
+	RowStatusNew        RowStatus = "new" // Will be imported (dry run)
+	RowStatusImported   RowStatus = "imported"
+	RowStatusDuplicate  RowStatus = "duplicate" // Already tracked, skipped
+	RowStatusInvalid    RowStatus = "invalid"
+	RowStatusRolledBack RowStatus = "rolled_back"
+)
+
+// ImportRow is a statement row and what the import did with it
+type ImportRow struct {
+	ID      string `json:"id,omitempty"`
+	BatchID string `json:"batchId,omitempty"`
+	Record
+	Status        RowStatus `json:"status"`
+	TransactionID *string   `json:"transactionId"` // Transaction created by the row
+	DuplicateOfID *string   `json:"duplicateOfId"` // Tracked transaction the row matched
+	Error         string    `json:"error,omitempty"`
+}
+
+// Movement is a tracked transaction seen from the account or card of a statement, signed like a Record
+type Movement struct {
+	TransactionID string
+	Date          time.Time
+	Amount        float64
+	ExternalID    string
+}
+
+// duplicateWindow is how far apart the dates of a row and a tracked transaction can be to be duplicates
+const duplicateWindow = 24 * time.Hour
+
+// MarkDuplicates flags the rows already tracked: same bank identifier, or same signed amount within a
+// day. Each tracked transaction matches one row at most, so repeated movements of the same amount on the
+// same day are only skipped as many times as they were tracked.
+func MarkDuplicates(rows []*ImportRow, tracked []Movement) {
+	used := make([]bool, len(tracked))
+
+	// Bank identifiers first, they are exact
+	for _, row := range rows {
+		if row.Status != RowStatusNew || row.ExternalID == "" {
+			continue
+		}
+		for i, movement := range tracked {
+			if !used[i] && movement.ExternalID == row.ExternalID {
+				used[i] = true
+				row.markDuplicate(movement.TransactionID)
+				break
+			}
+		}
+	}
+
+	for _, row := range rows {
+		if row.Status != RowStatusNew {
+			continue
+		}
+		for i, movement := range tracked {
+			if used[i] || math.Abs(movement.Amount-row.Amount) >= 0.005 {
+				continue
+			}
+			gap := movement.Date.Sub(row.PostedAt)
+			if gap < 0 {
+				gap = -gap
+			}
+			if gap <= duplicateWindow {
+				used[i] = true
+				row.markDuplicate(movement.TransactionID)
+				break
+			}
+		}
+	}
+}
+
+func (r *ImportRow) markDuplicate(transactionID string) {
+	id := transactionID
+	r.Status = RowStatusDuplicate
+	r.DuplicateOfID = &id
+}
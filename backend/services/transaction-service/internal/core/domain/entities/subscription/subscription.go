@@ -0,0 +1,322 @@
+package subscription
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Period is the billing cycle of a detected subscription
+type Period string
+
+const (
+	PeriodWeekly    Period = "weekly"
+	PeriodBiweekly  Period = "biweekly"
+	PeriodMonthly   Period = "monthly"
+	PeriodQuarterly Period = "quarterly"
+	PeriodYearly    Period = "yearly"
+)
+
+// Status tells if a subscription is still being charged
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusMissed    Status = "missed"    // The expected charge did not arrive yet
+	StatusCancelled Status = "cancelled" // Two or more charges in a row did not arrive
+)
+
+// periodSpec describes how to recognize a billing cycle from the days between charges
+type periodSpec struct {
+	period    Period
+	days      float64 // Average days between charges
+	tolerance float64 // Accepted deviation in days
+	perYear   float64 // Charges per year, to annualize the cost
+	graceDays int     // Days a charge can be late before it is considered missed
+}
+
+var periodSpecs = []periodSpec{
+	{PeriodWeekly, 7, 2, 52, 3},
+	{PeriodBiweekly, 14, 3, 26, 5},
+	{PeriodMonthly, 30.44, 5, 12, 7},
+	{PeriodQuarterly, 91.31, 10, 4, 15},
+	{PeriodYearly, 365.25, 20, 1, 30},
+}
+
+// Charge is a card or account debit considered by the detector
+type Charge struct {
+	TransactionID string
+	Merchant      string
+	Amount        float64
+	Currency      string
+	Date          time.Time
+	CardID        *string
+	AccountID     *string
+	CategoryID    *string
+}
+
+// PriceChange is a change of the amount charged by a subscription that was kept in the next charges
+type PriceChange struct {
+	Date           time.Time `json:"date"`
+	PreviousAmount float64   `json:"previousAmount"`
+	NewAmount      float64   `json:"newAmount"`
+	ChangePercent  float64   `json:"changePercent"`
+	TransactionID  string    `json:"transactionId"`
+}
+
+// IsIncrease checks if the price went up
+func (p PriceChange) IsIncrease() bool {
+	return p.NewAmount > p.PreviousAmount
+}
+
+// Subscription is a recurring charge detected from the transaction history
+type Subscription struct {
+	ID                string        `json:"id"`
+	UserID            string        `json:"userId"`
+	MerchantKey       string        `json:"merchantKey"` // Normalized merchant used to group the charges
+	MerchantName      string        `json:"merchantName"`
+	Currency          string        `json:"currency"`
+	Period            Period        `json:"period"`
+	Amount            float64       `json:"amount"` // Last amount charged
+	AnnualizedCost    float64       `json:"annualizedCost"`
+	ChargeCount       int           `json:"chargeCount"`
+	FirstChargeAt     time.Time     `json:"firstChargeAt"`
+	LastChargeAt      time.Time     `json:"lastChargeAt"`
+	NextExpectedAt    time.Time     `json:"nextExpectedAt"`
+	Status            Status        `json:"status"`
+	PriceChanges      []PriceChange `json:"priceChanges"`
+	CardID            *string       `json:"cardId"`
+	AccountID         *string       `json:"accountId"`
+	CategoryID        *string       `json:"categoryId"`
+	LastTransactionID string        `json:"lastTransactionId"`
+	DetectedAt        time.Time     `json:"detectedAt"`
+}
+
+// LatestPriceChange returns the most recent price change, if any
+func (s *Subscription) LatestPriceChange() *PriceChange {
+	if len(s.PriceChanges) == 0 {
+		return nil
+	}
+	return &s.PriceChanges[len(s.PriceChanges)-1]
+}
+
+// MonthlyCost returns the annualized cost spread over twelve months
+func (s *Subscription) MonthlyCost() float64 {
+	return roundCents(s.AnnualizedCost / 12)
+}
+
+// IsCharging checks if the subscription is still expected to charge (active or missed)
+func (s *Subscription) IsCharging() bool {
+	return s.Status != StatusCancelled
+}
+
+// DetectorConfig tunes the subscription detector
+type DetectorConfig struct {
+	MinCharges        int     // Charges needed to detect a subscription (yearly ones need only 2)
+	AmountTolerance   float64 // Maximum relative change between two charges of the same subscription
+	StableAmountRatio float64 // Share of consecutive charges that must keep the same amount
+	MinRegularity     float64 // Share of the gaps between charges that must match the period
+}
+
+// DefaultDetectorConfig is tuned for streaming, music and software subscriptions
+var DefaultDetectorConfig = DetectorConfig{
+	MinCharges:        3,
+	AmountTolerance:   0.5,
+	StableAmountRatio: 0.5,
+	MinRegularity:     0.75,
+}
+
+// samePriceTolerance is the relative difference under which two charges have the same price (FX, taxes)
+const samePriceTolerance = 0.01
+
+// Detect finds recurring charges grouped by merchant and currency. Charges of the same merchant must
+// repeat on a regular period with a stable amount; price changes are reported when they are kept.
+func Detect(charges []Charge, now time.Time, config DetectorConfig) []*Subscription {
+	groups := make(map[string][]Charge)
+	var keys []string
+	for _, charge := range charges {
+		merchantKey := NormalizeMerchant(charge.Merchant)
+		if merchantKey == "" || charge.Amount <= 0 {
+			continue
+		}
+		key := merchantKey + "|" + charge.Currency
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], charge)
+	}
+
+	var subscriptions []*Subscription
+	for _, key := range keys {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Date.Before(group[j].Date) })
+
+		merchantKey, _, _ := strings.Cut(key, "|")
+		if subscription := detectSeries(merchantKey, group, now, config); subscription != nil {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		if subscriptions[i].IsCharging() != subscriptions[j].IsCharging() {
+			return subscriptions[i].IsCharging()
+		}
+		return subscriptions[i].AnnualizedCost > subscriptions[j].AnnualizedCost
+	})
+	return subscriptions
+}
+
+// detectSeries checks if the charges of one merchant form a subscription
+func detectSeries(merchantKey string, charges []Charge, now time.Time, config DetectorConfig) *Subscription {
+	if len(charges) < 2 {
+		return nil
+	}
+
+	intervals := make([]float64, 0, len(charges)-1)
+	for i := 1; i < len(charges); i++ {
+		intervals = append(intervals, charges[i].Date.Sub(charges[i-1].Date).Hours()/24)
+	}
+
+	spec, ok := matchPeriod(median(intervals))
+	if !ok {
+		return nil
+	}
+
+	minCharges := config.MinCharges
+	if spec.period == PeriodYearly {
+		minCharges = 2
+	}
+	if len(charges) < minCharges {
+		return nil
+	}
+
+	regular := 0
+	for _, interval := range intervals {
+		if math.Abs(interval-spec.days) <= spec.tolerance {
+			regular++
+		}
+	}
+	if float64(regular) < config.MinRegularity*float64(len(intervals)) {
+		return nil
+	}
+
+	// The amount must be stable; changes that are kept are price changes
+	var priceChanges []PriceChange
+	stable := 0
+	for i := 1; i < len(charges); i++ {
+		previous, current := charges[i-1].Amount, charges[i].Amount
+		change := (current - previous) / previous
+		if math.Abs(change) > config.AmountTolerance {
+			return nil
+		}
+		if math.Abs(change) <= samePriceTolerance {
+			stable++
+			continue
+		}
+		kept := i == len(charges)-1 || relativeDifference(charges[i+1].Amount, current) <= samePriceTolerance
+		if kept {
+			priceChanges = append(priceChanges, PriceChange{
+				Date:           charges[i].Date,
+				PreviousAmount: previous,
+				NewAmount:      current,
+				ChangePercent:  roundCents(change * 100),
+				TransactionID:  charges[i].TransactionID,
+			})
+		}
+	}
+	if len(charges) > 2 && float64(stable) < config.StableAmountRatio*float64(len(intervals)) {
+		return nil
+	}
+
+	first, last := charges[0], charges[len(charges)-1]
+	nextExpected := last.Date.Add(time.Duration(spec.days * 24 * float64(time.Hour)))
+	grace := time.Duration(spec.graceDays) * 24 * time.Hour
+
+	status := StatusActive
+	switch {
+	case now.After(nextExpected.Add(time.Duration(spec.days * 24 * float64(time.Hour))).Add(grace)):
+		status = StatusCancelled
+	case now.After(nextExpected.Add(grace)):
+		status = StatusMissed
+	}
+
+	return &Subscription{
+		MerchantKey:       merchantKey,
+		MerchantName:      strings.TrimSpace(last.Merchant),
+		Currency:          last.Currency,
+		Period:            spec.period,
+		Amount:            last.Amount,
+		AnnualizedCost:    roundCents(last.Amount * spec.perYear),
+		ChargeCount:       len(charges),
+		FirstChargeAt:     first.Date,
+		LastChargeAt:      last.Date,
+		NextExpectedAt:    nextExpected,
+		Status:            status,
+		PriceChanges:      priceChanges,
+		CardID:            last.CardID,
+		AccountID:         last.AccountID,
+		CategoryID:        last.CategoryID,
+		LastTransactionID: last.TransactionID,
+		DetectedAt:        now,
+	}
+}
+
+// merchantNoise are tokens that vary between charges of the same merchant
+var merchantNoise = map[string]bool{
+	"www": true, "com": true, "net": true, "ar": true, "inc": true, "sa": true, "srl": true,
+	"llc": true, "ltd": true, "debito": true, "automatico": true, "pago": true,
+}
+
+// NormalizeMerchant turns merchant names like "NETFLIX.COM 8845" or "Spotify P1A2B3" into a stable key
+func NormalizeMerchant(merchant string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, merchant)
+
+	var tokens []string
+	for _, token := range strings.Fields(cleaned) {
+		if len([]rune(token)) < 2 || merchantNoise[token] {
+			continue
+		}
+		tokens = append(tokens, token)
+		if len(tokens) == 3 {
+			break
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+func matchPeriod(days float64) (periodSpec, bool) {
+	for _, spec := range periodSpecs {
+		if math.Abs(days-spec.days) <= spec.tolerance {
+			return spec, true
+		}
+	}
+	return periodSpec{}, false
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+func relativeDifference(a, b float64) float64 {
+	if b == 0 {
+		return math.Inf(1)
+	}
+	return math.Abs(a-b) / b
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,355 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+)
+
+var firstCharge = time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC)
+
+// history returns the charges of a merchant made the given days after the first charge, with the
+// given amounts (the last amount repeats when there are fewer amounts than days)
+func history(merchant string, days []int, amounts ...float64) []Charge {
+	charges := make([]Charge, len(days))
+	for i, day := range days {
+		amount := amounts[len(amounts)-1]
+		if i < len(amounts) {
+			amount = amounts[i]
+		}
+		charges[i] = Charge{
+			TransactionID: merchant + "-" + string(rune('a'+i)),
+			Merchant:      merchant,
+			Amount:        amount,
+			Currency:      "ARS",
+			Date:          firstCharge.AddDate(0, 0, day),
+		}
+	}
+	return charges
+}
+
+// afterLast returns the time some days after the last of the given days
+func afterLast(days []int, after float64) time.Time {
+	return firstCharge.AddDate(0, 0, days[len(days)-1]).Add(time.Duration(after * 24 * float64(time.Hour)))
+}
+
+func TestDetect(t *testing.T) {
+	monthly := []int{0, 31, 59, 90, 120}
+	weekly := []int{0, 7, 14, 21, 28}
+
+	tests := []struct {
+		name               string
+		charges            []Charge
+		now                time.Time
+		expectedPeriod     Period // Empty when no subscription is detected
+		expectedStatus     Status
+		expectedAmount     float64
+		expectedAnnualized float64
+	}{
+		{
+			name:               "monthly with the same amount",
+			charges:            history("NETFLIX.COM", monthly, 4500),
+			now:                afterLast(monthly, 10),
+			expectedPeriod:     PeriodMonthly,
+			expectedStatus:     StatusActive,
+			expectedAmount:     4500,
+			expectedAnnualized: 54000,
+		},
+		{
+			name:               "weekly",
+			charges:            history("Club de Lectura", weekly, 1000),
+			now:                afterLast(weekly, 3),
+			expectedPeriod:     PeriodWeekly,
+			expectedStatus:     StatusActive,
+			expectedAmount:     1000,
+			expectedAnnualized: 52000,
+		},
+		{
+			name:               "biweekly",
+			charges:            history("Lavadero", []int{0, 14, 28, 42}, 6000),
+			now:                afterLast([]int{42}, 5),
+			expectedPeriod:     PeriodBiweekly,
+			expectedStatus:     StatusActive,
+			expectedAmount:     6000,
+			expectedAnnualized: 156000,
+		},
+		{
+			name:               "quarterly",
+			charges:            history("Seguro Hogar", []int{0, 90, 181, 273}, 30000),
+			now:                afterLast([]int{273}, 30),
+			expectedPeriod:     PeriodQuarterly,
+			expectedStatus:     StatusActive,
+			expectedAmount:     30000,
+			expectedAnnualized: 120000,
+		},
+		{
+			name:               "yearly needs only two charges",
+			charges:            history("Dominio web", []int{0, 365}, 15000),
+			now:                afterLast([]int{365}, 100),
+			expectedPeriod:     PeriodYearly,
+			expectedStatus:     StatusActive,
+			expectedAmount:     15000,
+			expectedAnnualized: 15000,
+		},
+		{
+			name:    "monthly with too few charges",
+			charges: history("Spotify", []int{0, 31}, 2500),
+			now:     afterLast([]int{31}, 10),
+		},
+		{
+			name:               "gaps at the edge of the period tolerance",
+			charges:            history("Spotify", []int{0, 35, 61, 96}, 2500),
+			now:                afterLast([]int{96}, 10),
+			expectedPeriod:     PeriodMonthly,
+			expectedStatus:     StatusActive,
+			expectedAmount:     2500,
+			expectedAnnualized: 30000,
+		},
+		{
+			name:    "gaps beyond the period tolerance",
+			charges: history("Spotify", []int{0, 36, 72, 108}, 2500),
+			now:     afterLast([]int{108}, 10),
+		},
+		{
+			name:               "one irregular gap out of four",
+			charges:            history("Spotify", []int{0, 30, 60, 90, 150}, 2500),
+			now:                afterLast([]int{150}, 10),
+			expectedPeriod:     PeriodMonthly,
+			expectedStatus:     StatusActive,
+			expectedAmount:     2500,
+			expectedAnnualized: 30000,
+		},
+		{
+			name:    "irregular charges",
+			charges: history("Kiosco", []int{0, 10, 45, 50, 90}, 2500),
+			now:     afterLast([]int{90}, 10),
+		},
+		{
+			name:               "small variations of the amount",
+			charges:            history("Disney Plus", monthly, 3000, 3010, 2995, 3005, 3000),
+			now:                afterLast(monthly, 10),
+			expectedPeriod:     PeriodMonthly,
+			expectedStatus:     StatusActive,
+			expectedAmount:     3000,
+			expectedAnnualized: 36000,
+		},
+		{
+			name:    "amount change beyond the tolerance",
+			charges: history("Mercado Libre", monthly, 3000, 3000, 3000, 6000, 6000),
+			now:     afterLast(monthly, 10),
+		},
+		{
+			name:    "amount changing every charge",
+			charges: history("Mercado Libre", monthly, 3000, 3600, 3000, 3600, 3000),
+			now:     afterLast(monthly, 10),
+		},
+		{
+			name:               "monthly charge late within the grace days",
+			charges:            history("NETFLIX.COM", monthly, 4500),
+			now:                afterLast(monthly, 37),
+			expectedPeriod:     PeriodMonthly,
+			expectedStatus:     StatusActive,
+			expectedAmount:     4500,
+			expectedAnnualized: 54000,
+		},
+		{
+			name:               "monthly charge missed",
+			charges:            history("NETFLIX.COM", monthly, 4500),
+			now:                afterLast(monthly, 38),
+			expectedPeriod:     PeriodMonthly,
+			expectedStatus:     StatusMissed,
+			expectedAmount:     4500,
+			expectedAnnualized: 54000,
+		},
+		{
+			name:               "two monthly charges missed",
+			charges:            history("NETFLIX.COM", monthly, 4500),
+			now:                afterLast(monthly, 68),
+			expectedPeriod:     PeriodMonthly,
+			expectedStatus:     StatusCancelled,
+			expectedAmount:     4500,
+			expectedAnnualized: 54000,
+		},
+		{
+			name:               "weekly charge missed",
+			charges:            history("Club de Lectura", weekly, 1000),
+			now:                afterLast(weekly, 11),
+			expectedPeriod:     PeriodWeekly,
+			expectedStatus:     StatusMissed,
+			expectedAmount:     1000,
+			expectedAnnualized: 52000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscriptions := Detect(tt.charges, tt.now, DefaultDetectorConfig)
+
+			if tt.expectedPeriod == "" {
+				if len(subscriptions) != 0 {
+					t.Errorf("Detect() = %v %v, want no subscription", subscriptions[0].Period, subscriptions[0].Status)
+				}
+				return
+			}
+			if len(subscriptions) != 1 {
+				t.Fatalf("Detect() found %d subscriptions, want 1", len(subscriptions))
+			}
+
+			subscription := subscriptions[0]
+			if subscription.Period != tt.expectedPeriod || subscription.Status != tt.expectedStatus {
+				t.Errorf("Detect() = %v %v, want %v %v", subscription.Period, subscription.Status, tt.expectedPeriod, tt.expectedStatus)
+			}
+			if subscription.Amount != tt.expectedAmount || subscription.AnnualizedCost != tt.expectedAnnualized {
+				t.Errorf("Detect() amount = %v (%v a year), want %v (%v a year)",
+					subscription.Amount, subscription.AnnualizedCost, tt.expectedAmount, tt.expectedAnnualized)
+			}
+
+			last := tt.charges[len(tt.charges)-1]
+			if subscription.ChargeCount != len(tt.charges) || subscription.LastTransactionID != last.TransactionID ||
+				!subscription.FirstChargeAt.Equal(tt.charges[0].Date) || !subscription.LastChargeAt.Equal(last.Date) {
+				t.Errorf("Detect() = %d charges from %v to %v (last %v), want %d from %v to %v (last %v)",
+					subscription.ChargeCount, subscription.FirstChargeAt, subscription.LastChargeAt, subscription.LastTransactionID,
+					len(tt.charges), tt.charges[0].Date, last.Date, last.TransactionID)
+			}
+		})
+	}
+}
+
+func TestDetectPriceChanges(t *testing.T) {
+	days := []int{0, 31, 59, 90, 120, 151}
+
+	t.Run("increase kept in the next charges", func(t *testing.T) {
+		charges := history("Spotify", days, 2500, 2500, 2500, 3000)
+		subscriptions := Detect(charges, afterLast(days, 10), DefaultDetectorConfig)
+		if len(subscriptions) != 1 {
+			t.Fatalf("Detect() found %d subscriptions, want 1", len(subscriptions))
+		}
+
+		subscription := subscriptions[0]
+		if len(subscription.PriceChanges) != 1 {
+			t.Fatalf("Detect() price changes = %v, want 1", subscription.PriceChanges)
+		}
+		change := subscription.LatestPriceChange()
+		if change.PreviousAmount != 2500 || change.NewAmount != 3000 || change.ChangePercent != 20 || !change.IsIncrease() {
+			t.Errorf("LatestPriceChange() = %v -> %v (%v%%), want 2500 -> 3000 (20%%)", change.PreviousAmount, change.NewAmount, change.ChangePercent)
+		}
+		if !change.Date.Equal(charges[3].Date) || change.TransactionID != charges[3].TransactionID {
+			t.Errorf("LatestPriceChange() at %v (%v), want %v (%v)", change.Date, change.TransactionID, charges[3].Date, charges[3].TransactionID)
+		}
+		// The cost follows the new price
+		if subscription.Amount != 3000 || subscription.AnnualizedCost != 36000 || subscription.MonthlyCost() != 3000 {
+			t.Errorf("Detect() amount = %v (%v a year, %v a month), want 3000 (36000 a year, 3000 a month)",
+				subscription.Amount, subscription.AnnualizedCost, subscription.MonthlyCost())
+		}
+	})
+
+	t.Run("increase in the last charge", func(t *testing.T) {
+		charges := history("Spotify", days, 2500, 2500, 2500, 2500, 2500, 2750)
+		subscriptions := Detect(charges, afterLast(days, 10), DefaultDetectorConfig)
+		if len(subscriptions) != 1 {
+			t.Fatalf("Detect() found %d subscriptions, want 1", len(subscriptions))
+		}
+
+		change := subscriptions[0].LatestPriceChange()
+		if change == nil || change.NewAmount != 2750 || change.ChangePercent != 10 {
+			t.Errorf("LatestPriceChange() = %v, want 2500 -> 2750 (10%%)", change)
+		}
+	})
+
+	t.Run("decrease", func(t *testing.T) {
+		charges := history("Spotify", days, 3000, 3000, 3000, 2400)
+		subscriptions := Detect(charges, afterLast(days, 10), DefaultDetectorConfig)
+		if len(subscriptions) != 1 {
+			t.Fatalf("Detect() found %d subscriptions, want 1", len(subscriptions))
+		}
+
+		change := subscriptions[0].LatestPriceChange()
+		if change == nil || change.IsIncrease() || change.ChangePercent != -20 {
+			t.Errorf("LatestPriceChange() = %v, want 3000 -> 2400 (-20%%)", change)
+		}
+	})
+
+	t.Run("same price", func(t *testing.T) {
+		subscriptions := Detect(history("Spotify", days, 2500), afterLast(days, 10), DefaultDetectorConfig)
+		if len(subscriptions) != 1 {
+			t.Fatalf("Detect() found %d subscriptions, want 1", len(subscriptions))
+		}
+		if change := subscriptions[0].LatestPriceChange(); change != nil {
+			t.Errorf("LatestPriceChange() = %v, want none", change)
+		}
+	})
+}
+
+func TestDetectGroupsAndSorts(t *testing.T) {
+	days := []int{0, 31, 59, 90}
+	now := afterLast(days, 10)
+
+	// The same merchant with a changing reference, in two currencies, plus an expensive cancelled one
+	var charges []Charge
+	for i, charge := range history("NETFLIX.COM 8845", days, 4500) {
+		if i%2 == 1 {
+			charge.Merchant = "Netflix.com 1123"
+		}
+		charges = append(charges, charge)
+	}
+	for _, charge := range history("NETFLIX.COM", days, 10) {
+		charge.Currency = "USD"
+		charges = append(charges, charge)
+	}
+	charges = append(charges, history("Spotify", days, 2500)...)
+	for _, charge := range history("Gimnasio", []int{0, 31, 59}, 30000) {
+		charge.Date = charge.Date.AddDate(0, -6, 0)
+		charges = append(charges, charge)
+	}
+	// Refunds and charges without merchant are ignored
+	charges = append(charges, Charge{Merchant: "Spotify", Amount: -2500, Currency: "ARS", Date: now.AddDate(0, 0, -1)})
+	charges = append(charges, Charge{Merchant: "1234", Amount: 100, Currency: "ARS", Date: now})
+
+	subscriptions := Detect(charges, now, DefaultDetectorConfig)
+
+	expected := []struct {
+		merchantKey string
+		currency    string
+		status      Status
+	}{
+		{"netflix", "ARS", StatusActive},
+		{"spotify", "ARS", StatusActive},
+		{"netflix", "USD", StatusActive},
+		{"gimnasio", "ARS", StatusCancelled},
+	}
+	if len(subscriptions) != len(expected) {
+		t.Fatalf("Detect() found %d subscriptions, want %d", len(subscriptions), len(expected))
+	}
+	for i, want := range expected {
+		got := subscriptions[i]
+		if got.MerchantKey != want.merchantKey || got.Currency != want.currency || got.Status != want.status {
+			t.Errorf("Detect()[%d] = %v %v %v, want %v %v %v", i, got.MerchantKey, got.Currency, got.Status,
+				want.merchantKey, want.currency, want.status)
+		}
+	}
+	if subscriptions[0].ChargeCount != 4 || subscriptions[0].MerchantName != "Netflix.com 1123" {
+		t.Errorf("Detect() netflix = %d charges named %q, want 4 named %q", subscriptions[0].ChargeCount, subscriptions[0].MerchantName, "Netflix.com 1123")
+	}
+}
+
+func TestNormalizeMerchant(t *testing.T) {
+	tests := []struct {
+		merchant string
+		expected string
+	}{
+		{merchant: "NETFLIX.COM 8845", expected: "netflix"},
+		{merchant: "www.netflix.com", expected: "netflix"},
+		{merchant: "Spotify P1A2B3", expected: "spotify"},
+		{merchant: "DEBITO AUTOMATICO TELECOM ARGENTINA SA", expected: "telecom argentina"},
+		{merchant: "Amazon Prime Video Channels Extra", expected: "amazon prime video"},
+		{merchant: "Café Martínez", expected: "café martínez"},
+		{merchant: "1234 56", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.merchant, func(t *testing.T) {
+			if got := NormalizeMerchant(tt.merchant); got != tt.expected {
+				t.Errorf("NormalizeMerchant(%q) = %q, want %q", tt.merchant, got, tt.expected)
+			}
+		})
+	}
+}
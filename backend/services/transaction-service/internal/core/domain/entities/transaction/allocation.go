@@ -0,0 +1,73 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// MaxAllocations is the maximum number of parts a transaction can be split into
+const MaxAllocations = 20
+
+// Allocation is a part of a split transaction. The allocations of a transaction always add up to its
+// amount. An allocation without category keeps the category of the transaction; one with a counterpart
+// user is the share of that person (e.g. a roommate) and is not counted as spending of the owner.
+type Allocation struct {
+	ID                string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TransactionID     string    `json:"transactionId" gorm:"type:varchar(36);not null;index"`
+	Amount            float64   `json:"amount" gorm:"type:decimal(15,2);not null"`
+	CategoryID        *string   `json:"categoryId" gorm:"type:varchar(36);index"`
+	Note              string    `json:"note" gorm:"type:varchar(255)"`
+	CounterpartUserID *string   `json:"counterpartUserId" gorm:"type:varchar(36);index"`
+	CreatedAt         time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// IsShared checks if the allocation is the share of another person
+func (a *Allocation) IsShared() bool {
+	return a.CounterpartUserID != nil && *a.CounterpartUserID != ""
+}
+
+// CanBeSplit checks if the transaction accepts allocations: failed, canceled or reversed
+// transactions moved no money and cannot be split
+func (t *Transaction) CanBeSplit() bool {
+	return t.Status == TransactionStatusPending || t.Status == TransactionStatusCompleted
+}
+
+// ValidateAllocations checks that the allocations can split the transaction: at least two parts,
+// positive amounts with at most two decimals, adding up exactly to the transaction amount
+func (t *Transaction) ValidateAllocations(allocations []*Allocation) error {
+	if !t.CanBeSplit() {
+		return fmt.Errorf("transaction with status %s cannot be split", t.Status)
+	}
+	if len(allocations) < 2 {
+		return errors.New("a split needs at least two allocations")
+	}
+	if len(allocations) > MaxAllocations {
+		return fmt.Errorf("a transaction cannot be split in more than %d allocations", MaxAllocations)
+	}
+
+	// Compare in cents to avoid floating point drift
+	var totalCents int64
+	for i, allocation := range allocations {
+		if allocation.Amount <= 0 {
+			return fmt.Errorf("allocation %d: amount must be greater than 0", i+1)
+		}
+		cents := math.Round(allocation.Amount * 100)
+		if math.Abs(allocation.Amount*100-cents) > 1e-6 {
+			return fmt.Errorf("allocation %d: amount cannot have more than two decimals", i+1)
+		}
+		if len(allocation.Note) > 255 {
+			return fmt.Errorf("allocation %d: note cannot exceed 255 characters", i+1)
+		}
+		if allocation.CounterpartUserID != nil && *allocation.CounterpartUserID == t.UserID {
+			return fmt.Errorf("allocation %d: the counterpart cannot be the transaction owner", i+1)
+		}
+		totalCents += int64(cents)
+	}
+
+	if totalCents != int64(math.Round(t.Amount*100)) {
+		return fmt.Errorf("allocations add up to %.2f but the transaction amount is %.2f", float64(totalCents)/100, t.Amount)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package transaction
+
+import (
+	"strings"
+	"testing"
+)
+
+// parts splits an amount in allocations of the given amounts
+func parts(amounts ...float64) []*Allocation {
+	allocations := make([]*Allocation, len(amounts))
+	for i, amount := range amounts {
+		allocations[i] = &Allocation{Amount: amount}
+	}
+	return allocations
+}
+
+// equalParts returns count allocations of the same amount
+func equalParts(count int, amount float64) []*Allocation {
+	amounts := make([]float64, count)
+	for i := range amounts {
+		amounts[i] = amount
+	}
+	return parts(amounts...)
+}
+
+func TestValidateAllocations(t *testing.T) {
+	owner := "user-1"
+	roommate := "user-2"
+
+	tests := []struct {
+		name          string
+		amount        float64
+		status        TransactionStatus
+		allocations   []*Allocation
+		expectedError string // Empty when the split is valid
+	}{
+		{
+			name:        "two parts adding up to the amount",
+			amount:      1500,
+			allocations: parts(1000, 500),
+		},
+		{
+			name:        "thirds adjusted to the cent",
+			amount:      100,
+			allocations: parts(33.33, 33.33, 33.34),
+		},
+		{
+			name:        "decimals that drift as floats",
+			amount:      0.3,
+			allocations: parts(0.1, 0.2),
+		},
+		{
+			name:          "one cent short",
+			amount:        100,
+			allocations:   parts(33.33, 33.33, 33.33),
+			expectedError: "add up to 99.99",
+		},
+		{
+			name:          "one cent over",
+			amount:        1500,
+			allocations:   parts(1000, 500.01),
+			expectedError: "add up to 1500.01",
+		},
+		{
+			name:          "more than two decimals",
+			amount:        20.01,
+			allocations:   parts(10.005, 10.005),
+			expectedError: "allocation 1: amount cannot have more than two decimals",
+		},
+		{
+			name:          "zero amount",
+			amount:        1500,
+			allocations:   parts(1500, 0),
+			expectedError: "allocation 2: amount must be greater than 0",
+		},
+		{
+			name:          "negative amount",
+			amount:        1500,
+			allocations:   parts(2000, -500),
+			expectedError: "allocation 2: amount must be greater than 0",
+		},
+		{
+			name:          "a single allocation",
+			amount:        1500,
+			allocations:   parts(1500),
+			expectedError: "at least two allocations",
+		},
+		{
+			name:          "no allocations",
+			amount:        1500,
+			expectedError: "at least two allocations",
+		},
+		{
+			name:        "the maximum of allocations",
+			amount:      2000,
+			allocations: equalParts(MaxAllocations, 100),
+		},
+		{
+			name:          "more than the maximum of allocations",
+			amount:        2100,
+			allocations:   equalParts(MaxAllocations+1, 100),
+			expectedError: "more than 20 allocations",
+		},
+		{
+			name:   "share of another person",
+			amount: 1500,
+			allocations: []*Allocation{
+				{Amount: 750},
+				{Amount: 750, CounterpartUserID: &roommate},
+			},
+		},
+		{
+			name:   "owner as counterpart",
+			amount: 1500,
+			allocations: []*Allocation{
+				{Amount: 750},
+				{Amount: 750, CounterpartUserID: &owner},
+			},
+			expectedError: "allocation 2: the counterpart cannot be the transaction owner",
+		},
+		{
+			name:   "note too long",
+			amount: 1500,
+			allocations: []*Allocation{
+				{Amount: 750, Note: strings.Repeat("a", 256)},
+				{Amount: 750},
+			},
+			expectedError: "allocation 1: note cannot exceed 255 characters",
+		},
+		{
+			name:        "pending transaction",
+			amount:      1500,
+			status:      TransactionStatusPending,
+			allocations: parts(1000, 500),
+		},
+		{
+			name:          "failed transaction",
+			amount:        1500,
+			status:        TransactionStatusFailed,
+			allocations:   parts(1000, 500),
+			expectedError: "status failed cannot be split",
+		},
+		{
+			name:          "canceled transaction",
+			amount:        1500,
+			status:        TransactionStatusCanceled,
+			allocations:   parts(1000, 500),
+			expectedError: "status canceled cannot be split",
+		},
+		{
+			name:          "reversed transaction",
+			amount:        1500,
+			status:        TransactionStatusReversed,
+			allocations:   parts(1000, 500),
+			expectedError: "status reversed cannot be split",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			if status == "" {
+				status = TransactionStatusCompleted
+			}
+			transaction := &Transaction{ID: "tx-1", UserID: owner, Amount: tt.amount, Status: status}
+
+			err := transaction.ValidateAllocations(tt.allocations)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("ValidateAllocations() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("ValidateAllocations() error = %v, want %q", err, tt.expectedError)
+			}
+		})
+	}
+}
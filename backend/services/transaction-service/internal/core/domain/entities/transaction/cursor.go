@@ -0,0 +1,97 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Sort keys of transaction listings. Every listing breaks ties by ID, so the order is total and a cursor
+// points to a single position even when other transactions share its sort value.
+const (
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+	SortByAmount    = "amount"
+)
+
+// sortKeyAliases maps the accepted orderBy values to their sort key
+var sortKeyAliases = map[string]string{
+	"created_at": SortByCreatedAt,
+	"createdAt":  SortByCreatedAt,
+	"date":       SortByCreatedAt,
+	"updated_at": SortByUpdatedAt,
+	"updatedAt":  SortByUpdatedAt,
+	"amount":     SortByAmount,
+}
+
+// ParseSort returns the sort key and direction of a listing: newest first by default, and ascending
+// for an explicit orderBy unless order is "desc"
+func ParseSort(orderBy, order string) (string, bool, error) {
+	if orderBy == "" {
+		return SortByCreatedAt, false, nil
+	}
+	key, exists := sortKeyAliases[orderBy]
+	if !exists {
+		return "", false, fmt.Errorf("invalid orderBy: %s (use created_at, updated_at or amount)", orderBy)
+	}
+	return key, order != "desc", nil
+}
+
+// Cursor is the position after the last transaction of a page (keyset pagination). Unlike an offset it
+// is not shifted by transactions created while paging.
+type Cursor struct {
+	SortKey   string `json:"k"`
+	Value     string `json:"v"` // Sort value of the last transaction: RFC 3339 date or amount
+	ID        string `json:"id"`
+	Ascending bool   `json:"a,omitempty"`
+}
+
+// NewCursor returns the cursor after a transaction in a listing sorted by the given key
+func NewCursor(transaction *Transaction, sortKey string, ascending bool) *Cursor {
+	cursor := &Cursor{SortKey: sortKey, ID: transaction.ID, Ascending: ascending}
+	switch sortKey {
+	case SortByUpdatedAt:
+		cursor.Value = transaction.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case SortByAmount:
+		cursor.Value = strconv.FormatFloat(transaction.Amount, 'f', 2, 64)
+	default:
+		cursor.Value = transaction.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return cursor
+}
+
+// Encode returns the opaque form of the cursor given to clients
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// SortValue returns the sort value typed for the query: a time for dates, a number for amounts
+func (c *Cursor) SortValue() (interface{}, error) {
+	if c.SortKey == SortByAmount {
+		return strconv.ParseFloat(c.Value, 64)
+	}
+	return time.Parse(time.RFC3339Nano, c.Value)
+}
+
+// DecodeCursor reads a cursor given by a previous page
+func DecodeCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if _, exists := sortKeyAliases[cursor.SortKey]; !exists {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if _, err := cursor.SortValue(); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &cursor, nil
+}
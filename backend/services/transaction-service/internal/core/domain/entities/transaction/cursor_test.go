@@ -0,0 +1,127 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		orderBy           string
+		order             string
+		expectedKey       string
+		expectedAscending bool
+		expectError       bool
+	}{
+		{orderBy: "", order: "", expectedKey: SortByCreatedAt},
+		{orderBy: "", order: "asc", expectedKey: SortByCreatedAt},
+		{orderBy: "created_at", order: "", expectedKey: SortByCreatedAt, expectedAscending: true},
+		{orderBy: "createdAt", order: "desc", expectedKey: SortByCreatedAt},
+		{orderBy: "date", order: "asc", expectedKey: SortByCreatedAt, expectedAscending: true},
+		{orderBy: "updatedAt", order: "desc", expectedKey: SortByUpdatedAt},
+		{orderBy: "amount", order: "asc", expectedKey: SortByAmount, expectedAscending: true},
+		{orderBy: "amount", order: "desc", expectedKey: SortByAmount},
+		{orderBy: "merchant_name", expectError: true},
+		{orderBy: "Amount", expectError: true},
+		{orderBy: "amount; DROP TABLE transactions", expectError: true},
+		{orderBy: "id", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.orderBy+" "+tt.order, func(t *testing.T) {
+			key, ascending, err := ParseSort(tt.orderBy, tt.order)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseSort(%q) expected error but got none", tt.orderBy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSort() unexpected error: %v", err)
+			}
+			if key != tt.expectedKey || ascending != tt.expectedAscending {
+				t.Errorf("ParseSort() = %s, %v, want %s, %v", key, ascending, tt.expectedKey, tt.expectedAscending)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	transaction := &Transaction{
+		ID:        "tx-42",
+		Amount:    1234.5,
+		CreatedAt: time.Date(2025, 3, 10, 12, 30, 15, 123456789, time.FixedZone("ART", -3*60*60)),
+		UpdatedAt: time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		sortKey       string
+		ascending     bool
+		expectedValue string
+		expectedSort  interface{}
+	}{
+		{SortByCreatedAt, false, "2025-03-10T15:30:15.123456789Z", transaction.CreatedAt},
+		{SortByUpdatedAt, true, "2025-03-11T09:00:00Z", transaction.UpdatedAt},
+		{SortByAmount, true, "1234.50", 1234.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortKey, func(t *testing.T) {
+			cursor := NewCursor(transaction, tt.sortKey, tt.ascending)
+			if cursor.Value != tt.expectedValue {
+				t.Errorf("NewCursor() value = %s, want %s", cursor.Value, tt.expectedValue)
+			}
+
+			decoded, err := DecodeCursor(cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodeCursor() unexpected error: %v", err)
+			}
+			if *decoded != *cursor {
+				t.Errorf("DecodeCursor() = %+v, want %+v", *decoded, *cursor)
+			}
+
+			value, err := decoded.SortValue()
+			if err != nil {
+				t.Fatalf("SortValue() unexpected error: %v", err)
+			}
+			switch expected := tt.expectedSort.(type) {
+			case time.Time:
+				if date, ok := value.(time.Time); !ok || !date.Equal(expected) {
+					t.Errorf("SortValue() = %v, want %v", value, expected)
+				}
+			default:
+				if value != expected {
+					t.Errorf("SortValue() = %v, want %v", value, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(data string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(data))
+	}
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"not base64", "not a cursor!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"k":"amount","v":"10.00","id":"tx-1"}`))},
+		{"not JSON", encode("tx-1")},
+		{"without ID", encode(`{"k":"amount","v":"10.00"}`)},
+		{"sort key outside the whitelist", encode(`{"k":"merchant_name","v":"YPF","id":"tx-1"}`)},
+		{"amount that is not a number", encode(`{"k":"amount","v":"diez","id":"tx-1"}`)},
+		{"date that is not RFC 3339", encode(`{"k":"created_at","v":"10/03/2025","id":"tx-1"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if cursor, err := DecodeCursor(tt.encoded); err == nil {
+				t.Errorf("DecodeCursor() = %+v, expected error but got none", *cursor)
+			}
+		})
+	}
+}
@@ -59,6 +59,14 @@ const (
 	PaymentMethodInstallmentCompletion PaymentMethod = "installment_completion"
 )
 
+// CategorySource tells how a transaction got its category
+type CategorySource string
+
+const (
+	CategorySourceManual CategorySource = "manual" // Chosen by the user, categorization rules never override it
+	CategorySourceRule   CategorySource = "rule"   // Assigned by a categorization rule
+)
+
 // Transaction represents a financial transaction in the system
 type Transaction struct {
 	// Core identity
@@ -87,6 +95,11 @@ type Transaction struct {
 	PaymentMethod PaymentMethod `json:"paymentMethod" gorm:"type:varchar(30)"`
 	MerchantName  string        `json:"merchantName" gorm:"type:varchar(255)"`
 	MerchantID    string        `json:"merchantId" gorm:"type:varchar(100)"`
+	Notes         string        `json:"notes" gorm:"type:text"` // Free text of the user, searchable with the description
+
+	// Categorization
+	CategoryID     *string        `json:"categoryId" gorm:"type:varchar(36);index"`
+	CategorySource CategorySource `json:"categorySource" gorm:"type:varchar(10)"`
 
 	// Balance tracking
 	PreviousBalance float64 `json:"previousBalance" gorm:"type:decimal(15,2)"`
@@ -154,6 +167,20 @@ func IsValidPaymentMethod(method PaymentMethod) bool {
 
 // Business logic methods
 
+// IsCategorizedManually checks if the user chose the category of the transaction
+func (t *Transaction) IsCategorizedManually() bool {
+	return t.CategoryID != nil && t.CategorySource == CategorySourceManual
+}
+
+// SetCategory assigns a category to the transaction, or clears it when categoryID is nil
+func (t *Transaction) SetCategory(categoryID *string, source CategorySource) {
+	t.CategoryID = categoryID
+	t.CategorySource = source
+	if categoryID == nil {
+		t.CategorySource = ""
+	}
+}
+
 // CanBeCompleted checks if transaction can be marked as completed
 func (t *Transaction) CanBeCompleted() bool {
 	return t.Status == TransactionStatusPending
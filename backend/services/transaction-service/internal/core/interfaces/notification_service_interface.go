@@ -0,0 +1,10 @@
+package interfaces
+
+import "github.com/fintrack/transaction-service/internal/infrastructure/http/clients"
+
+// NotificationServiceInterface define los métodos para comunicarse con el notification-service
+type NotificationServiceInterface interface {
+	NotifyScheduledTransactionFailure(failure clients.ScheduledTransactionFailure) error
+	NotifyFraudAlert(alert clients.FraudAlert) error
+	NotifyBudgetAlert(alert clients.BudgetAlert) error
+}
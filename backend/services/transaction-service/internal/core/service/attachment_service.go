@@ -0,0 +1,250 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	domainattachment "github.com/fintrack/transaction-service/internal/core/domain/entities/attachment"
+)
+
+const (
+	// DefaultAttachmentMaxFileSize bounds each receipt or invoice
+	DefaultAttachmentMaxFileSize = 10 << 20
+	// DefaultAttachmentUserQuota bounds the storage of each user
+	DefaultAttachmentUserQuota = 200 << 20
+	// attachmentPurgeBatch bounds the orphan attachments deleted per pass
+	attachmentPurgeBatch = 500
+)
+
+// ErrAttachmentQuotaExceeded is returned by the repository when an attachment does not fit in the quota of its user
+var ErrAttachmentQuotaExceeded = errors.New("the file exceeds the storage quota")
+
+// AttachmentService implements AttachmentServiceInterface
+// Keeps the metadata in the database and the content in a storage driver (local disk or S3 compatible)
+type AttachmentService struct {
+	attachmentRepo  AttachmentRepositoryInterface
+	transactionRepo TransactionRepositoryInterface
+	storage         AttachmentStorageInterface
+	limits          AttachmentLimits
+}
+
+// NewAttachmentService creates a new attachment service. Zero limits take the defaults.
+func NewAttachmentService(attachmentRepo AttachmentRepositoryInterface, transactionRepo TransactionRepositoryInterface, storage AttachmentStorageInterface, limits AttachmentLimits) AttachmentServiceInterface {
+	if limits.MaxFileSize <= 0 {
+		limits.MaxFileSize = DefaultAttachmentMaxFileSize
+	}
+	if limits.UserQuota <= 0 {
+		limits.UserQuota = DefaultAttachmentUserQuota
+	}
+
+	return &AttachmentService{
+		attachmentRepo:  attachmentRepo,
+		transactionRepo: transactionRepo,
+		storage:         storage,
+		limits:          limits,
+	}
+}
+
+// Upload stores a file for a transaction or installment plan of the user. The content type is sniffed
+// from the file and the upload must fit both the size limit and the remaining quota of the user.
+func (s *AttachmentService) Upload(userID string, request UploadAttachmentRequest) (*domainattachment.Attachment, error) {
+	if err := s.checkOwner(userID, request.OwnerType, request.OwnerID); err != nil {
+		return nil, err
+	}
+	if request.Content == nil || request.Size <= 0 {
+		return nil, errors.New("the file is empty")
+	}
+	if request.Size > s.limits.MaxFileSize {
+		return nil, fmt.Errorf("the file exceeds the maximum size of %s", formatBytes(s.limits.MaxFileSize))
+	}
+
+	// Checked here to reject the upload before storing it; the insert checks it again for concurrent uploads
+	usage, err := s.GetUsage(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !usage.Fits(request.Size) {
+		return nil, quotaExceeded(usage)
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(request.Content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("cannot read the file: %w", err)
+	}
+	head = head[:n]
+
+	contentType, err := domainattachment.DetectContentType(head)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &domainattachment.Attachment{
+		ID:          generateAttachmentID(),
+		UserID:      userID,
+		OwnerType:   request.OwnerType,
+		OwnerID:     request.OwnerID,
+		FileName:    domainattachment.SanitizeFileName(request.FileName, contentType),
+		ContentType: contentType,
+		Size:        request.Size,
+	}
+	attachment.StorageKey = domainattachment.StorageKey(userID, attachment.ID, contentType)
+
+	content := io.MultiReader(bytes.NewReader(head), request.Content)
+	if err := s.storage.Put(attachment.StorageKey, content, request.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	created, err := s.attachmentRepo.Create(attachment, s.limits.UserQuota)
+	if err != nil {
+		// Without its metadata the content could never be reached nor counted
+		if deleteErr := s.storage.Delete(attachment.StorageKey); deleteErr != nil {
+			fmt.Printf("Warning: failed to delete stored content %s: %v\n", attachment.StorageKey, deleteErr)
+		}
+		if errors.Is(err, ErrAttachmentQuotaExceeded) {
+			if usage, usageErr := s.GetUsage(userID); usageErr == nil {
+				return nil, quotaExceeded(usage)
+			}
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetAttachments returns the attachments of a transaction or installment plan of the user
+func (s *AttachmentService) GetAttachments(userID string, ownerType domainattachment.OwnerType, ownerID string) ([]*domainattachment.Attachment, error) {
+	if err := s.checkOwner(userID, ownerType, ownerID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.GetByOwner(ownerType, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if attachments == nil {
+		attachments = []*domainattachment.Attachment{}
+	}
+	return attachments, nil
+}
+
+// GetAttachment returns an attachment of a record the user can see
+func (s *AttachmentService) GetAttachment(userID string, attachmentID string) (*domainattachment.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(attachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwner(userID, attachment.OwnerType, attachment.OwnerID); err != nil {
+		return nil, fmt.Errorf("attachment not found with ID: %s", attachmentID)
+	}
+	return attachment, nil
+}
+
+// OpenContent returns an attachment with a reader of its content
+func (s *AttachmentService) OpenContent(userID string, attachmentID string) (*domainattachment.Attachment, io.ReadCloser, error) {
+	attachment, err := s.GetAttachment(userID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.storage.Get(attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	return attachment, content, nil
+}
+
+// DeleteAttachment deletes an attachment and its content
+func (s *AttachmentService) DeleteAttachment(userID string, attachmentID string) error {
+	attachment, err := s.GetAttachment(userID, attachmentID)
+	if err != nil {
+		return err
+	}
+	return s.delete(attachment)
+}
+
+// GetUsage returns the storage taken by the attachments of the user and their quota
+func (s *AttachmentService) GetUsage(userID string) (*domainattachment.Usage, error) {
+	files, used, err := s.attachmentRepo.GetUsage(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domainattachment.Usage{
+		UsedBytes:  used,
+		QuotaBytes: s.limits.UserQuota,
+		Files:      files,
+	}, nil
+}
+
+// PurgeOrphans deletes the attachments whose transaction or installment plan was purged
+func (s *AttachmentService) PurgeOrphans() (int, error) {
+	orphans, err := s.attachmentRepo.GetOrphans(attachmentPurgeBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, attachment := range orphans {
+		if err := s.delete(attachment); err != nil {
+			fmt.Printf("Warning: failed to purge attachment %s: %v\n", attachment.ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// delete removes the content first: a leftover row is purged again, while leftover content would be unreachable
+func (s *AttachmentService) delete(attachment *domainattachment.Attachment) error {
+	if err := s.storage.Delete(attachment.StorageKey); err != nil {
+		return fmt.Errorf("failed to delete attachment content: %w", err)
+	}
+	return s.attachmentRepo.Delete(attachment.ID)
+}
+
+// checkOwner checks that the user can see the record: the owner or the member who made a transaction,
+// and the owner of an installment plan
+func (s *AttachmentService) checkOwner(userID string, ownerType domainattachment.OwnerType, ownerID string) error {
+	switch ownerType {
+	case domainattachment.OwnerTransaction:
+		transaction, err := s.transactionRepo.GetByID(ownerID)
+		if err != nil {
+			return err
+		}
+		if transaction.UserID != userID && transaction.InitiatedBy != userID {
+			return fmt.Errorf("transaction not found with ID: %s", ownerID)
+		}
+		return nil
+	case domainattachment.OwnerInstallmentPlan:
+		planUserID, err := s.attachmentRepo.GetInstallmentPlanUserID(ownerID)
+		if err != nil {
+			return err
+		}
+		if planUserID != userID {
+			return fmt.Errorf("installment plan not found with ID: %s", ownerID)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid attachment owner type: %s", ownerType)
+	}
+}
+
+// generateAttachmentID returns the ID of a new attachment, which names its content in the storage
+func generateAttachmentID() string {
+	return fmt.Sprintf("att_%d", time.Now().UnixNano())
+}
+
+// quotaExceeded returns the error of an upload that does not fit in the quota, with the usage of the user
+func quotaExceeded(usage *domainattachment.Usage) error {
+	return fmt.Errorf("%w: %s used of %s", ErrAttachmentQuotaExceeded, formatBytes(usage.UsedBytes), formatBytes(usage.QuotaBytes))
+}
+
+// formatBytes returns a size in MB for messages
+func formatBytes(size int64) string {
+	return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+}
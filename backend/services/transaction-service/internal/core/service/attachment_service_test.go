@@ -0,0 +1,308 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	domainattachment "github.com/fintrack/transaction-service/internal/core/domain/entities/attachment"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// MockAttachmentRepository keeps the attachments in memory and checks the quota on insert, as the MySQL repository does
+type MockAttachmentRepository struct {
+	AttachmentRepositoryInterface
+	attachments []*domainattachment.Attachment
+	err         error // Returned by Create when set
+}
+
+func (m *MockAttachmentRepository) Create(attachment *domainattachment.Attachment, quota int64) (*domainattachment.Attachment, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	_, used, _ := m.GetUsage(attachment.UserID)
+	if used+attachment.Size > quota {
+		return nil, ErrAttachmentQuotaExceeded
+	}
+	m.attachments = append(m.attachments, attachment)
+	return attachment, nil
+}
+
+func (m *MockAttachmentRepository) GetUsage(userID string) (int, int64, error) {
+	files, used := 0, int64(0)
+	for _, attachment := range m.attachments {
+		if attachment.UserID == userID {
+			files++
+			used += attachment.Size
+		}
+	}
+	return files, used, nil
+}
+
+// MockAttachmentStorage keeps the content of the attachments in memory
+type MockAttachmentStorage struct {
+	files map[string][]byte
+	onPut func() // Called once by the next Put, after the content is read
+}
+
+func (m *MockAttachmentStorage) Put(key string, content io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return fmt.Errorf("file size mismatch: expected %d bytes, got %d", size, len(data))
+	}
+	if onPut := m.onPut; onPut != nil {
+		m.onPut = nil
+		onPut()
+	}
+	m.files[key] = data
+	return nil
+}
+
+func (m *MockAttachmentStorage) Get(key string) (io.ReadCloser, error) {
+	data, exists := m.files[key]
+	if !exists {
+		return nil, fmt.Errorf("file not found: %s", key)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *MockAttachmentStorage) Delete(key string) error {
+	delete(m.files, key)
+	return nil
+}
+
+// Verify interface compliance
+var _ AttachmentStorageInterface = (*MockAttachmentStorage)(nil)
+
+// Test limits: files up to 1 KB and 2 KB per user
+const (
+	testAttachmentMaxFileSize = 1024
+	testAttachmentUserQuota   = 2048
+)
+
+var (
+	pdfHeader = []byte("%PDF-1.4\n%âãÏÓ\n1 0 obj\n")
+	pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+)
+
+// fileOf returns content that starts with a header and is padded to the given size
+func fileOf(header []byte, size int) []byte {
+	return append(append([]byte(nil), header...), bytes.Repeat([]byte{0}, size-len(header))...)
+}
+
+type attachmentFixture struct {
+	service *AttachmentService
+	repo    *MockAttachmentRepository
+	storage *MockAttachmentStorage
+}
+
+// setupAttachmentService creates the transaction tx-1 of user-1 and tx-2 of user-2
+func setupAttachmentService() *attachmentFixture {
+	transactions := &MockTransactionRepository{transactions: []*domaintransaction.Transaction{
+		{ID: "tx-1", UserID: "user-1"},
+		{ID: "tx-2", UserID: "user-2"},
+	}}
+	repo := &MockAttachmentRepository{}
+	storage := &MockAttachmentStorage{files: make(map[string][]byte)}
+	service := NewAttachmentService(repo, transactions, storage, AttachmentLimits{
+		MaxFileSize: testAttachmentMaxFileSize,
+		UserQuota:   testAttachmentUserQuota,
+	}).(*AttachmentService)
+
+	return &attachmentFixture{service: service, repo: repo, storage: storage}
+}
+
+// upload attaches content to a transaction on behalf of a user
+func (f *attachmentFixture) upload(userID, transactionID, fileName string, content []byte) (*domainattachment.Attachment, error) {
+	return f.service.Upload(userID, UploadAttachmentRequest{
+		OwnerType: domainattachment.OwnerTransaction,
+		OwnerID:   transactionID,
+		FileName:  fileName,
+		Size:      int64(len(content)),
+		Content:   bytes.NewReader(content),
+	})
+}
+
+func TestUploadAttachment(t *testing.T) {
+	tests := []struct {
+		name                string
+		userID              string
+		fileName            string
+		content             []byte
+		used                int64 // Bytes the user already stored
+		expectedContentType string
+		expectedFileName    string
+		expectedError       string
+	}{
+		{
+			name:                "PDF invoice",
+			userID:              "user-1",
+			fileName:            "factura.pdf",
+			content:             fileOf(pdfHeader, 600),
+			expectedContentType: "application/pdf",
+			expectedFileName:    "factura.pdf",
+		},
+		{
+			name:                "type sniffed from the content, not the name",
+			userID:              "user-1",
+			fileName:            "ticket.pdf",
+			content:             fileOf(pngHeader, 600),
+			expectedContentType: "image/png",
+			expectedFileName:    "ticket.pdf",
+		},
+		{
+			name:                "path in the file name",
+			userID:              "user-1",
+			fileName:            "../../etc/factura.pdf",
+			content:             fileOf(pdfHeader, 100),
+			expectedContentType: "application/pdf",
+			expectedFileName:    "factura.pdf",
+		},
+		{
+			name:                "file smaller than the sniffed bytes",
+			userID:              "user-1",
+			fileName:            "mini.png",
+			content:             pngHeader,
+			expectedContentType: "image/png",
+			expectedFileName:    "mini.png",
+		},
+		{
+			name:          "unsupported type with an allowed name",
+			userID:        "user-1",
+			fileName:      "factura.pdf",
+			content:       []byte("<html><body>factura</body></html>"),
+			expectedError: "unsupported file type text/html",
+		},
+		{
+			name:                "file at the size limit",
+			userID:              "user-1",
+			fileName:            "factura.pdf",
+			content:             fileOf(pdfHeader, testAttachmentMaxFileSize),
+			expectedContentType: "application/pdf",
+			expectedFileName:    "factura.pdf",
+		},
+		{
+			name:          "file over the size limit",
+			userID:        "user-1",
+			fileName:      "factura.pdf",
+			content:       fileOf(pdfHeader, testAttachmentMaxFileSize+1),
+			expectedError: "exceeds the maximum size",
+		},
+		{
+			name:          "empty file",
+			userID:        "user-1",
+			fileName:      "factura.pdf",
+			expectedError: "the file is empty",
+		},
+		{
+			name:                "file filling the quota",
+			userID:              "user-1",
+			fileName:            "factura.pdf",
+			content:             fileOf(pdfHeader, 48),
+			used:                2000,
+			expectedContentType: "application/pdf",
+			expectedFileName:    "factura.pdf",
+		},
+		{
+			name:          "file over the quota",
+			userID:        "user-1",
+			fileName:      "factura.pdf",
+			content:       fileOf(pdfHeader, 49),
+			used:          2000,
+			expectedError: "exceeds the storage quota",
+		},
+		{
+			name:          "transaction of another user",
+			userID:        "user-2",
+			fileName:      "factura.pdf",
+			content:       fileOf(pdfHeader, 100),
+			expectedError: "transaction not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupAttachmentService()
+			if tt.used > 0 {
+				f.repo.attachments = append(f.repo.attachments, &domainattachment.Attachment{ID: "att-old", UserID: tt.userID, Size: tt.used})
+			}
+			stored := len(f.repo.attachments)
+
+			attachment, err := f.upload(tt.userID, "tx-1", tt.fileName, tt.content)
+
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("Upload() error = %v, want %q", err, tt.expectedError)
+				}
+				if len(f.repo.attachments) != stored || len(f.storage.files) != 0 {
+					t.Errorf("Upload() rejected but stored %d attachments and %d files", len(f.repo.attachments)-stored, len(f.storage.files))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Upload() unexpected error: %v", err)
+			}
+
+			if attachment.ContentType != tt.expectedContentType || attachment.FileName != tt.expectedFileName {
+				t.Errorf("Upload() = %q %q, want %q %q", attachment.ContentType, attachment.FileName, tt.expectedContentType, tt.expectedFileName)
+			}
+			if attachment.UserID != tt.userID || attachment.OwnerID != "tx-1" || attachment.Size != int64(len(tt.content)) {
+				t.Errorf("Upload() = %v %v %v bytes, want %v tx-1 %v bytes", attachment.UserID, attachment.OwnerID, attachment.Size, tt.userID, len(tt.content))
+			}
+			if !strings.HasPrefix(attachment.StorageKey, "attachments/"+tt.userID+"/"+attachment.ID+".") {
+				t.Errorf("Upload() storage key = %q, want it named after the user and the attachment", attachment.StorageKey)
+			}
+			// The sniffed bytes are stored too
+			if !bytes.Equal(f.storage.files[attachment.StorageKey], tt.content) {
+				t.Error("Upload() stored content differs from the uploaded file")
+			}
+		})
+	}
+}
+
+func TestUploadAttachmentConcurrentQuota(t *testing.T) {
+	f := setupAttachmentService()
+	f.repo.attachments = append(f.repo.attachments, &domainattachment.Attachment{ID: "att-old", UserID: "user-1", Size: 100})
+
+	// A second upload finishes while the first one is being stored: both fit alone, not together
+	var second *domainattachment.Attachment
+	var secondErr error
+	f.storage.onPut = func() {
+		second, secondErr = f.upload("user-1", "tx-1", "otra.pdf", fileOf(pdfHeader, 1000))
+	}
+	_, err := f.upload("user-1", "tx-1", "factura.pdf", fileOf(pdfHeader, 1000))
+
+	if secondErr != nil {
+		t.Fatalf("Upload() of the second file unexpected error: %v", secondErr)
+	}
+	if err == nil || !errors.Is(err, ErrAttachmentQuotaExceeded) {
+		t.Fatalf("Upload() error = %v, want %v", err, ErrAttachmentQuotaExceeded)
+	}
+	if !strings.Contains(err.Error(), "used of") {
+		t.Errorf("Upload() error = %q, want the usage of the user", err)
+	}
+
+	// Only the second file is kept, with its content
+	if len(f.repo.attachments) != 2 || len(f.storage.files) != 1 || f.storage.files[second.StorageKey] == nil {
+		t.Errorf("Upload() kept %d attachments and %d files, want the stored one and the second one", len(f.repo.attachments), len(f.storage.files))
+	}
+}
+
+func TestUploadAttachmentRepositoryFailure(t *testing.T) {
+	f := setupAttachmentService()
+	f.repo.err = errors.New("failed to create attachment: connection refused")
+
+	if _, err := f.upload("user-1", "tx-1", "factura.pdf", fileOf(pdfHeader, 100)); err == nil {
+		t.Fatal("Upload() expected error but got none")
+	}
+	// Content without metadata could never be reached nor counted
+	if len(f.storage.files) != 0 {
+		t.Errorf("Upload() left %d files in the storage, want none", len(f.storage.files))
+	}
+}
@@ -0,0 +1,419 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	domainbudget "github.com/fintrack/transaction-service/internal/core/domain/entities/budget"
+	domaincategory "github.com/fintrack/transaction-service/internal/core/domain/entities/category"
+	"github.com/fintrack/transaction-service/internal/core/interfaces"
+	"github.com/fintrack/transaction-service/internal/infrastructure/http/clients"
+)
+
+const (
+	// budgetHistoryMonths is the number of months returned by the history when the request does not set one
+	budgetHistoryMonths = 6
+	// budgetHistoryMax bounds the months of the history
+	budgetHistoryMax = 24
+)
+
+// BudgetService implements BudgetServiceInterface
+// Compares monthly budgets by category, merchant or card against the actual spending, carrying over what
+// was left from one month to the next when the budget rolls over. Every computed month is kept as a
+// snapshot for reports and the chatbot, and the alert thresholds are notified once per budget and month.
+type BudgetService struct {
+	budgetRepo          BudgetRepositoryInterface
+	categoryRepo        CategoryRepositoryInterface
+	labelRepo           LabelRepositoryInterface
+	notificationService interfaces.NotificationServiceInterface
+}
+
+// NewBudgetService creates a new budget service
+func NewBudgetService(
+	budgetRepo BudgetRepositoryInterface,
+	categoryRepo CategoryRepositoryInterface,
+	labelRepo LabelRepositoryInterface,
+	notificationService interfaces.NotificationServiceInterface,
+) BudgetServiceInterface {
+	return &BudgetService{
+		budgetRepo:          budgetRepo,
+		categoryRepo:        categoryRepo,
+		labelRepo:           labelRepo,
+		notificationService: notificationService,
+	}
+}
+
+// CreateBudget creates a budget for a category, a merchant or a card of the user
+func (s *BudgetService) CreateBudget(userID string, request BudgetRequest) (*domainbudget.Budget, error) {
+	now := time.Now()
+	budget := &domainbudget.Budget{
+		ID:        fmt.Sprintf("bdg_%d", now.UnixNano()),
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.applyRequest(budget, request, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.budgetRepo.Create(budget); err != nil {
+		return nil, fmt.Errorf("failed to create budget: %w", err)
+	}
+
+	return budget, nil
+}
+
+// GetBudgets returns the budgets of the user, only the ones that apply to a month when one is given
+func (s *BudgetService) GetBudgets(userID string, month string) ([]*domainbudget.Budget, error) {
+	if month != "" {
+		if _, err := domainbudget.ParseMonth(month); err != nil {
+			return nil, err
+		}
+	}
+
+	budgets, err := s.budgetRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budgets: %w", err)
+	}
+	if month == "" {
+		return budgets, nil
+	}
+
+	active := []*domainbudget.Budget{}
+	for _, budget := range budgets {
+		if budget.ActiveIn(month) {
+			active = append(active, budget)
+		}
+	}
+	return active, nil
+}
+
+// GetBudget returns a budget of the user
+func (s *BudgetService) GetBudget(userID string, budgetID string) (*domainbudget.Budget, error) {
+	return s.getOwnBudget(userID, budgetID)
+}
+
+// UpdateBudget replaces the settings of a budget of the user
+func (s *BudgetService) UpdateBudget(userID string, budgetID string, request BudgetRequest) (*domainbudget.Budget, error) {
+	budget, err := s.getOwnBudget(userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.applyRequest(budget, request, now); err != nil {
+		return nil, err
+	}
+	budget.UpdatedAt = now
+
+	if err := s.budgetRepo.Update(budget); err != nil {
+		return nil, fmt.Errorf("failed to update budget: %w", err)
+	}
+
+	return budget, nil
+}
+
+// DeleteBudget deletes a budget of the user with its months and alerts
+func (s *BudgetService) DeleteBudget(userID string, budgetID string) error {
+	if _, err := s.getOwnBudget(userID, budgetID); err != nil {
+		return err
+	}
+
+	if err := s.budgetRepo.Delete(budgetID); err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+	return nil
+}
+
+// GetProgress returns a budget against the actual spending of a month, the current one by default
+func (s *BudgetService) GetProgress(userID string, budgetID string, month string) (*domainbudget.Period, error) {
+	budget, err := s.getOwnBudget(userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if month == "" {
+		month = domainbudget.MonthOf(now)
+	}
+	if _, err := domainbudget.ParseMonth(month); err != nil {
+		return nil, err
+	}
+	if !budget.ActiveIn(month) {
+		return nil, fmt.Errorf("budget does not apply to %s", month)
+	}
+
+	periods, err := s.computePeriods(budget, month, month, now)
+	if err != nil {
+		return nil, err
+	}
+	return periods[0], nil
+}
+
+// GetHistory returns a budget against the actual spending of its last months up to the current one,
+// oldest first
+func (s *BudgetService) GetHistory(userID string, budgetID string, months int) ([]*domainbudget.Period, error) {
+	budget, err := s.getOwnBudget(userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if months <= 0 {
+		months = budgetHistoryMonths
+	}
+	if months > budgetHistoryMax {
+		months = budgetHistoryMax
+	}
+
+	now := time.Now()
+	to := domainbudget.MonthOf(now)
+	if budget.EndMonth != nil && *budget.EndMonth < to {
+		to = *budget.EndMonth
+	}
+	if to < budget.StartMonth {
+		// The budget starts in a future month
+		return []*domainbudget.Period{}, nil
+	}
+	from := domainbudget.AddMonths(to, -(months - 1))
+	if from < budget.StartMonth {
+		from = budget.StartMonth
+	}
+
+	return s.computePeriods(budget, from, to, now)
+}
+
+// GetSummary returns every budget of a month against the actual spending, with totals by currency
+func (s *BudgetService) GetSummary(userID string, month string) (*BudgetSummary, error) {
+	now := time.Now()
+	if month == "" {
+		month = domainbudget.MonthOf(now)
+	}
+
+	budgets, err := s.GetBudgets(userID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &BudgetSummary{
+		Month:   month,
+		Budgets: []*BudgetProgress{},
+		Totals:  []*BudgetTotals{},
+	}
+	totals := make(map[string]*BudgetTotals)
+	for _, budget := range budgets {
+		periods, err := s.computePeriods(budget, month, month, now)
+		if err != nil {
+			return nil, err
+		}
+		period := periods[0]
+		summary.Budgets = append(summary.Budgets, &BudgetProgress{Budget: budget, Period: period})
+
+		total, exists := totals[budget.Currency]
+		if !exists {
+			total = &BudgetTotals{Currency: budget.Currency}
+			totals[budget.Currency] = total
+			summary.Totals = append(summary.Totals, total)
+		}
+		total.Limit += period.Limit
+		total.Actual += period.Actual
+		total.Remaining += period.Remaining
+		if period.Status == domainbudget.StatusExceeded {
+			total.Exceeded++
+		}
+	}
+	for _, total := range summary.Totals {
+		total.Limit = math.Round(total.Limit*100) / 100
+		total.Actual = math.Round(total.Actual*100) / 100
+		total.Remaining = math.Round(total.Remaining*100) / 100
+	}
+
+	return summary, nil
+}
+
+// RefreshAll recomputes the current month of every budget and sends the alerts of the thresholds reached
+// since the last pass. It returns the number of budgets refreshed.
+func (s *BudgetService) RefreshAll(now time.Time) (int, error) {
+	month := domainbudget.MonthOf(now)
+	budgets, err := s.budgetRepo.GetActive(month)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get active budgets: %w", err)
+	}
+
+	refreshed := 0
+	for _, budget := range budgets {
+		periods, err := s.computePeriods(budget, month, month, now)
+		if err != nil {
+			fmt.Printf("Warning: Failed to refresh budget %s: %v\n", budget.ID, err)
+			continue
+		}
+		refreshed++
+
+		if budget.AlertsEnabled {
+			s.sendAlerts(budget, periods[0])
+		}
+	}
+
+	return refreshed, nil
+}
+
+// computePeriods computes the months of a budget between two months. Earlier months are computed too,
+// up to MaxRolloverMonths, when the budget carries what was left over. The result is saved as snapshot.
+func (s *BudgetService) computePeriods(budget *domainbudget.Budget, from, to string, now time.Time) ([]*domainbudget.Period, error) {
+	start := from
+	if budget.Rollover != domainbudget.RolloverNone {
+		start = domainbudget.AddMonths(from, -domainbudget.MaxRolloverMonths)
+	}
+	if start < budget.StartMonth {
+		start = budget.StartMonth
+	}
+
+	periods := []*domainbudget.Period{}
+	var carriedOver float64
+	for month := start; month <= to; month = domainbudget.AddMonths(month, 1) {
+		if !budget.ActiveIn(month) {
+			break
+		}
+
+		monthStart, monthEnd, err := domainbudget.MonthRange(month)
+		if err != nil {
+			return nil, err
+		}
+		spending, err := s.budgetRepo.GetSpending(budget, monthStart, monthEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get spending of budget: %w", err)
+		}
+
+		period := domainbudget.NewPeriod(budget, month, carriedOver, spending.Transactions, spending.Installments, now)
+		carriedOver = domainbudget.CarryOver(budget.Rollover, period)
+		if month >= from {
+			periods = append(periods, period)
+		}
+	}
+
+	if err := s.budgetRepo.SavePeriods(periods); err != nil {
+		fmt.Printf("Warning: Failed to save the months of budget %s: %v\n", budget.ID, err)
+	}
+
+	return periods, nil
+}
+
+// sendAlerts claims every threshold the month reached and notifies the highest one claimed, so a jump
+// from under 50% to over 80% sends a single alert
+func (s *BudgetService) sendAlerts(budget *domainbudget.Budget, period *domainbudget.Period) {
+	highest := 0
+	for _, threshold := range period.Thresholds {
+		claimed, err := s.budgetRepo.ClaimAlert(period, threshold)
+		if err != nil {
+			fmt.Printf("Warning: Failed to claim alert %d%% of budget %s: %v\n", threshold, budget.ID, err)
+			continue
+		}
+		if claimed {
+			highest = threshold
+		}
+	}
+	if highest == 0 || s.notificationService == nil {
+		return
+	}
+
+	err := s.notificationService.NotifyBudgetAlert(clients.BudgetAlert{
+		UserID:       budget.UserID,
+		BudgetID:     budget.ID,
+		BudgetName:   budget.Name,
+		Scope:        string(budget.Scope),
+		Month:        period.Month,
+		Threshold:    highest,
+		Limit:        period.Limit,
+		Actual:       period.Actual,
+		Remaining:    period.Remaining,
+		PercentUsed:  period.PercentUsed,
+		Installments: period.Installments,
+		Currency:     budget.Currency,
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to notify alert %d%% of budget %s: %v\n", highest, budget.ID, err)
+	}
+}
+
+// applyRequest sets the settings of a request on a budget, checking the category or card it is for
+func (s *BudgetService) applyRequest(budget *domainbudget.Budget, request BudgetRequest, now time.Time) error {
+	budget.Name = strings.TrimSpace(request.Name)
+	budget.Scope = request.Scope
+	budget.Amount = request.Amount
+	budget.Currency = strings.ToUpper(strings.TrimSpace(request.Currency))
+	budget.Rollover = request.Rollover
+	if budget.Rollover == "" {
+		budget.Rollover = domainbudget.RolloverNone
+	}
+	budget.StartMonth = request.StartMonth
+	if budget.StartMonth == "" {
+		budget.StartMonth = domainbudget.MonthOf(now)
+	}
+	budget.EndMonth = request.EndMonth
+	budget.AlertsEnabled = true
+	if request.AlertsEnabled != nil {
+		budget.AlertsEnabled = *request.AlertsEnabled
+	}
+
+	// Only the target of the scope is kept
+	budget.CategoryID, budget.MerchantName, budget.CardID = nil, nil, nil
+	switch request.Scope {
+	case domainbudget.ScopeCategory:
+		budget.CategoryID = request.CategoryID
+	case domainbudget.ScopeMerchant:
+		if request.MerchantName != nil {
+			merchantName := strings.TrimSpace(*request.MerchantName)
+			budget.MerchantName = &merchantName
+		}
+	case domainbudget.ScopeCard:
+		budget.CardID = request.CardID
+	}
+
+	// A card budget is in the currency of the card unless the request sets one
+	if budget.Scope == domainbudget.ScopeCard && budget.CardID != nil {
+		cards, err := s.labelRepo.GetCardLabels(budget.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get cards: %w", err)
+		}
+		card, exists := cards[*budget.CardID]
+		if !exists {
+			return fmt.Errorf("card not found with ID: %s", *budget.CardID)
+		}
+		if budget.Currency == "" {
+			budget.Currency = card.Currency
+		}
+	}
+
+	if err := budget.Validate(); err != nil {
+		return err
+	}
+
+	if budget.Scope == domainbudget.ScopeCategory {
+		category, err := s.categoryRepo.GetByID(*budget.CategoryID)
+		if err != nil {
+			return err
+		}
+		if !category.IsVisibleTo(budget.UserID) {
+			return fmt.Errorf("category not found with ID: %s", *budget.CategoryID)
+		}
+		if category.Kind != domaincategory.CategoryKindExpense {
+			return errors.New("budgets can only be set on expense categories")
+		}
+	}
+
+	return nil
+}
+
+// getOwnBudget loads a budget of the user
+func (s *BudgetService) getOwnBudget(userID string, budgetID string) (*domainbudget.Budget, error) {
+	budget, err := s.budgetRepo.GetByID(budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if budget.UserID != userID {
+		return nil, fmt.Errorf("budget not found with ID: %s", budgetID)
+	}
+	return budget, nil
+}
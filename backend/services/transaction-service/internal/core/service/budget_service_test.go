@@ -0,0 +1,340 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	domainbudget "github.com/fintrack/transaction-service/internal/core/domain/entities/budget"
+	"github.com/fintrack/transaction-service/internal/core/interfaces"
+	"github.com/fintrack/transaction-service/internal/infrastructure/http/clients"
+)
+
+// budgetExpense is an expense in the scope of the budgets of the tests: a transaction, or an installment
+// due on its date
+type budgetExpense struct {
+	date        time.Time
+	amount      float64
+	installment bool
+}
+
+// MockBudgetRepository keeps budgets and expenses in memory and sums the expenses of a range as the
+// database does
+type MockBudgetRepository struct {
+	BudgetRepositoryInterface
+	budgets  map[string]*domainbudget.Budget
+	expenses []budgetExpense
+	saved    []*domainbudget.Period
+	claimed  map[string]bool
+}
+
+func (m *MockBudgetRepository) GetByID(id string) (*domainbudget.Budget, error) {
+	budget, exists := m.budgets[id]
+	if !exists {
+		return nil, fmt.Errorf("budget not found with ID: %s", id)
+	}
+	return budget, nil
+}
+
+func (m *MockBudgetRepository) GetActive(month string) ([]*domainbudget.Budget, error) {
+	var budgets []*domainbudget.Budget
+	for _, budget := range m.budgets {
+		if budget.ActiveIn(month) {
+			budgets = append(budgets, budget)
+		}
+	}
+	return budgets, nil
+}
+
+func (m *MockBudgetRepository) GetSpending(budget *domainbudget.Budget, from, to time.Time) (*BudgetSpending, error) {
+	spending := &BudgetSpending{}
+	for _, expense := range m.expenses {
+		if expense.date.Before(from) || !expense.date.Before(to) {
+			continue
+		}
+		if expense.installment {
+			spending.Installments += expense.amount
+		} else {
+			spending.Transactions += expense.amount
+		}
+	}
+	return spending, nil
+}
+
+func (m *MockBudgetRepository) SavePeriods(periods []*domainbudget.Period) error {
+	m.saved = append(m.saved, periods...)
+	return nil
+}
+
+func (m *MockBudgetRepository) ClaimAlert(period *domainbudget.Period, threshold int) (bool, error) {
+	key := fmt.Sprintf("%s/%s/%d", period.BudgetID, period.Month, threshold)
+	if m.claimed[key] {
+		return false, nil
+	}
+	m.claimed[key] = true
+	return true, nil
+}
+
+// MockNotificationService records the budget alerts sent
+type MockNotificationService struct {
+	interfaces.NotificationServiceInterface
+	alerts []clients.BudgetAlert
+	err    error
+}
+
+func (m *MockNotificationService) NotifyBudgetAlert(alert clients.BudgetAlert) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.alerts = append(m.alerts, alert)
+	return nil
+}
+
+var _ BudgetRepositoryInterface = (*MockBudgetRepository)(nil)
+var _ interfaces.NotificationServiceInterface = (*MockNotificationService)(nil)
+
+type budgetFixture struct {
+	service       *BudgetService
+	budgets       *MockBudgetRepository
+	notifications *MockNotificationService
+}
+
+func setupBudgetService() *budgetFixture {
+	budgets := &MockBudgetRepository{
+		budgets: make(map[string]*domainbudget.Budget),
+		claimed: make(map[string]bool),
+	}
+	notifications := &MockNotificationService{}
+	return &budgetFixture{
+		service:       NewBudgetService(budgets, nil, nil, notifications).(*BudgetService),
+		budgets:       budgets,
+		notifications: notifications,
+	}
+}
+
+// addBudget adds a groceries budget of 1000 ARS a month
+func (f *budgetFixture) addBudget(rollover domainbudget.Rollover, startMonth string) *domainbudget.Budget {
+	categoryID := "category-groceries"
+	budget := &domainbudget.Budget{
+		ID:            fmt.Sprintf("bdg-%d", len(f.budgets.budgets)+1),
+		UserID:        "user-1",
+		Name:          "Supermercado",
+		Scope:         domainbudget.ScopeCategory,
+		CategoryID:    &categoryID,
+		Amount:        1000,
+		Currency:      "ARS",
+		Rollover:      rollover,
+		StartMonth:    startMonth,
+		AlertsEnabled: true,
+	}
+	f.budgets.budgets[budget.ID] = budget
+	return budget
+}
+
+// spend records an expense on a day of a month, in local time as the months of the budgets
+func (f *budgetFixture) spend(month string, day int, amount float64, installment bool) {
+	start, _ := domainbudget.ParseMonth(month)
+	f.budgets.expenses = append(f.budgets.expenses, budgetExpense{
+		date:        start.AddDate(0, 0, day-1),
+		amount:      amount,
+		installment: installment,
+	})
+}
+
+func TestBudgetRolloverAcrossMonths(t *testing.T) {
+	tests := []struct {
+		rollover            domainbudget.Rollover
+		expectedCarriedOver []float64
+		expectedLimits      []float64
+	}{
+		{domainbudget.RolloverNone, []float64{0, 0, 0, 0}, []float64{1000, 1000, 1000, 1000}},
+		// 400 left in January; February is 200 over, which is not taken from March
+		{domainbudget.RolloverUnspent, []float64{0, 400, 0, 800}, []float64{1000, 1400, 1000, 1800}},
+		// The 200 February was over comes out of March
+		{domainbudget.RolloverFull, []float64{0, 400, -200, 600}, []float64{1000, 1400, 800, 1600}},
+	}
+	months := []string{"2025-01", "2025-02", "2025-03", "2025-04"}
+
+	for _, tt := range tests {
+		t.Run(string(tt.rollover), func(t *testing.T) {
+			fixture := setupBudgetService()
+			budget := fixture.addBudget(tt.rollover, "2025-01")
+			fixture.spend("2025-01", 10, 600, false)
+			fixture.spend("2025-02", 3, 1600, false)
+			fixture.spend("2025-03", 28, 200, false)
+			fixture.spend("2025-04", 1, 1000, false)
+
+			for i, month := range months {
+				fixture.budgets.saved = nil
+				period, err := fixture.service.GetProgress("user-1", budget.ID, month)
+				if err != nil {
+					t.Fatalf("GetProgress() unexpected error: %v", err)
+				}
+				if period.Month != month || period.CarriedOver != tt.expectedCarriedOver[i] || period.Limit != tt.expectedLimits[i] {
+					t.Errorf("GetProgress(%s) carried over %v with limit %v, want %v with %v",
+						month, period.CarriedOver, period.Limit, tt.expectedCarriedOver[i], tt.expectedLimits[i])
+				}
+				// The previous months are computed for the rollover but only the month asked for is saved
+				if len(fixture.budgets.saved) != 1 || fixture.budgets.saved[0] != period {
+					t.Errorf("GetProgress(%s) saved %d months, want 1", month, len(fixture.budgets.saved))
+				}
+			}
+		})
+	}
+}
+
+func TestBudgetRolloverLimitedMonths(t *testing.T) {
+	fixture := setupBudgetService()
+	// Nothing spent in over two years: only the last MaxRolloverMonths months are carried over
+	budget := fixture.addBudget(domainbudget.RolloverUnspent, "2023-01")
+
+	period, err := fixture.service.GetProgress("user-1", budget.ID, "2025-03")
+	if err != nil {
+		t.Fatalf("GetProgress() unexpected error: %v", err)
+	}
+	if expected := float64(domainbudget.MaxRolloverMonths) * 1000; period.CarriedOver != expected {
+		t.Errorf("GetProgress() carried over %v, want %v", period.CarriedOver, expected)
+	}
+}
+
+func TestBudgetInstallmentsByMonth(t *testing.T) {
+	fixture := setupBudgetService()
+	budget := fixture.addBudget(domainbudget.RolloverNone, "2025-01")
+	// A purchase of 3000 in 3 installments counts 1000 in each month one is due, not in the month of the purchase
+	fixture.spend("2025-02", 5, 1000, true)
+	fixture.spend("2025-03", 1, 1000, true)
+	fixture.spend("2025-04", 30, 1000, true)
+	fixture.spend("2025-02", 20, 500, false)
+
+	tests := []struct {
+		month                string
+		expectedSpent        float64
+		expectedInstallments float64
+		expectedStatus       domainbudget.Status
+	}{
+		{"2025-01", 0, 0, domainbudget.StatusOnTrack},
+		{"2025-02", 500, 1000, domainbudget.StatusExceeded},
+		{"2025-03", 0, 1000, domainbudget.StatusWarning},
+		{"2025-04", 0, 1000, domainbudget.StatusWarning},
+		{"2025-05", 0, 0, domainbudget.StatusOnTrack},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.month, func(t *testing.T) {
+			period, err := fixture.service.GetProgress("user-1", budget.ID, tt.month)
+			if err != nil {
+				t.Fatalf("GetProgress() unexpected error: %v", err)
+			}
+			if period.Spent != tt.expectedSpent || period.Installments != tt.expectedInstallments || period.Status != tt.expectedStatus {
+				t.Errorf("GetProgress(%s) = %v spent, %v in installments, %s, want %v, %v, %s", tt.month,
+					period.Spent, period.Installments, period.Status, tt.expectedSpent, tt.expectedInstallments, tt.expectedStatus)
+			}
+			if period.Actual != tt.expectedSpent+tt.expectedInstallments {
+				t.Errorf("GetProgress(%s) actual = %v, want %v", tt.month, period.Actual, tt.expectedSpent+tt.expectedInstallments)
+			}
+		})
+	}
+}
+
+func TestGetProgressValidation(t *testing.T) {
+	fixture := setupBudgetService()
+	budget := fixture.addBudget(domainbudget.RolloverNone, "2025-01")
+	endMonth := "2025-06"
+	budget.EndMonth = &endMonth
+
+	tests := []struct {
+		name   string
+		userID string
+		month  string
+	}{
+		{"budget of another user", "user-2", "2025-03"},
+		{"before the start", "user-1", "2024-12"},
+		{"after the end", "user-1", "2025-07"},
+		{"invalid month", "user-1", "03/2025"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fixture.service.GetProgress(tt.userID, budget.ID, tt.month); err == nil {
+				t.Error("GetProgress() expected error but got none")
+			}
+		})
+	}
+}
+
+func TestBudgetAlerts(t *testing.T) {
+	fixture := setupBudgetService()
+	budget := fixture.addBudget(domainbudget.RolloverNone, "2025-01")
+	silent := fixture.addBudget(domainbudget.RolloverNone, "2025-01")
+	silent.AlertsEnabled = false
+	now := time.Date(2025, 3, 20, 9, 0, 0, 0, time.Local)
+
+	steps := []struct {
+		name              string
+		spend             float64
+		at                time.Time
+		expectedThreshold int // Threshold of the alert sent by the refresh, 0 for none
+	}{
+		{"below the first threshold", 450, now, 0},
+		// From 45% to 85% in one refresh: 50 and 80 are claimed, one alert is sent
+		{"past two thresholds at once", 400, now, 80},
+		{"no new threshold", 50, now, 0},
+		{"past the limit", 200, now, 100},
+		{"still past the limit", 100, now, 0},
+		{"first threshold of the next month", 600, now.AddDate(0, 1, 0), 50},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			fixture.spend(domainbudget.MonthOf(step.at), 15, step.spend, false)
+			fixture.notifications.alerts = nil
+
+			refreshed, err := fixture.service.RefreshAll(step.at)
+			if err != nil {
+				t.Fatalf("RefreshAll() unexpected error: %v", err)
+			}
+			if refreshed != 2 {
+				t.Errorf("RefreshAll() = %d, want 2", refreshed)
+			}
+
+			if step.expectedThreshold == 0 {
+				if len(fixture.notifications.alerts) != 0 {
+					t.Errorf("RefreshAll() sent %+v, want no alert", fixture.notifications.alerts)
+				}
+				return
+			}
+			if len(fixture.notifications.alerts) != 1 {
+				t.Fatalf("RefreshAll() sent %d alerts, want 1", len(fixture.notifications.alerts))
+			}
+			alert := fixture.notifications.alerts[0]
+			if alert.BudgetID != budget.ID || alert.Threshold != step.expectedThreshold || alert.Month != domainbudget.MonthOf(step.at) {
+				t.Errorf("RefreshAll() sent %+v, want threshold %d of %s", alert, step.expectedThreshold, budget.ID)
+			}
+		})
+	}
+
+	// The budget without alerts claimed nothing, so enabling them later still alerts this month
+	for key := range fixture.budgets.claimed {
+		if strings.HasPrefix(key, silent.ID+"/") {
+			t.Errorf("RefreshAll() claimed %s of a budget without alerts", key)
+		}
+	}
+}
+
+func TestBudgetAlertsNotificationFailure(t *testing.T) {
+	fixture := setupBudgetService()
+	fixture.addBudget(domainbudget.RolloverNone, "2025-01")
+	fixture.notifications.err = errors.New("notification service unavailable")
+	now := time.Date(2025, 3, 20, 9, 0, 0, 0, time.Local)
+	fixture.spend("2025-03", 15, 600, false)
+
+	if refreshed, err := fixture.service.RefreshAll(now); err != nil || refreshed != 1 {
+		t.Fatalf("RefreshAll() = %d, %v, want 1 refreshed", refreshed, err)
+	}
+	// The threshold stays claimed: the alert is not retried on every pass
+	if !fixture.budgets.claimed["bdg-1/2025-03/50"] {
+		t.Errorf("RefreshAll() claimed %v, want the 50%% threshold", fixture.budgets.claimed)
+	}
+}
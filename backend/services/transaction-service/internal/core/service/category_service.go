@@ -0,0 +1,405 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	domaincategory "github.com/fintrack/transaction-service/internal/core/domain/entities/category"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// reapplyBatchSize is the number of transactions loaded per page when reapplying rules over the history
+const reapplyBatchSize = 200
+
+// CategoryService implements CategoryServiceInterface
+// Handles the category tree and the rules that categorize transactions automatically
+type CategoryService struct {
+	categoryRepo    CategoryRepositoryInterface
+	ruleRepo        CategorizationRuleRepositoryInterface
+	transactionRepo TransactionRepositoryInterface
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(categoryRepo CategoryRepositoryInterface, ruleRepo CategorizationRuleRepositoryInterface, transactionRepo TransactionRepositoryInterface) CategoryServiceInterface {
+	return &CategoryService{
+		categoryRepo:    categoryRepo,
+		ruleRepo:        ruleRepo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+// GetCategoryTree returns the default categories plus the user's custom ones as a tree
+func (s *CategoryService) GetCategoryTree(userID string) ([]*domaincategory.CategoryNode, error) {
+	categories, err := s.categoryRepo.GetForUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	return domaincategory.BuildCategoryTree(categories), nil
+}
+
+// CreateCategory creates a custom category, at the root or under a default or custom category
+func (s *CategoryService) CreateCategory(userID string, request CategoryRequest) (*domaincategory.Category, error) {
+	category := &domaincategory.Category{
+		UserID:   &userID,
+		ParentID: request.ParentID,
+		Name:     strings.TrimSpace(request.Name),
+		Kind:     request.Kind,
+		Icon:     strings.TrimSpace(request.Icon),
+	}
+
+	if request.ParentID != nil {
+		parent, err := s.getVisibleCategory(userID, *request.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ParentID != nil {
+			return nil, fmt.Errorf("categories can only be nested %d levels deep", domaincategory.MaxCategoryDepth)
+		}
+		if category.Kind == "" {
+			category.Kind = parent.Kind
+		}
+		if category.Kind != parent.Kind {
+			return nil, fmt.Errorf("a subcategory must have the same kind as its parent (%s)", parent.Kind)
+		}
+	}
+	if category.Kind == "" {
+		category.Kind = domaincategory.CategoryKindExpense
+	}
+
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkUniqueName(userID, category); err != nil {
+		return nil, err
+	}
+
+	created, err := s.categoryRepo.Create(category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateCategory renames a custom category or changes its icon. Default categories cannot be changed.
+func (s *CategoryService) UpdateCategory(userID string, categoryID string, request CategoryRequest) (*domaincategory.Category, error) {
+	category, err := s.getOwnCategory(userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(request.Name); name != "" {
+		category.Name = name
+	}
+	if request.Icon != "" {
+		category.Icon = strings.TrimSpace(request.Icon)
+	}
+
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkUniqueName(userID, category); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.categoryRepo.Update(category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteCategory deletes a custom category without subcategories. Its transactions move to the
+// parent category (or are left uncategorized) and the rules that assigned it are deleted.
+func (s *CategoryService) DeleteCategory(userID string, categoryID string) error {
+	category, err := s.getOwnCategory(userID, categoryID)
+	if err != nil {
+		return err
+	}
+
+	children, err := s.categoryRepo.CountChildren(categoryID)
+	if err != nil {
+		return fmt.Errorf("failed to check subcategories: %w", err)
+	}
+	if children > 0 {
+		return errors.New("category has subcategories, delete them first")
+	}
+
+	if err := s.transactionRepo.ReassignCategory(userID, categoryID, category.ParentID); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.DeleteByCategory(userID, categoryID); err != nil {
+		return fmt.Errorf("failed to delete category rules: %w", err)
+	}
+	return s.categoryRepo.Delete(categoryID)
+}
+
+// GetRules returns the categorization rules of the user by priority
+func (s *CategoryService) GetRules(userID string) ([]*domaincategory.CategorizationRule, error) {
+	rules, err := s.ruleRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categorization rules: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateRule creates an auto-categorization rule. It applies to new transactions; past ones
+// are recategorized with ReapplyRules.
+func (s *CategoryService) CreateRule(userID string, request CategorizationRuleRequest) (*domaincategory.CategorizationRule, error) {
+	rule := &domaincategory.CategorizationRule{UserID: userID, IsActive: true}
+	if err := s.applyRuleRequest(rule, request); err != nil {
+		return nil, err
+	}
+
+	created, err := s.ruleRepo.Create(rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create categorization rule: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateRule replaces the conditions, category and priority of a rule
+func (s *CategoryService) UpdateRule(userID string, ruleID string, request CategorizationRuleRequest) (*domaincategory.CategorizationRule, error) {
+	rule, err := s.getOwnRule(userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyRuleRequest(rule, request); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.ruleRepo.Update(rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update categorization rule: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteRule deletes a rule. Transactions it already categorized keep their category.
+func (s *CategoryService) DeleteRule(userID string, ruleID string) error {
+	if _, err := s.getOwnRule(userID, ruleID); err != nil {
+		return err
+	}
+	return s.ruleRepo.Delete(ruleID)
+}
+
+// CategorizeTransaction sets the category of a new transaction: the one chosen by the user, which must
+// be visible to them, or the one of the first matching rule. Transactions without a match stay uncategorized.
+func (s *CategoryService) CategorizeTransaction(transaction *domaintransaction.Transaction) error {
+	if transaction.CategoryID != nil {
+		if _, err := s.getVisibleCategory(transaction.UserID, *transaction.CategoryID); err != nil {
+			return err
+		}
+		transaction.CategorySource = domaintransaction.CategorySourceManual
+		return nil
+	}
+
+	rules, err := s.ruleRepo.GetByUserID(transaction.UserID)
+	if err != nil {
+		// Categorization never blocks a transaction; it can be reapplied later
+		fmt.Printf("Warning: Failed to load categorization rules for user %s: %v\n", transaction.UserID, err)
+		return nil
+	}
+
+	if rule := domaincategory.FindMatchingRule(rules, transaction); rule != nil {
+		categoryID := rule.CategoryID
+		transaction.SetCategory(&categoryID, domaintransaction.CategorySourceRule)
+	}
+	return nil
+}
+
+// RecategorizeTransaction changes the category of a transaction by hand. With CreateRule, a rule
+// matching the transaction merchant (or description) is created so similar transactions get the same category.
+func (s *CategoryService) RecategorizeTransaction(userID string, transactionID string, request RecategorizeRequest) (*RecategorizeResult, error) {
+	transaction, err := s.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.UserID != userID {
+		return nil, errors.New("unauthorized: only the transaction owner can change its category")
+	}
+
+	if request.CategoryID != nil {
+		if _, err := s.getVisibleCategory(userID, *request.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+	if request.CreateRule && request.CategoryID == nil {
+		return nil, errors.New("a category is required to create a rule")
+	}
+
+	var rule *domaincategory.CategorizationRule
+	if request.CreateRule {
+		rule, err = domaincategory.NewRuleFromTransaction(transaction, *request.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		// The rule the user just asked for wins over the existing ones
+		rule.Priority, err = s.nextRulePriority(userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	transaction.SetCategory(request.CategoryID, domaintransaction.CategorySourceManual)
+	if err := s.transactionRepo.UpdateCategory(transaction.ID, transaction.CategoryID, transaction.CategorySource); err != nil {
+		return nil, err
+	}
+
+	result := &RecategorizeResult{Transaction: transaction}
+	if rule != nil {
+		created, err := s.ruleRepo.Create(rule)
+		if err != nil {
+			return nil, fmt.Errorf("transaction recategorized but failed to create rule: %w", err)
+		}
+		result.Rule = created
+	}
+	return result, nil
+}
+
+// ReapplyRules runs the categorization rules over the user's past transactions. Transactions categorized
+// by hand are kept unless OverwriteManual is set; those categorized by a rule that no longer matches
+// are left uncategorized.
+func (s *CategoryService) ReapplyRules(userID string, request ReapplyRulesRequest) (*ReapplyRulesResult, error) {
+	if request.FromDate != nil && request.ToDate != nil && request.FromDate.After(*request.ToDate) {
+		return nil, errors.New("fromDate cannot be after toDate")
+	}
+
+	rules, err := s.ruleRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categorization rules: %w", err)
+	}
+
+	result := &ReapplyRulesResult{}
+	for offset := 0; ; offset += reapplyBatchSize {
+		transactions, err := s.transactionRepo.GetForCategorization(userID, request.FromDate, request.ToDate, request.OverwriteManual, reapplyBatchSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, transaction := range transactions {
+			result.Scanned++
+
+			rule := domaincategory.FindMatchingRule(rules, transaction)
+			switch {
+			case rule != nil:
+				if transaction.CategorySource == domaintransaction.CategorySourceRule && stringValue(transaction.CategoryID) == rule.CategoryID {
+					continue
+				}
+				categoryID := rule.CategoryID
+				if err := s.transactionRepo.UpdateCategory(transaction.ID, &categoryID, domaintransaction.CategorySourceRule); err != nil {
+					return nil, err
+				}
+				result.Categorized++
+			case transaction.CategorySource == domaintransaction.CategorySourceRule:
+				if err := s.transactionRepo.UpdateCategory(transaction.ID, nil, ""); err != nil {
+					return nil, err
+				}
+				result.Uncategorized++
+			}
+		}
+
+		if len(transactions) < reapplyBatchSize {
+			break
+		}
+	}
+
+	fmt.Printf("Categorization rules reapplied for user %s: %d scanned, %d categorized, %d uncategorized\n",
+		userID, result.Scanned, result.Categorized, result.Uncategorized)
+
+	return result, nil
+}
+
+// Helper methods
+
+// applyRuleRequest copies the request into the rule checking the category is visible to the user
+func (s *CategoryService) applyRuleRequest(rule *domaincategory.CategorizationRule, request CategorizationRuleRequest) error {
+	if request.CategoryID == "" {
+		return errors.New("rule category is required")
+	}
+	if _, err := s.getVisibleCategory(rule.UserID, request.CategoryID); err != nil {
+		return err
+	}
+
+	rule.CategoryID = request.CategoryID
+	rule.Name = strings.TrimSpace(request.Name)
+	rule.MerchantPattern = request.MerchantPattern
+	rule.DescriptionRegex = request.DescriptionRegex
+	rule.MinAmount = request.MinAmount
+	rule.MaxAmount = request.MaxAmount
+	rule.CardID = request.CardID
+	rule.Priority = request.Priority
+	if request.IsActive != nil {
+		rule.IsActive = *request.IsActive
+	}
+
+	return rule.Validate()
+}
+
+// nextRulePriority returns a priority above every rule of the user
+func (s *CategoryService) nextRulePriority(userID string) (int, error) {
+	rules, err := s.ruleRepo.GetByUserID(userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get categorization rules: %w", err)
+	}
+
+	priority := 0
+	for _, rule := range rules {
+		if rule.Priority >= priority {
+			priority = rule.Priority + 1
+		}
+	}
+	return priority, nil
+}
+
+// checkUniqueName rejects a category named like another one under the same parent
+func (s *CategoryService) checkUniqueName(userID string, category *domaincategory.Category) error {
+	categories, err := s.categoryRepo.GetForUser(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	for _, existing := range categories {
+		if existing.ID != category.ID && stringValue(existing.ParentID) == stringValue(category.ParentID) &&
+			strings.EqualFold(existing.Name, category.Name) {
+			return fmt.Errorf("a category named %q already exists", existing.Name)
+		}
+	}
+	return nil
+}
+
+// getVisibleCategory loads a default category or a custom category of the user
+func (s *CategoryService) getVisibleCategory(userID string, categoryID string) (*domaincategory.Category, error) {
+	category, err := s.categoryRepo.GetByID(categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !category.IsVisibleTo(userID) {
+		return nil, fmt.Errorf("category not found with ID: %s", categoryID)
+	}
+	return category, nil
+}
+
+// getOwnCategory loads a custom category of the user
+func (s *CategoryService) getOwnCategory(userID string, categoryID string) (*domaincategory.Category, error) {
+	category, err := s.getVisibleCategory(userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !category.IsOwnedBy(userID) {
+		return nil, errors.New("unauthorized: default categories cannot be changed")
+	}
+	return category, nil
+}
+
+// getOwnRule loads a categorization rule of the user
+func (s *CategoryService) getOwnRule(userID string, ruleID string) (*domaincategory.CategorizationRule, error) {
+	rule, err := s.ruleRepo.GetByID(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.UserID != userID {
+		return nil, fmt.Errorf("categorization rule not found with ID: %s", ruleID)
+	}
+	return rule, nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domainduplicate "github.com/fintrack/transaction-service/internal/core/domain/entities/duplicate"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+const (
+	// duplicateScanDays is the period scanned when the request does not set one
+	duplicateScanDays = 90
+	// duplicateScanMaxTransactions bounds the transactions analyzed per scan
+	duplicateScanMaxTransactions = 20000
+	// duplicateMergeMax bounds the duplicates merged into a transaction at once
+	duplicateMergeMax = 10
+	// auditActionMerged is the transaction_audit action of a merge, logged on the kept transaction
+	auditActionMerged = "merged"
+)
+
+// duplicateStatuses are the statuses of the transactions that can be duplicates
+var duplicateStatuses = []domaintransaction.TransactionStatus{
+	domaintransaction.TransactionStatusPending,
+	domaintransaction.TransactionStatusCompleted,
+}
+
+// DuplicateService implements DuplicateServiceInterface
+// Finds transactions recorded more than once (e.g. by hand and by an account service callback) and merges them
+type DuplicateService struct {
+	transactionRepo TransactionRepositoryInterface
+	duplicateRepo   DuplicateRepositoryInterface
+}
+
+// NewDuplicateService creates a new duplicate service
+func NewDuplicateService(transactionRepo TransactionRepositoryInterface, duplicateRepo DuplicateRepositoryInterface) DuplicateServiceInterface {
+	return &DuplicateService{
+		transactionRepo: transactionRepo,
+		duplicateRepo:   duplicateRepo,
+	}
+}
+
+// FindDuplicates returns the transactions of the same owner with the same amount and currency, created
+// within the default window of the transaction, that look like the same movement
+func (s *DuplicateService) FindDuplicates(transaction *domaintransaction.Transaction) ([]*domainduplicate.Candidate, error) {
+	config := domainduplicate.DefaultConfig()
+	if !domainduplicate.IsActive(transaction) {
+		return []*domainduplicate.Candidate{}, nil
+	}
+
+	fromDate := transaction.CreatedAt.Add(-config.Window)
+	toDate := transaction.CreatedAt.Add(config.Window)
+	others, _, err := s.transactionRepo.GetByUserID(transaction.UserID, TransactionFilters{
+		Statuses:   duplicateStatuses,
+		Currencies: []string{transaction.Currency},
+		MinAmount:  &transaction.Amount,
+		MaxAmount:  &transaction.Amount,
+		FromDate:   &fromDate,
+		ToDate:     &toDate,
+		Limit:      100,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	return domainduplicate.FindCandidates(transaction, others, config), nil
+}
+
+// ScanDuplicates groups the transactions of the user that look like duplicates, by default over the last 90 days
+func (s *DuplicateService) ScanDuplicates(userID string, request DuplicateScanRequest) (*DuplicateScanResult, error) {
+	config := domainduplicate.DefaultConfig()
+	if request.WindowMinutes != 0 {
+		config.Window = time.Duration(request.WindowMinutes) * time.Minute
+	}
+	if config.Window <= 0 || config.Window > domainduplicate.MaxWindow {
+		return nil, fmt.Errorf("windowMinutes must be between 1 and %d", int(domainduplicate.MaxWindow.Minutes()))
+	}
+
+	toDate := time.Now()
+	if request.ToDate != nil {
+		toDate = *request.ToDate
+	}
+	fromDate := toDate.AddDate(0, 0, -duplicateScanDays)
+	if request.FromDate != nil {
+		fromDate = *request.FromDate
+	}
+	if fromDate.After(toDate) {
+		return nil, errors.New("fromDate must be before toDate")
+	}
+
+	transactions, _, err := s.transactionRepo.GetByUserID(userID, TransactionFilters{
+		Statuses: duplicateStatuses,
+		FromDate: &fromDate,
+		ToDate:   &toDate,
+		Limit:    duplicateScanMaxTransactions,
+		OrderBy:  domaintransaction.SortByCreatedAt,
+		Order:    "asc",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	// Transactions of other members on shared accounts can only be merged by their owner
+	owned := make([]*domaintransaction.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		if transaction.UserID == userID {
+			owned = append(owned, transaction)
+		}
+	}
+
+	return &DuplicateScanResult{
+		FromDate: fromDate,
+		ToDate:   toDate,
+		Scanned:  len(owned),
+		Groups:   domainduplicate.FindGroups(owned, config),
+	}, nil
+}
+
+// MergeDuplicates keeps a transaction, completes it with the details only the duplicates have and deletes
+// the duplicates. Installment plans, installments, audit entries, attachments, schedule runs and
+// reconciliation items that pointed to a duplicate point to the kept transaction afterwards. Balances are
+// not touched: the merge fixes the records, not the money moved.
+func (s *DuplicateService) MergeDuplicates(userID string, request MergeDuplicatesRequest) (*domaintransaction.Transaction, error) {
+	if request.KeepID == "" {
+		return nil, errors.New("keepId is required")
+	}
+	if len(request.DuplicateIDs) == 0 {
+		return nil, errors.New("duplicateIds is required")
+	}
+	if len(request.DuplicateIDs) > duplicateMergeMax {
+		return nil, fmt.Errorf("at most %d duplicates can be merged at once", duplicateMergeMax)
+	}
+
+	keep, err := s.getOwnedTransaction(userID, request.KeepID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{keep.ID: true}
+	duplicates := make([]*domaintransaction.Transaction, 0, len(request.DuplicateIDs))
+	for _, duplicateID := range request.DuplicateIDs {
+		if seen[duplicateID] {
+			return nil, fmt.Errorf("transaction %s is listed more than once", duplicateID)
+		}
+		seen[duplicateID] = true
+
+		duplicate, err := s.getOwnedTransaction(userID, duplicateID)
+		if err != nil {
+			return nil, err
+		}
+		if duplicate.Currency != keep.Currency || !sameAmount(duplicate.Amount, keep.Amount) {
+			return nil, fmt.Errorf("transaction %s has a different amount or currency than %s", duplicate.ID, keep.ID)
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	oldStatus := keep.Status
+	changedFields := domainduplicate.Merge(keep, duplicates)
+	changedFields["mergedTransactionIds"] = request.DuplicateIDs
+
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		reason = "Duplicate transactions merged"
+	}
+
+	audit := &TransactionAuditEntry{
+		TransactionID: keep.ID,
+		Action:        auditActionMerged,
+		OldStatus:     &oldStatus,
+		NewStatus:     &keep.Status,
+		ChangedFields: changedFields,
+		ChangedBy:     userID,
+		ChangeReason:  reason,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := s.duplicateRepo.Merge(keep, request.DuplicateIDs, audit); err != nil {
+		return nil, err
+	}
+
+	return s.transactionRepo.GetByID(keep.ID)
+}
+
+// getOwnedTransaction returns a transaction of the user. Members who performed a transaction on a shared
+// account can see it but only the owner can merge it.
+func (s *DuplicateService) getOwnedTransaction(userID, transactionID string) (*domaintransaction.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.UserID != userID {
+		if transaction.InitiatedBy == userID {
+			return nil, fmt.Errorf("unauthorized: only the account owner can merge transaction %s", transactionID)
+		}
+		return nil, fmt.Errorf("transaction not found with ID: %s", transactionID)
+	}
+	if !domainduplicate.IsActive(transaction) {
+		return nil, fmt.Errorf("transaction %s is %s and cannot be merged", transactionID, transaction.Status)
+	}
+	return transaction, nil
+}
+
+func sameAmount(a, b float64) bool {
+	difference := a - b
+	return difference < 0.005 && difference > -0.005
+}
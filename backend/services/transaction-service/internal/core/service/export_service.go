@@ -0,0 +1,219 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	domainattachment "github.com/fintrack/transaction-service/internal/core/domain/entities/attachment"
+	domaincategory "github.com/fintrack/transaction-service/internal/core/domain/entities/category"
+	domainexport "github.com/fintrack/transaction-service/internal/core/domain/entities/export"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// Metadata keys with the currency conversion of a transaction made in another currency
+const (
+	metadataOriginalAmount   = "originalAmount"
+	metadataOriginalCurrency = "originalCurrency"
+	metadataExchangeRate     = "exchangeRate"
+)
+
+// exportDefaultCurrency is the currency of the OFX statement
+const exportDefaultCurrency = "ARS"
+
+// ExportService implements ExportServiceInterface
+// Streams the transactions of the list endpoint with the names of their categories, accounts and cards
+type ExportService struct {
+	transactionRepo TransactionRepositoryInterface
+	categoryRepo    CategoryRepositoryInterface
+	labelRepo       LabelRepositoryInterface
+	attachmentRepo  AttachmentRepositoryInterface
+}
+
+// NewExportService creates a new export service
+func NewExportService(transactionRepo TransactionRepositoryInterface, categoryRepo CategoryRepositoryInterface, labelRepo LabelRepositoryInterface, attachmentRepo AttachmentRepositoryInterface) ExportServiceInterface {
+	return &ExportService{
+		transactionRepo: transactionRepo,
+		categoryRepo:    categoryRepo,
+		labelRepo:       labelRepo,
+		attachmentRepo:  attachmentRepo,
+	}
+}
+
+// ExportTransactions writes the transactions matching the filters as they are read
+func (s *ExportService) ExportTransactions(userID string, request ExportRequest, w io.Writer) error {
+	if _, _, err := domainexport.ContentType(request.Format); err != nil {
+		return err
+	}
+
+	// The labels are loaded up front, so a failure here happens before anything is written
+	categories, err := s.categoryNames(userID)
+	if err != nil {
+		return err
+	}
+	accounts, err := s.labelRepo.GetAccountLabels(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+	cards, err := s.labelRepo.GetCardLabels(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get cards: %w", err)
+	}
+	attachments, err := s.transactionAttachments(userID)
+	if err != nil {
+		return err
+	}
+
+	writer, err := domainexport.NewWriter(request.Format, w, domainexport.Options{
+		FromDate:        request.Filters.FromDate,
+		ToDate:          request.Filters.ToDate,
+		DefaultCurrency: exportDefaultCurrency,
+		GeneratedAt:     time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	err = s.transactionRepo.StreamByUserID(userID, request.Filters, func(transaction *domaintransaction.Transaction) error {
+		row := buildExportRow(transaction, categories, accounts, cards)
+		row.Attachments = attachments[transaction.ID]
+		return writer.Write(row)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export transactions: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// categoryNames returns the name of every category of the user, with its parent for subcategories
+func (s *ExportService) categoryNames(userID string) (map[string]string, error) {
+	categories, err := s.categoryRepo.GetForUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	byID := make(map[string]*domaincategory.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	names := make(map[string]string, len(categories))
+	for _, category := range categories {
+		name := category.Name
+		if category.ParentID != nil {
+			if parent, exists := byID[*category.ParentID]; exists {
+				name = parent.Name + " / " + name
+			}
+		}
+		names[category.ID] = name
+	}
+	return names, nil
+}
+
+// transactionAttachments returns the attachments of the transactions of the user by transaction ID
+func (s *ExportService) transactionAttachments(userID string) (map[string][]domainexport.AttachmentRef, error) {
+	attachments, err := s.attachmentRepo.GetByUserID(userID, domainattachment.OwnerTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	byTransaction := make(map[string][]domainexport.AttachmentRef)
+	for _, attachment := range attachments {
+		byTransaction[attachment.OwnerID] = append(byTransaction[attachment.OwnerID], domainexport.AttachmentRef{
+			ID:          attachment.ID,
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+		})
+	}
+	return byTransaction, nil
+}
+
+// buildExportRow flattens a transaction. The amount is negative when the money left an account or card of
+// the user, and the account currency is the one of that account or card.
+func buildExportRow(transaction *domaintransaction.Transaction, categories map[string]string, accounts map[string]*AccountLabel, cards map[string]*CardLabel) *domainexport.Row {
+	row := &domainexport.Row{
+		ID:            transaction.ID,
+		Date:          transaction.CreatedAt,
+		ProcessedAt:   transaction.ProcessedAt,
+		Type:          string(transaction.Type),
+		TypeLabel:     transaction.GetDisplayName(),
+		Status:        string(transaction.Status),
+		Currency:      transaction.Currency,
+		Description:   transaction.Description,
+		MerchantName:  transaction.MerchantName,
+		PaymentMethod: string(transaction.PaymentMethod),
+		CategoryID:    transaction.CategoryID,
+		Tags:          transaction.Tags,
+		FromAccountID: transaction.FromAccountID,
+		ToAccountID:   transaction.ToAccountID,
+		FromCardID:    transaction.FromCardID,
+		ToCardID:      transaction.ToCardID,
+		ReferenceID:   transaction.ReferenceID,
+		ExternalID:    transaction.ExternalID,
+	}
+
+	if transaction.CategoryID != nil {
+		row.Category = categories[*transaction.CategoryID]
+	}
+
+	var fromCurrency, toCurrency string
+	if transaction.FromAccountID != nil {
+		if account, exists := accounts[*transaction.FromAccountID]; exists {
+			row.FromAccount, fromCurrency = account.Name, account.Currency
+		}
+	}
+	if transaction.FromCardID != nil {
+		if card, exists := cards[*transaction.FromCardID]; exists {
+			row.FromCard, fromCurrency = card.Name, card.Currency
+		}
+	}
+	if transaction.ToAccountID != nil {
+		if account, exists := accounts[*transaction.ToAccountID]; exists {
+			row.ToAccount, toCurrency = account.Name, account.Currency
+		}
+	}
+	if transaction.ToCardID != nil {
+		if card, exists := cards[*transaction.ToCardID]; exists {
+			row.ToCard, toCurrency = card.Name, card.Currency
+		}
+	}
+
+	switch {
+	case fromCurrency != "":
+		row.Amount, row.AccountCurrency = -transaction.Amount, fromCurrency
+	case toCurrency != "":
+		row.Amount, row.AccountCurrency = transaction.Amount, toCurrency
+	case transaction.IsCreditTransaction():
+		row.Amount = transaction.Amount
+	default:
+		row.Amount = -transaction.Amount
+	}
+
+	row.OriginalAmount = metadataFloat(transaction.Metadata, metadataOriginalAmount)
+	row.ExchangeRate = metadataFloat(transaction.Metadata, metadataExchangeRate)
+	if currency, ok := transaction.Metadata[metadataOriginalCurrency].(string); ok {
+		row.OriginalCurrency = strings.ToUpper(currency)
+	}
+
+	return row
+}
+
+// metadataFloat reads a number of the metadata, stored either as a JSON number or as a string
+func metadataFloat(metadata map[string]interface{}, key string) *float64 {
+	switch value := metadata[key].(type) {
+	case float64:
+		return &value
+	case string:
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
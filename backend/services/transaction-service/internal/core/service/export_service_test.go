@@ -0,0 +1,119 @@
+package service
+
+import (
+	"testing"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+func TestBuildExportRowSign(t *testing.T) {
+	accounts := map[string]*AccountLabel{
+		"acc-ars": {ID: "acc-ars", Name: "Banco Galicia", Currency: "ARS"},
+		"acc-usd": {ID: "acc-usd", Name: "Caja de ahorro USD", Currency: "USD"},
+	}
+	cards := map[string]*CardLabel{
+		"card-visa": {ID: "card-visa", Name: "Visa Galicia", Currency: "ARS"},
+	}
+	id := func(value string) *string { return &value }
+
+	tests := []struct {
+		name             string
+		transaction      domaintransaction.Transaction
+		expectedAmount   float64
+		expectedCurrency string // Account currency of the row
+	}{
+		{
+			name:             "money out of an account",
+			transaction:      domaintransaction.Transaction{Type: domaintransaction.TransactionTypeAccountWithdraw, FromAccountID: id("acc-ars")},
+			expectedAmount:   -1500,
+			expectedCurrency: "ARS",
+		},
+		{
+			name:             "card purchase",
+			transaction:      domaintransaction.Transaction{Type: domaintransaction.TransactionTypeCreditCharge, FromCardID: id("card-visa")},
+			expectedAmount:   -1500,
+			expectedCurrency: "ARS",
+		},
+		{
+			name:             "money into an account",
+			transaction:      domaintransaction.Transaction{Type: domaintransaction.TransactionTypeAccountDeposit, ToAccountID: id("acc-usd")},
+			expectedAmount:   1500,
+			expectedCurrency: "USD",
+		},
+		{
+			name:             "card payment",
+			transaction:      domaintransaction.Transaction{Type: domaintransaction.TransactionTypeCreditPayment, FromAccountID: id("acc-ars"), ToCardID: id("card-visa")},
+			expectedAmount:   -1500,
+			expectedCurrency: "ARS",
+		},
+		{
+			name:             "transfer between own accounts counts from the source",
+			transaction:      domaintransaction.Transaction{Type: domaintransaction.TransactionTypeAccountTransfer, FromAccountID: id("acc-usd"), ToAccountID: id("acc-ars")},
+			expectedAmount:   -1500,
+			expectedCurrency: "USD",
+		},
+		{
+			name:             "transfer from an account of someone else",
+			transaction:      domaintransaction.Transaction{Type: domaintransaction.TransactionTypeAccountTransfer, FromAccountID: id("acc-other"), ToAccountID: id("acc-ars")},
+			expectedAmount:   1500,
+			expectedCurrency: "ARS",
+		},
+		{
+			name:           "unknown accounts, income type",
+			transaction:    domaintransaction.Transaction{Type: domaintransaction.TransactionTypeWalletDeposit, ToAccountID: id("acc-other")},
+			expectedAmount: 1500,
+		},
+		{
+			name:           "unknown accounts, expense type",
+			transaction:    domaintransaction.Transaction{Type: domaintransaction.TransactionTypeDebitPurchase, FromCardID: id("card-other")},
+			expectedAmount: -1500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := tt.transaction
+			transaction.ID = "tx-1"
+			transaction.Amount = 1500
+			transaction.Currency = "ARS"
+
+			row := buildExportRow(&transaction, nil, accounts, cards)
+			if row.Amount != tt.expectedAmount || row.AccountCurrency != tt.expectedCurrency {
+				t.Errorf("buildExportRow() = %v in %q, want %v in %q", row.Amount, row.AccountCurrency, tt.expectedAmount, tt.expectedCurrency)
+			}
+		})
+	}
+}
+
+func TestBuildExportRowLabels(t *testing.T) {
+	categoryID := "cat-almacen"
+	fromAccountID := "acc-ars"
+	transaction := &domaintransaction.Transaction{
+		ID:            "tx-1",
+		Type:          domaintransaction.TransactionTypeDebitPurchase,
+		Amount:        10,
+		Currency:      "USD",
+		CategoryID:    &categoryID,
+		FromAccountID: &fromAccountID,
+		Metadata: map[string]interface{}{
+			metadataOriginalAmount:   "10.50",
+			metadataOriginalCurrency: "usd",
+			metadataExchangeRate:     1050.5,
+		},
+	}
+	categories := map[string]string{categoryID: "Supermercado / Almacén"}
+	accounts := map[string]*AccountLabel{fromAccountID: {ID: fromAccountID, Name: "Banco Galicia", Currency: "ARS"}}
+
+	row := buildExportRow(transaction, categories, accounts, nil)
+
+	if row.Category != "Supermercado / Almacén" || row.FromAccount != "Banco Galicia" {
+		t.Errorf("buildExportRow() labels = %q %q, want %q %q", row.Category, row.FromAccount, "Supermercado / Almacén", "Banco Galicia")
+	}
+	if row.Amount != -10 || row.Currency != "USD" || row.AccountCurrency != "ARS" {
+		t.Errorf("buildExportRow() = %v %v from %v, want -10 USD from ARS", row.Amount, row.Currency, row.AccountCurrency)
+	}
+	if row.OriginalAmount == nil || *row.OriginalAmount != 10.5 || row.OriginalCurrency != "USD" ||
+		row.ExchangeRate == nil || *row.ExchangeRate != 1050.5 {
+		t.Errorf("buildExportRow() conversion = %v %q at %v, want 10.5 USD at 1050.5", row.OriginalAmount, row.OriginalCurrency, row.ExchangeRate)
+	}
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	domainfraud "github.com/fintrack/transaction-service/internal/core/domain/entities/fraud"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+	"github.com/fintrack/transaction-service/internal/core/interfaces"
+	"github.com/fintrack/transaction-service/internal/infrastructure/http/clients"
+)
+
+const (
+	// fraudHistoryDays is the period of the history the transactions are compared against
+	fraudHistoryDays = 90
+	// fraudHistoryMaxTransactions bounds the history loaded to score a transaction
+	fraudHistoryMaxTransactions = 5000
+	// riskScoreListLimit is the number of scores listed when the request does not set one
+	riskScoreListLimit = 50
+	// riskScoreListMax bounds the scores listed at once
+	riskScoreListMax = 500
+)
+
+// FraudService implements FraudServiceInterface
+// Scores incoming transactions with rules over the history of the user: amount outliers, first-seen
+// merchants and cards, unusual hours, velocity and currency mismatches
+type FraudService struct {
+	transactionRepo     TransactionRepositoryInterface
+	fraudRepo           FraudRepositoryInterface
+	labelRepo           LabelRepositoryInterface
+	notificationService interfaces.NotificationServiceInterface
+}
+
+// NewFraudService creates a new fraud service
+func NewFraudService(
+	transactionRepo TransactionRepositoryInterface,
+	fraudRepo FraudRepositoryInterface,
+	labelRepo LabelRepositoryInterface,
+	notificationService interfaces.NotificationServiceInterface,
+) FraudServiceInterface {
+	return &FraudService{
+		transactionRepo:     transactionRepo,
+		fraudRepo:           fraudRepo,
+		labelRepo:           labelRepo,
+		notificationService: notificationService,
+	}
+}
+
+// ScoreTransaction scores a transaction before it is saved. The history is the one of the owner of the
+// account: transactions of members on shared accounts count, those of accounts shared with the owner do not.
+func (s *FraudService) ScoreTransaction(transaction *domaintransaction.Transaction) (*domainfraud.Score, error) {
+	settings, err := s.GetSettings(transaction.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, nil
+	}
+
+	now := time.Now()
+	fromDate := now.AddDate(0, 0, -fraudHistoryDays)
+	history, _, err := s.transactionRepo.GetByUserID(transaction.UserID, TransactionFilters{
+		FromDate: &fromDate,
+		Limit:    fraudHistoryMaxTransactions,
+		OrderBy:  domaintransaction.SortByCreatedAt,
+		Order:    "desc",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+
+	owned := make([]*domaintransaction.Transaction, 0, len(history))
+	for _, previous := range history {
+		if previous.UserID == transaction.UserID {
+			owned = append(owned, previous)
+		}
+	}
+
+	profile := domainfraud.BuildProfile(transaction, owned, settings, now)
+	profile.InstrumentCurrency = s.instrumentCurrency(transaction)
+
+	return domainfraud.Evaluate(transaction, profile, settings, now), nil
+}
+
+// RecordScore persists the score of a saved transaction and notifies the owner when it is not allowed silently
+func (s *FraudService) RecordScore(transaction *domaintransaction.Transaction, score *domainfraud.Score) error {
+	score.ID = fmt.Sprintf("risk_%d", time.Now().UnixNano())
+	score.TransactionID = transaction.ID
+	score.UserID = transaction.UserID
+
+	if err := s.fraudRepo.CreateScore(score); err != nil {
+		return err
+	}
+
+	if score.Action != domainfraud.ActionAllow {
+		s.notifyAlert(transaction, score)
+	}
+	return nil
+}
+
+// GetSettings returns the settings of a user, or the default ones when the user did not configure them
+func (s *FraudService) GetSettings(userID string) (*domainfraud.Settings, error) {
+	settings, err := s.fraudRepo.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return domainfraud.DefaultSettings(userID), nil
+	}
+	return settings, nil
+}
+
+// UpdateSettings changes the fields of the settings of a user present in the request
+func (s *FraudService) UpdateSettings(userID string, request FraudSettingsRequest) (*domainfraud.Settings, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Enabled != nil {
+		settings.Enabled = *request.Enabled
+	}
+	if request.AlertThreshold != nil {
+		settings.AlertThreshold = *request.AlertThreshold
+	}
+	if request.HoldThreshold != nil {
+		settings.HoldThreshold = *request.HoldThreshold
+	}
+	if request.BlockThreshold != nil {
+		settings.BlockThreshold = *request.BlockThreshold
+	}
+	if request.AmountDeviation != nil {
+		settings.AmountDeviation = *request.AmountDeviation
+	}
+	if request.VelocityCount != nil {
+		settings.VelocityCount = *request.VelocityCount
+	}
+	if request.VelocityWindowMinutes != nil {
+		settings.VelocityWindowMinutes = *request.VelocityWindowMinutes
+	}
+	if request.QuietHoursStart != nil {
+		settings.QuietHoursStart = *request.QuietHoursStart
+	}
+	if request.QuietHoursEnd != nil {
+		settings.QuietHoursEnd = *request.QuietHoursEnd
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.fraudRepo.SaveSettings(settings); err != nil {
+		return nil, err
+	}
+	return s.GetSettings(userID)
+}
+
+// GetTransactionScore returns the score of a transaction the user owns or performed
+func (s *FraudService) GetTransactionScore(userID string, transactionID string) (*domainfraud.Score, error) {
+	transaction, err := s.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.UserID != userID && transaction.InitiatedBy != userID {
+		return nil, fmt.Errorf("transaction not found with ID: %s", transactionID)
+	}
+
+	return s.fraudRepo.GetScoreByTransactionID(transactionID)
+}
+
+// GetScores lists the scores of the transactions of a user, latest first
+func (s *FraudService) GetScores(userID string, request RiskScoreListRequest) ([]*domainfraud.Score, error) {
+	if request.MinScore < 0 || request.MinScore > domainfraud.MaxScore {
+		return nil, fmt.Errorf("minScore must be between 0 and %d", domainfraud.MaxScore)
+	}
+	switch request.Action {
+	case "", domainfraud.ActionAllow, domainfraud.ActionAlert, domainfraud.ActionHold, domainfraud.ActionBlock:
+	default:
+		return nil, fmt.Errorf("invalid action: %s", request.Action)
+	}
+
+	limit := request.Limit
+	if limit <= 0 {
+		limit = riskScoreListLimit
+	}
+	if limit > riskScoreListMax {
+		limit = riskScoreListMax
+	}
+
+	return s.fraudRepo.GetScoresByUserID(userID, request.MinScore, request.Action, limit)
+}
+
+// instrumentCurrency returns the currency of the card or account a transaction uses, empty when unknown
+func (s *FraudService) instrumentCurrency(transaction *domaintransaction.Transaction) string {
+	if cardID := domainfraud.CardID(transaction); cardID != "" {
+		cards, err := s.labelRepo.GetCardLabels(transaction.UserID)
+		if err != nil {
+			fmt.Printf("Warning: Failed to get the cards of user %s for the risk score: %v\n", transaction.UserID, err)
+			return ""
+		}
+		if card, exists := cards[cardID]; exists {
+			return card.Currency
+		}
+		return ""
+	}
+
+	accountID := transaction.FromAccountID
+	if accountID == nil {
+		accountID = transaction.ToAccountID
+	}
+	if accountID == nil {
+		return ""
+	}
+	accounts, err := s.labelRepo.GetAccountLabels(transaction.UserID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to get the accounts of user %s for the risk score: %v\n", transaction.UserID, err)
+		return ""
+	}
+	if account, exists := accounts[*accountID]; exists {
+		return account.Currency
+	}
+	return ""
+}
+
+// notifyAlert reports a risky transaction through the notification service
+func (s *FraudService) notifyAlert(transaction *domaintransaction.Transaction, score *domainfraud.Score) {
+	if s.notificationService == nil {
+		return
+	}
+
+	reasons := make([]string, len(score.Reasons))
+	for i, reason := range score.Reasons {
+		reasons[i] = reason.Detail
+	}
+
+	err := s.notificationService.NotifyFraudAlert(clients.FraudAlert{
+		UserID:        transaction.UserID,
+		TransactionID: transaction.ID,
+		Description:   transaction.Description,
+		MerchantName:  transaction.MerchantName,
+		Amount:        transaction.Amount,
+		Currency:      transaction.Currency,
+		Score:         score.Score,
+		Action:        string(score.Action),
+		Reasons:       reasons,
+		CreatedAt:     score.CreatedAt,
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to notify risk score of transaction %s: %v\n", transaction.ID, err)
+	}
+}
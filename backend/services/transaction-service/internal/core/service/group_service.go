@@ -0,0 +1,488 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	domaingroup "github.com/fintrack/transaction-service/internal/core/domain/entities/group"
+)
+
+const (
+	// groupExpenseListLimit is the number of expenses listed when the request does not set one
+	groupExpenseListLimit = 50
+	// groupExpenseListMax bounds the expenses listed at once
+	groupExpenseListMax = 500
+)
+
+// GroupService implements GroupServiceInterface
+// Keeps the expenses a group of users shares, the balance of each member and the payments that settle
+// them. Settling up is a transfer between users; an expense that comes from a transaction of the payer
+// splits that transaction so the shares of the others do not count as spending of the payer.
+type GroupService struct {
+	groupRepo       GroupRepositoryInterface
+	transactionRepo TransactionRepositoryInterface
+	splitService    SplitServiceInterface
+	p2pService      P2PServiceInterface
+}
+
+// NewGroupService creates a new shared expense group service
+func NewGroupService(
+	groupRepo GroupRepositoryInterface,
+	transactionRepo TransactionRepositoryInterface,
+	splitService SplitServiceInterface,
+	p2pService P2PServiceInterface,
+) GroupServiceInterface {
+	return &GroupService{
+		groupRepo:       groupRepo,
+		transactionRepo: transactionRepo,
+		splitService:    splitService,
+		p2pService:      p2pService,
+	}
+}
+
+// CreateGroup creates a group owned by the user with the other members found by email or alias
+func (s *GroupService) CreateGroup(userID string, request CreateGroupRequest) (*domaingroup.Group, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if len(name) > 100 {
+		return nil, errors.New("name cannot exceed 100 characters")
+	}
+	currency := strings.ToUpper(strings.TrimSpace(request.Currency))
+	if len(currency) != 3 {
+		return nil, errors.New("currency must be a 3-letter code")
+	}
+	if len(request.Members)+1 > domaingroup.MaxMembers {
+		return nil, fmt.Errorf("a group cannot have more than %d members", domaingroup.MaxMembers)
+	}
+
+	now := time.Now()
+	group := &domaingroup.Group{
+		ID:        fmt.Sprintf("grp_%d", now.UnixNano()),
+		Name:      name,
+		Currency:  currency,
+		CreatedBy: userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	group.Members = append(group.Members, &domaingroup.Member{
+		GroupID: group.ID, UserID: userID, Role: domaingroup.MemberRoleOwner, JoinedAt: now,
+	})
+
+	for _, identifier := range request.Members {
+		party, err := s.p2pService.FindRecipient(userID, identifier)
+		if err != nil {
+			return nil, err
+		}
+		if group.IsMember(party.UserID) {
+			continue
+		}
+		group.Members = append(group.Members, &domaingroup.Member{
+			GroupID: group.ID, UserID: party.UserID, Role: domaingroup.MemberRoleMember, JoinedAt: now,
+		})
+	}
+
+	if err := s.groupRepo.CreateGroup(group); err != nil {
+		return nil, err
+	}
+	return s.groupRepo.GetGroup(group.ID)
+}
+
+// GetGroups returns the groups the user is a member of
+func (s *GroupService) GetGroups(userID string) ([]*domaingroup.Group, error) {
+	return s.groupRepo.GetGroupsByUserID(userID)
+}
+
+// GetGroup returns a group of the user with its members
+func (s *GroupService) GetGroup(userID string, groupID string) (*domaingroup.Group, error) {
+	group, err := s.groupRepo.GetGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	if !group.IsMember(userID) {
+		return nil, fmt.Errorf("group not found with ID: %s", groupID)
+	}
+	return group, nil
+}
+
+// AddMember adds a user found by email or alias to a group the user owns
+func (s *GroupService) AddMember(userID string, groupID string, request AddGroupMemberRequest) (*domaingroup.Group, error) {
+	group, err := s.GetGroup(userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Member(userID).Role != domaingroup.MemberRoleOwner {
+		return nil, errors.New("unauthorized: only the group owner can add members")
+	}
+	if len(group.Members) >= domaingroup.MaxMembers {
+		return nil, fmt.Errorf("a group cannot have more than %d members", domaingroup.MaxMembers)
+	}
+
+	party, err := s.p2pService.FindRecipient(userID, request.Member)
+	if err != nil {
+		return nil, err
+	}
+	if group.IsMember(party.UserID) {
+		return nil, errors.New("user is already a member of the group")
+	}
+
+	member := &domaingroup.Member{
+		GroupID:  group.ID,
+		UserID:   party.UserID,
+		Role:     domaingroup.MemberRoleMember,
+		JoinedAt: time.Now(),
+	}
+	if err := s.groupRepo.AddMember(member); err != nil {
+		return nil, err
+	}
+	return s.groupRepo.GetGroup(group.ID)
+}
+
+// RemoveMember removes a member with nothing owed in either direction. The owner removes anyone but
+// themselves; members can only leave.
+func (s *GroupService) RemoveMember(userID string, groupID string, memberID string) (*domaingroup.Group, error) {
+	group, err := s.GetGroup(userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	member := group.Member(memberID)
+	if member == nil {
+		return nil, fmt.Errorf("member not found with ID: %s", memberID)
+	}
+	if memberID != userID && group.Member(userID).Role != domaingroup.MemberRoleOwner {
+		return nil, errors.New("unauthorized: only the group owner can remove other members")
+	}
+	if member.Role == domaingroup.MemberRoleOwner {
+		return nil, errors.New("the group owner cannot leave the group")
+	}
+
+	balances, err := s.GetBalances(userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, balance := range balances.Balances {
+		if balance.UserID == memberID && balance.Net != 0 {
+			return nil, fmt.Errorf("member has a balance of %.2f %s and has to settle up first", balance.Net, group.Currency)
+		}
+	}
+
+	if err := s.groupRepo.RemoveMember(groupID, memberID); err != nil {
+		return nil, err
+	}
+	if memberID == userID {
+		return group, nil
+	}
+	return s.groupRepo.GetGroup(groupID)
+}
+
+// AddExpense records an expense paid by a member and splits it among the members chosen
+func (s *GroupService) AddExpense(userID string, groupID string, request GroupExpenseRequest) (*domaingroup.Expense, error) {
+	group, err := s.GetGroup(userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	paidBy := request.PaidBy
+	if paidBy == "" {
+		paidBy = userID
+	}
+	if !group.IsMember(paidBy) {
+		return nil, fmt.Errorf("payer %s is not a member of the group", paidBy)
+	}
+
+	amount := math.Round(request.Amount*100) / 100
+	description := strings.TrimSpace(request.Description)
+	if request.TransactionID != nil && *request.TransactionID != "" {
+		if paidBy != userID {
+			return nil, errors.New("only the payer can add an expense from their transaction")
+		}
+		transaction, err := s.transactionRepo.GetByID(*request.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		if transaction.UserID != userID {
+			return nil, fmt.Errorf("transaction not found with ID: %s", *request.TransactionID)
+		}
+		if transaction.Currency != group.Currency {
+			return nil, fmt.Errorf("transaction is in %s but the group uses %s", transaction.Currency, group.Currency)
+		}
+		if amount == 0 {
+			amount = transaction.Amount
+		}
+		if math.Abs(amount-transaction.Amount) > 0.005 {
+			return nil, fmt.Errorf("expense amount %.2f differs from the transaction amount %.2f", amount, transaction.Amount)
+		}
+		if description == "" {
+			description = transaction.Description
+		}
+	}
+	if description == "" {
+		return nil, errors.New("description is required")
+	}
+	if len(description) > 255 {
+		return nil, errors.New("description cannot exceed 255 characters")
+	}
+
+	method := request.SplitMethod
+	if method == "" {
+		method = domaingroup.SplitEqual
+	}
+	participants := request.Shares
+	if len(participants) == 0 {
+		if method != domaingroup.SplitEqual {
+			return nil, fmt.Errorf("a %s split needs the shares of the participants", method)
+		}
+		for _, member := range group.Members {
+			participants = append(participants, domaingroup.ShareInput{UserID: member.UserID})
+		}
+	}
+	for _, participant := range participants {
+		if !group.IsMember(participant.UserID) {
+			return nil, fmt.Errorf("participant %s is not a member of the group", participant.UserID)
+		}
+	}
+
+	shares, err := domaingroup.ComputeShares(method, amount, participants)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expense := &domaingroup.Expense{
+		ID:            fmt.Sprintf("gexp_%d", now.UnixNano()),
+		GroupID:       group.ID,
+		PaidBy:        paidBy,
+		Amount:        amount,
+		Description:   description,
+		SplitMethod:   method,
+		TransactionID: request.TransactionID,
+		ExpenseDate:   now,
+		CreatedBy:     userID,
+		CreatedAt:     now,
+		Shares:        shares,
+	}
+	if request.ExpenseDate != nil {
+		expense.ExpenseDate = *request.ExpenseDate
+	}
+	if expense.TransactionID != nil && *expense.TransactionID == "" {
+		expense.TransactionID = nil
+	}
+	for _, share := range expense.Shares {
+		share.ExpenseID = expense.ID
+	}
+
+	if err := s.groupRepo.CreateExpense(expense); err != nil {
+		return nil, err
+	}
+
+	if expense.TransactionID != nil {
+		if err := s.splitTransaction(group, expense); err != nil {
+			if deleteErr := s.groupRepo.DeleteExpense(expense.ID); deleteErr != nil {
+				fmt.Printf("Warning: Failed to delete expense %s after its split failed: %v\n", expense.ID, deleteErr)
+			}
+			return nil, err
+		}
+	}
+
+	return expense, nil
+}
+
+// GetExpenses lists the expenses of a group of the user, latest first
+func (s *GroupService) GetExpenses(userID string, groupID string, limit int) ([]*domaingroup.Expense, error) {
+	if _, err := s.GetGroup(userID, groupID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = groupExpenseListLimit
+	}
+	if limit > groupExpenseListMax {
+		limit = groupExpenseListMax
+	}
+	return s.groupRepo.GetExpenses(groupID, limit)
+}
+
+// DeleteExpense deletes an expense the user added or paid, or any one for the group owner. The split of
+// the transaction it came from is removed.
+func (s *GroupService) DeleteExpense(userID string, groupID string, expenseID string) error {
+	group, err := s.GetGroup(userID, groupID)
+	if err != nil {
+		return err
+	}
+	expense, err := s.groupRepo.GetExpense(expenseID)
+	if err != nil {
+		return err
+	}
+	if expense.GroupID != group.ID {
+		return fmt.Errorf("expense not found with ID: %s", expenseID)
+	}
+	if expense.CreatedBy != userID && expense.PaidBy != userID && group.Member(userID).Role != domaingroup.MemberRoleOwner {
+		return errors.New("unauthorized: only who added or paid the expense can delete it")
+	}
+
+	if err := s.groupRepo.DeleteExpense(expenseID); err != nil {
+		return err
+	}
+
+	if expense.TransactionID != nil {
+		if err := s.splitService.RemoveSplit(expense.PaidBy, *expense.TransactionID); err != nil {
+			fmt.Printf("Warning: Failed to remove the split of transaction %s: %v\n", *expense.TransactionID, err)
+		}
+	}
+	return nil
+}
+
+// GetBalances returns where each member of a group of the user stands
+func (s *GroupService) GetBalances(userID string, groupID string) (*GroupBalancesResponse, error) {
+	group, err := s.GetGroup(userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.groupRepo.GetExpenses(groupID, 0)
+	if err != nil {
+		return nil, err
+	}
+	settlements, err := s.groupRepo.GetSettlements(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := domaingroup.ComputeBalances(group.Members, expenses, settlements)
+	return &GroupBalancesResponse{
+		GroupID:  group.ID,
+		Currency: group.Currency,
+		Balances: balances,
+		Debts:    domaingroup.SimplifyDebts(balances),
+	}, nil
+}
+
+// SettleUp sends the money the user owes to a member of the group as a transfer between users. The
+// settlement stops counting if the member declines the transfer or it expires.
+func (s *GroupService) SettleUp(userID string, groupID string, request SettleUpRequest) (*domaingroup.Settlement, error) {
+	group, err := s.GetGroup(userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	recipient := group.Member(request.ToUserID)
+	if recipient == nil {
+		return nil, fmt.Errorf("member not found with ID: %s", request.ToUserID)
+	}
+	if recipient.UserID == userID {
+		return nil, errors.New("cannot settle up with yourself")
+	}
+	if request.FromAccountID == "" {
+		return nil, errors.New("fromAccountId is required")
+	}
+
+	balances, err := s.GetBalances(userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	var owed, owedToRecipient, suggested float64
+	for _, balance := range balances.Balances {
+		switch balance.UserID {
+		case userID:
+			owed = -balance.Net
+		case recipient.UserID:
+			owedToRecipient = balance.Net
+		}
+	}
+	for _, debt := range balances.Debts {
+		if debt.FromUserID == userID && debt.ToUserID == recipient.UserID {
+			suggested = debt.Amount
+		}
+	}
+	if owed <= 0 {
+		return nil, errors.New("you do not owe money in this group")
+	}
+	if owedToRecipient <= 0 {
+		return nil, errors.New("the member is not owed money in this group")
+	}
+
+	amount := math.Round(request.Amount*100) / 100
+	if amount == 0 {
+		if suggested == 0 {
+			return nil, errors.New("amount is required: the simplified payments do not include this member")
+		}
+		amount = suggested
+	}
+	if amount < 0 {
+		return nil, errors.New("amount must be greater than 0")
+	}
+	if limit := math.Min(owed, owedToRecipient); amount > limit+0.005 {
+		return nil, fmt.Errorf("amount cannot exceed %.2f %s", limit, group.Currency)
+	}
+
+	transfer, err := s.p2pService.SendMoney(userID, SendMoneyRequest{
+		To:            recipient.Email,
+		FromAccountID: request.FromAccountID,
+		Amount:        amount,
+		Currency:      group.Currency,
+		Description:   "Settle up: " + group.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	settlement := &domaingroup.Settlement{
+		ID:             fmt.Sprintf("gset_%d", time.Now().UnixNano()),
+		GroupID:        group.ID,
+		FromUserID:     userID,
+		ToUserID:       recipient.UserID,
+		Amount:         amount,
+		TransferID:     transfer.ID,
+		TransferStatus: string(transfer.Status),
+		CreatedBy:      userID,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.groupRepo.CreateSettlement(settlement); err != nil {
+		return nil, err
+	}
+	return settlement, nil
+}
+
+// GetSettlements lists the settlements of a group of the user, latest first
+func (s *GroupService) GetSettlements(userID string, groupID string) ([]*domaingroup.Settlement, error) {
+	if _, err := s.GetGroup(userID, groupID); err != nil {
+		return nil, err
+	}
+	return s.groupRepo.GetSettlements(groupID)
+}
+
+// splitTransaction splits the transaction of the payer into the shares of the expense, the shares of the
+// other members with them as counterparts. A transaction already split is left as it is.
+func (s *GroupService) splitTransaction(group *domaingroup.Group, expense *domaingroup.Expense) error {
+	current, err := s.splitService.GetSplit(expense.PaidBy, *expense.TransactionID)
+	if err != nil {
+		return err
+	}
+	if len(current.Allocations) > 0 {
+		return errors.New("transaction is already split, remove its split to add it to a group")
+	}
+
+	allocations := make([]AllocationRequest, 0, len(expense.Shares))
+	for _, share := range expense.Shares {
+		if share.Amount == 0 {
+			continue
+		}
+		allocation := AllocationRequest{Amount: share.Amount, Note: group.Name}
+		if share.UserID != expense.PaidBy {
+			counterpart := share.UserID
+			allocation.CounterpartUserID = &counterpart
+		}
+		allocations = append(allocations, allocation)
+	}
+	if len(allocations) < 2 {
+		// The payer covered only themselves, or a single other member: nothing to split
+		return nil
+	}
+
+	if _, err := s.splitService.SplitTransaction(expense.PaidBy, *expense.TransactionID, SplitTransactionRequest{Allocations: allocations}); err != nil {
+		return fmt.Errorf("could not split the transaction: %w", err)
+	}
+	return nil
+}
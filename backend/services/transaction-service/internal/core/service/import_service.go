@@ -0,0 +1,525 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	domainstatement "github.com/fintrack/transaction-service/internal/core/domain/entities/statement"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+const (
+	// importMaxRows bounds the movements of a single statement file
+	importMaxRows = 5000
+	// importHistoryLimit bounds the imports listed for a user
+	importHistoryLimit = 100
+	// importDefaultCurrency is used when neither the request nor the file tell the currency
+	importDefaultCurrency = "ARS"
+)
+
+// ImportService implements ImportServiceInterface
+// Imports bank statements as completed record-only transactions: the balances already reflect them
+type ImportService struct {
+	transactionRepo TransactionRepositoryInterface
+	batchRepo       ImportBatchRepositoryInterface
+	mappingRepo     CSVMappingRepositoryInterface
+	categoryService CategoryServiceInterface
+}
+
+// NewImportService creates a new import service
+func NewImportService(
+	transactionRepo TransactionRepositoryInterface,
+	batchRepo ImportBatchRepositoryInterface,
+	mappingRepo CSVMappingRepositoryInterface,
+	categoryService CategoryServiceInterface,
+) ImportServiceInterface {
+	return &ImportService{
+		transactionRepo: transactionRepo,
+		batchRepo:       batchRepo,
+		mappingRepo:     mappingRepo,
+		categoryService: categoryService,
+	}
+}
+
+// GetFormats lists the generic formats and the registered bank parsers
+func (s *ImportService) GetFormats() *ImportFormats {
+	return &ImportFormats{
+		Formats:     []string{domainstatement.FormatCSV, domainstatement.FormatOFX, domainstatement.FormatQIF},
+		BankParsers: domainstatement.BankParserNames(),
+	}
+}
+
+// PreviewImport reads the file and flags duplicates without creating anything
+func (s *ImportService) PreviewImport(userID string, request ImportRequest) (*ImportPreview, error) {
+	format, currency, rows, err := s.readStatement(userID, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ImportPreview{
+		Format:    format,
+		Currency:  currency,
+		Rows:      rows,
+		TotalRows: len(rows),
+	}
+	for _, row := range rows {
+		switch row.Status {
+		case domainstatement.RowStatusDuplicate:
+			preview.DuplicateCount++
+		case domainstatement.RowStatusInvalid:
+			preview.InvalidCount++
+		}
+		if !willImport(row, request.IncludeDuplicates) {
+			continue
+		}
+		preview.NewCount++
+		if row.IsDebit() {
+			preview.TotalDebits += row.AbsAmount()
+		} else {
+			preview.TotalCredits += row.AbsAmount()
+		}
+	}
+	preview.TotalDebits = math.Round(preview.TotalDebits*100) / 100
+	preview.TotalCredits = math.Round(preview.TotalCredits*100) / 100
+	preview.FromDate, preview.ToDate = rowsDateRange(rows)
+
+	return preview, nil
+}
+
+// Import creates the transactions of the statement in one go and records the import so it can be rolled back
+func (s *ImportService) Import(userID string, request ImportRequest) (*ImportResult, error) {
+	format, currency, rows, err := s.readStatement(userID, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDate, toDate := rowsDateRange(rows)
+	batch, err := s.batchRepo.Create(&domainstatement.ImportBatch{
+		UserID:    userID,
+		Format:    format,
+		FileName:  request.FileName,
+		AccountID: request.AccountID,
+		CardID:    request.CardID,
+		Currency:  currency,
+		Status:    domainstatement.BatchStatusProcessing,
+		TotalRows: len(rows),
+		FromDate:  fromDate,
+		ToDate:    toDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create import batch: %w", err)
+	}
+
+	var transactions []*domaintransaction.Transaction
+	var importedRows []*domainstatement.ImportRow
+	for _, row := range rows {
+		if !willImport(row, request.IncludeDuplicates) {
+			continue
+		}
+
+		transaction := buildImportedTransaction(userID, request, batch, row)
+		if err := transaction.Validate(); err != nil {
+			row.Status = domainstatement.RowStatusInvalid
+			row.Error = err.Error()
+			continue
+		}
+		if err := s.categoryService.CategorizeTransaction(transaction); err != nil {
+			fmt.Printf("Warning: Failed to categorize imported row %d of batch %s: %v\n", row.RowNumber, batch.ID, err)
+		}
+
+		transactions = append(transactions, transaction)
+		importedRows = append(importedRows, row)
+	}
+
+	if len(transactions) > 0 {
+		created, err := s.transactionRepo.CreateBatch(transactions)
+		if err != nil {
+			if statusErr := s.batchRepo.UpdateStatus(batch.ID, domainstatement.BatchStatusFailed); statusErr != nil {
+				fmt.Printf("Warning: Failed to mark import batch %s as failed: %v\n", batch.ID, statusErr)
+			}
+			return nil, fmt.Errorf("failed to import transactions: %w", err)
+		}
+		for i, transaction := range created {
+			id := transaction.ID
+			importedRows[i].Status = domainstatement.RowStatusImported
+			importedRows[i].TransactionID = &id
+		}
+	}
+
+	for _, row := range rows {
+		switch row.Status {
+		case domainstatement.RowStatusImported:
+			batch.ImportedCount++
+		case domainstatement.RowStatusDuplicate:
+			batch.DuplicateCount++
+		case domainstatement.RowStatusInvalid:
+			batch.InvalidCount++
+		}
+	}
+	batch.Status = domainstatement.BatchStatusCompleted
+
+	if err := s.batchRepo.Complete(batch, rows); err != nil {
+		return nil, fmt.Errorf("failed to save import batch %s: %w", batch.ID, err)
+	}
+
+	return &ImportResult{Batch: batch, Rows: rows}, nil
+}
+
+// GetBatches returns the latest imports of the user
+func (s *ImportService) GetBatches(userID string) ([]*domainstatement.ImportBatch, error) {
+	batches, err := s.batchRepo.GetByUserID(userID, importHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import batches: %w", err)
+	}
+	return batches, nil
+}
+
+// GetBatch returns an import of the user with its rows
+func (s *ImportService) GetBatch(userID string, batchID string) (*ImportResult, error) {
+	batch, err := s.getOwnBatch(userID, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.batchRepo.GetRows(batch.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import rows: %w", err)
+	}
+
+	return &ImportResult{Batch: batch, Rows: rows}, nil
+}
+
+// RollbackBatch deletes every transaction created by an import of the user
+func (s *ImportService) RollbackBatch(userID string, batchID string) (*RollbackImportResult, error) {
+	batch, err := s.getOwnBatch(userID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if !batch.CanBeRolledBack() {
+		return nil, errors.New("import batch is already rolled back")
+	}
+
+	deleted, err := s.batchRepo.Rollback(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll back import batch: %w", err)
+	}
+
+	batch, err = s.batchRepo.GetByID(batch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RollbackImportResult{Batch: batch, DeletedTransactions: deleted}, nil
+}
+
+// GetMappings returns the saved CSV column mappings of the user
+func (s *ImportService) GetMappings(userID string) ([]*domainstatement.CSVMapping, error) {
+	mappings, err := s.mappingRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+// CreateMapping saves a CSV column mapping for the user
+func (s *ImportService) CreateMapping(userID string, mapping domainstatement.CSVMapping) (*domainstatement.CSVMapping, error) {
+	mapping.Name = strings.TrimSpace(mapping.Name)
+	if err := mapping.ValidateSaved(); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUniqueMappingName(userID, "", mapping.Name); err != nil {
+		return nil, err
+	}
+
+	mapping.ID = ""
+	mapping.UserID = userID
+	created, err := s.mappingRepo.Create(&mapping)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create column mapping: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateMapping replaces a saved CSV column mapping of the user
+func (s *ImportService) UpdateMapping(userID string, mappingID string, mapping domainstatement.CSVMapping) (*domainstatement.CSVMapping, error) {
+	existing, err := s.getOwnMapping(userID, mappingID)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping.Name = strings.TrimSpace(mapping.Name)
+	if err := mapping.ValidateSaved(); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUniqueMappingName(userID, existing.ID, mapping.Name); err != nil {
+		return nil, err
+	}
+
+	mapping.ID = existing.ID
+	mapping.UserID = userID
+	updated, err := s.mappingRepo.Update(&mapping)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update column mapping: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteMapping deletes a saved CSV column mapping of the user
+func (s *ImportService) DeleteMapping(userID string, mappingID string) error {
+	mapping, err := s.getOwnMapping(userID, mappingID)
+	if err != nil {
+		return err
+	}
+	return s.mappingRepo.Delete(mapping.ID)
+}
+
+// readStatement parses the file of the request into rows, flagging the invalid lines and the movements
+// already tracked in the account or card. It returns the format and currency used.
+func (s *ImportService) readStatement(userID string, request *ImportRequest) (string, string, []*domainstatement.ImportRow, error) {
+	if (request.AccountID == nil) == (request.CardID == nil) {
+		return "", "", nil, errors.New("a statement is imported into either an account or a card")
+	}
+	if len(request.Content) == 0 {
+		return "", "", nil, errors.New("the statement file is empty")
+	}
+
+	mapping := request.Mapping
+	if request.MappingID != nil && *request.MappingID != "" {
+		saved, err := s.getOwnMapping(userID, *request.MappingID)
+		if err != nil {
+			return "", "", nil, err
+		}
+		mapping = saved
+	}
+
+	format := strings.ToLower(strings.TrimSpace(request.Format))
+	if format == "" && mapping != nil {
+		format = domainstatement.FormatCSV
+	}
+	if format == "" {
+		return "", "", nil, errors.New("format is required")
+	}
+
+	parser, err := domainstatement.NewParser(format, mapping)
+	if err != nil {
+		return "", "", nil, err
+	}
+	result, err := parser.Parse(request.Content)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid statement file: %w", err)
+	}
+	if len(result.Records)+len(result.Errors) > importMaxRows {
+		return "", "", nil, fmt.Errorf("a statement cannot have more than %d movements", importMaxRows)
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(request.Currency))
+	if currency == "" {
+		currency = result.Currency
+	}
+	if currency == "" {
+		currency = importDefaultCurrency
+	}
+	if len(currency) != 3 {
+		return "", "", nil, errors.New("currency must be a 3 letter code")
+	}
+
+	rows := make([]*domainstatement.ImportRow, 0, len(result.Records)+len(result.Errors))
+	for _, record := range result.Records {
+		if record.Currency == "" {
+			record.Currency = currency
+		}
+		rows = append(rows, &domainstatement.ImportRow{Record: record, Status: domainstatement.RowStatusNew})
+	}
+	for _, rowError := range result.Errors {
+		rows = append(rows, &domainstatement.ImportRow{
+			Record: domainstatement.Record{RowNumber: rowError.RowNumber},
+			Status: domainstatement.RowStatusInvalid,
+			Error:  rowError.Message,
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].RowNumber < rows[j].RowNumber })
+
+	tracked, err := s.trackedMovements(userID, request, rows)
+	if err != nil {
+		return "", "", nil, err
+	}
+	domainstatement.MarkDuplicates(rows, tracked)
+
+	return format, currency, rows, nil
+}
+
+// trackedMovements returns the transactions of the account or card around the dates of the statement,
+// signed like statement records
+func (s *ImportService) trackedMovements(userID string, request *ImportRequest, rows []*domainstatement.ImportRow) ([]domainstatement.Movement, error) {
+	fromDate, toDate := rowsDateRange(rows)
+	if fromDate == nil {
+		return nil, nil
+	}
+	from := fromDate.AddDate(0, 0, -1)
+	to := toDate.AddDate(0, 0, 2)
+
+	transactions, _, err := s.transactionRepo.GetByUserID(userID, TransactionFilters{
+		Statuses: []domaintransaction.TransactionStatus{domaintransaction.TransactionStatusCompleted, domaintransaction.TransactionStatusPending},
+		FromDate: &from,
+		ToDate:   &to,
+		Limit:    importMaxRows * 2,
+		OrderBy:  "created_at",
+		Order:    "asc",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	var movements []domainstatement.Movement
+	for _, transaction := range transactions {
+		amount, ok := signedAmount(transaction, request.AccountID, request.CardID)
+		if !ok {
+			continue
+		}
+		movements = append(movements, domainstatement.Movement{
+			TransactionID: transaction.ID,
+			Date:          transaction.CreatedAt,
+			Amount:        amount,
+			ExternalID:    transaction.ExternalID,
+		})
+	}
+	return movements, nil
+}
+
+func (s *ImportService) getOwnBatch(userID string, batchID string) (*domainstatement.ImportBatch, error) {
+	batch, err := s.batchRepo.GetByID(batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.UserID != userID {
+		return nil, fmt.Errorf("import batch not found with ID: %s", batchID)
+	}
+	return batch, nil
+}
+
+func (s *ImportService) getOwnMapping(userID string, mappingID string) (*domainstatement.CSVMapping, error) {
+	mapping, err := s.mappingRepo.GetByID(mappingID)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.UserID != userID {
+		return nil, fmt.Errorf("column mapping not found with ID: %s", mappingID)
+	}
+	return mapping, nil
+}
+
+func (s *ImportService) ensureUniqueMappingName(userID string, mappingID string, name string) error {
+	mappings, err := s.mappingRepo.GetByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get column mappings: %w", err)
+	}
+	for _, existing := range mappings {
+		if existing.ID != mappingID && strings.EqualFold(existing.Name, name) {
+			return fmt.Errorf("a column mapping named %q already exists", existing.Name)
+		}
+	}
+	return nil
+}
+
+// willImport checks if a row becomes a transaction
+func willImport(row *domainstatement.ImportRow, includeDuplicates bool) bool {
+	return row.Status == domainstatement.RowStatusNew ||
+		(includeDuplicates && row.Status == domainstatement.RowStatusDuplicate)
+}
+
+// buildImportedTransaction turns a statement row into a completed record-only transaction dated when the
+// bank posted it. Debits of an account are withdrawals and credits deposits; on a credit card they are
+// charges and payments.
+func buildImportedTransaction(userID string, request ImportRequest, batch *domainstatement.ImportBatch, row *domainstatement.ImportRow) *domaintransaction.Transaction {
+	postedAt := row.PostedAt
+	description := row.Description
+	if description == "" {
+		description = row.Merchant
+	}
+
+	transaction := &domaintransaction.Transaction{
+		Status:       domaintransaction.TransactionStatusCompleted,
+		Amount:       math.Round(row.AbsAmount()*100) / 100,
+		Currency:     row.Currency,
+		UserID:       userID,
+		InitiatedBy:  userID,
+		Description:  description,
+		MerchantName: row.Merchant,
+		ExternalID:   truncate(row.ExternalID, 100),
+		ProcessedAt:  &postedAt,
+		CreatedAt:    postedAt,
+		Metadata: map[string]interface{}{
+			"recordOnly":    true,
+			"importBatchId": batch.ID,
+			"importFormat":  batch.Format,
+			"importRow":     row.RowNumber,
+		},
+	}
+
+	switch {
+	case request.CardID != nil && row.IsDebit():
+		transaction.Type = domaintransaction.TransactionTypeCreditCharge
+		transaction.FromCardID = request.CardID
+		transaction.PaymentMethod = domaintransaction.PaymentMethodCreditCard
+	case request.CardID != nil:
+		transaction.Type = domaintransaction.TransactionTypeCreditPayment
+		transaction.ToCardID = request.CardID
+		transaction.PaymentMethod = domaintransaction.PaymentMethodBankTransfer
+	case row.IsDebit():
+		transaction.Type = domaintransaction.TransactionTypeAccountWithdraw
+		transaction.FromAccountID = request.AccountID
+		transaction.PaymentMethod = domaintransaction.PaymentMethodBankTransfer
+	default:
+		transaction.Type = domaintransaction.TransactionTypeAccountDeposit
+		transaction.ToAccountID = request.AccountID
+		transaction.PaymentMethod = domaintransaction.PaymentMethodBankTransfer
+	}
+
+	return transaction
+}
+
+// signedAmount returns the amount of a transaction as seen by the account or card: negative when the
+// money left it. ok is false when the transaction does not move money of the account or card.
+func signedAmount(transaction *domaintransaction.Transaction, accountID, cardID *string) (float64, bool) {
+	switch {
+	case accountID != nil && transaction.FromAccountID != nil && *transaction.FromAccountID == *accountID:
+		return -transaction.Amount, true
+	case accountID != nil && transaction.ToAccountID != nil && *transaction.ToAccountID == *accountID:
+		return transaction.Amount, true
+	case cardID != nil && transaction.FromCardID != nil && *transaction.FromCardID == *cardID:
+		return -transaction.Amount, true
+	case cardID != nil && transaction.ToCardID != nil && *transaction.ToCardID == *cardID:
+		return transaction.Amount, true
+	default:
+		return 0, false
+	}
+}
+
+// rowsDateRange returns the first and last dates of the valid rows
+func rowsDateRange(rows []*domainstatement.ImportRow) (*time.Time, *time.Time) {
+	var from, to *time.Time
+	for _, row := range rows {
+		if row.PostedAt.IsZero() {
+			continue
+		}
+		date := row.PostedAt
+		if from == nil || date.Before(*from) {
+			from = &date
+		}
+		if to == nil || date.After(*to) {
+			to = &date
+		}
+	}
+	return from, to
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length]
+}
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	domainstatement "github.com/fintrack/transaction-service/internal/core/domain/entities/statement"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// MockTransactionRepository keeps the transactions of the import tests in memory
+type MockTransactionRepository struct {
+	TransactionRepositoryInterface
+	transactions []*domaintransaction.Transaction
+	nextID       int
+}
+
+func (m *MockTransactionRepository) GetByUserID(userID string, filters TransactionFilters) ([]*domaintransaction.Transaction, int, error) {
+	var transactions []*domaintransaction.Transaction
+	for _, transaction := range m.transactions {
+		if transaction.UserID != userID {
+			continue
+		}
+		if filters.FromDate != nil && transaction.CreatedAt.Before(*filters.FromDate) {
+			continue
+		}
+		if filters.ToDate != nil && transaction.CreatedAt.After(*filters.ToDate) {
+			continue
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, len(transactions), nil
+}
+
+func (m *MockTransactionRepository) GetByID(id string) (*domaintransaction.Transaction, error) {
+	for _, transaction := range m.transactions {
+		if transaction.ID == id {
+			return transaction, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction not found with ID: %s", id)
+}
+
+func (m *MockTransactionRepository) CreateBatch(transactions []*domaintransaction.Transaction) ([]*domaintransaction.Transaction, error) {
+	for _, transaction := range transactions {
+		m.nextID++
+		transaction.ID = fmt.Sprintf("tx-%d", m.nextID)
+		m.transactions = append(m.transactions, transaction)
+	}
+	return transactions, nil
+}
+
+// deleteImported removes the transactions created by an import, like the rollback of the MySQL repository
+func (m *MockTransactionRepository) deleteImported(batchID string) int {
+	var kept []*domaintransaction.Transaction
+	deleted := 0
+	for _, transaction := range m.transactions {
+		if transaction.Metadata["importBatchId"] == batchID {
+			deleted++
+			continue
+		}
+		kept = append(kept, transaction)
+	}
+	m.transactions = kept
+	return deleted
+}
+
+// MockImportBatchRepository keeps the imports in memory and rolls them back on the mock transactions
+type MockImportBatchRepository struct {
+	transactions *MockTransactionRepository
+	batches      map[string]*domainstatement.ImportBatch
+	rows         map[string][]*domainstatement.ImportRow
+}
+
+func (m *MockImportBatchRepository) Create(batch *domainstatement.ImportBatch) (*domainstatement.ImportBatch, error) {
+	batch.ID = fmt.Sprintf("batch-%d", len(m.batches)+1)
+	m.batches[batch.ID] = batch
+	return batch, nil
+}
+
+func (m *MockImportBatchRepository) Complete(batch *domainstatement.ImportBatch, rows []*domainstatement.ImportRow) error {
+	m.batches[batch.ID] = batch
+	m.rows[batch.ID] = rows
+	return nil
+}
+
+func (m *MockImportBatchRepository) UpdateStatus(batchID string, status domainstatement.BatchStatus) error {
+	m.batches[batchID].Status = status
+	return nil
+}
+
+func (m *MockImportBatchRepository) GetByID(id string) (*domainstatement.ImportBatch, error) {
+	batch, exists := m.batches[id]
+	if !exists {
+		return nil, fmt.Errorf("import batch not found with ID: %s", id)
+	}
+	return batch, nil
+}
+
+func (m *MockImportBatchRepository) GetByUserID(userID string, limit int) ([]*domainstatement.ImportBatch, error) {
+	var batches []*domainstatement.ImportBatch
+	for _, batch := range m.batches {
+		if batch.UserID == userID {
+			batches = append(batches, batch)
+		}
+	}
+	return batches, nil
+}
+
+func (m *MockImportBatchRepository) GetRows(batchID string) ([]*domainstatement.ImportRow, error) {
+	return m.rows[batchID], nil
+}
+
+func (m *MockImportBatchRepository) Rollback(batch *domainstatement.ImportBatch) (int, error) {
+	deleted := m.transactions.deleteImported(batch.ID)
+	m.batches[batch.ID].Status = domainstatement.BatchStatusRolledBack
+	return deleted, nil
+}
+
+// MockCategoryService leaves the imported transactions uncategorized
+type MockCategoryService struct {
+	CategoryServiceInterface
+	categorized int
+}
+
+func (m *MockCategoryService) CategorizeTransaction(transaction *domaintransaction.Transaction) error {
+	m.categorized++
+	return nil
+}
+
+// Verify interface compliance
+var _ ImportBatchRepositoryInterface = (*MockImportBatchRepository)(nil)
+
+const importTestUserID = "user-1"
+
+// galiciaImportStatement is a Galicia export of March 2025 with a preamble and separate debit and credit columns
+const galiciaImportStatement = `Banco Galicia - Movimientos de cuenta
+Cuenta: CA $ 4023-1234567-8
+
+Fecha;Descripción;Débitos;Créditos;Saldo;Número de Comprobante
+03/03/2025;Compra con débito SUPERMERCADO DIA;12.345,67;;87.654,33;000123
+05/03/2025;Transferencia recibida;;150.000,00;237.654,33;000124
+10/03/2025;Pago de servicios EDENOR;1.234,50;;236.419,83;000125
+12/03/2025;Movimiento sin fecha válida;;abc;;000126
+`
+
+type importFixture struct {
+	service      ImportServiceInterface
+	transactions *MockTransactionRepository
+	batches      *MockImportBatchRepository
+	accountID    string
+}
+
+// setupImportService creates the import service with the EDENOR payment of the statement already tracked
+func setupImportService() *importFixture {
+	accountID := "account-galicia"
+	transactions := &MockTransactionRepository{}
+	transactions.transactions = append(transactions.transactions, &domaintransaction.Transaction{
+		ID:            "tracked-edenor",
+		UserID:        importTestUserID,
+		Type:          domaintransaction.TransactionTypeAccountWithdraw,
+		Status:        domaintransaction.TransactionStatusCompleted,
+		Amount:        1234.50,
+		Currency:      "ARS",
+		FromAccountID: &accountID,
+		CreatedAt:     time.Date(2025, 3, 10, 16, 30, 0, 0, time.Local),
+	})
+	batches := &MockImportBatchRepository{
+		transactions: transactions,
+		batches:      make(map[string]*domainstatement.ImportBatch),
+		rows:         make(map[string][]*domainstatement.ImportRow),
+	}
+
+	return &importFixture{
+		service:      NewImportService(transactions, batches, nil, &MockCategoryService{}),
+		transactions: transactions,
+		batches:      batches,
+		accountID:    accountID,
+	}
+}
+
+func (f *importFixture) request(includeDuplicates bool) ImportRequest {
+	return ImportRequest{
+		Format:            "galicia",
+		FileName:          "movimientos-marzo.csv",
+		Content:           []byte(galiciaImportStatement),
+		AccountID:         &f.accountID,
+		IncludeDuplicates: includeDuplicates,
+	}
+}
+
+func rowStatuses(rows []*domainstatement.ImportRow) string {
+	statuses := make([]string, len(rows))
+	for i, row := range rows {
+		statuses[i] = string(row.Status)
+	}
+	return strings.Join(statuses, ",")
+}
+
+func TestPreviewImport(t *testing.T) {
+	fixture := setupImportService()
+
+	preview, err := fixture.service.PreviewImport(importTestUserID, fixture.request(false))
+	if err != nil {
+		t.Fatalf("PreviewImport() unexpected error: %v", err)
+	}
+
+	if preview.TotalRows != 4 || preview.NewCount != 2 || preview.DuplicateCount != 1 || preview.InvalidCount != 1 {
+		t.Errorf("PreviewImport() counts = %d total, %d new, %d duplicate, %d invalid, want 4, 2, 1, 1",
+			preview.TotalRows, preview.NewCount, preview.DuplicateCount, preview.InvalidCount)
+	}
+	if preview.TotalDebits != 12345.67 || preview.TotalCredits != 150000 {
+		t.Errorf("PreviewImport() totals = %v debits, %v credits, want 12345.67, 150000", preview.TotalDebits, preview.TotalCredits)
+	}
+	if preview.Currency != "ARS" {
+		t.Errorf("PreviewImport() currency = %s, want ARS", preview.Currency)
+	}
+	if duplicateOf := preview.Rows[2].DuplicateOfID; duplicateOf == nil || *duplicateOf != "tracked-edenor" {
+		t.Errorf("PreviewImport() row 3 duplicate of %v, want tracked-edenor", duplicateOf)
+	}
+	if len(fixture.transactions.transactions) != 1 || len(fixture.batches.batches) != 0 {
+		t.Error("PreviewImport() must not create transactions or imports")
+	}
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name              string
+		includeDuplicates bool
+		expectedStatuses  string
+		expectedImported  int
+		expectedDuplicate int
+	}{
+		{
+			name:              "duplicates are skipped",
+			expectedStatuses:  "imported,imported,duplicate,invalid",
+			expectedImported:  2,
+			expectedDuplicate: 1,
+		},
+		{
+			name:              "duplicates are imported on request",
+			includeDuplicates: true,
+			expectedStatuses:  "imported,imported,imported,invalid",
+			expectedImported:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := setupImportService()
+
+			result, err := fixture.service.Import(importTestUserID, fixture.request(tt.includeDuplicates))
+			if err != nil {
+				t.Fatalf("Import() unexpected error: %v", err)
+			}
+
+			if got := rowStatuses(result.Rows); got != tt.expectedStatuses {
+				t.Errorf("Import() rows = %s, want %s", got, tt.expectedStatuses)
+			}
+			batch := result.Batch
+			if batch.Status != domainstatement.BatchStatusCompleted || batch.ImportedCount != tt.expectedImported ||
+				batch.DuplicateCount != tt.expectedDuplicate || batch.InvalidCount != 1 {
+				t.Errorf("Import() batch = %s with %d imported, %d duplicate, %d invalid", batch.Status, batch.ImportedCount, batch.DuplicateCount, batch.InvalidCount)
+			}
+			if got := len(fixture.transactions.transactions); got != tt.expectedImported+1 {
+				t.Errorf("Import() transactions = %d, want %d", got, tt.expectedImported+1)
+			}
+
+			// The first row is a debit of the account dated when the bank posted it
+			transaction := fixture.transactions.transactions[1]
+			if transaction.Type != domaintransaction.TransactionTypeAccountWithdraw || transaction.Amount != 12345.67 ||
+				transaction.FromAccountID == nil || *transaction.FromAccountID != fixture.accountID {
+				t.Errorf("Import() first transaction = %s of %v", transaction.Type, transaction.Amount)
+			}
+			if !transaction.CreatedAt.Equal(time.Date(2025, 3, 3, 0, 0, 0, 0, time.Local)) || transaction.ExternalID != "000123" {
+				t.Errorf("Import() first transaction dated %v with reference %q", transaction.CreatedAt, transaction.ExternalID)
+			}
+			if transaction.Metadata["importBatchId"] != batch.ID {
+				t.Errorf("Import() first transaction batch = %v, want %s", transaction.Metadata["importBatchId"], batch.ID)
+			}
+		})
+	}
+}
+
+func TestImportTwiceFlagsEveryRowAsDuplicate(t *testing.T) {
+	fixture := setupImportService()
+	if _, err := fixture.service.Import(importTestUserID, fixture.request(false)); err != nil {
+		t.Fatalf("Import() unexpected error: %v", err)
+	}
+
+	result, err := fixture.service.Import(importTestUserID, fixture.request(false))
+	if err != nil {
+		t.Fatalf("Import() unexpected error: %v", err)
+	}
+	if got := rowStatuses(result.Rows); got != "duplicate,duplicate,duplicate,invalid" {
+		t.Errorf("Import() rows = %s, want every valid row duplicate", got)
+	}
+	if len(fixture.transactions.transactions) != 3 {
+		t.Errorf("Import() transactions = %d, want 3", len(fixture.transactions.transactions))
+	}
+}
+
+func TestRollbackBatch(t *testing.T) {
+	fixture := setupImportService()
+	imported, err := fixture.service.Import(importTestUserID, fixture.request(false))
+	if err != nil {
+		t.Fatalf("Import() unexpected error: %v", err)
+	}
+
+	if _, err := fixture.service.RollbackBatch("user-2", imported.Batch.ID); err == nil {
+		t.Error("RollbackBatch() of another user expected error but got none")
+	}
+
+	result, err := fixture.service.RollbackBatch(importTestUserID, imported.Batch.ID)
+	if err != nil {
+		t.Fatalf("RollbackBatch() unexpected error: %v", err)
+	}
+	if result.DeletedTransactions != 2 || result.Batch.Status != domainstatement.BatchStatusRolledBack {
+		t.Errorf("RollbackBatch() = %d deleted, %s, want 2 deleted, rolled_back", result.DeletedTransactions, result.Batch.Status)
+	}
+	if len(fixture.transactions.transactions) != 1 || fixture.transactions.transactions[0].ID != "tracked-edenor" {
+		t.Error("RollbackBatch() must only delete the transactions of the import")
+	}
+
+	if _, err := fixture.service.RollbackBatch(importTestUserID, imported.Batch.ID); err == nil {
+		t.Error("RollbackBatch() twice expected error but got none")
+	}
+
+	// Once rolled back the rows are new again
+	reimported, err := fixture.service.Import(importTestUserID, fixture.request(false))
+	if err != nil {
+		t.Fatalf("Import() unexpected error: %v", err)
+	}
+	if reimported.Batch.ImportedCount != 2 || reimported.Batch.DuplicateCount != 1 {
+		t.Errorf("Import() after rollback = %d imported, %d duplicate, want 2, 1", reimported.Batch.ImportedCount, reimported.Batch.DuplicateCount)
+	}
+}
+
+func TestImportRequestValidation(t *testing.T) {
+	fixture := setupImportService()
+	cardID := "card-1"
+
+	tests := []struct {
+		name    string
+		request func() ImportRequest
+	}{
+		{"account and card", func() ImportRequest {
+			request := fixture.request(false)
+			request.CardID = &cardID
+			return request
+		}},
+		{"neither account nor card", func() ImportRequest {
+			request := fixture.request(false)
+			request.AccountID = nil
+			return request
+		}},
+		{"empty file", func() ImportRequest {
+			request := fixture.request(false)
+			request.Content = nil
+			return request
+		}},
+		{"unknown format", func() ImportRequest {
+			request := fixture.request(false)
+			request.Format = "xlsx"
+			return request
+		}},
+		{"invalid currency", func() ImportRequest {
+			request := fixture.request(false)
+			request.Currency = "PESOS"
+			return request
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fixture.service.Import(importTestUserID, tt.request()); err == nil {
+				t.Error("Import() expected error but got none")
+			}
+		})
+	}
+	if len(fixture.batches.batches) != 0 {
+		t.Error("Import() must not record rejected requests")
+	}
+}
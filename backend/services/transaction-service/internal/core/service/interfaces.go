@@ -1,8 +1,20 @@
 package service
 
 import (
+	"io"
 	"time"
 
+	domainattachment "github.com/fintrack/transaction-service/internal/core/domain/entities/attachment"
+	domainbudget "github.com/fintrack/transaction-service/internal/core/domain/entities/budget"
+	domaincategory "github.com/fintrack/transaction-service/internal/core/domain/entities/category"
+	domainduplicate "github.com/fintrack/transaction-service/internal/core/domain/entities/duplicate"
+	domainfraud "github.com/fintrack/transaction-service/internal/core/domain/entities/fraud"
+	domaingroup "github.com/fintrack/transaction-service/internal/core/domain/entities/group"
+	domainp2p "github.com/fintrack/transaction-service/internal/core/domain/entities/p2p"
+	domainreconciliation "github.com/fintrack/transaction-service/internal/core/domain/entities/reconciliation"
+	domainschedule "github.com/fintrack/transaction-service/internal/core/domain/entities/schedule"
+	domainstatement "github.com/fintrack/transaction-service/internal/core/domain/entities/statement"
+	domainsubscription "github.com/fintrack/transaction-service/internal/core/domain/entities/subscription"
 	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
 )
 
@@ -21,6 +33,8 @@ type TransactionServiceInterface interface {
 	FailTransaction(id string, reason string, failedBy string) error
 	CancelTransaction(id string, reason string, canceledBy string) error
 	ReverseTransaction(id string, reason string, reversedBy string) (*domaintransaction.Transaction, error)
+	// ReleaseHeldTransaction executes a transaction held by the fraud checks once the user confirms it
+	ReleaseHeldTransaction(id string, userID string) (*domaintransaction.Transaction, error)
 
 	// Balance and account operations
 	ProcessWalletDeposit(userID string, accountID string, amount float64, description string, initiatedBy string) (*domaintransaction.Transaction, error)
@@ -48,6 +62,197 @@ type TransactionRuleServiceInterface interface {
 	DeleteRule(ruleID string, deletedBy string) error
 }
 
+// CategoryServiceInterface defines the contract for transaction categories and auto-categorization rules
+type CategoryServiceInterface interface {
+	// Category tree (default categories plus the user's custom ones)
+	GetCategoryTree(userID string) ([]*domaincategory.CategoryNode, error)
+	CreateCategory(userID string, request CategoryRequest) (*domaincategory.Category, error)
+	UpdateCategory(userID string, categoryID string, request CategoryRequest) (*domaincategory.Category, error)
+	DeleteCategory(userID string, categoryID string) error
+
+	// Auto-categorization rules
+	GetRules(userID string) ([]*domaincategory.CategorizationRule, error)
+	CreateRule(userID string, request CategorizationRuleRequest) (*domaincategory.CategorizationRule, error)
+	UpdateRule(userID string, ruleID string, request CategorizationRuleRequest) (*domaincategory.CategorizationRule, error)
+	DeleteRule(userID string, ruleID string) error
+
+	// Categorization of transactions
+	CategorizeTransaction(transaction *domaintransaction.Transaction) error
+	RecategorizeTransaction(userID string, transactionID string, request RecategorizeRequest) (*RecategorizeResult, error)
+	ReapplyRules(userID string, request ReapplyRulesRequest) (*ReapplyRulesResult, error)
+}
+
+// SplitServiceInterface defines the contract for splitting transactions across categories or people
+type SplitServiceInterface interface {
+	GetSplit(userID string, transactionID string) (*SplitResult, error)
+	SplitTransaction(userID string, transactionID string, request SplitTransactionRequest) (*SplitResult, error)
+	RemoveSplit(userID string, transactionID string) error
+}
+
+// ScheduleServiceInterface defines the contract for recurring and future-dated transactions
+type ScheduleServiceInterface interface {
+	CreateSchedule(userID string, request ScheduledTransactionRequest) (*domainschedule.ScheduledTransaction, error)
+	GetSchedules(userID string, status *domainschedule.ScheduleStatus) ([]*domainschedule.ScheduledTransaction, error)
+	GetSchedule(userID string, scheduleID string) (*ScheduleDetail, error)
+	UpdateSchedule(userID string, scheduleID string, request ScheduledTransactionRequest) (*domainschedule.ScheduledTransaction, error)
+	CancelSchedule(userID string, scheduleID string) error
+	GetRuns(userID string, scheduleID string, limit int) ([]*domainschedule.ScheduledRun, error)
+
+	// Occurrence controls
+	PauseSchedule(userID string, scheduleID string) (*domainschedule.ScheduledTransaction, error)
+	ResumeSchedule(userID string, scheduleID string) (*domainschedule.ScheduledTransaction, error)
+	SkipNextOccurrence(userID string, scheduleID string) (*domainschedule.ScheduledTransaction, error)
+	EditNextOccurrence(userID string, scheduleID string, request NextOccurrenceRequest) (*domainschedule.ScheduledTransaction, error)
+
+	// RunDueSchedules materializes every occurrence due at the given time. It is called by the scheduler.
+	RunDueSchedules(now time.Time) (*ScheduleRunSummary, error)
+}
+
+// SubscriptionServiceInterface defines the contract for detecting subscriptions from the transaction history
+type SubscriptionServiceInterface interface {
+	// GetSubscriptions detects the subscriptions of the user and refreshes their snapshot
+	GetSubscriptions(userID string) (*SubscriptionReport, error)
+	// RefreshAll refreshes the snapshot of every user with recent charges. It is called by the scheduler.
+	RefreshAll(now time.Time) (int, error)
+}
+
+// ImportServiceInterface defines the contract for importing bank statements (CSV, OFX, QIF and bank exports)
+type ImportServiceInterface interface {
+	GetFormats() *ImportFormats
+	// PreviewImport reads the file and flags duplicates without creating anything (dry run)
+	PreviewImport(userID string, request ImportRequest) (*ImportPreview, error)
+	Import(userID string, request ImportRequest) (*ImportResult, error)
+	GetBatches(userID string) ([]*domainstatement.ImportBatch, error)
+	GetBatch(userID string, batchID string) (*ImportResult, error)
+	// RollbackBatch deletes every transaction created by an import
+	RollbackBatch(userID string, batchID string) (*RollbackImportResult, error)
+
+	// Saved CSV column mappings
+	GetMappings(userID string) ([]*domainstatement.CSVMapping, error)
+	CreateMapping(userID string, mapping domainstatement.CSVMapping) (*domainstatement.CSVMapping, error)
+	UpdateMapping(userID string, mappingID string, mapping domainstatement.CSVMapping) (*domainstatement.CSVMapping, error)
+	DeleteMapping(userID string, mappingID string) error
+}
+
+// ReconciliationServiceInterface defines the contract for reconciling bank statements with tracked transactions
+type ReconciliationServiceInterface interface {
+	CreateReconciliation(userID string, request ReconciliationRequest) (*ReconciliationDetail, error)
+	GetReconciliations(userID string, accountID, cardID *string) ([]*domainreconciliation.Reconciliation, error)
+	GetReconciliation(userID string, reconciliationID string) (*ReconciliationDetail, error)
+
+	// Resolving items
+	ConfirmItem(userID string, reconciliationID string, itemID string) (*ReconciliationDetail, error)
+	LinkItem(userID string, reconciliationID string, itemID string, transactionID string) (*ReconciliationDetail, error)
+	UnlinkItem(userID string, reconciliationID string, itemID string) (*ReconciliationDetail, error)
+
+	// GetStatus tells how much of an account or card is reconciled up to a date
+	GetStatus(userID string, request ReconciliationStatusRequest) (*ReconciliationStatus, error)
+}
+
+// ExportServiceInterface defines the contract for exporting transactions to CSV, OFX and JSON Lines
+type ExportServiceInterface interface {
+	// ExportTransactions writes the transactions matching the filters of the list, without pagination, as
+	// they are read. Nothing is written when it fails before the first transaction.
+	ExportTransactions(userID string, request ExportRequest, w io.Writer) error
+}
+
+// AttachmentServiceInterface defines the contract for receipts and invoices attached to transactions and
+// installment plans
+type AttachmentServiceInterface interface {
+	Upload(userID string, request UploadAttachmentRequest) (*domainattachment.Attachment, error)
+	GetAttachments(userID string, ownerType domainattachment.OwnerType, ownerID string) ([]*domainattachment.Attachment, error)
+	GetAttachment(userID string, attachmentID string) (*domainattachment.Attachment, error)
+	// OpenContent returns the attachment with a reader of its content, which the caller must close
+	OpenContent(userID string, attachmentID string) (*domainattachment.Attachment, io.ReadCloser, error)
+	DeleteAttachment(userID string, attachmentID string) error
+	GetUsage(userID string) (*domainattachment.Usage, error)
+	// PurgeOrphans deletes the attachments of purged transactions and installment plans. It is called by a job.
+	PurgeOrphans() (int, error)
+}
+
+// DuplicateServiceInterface defines the contract for finding and merging transactions recorded more than once
+type DuplicateServiceInterface interface {
+	// FindDuplicates returns the transactions that look like duplicates of a transaction, e.g. right after creating it
+	FindDuplicates(transaction *domaintransaction.Transaction) ([]*domainduplicate.Candidate, error)
+	ScanDuplicates(userID string, request DuplicateScanRequest) (*DuplicateScanResult, error)
+	// MergeDuplicates keeps one transaction, moves the references of the others to it and deletes them
+	MergeDuplicates(userID string, request MergeDuplicatesRequest) (*domaintransaction.Transaction, error)
+}
+
+// FraudServiceInterface defines the contract for the fraud and anomaly risk scoring of incoming transactions
+type FraudServiceInterface interface {
+	// ScoreTransaction scores a transaction before it is saved, against the history and the settings of its
+	// owner. It returns nil when the owner disabled the scoring.
+	ScoreTransaction(transaction *domaintransaction.Transaction) (*domainfraud.Score, error)
+	// RecordScore persists the score of a saved transaction and notifies the owner when it reaches the alert threshold
+	RecordScore(transaction *domaintransaction.Transaction, score *domainfraud.Score) error
+	GetSettings(userID string) (*domainfraud.Settings, error)
+	UpdateSettings(userID string, request FraudSettingsRequest) (*domainfraud.Settings, error)
+	GetTransactionScore(userID string, transactionID string) (*domainfraud.Score, error)
+	GetScores(userID string, request RiskScoreListRequest) ([]*domainfraud.Score, error)
+}
+
+// P2PServiceInterface defines the contract for transfers between FinTrack users found by email or alias
+type P2PServiceInterface interface {
+	GetProfile(userID string) (*domainp2p.Profile, error)
+	UpdateProfile(userID string, request P2PProfileRequest) (*domainp2p.Profile, error)
+	// FindRecipient resolves an email or alias to the user it belongs to, to confirm before sending
+	FindRecipient(userID string, identifier string) (*domainp2p.Party, error)
+	GetLimits(userID string, currency string) (*P2PLimitsResponse, error)
+	SendMoney(userID string, request SendMoneyRequest) (*domainp2p.Transfer, error)
+	RequestMoney(userID string, request RequestMoneyRequest) (*domainp2p.Transfer, error)
+	// AcceptTransfer completes a pending transfer: the recipient takes a sent one, or the sender pays a request
+	AcceptTransfer(userID string, transferID string, request RespondTransferRequest) (*domainp2p.Transfer, error)
+	DeclineTransfer(userID string, transferID string, request RespondTransferRequest) (*domainp2p.Transfer, error)
+	CancelTransfer(userID string, transferID string) (*domainp2p.Transfer, error)
+	GetTransfer(userID string, transferID string) (*domainp2p.Transfer, error)
+	ListTransfers(userID string, filters P2PTransferFilters) ([]*domainp2p.Transfer, error)
+	// ExpirePending expires the transfers nobody answered in time, refunding the sent ones. It is called by a job.
+	ExpirePending(now time.Time) (int, error)
+}
+
+// GroupServiceInterface defines the contract for groups of users sharing expenses and settling up between them
+type GroupServiceInterface interface {
+	CreateGroup(userID string, request CreateGroupRequest) (*domaingroup.Group, error)
+	GetGroups(userID string) ([]*domaingroup.Group, error)
+	GetGroup(userID string, groupID string) (*domaingroup.Group, error)
+	// AddMember adds the user with an email or alias; only the owner can add members
+	AddMember(userID string, groupID string, request AddGroupMemberRequest) (*domaingroup.Group, error)
+	// RemoveMember removes a member settled up in the group; the owner removes anyone, members leave
+	RemoveMember(userID string, groupID string, memberID string) (*domaingroup.Group, error)
+
+	AddExpense(userID string, groupID string, request GroupExpenseRequest) (*domaingroup.Expense, error)
+	GetExpenses(userID string, groupID string, limit int) ([]*domaingroup.Expense, error)
+	DeleteExpense(userID string, groupID string, expenseID string) error
+
+	// GetBalances returns where each member stands and the simplified payments that settle the group
+	GetBalances(userID string, groupID string) (*GroupBalancesResponse, error)
+	// SettleUp pays a member back through a transfer between users
+	SettleUp(userID string, groupID string, request SettleUpRequest) (*domaingroup.Settlement, error)
+	GetSettlements(userID string, groupID string) ([]*domaingroup.Settlement, error)
+}
+
+// BudgetServiceInterface defines the contract for monthly budgets and their progress against actual spending
+type BudgetServiceInterface interface {
+	CreateBudget(userID string, request BudgetRequest) (*domainbudget.Budget, error)
+	// GetBudgets returns the budgets of the user, only the ones that apply to a month when one is given
+	GetBudgets(userID string, month string) ([]*domainbudget.Budget, error)
+	GetBudget(userID string, budgetID string) (*domainbudget.Budget, error)
+	UpdateBudget(userID string, budgetID string, request BudgetRequest) (*domainbudget.Budget, error)
+	DeleteBudget(userID string, budgetID string) error
+
+	// GetProgress returns a budget against the actual spending of a month, the current one by default
+	GetProgress(userID string, budgetID string, month string) (*domainbudget.Period, error)
+	// GetHistory returns a budget against the actual spending of its last months, oldest first
+	GetHistory(userID string, budgetID string, months int) ([]*domainbudget.Period, error)
+	// GetSummary returns every budget of a month against the actual spending, with totals by currency
+	GetSummary(userID string, month string) (*BudgetSummary, error)
+
+	// RefreshAll recomputes the current month of every budget for reports and the chatbot, and sends the
+	// alerts of the thresholds reached. It is called by a job.
+	RefreshAll(now time.Time) (int, error)
+}
+
 // TransactionAuditServiceInterface defines the contract for audit operations
 // Separated for better adherence to Single Responsibility Principle (SRP)
 type TransactionAuditServiceInterface interface {
@@ -93,8 +298,10 @@ type CreateTransactionRequest struct {
 	PaymentMethod domaintransaction.PaymentMethod   `json:"paymentMethod"`
 	MerchantName  string                            `json:"merchantName"`
 	MerchantID    string                            `json:"merchantId"`
+	Notes         string                            `json:"notes"`
 	ReferenceID   string                            `json:"referenceId"`
 	ExternalID    string                            `json:"externalId"`
+	CategoryID    *string                           `json:"categoryId"` // Chosen by the user; otherwise categorization rules apply
 	Metadata      map[string]interface{}            `json:"metadata"`
 	Tags          []string                          `json:"tags"`
 }
@@ -112,6 +319,222 @@ type CreateRuleRequest struct {
 	EffectiveUntil   *time.Time                        `json:"effectiveUntil"`
 }
 
+// CategoryRequest represents the data of a custom category
+type CategoryRequest struct {
+	Name     string                      `json:"name"`
+	ParentID *string                     `json:"parentId"`
+	Kind     domaincategory.CategoryKind `json:"kind"` // Defaults to the parent kind, or expense
+	Icon     string                      `json:"icon"`
+}
+
+// CategorizationRuleRequest represents the data of an auto-categorization rule
+type CategorizationRuleRequest struct {
+	CategoryID       string   `json:"categoryId"`
+	Name             string   `json:"name"`
+	MerchantPattern  string   `json:"merchantPattern"`
+	DescriptionRegex string   `json:"descriptionRegex"`
+	MinAmount        *float64 `json:"minAmount"`
+	MaxAmount        *float64 `json:"maxAmount"`
+	CardID           *string  `json:"cardId"`
+	Priority         int      `json:"priority"`
+	IsActive         *bool    `json:"isActive"` // Defaults to true
+}
+
+// RecategorizeRequest represents a manual change of the category of a transaction
+type RecategorizeRequest struct {
+	CategoryID *string `json:"categoryId"` // Nil removes the category
+	CreateRule bool    `json:"createRule"` // Also categorize future transactions like this one
+}
+
+// RecategorizeResult is the recategorized transaction and the rule created from it, if any
+type RecategorizeResult struct {
+	Transaction *domaintransaction.Transaction     `json:"transaction"`
+	Rule        *domaincategory.CategorizationRule `json:"rule,omitempty"`
+}
+
+// ReapplyRulesRequest represents a bulk application of the categorization rules over past transactions
+type ReapplyRulesRequest struct {
+	FromDate        *time.Time `json:"fromDate"`
+	ToDate          *time.Time `json:"toDate"`
+	OverwriteManual bool       `json:"overwriteManual"` // Also recategorize transactions categorized by hand
+}
+
+// ReapplyRulesResult summarizes a bulk application of the categorization rules
+type ReapplyRulesResult struct {
+	Scanned       int `json:"scanned"`
+	Categorized   int `json:"categorized"`   // Transactions whose category changed
+	Uncategorized int `json:"uncategorized"` // Transactions left without category because no rule matches anymore
+}
+
+// AllocationRequest represents one part of a split transaction
+type AllocationRequest struct {
+	Amount            float64 `json:"amount"`
+	CategoryID        *string `json:"categoryId"` // Empty keeps the category of the transaction
+	Note              string  `json:"note"`
+	CounterpartUserID *string `json:"counterpartUserId"` // Person this part belongs to, if it is not the owner
+}
+
+// SplitTransactionRequest replaces the allocations of a transaction
+type SplitTransactionRequest struct {
+	Allocations []AllocationRequest `json:"allocations"`
+}
+
+// SplitResult is a transaction with its allocations (empty when it is not split)
+type SplitResult struct {
+	Transaction *domaintransaction.Transaction  `json:"transaction"`
+	Allocations []*domaintransaction.Allocation `json:"allocations"`
+}
+
+// ScheduledTransactionRequest creates or updates a scheduled transaction
+type ScheduledTransactionRequest struct {
+	Type          domaintransaction.TransactionType `json:"type"`
+	Amount        float64                           `json:"amount"`
+	Currency      string                            `json:"currency"`
+	FromAccountID *string                           `json:"fromAccountId"`
+	ToAccountID   *string                           `json:"toAccountId"`
+	FromCardID    *string                           `json:"fromCardId"`
+	ToCardID      *string                           `json:"toCardId"`
+	Description   string                            `json:"description"`
+	MerchantName  string                            `json:"merchantName"`
+	CategoryID    *string                           `json:"categoryId"`
+	Recurrence    string                            `json:"recurrence"` // RRULE, e.g. FREQ=MONTHLY;BYMONTHDAY=5. Empty for a one-off transaction
+	StartAt       time.Time                         `json:"startAt"`    // First occurrence, or the date of a one-off transaction
+}
+
+// NextOccurrenceRequest changes only the next occurrence of a schedule
+type NextOccurrenceRequest struct {
+	Amount      *float64   `json:"amount"`
+	Description *string    `json:"description"`
+	RunAt       *time.Time `json:"runAt"` // Moves the next occurrence, before the one that follows it
+}
+
+// ScheduleDetail is a scheduled transaction with its upcoming occurrences and latest runs
+type ScheduleDetail struct {
+	Schedule   *domainschedule.ScheduledTransaction `json:"schedule"`
+	Upcoming   []time.Time                          `json:"upcoming"`
+	RecentRuns []*domainschedule.ScheduledRun       `json:"recentRuns"`
+}
+
+// ScheduleRunSummary summarizes one pass of the scheduler
+type ScheduleRunSummary struct {
+	Due      int `json:"due"`
+	Executed int `json:"executed"`
+	Failed   int `json:"failed"`
+	Paused   int `json:"paused"` // Schedules paused after too many consecutive failures
+}
+
+// SubscriptionReport lists the detected subscriptions of a user with their total cost
+type SubscriptionReport struct {
+	Subscriptions  []*domainsubscription.Subscription `json:"subscriptions"`
+	ActiveCount    int                                `json:"activeCount"`
+	MissedCount    int                                `json:"missedCount"`
+	CancelledCount int                                `json:"cancelledCount"`
+	PriceIncreases int                                `json:"priceIncreases"` // Subscriptions whose last price change was an increase
+	MonthlyCost    map[string]float64                 `json:"monthlyCost"`    // By currency, active and missed subscriptions only
+	AnnualizedCost map[string]float64                 `json:"annualizedCost"` // By currency, active and missed subscriptions only
+	GeneratedAt    time.Time                          `json:"generatedAt"`
+}
+
+// ImportRequest is a statement file to import into an account or a credit card
+type ImportRequest struct {
+	Format            string                      `json:"format"` // csv, ofx, qif or a bank parser; csv when a mapping is given
+	FileName          string                      `json:"fileName"`
+	Content           []byte                      `json:"-"`
+	MappingID         *string                     `json:"mappingId"` // Saved CSV mapping of the user
+	Mapping           *domainstatement.CSVMapping `json:"mapping"`   // Or a mapping for this file only
+	AccountID         *string                     `json:"accountId"`
+	CardID            *string                     `json:"cardId"`
+	Currency          string                      `json:"currency"`          // When the file does not tell it, ARS by default
+	IncludeDuplicates bool                        `json:"includeDuplicates"` // Import the rows that match tracked transactions too
+}
+
+// ImportFormats lists the formats the importer reads
+type ImportFormats struct {
+	Formats     []string `json:"formats"`
+	BankParsers []string `json:"bankParsers"`
+}
+
+// ImportPreview is the result of reading a statement without importing it
+type ImportPreview struct {
+	Format         string                       `json:"format"`
+	Currency       string                       `json:"currency"`
+	Rows           []*domainstatement.ImportRow `json:"rows"`
+	TotalRows      int                          `json:"totalRows"`
+	NewCount       int                          `json:"newCount"`
+	DuplicateCount int                          `json:"duplicateCount"`
+	InvalidCount   int                          `json:"invalidCount"`
+	TotalDebits    float64                      `json:"totalDebits"`  // Of the rows to import
+	TotalCredits   float64                      `json:"totalCredits"` // Of the rows to import
+	FromDate       *time.Time                   `json:"fromDate"`
+	ToDate         *time.Time                   `json:"toDate"`
+}
+
+// ImportResult is an import with what happened to each row
+type ImportResult struct {
+	Batch *domainstatement.ImportBatch `json:"batch"`
+	Rows  []*domainstatement.ImportRow `json:"rows"`
+}
+
+// RollbackImportResult is the outcome of rolling back an import
+type RollbackImportResult struct {
+	Batch               *domainstatement.ImportBatch `json:"batch"`
+	DeletedTransactions int                          `json:"deletedTransactions"`
+}
+
+// ReconciliationRequest starts the reconciliation of a statement: the rows of an import or an uploaded file
+type ReconciliationRequest struct {
+	ImportBatchID   *string        `json:"importBatchId"`
+	Statement       *ImportRequest `json:"-"`               // Uploaded statement, not imported
+	DateWindowDays  *int           `json:"dateWindowDays"`  // 3 days by default
+	AmountTolerance *float64       `json:"amountTolerance"` // Same cents by default
+}
+
+// ReconciliationDetail is a reconciliation with its items by date
+type ReconciliationDetail struct {
+	Reconciliation *domainreconciliation.Reconciliation `json:"reconciliation"`
+	Items          []*domainreconciliation.Item         `json:"items"`
+}
+
+// LinkReconciliationItemRequest links a statement movement with a tracked transaction
+type LinkReconciliationItemRequest struct {
+	TransactionID string `json:"transactionId"`
+}
+
+// ReconciliationStatusRequest asks for the reconciliation status of an account or card
+type ReconciliationStatusRequest struct {
+	AccountID *string   `json:"accountId"`
+	CardID    *string   `json:"cardId"`
+	AsOf      time.Time `json:"asOf"` // Today when zero
+}
+
+// ReconciliationStatus tells how much of an account or card is reconciled up to a date
+type ReconciliationStatus struct {
+	AccountID                *string                      `json:"accountId"`
+	CardID                   *string                      `json:"cardId"`
+	AsOf                     time.Time                    `json:"asOf"`
+	IsReconciled             bool                         `json:"isReconciled"`
+	ReconciledThrough        *time.Time                   `json:"reconciledThrough"` // End of the latest completed reconciliation
+	LastStatementDate        *time.Time                   `json:"lastStatementDate"`
+	Reconciliations          int                          `json:"reconciliations"`
+	TotalTransactions        int                          `json:"totalTransactions"`
+	ReconciledTransactions   int                          `json:"reconciledTransactions"`
+	UnreconciledTransactions int                          `json:"unreconciledTransactions"`
+	UnreconciledAmount       float64                      `json:"unreconciledAmount"` // Signed like the statement
+	PendingMatches           int                          `json:"pendingMatches"`
+	MissingInFinTrack        int                          `json:"missingInFinTrack"`
+	MissingInBank            int                          `json:"missingInBank"`
+	Unreconciled             []*ReconciliationStatusEntry `json:"unreconciled"`
+	OpenItems                []*domainreconciliation.Item `json:"openItems"` // Pending matches and statement movements missing in FinTrack
+}
+
+// ReconciliationStatusEntry is a tracked transaction not reconciled with any statement
+type ReconciliationStatusEntry struct {
+	TransactionID string    `json:"transactionId"`
+	Date          time.Time `json:"date"`
+	Amount        float64   `json:"amount"`
+	Description   string    `json:"description"`
+}
+
 // TransactionFilters represents filters for querying transactions
 type TransactionFilters struct {
 	Types         []domaintransaction.TransactionType   `json:"types"`
@@ -124,12 +547,25 @@ type TransactionFilters struct {
 	CardID        *string                               `json:"cardId"`
 	MerchantName  *string                               `json:"merchantName"`
 	PaymentMethod *domaintransaction.PaymentMethod      `json:"paymentMethod"`
+	CategoryID    *string                               `json:"categoryId"`    // Includes its subcategories
+	Uncategorized bool                                  `json:"uncategorized"` // Only transactions without category
+	Search        string                                `json:"search"`        // Free text over description, merchant, notes and tags
+	Tags          []string                              `json:"tags"`          // Transactions with all of these tags
+	Currencies    []string                              `json:"currencies"`
+	AmountSign    string                                `json:"amountSign"` // AmountSignNegative or AmountSignPositive
 	Limit         int                                   `json:"limit"`
 	Offset        int                                   `json:"offset"`
+	Cursor        *domaintransaction.Cursor             `json:"cursor"` // Keyset pagination, the offset is ignored when set
 	OrderBy       string                                `json:"orderBy"`
 	Order         string                                `json:"order"`
 }
 
+// Amount signs of the transaction filters
+const (
+	AmountSignNegative = "negative" // Money out of the user, account or card listed
+	AmountSignPositive = "positive" // Money into the user, account or card listed
+)
+
 // Response DTOs for service operations
 
 // AuditEntry represents an audit log entry
@@ -164,3 +600,212 @@ type UserLimits struct {
 	SingleTransactionLimit  float64 `json:"singleTransactionLimit"`
 	RequiresApprovalAbove   float64 `json:"requiresApprovalAbove"`
 }
+
+// ExportRequest selects the format and the transactions of an export
+type ExportRequest struct {
+	Format  string             `json:"format"` // csv, ofx or jsonl
+	Filters TransactionFilters `json:"filters"`
+}
+
+// UploadAttachmentRequest is a file to attach to a transaction or an installment plan
+type UploadAttachmentRequest struct {
+	OwnerType domainattachment.OwnerType `json:"ownerType"`
+	OwnerID   string                     `json:"ownerId"`
+	FileName  string                     `json:"fileName"`
+	Size      int64                      `json:"size"`
+	Content   io.Reader                  `json:"-"`
+}
+
+// AttachmentLimits bound the size of each attachment and the storage of each user
+type AttachmentLimits struct {
+	MaxFileSize int64 `json:"maxFileSize"`
+	UserQuota   int64 `json:"userQuota"`
+}
+
+// DuplicateScanRequest selects the transactions scanned for duplicates
+type DuplicateScanRequest struct {
+	FromDate      *time.Time `json:"fromDate"`
+	ToDate        *time.Time `json:"toDate"`
+	WindowMinutes int        `json:"windowMinutes"` // How far apart duplicates can be created; 10 by default
+}
+
+// DuplicateScanResult lists the groups of transactions that look like duplicates
+type DuplicateScanResult struct {
+	FromDate time.Time                `json:"fromDate"`
+	ToDate   time.Time                `json:"toDate"`
+	Scanned  int                      `json:"scanned"`
+	Groups   []*domainduplicate.Group `json:"groups"`
+}
+
+// MergeDuplicatesRequest selects the transaction to keep and the duplicates merged into it
+type MergeDuplicatesRequest struct {
+	KeepID       string   `json:"keepId"`
+	DuplicateIDs []string `json:"duplicateIds"`
+	Reason       string   `json:"reason"`
+}
+
+// FraudSettingsRequest updates the risk scoring settings of a user; the fields left out keep their value
+type FraudSettingsRequest struct {
+	Enabled               *bool    `json:"enabled"`
+	AlertThreshold        *int     `json:"alertThreshold"` // 0 disables the level
+	HoldThreshold         *int     `json:"holdThreshold"`
+	BlockThreshold        *int     `json:"blockThreshold"`
+	AmountDeviation       *float64 `json:"amountDeviation"`
+	VelocityCount         *int     `json:"velocityCount"`
+	VelocityWindowMinutes *int     `json:"velocityWindowMinutes"`
+	QuietHoursStart       *int     `json:"quietHoursStart"`
+	QuietHoursEnd         *int     `json:"quietHoursEnd"`
+}
+
+// RiskScoreListRequest selects the risk scores listed, latest first
+type RiskScoreListRequest struct {
+	MinScore int                `json:"minScore"`
+	Action   domainfraud.Action `json:"action"`
+	Limit    int                `json:"limit"` // 50 by default
+}
+
+// P2PProfileRequest updates how a user is found and paid by other users; the fields left out keep their value
+type P2PProfileRequest struct {
+	Alias            *string  `json:"alias"`
+	DefaultAccountID *string  `json:"defaultAccountId"` // Empty to fall back to the oldest wallet
+	AutoAccept       *bool    `json:"autoAccept"`
+	DailyAmountLimit *float64 `json:"dailyAmountLimit"` // 0 removes the own limit
+	DailyCountLimit  *int     `json:"dailyCountLimit"`  // 0 removes the own limit
+}
+
+// P2PLimitsResponse shows the daily limits of transfers to other users in a currency and what is left of them
+type P2PLimitsResponse struct {
+	Currency        string           `json:"currency"`
+	Limits          domainp2p.Limits `json:"limits"`
+	Usage           domainp2p.Usage  `json:"usage"`
+	RemainingAmount float64          `json:"remainingAmount"`
+	RemainingCount  int              `json:"remainingCount"`
+}
+
+// SendMoneyRequest sends money to another user, who accepts it into one of their accounts
+type SendMoneyRequest struct {
+	To            string  `json:"to"` // Email or alias of the recipient
+	FromAccountID string  `json:"fromAccountId"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"` // Optional; the account has to be in it when set
+	Description   string  `json:"description"`
+}
+
+// RequestMoneyRequest asks another user for money, which they pay when they accept
+type RequestMoneyRequest struct {
+	From        string  `json:"from"`        // Email or alias of the user asked to pay
+	ToAccountID *string `json:"toAccountId"` // Where to receive it; the default account otherwise
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"` // The one of the receiving account by default
+	Description string  `json:"description"`
+}
+
+// RespondTransferRequest answers a pending transfer
+type RespondTransferRequest struct {
+	AccountID *string `json:"accountId"` // On accept: account to receive into, or to pay a request from
+	Reason    string  `json:"reason"`    // On decline
+}
+
+// P2PTransferFilters selects the transfers listed, latest first
+type P2PTransferFilters struct {
+	Direction string                   `json:"direction"` // P2PDirectionSent or P2PDirectionReceived, both by default
+	Status    domainp2p.TransferStatus `json:"status"`
+	Awaiting  bool                     `json:"awaiting"` // Only the pending ones the user has to answer
+	Limit     int                      `json:"limit"`    // 50 by default
+}
+
+// Directions of the transfer filters
+const (
+	P2PDirectionSent     = "sent"     // The user is the one the money comes from
+	P2PDirectionReceived = "received" // The user is the one the money goes to
+)
+
+// CreateGroupRequest creates a group with the creator as owner
+type CreateGroupRequest struct {
+	Name     string   `json:"name"`
+	Currency string   `json:"currency"`
+	Members  []string `json:"members"` // Emails or aliases of the other members
+}
+
+// AddGroupMemberRequest adds a user to a group
+type AddGroupMemberRequest struct {
+	Member string `json:"member"` // Email or alias
+}
+
+// GroupExpenseRequest adds an expense to a group
+type GroupExpenseRequest struct {
+	PaidBy        string                   `json:"paidBy"` // The user adding it by default
+	Amount        float64                  `json:"amount"`
+	Description   string                   `json:"description"`
+	SplitMethod   domaingroup.SplitMethod  `json:"splitMethod"` // equal by default
+	Shares        []domaingroup.ShareInput `json:"shares"`      // All the members, equally, by default
+	TransactionID *string                  `json:"transactionId"`
+	ExpenseDate   *time.Time               `json:"expenseDate"` // Now by default
+}
+
+// SettleUpRequest pays back a member of a group
+type SettleUpRequest struct {
+	ToUserID      string  `json:"toUserId"`
+	FromAccountID string  `json:"fromAccountId"`
+	Amount        float64 `json:"amount"` // What the user owes the member after simplifying by default
+}
+
+// GroupBalancesResponse is where each member of a group stands
+type GroupBalancesResponse struct {
+	GroupID  string                 `json:"groupId"`
+	Currency string                 `json:"currency"`
+	Balances []*domaingroup.Balance `json:"balances"`
+	Debts    []*domaingroup.Debt    `json:"debts"` // Fewest payments that settle the group
+}
+
+// BudgetRequest creates or replaces a budget
+type BudgetRequest struct {
+	Name          string                `json:"name"`
+	Scope         domainbudget.Scope    `json:"scope"`
+	CategoryID    *string               `json:"categoryId"`   // With the category scope; includes its subcategories
+	MerchantName  *string               `json:"merchantName"` // With the merchant scope
+	CardID        *string               `json:"cardId"`       // With the card scope
+	Amount        float64               `json:"amount"`
+	Currency      string                `json:"currency"`
+	Rollover      domainbudget.Rollover `json:"rollover"`      // none by default
+	StartMonth    string                `json:"startMonth"`    // YYYY-MM, the current month by default
+	EndMonth      *string               `json:"endMonth"`      // YYYY-MM, no end by default
+	AlertsEnabled *bool                 `json:"alertsEnabled"` // true by default
+}
+
+// BudgetProgress is a budget with its progress in a month
+type BudgetProgress struct {
+	Budget *domainbudget.Budget `json:"budget"`
+	Period *domainbudget.Period `json:"period"`
+}
+
+// BudgetTotals adds up the budgets of a month in one currency
+type BudgetTotals struct {
+	Currency  string  `json:"currency"`
+	Limit     float64 `json:"limit"`
+	Actual    float64 `json:"actual"`
+	Remaining float64 `json:"remaining"`
+	Exceeded  int     `json:"exceeded"` // Budgets past their limit
+}
+
+// BudgetSummary is budget versus actual for every budget of a month
+type BudgetSummary struct {
+	Month   string            `json:"month"`
+	Budgets []*BudgetProgress `json:"budgets"`
+	Totals  []*BudgetTotals   `json:"totals"` // One per currency; budgets of one scope can overlap others
+}
+
+// MembershipServiceInterface defines the contract for checking what the members of shared accounts can do
+type MembershipServiceInterface interface {
+	// VerifyInitiator checks that a user can perform a transaction on the account or card of the request.
+	// It returns ErrInitiatorNotAllowed when the user is not an active member allowed to transact.
+	VerifyInitiator(request CreateTransactionRequest, initiatedBy string) error
+}
+
+// AccountMembership is the role of a user in a shared account
+type AccountMembership struct {
+	AccountID string  `json:"accountId"`
+	UserID    string  `json:"userId"`
+	Role      string  `json:"role"`
+	CardID    *string `json:"cardId,omitempty"` // Card of a card holder
+}
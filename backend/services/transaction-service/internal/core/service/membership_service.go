@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Roles of the members of a shared account, as stored by the account service
+const (
+	memberRoleOwner      = "owner"
+	memberRoleCoOwner    = "co_owner"
+	memberRoleCardHolder = "card_holder"
+)
+
+// ErrInitiatorNotAllowed is returned when the user named as initiator cannot transact on the account or card
+var ErrInitiatorNotAllowed = errors.New("initiator is not allowed to transact on the account")
+
+// MembershipService implements MembershipServiceInterface
+// Owners and co-owners transact on the whole account; card holders only with their own card, and viewers never
+type MembershipService struct {
+	memberRepo AccountMemberRepositoryInterface
+}
+
+// NewMembershipService creates a new membership service
+func NewMembershipService(memberRepo AccountMemberRepositoryInterface) MembershipServiceInterface {
+	return &MembershipService{
+		memberRepo: memberRepo,
+	}
+}
+
+// VerifyInitiator checks that the initiator is an active member of the account of the transaction with a role
+// allowed to transact
+func (s *MembershipService) VerifyInitiator(request CreateTransactionRequest, initiatedBy string) error {
+	if initiatedBy == "" {
+		return ErrInitiatorNotAllowed
+	}
+
+	cardID := request.FromCardID
+	if cardID == nil {
+		cardID = request.ToCardID
+	}
+
+	var accountID string
+	switch {
+	case request.FromAccountID != nil:
+		accountID = *request.FromAccountID
+	case request.ToAccountID != nil:
+		accountID = *request.ToAccountID
+	case cardID != nil:
+		id, err := s.memberRepo.GetCardAccountID(*cardID)
+		if err != nil {
+			return fmt.Errorf("failed to get account of card: %w", err)
+		}
+		accountID = id
+	}
+	if accountID == "" {
+		return ErrInitiatorNotAllowed
+	}
+
+	membership, err := s.memberRepo.GetActiveMembership(accountID, initiatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to get membership: %w", err)
+	}
+	if membership == nil {
+		return ErrInitiatorNotAllowed
+	}
+
+	switch membership.Role {
+	case memberRoleOwner, memberRoleCoOwner:
+		return nil
+	case memberRoleCardHolder:
+		if membership.CardID != nil && cardID != nil && *membership.CardID == *cardID {
+			return nil
+		}
+	}
+	return ErrInitiatorNotAllowed
+}
@@ -0,0 +1,758 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	domainp2p "github.com/fintrack/transaction-service/internal/core/domain/entities/p2p"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// ErrAliasTaken is returned by the repository when another user already has the alias
+var ErrAliasTaken = errors.New("alias already taken")
+
+const (
+	// DefaultP2PDailyAmountLimit bounds what a user sends to other users per day and currency
+	DefaultP2PDailyAmountLimit = 500000.0
+	// DefaultP2PDailyCountLimit bounds the transfers a user sends to other users per day and currency
+	DefaultP2PDailyCountLimit = 20
+	// p2pTransferListLimit is the number of transfers listed when the filters do not set one
+	p2pTransferListLimit = 50
+	// p2pTransferListMax bounds the transfers listed at once
+	p2pTransferListMax = 500
+	// p2pExpiryBatchSize bounds the transfers expired in one run of the job
+	p2pExpiryBatchSize = 100
+)
+
+// P2PService implements P2PServiceInterface
+// Moves money between the accounts of two FinTrack users. The money leaves the sender when a transfer is
+// sent (or when a request is paid), waits until the other party accepts, and goes back to the sender when
+// the transfer is declined, cancelled or expires. Each side is a regular transaction of its owner.
+type P2PService struct {
+	p2pRepo            P2PRepositoryInterface
+	transactionService TransactionServiceInterface
+	limits             domainp2p.Limits
+	logger             *log.Logger // Warnings of the steps that cannot fail the request, e.g. in the expiry job
+}
+
+// NewP2PService creates a new peer-to-peer transfer service with the daily limits of the platform.
+// Zero limits take the defaults.
+func NewP2PService(
+	p2pRepo P2PRepositoryInterface,
+	transactionService TransactionServiceInterface,
+	limits domainp2p.Limits,
+) P2PServiceInterface {
+	if limits.DailyAmount <= 0 {
+		limits.DailyAmount = DefaultP2PDailyAmountLimit
+	}
+	if limits.DailyCount <= 0 {
+		limits.DailyCount = DefaultP2PDailyCountLimit
+	}
+
+	return &P2PService{
+		p2pRepo:            p2pRepo,
+		transactionService: transactionService,
+		limits:             limits,
+		logger:             log.Default(),
+	}
+}
+
+// GetProfile returns the profile of a user, an empty one until the user changes it
+func (s *P2PService) GetProfile(userID string) (*domainp2p.Profile, error) {
+	profile, err := s.p2pRepo.GetProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return &domainp2p.Profile{UserID: userID}, nil
+	}
+	return profile, nil
+}
+
+// UpdateProfile changes the fields of the profile of a user present in the request
+func (s *P2PService) UpdateProfile(userID string, request P2PProfileRequest) (*domainp2p.Profile, error) {
+	profile, err := s.GetProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Alias != nil {
+		alias := domainp2p.NormalizeAlias(*request.Alias)
+		if alias != "" {
+			if err := domainp2p.ValidateAlias(alias); err != nil {
+				return nil, err
+			}
+		}
+		profile.Alias = alias
+	}
+	if request.DefaultAccountID != nil {
+		if *request.DefaultAccountID == "" {
+			profile.DefaultAccountID = nil
+		} else {
+			account, err := s.ownAccount(userID, *request.DefaultAccountID)
+			if err != nil {
+				return nil, err
+			}
+			profile.DefaultAccountID = &account.ID
+		}
+	}
+	if request.AutoAccept != nil {
+		profile.AutoAccept = *request.AutoAccept
+	}
+	if request.DailyAmountLimit != nil {
+		if *request.DailyAmountLimit < 0 {
+			return nil, errors.New("daily amount limit cannot be negative")
+		}
+		profile.DailyAmountLimit = request.DailyAmountLimit
+		if *request.DailyAmountLimit == 0 {
+			profile.DailyAmountLimit = nil
+		}
+	}
+	if request.DailyCountLimit != nil {
+		if *request.DailyCountLimit < 0 {
+			return nil, errors.New("daily count limit cannot be negative")
+		}
+		profile.DailyCountLimit = request.DailyCountLimit
+		if *request.DailyCountLimit == 0 {
+			profile.DailyCountLimit = nil
+		}
+	}
+
+	if profile.AutoAccept && profile.DefaultAccountID == nil {
+		return nil, errors.New("a default account is required to accept transfers automatically")
+	}
+
+	if err := s.p2pRepo.SaveProfile(profile); err != nil {
+		if errors.Is(err, ErrAliasTaken) {
+			return nil, fmt.Errorf("alias %s is already taken", profile.Alias)
+		}
+		return nil, err
+	}
+	return s.GetProfile(userID)
+}
+
+// FindRecipient resolves an email or alias to the user it belongs to
+func (s *P2PService) FindRecipient(userID string, identifier string) (*domainp2p.Party, error) {
+	party, err := s.resolveParty(identifier)
+	if err != nil {
+		return nil, err
+	}
+	if party.UserID == userID {
+		return nil, errors.New("cannot transfer money to yourself")
+	}
+	return party, nil
+}
+
+// GetLimits returns the daily limits of a user in a currency and what is left of them today
+func (s *P2PService) GetLimits(userID string, currency string) (*P2PLimitsResponse, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return nil, errors.New("currency is required")
+	}
+
+	limits, usage, err := s.dailyUsage(userID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &P2PLimitsResponse{
+		Currency:        currency,
+		Limits:          limits,
+		Usage:           *usage,
+		RemainingAmount: limits.DailyAmount - usage.Amount,
+		RemainingCount:  limits.DailyCount - usage.Count,
+	}
+	if response.RemainingAmount < 0 {
+		response.RemainingAmount = 0
+	}
+	if response.RemainingCount < 0 {
+		response.RemainingCount = 0
+	}
+	return response, nil
+}
+
+// SendMoney debits the account of the user and leaves the money waiting for the recipient to accept it,
+// or credits it right away when the recipient accepts transfers automatically
+func (s *P2PService) SendMoney(userID string, request SendMoneyRequest) (*domainp2p.Transfer, error) {
+	if request.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if request.FromAccountID == "" {
+		return nil, errors.New("fromAccountId is required")
+	}
+
+	recipient, err := s.FindRecipient(userID, request.To)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.ownAccount(userID, request.FromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if currency := strings.ToUpper(strings.TrimSpace(request.Currency)); currency != "" && account.Currency != currency {
+		return nil, fmt.Errorf("account %s is in %s, not in %s", account.ID, account.Currency, currency)
+	}
+	limits, since, err := s.dailyLimits(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	transfer := &domainp2p.Transfer{
+		ID:              fmt.Sprintf("p2p_%d", now.UnixNano()),
+		Kind:            domainp2p.TransferKindSend,
+		SenderUserID:    userID,
+		RecipientUserID: recipient.UserID,
+		SenderAccountID: &account.ID,
+		Amount:          request.Amount,
+		Currency:        account.Currency,
+		Description:     strings.TrimSpace(request.Description),
+		// Processing until the money left the sender, so it cannot be accepted before
+		Status:    domainp2p.TransferStatusProcessing,
+		ExpiresAt: now.Add(domainp2p.DefaultExpiry),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	// The transfer reserves its amount of the daily limits as it is created
+	if err := s.p2pRepo.CreateWithinLimits(transfer, limits, since); err != nil {
+		return nil, err
+	}
+
+	if err := s.debit(transfer, account); err != nil {
+		s.fail(transfer, err.Error())
+		return nil, fmt.Errorf("transfer failed: %w", err)
+	}
+	transfer.Status = domainp2p.TransferStatusPending
+	transfer.UpdatedAt = time.Now()
+	if err := s.p2pRepo.Update(transfer); err != nil {
+		return nil, err
+	}
+
+	s.autoAccept(transfer)
+	return s.GetTransfer(userID, transfer.ID)
+}
+
+// RequestMoney asks another user for money, to be received into an account of the user once they pay it
+func (s *P2PService) RequestMoney(userID string, request RequestMoneyRequest) (*domainp2p.Transfer, error) {
+	if request.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+
+	payer, err := s.FindRecipient(userID, request.From)
+	if err != nil {
+		return nil, err
+	}
+	currency := strings.ToUpper(strings.TrimSpace(request.Currency))
+	account, err := s.receivingAccount(userID, request.ToAccountID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	transfer := &domainp2p.Transfer{
+		ID:                 fmt.Sprintf("p2p_%d", now.UnixNano()),
+		Kind:               domainp2p.TransferKindRequest,
+		SenderUserID:       payer.UserID,
+		RecipientUserID:    userID,
+		RecipientAccountID: &account.ID,
+		Amount:             request.Amount,
+		Currency:           account.Currency,
+		Description:        strings.TrimSpace(request.Description),
+		Status:             domainp2p.TransferStatusPending,
+		ExpiresAt:          now.Add(domainp2p.DefaultExpiry),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := s.p2pRepo.Create(transfer); err != nil {
+		return nil, err
+	}
+
+	return s.GetTransfer(userID, transfer.ID)
+}
+
+// AcceptTransfer completes a pending transfer. The recipient of a sent transfer receives it into the chosen
+// account or their default one; the user asked in a request pays it from the chosen account or their default one.
+func (s *P2PService) AcceptTransfer(userID string, transferID string, request RespondTransferRequest) (*domainp2p.Transfer, error) {
+	transfer, err := s.claimAnswer(userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.Kind == domainp2p.TransferKindRequest {
+		err = s.payRequest(transfer, request.AccountID)
+	} else {
+		var account *domainp2p.Account
+		account, err = s.receivingAccount(userID, request.AccountID, transfer.Currency)
+		if err == nil {
+			err = s.complete(transfer, account)
+		}
+	}
+	if err != nil {
+		s.release(transfer)
+		return nil, err
+	}
+
+	return s.GetTransfer(userID, transferID)
+}
+
+// DeclineTransfer rejects a pending transfer, refunding the sender of a sent one
+func (s *P2PService) DeclineTransfer(userID string, transferID string, request RespondTransferRequest) (*domainp2p.Transfer, error) {
+	transfer, err := s.claimAnswer(userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.close(transfer, domainp2p.TransferStatusDeclined, strings.TrimSpace(request.Reason)); err != nil {
+		return nil, err
+	}
+	return s.GetTransfer(userID, transferID)
+}
+
+// CancelTransfer withdraws a pending transfer the user started, refunding a sent one
+func (s *P2PService) CancelTransfer(userID string, transferID string) (*domainp2p.Transfer, error) {
+	transfer, err := s.getPending(userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.InitiatorID() != userID {
+		return nil, errors.New("unauthorized: only the user who started the transfer can cancel it")
+	}
+	if err := s.claim(transfer); err != nil {
+		return nil, err
+	}
+
+	if err := s.close(transfer, domainp2p.TransferStatusCancelled, "cancelled by the user"); err != nil {
+		return nil, err
+	}
+	return s.GetTransfer(userID, transferID)
+}
+
+// GetTransfer returns a transfer the user is a party of
+func (s *P2PService) GetTransfer(userID string, transferID string) (*domainp2p.Transfer, error) {
+	transfer, err := s.p2pRepo.GetByID(transferID)
+	if err != nil {
+		return nil, err
+	}
+	if !transfer.IsParty(userID) {
+		return nil, fmt.Errorf("transfer not found with ID: %s", transferID)
+	}
+	return transfer, nil
+}
+
+// ListTransfers lists the transfers the user sent or received, latest first
+func (s *P2PService) ListTransfers(userID string, filters P2PTransferFilters) ([]*domainp2p.Transfer, error) {
+	switch filters.Direction {
+	case "", P2PDirectionSent, P2PDirectionReceived:
+	default:
+		return nil, fmt.Errorf("invalid direction: %s", filters.Direction)
+	}
+	switch filters.Status {
+	case "", domainp2p.TransferStatusPending, domainp2p.TransferStatusProcessing, domainp2p.TransferStatusCompleted,
+		domainp2p.TransferStatusDeclined, domainp2p.TransferStatusCancelled, domainp2p.TransferStatusExpired,
+		domainp2p.TransferStatusFailed:
+	default:
+		return nil, fmt.Errorf("invalid status: %s", filters.Status)
+	}
+
+	if filters.Limit <= 0 {
+		filters.Limit = p2pTransferListLimit
+	}
+	if filters.Limit > p2pTransferListMax {
+		filters.Limit = p2pTransferListMax
+	}
+
+	return s.p2pRepo.GetByUserID(userID, filters)
+}
+
+// ExpirePending expires the pending transfers past their expiry, refunding the sent ones
+func (s *P2PService) ExpirePending(now time.Time) (int, error) {
+	transfers, err := s.p2pRepo.GetExpired(now, p2pExpiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, transfer := range transfers {
+		if err := s.expire(transfer); err != nil {
+			s.logger.Printf("Warning: Failed to expire transfer %s: %v", transfer.ID, err)
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// claimAnswer loads a pending transfer the user has to answer and claims it
+func (s *P2PService) claimAnswer(userID string, transferID string) (*domainp2p.Transfer, error) {
+	transfer, err := s.getPending(userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.ResponderID() != userID {
+		return nil, errors.New("unauthorized: only the other party can accept or decline the transfer")
+	}
+	if err := s.claim(transfer); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// getPending loads a transfer of the user that can still be answered, expiring it when it waited too long
+func (s *P2PService) getPending(userID string, transferID string) (*domainp2p.Transfer, error) {
+	transfer, err := s.GetTransfer(userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.IsExpired(time.Now()) {
+		if err := s.expire(transfer); err != nil {
+			s.logger.Printf("Warning: Failed to expire transfer %s: %v", transfer.ID, err)
+		}
+		return nil, errors.New("transfer expired")
+	}
+	if transfer.Status != domainp2p.TransferStatusPending {
+		return nil, fmt.Errorf("transfer is %s and cannot be changed", transfer.Status)
+	}
+	return transfer, nil
+}
+
+// claim moves a pending transfer to processing, so two answers cannot both go through
+func (s *P2PService) claim(transfer *domainp2p.Transfer) error {
+	claimed, err := s.p2pRepo.Claim(transfer.ID, domainp2p.TransferStatusPending, domainp2p.TransferStatusProcessing)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return errors.New("transfer was already answered")
+	}
+	transfer.Status = domainp2p.TransferStatusProcessing
+	return nil
+}
+
+// release puts a claimed transfer back to pending after an answer that could not go through
+func (s *P2PService) release(transfer *domainp2p.Transfer) {
+	if _, err := s.p2pRepo.Claim(transfer.ID, domainp2p.TransferStatusProcessing, domainp2p.TransferStatusPending); err != nil {
+		s.logger.Printf("Warning: Failed to release transfer %s: %v", transfer.ID, err)
+	}
+	transfer.Status = domainp2p.TransferStatusPending
+}
+
+// expire claims and closes a transfer nobody answered in time
+func (s *P2PService) expire(transfer *domainp2p.Transfer) error {
+	if err := s.claim(transfer); err != nil {
+		return err
+	}
+	return s.close(transfer, domainp2p.TransferStatusExpired, "not answered in time")
+}
+
+// autoAccept credits a sent transfer into the default account of a recipient who accepts them automatically
+func (s *P2PService) autoAccept(transfer *domainp2p.Transfer) {
+	profile, err := s.p2pRepo.GetProfile(transfer.RecipientUserID)
+	if err != nil || profile == nil || !profile.AutoAccept || profile.DefaultAccountID == nil {
+		return
+	}
+
+	account, err := s.receivingAccount(transfer.RecipientUserID, profile.DefaultAccountID, transfer.Currency)
+	if err != nil {
+		// The default account is in another currency: the recipient chooses one on accept
+		return
+	}
+	if err := s.claim(transfer); err != nil {
+		return
+	}
+	if err := s.complete(transfer, account); err != nil {
+		s.logger.Printf("Warning: Failed to accept transfer %s automatically: %v", transfer.ID, err)
+		s.release(transfer)
+	}
+}
+
+// payRequest debits the user asked in a request and credits the account of the requester
+func (s *P2PService) payRequest(transfer *domainp2p.Transfer, accountID *string) error {
+	account, err := s.receivingAccount(transfer.SenderUserID, accountID, transfer.Currency)
+	if err != nil {
+		return err
+	}
+	// The claimed request is in processing, so it already reserves its amount for the checks of other transfers
+	limits, since, err := s.dailyLimits(transfer.SenderUserID)
+	if err != nil {
+		return err
+	}
+	if err := s.p2pRepo.CheckWithinLimits(transfer, limits, since); err != nil {
+		return err
+	}
+	requesterAccount, err := s.ownAccount(transfer.RecipientUserID, stringValue(transfer.RecipientAccountID))
+	if err != nil {
+		return fmt.Errorf("the account of the requester can no longer receive it: %w", err)
+	}
+
+	if err := s.debit(transfer, account); err != nil {
+		return err
+	}
+	if err := s.complete(transfer, requesterAccount); err != nil {
+		// The payer was debited: give the money back and leave the request failed
+		if closeErr := s.close(transfer, domainp2p.TransferStatusFailed, err.Error()); closeErr != nil {
+			s.logger.Printf("Warning: Failed to refund transfer %s: %v", transfer.ID, closeErr)
+		}
+		return fmt.Errorf("transfer failed: %w", err)
+	}
+	return nil
+}
+
+// debit takes the money of the transfer out of an account of the sender
+func (s *P2PService) debit(transfer *domainp2p.Transfer, account *domainp2p.Account) error {
+	recipient := s.partyName(transfer.RecipientUserID)
+	transaction, err := s.createTransaction(transfer, account, false, transfer.RecipientUserID,
+		"sent", describeTransfer("Transfer to "+recipient, transfer.Description))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	transfer.SenderAccountID = &account.ID
+	transfer.SenderTransactionID = &transaction.ID
+	transfer.DebitedAt = &now
+	transfer.UpdatedAt = now
+	return s.p2pRepo.Update(transfer)
+}
+
+// complete credits the money of a claimed transfer into an account of the recipient
+func (s *P2PService) complete(transfer *domainp2p.Transfer, account *domainp2p.Account) error {
+	sender := s.partyName(transfer.SenderUserID)
+	transaction, err := s.createTransaction(transfer, account, true, transfer.SenderUserID,
+		"received", describeTransfer("Transfer from "+sender, transfer.Description))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	transfer.RecipientAccountID = &account.ID
+	transfer.RecipientTransactionID = &transaction.ID
+	transfer.Status = domainp2p.TransferStatusCompleted
+	transfer.RespondedAt = &now
+	transfer.CompletedAt = &now
+	transfer.UpdatedAt = now
+	return s.p2pRepo.Update(transfer)
+}
+
+// close ends a claimed transfer without completing it, refunding the sender when the money already left.
+// A refund that fails puts the transfer back to pending so it can be answered or expired again.
+func (s *P2PService) close(transfer *domainp2p.Transfer, status domainp2p.TransferStatus, reason string) error {
+	if transfer.IsDebited() {
+		account, err := s.ownAccount(transfer.SenderUserID, stringValue(transfer.SenderAccountID))
+		if err == nil {
+			recipient := s.partyName(transfer.RecipientUserID)
+			var transaction *domaintransaction.Transaction
+			transaction, err = s.createTransaction(transfer, account, true, transfer.RecipientUserID,
+				"refund", describeTransfer("Refund of transfer to "+recipient, transfer.Description))
+			if err == nil {
+				transfer.RefundTransactionID = &transaction.ID
+			}
+		}
+		if err != nil {
+			s.release(transfer)
+			return fmt.Errorf("failed to refund the sender: %w", err)
+		}
+	}
+
+	now := time.Now()
+	transfer.Status = status
+	transfer.Reason = reason
+	transfer.RespondedAt = &now
+	transfer.UpdatedAt = now
+	return s.p2pRepo.Update(transfer)
+}
+
+// fail marks a transfer that could not be debited as failed
+func (s *P2PService) fail(transfer *domainp2p.Transfer, reason string) {
+	transfer.Status = domainp2p.TransferStatusFailed
+	transfer.Reason = reason
+	transfer.UpdatedAt = time.Now()
+	if err := s.p2pRepo.Update(transfer); err != nil {
+		s.logger.Printf("Warning: Failed to mark transfer %s as failed: %v", transfer.ID, err)
+	}
+}
+
+// createTransaction records one side of a transfer as a deposit into or a withdrawal from an account of its owner.
+// A withdrawal held by the fraud checks is cancelled: the money of a transfer moves at once or not at all.
+func (s *P2PService) createTransaction(
+	transfer *domainp2p.Transfer,
+	account *domainp2p.Account,
+	deposit bool,
+	counterpartyID string,
+	direction string,
+	description string,
+) (*domaintransaction.Transaction, error) {
+	request := CreateTransactionRequest{
+		UserID:        account.UserID,
+		Amount:        transfer.Amount,
+		Currency:      transfer.Currency,
+		Description:   description,
+		PaymentMethod: domaintransaction.PaymentMethodBankTransfer,
+		ReferenceID:   transfer.ID,
+		Metadata: map[string]interface{}{
+			domainp2p.MetadataTransferID:     transfer.ID,
+			domainp2p.MetadataCounterpartyID: counterpartyID,
+			domainp2p.MetadataDirection:      direction,
+		},
+	}
+	if account.IsWallet() {
+		request.PaymentMethod = domaintransaction.PaymentMethodWallet
+	}
+	switch {
+	case deposit && account.IsWallet():
+		request.Type = domaintransaction.TransactionTypeWalletDeposit
+	case deposit:
+		request.Type = domaintransaction.TransactionTypeAccountDeposit
+	case account.IsWallet():
+		request.Type = domaintransaction.TransactionTypeWalletWithdrawal
+	default:
+		request.Type = domaintransaction.TransactionTypeAccountWithdraw
+	}
+	if deposit {
+		request.ToAccountID = &account.ID
+	} else {
+		request.FromAccountID = &account.ID
+	}
+
+	transaction, err := s.transactionService.CreateTransaction(request, account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Status != domaintransaction.TransactionStatusCompleted {
+		reason := "transfer between users held for review"
+		if err := s.transactionService.CancelTransaction(transaction.ID, reason, account.UserID); err != nil {
+			s.logger.Printf("Warning: Failed to cancel held transaction %s: %v", transaction.ID, err)
+		}
+		return nil, errors.New("transfer held for review by the fraud checks")
+	}
+	return transaction, nil
+}
+
+// receivingAccount picks the account of a user a transfer goes into or is paid from: the chosen one, the
+// default one of the profile, or the oldest wallet in the currency. An empty currency takes the one of the account.
+func (s *P2PService) receivingAccount(userID string, accountID *string, currency string) (*domainp2p.Account, error) {
+	if accountID != nil && *accountID != "" {
+		account, err := s.ownAccount(userID, *accountID)
+		if err != nil {
+			return nil, err
+		}
+		if currency != "" && account.Currency != currency {
+			return nil, fmt.Errorf("account %s is in %s, not in %s", account.ID, account.Currency, currency)
+		}
+		return account, nil
+	}
+
+	profile, err := s.p2pRepo.GetProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.p2pRepo.GetAccounts(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile != nil && profile.DefaultAccountID != nil {
+		for _, account := range accounts {
+			if account.ID == *profile.DefaultAccountID && account.CanTransfer() &&
+				(currency == "" || account.Currency == currency) {
+				return account, nil
+			}
+		}
+	}
+	for _, account := range accounts {
+		if account.IsWallet() && (currency == "" || account.Currency == currency) {
+			return account, nil
+		}
+	}
+
+	if currency == "" {
+		return nil, errors.New("no default account or wallet to use, choose an account")
+	}
+	return nil, fmt.Errorf("no default account or wallet in %s to use, choose an account", currency)
+}
+
+// ownAccount returns an active account of the user that money can be moved in and out of
+func (s *P2PService) ownAccount(userID string, accountID string) (*domainp2p.Account, error) {
+	accounts, err := s.p2pRepo.GetAccounts(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		if account.ID != accountID {
+			continue
+		}
+		if !account.CanTransfer() {
+			return nil, fmt.Errorf("account %s cannot send or receive transfers", accountID)
+		}
+		return account, nil
+	}
+	return nil, fmt.Errorf("account not found with ID: %s", accountID)
+}
+
+// resolveParty finds the user an email or alias belongs to. A user found by alias is shown without the email.
+func (s *P2PService) resolveParty(identifier string) (*domainp2p.Party, error) {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return nil, errors.New("email or alias of the other user is required")
+	}
+
+	if domainp2p.IsEmail(identifier) {
+		return s.p2pRepo.GetPartyByEmail(strings.ToLower(identifier))
+	}
+
+	profile, err := s.p2pRepo.GetProfileByAlias(domainp2p.NormalizeAlias(identifier))
+	if err != nil {
+		return nil, err
+	}
+	party, err := s.p2pRepo.GetParty(profile.UserID)
+	if err != nil {
+		return nil, err
+	}
+	party.Email = ""
+	party.Alias = profile.Alias
+	return party, nil
+}
+
+// partyName returns the name of a user for the description of a transaction, the ID when it cannot be found
+func (s *P2PService) partyName(userID string) string {
+	party, err := s.p2pRepo.GetParty(userID)
+	if err != nil || party.Name == "" {
+		return userID
+	}
+	return party.Name
+}
+
+// dailyLimits returns the effective limits of a user and the start of the day they apply to
+func (s *P2PService) dailyLimits(userID string) (domainp2p.Limits, time.Time, error) {
+	profile, err := s.p2pRepo.GetProfile(userID)
+	if err != nil {
+		return domainp2p.Limits{}, time.Time{}, err
+	}
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return domainp2p.EffectiveLimits(s.limits, profile), startOfDay, nil
+}
+
+// dailyUsage returns the effective limits of a user in a currency and what the user sent today
+func (s *P2PService) dailyUsage(userID string, currency string) (domainp2p.Limits, *domainp2p.Usage, error) {
+	limits, since, err := s.dailyLimits(userID)
+	if err != nil {
+		return domainp2p.Limits{}, nil, err
+	}
+	usage, err := s.p2pRepo.GetDailyUsage(userID, currency, since)
+	if err != nil {
+		return domainp2p.Limits{}, nil, err
+	}
+	return limits, usage, nil
+}
+
+// describeTransfer appends the description of a transfer to the one of its transactions
+func describeTransfer(prefix string, description string) string {
+	if description == "" {
+		return prefix
+	}
+	return prefix + ": " + description
+}
@@ -0,0 +1,687 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	domainp2p "github.com/fintrack/transaction-service/internal/core/domain/entities/p2p"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// MockP2PRepository implements P2PRepositoryInterface for testing. Transfers are copied in and out, as
+// a database would, and every status they are saved with is recorded.
+type MockP2PRepository struct {
+	profiles  map[string]*domainp2p.Profile
+	parties   map[string]*domainp2p.Party
+	accounts  map[string][]*domainp2p.Account
+	transfers map[string]*domainp2p.Transfer
+	statuses  map[string][]domainp2p.TransferStatus
+	created   int
+}
+
+func NewMockP2PRepository() *MockP2PRepository {
+	return &MockP2PRepository{
+		profiles:  make(map[string]*domainp2p.Profile),
+		parties:   make(map[string]*domainp2p.Party),
+		accounts:  make(map[string][]*domainp2p.Account),
+		transfers: make(map[string]*domainp2p.Transfer),
+		statuses:  make(map[string][]domainp2p.TransferStatus),
+	}
+}
+
+func (m *MockP2PRepository) GetProfile(userID string) (*domainp2p.Profile, error) {
+	return m.profiles[userID], nil
+}
+
+func (m *MockP2PRepository) GetProfileByAlias(alias string) (*domainp2p.Profile, error) {
+	for _, profile := range m.profiles {
+		if profile.Alias == alias {
+			return profile, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found with alias: %s", alias)
+}
+
+func (m *MockP2PRepository) SaveProfile(profile *domainp2p.Profile) error {
+	m.profiles[profile.UserID] = profile
+	return nil
+}
+
+func (m *MockP2PRepository) GetParty(userID string) (*domainp2p.Party, error) {
+	party, exists := m.parties[userID]
+	if !exists {
+		return nil, fmt.Errorf("user not found with ID: %s", userID)
+	}
+	copied := *party
+	return &copied, nil
+}
+
+func (m *MockP2PRepository) GetPartyByEmail(email string) (*domainp2p.Party, error) {
+	for _, party := range m.parties {
+		if party.Email == email {
+			copied := *party
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found with email: %s", email)
+}
+
+func (m *MockP2PRepository) GetAccounts(userID string) ([]*domainp2p.Account, error) {
+	return m.accounts[userID], nil
+}
+
+func (m *MockP2PRepository) Create(transfer *domainp2p.Transfer) error {
+	// Transfers created in the same nanosecond would share their ID
+	m.created++
+	transfer.ID = fmt.Sprintf("%s_%d", transfer.ID, m.created)
+	return m.Update(transfer)
+}
+
+func (m *MockP2PRepository) GetByID(id string) (*domainp2p.Transfer, error) {
+	transfer, exists := m.transfers[id]
+	if !exists {
+		return nil, fmt.Errorf("transfer not found with ID: %s", id)
+	}
+	copied := *transfer
+	return &copied, nil
+}
+
+func (m *MockP2PRepository) Update(transfer *domainp2p.Transfer) error {
+	copied := *transfer
+	m.transfers[transfer.ID] = &copied
+	m.recordStatus(transfer.ID, transfer.Status)
+	return nil
+}
+
+func (m *MockP2PRepository) Claim(id string, from, to domainp2p.TransferStatus) (bool, error) {
+	transfer, exists := m.transfers[id]
+	if !exists || transfer.Status != from {
+		return false, nil
+	}
+	transfer.Status = to
+	m.recordStatus(id, to)
+	return true, nil
+}
+
+func (m *MockP2PRepository) GetByUserID(userID string, filters P2PTransferFilters) ([]*domainp2p.Transfer, error) {
+	var transfers []*domainp2p.Transfer
+	for _, transfer := range m.transfers {
+		if transfer.IsParty(userID) {
+			copied := *transfer
+			transfers = append(transfers, &copied)
+		}
+	}
+	return transfers, nil
+}
+
+func (m *MockP2PRepository) GetExpired(now time.Time, limit int) ([]*domainp2p.Transfer, error) {
+	var transfers []*domainp2p.Transfer
+	for _, transfer := range m.transfers {
+		if transfer.IsExpired(now) && len(transfers) < limit {
+			copied := *transfer
+			transfers = append(transfers, &copied)
+		}
+	}
+	return transfers, nil
+}
+
+func (m *MockP2PRepository) GetDailyUsage(userID string, currency string, since time.Time) (*domainp2p.Usage, error) {
+	return m.usage(userID, currency, since, ""), nil
+}
+
+func (m *MockP2PRepository) CreateWithinLimits(transfer *domainp2p.Transfer, limits domainp2p.Limits, since time.Time) error {
+	usage := m.usage(transfer.SenderUserID, transfer.Currency, since, transfer.ID)
+	if err := domainp2p.CheckLimits(limits, usage, transfer.Amount); err != nil {
+		return err
+	}
+	return m.Create(transfer)
+}
+
+func (m *MockP2PRepository) CheckWithinLimits(transfer *domainp2p.Transfer, limits domainp2p.Limits, since time.Time) error {
+	usage := m.usage(transfer.SenderUserID, transfer.Currency, since, transfer.ID)
+	return domainp2p.CheckLimits(limits, usage, transfer.Amount)
+}
+
+// usage sums the transfers of a sender as the daily usage query does, leaving one transfer out
+func (m *MockP2PRepository) usage(userID string, currency string, since time.Time, excludedID string) *domainp2p.Usage {
+	usage := &domainp2p.Usage{}
+	for _, transfer := range m.transfers {
+		if transfer.ID == excludedID || transfer.SenderUserID != userID || transfer.Currency != currency {
+			continue
+		}
+		if transfer.RefundTransactionID != nil {
+			continue
+		}
+		switch transfer.Status {
+		case domainp2p.TransferStatusPending, domainp2p.TransferStatusProcessing, domainp2p.TransferStatusCompleted:
+		default:
+			continue
+		}
+		// Transfers being debited reserve their amount
+		if transfer.DebitedAt == nil && transfer.Status != domainp2p.TransferStatusProcessing {
+			continue
+		}
+		if transfer.DebitedAt != nil && transfer.DebitedAt.Before(since) {
+			continue
+		}
+		usage.Amount += transfer.Amount
+		usage.Count++
+	}
+	return usage
+}
+
+// recordStatus keeps the statuses a transfer went through, skipping repeated saves with the same one
+func (m *MockP2PRepository) recordStatus(id string, status domainp2p.TransferStatus) {
+	statuses := m.statuses[id]
+	if len(statuses) == 0 || statuses[len(statuses)-1] != status {
+		m.statuses[id] = append(statuses, status)
+	}
+}
+
+// MockP2PTransactionService implements the transaction operations used by transfers for testing
+type MockP2PTransactionService struct {
+	TransactionServiceInterface
+	requests  []CreateTransactionRequest
+	cancelled []string
+	hold      bool   // Transactions come back pending, as when the fraud checks hold them
+	err       error  // Returned by CreateTransaction when set
+	onCreate  func() // Called once by the next CreateTransaction, before it returns
+}
+
+func (m *MockP2PTransactionService) CreateTransaction(request CreateTransactionRequest, initiatedBy string) (*domaintransaction.Transaction, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.requests = append(m.requests, request)
+	if onCreate := m.onCreate; onCreate != nil {
+		m.onCreate = nil
+		onCreate()
+	}
+
+	status := domaintransaction.TransactionStatusCompleted
+	if m.hold {
+		status = domaintransaction.TransactionStatusPending
+	}
+	return &domaintransaction.Transaction{
+		ID:          fmt.Sprintf("txn_%d", len(m.requests)),
+		UserID:      request.UserID,
+		Type:        request.Type,
+		Status:      status,
+		Amount:      request.Amount,
+		Currency:    request.Currency,
+		InitiatedBy: initiatedBy,
+	}, nil
+}
+
+func (m *MockP2PTransactionService) CancelTransaction(id string, reason string, canceledBy string) error {
+	m.cancelled = append(m.cancelled, id)
+	return nil
+}
+
+// directions returns the p2pDirection of every transaction created, in order
+func (m *MockP2PTransactionService) directions() []string {
+	directions := make([]string, len(m.requests))
+	for i, request := range m.requests {
+		directions[i], _ = request.Metadata[domainp2p.MetadataDirection].(string)
+	}
+	return directions
+}
+
+// Verify interface compliance
+var _ P2PRepositoryInterface = (*MockP2PRepository)(nil)
+
+type p2pFixture struct {
+	service      *P2PService
+	repo         *MockP2PRepository
+	transactions *MockP2PTransactionService
+	logs         *bytes.Buffer
+}
+
+// setupP2PService creates Ana (ana@fintrack.com, bank account in ARS) and Bruno (alias "bruno.pagos",
+// wallet in ARS and savings account in USD) with the given platform limits
+func setupP2PService(limits domainp2p.Limits) *p2pFixture {
+	repo := NewMockP2PRepository()
+	repo.parties["ana"] = &domainp2p.Party{UserID: "ana", Name: "Ana", Email: "ana@fintrack.com"}
+	repo.parties["bruno"] = &domainp2p.Party{UserID: "bruno", Name: "Bruno", Email: "bruno@fintrack.com"}
+	repo.profiles["bruno"] = &domainp2p.Profile{UserID: "bruno", Alias: "bruno.pagos"}
+	repo.accounts["ana"] = []*domainp2p.Account{
+		{ID: "ana-bank", UserID: "ana", Name: "Banco Galicia", AccountType: "bank_account", Currency: "ARS"},
+	}
+	repo.accounts["bruno"] = []*domainp2p.Account{
+		{ID: "bruno-wallet", UserID: "bruno", Name: "Billetera", AccountType: "wallet", Currency: "ARS"},
+		{ID: "bruno-usd", UserID: "bruno", Name: "Caja de ahorro USD", AccountType: "savings", Currency: "USD"},
+	}
+
+	transactions := &MockP2PTransactionService{}
+	logs := &bytes.Buffer{}
+	service := NewP2PService(repo, transactions, limits).(*P2PService)
+	service.logger = log.New(logs, "", 0)
+
+	return &p2pFixture{service: service, repo: repo, transactions: transactions, logs: logs}
+}
+
+// send sends money from Ana to Bruno, failing the test on error
+func (f *p2pFixture) send(t *testing.T, amount float64) *domainp2p.Transfer {
+	t.Helper()
+	transfer, err := f.service.SendMoney("ana", SendMoneyRequest{To: "bruno.pagos", FromAccountID: "ana-bank", Amount: amount})
+	if err != nil {
+		t.Fatalf("SendMoney() unexpected error: %v", err)
+	}
+	return transfer
+}
+
+func TestSendMoneyStateMachine(t *testing.T) {
+	f := setupP2PService(domainp2p.Limits{})
+
+	transfer := f.send(t, 1500)
+	if transfer.Status != domainp2p.TransferStatusPending {
+		t.Fatalf("SendMoney() status = %v, want %v", transfer.Status, domainp2p.TransferStatusPending)
+	}
+	if transfer.SenderTransactionID == nil || transfer.DebitedAt == nil {
+		t.Fatal("SendMoney() expected the sender to be debited")
+	}
+	if got := f.transactions.requests[0]; got.Type != domaintransaction.TransactionTypeAccountWithdraw || *got.FromAccountID != "ana-bank" {
+		t.Errorf("SendMoney() debit = %v from %v, want %v from ana-bank", got.Type, got.FromAccountID, domaintransaction.TransactionTypeAccountWithdraw)
+	}
+
+	// The sender cannot accept the transfer for the recipient
+	if _, err := f.service.AcceptTransfer("ana", transfer.ID, RespondTransferRequest{}); err == nil {
+		t.Error("AcceptTransfer() by the sender expected error but got none")
+	}
+
+	accepted, err := f.service.AcceptTransfer("bruno", transfer.ID, RespondTransferRequest{})
+	if err != nil {
+		t.Fatalf("AcceptTransfer() unexpected error: %v", err)
+	}
+	if accepted.Status != domainp2p.TransferStatusCompleted || accepted.RecipientTransactionID == nil {
+		t.Fatalf("AcceptTransfer() = %v with recipient transaction %v, want completed", accepted.Status, accepted.RecipientTransactionID)
+	}
+	if got := *accepted.RecipientAccountID; got != "bruno-wallet" {
+		t.Errorf("AcceptTransfer() received into %s, want the wallet in the currency", got)
+	}
+
+	// Processing until debited, pending until claimed, processing while the answer goes through
+	want := []domainp2p.TransferStatus{
+		domainp2p.TransferStatusProcessing,
+		domainp2p.TransferStatusPending,
+		domainp2p.TransferStatusProcessing,
+		domainp2p.TransferStatusCompleted,
+	}
+	if got := f.repo.statuses[transfer.ID]; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("transfer statuses = %v, want %v", got, want)
+	}
+	if got, want := strings.Join(f.transactions.directions(), ","), "sent,received"; got != want {
+		t.Errorf("transactions = %s, want %s", got, want)
+	}
+
+	// A transfer is answered once
+	if _, err := f.service.AcceptTransfer("bruno", transfer.ID, RespondTransferRequest{}); err == nil {
+		t.Error("AcceptTransfer() of a completed transfer expected error but got none")
+	}
+	if _, err := f.service.DeclineTransfer("bruno", transfer.ID, RespondTransferRequest{}); err == nil {
+		t.Error("DeclineTransfer() of a completed transfer expected error but got none")
+	}
+}
+
+func TestAcceptTransferNotYetDebited(t *testing.T) {
+	f := setupP2PService(domainp2p.Limits{})
+
+	// A transfer still processing its debit cannot be claimed
+	f.repo.transfers["p2p_processing"] = &domainp2p.Transfer{
+		ID:              "p2p_processing",
+		Kind:            domainp2p.TransferKindSend,
+		SenderUserID:    "ana",
+		RecipientUserID: "bruno",
+		Amount:          100,
+		Currency:        "ARS",
+		Status:          domainp2p.TransferStatusProcessing,
+		ExpiresAt:       time.Now().Add(domainp2p.DefaultExpiry),
+	}
+
+	if _, err := f.service.AcceptTransfer("bruno", "p2p_processing", RespondTransferRequest{}); err == nil {
+		t.Error("AcceptTransfer() of a processing transfer expected error but got none")
+	}
+	if len(f.transactions.requests) != 0 {
+		t.Errorf("AcceptTransfer() created %d transactions, want none", len(f.transactions.requests))
+	}
+}
+
+func TestAcceptTransferReleasedOnFailure(t *testing.T) {
+	f := setupP2PService(domainp2p.Limits{})
+	transfer := f.send(t, 500)
+
+	// The chosen account is in another currency: the transfer waits for another answer
+	usd := "bruno-usd"
+	if _, err := f.service.AcceptTransfer("bruno", transfer.ID, RespondTransferRequest{AccountID: &usd}); err == nil {
+		t.Fatal("AcceptTransfer() into an account in another currency expected error but got none")
+	}
+	if got := f.repo.transfers[transfer.ID].Status; got != domainp2p.TransferStatusPending {
+		t.Errorf("transfer status = %v, want %v", got, domainp2p.TransferStatusPending)
+	}
+}
+
+func TestSendMoneyAutoAccept(t *testing.T) {
+	tests := []struct {
+		name           string
+		defaultAccount string
+		expectedStatus domainp2p.TransferStatus
+		expectedCount  int // Transactions created
+	}{
+		{
+			name:           "default account in the currency",
+			defaultAccount: "bruno-wallet",
+			expectedStatus: domainp2p.TransferStatusCompleted,
+			expectedCount:  2,
+		},
+		{
+			name:           "default account in another currency",
+			defaultAccount: "bruno-usd",
+			expectedStatus: domainp2p.TransferStatusPending,
+			expectedCount:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupP2PService(domainp2p.Limits{})
+			f.repo.profiles["bruno"].AutoAccept = true
+			f.repo.profiles["bruno"].DefaultAccountID = &tt.defaultAccount
+
+			transfer := f.send(t, 750)
+			if transfer.Status != tt.expectedStatus {
+				t.Errorf("SendMoney() status = %v, want %v", transfer.Status, tt.expectedStatus)
+			}
+			if len(f.transactions.requests) != tt.expectedCount {
+				t.Errorf("SendMoney() created %d transactions, want %d", len(f.transactions.requests), tt.expectedCount)
+			}
+		})
+	}
+}
+
+func TestTransferRefunds(t *testing.T) {
+	tests := []struct {
+		name           string
+		answer         func(f *p2pFixture, transferID string) error
+		expectedStatus domainp2p.TransferStatus
+	}{
+		{
+			name: "declined by the recipient",
+			answer: func(f *p2pFixture, transferID string) error {
+				_, err := f.service.DeclineTransfer("bruno", transferID, RespondTransferRequest{Reason: "no lo conozco"})
+				return err
+			},
+			expectedStatus: domainp2p.TransferStatusDeclined,
+		},
+		{
+			name: "cancelled by the sender",
+			answer: func(f *p2pFixture, transferID string) error {
+				_, err := f.service.CancelTransfer("ana", transferID)
+				return err
+			},
+			expectedStatus: domainp2p.TransferStatusCancelled,
+		},
+		{
+			name: "expired by the job",
+			answer: func(f *p2pFixture, transferID string) error {
+				expired, err := f.service.ExpirePending(time.Now().Add(domainp2p.DefaultExpiry + time.Hour))
+				if err == nil && expired != 1 {
+					err = fmt.Errorf("expired %d transfers, want 1", expired)
+				}
+				return err
+			},
+			expectedStatus: domainp2p.TransferStatusExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupP2PService(domainp2p.Limits{})
+			transfer := f.send(t, 2000)
+
+			if err := tt.answer(f, transfer.ID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			closed := f.repo.transfers[transfer.ID]
+			if closed.Status != tt.expectedStatus {
+				t.Errorf("transfer status = %v, want %v", closed.Status, tt.expectedStatus)
+			}
+			if closed.RefundTransactionID == nil {
+				t.Fatal("expected the sender to be refunded")
+			}
+			if got, want := strings.Join(f.transactions.directions(), ","), "sent,refund"; got != want {
+				t.Errorf("transactions = %s, want %s", got, want)
+			}
+			refund := f.transactions.requests[1]
+			if refund.Type != domaintransaction.TransactionTypeAccountDeposit || *refund.ToAccountID != "ana-bank" || refund.Amount != 2000 {
+				t.Errorf("refund = %v of %.2f into %v, want %v of 2000.00 into ana-bank", refund.Type, refund.Amount, refund.ToAccountID, domaintransaction.TransactionTypeAccountDeposit)
+			}
+
+			// Refunded transfers no longer count against the daily limit
+			usage, _ := f.repo.GetDailyUsage("ana", "ARS", time.Now().Add(-time.Hour))
+			if usage.Count != 0 || usage.Amount != 0 {
+				t.Errorf("daily usage = %+v, want none", *usage)
+			}
+		})
+	}
+}
+
+func TestCancelTransferOnlyByInitiator(t *testing.T) {
+	f := setupP2PService(domainp2p.Limits{})
+	transfer := f.send(t, 300)
+
+	if _, err := f.service.CancelTransfer("bruno", transfer.ID); err == nil {
+		t.Error("CancelTransfer() by the recipient expected error but got none")
+	}
+	if got := f.repo.transfers[transfer.ID].Status; got != domainp2p.TransferStatusPending {
+		t.Errorf("transfer status = %v, want %v", got, domainp2p.TransferStatusPending)
+	}
+}
+
+func TestExpirePendingRefundFailure(t *testing.T) {
+	f := setupP2PService(domainp2p.Limits{})
+	transfer := f.send(t, 400)
+
+	f.transactions.err = errors.New("account service unavailable")
+	expired, err := f.service.ExpirePending(time.Now().Add(domainp2p.DefaultExpiry + time.Hour))
+	if err != nil {
+		t.Fatalf("ExpirePending() unexpected error: %v", err)
+	}
+	if expired != 0 {
+		t.Errorf("ExpirePending() = %d, want 0", expired)
+	}
+
+	// The transfer waits to be expired again, and the failure is logged
+	if got := f.repo.transfers[transfer.ID].Status; got != domainp2p.TransferStatusPending {
+		t.Errorf("transfer status = %v, want %v", got, domainp2p.TransferStatusPending)
+	}
+	if !strings.Contains(f.logs.String(), "Failed to expire transfer "+transfer.ID) {
+		t.Errorf("ExpirePending() logs = %q, want the failed transfer", f.logs.String())
+	}
+}
+
+func TestSendMoneyHeldByFraudChecks(t *testing.T) {
+	f := setupP2PService(domainp2p.Limits{})
+	f.transactions.hold = true
+
+	if _, err := f.service.SendMoney("ana", SendMoneyRequest{To: "bruno.pagos", FromAccountID: "ana-bank", Amount: 900}); err == nil {
+		t.Fatal("SendMoney() held by the fraud checks expected error but got none")
+	}
+	if len(f.transactions.cancelled) != 1 {
+		t.Errorf("SendMoney() cancelled %d held transactions, want 1", len(f.transactions.cancelled))
+	}
+	for id, transfer := range f.repo.transfers {
+		if transfer.Status != domainp2p.TransferStatusFailed {
+			t.Errorf("transfer %s status = %v, want %v", id, transfer.Status, domainp2p.TransferStatusFailed)
+		}
+	}
+}
+
+func TestSendMoneyDailyLimits(t *testing.T) {
+	ownAmount := 800.0
+	ownCount := 1
+
+	tests := []struct {
+		name          string
+		limits        domainp2p.Limits
+		profile       *domainp2p.Profile // Own limits of the sender
+		amounts       []float64
+		expectedError string // Of the last transfer; the others go through
+	}{
+		{
+			name:    "within the limits",
+			limits:  domainp2p.Limits{DailyAmount: 1000, DailyCount: 3},
+			amounts: []float64{600, 400},
+		},
+		{
+			name:          "amount limit exceeded",
+			limits:        domainp2p.Limits{DailyAmount: 1000, DailyCount: 3},
+			amounts:       []float64{600, 400.01},
+			expectedError: "daily amount limit",
+		},
+		{
+			name:          "count limit reached",
+			limits:        domainp2p.Limits{DailyAmount: 1000, DailyCount: 2},
+			amounts:       []float64{100, 100, 100},
+			expectedError: "daily limit of transfers",
+		},
+		{
+			name:          "own amount limit lower than the platform",
+			limits:        domainp2p.Limits{DailyAmount: 1000, DailyCount: 3},
+			profile:       &domainp2p.Profile{UserID: "ana", DailyAmountLimit: &ownAmount},
+			amounts:       []float64{500, 301},
+			expectedError: "daily amount limit",
+		},
+		{
+			name:          "own count limit lower than the platform",
+			limits:        domainp2p.Limits{DailyAmount: 1000, DailyCount: 3},
+			profile:       &domainp2p.Profile{UserID: "ana", DailyCountLimit: &ownCount},
+			amounts:       []float64{100, 100},
+			expectedError: "daily limit of transfers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupP2PService(tt.limits)
+			if tt.profile != nil {
+				f.repo.profiles["ana"] = tt.profile
+			}
+
+			last := len(tt.amounts) - 1
+			for _, amount := range tt.amounts[:last] {
+				f.send(t, amount)
+			}
+
+			transfers := len(f.repo.transfers)
+			transactions := len(f.transactions.requests)
+			_, err := f.service.SendMoney("ana", SendMoneyRequest{To: "bruno.pagos", FromAccountID: "ana-bank", Amount: tt.amounts[last]})
+
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("SendMoney() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Fatalf("SendMoney() error = %v, want %q", err, tt.expectedError)
+			}
+			// A rejected transfer is neither recorded nor debited
+			if len(f.repo.transfers) != transfers || len(f.transactions.requests) != transactions {
+				t.Error("SendMoney() over the limit recorded a transfer or a transaction")
+			}
+		})
+	}
+}
+
+func TestSendMoneyConcurrentDailyLimit(t *testing.T) {
+	f := setupP2PService(domainp2p.Limits{DailyAmount: 1000, DailyCount: 5})
+
+	// A second send arrives while the first one is being debited: together they exceed the limit
+	var secondErr error
+	f.transactions.onCreate = func() {
+		_, secondErr = f.service.SendMoney("ana", SendMoneyRequest{To: "bruno.pagos", FromAccountID: "ana-bank", Amount: 500})
+	}
+	f.send(t, 600)
+
+	if secondErr == nil || !strings.Contains(secondErr.Error(), "daily amount limit") {
+		t.Fatalf("concurrent SendMoney() error = %v, want %q", secondErr, "daily amount limit")
+	}
+	if len(f.repo.transfers) != 1 || len(f.transactions.requests) != 1 {
+		t.Errorf("concurrent sends recorded %d transfers and %d transactions, want 1 and 1",
+			len(f.repo.transfers), len(f.transactions.requests))
+	}
+	usage, _ := f.repo.GetDailyUsage("ana", "ARS", time.Now().Add(-time.Hour))
+	if usage.Amount != 600 || usage.Count != 1 {
+		t.Errorf("daily usage = %v in %d transfers, want 600 in 1", usage.Amount, usage.Count)
+	}
+}
+
+func TestSendMoneyLimitFreedByRefund(t *testing.T) {
+	f := setupP2PService(domainp2p.Limits{DailyAmount: 1000, DailyCount: 1})
+
+	transfer := f.send(t, 1000)
+	if _, err := f.service.SendMoney("ana", SendMoneyRequest{To: "bruno.pagos", FromAccountID: "ana-bank", Amount: 1}); err == nil {
+		t.Fatal("SendMoney() over the count limit expected error but got none")
+	}
+
+	if _, err := f.service.DeclineTransfer("bruno", transfer.ID, RespondTransferRequest{}); err != nil {
+		t.Fatalf("DeclineTransfer() unexpected error: %v", err)
+	}
+	f.send(t, 1000)
+}
+
+func TestRequestMoneyPaymentLimit(t *testing.T) {
+	f := setupP2PService(domainp2p.Limits{DailyAmount: 1000, DailyCount: 5})
+
+	// Bruno asks Ana for more than she can send today
+	request, err := f.service.RequestMoney("bruno", RequestMoneyRequest{From: "ana@fintrack.com", Amount: 1200})
+	if err != nil {
+		t.Fatalf("RequestMoney() unexpected error: %v", err)
+	}
+	if request.Status != domainp2p.TransferStatusPending || *request.RecipientAccountID != "bruno-wallet" {
+		t.Fatalf("RequestMoney() = %v into %v, want pending into bruno-wallet", request.Status, request.RecipientAccountID)
+	}
+
+	bank := "ana-bank"
+	if _, err := f.service.AcceptTransfer("ana", request.ID, RespondTransferRequest{AccountID: &bank}); err == nil {
+		t.Fatal("AcceptTransfer() over the daily limit expected error but got none")
+	}
+	if got := f.repo.transfers[request.ID].Status; got != domainp2p.TransferStatusPending {
+		t.Errorf("request status = %v, want %v", got, domainp2p.TransferStatusPending)
+	}
+	if len(f.transactions.requests) != 0 {
+		t.Errorf("AcceptTransfer() created %d transactions, want none", len(f.transactions.requests))
+	}
+}
+
+func TestRequestMoneyPaid(t *testing.T) {
+	f := setupP2PService(domainp2p.Limits{})
+
+	request, err := f.service.RequestMoney("bruno", RequestMoneyRequest{From: "ana@fintrack.com", Amount: 350})
+	if err != nil {
+		t.Fatalf("RequestMoney() unexpected error: %v", err)
+	}
+
+	// Only the user asked to pay can accept it
+	if _, err := f.service.AcceptTransfer("bruno", request.ID, RespondTransferRequest{}); err == nil {
+		t.Error("AcceptTransfer() by the requester expected error but got none")
+	}
+
+	bank := "ana-bank"
+	paid, err := f.service.AcceptTransfer("ana", request.ID, RespondTransferRequest{AccountID: &bank})
+	if err != nil {
+		t.Fatalf("AcceptTransfer() unexpected error: %v", err)
+	}
+	if paid.Status != domainp2p.TransferStatusCompleted || *paid.SenderAccountID != "ana-bank" {
+		t.Errorf("AcceptTransfer() = %v from %v, want completed from ana-bank", paid.Status, paid.SenderAccountID)
+	}
+	if got, want := strings.Join(f.transactions.directions(), ","), "sent,received"; got != want {
+		t.Errorf("transactions = %s, want %s", got, want)
+	}
+}
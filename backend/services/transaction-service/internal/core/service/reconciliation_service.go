@@ -0,0 +1,543 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	domainreconciliation "github.com/fintrack/transaction-service/internal/core/domain/entities/reconciliation"
+	domainstatement "github.com/fintrack/transaction-service/internal/core/domain/entities/statement"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+const (
+	// reconciliationMaxWindowDays bounds the date window of the fuzzy matching
+	reconciliationMaxWindowDays = 15
+	// reconciliationTransactionsLimit bounds the transactions read to reconcile or report a status
+	reconciliationTransactionsLimit = 10000
+	// reconciliationStatusListLimit bounds the unreconciled transactions listed in a status
+	reconciliationStatusListLimit = 100
+)
+
+// ReconciliationService implements ReconciliationServiceInterface
+// Compares bank statements with the transactions tracked in FinTrack for the same account or card
+type ReconciliationService struct {
+	transactionRepo    TransactionRepositoryInterface
+	reconciliationRepo ReconciliationRepositoryInterface
+	importService      ImportServiceInterface
+}
+
+// NewReconciliationService creates a new reconciliation service. Statements are read through the import service.
+func NewReconciliationService(
+	transactionRepo TransactionRepositoryInterface,
+	reconciliationRepo ReconciliationRepositoryInterface,
+	importService ImportServiceInterface,
+) ReconciliationServiceInterface {
+	return &ReconciliationService{
+		transactionRepo:    transactionRepo,
+		reconciliationRepo: reconciliationRepo,
+		importService:      importService,
+	}
+}
+
+// CreateReconciliation matches the movements of a statement with the tracked transactions of its account or card
+func (s *ReconciliationService) CreateReconciliation(userID string, request ReconciliationRequest) (*ReconciliationDetail, error) {
+	config, err := reconciliationConfig(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var reconciliation *domainreconciliation.Reconciliation
+	var bank []domainreconciliation.BankEntry
+	switch {
+	case request.ImportBatchID != nil && request.Statement != nil:
+		return nil, errors.New("reconcile either an import or an uploaded statement")
+	case request.ImportBatchID != nil:
+		reconciliation, bank, err = s.readImport(userID, *request.ImportBatchID)
+	case request.Statement != nil:
+		reconciliation, bank, err = s.readUpload(userID, *request.Statement)
+	default:
+		return nil, errors.New("an import or a statement file is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bank) == 0 {
+		return nil, errors.New("the statement has no movements to reconcile")
+	}
+
+	reconciliation.UserID = userID
+	reconciliation.DateWindowDays = config.DateWindowDays
+	reconciliation.AmountTolerance = config.AmountTolerance
+	reconciliation.StatementFrom, reconciliation.StatementTo = bankPeriod(bank)
+
+	book, err := s.trackedEntries(userID, reconciliation,
+		reconciliation.StatementFrom.AddDate(0, 0, -config.DateWindowDays-1),
+		reconciliation.StatementTo.AddDate(0, 0, config.DateWindowDays+1))
+	if err != nil {
+		return nil, err
+	}
+
+	items := domainreconciliation.Match(bank, book, reconciliation.StatementFrom, reconciliation.StatementTo, config)
+	reconciliation.Summarize(items, time.Now())
+
+	created, err := s.reconciliationRepo.Create(reconciliation, items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reconciliation: %w", err)
+	}
+
+	return s.detail(created)
+}
+
+// GetReconciliations returns the reconciliations of the user, optionally of one account or card
+func (s *ReconciliationService) GetReconciliations(userID string, accountID, cardID *string) ([]*domainreconciliation.Reconciliation, error) {
+	reconciliations, err := s.reconciliationRepo.GetByUserID(userID, accountID, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliations: %w", err)
+	}
+	return reconciliations, nil
+}
+
+// GetReconciliation returns a reconciliation of the user with its items
+func (s *ReconciliationService) GetReconciliation(userID string, reconciliationID string) (*ReconciliationDetail, error) {
+	reconciliation, err := s.getOwnReconciliation(userID, reconciliationID)
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(reconciliation)
+}
+
+// ConfirmItem accepts a suggested match
+func (s *ReconciliationService) ConfirmItem(userID string, reconciliationID string, itemID string) (*ReconciliationDetail, error) {
+	reconciliation, items, item, err := s.getItem(userID, reconciliationID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := item.Confirm(now); err != nil {
+		return nil, err
+	}
+
+	reconciliation.Summarize(items, now)
+	if err := s.reconciliationRepo.SaveItems(reconciliation, []*domainreconciliation.Item{item}, nil); err != nil {
+		return nil, fmt.Errorf("failed to confirm reconciliation item: %w", err)
+	}
+
+	return s.detail(reconciliation)
+}
+
+// LinkItem matches a statement movement missing in FinTrack with a transaction chosen by the user. If the
+// transaction was reported as missing in the bank, that item goes away.
+func (s *ReconciliationService) LinkItem(userID string, reconciliationID string, itemID string, transactionID string) (*ReconciliationDetail, error) {
+	if strings.TrimSpace(transactionID) == "" {
+		return nil, errors.New("transaction ID is required")
+	}
+
+	reconciliation, items, item, err := s.getItem(userID, reconciliationID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.UserID != userID {
+		return nil, fmt.Errorf("transaction not found with ID: %s", transactionID)
+	}
+	entry, ok := bookEntry(transaction, reconciliation)
+	if !ok {
+		return nil, errors.New("the transaction does not move money of the account or card of the reconciliation")
+	}
+
+	var removedIDs []string
+	for _, other := range items {
+		if other.TransactionID == nil || *other.TransactionID != transaction.ID {
+			continue
+		}
+		if other.Status == domainreconciliation.ItemStatusMatched {
+			return nil, errors.New("the transaction is already matched in this reconciliation")
+		}
+		removedIDs = append(removedIDs, other.ID)
+	}
+
+	now := time.Now()
+	if err := item.Link(entry, now); err != nil {
+		return nil, err
+	}
+
+	remaining := items[:0:0]
+	for _, other := range items {
+		if !containsString(removedIDs, other.ID) {
+			remaining = append(remaining, other)
+		}
+	}
+	reconciliation.Summarize(remaining, now)
+
+	if err := s.reconciliationRepo.SaveItems(reconciliation, []*domainreconciliation.Item{item}, removedIDs); err != nil {
+		return nil, fmt.Errorf("failed to link reconciliation item: %w", err)
+	}
+
+	return s.detail(reconciliation)
+}
+
+// UnlinkItem rejects a match: the statement movement becomes missing in FinTrack and the transaction missing in the bank
+func (s *ReconciliationService) UnlinkItem(userID string, reconciliationID string, itemID string) (*ReconciliationDetail, error) {
+	reconciliation, items, item, err := s.getItem(userID, reconciliationID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	missingInBank, err := item.Unlink()
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation.Summarize(append(items, missingInBank), time.Now())
+	if err := s.reconciliationRepo.SaveItems(reconciliation, []*domainreconciliation.Item{item, missingInBank}, nil); err != nil {
+		return nil, fmt.Errorf("failed to unlink reconciliation item: %w", err)
+	}
+
+	return s.detail(reconciliation)
+}
+
+// GetStatus tells how much of an account or card is reconciled up to a date. A transaction is reconciled
+// when a confirmed match links it with a statement movement.
+func (s *ReconciliationService) GetStatus(userID string, request ReconciliationStatusRequest) (*ReconciliationStatus, error) {
+	if (request.AccountID == nil) == (request.CardID == nil) {
+		return nil, errors.New("either an account or a card is required")
+	}
+
+	asOf := request.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 23, 59, 59, 0, asOf.Location())
+
+	status := &ReconciliationStatus{
+		AccountID:    request.AccountID,
+		CardID:       request.CardID,
+		AsOf:         asOf,
+		Unreconciled: []*ReconciliationStatusEntry{},
+		OpenItems:    []*domainreconciliation.Item{},
+	}
+
+	reconciliations, err := s.reconciliationRepo.GetByUserID(userID, request.AccountID, request.CardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliations: %w", err)
+	}
+	var periods []*domainreconciliation.Reconciliation
+	for _, reconciliation := range reconciliations {
+		if reconciliation.StatementFrom.After(asOf) {
+			continue
+		}
+		periods = append(periods, reconciliation)
+		statementTo := reconciliation.StatementTo
+		if status.LastStatementDate == nil || statementTo.After(*status.LastStatementDate) {
+			status.LastStatementDate = &statementTo
+		}
+		if reconciliation.CompletedAt != nil && !statementTo.After(asOf) &&
+			(status.ReconciledThrough == nil || statementTo.After(*status.ReconciledThrough)) {
+			status.ReconciledThrough = &statementTo
+		}
+	}
+	status.Reconciliations = len(periods)
+
+	items, err := s.reconciliationRepo.GetItemsUntil(userID, request.AccountID, request.CardID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliation items: %w", err)
+	}
+
+	// The same statement can be reconciled more than once, so movements are told apart by their content
+	reconciled := make(map[string]bool)
+	matchedMovements := make(map[string]bool)
+	pending := make(map[string]bool)
+	for _, item := range items {
+		if item.Status == domainreconciliation.ItemStatusMatched && item.Confirmed {
+			reconciled[*item.TransactionID] = true
+			matchedMovements[movementKey(item)] = true
+		}
+	}
+	missingMovements := make(map[string]bool)
+	for _, item := range items {
+		switch {
+		case item.IsPending() && !reconciled[*item.TransactionID] && !pending[*item.TransactionID]:
+			pending[*item.TransactionID] = true
+			status.OpenItems = append(status.OpenItems, item)
+		case item.Status == domainreconciliation.ItemStatusMissingInFinTrack && !matchedMovements[movementKey(item)] && !missingMovements[movementKey(item)]:
+			missingMovements[movementKey(item)] = true
+			status.OpenItems = append(status.OpenItems, item)
+		}
+	}
+	status.PendingMatches = len(pending)
+	status.MissingInFinTrack = len(missingMovements)
+
+	transactions, _, err := s.transactionRepo.GetByUserID(userID, TransactionFilters{
+		Statuses: []domaintransaction.TransactionStatus{domaintransaction.TransactionStatusCompleted},
+		ToDate:   &asOf,
+		Limit:    reconciliationTransactionsLimit,
+		OrderBy:  "created_at",
+		Order:    "desc",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	for _, transaction := range transactions {
+		amount, ok := signedAmount(transaction, request.AccountID, request.CardID)
+		if !ok {
+			continue
+		}
+		status.TotalTransactions++
+		if reconciled[transaction.ID] {
+			status.ReconciledTransactions++
+			continue
+		}
+
+		status.UnreconciledTransactions++
+		status.UnreconciledAmount += amount
+		if inStatementPeriod(transaction.CreatedAt, periods) {
+			status.MissingInBank++
+		}
+		if len(status.Unreconciled) < reconciliationStatusListLimit {
+			status.Unreconciled = append(status.Unreconciled, &ReconciliationStatusEntry{
+				TransactionID: transaction.ID,
+				Date:          transaction.CreatedAt,
+				Amount:        amount,
+				Description:   transactionDescription(transaction),
+			})
+		}
+	}
+	status.UnreconciledAmount = math.Round(status.UnreconciledAmount*100) / 100
+
+	status.IsReconciled = status.Reconciliations > 0 && status.UnreconciledTransactions == 0 &&
+		status.PendingMatches == 0 && status.MissingInFinTrack == 0
+
+	return status, nil
+}
+
+// readImport takes the statement movements from the rows of an import
+func (s *ReconciliationService) readImport(userID string, batchID string) (*domainreconciliation.Reconciliation, []domainreconciliation.BankEntry, error) {
+	result, err := s.importService.GetBatch(userID, batchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if result.Batch.Status != domainstatement.BatchStatusCompleted {
+		return nil, nil, fmt.Errorf("cannot reconcile an import with status %s", result.Batch.Status)
+	}
+
+	var bank []domainreconciliation.BankEntry
+	for _, row := range result.Rows {
+		if row.Status != domainstatement.RowStatusImported && row.Status != domainstatement.RowStatusDuplicate {
+			continue
+		}
+		entry := bankEntry(row)
+		entry.ImportRowID = row.ID
+		if row.TransactionID != nil {
+			entry.TransactionID = *row.TransactionID
+		}
+		bank = append(bank, entry)
+	}
+
+	batchID = result.Batch.ID
+	return &domainreconciliation.Reconciliation{
+		AccountID:     result.Batch.AccountID,
+		CardID:        result.Batch.CardID,
+		Currency:      result.Batch.Currency,
+		Source:        domainreconciliation.SourceImport,
+		ImportBatchID: &batchID,
+		FileName:      result.Batch.FileName,
+	}, bank, nil
+}
+
+// readUpload takes the statement movements from a file that is not imported
+func (s *ReconciliationService) readUpload(userID string, statement ImportRequest) (*domainreconciliation.Reconciliation, []domainreconciliation.BankEntry, error) {
+	preview, err := s.importService.PreviewImport(userID, statement)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var bank []domainreconciliation.BankEntry
+	for _, row := range preview.Rows {
+		if row.Status != domainstatement.RowStatusInvalid {
+			bank = append(bank, bankEntry(row))
+		}
+	}
+
+	return &domainreconciliation.Reconciliation{
+		AccountID: statement.AccountID,
+		CardID:    statement.CardID,
+		Currency:  preview.Currency,
+		Source:    domainreconciliation.SourceUpload,
+		FileName:  statement.FileName,
+	}, bank, nil
+}
+
+// trackedEntries returns the completed and pending transactions of the account or card of the
+// reconciliation between two dates, in its currency
+func (s *ReconciliationService) trackedEntries(userID string, reconciliation *domainreconciliation.Reconciliation, from, to time.Time) ([]domainreconciliation.BookEntry, error) {
+	transactions, _, err := s.transactionRepo.GetByUserID(userID, TransactionFilters{
+		Statuses: []domaintransaction.TransactionStatus{domaintransaction.TransactionStatusCompleted, domaintransaction.TransactionStatusPending},
+		FromDate: &from,
+		ToDate:   &to,
+		Limit:    reconciliationTransactionsLimit,
+		OrderBy:  "created_at",
+		Order:    "asc",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	var entries []domainreconciliation.BookEntry
+	for _, transaction := range transactions {
+		if entry, ok := bookEntry(transaction, reconciliation); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (s *ReconciliationService) getOwnReconciliation(userID string, reconciliationID string) (*domainreconciliation.Reconciliation, error) {
+	reconciliation, err := s.reconciliationRepo.GetByID(reconciliationID)
+	if err != nil {
+		return nil, err
+	}
+	if reconciliation.UserID != userID {
+		return nil, fmt.Errorf("reconciliation not found with ID: %s", reconciliationID)
+	}
+	return reconciliation, nil
+}
+
+// getItem returns a reconciliation of the user with all its items and the requested one
+func (s *ReconciliationService) getItem(userID string, reconciliationID string, itemID string) (*domainreconciliation.Reconciliation, []*domainreconciliation.Item, *domainreconciliation.Item, error) {
+	reconciliation, err := s.getOwnReconciliation(userID, reconciliationID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	items, err := s.reconciliationRepo.GetItems(reconciliation.ID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get reconciliation items: %w", err)
+	}
+	for _, item := range items {
+		if item.ID == itemID {
+			return reconciliation, items, item, nil
+		}
+	}
+	return nil, nil, nil, fmt.Errorf("reconciliation item not found with ID: %s", itemID)
+}
+
+func (s *ReconciliationService) detail(reconciliation *domainreconciliation.Reconciliation) (*ReconciliationDetail, error) {
+	items, err := s.reconciliationRepo.GetItems(reconciliation.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliation items: %w", err)
+	}
+	return &ReconciliationDetail{Reconciliation: reconciliation, Items: items}, nil
+}
+
+// reconciliationConfig returns the matching configuration of a request
+func reconciliationConfig(request ReconciliationRequest) (domainreconciliation.MatchConfig, error) {
+	config := domainreconciliation.DefaultMatchConfig()
+	if request.DateWindowDays != nil {
+		if *request.DateWindowDays < 0 || *request.DateWindowDays > reconciliationMaxWindowDays {
+			return config, fmt.Errorf("date window must be between 0 and %d days", reconciliationMaxWindowDays)
+		}
+		config.DateWindowDays = *request.DateWindowDays
+	}
+	if request.AmountTolerance != nil {
+		if *request.AmountTolerance < 0 {
+			return config, errors.New("amount tolerance cannot be negative")
+		}
+		config.AmountTolerance = *request.AmountTolerance
+	}
+	return config, nil
+}
+
+func bankEntry(row *domainstatement.ImportRow) domainreconciliation.BankEntry {
+	description := row.Description
+	if description == "" {
+		description = row.Merchant
+	}
+	return domainreconciliation.BankEntry{
+		Date:        row.PostedAt,
+		Amount:      row.Amount,
+		Description: description,
+		ExternalID:  row.ExternalID,
+	}
+}
+
+// bookEntry returns a transaction as seen by the statement of the reconciliation. ok is false when it
+// belongs to another account, card or currency.
+func bookEntry(transaction *domaintransaction.Transaction, reconciliation *domainreconciliation.Reconciliation) (domainreconciliation.BookEntry, bool) {
+	if transaction.Currency != reconciliation.Currency {
+		return domainreconciliation.BookEntry{}, false
+	}
+	amount, ok := signedAmount(transaction, reconciliation.AccountID, reconciliation.CardID)
+	if !ok {
+		return domainreconciliation.BookEntry{}, false
+	}
+	return domainreconciliation.BookEntry{
+		TransactionID: transaction.ID,
+		Date:          transaction.CreatedAt,
+		Amount:        amount,
+		Description:   transactionDescription(transaction),
+		ExternalID:    transaction.ExternalID,
+	}, true
+}
+
+// transactionDescription joins the merchant and the description of a transaction to compare it with bank descriptions
+func transactionDescription(transaction *domaintransaction.Transaction) string {
+	description := transaction.Description
+	if transaction.MerchantName != "" && !strings.Contains(strings.ToLower(description), strings.ToLower(transaction.MerchantName)) {
+		description = strings.TrimSpace(transaction.MerchantName + " " + description)
+	}
+	return description
+}
+
+// bankPeriod returns the first and last days of the statement movements
+func bankPeriod(bank []domainreconciliation.BankEntry) (time.Time, time.Time) {
+	from, to := bank[0].Date, bank[0].Date
+	for _, entry := range bank[1:] {
+		if entry.Date.Before(from) {
+			from = entry.Date
+		}
+		if entry.Date.After(to) {
+			to = entry.Date
+		}
+	}
+	return time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location()),
+		time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, to.Location())
+}
+
+// movementKey identifies a statement movement across reconciliations of the same account or card
+func movementKey(item *domainreconciliation.Item) string {
+	if item.BankDate == nil || item.BankAmount == nil {
+		return ""
+	}
+	reference := item.BankExternalID
+	if reference == "" {
+		reference = strings.ToLower(item.BankDescription)
+	}
+	return fmt.Sprintf("%s|%.2f|%s", item.BankDate.Format("2006-01-02"), *item.BankAmount, reference)
+}
+
+func inStatementPeriod(date time.Time, reconciliations []*domainreconciliation.Reconciliation) bool {
+	for _, reconciliation := range reconciliations {
+		if !date.Before(reconciliation.StatementFrom) && date.Before(reconciliation.StatementTo.AddDate(0, 0, 1)) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
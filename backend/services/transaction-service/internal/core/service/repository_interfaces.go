@@ -1,8 +1,19 @@
 package service
 
 import (
+	"io"
 	"time"
 
+	domainattachment "github.com/fintrack/transaction-service/internal/core/domain/entities/attachment"
+	domainbudget "github.com/fintrack/transaction-service/internal/core/domain/entities/budget"
+	domaincategory "github.com/fintrack/transaction-service/internal/core/domain/entities/category"
+	domainfraud "github.com/fintrack/transaction-service/internal/core/domain/entities/fraud"
+	domaingroup "github.com/fintrack/transaction-service/internal/core/domain/entities/group"
+	domainp2p "github.com/fintrack/transaction-service/internal/core/domain/entities/p2p"
+	domainreconciliation "github.com/fintrack/transaction-service/internal/core/domain/entities/reconciliation"
+	domainschedule "github.com/fintrack/transaction-service/internal/core/domain/entities/schedule"
+	domainstatement "github.com/fintrack/transaction-service/internal/core/domain/entities/statement"
+	domainsubscription "github.com/fintrack/transaction-service/internal/core/domain/entities/subscription"
 	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
 )
 
@@ -16,16 +27,26 @@ type TransactionRepositoryInterface interface {
 	Delete(id string) error
 
 	// Query operations
+	// IsVisibleTo tells whether a member of a shared account or a card holder can see a transaction
+	IsVisibleTo(id, userID string) (bool, error)
 	GetByUserID(userID string, filters TransactionFilters) ([]*domaintransaction.Transaction, int, error)
-	GetByAccountID(accountID string, filters TransactionFilters) ([]*domaintransaction.Transaction, int, error)
-	GetByCardID(cardID string, filters TransactionFilters) ([]*domaintransaction.Transaction, int, error)
+	// GetByAccountID and GetByCardID only return the transactions the user can see, as GetByUserID does
+	GetByAccountID(accountID, userID string, filters TransactionFilters) ([]*domaintransaction.Transaction, int, error)
+	GetByCardID(cardID, userID string, filters TransactionFilters) ([]*domaintransaction.Transaction, int, error)
 	GetByReferenceID(referenceID string) (*domaintransaction.Transaction, error)
 	GetByExternalID(externalID string) (*domaintransaction.Transaction, error)
+	// StreamByUserID calls fn for every transaction matching the filters of GetByUserID, without pagination
+	StreamByUserID(userID string, filters TransactionFilters, fn func(*domaintransaction.Transaction) error) error
 
 	// Batch operations
 	CreateBatch(transactions []*domaintransaction.Transaction) ([]*domaintransaction.Transaction, error)
 	UpdateBatch(transactions []*domaintransaction.Transaction) ([]*domaintransaction.Transaction, error)
 
+	// Categorization operations
+	GetForCategorization(userID string, fromDate, toDate *time.Time, includeManual bool, limit, offset int) ([]*domaintransaction.Transaction, error)
+	UpdateCategory(id string, categoryID *string, source domaintransaction.CategorySource) error
+	ReassignCategory(userID, fromCategoryID string, toCategoryID *string) error
+
 	// Aggregation operations
 	GetUserTransactionSummary(userID string, fromDate, toDate *time.Time) (*TransactionSummary, error)
 	GetAccountTransactionSummary(accountID string, fromDate, toDate *time.Time) (*TransactionSummary, error)
@@ -46,6 +67,65 @@ type TransactionRuleRepositoryInterface interface {
 	GetActiveRulesForTransaction(userID string, accountID *string, cardID *string, transactionType domaintransaction.TransactionType) ([]*domaintransaction.TransactionRule, error)
 }
 
+// AllocationRepositoryInterface defines the contract for split transaction allocations data access
+type AllocationRepositoryInterface interface {
+	GetByTransactionID(transactionID string) ([]*domaintransaction.Allocation, error)
+	// ReplaceForTransaction atomically replaces every allocation of a transaction
+	ReplaceForTransaction(transactionID string, allocations []*domaintransaction.Allocation) ([]*domaintransaction.Allocation, error)
+	DeleteByTransactionID(transactionID string) error
+}
+
+// ScheduledTransactionRepositoryInterface defines the contract for scheduled transactions data access
+type ScheduledTransactionRepositoryInterface interface {
+	Create(schedule *domainschedule.ScheduledTransaction) (*domainschedule.ScheduledTransaction, error)
+	GetByID(id string) (*domainschedule.ScheduledTransaction, error)
+	Update(schedule *domainschedule.ScheduledTransaction) (*domainschedule.ScheduledTransaction, error)
+	GetByUserID(userID string, status *domainschedule.ScheduleStatus) ([]*domainschedule.ScheduledTransaction, error)
+
+	// GetDue returns active schedules whose next run is at or before now, oldest first
+	GetDue(now time.Time, limit int) ([]*domainschedule.ScheduledTransaction, error)
+
+	// CreateRun claims an occurrence. It returns ErrRunAlreadyClaimed when the occurrence already has a run.
+	CreateRun(run *domainschedule.ScheduledRun) (*domainschedule.ScheduledRun, error)
+	UpdateRun(run *domainschedule.ScheduledRun) error
+	GetRuns(scheduleID string, limit int) ([]*domainschedule.ScheduledRun, error)
+}
+
+// SubscriptionRepositoryInterface defines the contract for the snapshot of detected subscriptions,
+// which report-service and the chatbot read
+type SubscriptionRepositoryInterface interface {
+	// ReplaceForUser atomically replaces the detected subscriptions of a user
+	ReplaceForUser(userID string, subscriptions []*domainsubscription.Subscription) error
+	GetByUserID(userID string) ([]*domainsubscription.Subscription, error)
+
+	// GetUsersWithCharges returns the users with card or account debits since the given date
+	GetUsersWithCharges(since time.Time) ([]string, error)
+}
+
+// CategoryRepositoryInterface defines the contract for transaction categories data access
+type CategoryRepositoryInterface interface {
+	Create(category *domaincategory.Category) (*domaincategory.Category, error)
+	GetByID(id string) (*domaincategory.Category, error)
+	Update(category *domaincategory.Category) (*domaincategory.Category, error)
+	Delete(id string) error
+
+	// GetForUser returns the default categories plus the custom categories of the user
+	GetForUser(userID string) ([]*domaincategory.Category, error)
+	CountChildren(id string) (int, error)
+}
+
+// CategorizationRuleRepositoryInterface defines the contract for auto-categorization rules data access
+type CategorizationRuleRepositoryInterface interface {
+	Create(rule *domaincategory.CategorizationRule) (*domaincategory.CategorizationRule, error)
+	GetByID(id string) (*domaincategory.CategorizationRule, error)
+	Update(rule *domaincategory.CategorizationRule) (*domaincategory.CategorizationRule, error)
+	Delete(id string) error
+
+	// GetByUserID returns the rules of a user by priority, highest first
+	GetByUserID(userID string) ([]*domaincategory.CategorizationRule, error)
+	DeleteByCategory(userID, categoryID string) error
+}
+
 // TransactionAuditRepositoryInterface defines the contract for audit data access
 type TransactionAuditRepositoryInterface interface {
 	Create(audit *TransactionAuditEntry) error
@@ -81,3 +161,198 @@ type TransactionAuditEntry struct {
 	UserAgent     string                               `json:"userAgent" db:"user_agent"`
 	CreatedAt     time.Time                            `json:"createdAt" db:"created_at"`
 }
+
+// ImportBatchRepositoryInterface defines the contract for bank statement imports data access
+type ImportBatchRepositoryInterface interface {
+	Create(batch *domainstatement.ImportBatch) (*domainstatement.ImportBatch, error)
+	// Complete saves the rows of an import together with its counters and final status
+	Complete(batch *domainstatement.ImportBatch, rows []*domainstatement.ImportRow) error
+	UpdateStatus(batchID string, status domainstatement.BatchStatus) error
+	GetByID(id string) (*domainstatement.ImportBatch, error)
+	GetByUserID(userID string, limit int) ([]*domainstatement.ImportBatch, error)
+	GetRows(batchID string) ([]*domainstatement.ImportRow, error)
+	// Rollback deletes the transactions created by an import and marks it as rolled back
+	Rollback(batch *domainstatement.ImportBatch) (int, error)
+}
+
+// ReconciliationRepositoryInterface defines the contract for statement reconciliations data access
+type ReconciliationRepositoryInterface interface {
+	Create(reconciliation *domainreconciliation.Reconciliation, items []*domainreconciliation.Item) (*domainreconciliation.Reconciliation, error)
+	GetByID(id string) (*domainreconciliation.Reconciliation, error)
+	GetByUserID(userID string, accountID, cardID *string) ([]*domainreconciliation.Reconciliation, error)
+	GetItems(reconciliationID string) ([]*domainreconciliation.Item, error)
+	// SaveItems updates the changed items, creates the ones without ID and deletes the removed ones,
+	// together with the counters of the reconciliation
+	SaveItems(reconciliation *domainreconciliation.Reconciliation, changed []*domainreconciliation.Item, removedIDs []string) error
+	// GetItemsUntil returns the items of the reconciliations of an account or card dated up to a date
+	GetItemsUntil(userID string, accountID, cardID *string, until time.Time) ([]*domainreconciliation.Item, error)
+}
+
+// CSVMappingRepositoryInterface defines the contract for the saved CSV column mappings of users
+type CSVMappingRepositoryInterface interface {
+	Create(mapping *domainstatement.CSVMapping) (*domainstatement.CSVMapping, error)
+	GetByID(id string) (*domainstatement.CSVMapping, error)
+	GetByUserID(userID string) ([]*domainstatement.CSVMapping, error)
+	Update(mapping *domainstatement.CSVMapping) (*domainstatement.CSVMapping, error)
+	Delete(id string) error
+}
+
+// AccountLabel is how an account is named in exports
+type AccountLabel struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+// CardLabel is how a card is named in exports: its nickname, or brand and last digits
+type CardLabel struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Currency string `json:"currency"` // Currency of the account of the card
+}
+
+// LabelRepositoryInterface defines the contract for reading the names of the accounts and cards of a user
+type LabelRepositoryInterface interface {
+	// GetAccountLabels returns the own and shared accounts of a user, including the deleted ones, by ID
+	GetAccountLabels(userID string) (map[string]*AccountLabel, error)
+	// GetCardLabels returns the cards of the own and shared accounts of a user, including the deleted ones, by ID
+	GetCardLabels(userID string) (map[string]*CardLabel, error)
+}
+
+// AttachmentRepositoryInterface defines the contract for the metadata of stored attachments
+type AttachmentRepositoryInterface interface {
+	// Create stores an attachment only if the attachments of its user, this one included, fit in the quota.
+	// It returns ErrAttachmentQuotaExceeded otherwise; concurrent uploads of a user are checked one at a time.
+	Create(attachment *domainattachment.Attachment, quota int64) (*domainattachment.Attachment, error)
+	GetByID(id string) (*domainattachment.Attachment, error)
+	GetByOwner(ownerType domainattachment.OwnerType, ownerID string) ([]*domainattachment.Attachment, error)
+	// GetByUserID returns the attachments of the records of a type the user can see, e.g. for the export
+	GetByUserID(userID string, ownerType domainattachment.OwnerType) ([]*domainattachment.Attachment, error)
+	Delete(id string) error
+	// GetUsage returns how many files and bytes the user uploaded
+	GetUsage(userID string) (int, int64, error)
+	// GetOrphans returns attachments whose transaction or installment plan no longer exists
+	GetOrphans(limit int) ([]*domainattachment.Attachment, error)
+	// GetInstallmentPlanUserID returns the owner of an installment plan (managed by the account service)
+	GetInstallmentPlanUserID(planID string) (string, error)
+}
+
+// AttachmentStorageInterface defines the contract for storing the content of attachments. Keys are
+// relative paths like attachments/{userId}/{attachmentId}.pdf.
+type AttachmentStorageInterface interface {
+	Put(key string, content io.Reader, size int64, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	// Delete removes the content of a key; deleting a missing key is not an error
+	Delete(key string) error
+}
+
+// DuplicateRepositoryInterface defines the contract for merging duplicate transactions
+type DuplicateRepositoryInterface interface {
+	// Merge saves the kept transaction, moves the installment, audit, attachment, schedule and reconciliation
+	// references of the duplicates to it, deletes the duplicates and logs the audit entry, all or nothing
+	Merge(keep *domaintransaction.Transaction, duplicateIDs []string, audit *TransactionAuditEntry) error
+}
+
+// FraudRepositoryInterface defines the contract for the risk scoring settings of users and the scores of transactions
+type FraudRepositoryInterface interface {
+	// GetSettings returns the settings of a user, nil when the user did not configure them
+	GetSettings(userID string) (*domainfraud.Settings, error)
+	SaveSettings(settings *domainfraud.Settings) error
+	CreateScore(score *domainfraud.Score) error
+	GetScoreByTransactionID(transactionID string) (*domainfraud.Score, error)
+	// GetScoresByUserID returns the scores of the transactions of a user, latest first; an empty action lists all
+	GetScoresByUserID(userID string, minScore int, action domainfraud.Action, limit int) ([]*domainfraud.Score, error)
+}
+
+// P2PRepositoryInterface defines the contract for the profiles of users and the transfers between them
+type P2PRepositoryInterface interface {
+	// GetProfile returns the profile of a user, nil when the user did not create one
+	GetProfile(userID string) (*domainp2p.Profile, error)
+	GetProfileByAlias(alias string) (*domainp2p.Profile, error)
+	// SaveProfile creates or replaces a profile. It returns ErrAliasTaken when another user has the alias.
+	SaveProfile(profile *domainp2p.Profile) error
+	// GetParty returns an active user as shown to the other side of a transfer
+	GetParty(userID string) (*domainp2p.Party, error)
+	GetPartyByEmail(email string) (*domainp2p.Party, error)
+	// GetAccounts returns the active own accounts of a user, oldest first
+	GetAccounts(userID string) ([]*domainp2p.Account, error)
+
+	Create(transfer *domainp2p.Transfer) error
+	GetByID(id string) (*domainp2p.Transfer, error)
+	Update(transfer *domainp2p.Transfer) error
+	// Claim moves a transfer from one status to another only if it still has the first one, so a transfer
+	// is answered once. It returns false when another request changed it first.
+	Claim(id string, from, to domainp2p.TransferStatus) (bool, error)
+	GetByUserID(userID string, filters P2PTransferFilters) ([]*domainp2p.Transfer, error)
+	GetExpired(now time.Time, limit int) ([]*domainp2p.Transfer, error)
+	// GetDailyUsage sums what a user sent to other users in a currency since a time, refunded transfers aside.
+	// Transfers in processing that were not debited yet count too: their amount is reserved.
+	GetDailyUsage(userID string, currency string, since time.Time) (*domainp2p.Usage, error)
+	// CreateWithinLimits creates a transfer only if it fits in the daily limits of its sender, returning the error
+	// of domainp2p.CheckLimits when it does not. Checks of the same sender run one at a time, so concurrent
+	// transfers cannot exceed the limits together.
+	CreateWithinLimits(transfer *domainp2p.Transfer, limits domainp2p.Limits, since time.Time) error
+	// CheckWithinLimits checks as CreateWithinLimits does that a transfer already recorded and claimed fits,
+	// leaving it out of the usage
+	CheckWithinLimits(transfer *domainp2p.Transfer, limits domainp2p.Limits, since time.Time) error
+}
+
+// GroupRepositoryInterface defines the contract for groups of users, their shared expenses and settlements
+type GroupRepositoryInterface interface {
+	// CreateGroup persists a group with its members
+	CreateGroup(group *domaingroup.Group) error
+	// GetGroup returns a group with its members and their names
+	GetGroup(id string) (*domaingroup.Group, error)
+	// GetGroupsByUserID returns the groups a user is a member of, latest first
+	GetGroupsByUserID(userID string) ([]*domaingroup.Group, error)
+	AddMember(member *domaingroup.Member) error
+	RemoveMember(groupID string, userID string) error
+
+	// CreateExpense persists an expense with its shares
+	CreateExpense(expense *domaingroup.Expense) error
+	GetExpense(id string) (*domaingroup.Expense, error)
+	// GetExpenses returns the expenses of a group with their shares, latest first; 0 returns them all
+	GetExpenses(groupID string, limit int) ([]*domaingroup.Expense, error)
+	DeleteExpense(id string) error
+
+	CreateSettlement(settlement *domaingroup.Settlement) error
+	// GetSettlements returns the settlements of a group with the status of their transfers, latest first
+	GetSettlements(groupID string) ([]*domaingroup.Settlement, error)
+}
+
+// BudgetSpending is the actual spending of a budget in a date range
+type BudgetSpending struct {
+	Transactions float64 `json:"transactions"` // Own expenses, purchases in installments aside
+	Installments float64 `json:"installments"` // Installments due in the range
+}
+
+// BudgetRepositoryInterface defines the contract for budgets, the snapshot of their months and their alerts
+type BudgetRepositoryInterface interface {
+	Create(budget *domainbudget.Budget) error
+	GetByID(id string) (*domainbudget.Budget, error)
+	// GetByUserID returns the budgets of a user, by name
+	GetByUserID(userID string) ([]*domainbudget.Budget, error)
+	// GetActive returns the budgets of every user that apply to a month
+	GetActive(month string) ([]*domainbudget.Budget, error)
+	Update(budget *domainbudget.Budget) error
+	// Delete removes a budget with its months and alerts
+	Delete(id string) error
+
+	// GetSpending sums the expenses in the scope and currency of a budget between two dates. Purchases
+	// paid in installments count through the installments due in the range instead of their full amount.
+	GetSpending(budget *domainbudget.Budget, from, to time.Time) (*BudgetSpending, error)
+	// SavePeriods creates or replaces the snapshot of months of budgets read by reports and the chatbot
+	SavePeriods(periods []*domainbudget.Period) error
+	// ClaimAlert records that an alert threshold of a month was reached, so it is sent once. It returns false
+	// when the alert was already claimed.
+	ClaimAlert(period *domainbudget.Period, threshold int) (bool, error)
+}
+
+// AccountMemberRepositoryInterface defines the contract for reading the members of shared accounts, which the
+// account service manages
+type AccountMemberRepositoryInterface interface {
+	// GetActiveMembership returns the active membership of a user in an account, nil when there is none
+	GetActiveMembership(accountID, userID string) (*AccountMembership, error)
+	// GetCardAccountID returns the account a card belongs to, empty when the card does not exist
+	GetCardAccountID(cardID string) (string, error)
+}
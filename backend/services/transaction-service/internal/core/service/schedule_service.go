@@ -0,0 +1,400 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domainschedule "github.com/fintrack/transaction-service/internal/core/domain/entities/schedule"
+	"github.com/fintrack/transaction-service/internal/core/interfaces"
+	"github.com/fintrack/transaction-service/internal/infrastructure/http/clients"
+)
+
+// ErrRunAlreadyClaimed is returned by the repository when an occurrence of a schedule already has a run,
+// so the same occurrence is never materialized twice
+var ErrRunAlreadyClaimed = errors.New("scheduled run already claimed")
+
+const (
+	// dueBatchSize bounds the schedules processed in one pass of the scheduler
+	dueBatchSize = 100
+	// upcomingOccurrences is the number of next dates shown in the schedule detail
+	upcomingOccurrences = 5
+	// recentRuns is the number of runs shown in the schedule detail
+	recentRuns = 10
+)
+
+// ScheduleService implements ScheduleServiceInterface
+// Keeps recurring and future-dated transaction templates and materializes them through the transaction service
+type ScheduleService struct {
+	scheduleRepo        ScheduledTransactionRepositoryInterface
+	categoryRepo        CategoryRepositoryInterface
+	transactionService  TransactionServiceInterface
+	notificationService interfaces.NotificationServiceInterface
+}
+
+// NewScheduleService creates a new schedule service
+func NewScheduleService(
+	scheduleRepo ScheduledTransactionRepositoryInterface,
+	categoryRepo CategoryRepositoryInterface,
+	transactionService TransactionServiceInterface,
+	notificationService interfaces.NotificationServiceInterface,
+) ScheduleServiceInterface {
+	return &ScheduleService{
+		scheduleRepo:        scheduleRepo,
+		categoryRepo:        categoryRepo,
+		transactionService:  transactionService,
+		notificationService: notificationService,
+	}
+}
+
+// CreateSchedule registers a recurring template or a one-off future-dated transaction
+func (s *ScheduleService) CreateSchedule(userID string, request ScheduledTransactionRequest) (*domainschedule.ScheduledTransaction, error) {
+	schedule := &domainschedule.ScheduledTransaction{UserID: userID}
+	if err := s.applyRequest(schedule, request); err != nil {
+		return nil, err
+	}
+	if err := validateStartAt(schedule.StartAt, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := schedule.Initialize(); err != nil {
+		return nil, err
+	}
+
+	return s.scheduleRepo.Create(schedule)
+}
+
+// GetSchedules returns the scheduled transactions of the user, optionally filtered by status
+func (s *ScheduleService) GetSchedules(userID string, status *domainschedule.ScheduleStatus) ([]*domainschedule.ScheduledTransaction, error) {
+	return s.scheduleRepo.GetByUserID(userID, status)
+}
+
+// GetSchedule returns a scheduled transaction with its upcoming dates and latest runs
+func (s *ScheduleService) GetSchedule(userID string, scheduleID string) (*ScheduleDetail, error) {
+	schedule, err := s.getOwnSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := s.scheduleRepo.GetRuns(schedule.ID, recentRuns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled runs: %w", err)
+	}
+
+	return &ScheduleDetail{
+		Schedule:   schedule,
+		Upcoming:   schedule.Upcoming(upcomingOccurrences),
+		RecentRuns: runs,
+	}, nil
+}
+
+// UpdateSchedule replaces the template of a schedule. Changing the recurrence or the start date restarts the series.
+func (s *ScheduleService) UpdateSchedule(userID string, scheduleID string, request ScheduledTransactionRequest) (*domainschedule.ScheduledTransaction, error) {
+	schedule, err := s.getOwnSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.IsFinished() {
+		return nil, fmt.Errorf("cannot update a %s schedule", schedule.Status)
+	}
+
+	previousRecurrence, previousStartAt := schedule.Recurrence, schedule.StartAt
+	if err := s.applyRequest(schedule, request); err != nil {
+		return nil, err
+	}
+
+	if schedule.Recurrence != previousRecurrence || !schedule.StartAt.Equal(previousStartAt) {
+		if err := validateStartAt(schedule.StartAt, time.Now()); err != nil {
+			return nil, err
+		}
+		status := schedule.Status
+		if err := schedule.Initialize(); err != nil {
+			return nil, err
+		}
+		schedule.Status = status
+	}
+
+	return s.scheduleRepo.Update(schedule)
+}
+
+// CancelSchedule stops a schedule permanently. Transactions already created are kept.
+func (s *ScheduleService) CancelSchedule(userID string, scheduleID string) error {
+	schedule, err := s.getOwnSchedule(userID, scheduleID)
+	if err != nil {
+		return err
+	}
+	if err := schedule.Cancel(); err != nil {
+		return err
+	}
+
+	_, err = s.scheduleRepo.Update(schedule)
+	return err
+}
+
+// GetRuns returns the latest runs of a schedule
+func (s *ScheduleService) GetRuns(userID string, scheduleID string, limit int) ([]*domainschedule.ScheduledRun, error) {
+	if _, err := s.getOwnSchedule(userID, scheduleID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	return s.scheduleRepo.GetRuns(scheduleID, limit)
+}
+
+// PauseSchedule stops materializing occurrences until the schedule is resumed
+func (s *ScheduleService) PauseSchedule(userID string, scheduleID string) (*domainschedule.ScheduledTransaction, error) {
+	schedule, err := s.getOwnSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := schedule.Pause(); err != nil {
+		return nil, err
+	}
+
+	return s.scheduleRepo.Update(schedule)
+}
+
+// ResumeSchedule reactivates a paused schedule. Occurrences missed while it was paused are recorded as skipped.
+func (s *ScheduleService) ResumeSchedule(userID string, scheduleID string) (*domainschedule.ScheduledTransaction, error) {
+	schedule, err := s.getOwnSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped, err := schedule.Resume(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, occurrence := range skipped {
+		s.recordSkippedRun(schedule.ID, occurrence, "missed while the schedule was paused")
+	}
+
+	return s.scheduleRepo.Update(schedule)
+}
+
+// SkipNextOccurrence moves the schedule past its next occurrence without creating a transaction
+func (s *ScheduleService) SkipNextOccurrence(userID string, scheduleID string) (*domainschedule.ScheduledTransaction, error) {
+	schedule, err := s.getOwnSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.IsFinished() || schedule.NextOccurrenceAt == nil {
+		return nil, errors.New("schedule has no pending occurrences")
+	}
+
+	occurrence := *schedule.NextOccurrenceAt
+	if err := schedule.Advance(); err != nil {
+		return nil, err
+	}
+	s.recordSkippedRun(schedule.ID, occurrence, "skipped by the user")
+
+	return s.scheduleRepo.Update(schedule)
+}
+
+// EditNextOccurrence changes the amount, description or date of the next occurrence only
+func (s *ScheduleService) EditNextOccurrence(userID string, scheduleID string, request NextOccurrenceRequest) (*domainschedule.ScheduledTransaction, error) {
+	schedule, err := s.getOwnSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Amount == nil && request.Description == nil && request.RunAt == nil {
+		return nil, errors.New("amount, description or runAt is required")
+	}
+
+	var description *string
+	if request.Description != nil {
+		trimmed := strings.TrimSpace(*request.Description)
+		description = &trimmed
+	}
+	var runAt *time.Time
+	if request.RunAt != nil {
+		truncated := request.RunAt.Truncate(time.Second)
+		runAt = &truncated
+	}
+
+	if err := schedule.EditNextOccurrence(request.Amount, description, runAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.scheduleRepo.Update(schedule)
+}
+
+// RunDueSchedules materializes the due occurrence of every active schedule
+func (s *ScheduleService) RunDueSchedules(now time.Time) (*ScheduleRunSummary, error) {
+	schedules, err := s.scheduleRepo.GetDue(now, dueBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due schedules: %w", err)
+	}
+
+	summary := &ScheduleRunSummary{Due: len(schedules)}
+	for _, schedule := range schedules {
+		executed, paused, err := s.runOccurrence(schedule, now)
+		if err != nil {
+			fmt.Printf("Warning: Failed to run scheduled transaction %s: %v\n", schedule.ID, err)
+			continue
+		}
+		if executed {
+			summary.Executed++
+		} else {
+			summary.Failed++
+		}
+		if paused {
+			summary.Paused++
+		}
+	}
+
+	return summary, nil
+}
+
+// runOccurrence creates the transaction of the next occurrence of a schedule and moves it forward.
+// A failed occurrence is not retried: it is recorded, the user is notified and the schedule advances.
+func (s *ScheduleService) runOccurrence(schedule *domainschedule.ScheduledTransaction, now time.Time) (executed bool, paused bool, err error) {
+	occurrence := *schedule.NextOccurrenceAt
+	template := schedule.NextTransactionTemplate()
+
+	run, err := s.scheduleRepo.CreateRun(&domainschedule.ScheduledRun{
+		ScheduleID:   schedule.ID,
+		ScheduledFor: occurrence,
+		Status:       domainschedule.RunStatusProcessing,
+		Amount:       template.Amount,
+	})
+	if errors.Is(err, ErrRunAlreadyClaimed) {
+		// The occurrence was already materialized (e.g. by another instance): only move the schedule forward
+		if err := schedule.Advance(); err != nil {
+			return false, false, err
+		}
+		_, err := s.scheduleRepo.Update(schedule)
+		return true, false, err
+	}
+	if err != nil {
+		return false, false, err
+	}
+
+	transaction, createErr := s.transactionService.CreateTransaction(CreateTransactionRequest{
+		UserID:        schedule.UserID,
+		Type:          template.Type,
+		Amount:        template.Amount,
+		Currency:      template.Currency,
+		FromAccountID: template.FromAccountID,
+		ToAccountID:   template.ToAccountID,
+		FromCardID:    template.FromCardID,
+		ToCardID:      template.ToCardID,
+		Description:   template.Description,
+		MerchantName:  template.MerchantName,
+		CategoryID:    template.CategoryID,
+		ReferenceID:   fmt.Sprintf("sched_%s_%s", schedule.ID, occurrence.Format("20060102")),
+		Metadata: map[string]interface{}{
+			"source":                 "scheduler",
+			"scheduledTransactionId": schedule.ID,
+			"scheduledFor":           occurrence.Format(time.RFC3339),
+		},
+	}, schedule.UserID)
+
+	if createErr != nil {
+		run.Status = domainschedule.RunStatusFailed
+		run.Error = createErr.Error()
+		paused = schedule.RecordFailure(now, createErr.Error())
+	} else {
+		run.Status = domainschedule.RunStatusExecuted
+		run.TransactionID = &transaction.ID
+		schedule.RecordSuccess(now)
+		executed = true
+	}
+
+	if err := s.scheduleRepo.UpdateRun(run); err != nil {
+		return false, false, err
+	}
+	if err := schedule.Advance(); err != nil {
+		return false, false, err
+	}
+	if _, err := s.scheduleRepo.Update(schedule); err != nil {
+		return false, false, err
+	}
+
+	if createErr != nil {
+		s.notifyFailure(schedule, template.Amount, template.Description, occurrence, createErr.Error(), paused)
+	}
+	return executed, paused, nil
+}
+
+// notifyFailure reports a failed occurrence through the notification service
+func (s *ScheduleService) notifyFailure(schedule *domainschedule.ScheduledTransaction, amount float64, description string, occurrence time.Time, reason string, paused bool) {
+	if s.notificationService == nil {
+		return
+	}
+
+	err := s.notificationService.NotifyScheduledTransactionFailure(clients.ScheduledTransactionFailure{
+		UserID:       schedule.UserID,
+		ScheduleID:   schedule.ID,
+		Description:  description,
+		Amount:       amount,
+		Currency:     schedule.Currency,
+		ScheduledFor: occurrence,
+		Reason:       reason,
+		Paused:       paused,
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to notify failure of scheduled transaction %s: %v\n", schedule.ID, err)
+	}
+}
+
+// recordSkippedRun keeps track of an occurrence that did not create a transaction
+func (s *ScheduleService) recordSkippedRun(scheduleID string, occurrence time.Time, reason string) {
+	_, err := s.scheduleRepo.CreateRun(&domainschedule.ScheduledRun{
+		ScheduleID:   scheduleID,
+		ScheduledFor: occurrence,
+		Status:       domainschedule.RunStatusSkipped,
+		Error:        reason,
+	})
+	if err != nil && !errors.Is(err, ErrRunAlreadyClaimed) {
+		fmt.Printf("Warning: Failed to record skipped run of scheduled transaction %s: %v\n", scheduleID, err)
+	}
+}
+
+// applyRequest copies the template of the request into the schedule and validates it
+func (s *ScheduleService) applyRequest(schedule *domainschedule.ScheduledTransaction, request ScheduledTransactionRequest) error {
+	if request.CategoryID != nil {
+		category, err := s.categoryRepo.GetByID(*request.CategoryID)
+		if err != nil || !category.IsVisibleTo(schedule.UserID) {
+			return fmt.Errorf("category not found with ID: %s", *request.CategoryID)
+		}
+	}
+
+	schedule.Type = request.Type
+	schedule.Amount = request.Amount
+	schedule.Currency = request.Currency
+	schedule.FromAccountID = request.FromAccountID
+	schedule.ToAccountID = request.ToAccountID
+	schedule.FromCardID = request.FromCardID
+	schedule.ToCardID = request.ToCardID
+	schedule.Description = strings.TrimSpace(request.Description)
+	schedule.MerchantName = strings.TrimSpace(request.MerchantName)
+	schedule.CategoryID = request.CategoryID
+	schedule.Recurrence = strings.TrimSpace(request.Recurrence)
+	schedule.StartAt = request.StartAt.Truncate(time.Second) // Occurrences are stored with second precision
+
+	return schedule.Validate()
+}
+
+// getOwnSchedule loads a scheduled transaction of the user
+func (s *ScheduleService) getOwnSchedule(userID string, scheduleID string) (*domainschedule.ScheduledTransaction, error) {
+	schedule, err := s.scheduleRepo.GetByID(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.UserID != userID {
+		return nil, fmt.Errorf("scheduled transaction not found with ID: %s", scheduleID)
+	}
+	return schedule, nil
+}
+
+// validateStartAt rejects schedules that start before today
+func validateStartAt(startAt time.Time, now time.Time) error {
+	local := now.In(startAt.Location())
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, startAt.Location())
+	if startAt.Before(today) {
+		return errors.New("start date cannot be in the past")
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+// SplitService implements SplitServiceInterface
+// Splits a transaction into allocations with their own amount, category, note and counterpart
+type SplitService struct {
+	transactionRepo TransactionRepositoryInterface
+	allocationRepo  AllocationRepositoryInterface
+	categoryRepo    CategoryRepositoryInterface
+}
+
+// NewSplitService creates a new split service
+func NewSplitService(transactionRepo TransactionRepositoryInterface, allocationRepo AllocationRepositoryInterface, categoryRepo CategoryRepositoryInterface) SplitServiceInterface {
+	return &SplitService{
+		transactionRepo: transactionRepo,
+		allocationRepo:  allocationRepo,
+		categoryRepo:    categoryRepo,
+	}
+}
+
+// GetSplit returns the transaction with its allocations. The owner and the counterparts of the split can see it.
+func (s *SplitService) GetSplit(userID string, transactionID string) (*SplitResult, error) {
+	transaction, err := s.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations, err := s.allocationRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocations: %w", err)
+	}
+
+	if transaction.UserID != userID && !isCounterpart(allocations, userID) {
+		return nil, fmt.Errorf("transaction not found with ID: %s", transactionID)
+	}
+
+	return &SplitResult{Transaction: transaction, Allocations: allocations}, nil
+}
+
+// SplitTransaction replaces the allocations of a transaction owned by the user
+func (s *SplitService) SplitTransaction(userID string, transactionID string, request SplitTransactionRequest) (*SplitResult, error) {
+	transaction, err := s.getOwnTransaction(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations := make([]*domaintransaction.Allocation, 0, len(request.Allocations))
+	for i, item := range request.Allocations {
+		allocation := &domaintransaction.Allocation{
+			TransactionID: transaction.ID,
+			Amount:        item.Amount,
+			CategoryID:    item.CategoryID,
+			Note:          strings.TrimSpace(item.Note),
+		}
+		if item.CounterpartUserID != nil && strings.TrimSpace(*item.CounterpartUserID) != "" {
+			counterpart := strings.TrimSpace(*item.CounterpartUserID)
+			allocation.CounterpartUserID = &counterpart
+		}
+
+		if allocation.CategoryID != nil {
+			category, err := s.categoryRepo.GetByID(*allocation.CategoryID)
+			if err != nil || !category.IsVisibleTo(userID) {
+				return nil, fmt.Errorf("allocation %d: category not found with ID: %s", i+1, *allocation.CategoryID)
+			}
+		}
+
+		allocations = append(allocations, allocation)
+	}
+
+	if err := transaction.ValidateAllocations(allocations); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.allocationRepo.ReplaceForTransaction(transaction.ID, allocations)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SplitResult{Transaction: transaction, Allocations: saved}, nil
+}
+
+// RemoveSplit deletes the allocations of a transaction, which counts again as a whole
+func (s *SplitService) RemoveSplit(userID string, transactionID string) error {
+	if _, err := s.getOwnTransaction(userID, transactionID); err != nil {
+		return err
+	}
+	return s.allocationRepo.DeleteByTransactionID(transactionID)
+}
+
+// getOwnTransaction loads a transaction owned by the user
+func (s *SplitService) getOwnTransaction(userID string, transactionID string) (*domaintransaction.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.UserID != userID {
+		return nil, errors.New("unauthorized: only the transaction owner can split it")
+	}
+	return transaction, nil
+}
+
+func isCounterpart(allocations []*domaintransaction.Allocation, userID string) bool {
+	for _, allocation := range allocations {
+		if allocation.IsShared() && *allocation.CounterpartUserID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	domainsubscription "github.com/fintrack/transaction-service/internal/core/domain/entities/subscription"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+)
+
+const (
+	// subscriptionLookbackDays covers a full year plus the grace of yearly subscriptions
+	subscriptionLookbackDays = 400
+	// subscriptionMaxCharges bounds the transactions analyzed per user
+	subscriptionMaxCharges = 5000
+)
+
+// subscriptionChargeTypes are the debits that can belong to a subscription
+var subscriptionChargeTypes = []domaintransaction.TransactionType{
+	domaintransaction.TransactionTypeCreditCharge,
+	domaintransaction.TransactionTypeDebitPurchase,
+	domaintransaction.TransactionTypeAccountWithdraw,
+}
+
+// SubscriptionService implements SubscriptionServiceInterface
+// Detects recurring charges by merchant, amount and period and keeps a snapshot for reports and the chatbot
+type SubscriptionService struct {
+	transactionRepo  TransactionRepositoryInterface
+	subscriptionRepo SubscriptionRepositoryInterface
+}
+
+// NewSubscriptionService creates a new subscription service
+func NewSubscriptionService(transactionRepo TransactionRepositoryInterface, subscriptionRepo SubscriptionRepositoryInterface) SubscriptionServiceInterface {
+	return &SubscriptionService{
+		transactionRepo:  transactionRepo,
+		subscriptionRepo: subscriptionRepo,
+	}
+}
+
+// GetSubscriptions detects the subscriptions of the user and refreshes their snapshot
+func (s *SubscriptionService) GetSubscriptions(userID string) (*SubscriptionReport, error) {
+	now := time.Now()
+	subscriptions, err := s.detect(userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildSubscriptionReport(subscriptions, now), nil
+}
+
+// RefreshAll refreshes the snapshot of every user with recent charges
+func (s *SubscriptionService) RefreshAll(now time.Time) (int, error) {
+	userIDs, err := s.subscriptionRepo.GetUsersWithCharges(now.AddDate(0, 0, -subscriptionLookbackDays))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get users with charges: %w", err)
+	}
+
+	refreshed := 0
+	for _, userID := range userIDs {
+		if _, err := s.detect(userID, now); err != nil {
+			fmt.Printf("Warning: Failed to refresh subscriptions for user %s: %v\n", userID, err)
+			continue
+		}
+		refreshed++
+	}
+
+	return refreshed, nil
+}
+
+// detect runs the detector over the recent charges of the user and saves the result
+func (s *SubscriptionService) detect(userID string, now time.Time) ([]*domainsubscription.Subscription, error) {
+	fromDate := now.AddDate(0, 0, -subscriptionLookbackDays)
+	transactions, _, err := s.transactionRepo.GetByUserID(userID, TransactionFilters{
+		Types:    subscriptionChargeTypes,
+		Statuses: []domaintransaction.TransactionStatus{domaintransaction.TransactionStatusCompleted},
+		FromDate: &fromDate,
+		Limit:    subscriptionMaxCharges,
+		OrderBy:  "created_at",
+		Order:    "asc",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	charges := make([]domainsubscription.Charge, 0, len(transactions))
+	for _, transaction := range transactions {
+		// Charges of other members on shared accounts are not subscriptions of this user
+		if transaction.UserID != userID {
+			continue
+		}
+		merchant := transaction.MerchantName
+		if merchant == "" {
+			merchant = transaction.Description
+		}
+		charges = append(charges, domainsubscription.Charge{
+			TransactionID: transaction.ID,
+			Merchant:      merchant,
+			Amount:        transaction.Amount,
+			Currency:      transaction.Currency,
+			Date:          transaction.CreatedAt,
+			CardID:        transaction.FromCardID,
+			AccountID:     transaction.FromAccountID,
+			CategoryID:    transaction.CategoryID,
+		})
+	}
+
+	subscriptions := domainsubscription.Detect(charges, now, domainsubscription.DefaultDetectorConfig)
+	for _, subscription := range subscriptions {
+		subscription.UserID = userID
+	}
+
+	if err := s.subscriptionRepo.ReplaceForUser(userID, subscriptions); err != nil {
+		return nil, fmt.Errorf("failed to save subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// buildSubscriptionReport counts the subscriptions by status and adds up the cost of the ones still charging
+func buildSubscriptionReport(subscriptions []*domainsubscription.Subscription, now time.Time) *SubscriptionReport {
+	report := &SubscriptionReport{
+		Subscriptions:  subscriptions,
+		MonthlyCost:    map[string]float64{},
+		AnnualizedCost: map[string]float64{},
+		GeneratedAt:    now,
+	}
+	if report.Subscriptions == nil {
+		report.Subscriptions = []*domainsubscription.Subscription{}
+	}
+
+	for _, subscription := range subscriptions {
+		switch subscription.Status {
+		case domainsubscription.StatusActive:
+			report.ActiveCount++
+		case domainsubscription.StatusMissed:
+			report.MissedCount++
+		case domainsubscription.StatusCancelled:
+			report.CancelledCount++
+		}
+
+		if change := subscription.LatestPriceChange(); change != nil && change.IsIncrease() {
+			report.PriceIncreases++
+		}
+
+		if subscription.IsCharging() {
+			report.AnnualizedCost[subscription.Currency] += subscription.AnnualizedCost
+		}
+	}
+
+	for currency, annualized := range report.AnnualizedCost {
+		report.AnnualizedCost[currency] = math.Round(annualized*100) / 100
+		report.MonthlyCost[currency] = math.Round(annualized/12*100) / 100
+	}
+
+	return report
+}
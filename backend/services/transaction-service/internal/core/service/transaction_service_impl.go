@@ -3,7 +3,9 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strings"
 
+	domainfraud "github.com/fintrack/transaction-service/internal/core/domain/entities/fraud"
 	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
 	"github.com/fintrack/transaction-service/internal/core/interfaces"
 )
@@ -16,8 +18,16 @@ type TransactionService struct {
 	auditService    TransactionAuditServiceInterface
 	externalService ExternalServiceInterface
 	accountService  interfaces.AccountServiceInterface
+	categoryService CategoryServiceInterface
+	fraudService    FraudServiceInterface
 }
 
+// ErrTransactionBlocked is returned when the fraud checks block a transaction
+var ErrTransactionBlocked = errors.New("transaction blocked by the fraud checks")
+
+// fraudHoldMetadataKey marks the transactions held by the fraud checks until the user releases them
+const fraudHoldMetadataKey = "fraudHold"
+
 // NewTransactionService creates a new transaction service instance
 // Dependency injection is used to promote testability and loose coupling (Dependency Inversion Principle)
 func NewTransactionService(
@@ -26,6 +36,8 @@ func NewTransactionService(
 	auditService TransactionAuditServiceInterface,
 	externalService ExternalServiceInterface,
 	accountService interfaces.AccountServiceInterface,
+	categoryService CategoryServiceInterface,
+	fraudService FraudServiceInterface,
 ) TransactionServiceInterface {
 	return &TransactionService{
 		transactionRepo: transactionRepo,
@@ -33,6 +45,8 @@ func NewTransactionService(
 		auditService:    auditService,
 		externalService: externalService,
 		accountService:  accountService,
+		categoryService: categoryService,
+		fraudService:    fraudService,
 	}
 }
 
@@ -59,6 +73,8 @@ func (s *TransactionService) CreateTransaction(request CreateTransactionRequest,
 		PaymentMethod: request.PaymentMethod,
 		MerchantName:  request.MerchantName,
 		MerchantID:    request.MerchantID,
+		Notes:         request.Notes,
+		CategoryID:    request.CategoryID,
 		ReferenceID:   request.ReferenceID,
 		ExternalID:    request.ExternalID,
 		Metadata:      request.Metadata,
@@ -70,6 +86,11 @@ func (s *TransactionService) CreateTransaction(request CreateTransactionRequest,
 		return nil, fmt.Errorf("transaction validation failed: %w", err)
 	}
 
+	// Assign the chosen category or the one of the first matching categorization rule
+	if err := s.categoryService.CategorizeTransaction(transaction); err != nil {
+		return nil, fmt.Errorf("transaction validation failed: %w", err)
+	}
+
 	// Perform pre-transaction validations based on transaction type
 	if err := s.performPreTransactionValidations(transaction); err != nil {
 		transaction.Status = domaintransaction.TransactionStatusFailed
@@ -79,24 +100,40 @@ func (s *TransactionService) CreateTransaction(request CreateTransactionRequest,
 		return nil, fmt.Errorf("pre-transaction validation failed: %w", err)
 	}
 
+	// Check if this is a record-only transaction (balance already updated by another service)
+	recordOnly := isRecordOnly(transaction)
+
+	// Score the fraud and anomaly risk against the history of the user
+	riskScore := s.scoreTransaction(transaction, recordOnly)
+	if riskScore != nil && riskScore.Action == domainfraud.ActionBlock {
+		transaction.Status = domaintransaction.TransactionStatusFailed
+		transaction.FailureReason = fmt.Sprintf("%s: %s", ErrTransactionBlocked, riskReasons(riskScore))
+		// Save the blocked transaction for audit purposes
+		if blockedTransaction, err := s.transactionRepo.Create(transaction); err == nil {
+			s.recordRiskScore(blockedTransaction, riskScore)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrTransactionBlocked, riskReasons(riskScore))
+	}
+	held := riskScore != nil && riskScore.Action == domainfraud.ActionHold
+	if held {
+		if transaction.Metadata == nil {
+			transaction.Metadata = make(map[string]interface{})
+		}
+		transaction.Metadata[fraudHoldMetadataKey] = true
+	}
+
 	// Save transaction in PENDING status
 	savedTransaction, err := s.transactionRepo.Create(transaction)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
+	if riskScore != nil {
+		s.recordRiskScore(savedTransaction, riskScore)
+	}
 
-	// Check if this is a record-only transaction (balance already updated by another service)
-	recordOnly := false
-	if savedTransaction.Metadata != nil {
-		if recordOnlyValue, exists := savedTransaction.Metadata["recordOnly"]; exists {
-			// Handle both boolean and string values
-			switch v := recordOnlyValue.(type) {
-			case bool:
-				recordOnly = v
-			case string:
-				recordOnly = v == "true"
-			}
-		}
+	// A held transaction stays pending without moving money until the user releases it
+	if held {
+		return savedTransaction, nil
 	}
 
 	// Execute the transaction (update balances) only if not record-only
@@ -136,6 +173,88 @@ func (s *TransactionService) CreateTransaction(request CreateTransactionRequest,
 	return updatedTransaction, nil
 }
 
+// ReleaseHeldTransaction executes a transaction held by the fraud checks once its owner confirms it.
+// The balances are validated again since they may have changed while it was held.
+func (s *TransactionService) ReleaseHeldTransaction(id string, userID string) (*domaintransaction.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.UserID != userID {
+		if transaction.InitiatedBy == userID {
+			return nil, fmt.Errorf("unauthorized: only the account owner can release transaction %s", id)
+		}
+		return nil, fmt.Errorf("transaction not found with ID: %s", id)
+	}
+	if !isHeld(transaction) || transaction.Status != domaintransaction.TransactionStatusPending {
+		return nil, fmt.Errorf("transaction %s is not held by the fraud checks", id)
+	}
+
+	delete(transaction.Metadata, fraudHoldMetadataKey)
+	transaction.Metadata["fraudReleasedBy"] = userID
+
+	if err := s.performPreTransactionValidations(transaction); err != nil {
+		transaction.Status = domaintransaction.TransactionStatusFailed
+		transaction.FailureReason = err.Error()
+		s.transactionRepo.Update(transaction)
+		return nil, fmt.Errorf("pre-transaction validation failed: %w", err)
+	}
+
+	if err := s.executeTransaction(transaction); err != nil {
+		transaction.Status = domaintransaction.TransactionStatusFailed
+		transaction.FailureReason = err.Error()
+		s.transactionRepo.Update(transaction)
+		return nil, fmt.Errorf("transaction execution failed: %w", err)
+	}
+
+	transaction.Status = domaintransaction.TransactionStatusCompleted
+	releasedTransaction, err := s.transactionRepo.Update(transaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update transaction status: %w", err)
+	}
+
+	oldStatus := domaintransaction.TransactionStatusPending
+	newStatus := domaintransaction.TransactionStatusCompleted
+	if err := s.auditService.LogTransactionChange(
+		releasedTransaction.ID,
+		"release_transaction",
+		&oldStatus,
+		&newStatus,
+		userID,
+		"Transaction held by the fraud checks released by its owner",
+	); err != nil {
+		fmt.Printf("Warning: Failed to log transaction %s for audit: %v\n", releasedTransaction.ID, err)
+	}
+
+	return releasedTransaction, nil
+}
+
+// scoreTransaction returns the risk score of a transaction, nil when it is not scored. The money of
+// record-only transactions already moved, so they are only alerted. A scoring error does not stop the
+// transaction.
+func (s *TransactionService) scoreTransaction(transaction *domaintransaction.Transaction, recordOnly bool) *domainfraud.Score {
+	if s.fraudService == nil {
+		return nil
+	}
+
+	score, err := s.fraudService.ScoreTransaction(transaction)
+	if err != nil {
+		fmt.Printf("Warning: Failed to score risk of transaction for user %s: %v\n", transaction.UserID, err)
+		return nil
+	}
+	if score != nil && recordOnly && (score.Action == domainfraud.ActionHold || score.Action == domainfraud.ActionBlock) {
+		score.Action = domainfraud.ActionAlert
+	}
+	return score
+}
+
+// recordRiskScore persists the risk score of a saved transaction
+func (s *TransactionService) recordRiskScore(transaction *domaintransaction.Transaction, score *domainfraud.Score) {
+	if err := s.fraudService.RecordScore(transaction, score); err != nil {
+		fmt.Printf("Warning: Failed to record risk score of transaction %s: %v\n", transaction.ID, err)
+	}
+}
+
 // performPreTransactionValidations validates that the transaction can be executed
 func (s *TransactionService) performPreTransactionValidations(transaction *domaintransaction.Transaction) error {
 	switch transaction.Type {
@@ -152,6 +271,33 @@ func (s *TransactionService) performPreTransactionValidations(transaction *domai
 	}
 }
 
+// isRecordOnly checks if the balance of a transaction was already updated by another service
+func isRecordOnly(transaction *domaintransaction.Transaction) bool {
+	// Handle both boolean and string values
+	switch v := transaction.Metadata["recordOnly"].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+// isHeld checks if a transaction is held by the fraud checks
+func isHeld(transaction *domaintransaction.Transaction) bool {
+	held, _ := transaction.Metadata[fraudHoldMetadataKey].(bool)
+	return held
+}
+
+// riskReasons joins the details of the signals of a risk score
+func riskReasons(score *domainfraud.Score) string {
+	details := make([]string, len(score.Reasons))
+	for i, reason := range score.Reasons {
+		details[i] = reason.Detail
+	}
+	return strings.Join(details, "; ")
+}
+
 // Helper functions for safe pointer handling
 func stringValue(s *string) string {
 	if s == nil {
@@ -408,9 +554,15 @@ func (s *TransactionService) GetTransactionByID(id string, userID string) (*doma
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
 
-	// Check if user has permission to view this transaction
-	if transaction.UserID != userID {
-		return nil, errors.New("unauthorized: user does not have permission to view this transaction")
+	// Owners and the members who performed it can always see it; other members as in the account listings
+	if transaction.UserID != userID && transaction.InitiatedBy != userID {
+		visible, err := s.transactionRepo.IsVisibleTo(id, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			return nil, errors.New("unauthorized: user does not have permission to view this transaction")
+		}
 	}
 
 	return transaction, nil
@@ -471,6 +623,9 @@ func (s *TransactionService) ProcessTransaction(id string, processedBy string) e
 	if transaction.Status != domaintransaction.TransactionStatusPending {
 		return fmt.Errorf("transaction is not in pending status, current status: %s", transaction.Status)
 	}
+	if isHeld(transaction) {
+		return fmt.Errorf("transaction %s is held by the fraud checks and has to be released by its owner", id)
+	}
 
 	// Mark as completed - simplified for this version
 	_, err = s.UpdateTransactionStatus(id, domaintransaction.TransactionStatusCompleted, "Transaction processed successfully", processedBy)
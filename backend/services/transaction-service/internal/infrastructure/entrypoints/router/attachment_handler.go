@@ -0,0 +1,267 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	domainattachment "github.com/fintrack/transaction-service/internal/core/domain/entities/attachment"
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+	"github.com/fintrack/transaction-service/internal/infrastructure/storage"
+)
+
+// attachmentFormOverhead is the room left in an upload request for the multipart boundaries and headers
+const attachmentFormOverhead = 1 << 20
+
+// AttachmentHandler handles HTTP requests for the receipts and invoices of transactions and installment plans
+type AttachmentHandler struct {
+	attachmentService service.AttachmentServiceInterface
+	maxFileSize       int64
+}
+
+// NewAttachmentHandler creates a new attachment handler. The storage driver is chosen with
+// ATTACHMENT_STORAGE (local by default, or s3).
+func NewAttachmentHandler(db *sql.DB) *AttachmentHandler {
+	attachmentStorage, err := newAttachmentStorage()
+	if err != nil {
+		log.Fatalf("Failed to initialize attachment storage: %v", err)
+	}
+
+	limits := service.AttachmentLimits{
+		MaxFileSize: megabytesFromEnv("ATTACHMENT_MAX_SIZE_MB", service.DefaultAttachmentMaxFileSize),
+		UserQuota:   megabytesFromEnv("ATTACHMENT_QUOTA_MB", service.DefaultAttachmentUserQuota),
+	}
+
+	attachmentService := service.NewAttachmentService(
+		mysql.NewAttachmentRepository(db),
+		mysql.NewTransactionRepository(db),
+		attachmentStorage,
+		limits,
+	)
+
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+		maxFileSize:       limits.MaxFileSize,
+	}
+}
+
+// newAttachmentStorage creates the storage driver configured in the environment
+func newAttachmentStorage() (service.AttachmentStorageInterface, error) {
+	if strings.ToLower(os.Getenv("ATTACHMENT_STORAGE")) == "s3" {
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			Region:          os.Getenv("S3_REGION"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		})
+	}
+
+	directory := "data/attachments"
+	if dir := os.Getenv("ATTACHMENT_LOCAL_DIR"); dir != "" {
+		directory = dir
+	}
+	return storage.NewLocalStorage(directory)
+}
+
+// megabytesFromEnv reads a size in MB from the environment, in bytes
+func megabytesFromEnv(name string, defaultBytes int64) int64 {
+	if value := os.Getenv(name); value != "" {
+		if megabytes, err := strconv.Atoi(value); err == nil && megabytes > 0 {
+			return int64(megabytes) << 20
+		}
+	}
+	return defaultBytes
+}
+
+// AttachmentService exposes the attachment service to the background cleaner
+func (h *AttachmentHandler) AttachmentService() service.AttachmentServiceInterface {
+	return h.attachmentService
+}
+
+// UploadTransactionAttachmentHTTP attaches a file to a transaction
+func (h *AttachmentHandler) UploadTransactionAttachmentHTTP(w http.ResponseWriter, r *http.Request) {
+	h.upload(w, r, domainattachment.OwnerTransaction)
+}
+
+// UploadInstallmentPlanAttachmentHTTP attaches a file to an installment plan
+func (h *AttachmentHandler) UploadInstallmentPlanAttachmentHTTP(w http.ResponseWriter, r *http.Request) {
+	h.upload(w, r, domainattachment.OwnerInstallmentPlan)
+}
+
+// ListTransactionAttachmentsHTTP lists the attachments of a transaction
+func (h *AttachmentHandler) ListTransactionAttachmentsHTTP(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, domainattachment.OwnerTransaction)
+}
+
+// ListInstallmentPlanAttachmentsHTTP lists the attachments of an installment plan
+func (h *AttachmentHandler) ListInstallmentPlanAttachmentsHTTP(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, domainattachment.OwnerInstallmentPlan)
+}
+
+// upload reads the multipart field "file" and stores it for the record of the path
+func (h *AttachmentHandler) upload(w http.ResponseWriter, r *http.Request, ownerType domainattachment.OwnerType) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxFileSize+attachmentFormOverhead)
+	if err := r.ParseMultipartForm(attachmentFormOverhead); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid upload", err.Error())
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid upload", "file is required")
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.Upload(userID, service.UploadAttachmentRequest{
+		OwnerType: ownerType,
+		OwnerID:   r.PathValue("id"),
+		FileName:  header.Filename,
+		Size:      header.Size,
+		Content:   file,
+	})
+	if err != nil {
+		h.writeServiceError(w, "Failed to upload attachment", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, attachment)
+}
+
+func (h *AttachmentHandler) list(w http.ResponseWriter, r *http.Request, ownerType domainattachment.OwnerType) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	attachments, err := h.attachmentService.GetAttachments(userID, ownerType, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get attachments", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, attachments)
+}
+
+// GetAttachmentHTTP returns the metadata of an attachment
+func (h *AttachmentHandler) GetAttachmentHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get attachment", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, attachment)
+}
+
+// DownloadAttachmentHTTP streams the content of an attachment as a download
+func (h *AttachmentHandler) DownloadAttachmentHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	attachment, content, err := h.attachmentService.OpenContent(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to download attachment", err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content); err != nil {
+		log.Printf("Warning: download of attachment %s interrupted: %v", attachment.ID, err)
+	}
+}
+
+// DeleteAttachmentHTTP deletes an attachment and its content
+func (h *AttachmentHandler) DeleteAttachmentHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(userID, r.PathValue("id")); err != nil {
+		h.writeServiceError(w, "Failed to delete attachment", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetUsageHTTP returns the storage the user takes with attachments and their quota
+func (h *AttachmentHandler) GetUsageHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	usage, err := h.attachmentService.GetUsage(userID)
+	if err != nil {
+		h.writeServiceError(w, "Failed to get attachment usage", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, usage)
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *AttachmentHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *AttachmentHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *AttachmentHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
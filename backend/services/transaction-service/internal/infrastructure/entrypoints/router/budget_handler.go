@@ -0,0 +1,232 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/http/clients"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// BudgetHandler handles HTTP requests for monthly budgets and their progress against actual spending
+type BudgetHandler struct {
+	budgetService service.BudgetServiceInterface
+}
+
+// NewBudgetHandler creates a new budget handler
+func NewBudgetHandler(db *sql.DB) *BudgetHandler {
+	// Create notification service client - using environment variable or default localhost
+	notificationServiceURL := "http://localhost:8088"
+	if url := os.Getenv("NOTIFICATION_SERVICE_URL"); url != "" {
+		notificationServiceURL = url
+	}
+
+	budgetService := service.NewBudgetService(
+		mysql.NewBudgetRepository(db),
+		mysql.NewCategoryRepository(db),
+		mysql.NewLabelRepository(db),
+		clients.NewNotificationClient(notificationServiceURL),
+	)
+
+	return &BudgetHandler{
+		budgetService: budgetService,
+	}
+}
+
+// BudgetService exposes the budget service to the background refresher
+func (h *BudgetHandler) BudgetService() service.BudgetServiceInterface {
+	return h.budgetService
+}
+
+// CreateBudgetHTTP creates a budget for a category, a merchant or a card
+func (h *BudgetHandler) CreateBudgetHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var request service.BudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	budget, err := h.budgetService.CreateBudget(userID, request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to create budget", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, budget)
+}
+
+// ListBudgetsHTTP returns the budgets of the user (?month=YYYY-MM for the ones that apply to a month)
+func (h *BudgetHandler) ListBudgetsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	budgets, err := h.budgetService.GetBudgets(userID, r.URL.Query().Get("month"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to list budgets", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, budgets)
+}
+
+// GetBudgetHTTP returns a budget
+func (h *BudgetHandler) GetBudgetHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	budget, err := h.budgetService.GetBudget(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get budget", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, budget)
+}
+
+// UpdateBudgetHTTP replaces the settings of a budget
+func (h *BudgetHandler) UpdateBudgetHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var request service.BudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	budget, err := h.budgetService.UpdateBudget(userID, r.PathValue("id"), request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to update budget", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, budget)
+}
+
+// DeleteBudgetHTTP deletes a budget
+func (h *BudgetHandler) DeleteBudgetHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	if err := h.budgetService.DeleteBudget(userID, r.PathValue("id")); err != nil {
+		h.writeServiceError(w, "Failed to delete budget", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetProgressHTTP returns a budget against the actual spending of a month (?month=YYYY-MM, the current one by default)
+func (h *BudgetHandler) GetProgressHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	period, err := h.budgetService.GetProgress(userID, r.PathValue("id"), r.URL.Query().Get("month"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get budget progress", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, period)
+}
+
+// GetHistoryHTTP returns a budget against the actual spending of its last months (?months=)
+func (h *BudgetHandler) GetHistoryHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	months := 0
+	if value := r.URL.Query().Get("months"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", "months must be a number")
+			return
+		}
+		months = parsed
+	}
+
+	periods, err := h.budgetService.GetHistory(userID, r.PathValue("id"), months)
+	if err != nil {
+		h.writeServiceError(w, "Failed to get budget history", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, periods)
+}
+
+// GetSummaryHTTP returns every budget of a month against the actual spending (?month=YYYY-MM)
+func (h *BudgetHandler) GetSummaryHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	summary, err := h.budgetService.GetSummary(userID, r.URL.Query().Get("month"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get budget summary", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, summary)
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *BudgetHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *BudgetHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *BudgetHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
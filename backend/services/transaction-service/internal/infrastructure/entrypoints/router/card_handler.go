@@ -34,6 +34,13 @@ func NewCardHandler(db *sql.DB) *CardHandler {
 	}
 	accountClient := clients.NewAccountClient(accountServiceURL)
 
+	// Create category service for auto-categorization
+	categoryService := service.NewCategoryService(
+		mysql.NewCategoryRepository(db),
+		mysql.NewCategorizationRuleRepository(db),
+		transactionRepo,
+	)
+
 	// Create transaction service
 	transactionService := service.NewTransactionService(
 		transactionRepo,
@@ -41,6 +48,8 @@ func NewCardHandler(db *sql.DB) *CardHandler {
 		auditService,
 		externalService,
 		accountClient,
+		categoryService,
+		newFraudService(db),
 	)
 
 	return &CardHandler{
@@ -85,6 +94,12 @@ type CardTransactionResponse struct {
 
 // ChargeCreditCardHTTP handles HTTP requests for credit card charges
 func (h *CardHandler) ChargeCreditCardHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
 	var request CreditCardChargeRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -103,6 +118,7 @@ func (h *CardHandler) ChargeCreditCardHTTP(w http.ResponseWriter, r *http.Reques
 		Amount:      request.Amount,
 		Description: request.Description,
 		Reference:   request.MerchantName,
+		PerformedBy: userID,
 	}
 
 	// Process the charge using AccountClient
@@ -114,7 +130,7 @@ func (h *CardHandler) ChargeCreditCardHTTP(w http.ResponseWriter, r *http.Reques
 
 	// Create transaction record using existing TransactionService method
 	createRequest := service.CreateTransactionRequest{
-		UserID:       "system", // TODO: Extract from card info
+		UserID:       userID,
 		Type:         "CREDIT_CARD_CHARGE",
 		Amount:       request.Amount,
 		Description:  request.Description,
@@ -154,6 +170,12 @@ func (h *CardHandler) ChargeCreditCardHTTP(w http.ResponseWriter, r *http.Reques
 
 // PayCreditCardHTTP handles HTTP requests for credit card payments
 func (h *CardHandler) PayCreditCardHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
 	var request CreditCardPaymentRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -171,6 +193,7 @@ func (h *CardHandler) PayCreditCardHTTP(w http.ResponseWriter, r *http.Request)
 		CardID:        request.CardID,
 		Amount:        request.Amount,
 		PaymentMethod: request.PaymentMethod,
+		PerformedBy:   userID,
 	}
 
 	// Process the payment using AccountClient
@@ -182,7 +205,7 @@ func (h *CardHandler) PayCreditCardHTTP(w http.ResponseWriter, r *http.Request)
 
 	// Create transaction record using existing TransactionService method
 	createRequest := service.CreateTransactionRequest{
-		UserID:      "system", // TODO: Extract from card info
+		UserID:      userID,
 		Type:        "CREDIT_CARD_PAYMENT",
 		Amount:      request.Amount,
 		Description: "Credit card payment",
@@ -221,6 +244,12 @@ func (h *CardHandler) PayCreditCardHTTP(w http.ResponseWriter, r *http.Request)
 
 // ProcessDebitCardTransactionHTTP handles HTTP requests for debit card transactions
 func (h *CardHandler) ProcessDebitCardTransactionHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
 	var request DebitCardTransactionRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -239,6 +268,7 @@ func (h *CardHandler) ProcessDebitCardTransactionHTTP(w http.ResponseWriter, r *
 		Amount:       request.Amount,
 		Description:  request.Description,
 		MerchantName: request.MerchantName,
+		PerformedBy:  userID,
 	}
 
 	// Process the debit transaction using AccountClient
@@ -250,7 +280,7 @@ func (h *CardHandler) ProcessDebitCardTransactionHTTP(w http.ResponseWriter, r *
 
 	// Create transaction record using existing TransactionService method
 	createRequest := service.CreateTransactionRequest{
-		UserID:       "system", // TODO: Extract from card info
+		UserID:       userID,
 		Type:         "DEBIT_CARD_TRANSACTION",
 		Amount:       request.Amount,
 		Description:  request.Description,
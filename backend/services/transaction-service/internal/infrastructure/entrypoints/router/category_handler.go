@@ -0,0 +1,268 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// CategoryHandler handles HTTP requests for transaction categories and categorization rules
+type CategoryHandler struct {
+	categoryService service.CategoryServiceInterface
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(db *sql.DB) *CategoryHandler {
+	categoryService := service.NewCategoryService(
+		mysql.NewCategoryRepository(db),
+		mysql.NewCategorizationRuleRepository(db),
+		mysql.NewTransactionRepository(db),
+	)
+
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+// GetCategoriesHTTP returns the category tree of the user: default categories plus their own
+func (h *CategoryHandler) GetCategoriesHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	tree, err := h.categoryService.GetCategoryTree(userID)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get categories", err.Error())
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{"categories": tree})
+}
+
+// CreateCategoryHTTP creates a custom category
+func (h *CategoryHandler) CreateCategoryHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(userID, req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to create category", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, category)
+}
+
+// UpdateCategoryHTTP renames a custom category or changes its icon
+func (h *CategoryHandler) UpdateCategoryHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(userID, r.PathValue("id"), req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to update category", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, category)
+}
+
+// DeleteCategoryHTTP deletes a custom category
+func (h *CategoryHandler) DeleteCategoryHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(userID, r.PathValue("id")); err != nil {
+		h.writeServiceError(w, "Failed to delete category", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetRulesHTTP returns the categorization rules of the user
+func (h *CategoryHandler) GetRulesHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	rules, err := h.categoryService.GetRules(userID)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get categorization rules", err.Error())
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{"rules": rules})
+}
+
+// CreateRuleHTTP creates a categorization rule
+func (h *CategoryHandler) CreateRuleHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.CategorizationRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	rule, err := h.categoryService.CreateRule(userID, req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to create categorization rule", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, rule)
+}
+
+// UpdateRuleHTTP updates a categorization rule
+func (h *CategoryHandler) UpdateRuleHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.CategorizationRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	rule, err := h.categoryService.UpdateRule(userID, r.PathValue("id"), req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to update categorization rule", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, rule)
+}
+
+// DeleteRuleHTTP deletes a categorization rule
+func (h *CategoryHandler) DeleteRuleHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	if err := h.categoryService.DeleteRule(userID, r.PathValue("id")); err != nil {
+		h.writeServiceError(w, "Failed to delete categorization rule", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReapplyRulesHTTP runs the categorization rules over the past transactions of the user
+func (h *CategoryHandler) ReapplyRulesHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.ReapplyRulesRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+			return
+		}
+	}
+
+	result, err := h.categoryService.ReapplyRules(userID, req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to reapply categorization rules", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// RecategorizeTransactionHTTP changes the category of a transaction by hand
+func (h *CategoryHandler) RecategorizeTransactionHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.RecategorizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := h.categoryService.RecategorizeTransaction(userID, r.PathValue("id"), req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to recategorize transaction", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// Helper methods
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *CategoryHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *CategoryHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *CategoryHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
@@ -0,0 +1,128 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// DuplicateHandler handles HTTP requests for duplicate transaction detection and merge
+type DuplicateHandler struct {
+	duplicateService service.DuplicateServiceInterface
+}
+
+// NewDuplicateHandler creates a new duplicate handler
+func NewDuplicateHandler(db *sql.DB) *DuplicateHandler {
+	duplicateService := service.NewDuplicateService(
+		mysql.NewTransactionRepository(db),
+		mysql.NewDuplicateRepository(db),
+	)
+
+	return &DuplicateHandler{
+		duplicateService: duplicateService,
+	}
+}
+
+// ScanDuplicatesHTTP groups the transactions of the user that look like duplicates
+// (?fromDate=, ?toDate=, ?windowMinutes=)
+func (h *DuplicateHandler) ScanDuplicatesHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	query := r.URL.Query()
+	var request service.DuplicateScanRequest
+	if value := query.Get("fromDate"); value != "" {
+		fromDate, err := parseFilterDate(value, false)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+			return
+		}
+		request.FromDate = &fromDate
+	}
+	if value := query.Get("toDate"); value != "" {
+		toDate, err := parseFilterDate(value, true)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+			return
+		}
+		request.ToDate = &toDate
+	}
+	if value := query.Get("windowMinutes"); value != "" {
+		windowMinutes, err := strconv.Atoi(value)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", "windowMinutes must be a number")
+			return
+		}
+		request.WindowMinutes = windowMinutes
+	}
+
+	result, err := h.duplicateService.ScanDuplicates(userID, request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to scan duplicates", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// MergeDuplicatesHTTP merges duplicates into the transaction to keep
+func (h *DuplicateHandler) MergeDuplicatesHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var request service.MergeDuplicatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	transaction, err := h.duplicateService.MergeDuplicates(userID, request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to merge transactions", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, transaction)
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *DuplicateHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *DuplicateHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *DuplicateHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
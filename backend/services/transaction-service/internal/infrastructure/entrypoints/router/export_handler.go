@@ -0,0 +1,139 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	domainexport "github.com/fintrack/transaction-service/internal/core/domain/entities/export"
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// ExportHandler handles HTTP requests for transaction exports
+type ExportHandler struct {
+	exportService service.ExportServiceInterface
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(db *sql.DB) *ExportHandler {
+	exportService := service.NewExportService(
+		mysql.NewTransactionRepository(db),
+		mysql.NewCategoryRepository(db),
+		mysql.NewLabelRepository(db),
+		mysql.NewAttachmentRepository(db),
+	)
+
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportTransactionsHTTP streams the transactions of the list endpoint as a file (?format=csv|ofx|jsonl,
+// csv by default). It accepts the same filters as the list and ignores the pagination.
+func (h *ExportHandler) ExportTransactionsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = domainexport.FormatCSV
+	}
+	contentType, extension, err := domainexport.ContentType(format)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	filters, err := parseTransactionFilters(r)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
+
+	// The headers are sent with the first bytes of the file, so errors before it still get a JSON response
+	output := &exportResponseWriter{
+		ResponseWriter: w,
+		contentType:    contentType,
+		fileName:       fmt.Sprintf("transactions-%s.%s", time.Now().Format("20060102"), extension),
+	}
+
+	err = h.exportService.ExportTransactions(userID, service.ExportRequest{Format: format, Filters: filters}, output)
+	if err != nil {
+		if !output.started {
+			h.writeServiceError(w, "Failed to export transactions", err)
+			return
+		}
+		log.Printf("Warning: export of transactions for user %s interrupted: %v", userID, err)
+		return
+	}
+
+	if !output.started {
+		output.start()
+	}
+}
+
+// exportResponseWriter sets the file headers right before the first write
+type exportResponseWriter struct {
+	http.ResponseWriter
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (e *exportResponseWriter) start() {
+	e.started = true
+	e.Header().Set("Content-Type", e.contentType)
+	e.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.fileName))
+	e.WriteHeader(http.StatusOK)
+}
+
+// Write writes part of the file and flushes it to the client
+func (e *exportResponseWriter) Write(data []byte) (int, error) {
+	if !e.started {
+		e.start()
+	}
+	n, err := e.ResponseWriter.Write(data)
+	if flusher, ok := e.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *ExportHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *ExportHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *ExportHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
@@ -0,0 +1,169 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	domainfraud "github.com/fintrack/transaction-service/internal/core/domain/entities/fraud"
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/http/clients"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// FraudHandler handles HTTP requests for the risk scoring settings and the scores of transactions
+type FraudHandler struct {
+	fraudService service.FraudServiceInterface
+}
+
+// NewFraudHandler creates a new fraud handler
+func NewFraudHandler(db *sql.DB) *FraudHandler {
+	return &FraudHandler{
+		fraudService: newFraudService(db),
+	}
+}
+
+// newFraudService creates the fraud service shared by the handlers that create transactions
+func newFraudService(db *sql.DB) service.FraudServiceInterface {
+	// Create notification service client - using environment variable or default localhost
+	notificationServiceURL := "http://localhost:8088"
+	if url := os.Getenv("NOTIFICATION_SERVICE_URL"); url != "" {
+		notificationServiceURL = url
+	}
+
+	return service.NewFraudService(
+		mysql.NewTransactionRepository(db),
+		mysql.NewFraudRepository(db),
+		mysql.NewLabelRepository(db),
+		clients.NewNotificationClient(notificationServiceURL),
+	)
+}
+
+// GetSettingsHTTP returns the risk scoring settings of the user, the default ones until they are changed
+func (h *FraudHandler) GetSettingsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	settings, err := h.fraudService.GetSettings(userID)
+	if err != nil {
+		h.writeServiceError(w, "Failed to get fraud settings", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, settings)
+}
+
+// UpdateSettingsHTTP changes the thresholds of the risk scoring of the user
+func (h *FraudHandler) UpdateSettingsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var request service.FraudSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	settings, err := h.fraudService.UpdateSettings(userID, request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to update fraud settings", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, settings)
+}
+
+// ListScoresHTTP lists the risk scores of the transactions of the user (?minScore=, ?action=, ?limit=)
+func (h *FraudHandler) ListScoresHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	query := r.URL.Query()
+	request := service.RiskScoreListRequest{
+		Action: domainfraud.Action(query.Get("action")),
+	}
+	if value := query.Get("minScore"); value != "" {
+		minScore, err := strconv.Atoi(value)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", "minScore must be a number")
+			return
+		}
+		request.MinScore = minScore
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", "limit must be a number")
+			return
+		}
+		request.Limit = limit
+	}
+
+	scores, err := h.fraudService.GetScores(userID, request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to list risk scores", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, scores)
+}
+
+// GetTransactionScoreHTTP returns the risk score of a transaction with its reasons
+func (h *FraudHandler) GetTransactionScoreHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	score, err := h.fraudService.GetTransactionScore(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get risk score", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, score)
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *FraudHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *FraudHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *FraudHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
@@ -0,0 +1,283 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// GroupHandler handles HTTP requests for groups of users sharing expenses
+type GroupHandler struct {
+	groupService service.GroupServiceInterface
+}
+
+// NewGroupHandler creates a new shared expense group handler. It splits transactions with the split
+// service and settles up with the transfers between users.
+func NewGroupHandler(db *sql.DB, splitService service.SplitServiceInterface, p2pService service.P2PServiceInterface) *GroupHandler {
+	groupService := service.NewGroupService(
+		mysql.NewGroupRepository(db),
+		mysql.NewTransactionRepository(db),
+		splitService,
+		p2pService,
+	)
+
+	return &GroupHandler{
+		groupService: groupService,
+	}
+}
+
+// CreateGroupHTTP creates a group owned by the user
+func (h *GroupHandler) CreateGroupHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var request service.CreateGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	group, err := h.groupService.CreateGroup(userID, request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to create group", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, group)
+}
+
+// ListGroupsHTTP returns the groups of the user
+func (h *GroupHandler) ListGroupsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	groups, err := h.groupService.GetGroups(userID)
+	if err != nil {
+		h.writeServiceError(w, "Failed to list groups", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, groups)
+}
+
+// GetGroupHTTP returns a group with its members
+func (h *GroupHandler) GetGroupHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	group, err := h.groupService.GetGroup(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get group", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, group)
+}
+
+// AddMemberHTTP adds a user to the group by email or alias
+func (h *GroupHandler) AddMemberHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var request service.AddGroupMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	group, err := h.groupService.AddMember(userID, r.PathValue("id"), request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to add member", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, group)
+}
+
+// RemoveMemberHTTP removes a member from the group, or lets the user leave it
+func (h *GroupHandler) RemoveMemberHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	group, err := h.groupService.RemoveMember(userID, r.PathValue("id"), r.PathValue("userId"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to remove member", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, group)
+}
+
+// AddExpenseHTTP adds an expense paid by a member to the group
+func (h *GroupHandler) AddExpenseHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var request service.GroupExpenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	expense, err := h.groupService.AddExpense(userID, r.PathValue("id"), request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to add expense", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, expense)
+}
+
+// ListExpensesHTTP lists the expenses of the group (?limit=)
+func (h *GroupHandler) ListExpensesHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", "limit must be a number")
+			return
+		}
+		limit = parsed
+	}
+
+	expenses, err := h.groupService.GetExpenses(userID, r.PathValue("id"), limit)
+	if err != nil {
+		h.writeServiceError(w, "Failed to list expenses", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, expenses)
+}
+
+// DeleteExpenseHTTP deletes an expense of the group
+func (h *GroupHandler) DeleteExpenseHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	if err := h.groupService.DeleteExpense(userID, r.PathValue("id"), r.PathValue("expenseId")); err != nil {
+		h.writeServiceError(w, "Failed to delete expense", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetBalancesHTTP returns who owes whom in the group
+func (h *GroupHandler) GetBalancesHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	balances, err := h.groupService.GetBalances(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get balances", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, balances)
+}
+
+// SettleUpHTTP pays a member back through a transfer between users
+func (h *GroupHandler) SettleUpHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var request service.SettleUpRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	settlement, err := h.groupService.SettleUp(userID, r.PathValue("id"), request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to settle up", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, settlement)
+}
+
+// ListSettlementsHTTP lists the settlements of the group
+func (h *GroupHandler) ListSettlementsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	settlements, err := h.groupService.GetSettlements(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to list settlements", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, settlements)
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *GroupHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *GroupHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *GroupHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
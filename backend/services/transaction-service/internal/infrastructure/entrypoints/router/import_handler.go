@@ -0,0 +1,318 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	domainstatement "github.com/fintrack/transaction-service/internal/core/domain/entities/statement"
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// maxStatementSize bounds the size of an uploaded statement file
+const maxStatementSize = 10 << 20
+
+// ImportHandler handles HTTP requests for bank statement imports
+type ImportHandler struct {
+	importService service.ImportServiceInterface
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(db *sql.DB) *ImportHandler {
+	transactionRepo := mysql.NewTransactionRepository(db)
+
+	importService := service.NewImportService(
+		transactionRepo,
+		mysql.NewImportBatchRepository(db),
+		mysql.NewCSVMappingRepository(db),
+		service.NewCategoryService(
+			mysql.NewCategoryRepository(db),
+			mysql.NewCategorizationRuleRepository(db),
+			transactionRepo,
+		),
+	)
+
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// GetFormatsHTTP lists the statement formats and bank parsers available
+func (h *ImportHandler) GetFormatsHTTP(w http.ResponseWriter, r *http.Request) {
+	h.writeJSONResponse(w, http.StatusOK, h.importService.GetFormats())
+}
+
+// PreviewImportHTTP reads an uploaded statement and returns what would be imported
+func (h *ImportHandler) PreviewImportHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	req, err := readStatementUpload(w, r)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	preview, err := h.importService.PreviewImport(userID, *req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to preview import", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, preview)
+}
+
+// ImportHTTP imports an uploaded statement
+func (h *ImportHandler) ImportHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	req, err := readStatementUpload(w, r)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := h.importService.Import(userID, *req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to import statement", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, result)
+}
+
+// ListImportsHTTP returns the latest imports of the user
+func (h *ImportHandler) ListImportsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	batches, err := h.importService.GetBatches(userID)
+	if err != nil {
+		h.writeServiceError(w, "Failed to get imports", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"imports": batches,
+		"total":   len(batches),
+	})
+}
+
+// GetImportHTTP returns an import with the outcome of each row
+func (h *ImportHandler) GetImportHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	result, err := h.importService.GetBatch(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get import", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// RollbackImportHTTP deletes the transactions created by an import
+func (h *ImportHandler) RollbackImportHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	result, err := h.importService.RollbackBatch(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to roll back import", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// GetMappingsHTTP returns the saved CSV column mappings of the user
+func (h *ImportHandler) GetMappingsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	mappings, err := h.importService.GetMappings(userID)
+	if err != nil {
+		h.writeServiceError(w, "Failed to get column mappings", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"mappings": mappings,
+		"total":    len(mappings),
+	})
+}
+
+// CreateMappingHTTP saves a CSV column mapping for the user
+func (h *ImportHandler) CreateMappingHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req domainstatement.CSVMapping
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	mapping, err := h.importService.CreateMapping(userID, req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to create column mapping", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, mapping)
+}
+
+// UpdateMappingHTTP replaces a saved CSV column mapping of the user
+func (h *ImportHandler) UpdateMappingHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req domainstatement.CSVMapping
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	mapping, err := h.importService.UpdateMapping(userID, r.PathValue("id"), req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to update column mapping", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, mapping)
+}
+
+// DeleteMappingHTTP deletes a saved CSV column mapping of the user
+func (h *ImportHandler) DeleteMappingHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	if err := h.importService.DeleteMapping(userID, r.PathValue("id")); err != nil {
+		h.writeServiceError(w, "Failed to delete column mapping", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readStatementUpload reads a multipart upload: the statement in the "file" part and the options in the
+// form fields format, accountId, cardId, currency, mappingId, mapping (JSON) and includeDuplicates
+func readStatementUpload(w http.ResponseWriter, r *http.Request) (*service.ImportRequest, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxStatementSize)
+	if err := r.ParseMultipartForm(maxStatementSize); err != nil {
+		return nil, fmt.Errorf("invalid upload: %w", err)
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("statement file is required: %w", err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read statement file: %w", err)
+	}
+
+	req := &service.ImportRequest{
+		Format:   r.FormValue("format"),
+		FileName: header.Filename,
+		Content:  content,
+		Currency: r.FormValue("currency"),
+	}
+	req.AccountID = formValuePtr(r, "accountId")
+	req.CardID = formValuePtr(r, "cardId")
+	req.MappingID = formValuePtr(r, "mappingId")
+
+	if value := strings.TrimSpace(r.FormValue("mapping")); value != "" {
+		var mapping domainstatement.CSVMapping
+		if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+			return nil, fmt.Errorf("invalid mapping: %w", err)
+		}
+		req.Mapping = &mapping
+	}
+
+	if value := r.FormValue("includeDuplicates"); value != "" {
+		includeDuplicates, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid includeDuplicates value: %s", value)
+		}
+		req.IncludeDuplicates = includeDuplicates
+	}
+
+	return req, nil
+}
+
+// formValuePtr returns a form field, or nil when it is empty
+func formValuePtr(r *http.Request, key string) *string {
+	value := strings.TrimSpace(r.FormValue(key))
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *ImportHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *ImportHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *ImportHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
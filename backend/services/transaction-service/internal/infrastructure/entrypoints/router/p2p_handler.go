@@ -0,0 +1,325 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	domainp2p "github.com/fintrack/transaction-service/internal/core/domain/entities/p2p"
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// P2PHandler handles HTTP requests for transfers between FinTrack users
+type P2PHandler struct {
+	p2pService service.P2PServiceInterface
+}
+
+// NewP2PHandler creates a new peer-to-peer transfer handler. The daily limits of the platform are set with
+// P2P_DAILY_AMOUNT_LIMIT and P2P_DAILY_COUNT_LIMIT.
+func NewP2PHandler(db *sql.DB, transactionService service.TransactionServiceInterface) *P2PHandler {
+	var limits domainp2p.Limits
+	if value := os.Getenv("P2P_DAILY_AMOUNT_LIMIT"); value != "" {
+		if amount, err := strconv.ParseFloat(value, 64); err == nil && amount > 0 {
+			limits.DailyAmount = amount
+		}
+	}
+	if value := os.Getenv("P2P_DAILY_COUNT_LIMIT"); value != "" {
+		if count, err := strconv.Atoi(value); err == nil && count > 0 {
+			limits.DailyCount = count
+		}
+	}
+
+	return &P2PHandler{
+		p2pService: service.NewP2PService(mysql.NewP2PRepository(db), transactionService, limits),
+	}
+}
+
+// P2PService exposes the peer-to-peer transfer service to the background expirer
+func (h *P2PHandler) P2PService() service.P2PServiceInterface {
+	return h.p2pService
+}
+
+// GetProfileHTTP returns how the user is found and paid by other users
+func (h *P2PHandler) GetProfileHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	profile, err := h.p2pService.GetProfile(userID)
+	if err != nil {
+		h.writeServiceError(w, "Failed to get transfer profile", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, profile)
+}
+
+// UpdateProfileHTTP changes the alias, default account, auto-accept and own limits of the user
+func (h *P2PHandler) UpdateProfileHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var request service.P2PProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	profile, err := h.p2pService.UpdateProfile(userID, request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to update transfer profile", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, profile)
+}
+
+// FindRecipientHTTP resolves the email or alias in ?q= to the user it belongs to
+func (h *P2PHandler) FindRecipientHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	party, err := h.p2pService.FindRecipient(userID, r.URL.Query().Get("q"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to find recipient", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, party)
+}
+
+// GetLimitsHTTP returns the daily limits of the user in ?currency= and what is left of them today
+func (h *P2PHandler) GetLimitsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	limits, err := h.p2pService.GetLimits(userID, r.URL.Query().Get("currency"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get transfer limits", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, limits)
+}
+
+// SendMoneyHTTP sends money to another user by email or alias
+func (h *P2PHandler) SendMoneyHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var request service.SendMoneyRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	transfer, err := h.p2pService.SendMoney(userID, request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to send money", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, transfer)
+}
+
+// RequestMoneyHTTP asks another user for money by email or alias
+func (h *P2PHandler) RequestMoneyHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var request service.RequestMoneyRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	transfer, err := h.p2pService.RequestMoney(userID, request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to request money", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, transfer)
+}
+
+// ListTransfersHTTP lists the transfers of the user (?direction=sent|received, ?status=, ?awaiting=true, ?limit=)
+func (h *P2PHandler) ListTransfersHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	query := r.URL.Query()
+	filters := service.P2PTransferFilters{
+		Direction: query.Get("direction"),
+		Status:    domainp2p.TransferStatus(query.Get("status")),
+	}
+	if value := query.Get("awaiting"); value != "" {
+		awaiting, err := strconv.ParseBool(value)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", "awaiting must be true or false")
+			return
+		}
+		filters.Awaiting = awaiting
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", "limit must be a number")
+			return
+		}
+		filters.Limit = limit
+	}
+
+	transfers, err := h.p2pService.ListTransfers(userID, filters)
+	if err != nil {
+		h.writeServiceError(w, "Failed to list transfers", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, transfers)
+}
+
+// GetTransferHTTP returns a transfer the user sent or received
+func (h *P2PHandler) GetTransferHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	transfer, err := h.p2pService.GetTransfer(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get transfer", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, transfer)
+}
+
+// AcceptTransferHTTP accepts a pending transfer into an account, or pays a pending request from one
+func (h *P2PHandler) AcceptTransferHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	request, ok := h.decodeResponse(w, r)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.p2pService.AcceptTransfer(userID, r.PathValue("id"), request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to accept transfer", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, transfer)
+}
+
+// DeclineTransferHTTP declines a pending transfer or request
+func (h *P2PHandler) DeclineTransferHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	request, ok := h.decodeResponse(w, r)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.p2pService.DeclineTransfer(userID, r.PathValue("id"), request)
+	if err != nil {
+		h.writeServiceError(w, "Failed to decline transfer", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, transfer)
+}
+
+// CancelTransferHTTP cancels a pending transfer or request the user started
+func (h *P2PHandler) CancelTransferHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	transfer, err := h.p2pService.CancelTransfer(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to cancel transfer", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, transfer)
+}
+
+// decodeResponse reads the optional body of an answer to a transfer
+func (h *P2PHandler) decodeResponse(w http.ResponseWriter, r *http.Request) (service.RespondTransferRequest, bool) {
+	var request service.RespondTransferRequest
+	if r.ContentLength == 0 {
+		return request, true
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return request, false
+	}
+	return request, true
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *P2PHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *P2PHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *P2PHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
@@ -0,0 +1,244 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// ReconciliationHandler handles HTTP requests for statement reconciliation
+type ReconciliationHandler struct {
+	reconciliationService service.ReconciliationServiceInterface
+}
+
+// NewReconciliationHandler creates a new reconciliation handler. Statements are read with the given import service.
+func NewReconciliationHandler(db *sql.DB, importService service.ImportServiceInterface) *ReconciliationHandler {
+	reconciliationService := service.NewReconciliationService(
+		mysql.NewTransactionRepository(db),
+		mysql.NewReconciliationRepository(db),
+		importService,
+	)
+
+	return &ReconciliationHandler{
+		reconciliationService: reconciliationService,
+	}
+}
+
+// CreateReconciliationHTTP reconciles an import (JSON body with importBatchId) or an uploaded statement
+// (multipart, same fields as the import preview)
+func (h *ReconciliationHandler) CreateReconciliationHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.ReconciliationRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		statement, err := readStatementUpload(w, r)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+			return
+		}
+		req.Statement = statement
+
+		if value := r.FormValue("dateWindowDays"); value != "" {
+			days, err := strconv.Atoi(value)
+			if err != nil {
+				h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", "Invalid dateWindowDays value: "+value)
+				return
+			}
+			req.DateWindowDays = &days
+		}
+		if value := r.FormValue("amountTolerance"); value != "" {
+			tolerance, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", "Invalid amountTolerance value: "+value)
+				return
+			}
+			req.AmountTolerance = &tolerance
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	detail, err := h.reconciliationService.CreateReconciliation(userID, req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to reconcile statement", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, detail)
+}
+
+// ListReconciliationsHTTP returns the reconciliations of the user (?accountId= or ?cardId= to filter)
+func (h *ReconciliationHandler) ListReconciliationsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	reconciliations, err := h.reconciliationService.GetReconciliations(userID, queryValuePtr(r, "accountId"), queryValuePtr(r, "cardId"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get reconciliations", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"reconciliations": reconciliations,
+		"total":           len(reconciliations),
+	})
+}
+
+// GetReconciliationStatusHTTP returns how much of an account or card is reconciled up to a date
+// (?accountId= or ?cardId=, and ?asOf=YYYY-MM-DD, today by default)
+func (h *ReconciliationHandler) GetReconciliationStatusHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	req := service.ReconciliationStatusRequest{
+		AccountID: queryValuePtr(r, "accountId"),
+		CardID:    queryValuePtr(r, "cardId"),
+	}
+	if value := r.URL.Query().Get("asOf"); value != "" {
+		asOf, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", "asOf must be a date like 2025-01-31")
+			return
+		}
+		req.AsOf = asOf
+	}
+
+	status, err := h.reconciliationService.GetStatus(userID, req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to get reconciliation status", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, status)
+}
+
+// GetReconciliationHTTP returns a reconciliation with its items
+func (h *ReconciliationHandler) GetReconciliationHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	detail, err := h.reconciliationService.GetReconciliation(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get reconciliation", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, detail)
+}
+
+// ConfirmItemHTTP accepts a suggested match
+func (h *ReconciliationHandler) ConfirmItemHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	detail, err := h.reconciliationService.ConfirmItem(userID, r.PathValue("id"), r.PathValue("itemId"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to confirm match", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, detail)
+}
+
+// LinkItemHTTP matches a statement movement missing in FinTrack with a transaction
+func (h *ReconciliationHandler) LinkItemHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.LinkReconciliationItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	detail, err := h.reconciliationService.LinkItem(userID, r.PathValue("id"), r.PathValue("itemId"), req.TransactionID)
+	if err != nil {
+		h.writeServiceError(w, "Failed to link transaction", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, detail)
+}
+
+// UnlinkItemHTTP rejects a match
+func (h *ReconciliationHandler) UnlinkItemHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	detail, err := h.reconciliationService.UnlinkItem(userID, r.PathValue("id"), r.PathValue("itemId"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to unlink match", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, detail)
+}
+
+// queryValuePtr returns a query parameter, or nil when it is empty
+func queryValuePtr(r *http.Request, key string) *string {
+	value := strings.TrimSpace(r.URL.Query().Get(key))
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *ReconciliationHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *ReconciliationHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *ReconciliationHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
@@ -4,13 +4,27 @@ import (
 	"database/sql"
 	"net/http"
 
+	"github.com/fintrack/transaction-service/internal/core/service"
 	"github.com/fintrack/transaction-service/internal/infrastructure/entrypoints/middleware"
 )
 
 // Router handles all HTTP routing for the transaction service
 type Router struct {
-	handler     *TransactionHandler
-	cardHandler *CardHandler
+	handler               *TransactionHandler
+	cardHandler           *CardHandler
+	categoryHandler       *CategoryHandler
+	splitHandler          *SplitHandler
+	scheduleHandler       *ScheduleHandler
+	subscriptionHandler   *SubscriptionHandler
+	importHandler         *ImportHandler
+	reconciliationHandler *ReconciliationHandler
+	exportHandler         *ExportHandler
+	attachmentHandler     *AttachmentHandler
+	duplicateHandler      *DuplicateHandler
+	fraudHandler          *FraudHandler
+	p2pHandler            *P2PHandler
+	groupHandler          *GroupHandler
+	budgetHandler         *BudgetHandler
 }
 
 // NewRouter creates a new router instance
@@ -18,10 +32,36 @@ func NewRouter(db *sql.DB) *Router {
 	// Create handlers
 	transactionHandler := NewTransactionHandler(db)
 	cardHandler := NewCardHandler(db)
+	categoryHandler := NewCategoryHandler(db)
+	splitHandler := NewSplitHandler(db)
+	scheduleHandler := NewScheduleHandler(db, transactionHandler.transactionService)
+	subscriptionHandler := NewSubscriptionHandler(db)
+	importHandler := NewImportHandler(db)
+	reconciliationHandler := NewReconciliationHandler(db, importHandler.importService)
+	exportHandler := NewExportHandler(db)
+	attachmentHandler := NewAttachmentHandler(db)
+	duplicateHandler := NewDuplicateHandler(db)
+	fraudHandler := NewFraudHandler(db)
+	p2pHandler := NewP2PHandler(db, transactionHandler.transactionService)
+	groupHandler := NewGroupHandler(db, splitHandler.splitService, p2pHandler.p2pService)
+	budgetHandler := NewBudgetHandler(db)
 
 	router := &Router{
-		handler:     transactionHandler,
-		cardHandler: cardHandler,
+		handler:               transactionHandler,
+		cardHandler:           cardHandler,
+		categoryHandler:       categoryHandler,
+		splitHandler:          splitHandler,
+		scheduleHandler:       scheduleHandler,
+		subscriptionHandler:   subscriptionHandler,
+		importHandler:         importHandler,
+		reconciliationHandler: reconciliationHandler,
+		exportHandler:         exportHandler,
+		attachmentHandler:     attachmentHandler,
+		duplicateHandler:      duplicateHandler,
+		fraudHandler:          fraudHandler,
+		p2pHandler:            p2pHandler,
+		groupHandler:          groupHandler,
+		budgetHandler:         budgetHandler,
 	}
 
 	return router
@@ -37,10 +77,119 @@ func (r *Router) SetupRoutes() http.Handler {
 	// Transaction routes - using pattern matching (Go 1.22+)
 	mux.HandleFunc("POST /api/v1/transactions", r.handler.CreateTransactionHTTP)
 	mux.HandleFunc("GET /api/v1/transactions", r.handler.ListTransactionsHTTP)
+	mux.HandleFunc("GET /api/v1/transactions/export", r.exportHandler.ExportTransactionsHTTP)
+	mux.HandleFunc("GET /api/v1/transactions/duplicates", r.duplicateHandler.ScanDuplicatesHTTP)
+	mux.HandleFunc("POST /api/v1/transactions/merge", r.duplicateHandler.MergeDuplicatesHTTP)
 	mux.HandleFunc("GET /api/v1/transactions/{id}", r.handler.GetTransactionHTTP)
 	mux.HandleFunc("PUT /api/v1/transactions/{id}/status", r.handler.UpdateTransactionStatusHTTP)
 	mux.HandleFunc("POST /api/v1/transactions/{id}/process", r.handler.ProcessTransactionHTTP)
 	mux.HandleFunc("POST /api/v1/transactions/{id}/reverse", r.handler.ReverseTransactionHTTP)
+	mux.HandleFunc("POST /api/v1/transactions/{id}/release", r.handler.ReleaseTransactionHTTP)
+	mux.HandleFunc("GET /api/v1/transactions/{id}/risk", r.fraudHandler.GetTransactionScoreHTTP)
+	mux.HandleFunc("PUT /api/v1/transactions/{id}/category", r.categoryHandler.RecategorizeTransactionHTTP)
+
+	// Split transaction routes
+	mux.HandleFunc("GET /api/v1/transactions/{id}/split", r.splitHandler.GetSplitHTTP)
+	mux.HandleFunc("PUT /api/v1/transactions/{id}/split", r.splitHandler.SplitTransactionHTTP)
+	mux.HandleFunc("DELETE /api/v1/transactions/{id}/split", r.splitHandler.RemoveSplitHTTP)
+
+	// Receipt and invoice attachment routes
+	mux.HandleFunc("POST /api/v1/transactions/{id}/attachments", r.attachmentHandler.UploadTransactionAttachmentHTTP)
+	mux.HandleFunc("GET /api/v1/transactions/{id}/attachments", r.attachmentHandler.ListTransactionAttachmentsHTTP)
+	mux.HandleFunc("POST /api/v1/installment-plans/{id}/attachments", r.attachmentHandler.UploadInstallmentPlanAttachmentHTTP)
+	mux.HandleFunc("GET /api/v1/installment-plans/{id}/attachments", r.attachmentHandler.ListInstallmentPlanAttachmentsHTTP)
+	mux.HandleFunc("GET /api/v1/attachments/usage", r.attachmentHandler.GetUsageHTTP)
+	mux.HandleFunc("GET /api/v1/attachments/{id}", r.attachmentHandler.GetAttachmentHTTP)
+	mux.HandleFunc("GET /api/v1/attachments/{id}/content", r.attachmentHandler.DownloadAttachmentHTTP)
+	mux.HandleFunc("DELETE /api/v1/attachments/{id}", r.attachmentHandler.DeleteAttachmentHTTP)
+
+	// Fraud and anomaly scoring routes
+	mux.HandleFunc("GET /api/v1/fraud/settings", r.fraudHandler.GetSettingsHTTP)
+	mux.HandleFunc("PUT /api/v1/fraud/settings", r.fraudHandler.UpdateSettingsHTTP)
+	mux.HandleFunc("GET /api/v1/fraud/scores", r.fraudHandler.ListScoresHTTP)
+
+	// Transfers between FinTrack users by email or alias
+	mux.HandleFunc("GET /api/v1/p2p/profile", r.p2pHandler.GetProfileHTTP)
+	mux.HandleFunc("PUT /api/v1/p2p/profile", r.p2pHandler.UpdateProfileHTTP)
+	mux.HandleFunc("GET /api/v1/p2p/recipients", r.p2pHandler.FindRecipientHTTP)
+	mux.HandleFunc("GET /api/v1/p2p/limits", r.p2pHandler.GetLimitsHTTP)
+	mux.HandleFunc("POST /api/v1/p2p/transfers", r.p2pHandler.SendMoneyHTTP)
+	mux.HandleFunc("POST /api/v1/p2p/requests", r.p2pHandler.RequestMoneyHTTP)
+	mux.HandleFunc("GET /api/v1/p2p/transfers", r.p2pHandler.ListTransfersHTTP)
+	mux.HandleFunc("GET /api/v1/p2p/transfers/{id}", r.p2pHandler.GetTransferHTTP)
+	mux.HandleFunc("POST /api/v1/p2p/transfers/{id}/accept", r.p2pHandler.AcceptTransferHTTP)
+	mux.HandleFunc("POST /api/v1/p2p/transfers/{id}/decline", r.p2pHandler.DeclineTransferHTTP)
+	mux.HandleFunc("POST /api/v1/p2p/transfers/{id}/cancel", r.p2pHandler.CancelTransferHTTP)
+
+	// Shared expense group routes
+	mux.HandleFunc("POST /api/v1/groups", r.groupHandler.CreateGroupHTTP)
+	mux.HandleFunc("GET /api/v1/groups", r.groupHandler.ListGroupsHTTP)
+	mux.HandleFunc("GET /api/v1/groups/{id}", r.groupHandler.GetGroupHTTP)
+	mux.HandleFunc("POST /api/v1/groups/{id}/members", r.groupHandler.AddMemberHTTP)
+	mux.HandleFunc("DELETE /api/v1/groups/{id}/members/{userId}", r.groupHandler.RemoveMemberHTTP)
+	mux.HandleFunc("POST /api/v1/groups/{id}/expenses", r.groupHandler.AddExpenseHTTP)
+	mux.HandleFunc("GET /api/v1/groups/{id}/expenses", r.groupHandler.ListExpensesHTTP)
+	mux.HandleFunc("DELETE /api/v1/groups/{id}/expenses/{expenseId}", r.groupHandler.DeleteExpenseHTTP)
+	mux.HandleFunc("GET /api/v1/groups/{id}/balances", r.groupHandler.GetBalancesHTTP)
+	mux.HandleFunc("POST /api/v1/groups/{id}/settlements", r.groupHandler.SettleUpHTTP)
+	mux.HandleFunc("GET /api/v1/groups/{id}/settlements", r.groupHandler.ListSettlementsHTTP)
+
+	// Monthly budget routes
+	mux.HandleFunc("POST /api/v1/budgets", r.budgetHandler.CreateBudgetHTTP)
+	mux.HandleFunc("GET /api/v1/budgets", r.budgetHandler.ListBudgetsHTTP)
+	mux.HandleFunc("GET /api/v1/budgets/summary", r.budgetHandler.GetSummaryHTTP)
+	mux.HandleFunc("GET /api/v1/budgets/{id}", r.budgetHandler.GetBudgetHTTP)
+	mux.HandleFunc("PUT /api/v1/budgets/{id}", r.budgetHandler.UpdateBudgetHTTP)
+	mux.HandleFunc("DELETE /api/v1/budgets/{id}", r.budgetHandler.DeleteBudgetHTTP)
+	mux.HandleFunc("GET /api/v1/budgets/{id}/progress", r.budgetHandler.GetProgressHTTP)
+	mux.HandleFunc("GET /api/v1/budgets/{id}/history", r.budgetHandler.GetHistoryHTTP)
+
+	// Scheduled and recurring transaction routes
+	mux.HandleFunc("GET /api/v1/scheduled-transactions", r.scheduleHandler.ListSchedulesHTTP)
+	mux.HandleFunc("POST /api/v1/scheduled-transactions", r.scheduleHandler.CreateScheduleHTTP)
+	mux.HandleFunc("GET /api/v1/scheduled-transactions/{id}", r.scheduleHandler.GetScheduleHTTP)
+	mux.HandleFunc("PUT /api/v1/scheduled-transactions/{id}", r.scheduleHandler.UpdateScheduleHTTP)
+	mux.HandleFunc("DELETE /api/v1/scheduled-transactions/{id}", r.scheduleHandler.CancelScheduleHTTP)
+	mux.HandleFunc("POST /api/v1/scheduled-transactions/{id}/pause", r.scheduleHandler.PauseScheduleHTTP)
+	mux.HandleFunc("POST /api/v1/scheduled-transactions/{id}/resume", r.scheduleHandler.ResumeScheduleHTTP)
+	mux.HandleFunc("POST /api/v1/scheduled-transactions/{id}/skip", r.scheduleHandler.SkipNextOccurrenceHTTP)
+	mux.HandleFunc("PUT /api/v1/scheduled-transactions/{id}/next-occurrence", r.scheduleHandler.EditNextOccurrenceHTTP)
+	mux.HandleFunc("GET /api/v1/scheduled-transactions/{id}/runs", r.scheduleHandler.GetRunsHTTP)
+
+	// Subscriptions detected from the transaction history
+	mux.HandleFunc("GET /api/v1/subscriptions", r.subscriptionHandler.GetSubscriptionsHTTP)
+
+	// Bank statement import routes
+	mux.HandleFunc("GET /api/v1/imports/formats", r.importHandler.GetFormatsHTTP)
+	mux.HandleFunc("POST /api/v1/imports/preview", r.importHandler.PreviewImportHTTP)
+	mux.HandleFunc("POST /api/v1/imports", r.importHandler.ImportHTTP)
+	mux.HandleFunc("GET /api/v1/imports", r.importHandler.ListImportsHTTP)
+	mux.HandleFunc("GET /api/v1/imports/{id}", r.importHandler.GetImportHTTP)
+	mux.HandleFunc("POST /api/v1/imports/{id}/rollback", r.importHandler.RollbackImportHTTP)
+	mux.HandleFunc("GET /api/v1/imports/mappings", r.importHandler.GetMappingsHTTP)
+	mux.HandleFunc("POST /api/v1/imports/mappings", r.importHandler.CreateMappingHTTP)
+	mux.HandleFunc("PUT /api/v1/imports/mappings/{id}", r.importHandler.UpdateMappingHTTP)
+	mux.HandleFunc("DELETE /api/v1/imports/mappings/{id}", r.importHandler.DeleteMappingHTTP)
+
+	// Statement reconciliation routes
+	mux.HandleFunc("POST /api/v1/reconciliations", r.reconciliationHandler.CreateReconciliationHTTP)
+	mux.HandleFunc("GET /api/v1/reconciliations", r.reconciliationHandler.ListReconciliationsHTTP)
+	mux.HandleFunc("GET /api/v1/reconciliations/status", r.reconciliationHandler.GetReconciliationStatusHTTP)
+	mux.HandleFunc("GET /api/v1/reconciliations/{id}", r.reconciliationHandler.GetReconciliationHTTP)
+	mux.HandleFunc("POST /api/v1/reconciliations/{id}/items/{itemId}/confirm", r.reconciliationHandler.ConfirmItemHTTP)
+	mux.HandleFunc("POST /api/v1/reconciliations/{id}/items/{itemId}/link", r.reconciliationHandler.LinkItemHTTP)
+	mux.HandleFunc("POST /api/v1/reconciliations/{id}/items/{itemId}/unlink", r.reconciliationHandler.UnlinkItemHTTP)
+
+	// Category and auto-categorization rule routes
+	mux.HandleFunc("GET /api/v1/categories", r.categoryHandler.GetCategoriesHTTP)
+	mux.HandleFunc("POST /api/v1/categories", r.categoryHandler.CreateCategoryHTTP)
+	mux.HandleFunc("PUT /api/v1/categories/{id}", r.categoryHandler.UpdateCategoryHTTP)
+	mux.HandleFunc("DELETE /api/v1/categories/{id}", r.categoryHandler.DeleteCategoryHTTP)
+	mux.HandleFunc("GET /api/v1/categorization-rules", r.categoryHandler.GetRulesHTTP)
+	mux.HandleFunc("POST /api/v1/categorization-rules", r.categoryHandler.CreateRuleHTTP)
+	mux.HandleFunc("POST /api/v1/categorization-rules/reapply", r.categoryHandler.ReapplyRulesHTTP)
+	mux.HandleFunc("PUT /api/v1/categorization-rules/{id}", r.categoryHandler.UpdateRuleHTTP)
+	mux.HandleFunc("DELETE /api/v1/categorization-rules/{id}", r.categoryHandler.DeleteRuleHTTP)
 
 	// Card transaction routes
 	mux.HandleFunc("POST /api/v1/cards/credit/charge", r.cardHandler.ChargeCreditCardHTTP)
@@ -51,6 +200,31 @@ func (r *Router) SetupRoutes() http.Handler {
 	return middleware.AuthMiddleware(mux)
 }
 
+// ScheduleService returns the service used by the background scheduler of scheduled transactions
+func (r *Router) ScheduleService() service.ScheduleServiceInterface {
+	return r.scheduleHandler.ScheduleService()
+}
+
+// SubscriptionService returns the service used by the background refresher of detected subscriptions
+func (r *Router) SubscriptionService() service.SubscriptionServiceInterface {
+	return r.subscriptionHandler.SubscriptionService()
+}
+
+// AttachmentService returns the service used by the background cleaner of orphan attachments
+func (r *Router) AttachmentService() service.AttachmentServiceInterface {
+	return r.attachmentHandler.AttachmentService()
+}
+
+// P2PService returns the service used by the background expirer of unanswered transfers
+func (r *Router) P2PService() service.P2PServiceInterface {
+	return r.p2pHandler.P2PService()
+}
+
+// BudgetService returns the service used by the background refresher of budgets and their alerts
+func (r *Router) BudgetService() service.BudgetServiceInterface {
+	return r.budgetHandler.BudgetService()
+}
+
 // healthCheck handles health check requests
 func (r *Router) healthCheck(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,284 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	domainschedule "github.com/fintrack/transaction-service/internal/core/domain/entities/schedule"
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/http/clients"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// ScheduleHandler handles HTTP requests for recurring and scheduled transactions
+type ScheduleHandler struct {
+	scheduleService service.ScheduleServiceInterface
+}
+
+// NewScheduleHandler creates a new schedule handler. Occurrences are materialized through the given transaction service.
+func NewScheduleHandler(db *sql.DB, transactionService service.TransactionServiceInterface) *ScheduleHandler {
+	// Create notification service client - using environment variable or default localhost
+	notificationServiceURL := "http://localhost:8088"
+	if url := os.Getenv("NOTIFICATION_SERVICE_URL"); url != "" {
+		notificationServiceURL = url
+	}
+
+	scheduleService := service.NewScheduleService(
+		mysql.NewScheduledTransactionRepository(db),
+		mysql.NewCategoryRepository(db),
+		transactionService,
+		clients.NewNotificationClient(notificationServiceURL),
+	)
+
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// ScheduleService exposes the schedule service to the background scheduler
+func (h *ScheduleHandler) ScheduleService() service.ScheduleServiceInterface {
+	return h.scheduleService
+}
+
+// ListSchedulesHTTP returns the scheduled transactions of the user
+func (h *ScheduleHandler) ListSchedulesHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var status *domainschedule.ScheduleStatus
+	if value := r.URL.Query().Get("status"); value != "" {
+		parsed := domainschedule.ScheduleStatus(value)
+		status = &parsed
+	}
+
+	schedules, err := h.scheduleService.GetSchedules(userID, status)
+	if err != nil {
+		h.writeServiceError(w, "Failed to get scheduled transactions", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"scheduledTransactions": schedules,
+		"total":                 len(schedules),
+	})
+}
+
+// CreateScheduleHTTP creates a recurring template or a one-off future-dated transaction
+func (h *ScheduleHandler) CreateScheduleHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.ScheduledTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	schedule, err := h.scheduleService.CreateSchedule(userID, req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to create scheduled transaction", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, schedule)
+}
+
+// GetScheduleHTTP returns a scheduled transaction with its upcoming occurrences and latest runs
+func (h *ScheduleHandler) GetScheduleHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	detail, err := h.scheduleService.GetSchedule(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get scheduled transaction", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, detail)
+}
+
+// UpdateScheduleHTTP replaces the template and recurrence of a scheduled transaction
+func (h *ScheduleHandler) UpdateScheduleHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.ScheduledTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	schedule, err := h.scheduleService.UpdateSchedule(userID, r.PathValue("id"), req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to update scheduled transaction", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, schedule)
+}
+
+// CancelScheduleHTTP cancels a scheduled transaction
+func (h *ScheduleHandler) CancelScheduleHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	if err := h.scheduleService.CancelSchedule(userID, r.PathValue("id")); err != nil {
+		h.writeServiceError(w, "Failed to cancel scheduled transaction", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PauseScheduleHTTP pauses a scheduled transaction
+func (h *ScheduleHandler) PauseScheduleHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	schedule, err := h.scheduleService.PauseSchedule(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to pause scheduled transaction", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, schedule)
+}
+
+// ResumeScheduleHTTP resumes a paused scheduled transaction
+func (h *ScheduleHandler) ResumeScheduleHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	schedule, err := h.scheduleService.ResumeSchedule(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to resume scheduled transaction", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, schedule)
+}
+
+// SkipNextOccurrenceHTTP skips the next occurrence of a scheduled transaction
+func (h *ScheduleHandler) SkipNextOccurrenceHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	schedule, err := h.scheduleService.SkipNextOccurrence(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to skip next occurrence", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, schedule)
+}
+
+// EditNextOccurrenceHTTP changes the amount, description or date of the next occurrence only
+func (h *ScheduleHandler) EditNextOccurrenceHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.NextOccurrenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	schedule, err := h.scheduleService.EditNextOccurrence(userID, r.PathValue("id"), req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to edit next occurrence", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, schedule)
+}
+
+// GetRunsHTTP returns the execution history of a scheduled transaction
+func (h *ScheduleHandler) GetRunsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	limit := 50
+	if value := r.URL.Query().Get("limit"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			limit = parsed
+		}
+	}
+
+	runs, err := h.scheduleService.GetRuns(userID, r.PathValue("id"), limit)
+	if err != nil {
+		h.writeServiceError(w, "Failed to get scheduled runs", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"runs":  runs,
+		"total": len(runs),
+	})
+}
+
+// Helper methods
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *ScheduleHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *ScheduleHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *ScheduleHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
@@ -0,0 +1,118 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// SplitHandler handles HTTP requests for split transactions
+type SplitHandler struct {
+	splitService service.SplitServiceInterface
+}
+
+// NewSplitHandler creates a new split handler
+func NewSplitHandler(db *sql.DB) *SplitHandler {
+	splitService := service.NewSplitService(
+		mysql.NewTransactionRepository(db),
+		mysql.NewAllocationRepository(db),
+		mysql.NewCategoryRepository(db),
+	)
+
+	return &SplitHandler{
+		splitService: splitService,
+	}
+}
+
+// GetSplitHTTP returns a transaction with its allocations
+func (h *SplitHandler) GetSplitHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	result, err := h.splitService.GetSplit(userID, r.PathValue("id"))
+	if err != nil {
+		h.writeServiceError(w, "Failed to get transaction split", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// SplitTransactionHTTP replaces the allocations of a transaction
+func (h *SplitHandler) SplitTransactionHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	var req service.SplitTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := h.splitService.SplitTransaction(userID, r.PathValue("id"), req)
+	if err != nil {
+		h.writeServiceError(w, "Failed to split transaction", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// RemoveSplitHTTP deletes the allocations of a transaction
+func (h *SplitHandler) RemoveSplitHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	if err := h.splitService.RemoveSplit(userID, r.PathValue("id")); err != nil {
+		h.writeServiceError(w, "Failed to remove transaction split", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Helper methods
+
+// writeServiceError maps service errors to HTTP status codes
+func (h *SplitHandler) writeServiceError(w http.ResponseWriter, errorTitle string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, http.StatusNotFound, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		h.writeErrorResponse(w, http.StatusForbidden, errorTitle, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		h.writeErrorResponse(w, http.StatusInternalServerError, errorTitle, err.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, errorTitle, err.Error())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (h *SplitHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *SplitHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
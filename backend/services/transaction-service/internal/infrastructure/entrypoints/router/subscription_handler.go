@@ -0,0 +1,66 @@
+package router
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/repositories/mysql"
+)
+
+// SubscriptionHandler handles HTTP requests for subscriptions detected from the transaction history
+type SubscriptionHandler struct {
+	subscriptionService service.SubscriptionServiceInterface
+}
+
+// NewSubscriptionHandler creates a new subscription handler
+func NewSubscriptionHandler(db *sql.DB) *SubscriptionHandler {
+	subscriptionService := service.NewSubscriptionService(
+		mysql.NewTransactionRepository(db),
+		mysql.NewSubscriptionRepository(db),
+	)
+
+	return &SubscriptionHandler{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// SubscriptionService exposes the subscription service to the background refresher
+func (h *SubscriptionHandler) SubscriptionService() service.SubscriptionServiceInterface {
+	return h.subscriptionService
+}
+
+// GetSubscriptionsHTTP returns the recurring charges of the user with their annualized cost
+func (h *SubscriptionHandler) GetSubscriptionsHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	report, err := h.subscriptionService.GetSubscriptions(userID)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get subscriptions", err.Error())
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, report)
+}
+
+// writeJSONResponse writes a JSON response
+func (h *SubscriptionHandler) writeJSONResponse(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeErrorResponse writes an error response
+func (h *SubscriptionHandler) writeErrorResponse(w http.ResponseWriter, status int, error string, message string) {
+	response := ErrorResponse{
+		Error:   error,
+		Message: message,
+		Code:    status,
+	}
+	h.writeJSONResponse(w, status, response)
+}
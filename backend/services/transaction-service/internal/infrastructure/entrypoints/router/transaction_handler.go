@@ -1,14 +1,19 @@
 package router
 
 import (
+	"crypto/subtle"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	domainduplicate "github.com/fintrack/transaction-service/internal/core/domain/entities/duplicate"
 	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
 	"github.com/fintrack/transaction-service/internal/core/service"
 	"github.com/fintrack/transaction-service/internal/infrastructure/http/clients"
@@ -18,6 +23,9 @@ import (
 // TransactionHandler handles HTTP requests for transaction operations
 type TransactionHandler struct {
 	transactionService service.TransactionServiceInterface
+	duplicateService   service.DuplicateServiceInterface
+	membershipService  service.MembershipServiceInterface
+	internalToken      string // Shared secret of the calls between services
 }
 
 // NewTransactionHandler creates a new transaction handler
@@ -37,6 +45,13 @@ func NewTransactionHandler(db *sql.DB) *TransactionHandler {
 	}
 	accountService := clients.NewAccountClient(accountServiceURL)
 
+	// Create category service for auto-categorization
+	categoryService := service.NewCategoryService(
+		mysql.NewCategoryRepository(db),
+		mysql.NewCategorizationRuleRepository(db),
+		transactionRepo,
+	)
+
 	// Create transaction service
 	transactionService := service.NewTransactionService(
 		transactionRepo,
@@ -44,10 +59,15 @@ func NewTransactionHandler(db *sql.DB) *TransactionHandler {
 		auditService,
 		externalService,
 		accountService,
+		categoryService,
+		newFraudService(db),
 	)
 
 	return &TransactionHandler{
 		transactionService: transactionService,
+		duplicateService:   service.NewDuplicateService(transactionRepo, mysql.NewDuplicateRepository(db)),
+		membershipService:  service.NewMembershipService(mysql.NewAccountMemberRepository(db)),
+		internalToken:      os.Getenv("INTERNAL_SERVICE_TOKEN"),
 	}
 }
 
@@ -66,8 +86,11 @@ type CreateTransactionRequest struct {
 	PaymentMethod string                 `json:"paymentMethod"`
 	MerchantName  string                 `json:"merchantName"`
 	MerchantID    string                 `json:"merchantId"`
+	Notes         string                 `json:"notes"`
+	CategoryID    *string                `json:"categoryId"` // Optional, otherwise the categorization rules decide
 	ReferenceID   string                 `json:"referenceId"`
 	ExternalID    string                 `json:"externalId"`
+	InitiatedBy   string                 `json:"initiatedBy"` // Member acting on a shared account; only other services can set it
 	Metadata      map[string]interface{} `json:"metadata"`
 	Tags          []string               `json:"tags"`
 }
@@ -91,6 +114,9 @@ type TransactionResponse struct {
 	PaymentMethod   string                 `json:"paymentMethod"`
 	MerchantName    string                 `json:"merchantName"`
 	MerchantID      string                 `json:"merchantId"`
+	Notes           string                 `json:"notes"`
+	CategoryID      *string                `json:"categoryId"`
+	CategorySource  string                 `json:"categorySource"`
 	PreviousBalance float64                `json:"previousBalance"`
 	NewBalance      float64                `json:"newBalance"`
 	ProcessedAt     *string                `json:"processedAt"`
@@ -100,6 +126,10 @@ type TransactionResponse struct {
 	Tags            []string               `json:"tags"`
 	CreatedAt       string                 `json:"createdAt"`
 	UpdatedAt       string                 `json:"updatedAt"`
+
+	// Set on create when the transaction looks like one already recorded
+	Warnings            []string                     `json:"warnings,omitempty"`
+	DuplicateCandidates []*domainduplicate.Candidate `json:"duplicateCandidates,omitempty"`
 }
 
 // TransactionListResponse represents the response for listing transactions
@@ -108,6 +138,7 @@ type TransactionListResponse struct {
 	Total        int                    `json:"total"`
 	Page         int                    `json:"page"`
 	PageSize     int                    `json:"pageSize"`
+	NextCursor   string                 `json:"nextCursor,omitempty"` // Pass as ?cursor= for the next page; empty on the last one
 }
 
 // ErrorResponse represents an error response
@@ -173,6 +204,8 @@ func (h *TransactionHandler) CreateTransactionHTTP(w http.ResponseWriter, r *htt
 		PaymentMethod: domaintransaction.PaymentMethod(req.PaymentMethod),
 		MerchantName:  req.MerchantName,
 		MerchantID:    req.MerchantID,
+		Notes:         req.Notes,
+		CategoryID:    req.CategoryID,
 		ReferenceID:   req.ReferenceID,
 		ExternalID:    req.ExternalID,
 		Metadata:      req.Metadata,
@@ -186,10 +219,34 @@ func (h *TransactionHandler) CreateTransactionHTTP(w http.ResponseWriter, r *htt
 
 	log.Printf("🔄 Calling transactionService.CreateTransaction for type=%s, amount=%.2f\n", serviceReq.Type, serviceReq.Amount)
 
+	// On shared accounts the transaction belongs to the account owner but records the member that performed it.
+	// That is the authenticated user, unless another service names the member it acts for.
+	initiatedBy := userID
+	if req.InitiatedBy != "" && req.InitiatedBy != userID {
+		if !h.isInternalServiceCall(r) {
+			h.writeErrorResponse(w, http.StatusForbidden, "Forbidden", "initiatedBy can only be set by internal services")
+			return
+		}
+		if err := h.membershipService.VerifyInitiator(serviceReq, req.InitiatedBy); err != nil {
+			log.Printf("❌ Initiator check failed: %v\n", err)
+			if errors.Is(err, service.ErrInitiatorNotAllowed) {
+				h.writeErrorResponse(w, http.StatusForbidden, "Forbidden", err.Error())
+				return
+			}
+			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to verify initiator", err.Error())
+			return
+		}
+		initiatedBy = req.InitiatedBy
+	}
+
 	// Create transaction
-	transaction, err := h.transactionService.CreateTransaction(serviceReq, userID)
+	transaction, err := h.transactionService.CreateTransaction(serviceReq, initiatedBy)
 	if err != nil {
 		log.Printf("❌ CreateTransaction failed: %v\n", err)
+		if errors.Is(err, service.ErrTransactionBlocked) {
+			h.writeErrorResponse(w, http.StatusForbidden, "Transaction blocked", err.Error())
+			return
+		}
 		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to create transaction", err.Error())
 		return
 	}
@@ -198,9 +255,33 @@ func (h *TransactionHandler) CreateTransactionHTTP(w http.ResponseWriter, r *htt
 
 	// Convert to response
 	response := h.toTransactionResponse(transaction)
+
+	if held, _ := transaction.Metadata["fraudHold"].(bool); held {
+		response.Warnings = append(response.Warnings, fmt.Sprintf("Held for review by the fraud checks; release it with POST /api/v1/transactions/%s/release", transaction.ID))
+	}
+
+	// The transaction is kept either way; the client decides whether to merge it
+	candidates, err := h.duplicateService.FindDuplicates(transaction)
+	if err != nil {
+		log.Printf("Warning: duplicate check of transaction %s failed: %v", transaction.ID, err)
+	} else if len(candidates) > 0 {
+		candidateIDs := make([]string, len(candidates))
+		for i, candidate := range candidates {
+			candidateIDs[i] = candidate.TransactionID
+		}
+		response.Warnings = append(response.Warnings, fmt.Sprintf("Possible duplicate of %s", strings.Join(candidateIDs, ", ")))
+		response.DuplicateCandidates = candidates
+	}
+
 	h.writeJSONResponse(w, http.StatusCreated, response)
 }
 
+// isInternalServiceCall tells whether the request carries the shared secret of the calls between services
+func (h *TransactionHandler) isInternalServiceCall(r *http.Request) bool {
+	token := r.Header.Get("X-Internal-Service-Token")
+	return h.internalToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.internalToken)) == 1
+}
+
 // GetTransactionHTTP retrieves a transaction by ID
 func (h *TransactionHandler) GetTransactionHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -249,7 +330,7 @@ func (h *TransactionHandler) ListTransactionsHTTP(w http.ResponseWriter, r *http
 	}
 
 	// Parse query parameters
-	filters, err := h.parseFiltersFromURL(r)
+	filters, err := parseTransactionFilters(r)
 	if err != nil {
 		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
 		return
@@ -279,6 +360,12 @@ func (h *TransactionHandler) ListTransactionsHTTP(w http.ResponseWriter, r *http
 		PageSize:     filters.Limit,
 	}
 
+	// A full page may have more after it
+	if len(transactions) > 0 && len(transactions) == filters.Limit {
+		sortKey, ascending, _ := domaintransaction.ParseSort(filters.OrderBy, filters.Order)
+		response.NextCursor = domaintransaction.NewCursor(transactions[len(transactions)-1], sortKey, ascending).Encode()
+	}
+
 	h.writeJSONResponse(w, http.StatusOK, response)
 }
 
@@ -426,6 +513,33 @@ func (h *TransactionHandler) ReverseTransactionHTTP(w http.ResponseWriter, r *ht
 	h.writeJSONResponse(w, http.StatusCreated, response)
 }
 
+// ReleaseTransactionHTTP executes a transaction held by the fraud checks after its owner confirms it
+func (h *TransactionHandler) ReleaseTransactionHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", "User ID is required")
+		return
+	}
+
+	transaction, err := h.transactionService.ReleaseHeldTransaction(r.PathValue("id"), userID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			h.writeErrorResponse(w, http.StatusNotFound, "Transaction not found", err.Error())
+		case strings.HasPrefix(err.Error(), "unauthorized"):
+			h.writeErrorResponse(w, http.StatusForbidden, "Failed to release transaction", err.Error())
+		case strings.HasPrefix(err.Error(), "failed to"):
+			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to release transaction", err.Error())
+		default:
+			h.writeErrorResponse(w, http.StatusBadRequest, "Failed to release transaction", err.Error())
+		}
+		return
+	}
+
+	response := h.toTransactionResponse(transaction)
+	h.writeJSONResponse(w, http.StatusOK, response)
+}
+
 // Helper methods
 
 // extractTransactionID extracts transaction ID from URL path
@@ -445,18 +559,12 @@ func (h *TransactionHandler) extractTransactionID(path, suffix string) string {
 	return remaining
 }
 
-// parseFiltersFromURL parses query parameters into TransactionFilters
-func (h *TransactionHandler) parseFiltersFromURL(r *http.Request) (service.TransactionFilters, error) {
+// parseTransactionFilters parses query parameters into TransactionFilters. The list and the export share it,
+// so both select the same transactions.
+func parseTransactionFilters(r *http.Request) (service.TransactionFilters, error) {
 	filters := service.TransactionFilters{}
 	query := r.URL.Query()
 
-	// Parse pagination
-	if page := query.Get("page"); page != "" {
-		if p, err := strconv.Atoi(page); err == nil && p > 0 {
-			filters.Offset = (p - 1) * filters.Limit
-		}
-	}
-
 	// Accept both 'limit' and 'pageSize' for compatibility
 	if limit := query.Get("limit"); limit != "" {
 		if l, err := strconv.Atoi(limit); err == nil && l > 0 && l <= 100 {
@@ -474,6 +582,21 @@ func (h *TransactionHandler) parseFiltersFromURL(r *http.Request) (service.Trans
 		filters.Limit = 10 // Default page size changed to 10
 	}
 
+	// Parse pagination: a page number, or the cursor of the previous page
+	if page := query.Get("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil && p > 0 {
+			filters.Offset = (p - 1) * filters.Limit
+		}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		decoded, err := domaintransaction.DecodeCursor(cursor)
+		if err != nil {
+			return filters, err
+		}
+		filters.Cursor = decoded
+	}
+
 	// Parse types
 	if types := query["type"]; len(types) > 0 {
 		for _, typeStr := range types {
@@ -488,6 +611,23 @@ func (h *TransactionHandler) parseFiltersFromURL(r *http.Request) (service.Trans
 		}
 	}
 
+	// Parse dates: a plain date as toDate covers that whole day
+	if fromDate := query.Get("fromDate"); fromDate != "" {
+		date, err := parseFilterDate(fromDate, false)
+		if err != nil {
+			return filters, err
+		}
+		filters.FromDate = &date
+	}
+
+	if toDate := query.Get("toDate"); toDate != "" {
+		date, err := parseFilterDate(toDate, true)
+		if err != nil {
+			return filters, err
+		}
+		filters.ToDate = &date
+	}
+
 	// Parse amounts
 	if minAmount := query.Get("minAmount"); minAmount != "" {
 		if ma, err := strconv.ParseFloat(minAmount, 64); err == nil {
@@ -501,6 +641,44 @@ func (h *TransactionHandler) parseFiltersFromURL(r *http.Request) (service.Trans
 		}
 	}
 
+	// Parse category, including its subcategories
+	if categoryID := query.Get("categoryId"); categoryID != "" {
+		filters.CategoryID = &categoryID
+	}
+
+	if uncategorized := query.Get("uncategorized"); uncategorized != "" {
+		filters.Uncategorized = uncategorized == "true"
+	}
+
+	// Parse free text search and the rest of the filters
+	filters.Search = strings.TrimSpace(query.Get("q"))
+	filters.Tags = query["tag"]
+	filters.Currencies = query["currency"]
+
+	if sign := query.Get("sign"); sign != "" {
+		if sign != service.AmountSignNegative && sign != service.AmountSignPositive {
+			return filters, fmt.Errorf("invalid sign: %s (use negative or positive)", sign)
+		}
+		filters.AmountSign = sign
+	}
+
+	if accountID := query.Get("accountId"); accountID != "" {
+		filters.AccountID = &accountID
+	}
+
+	if cardID := query.Get("cardId"); cardID != "" {
+		filters.CardID = &cardID
+	}
+
+	if merchantName := query.Get("merchantName"); merchantName != "" {
+		filters.MerchantName = &merchantName
+	}
+
+	if paymentMethod := query.Get("paymentMethod"); paymentMethod != "" {
+		method := domaintransaction.PaymentMethod(paymentMethod)
+		filters.PaymentMethod = &method
+	}
+
 	// Parse order
 	if orderBy := query.Get("orderBy"); orderBy != "" {
 		filters.OrderBy = orderBy
@@ -510,9 +688,33 @@ func (h *TransactionHandler) parseFiltersFromURL(r *http.Request) (service.Trans
 		filters.Order = order
 	}
 
+	sortKey, ascending, err := domaintransaction.ParseSort(filters.OrderBy, filters.Order)
+	if err != nil {
+		return filters, err
+	}
+	if filters.Cursor != nil && (filters.Cursor.SortKey != sortKey || filters.Cursor.Ascending != ascending) {
+		return filters, fmt.Errorf("the cursor belongs to another order, keep orderBy and order while paging")
+	}
+
 	return filters, nil
 }
 
+// parseFilterDate parses a date filter given as RFC 3339 or YYYY-MM-DD. endOfDay moves a plain date to its last instant.
+func parseFilterDate(value string, endOfDay bool) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC 3339", value)
+	}
+	if endOfDay {
+		date = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return date, nil
+}
+
 // toTransactionResponse converts domain transaction to response DTO
 func (h *TransactionHandler) toTransactionResponse(transaction *domaintransaction.Transaction) *TransactionResponse {
 	response := &TransactionResponse{
@@ -533,6 +735,9 @@ func (h *TransactionHandler) toTransactionResponse(transaction *domaintransactio
 		PaymentMethod:   string(transaction.PaymentMethod),
 		MerchantName:    transaction.MerchantName,
 		MerchantID:      transaction.MerchantID,
+		Notes:           transaction.Notes,
+		CategoryID:      transaction.CategoryID,
+		CategorySource:  string(transaction.CategorySource),
 		PreviousBalance: transaction.PreviousBalance,
 		NewBalance:      transaction.NewBalance,
 		FailureReason:   transaction.FailureReason,
@@ -0,0 +1,549 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	domainfraud "github.com/fintrack/transaction-service/internal/core/domain/entities/fraud"
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+	"github.com/fintrack/transaction-service/internal/core/service"
+	"github.com/fintrack/transaction-service/internal/infrastructure/http/clients"
+)
+
+// MockTransactionRepository keeps the transactions of the handler tests in memory
+type MockTransactionRepository struct {
+	service.TransactionRepositoryInterface
+	transactions   []*domaintransaction.Transaction
+	sharedAccounts map[string][]string // Accounts each user sees as co-owner or viewer
+}
+
+func (m *MockTransactionRepository) Create(transaction *domaintransaction.Transaction) (*domaintransaction.Transaction, error) {
+	transaction.ID = fmt.Sprintf("tx-%d", len(m.transactions)+1)
+	if transaction.CreatedAt.IsZero() {
+		transaction.CreatedAt = time.Now()
+	}
+	transaction.UpdatedAt = transaction.CreatedAt
+	m.transactions = append(m.transactions, transaction)
+	return transaction, nil
+}
+
+func (m *MockTransactionRepository) GetByID(id string) (*domaintransaction.Transaction, error) {
+	for _, transaction := range m.transactions {
+		if transaction.ID == id {
+			return transaction, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction not found with ID: %s", id)
+}
+
+func (m *MockTransactionRepository) IsVisibleTo(id, userID string) (bool, error) {
+	transaction, err := m.GetByID(id)
+	if err != nil {
+		return false, err
+	}
+	for _, accountID := range m.sharedAccounts[userID] {
+		if (transaction.FromAccountID != nil && *transaction.FromAccountID == accountID) ||
+			(transaction.ToAccountID != nil && *transaction.ToAccountID == accountID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockTransactionRepository) Update(transaction *domaintransaction.Transaction) (*domaintransaction.Transaction, error) {
+	transaction.UpdatedAt = time.Now()
+	return transaction, nil
+}
+
+func (m *MockTransactionRepository) GetByUserID(userID string, filters service.TransactionFilters) ([]*domaintransaction.Transaction, int, error) {
+	var transactions []*domaintransaction.Transaction
+	for _, transaction := range m.transactions {
+		if transaction.UserID != userID {
+			continue
+		}
+		if len(filters.Statuses) > 0 && !containsStatus(filters.Statuses, transaction.Status) {
+			continue
+		}
+		if filters.MinAmount != nil && transaction.Amount < *filters.MinAmount {
+			continue
+		}
+		if filters.MaxAmount != nil && transaction.Amount > *filters.MaxAmount {
+			continue
+		}
+		if filters.FromDate != nil && transaction.CreatedAt.Before(*filters.FromDate) {
+			continue
+		}
+		if filters.ToDate != nil && transaction.CreatedAt.After(*filters.ToDate) {
+			continue
+		}
+		transactions = append(transactions, transaction)
+	}
+	total := len(transactions)
+	if filters.Limit > 0 && len(transactions) > filters.Limit {
+		transactions = transactions[:filters.Limit]
+	}
+	return transactions, total, nil
+}
+
+func containsStatus(statuses []domaintransaction.TransactionStatus, status domaintransaction.TransactionStatus) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
+
+// MockAccountService is an account service whose accounts hold 100000 ARS and records the withdrawals
+type MockAccountService struct {
+	withdrawals []string
+}
+
+func (m *MockAccountService) GetAccountBalance(accountID string) (*clients.AccountBalance, error) {
+	return &clients.AccountBalance{AccountID: accountID, Balance: 100000, Currency: "ARS"}, nil
+}
+
+func (m *MockAccountService) GetAccountInfo(accountID string) (*clients.AccountInfo, error) {
+	return &clients.AccountInfo{ID: accountID, AccountType: "savings", Balance: 100000, Currency: "ARS", IsActive: true}, nil
+}
+
+func (m *MockAccountService) AddFunds(accountID string, amount float64, description string, reference string) (*clients.BalanceUpdateResponse, error) {
+	return &clients.BalanceUpdateResponse{Success: true}, nil
+}
+
+func (m *MockAccountService) WithdrawFunds(accountID string, amount float64, description string, reference string) (*clients.BalanceUpdateResponse, error) {
+	m.withdrawals = append(m.withdrawals, reference)
+	return &clients.BalanceUpdateResponse{Success: true}, nil
+}
+
+func (m *MockAccountService) UpdateCreditUsage(accountID string, amount float64, description string, reference string) (*clients.BalanceUpdateResponse, error) {
+	return &clients.BalanceUpdateResponse{Success: true}, nil
+}
+
+func (m *MockAccountService) GetAvailableCredit(accountID string) (*clients.AccountBalance, error) {
+	return &clients.AccountBalance{AccountID: accountID, Balance: 100000, Currency: "ARS"}, nil
+}
+
+func (m *MockAccountService) ValidateAccountExists(accountID string) (bool, error) {
+	return true, nil
+}
+
+func (m *MockAccountService) HealthCheck() error {
+	return nil
+}
+
+// MockCategoryService leaves the transactions uncategorized
+type MockCategoryService struct {
+	service.CategoryServiceInterface
+}
+
+func (m *MockCategoryService) CategorizeTransaction(transaction *domaintransaction.Transaction) error {
+	return nil
+}
+
+// MockFraudService scores every transaction with the same action and records the scores
+type MockFraudService struct {
+	service.FraudServiceInterface
+	action   domainfraud.Action
+	recorded []*domainfraud.Score
+}
+
+func (m *MockFraudService) ScoreTransaction(transaction *domaintransaction.Transaction) (*domainfraud.Score, error) {
+	return &domainfraud.Score{
+		UserID: transaction.UserID,
+		Score:  80,
+		Action: m.action,
+		Reasons: []domainfraud.Reason{
+			{Signal: domainfraud.SignalNewCard, Points: 20, Detail: "First use of card card-9"},
+		},
+	}, nil
+}
+
+func (m *MockFraudService) RecordScore(transaction *domaintransaction.Transaction, score *domainfraud.Score) error {
+	score.TransactionID = transaction.ID
+	m.recorded = append(m.recorded, score)
+	return nil
+}
+
+const handlerTestUserID = "user-1"
+
+type transactionHandlerFixture struct {
+	handler      *TransactionHandler
+	transactions *MockTransactionRepository
+	accounts     *MockAccountService
+}
+
+// setupTransactionHandler builds the handler with the real transaction and duplicate services over mocks
+func setupTransactionHandler(fraudService service.FraudServiceInterface) *transactionHandlerFixture {
+	transactions := &MockTransactionRepository{}
+	accounts := &MockAccountService{}
+	transactionService := service.NewTransactionService(
+		transactions,
+		service.NewTransactionRuleService(nil),
+		service.NewTransactionAuditService(nil),
+		service.NewMockExternalService(),
+		accounts,
+		&MockCategoryService{},
+		fraudService,
+	)
+
+	return &transactionHandlerFixture{
+		handler: &TransactionHandler{
+			transactionService: transactionService,
+			duplicateService:   service.NewDuplicateService(transactions, nil),
+		},
+		transactions: transactions,
+		accounts:     accounts,
+	}
+}
+
+// addTransaction records a completed transaction created minutes ago
+func (f *transactionHandlerFixture) addTransaction(amount float64, merchant string, minutesAgo int) *domaintransaction.Transaction {
+	accountID := "account-1"
+	transaction, _ := f.transactions.Create(&domaintransaction.Transaction{
+		UserID:        handlerTestUserID,
+		InitiatedBy:   handlerTestUserID,
+		Type:          domaintransaction.TransactionTypeAccountWithdraw,
+		Status:        domaintransaction.TransactionStatusCompleted,
+		Amount:        amount,
+		Currency:      "ARS",
+		FromAccountID: &accountID,
+		MerchantName:  merchant,
+		CreatedAt:     time.Now().Add(-time.Duration(minutesAgo) * time.Minute),
+	})
+	return transaction
+}
+
+// createTransaction posts a withdrawal from account-1
+func (f *transactionHandlerFixture) createTransaction(t *testing.T, amount float64, merchant string) (*httptest.ResponseRecorder, *TransactionResponse) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]interface{}{
+		"type":          string(domaintransaction.TransactionTypeAccountWithdraw),
+		"amount":        amount,
+		"currency":      "ARS",
+		"fromAccountId": "account-1",
+		"description":   "Compra",
+		"merchantName":  merchant,
+		"paymentMethod": string(domaintransaction.PaymentMethodDebitCard),
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", handlerTestUserID)
+	w := httptest.NewRecorder()
+	f.handler.CreateTransactionHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		return w, nil
+	}
+	var response TransactionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	return w, &response
+}
+
+func TestCreateTransactionDuplicateWarning(t *testing.T) {
+	tests := []struct {
+		name               string
+		amount             float64
+		merchant           string
+		expectedCandidates []string
+	}{
+		{
+			name:               "same purchase a few minutes later",
+			amount:             1500,
+			merchant:           "Supermercado Día",
+			expectedCandidates: []string{"tx-2", "tx-1"},
+		},
+		{
+			name:     "another amount",
+			amount:   1499,
+			merchant: "Supermercado Día",
+		},
+		{
+			name:     "another merchant",
+			amount:   1500,
+			merchant: "YPF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := setupTransactionHandler(nil)
+			fixture.addTransaction(1500, "Supermercado Dia", 8)
+			fixture.addTransaction(1500, "SUPERMERCADO DIA", 3)
+			fixture.addTransaction(1500, "Supermercado Día", 30) // Outside the window
+
+			w, response := fixture.createTransaction(t, tt.amount, tt.merchant)
+			if response == nil {
+				t.Fatalf("CreateTransactionHTTP() status = %v, want %v: %s", w.Code, http.StatusCreated, w.Body.String())
+			}
+
+			// The transaction is created either way
+			if response.Status != string(domaintransaction.TransactionStatusCompleted) || len(fixture.accounts.withdrawals) != 1 {
+				t.Errorf("CreateTransactionHTTP() status = %s with %d withdrawals, want completed", response.Status, len(fixture.accounts.withdrawals))
+			}
+
+			var candidates []string
+			for _, candidate := range response.DuplicateCandidates {
+				candidates = append(candidates, candidate.TransactionID)
+			}
+			if strings.Join(candidates, ",") != strings.Join(tt.expectedCandidates, ",") {
+				t.Errorf("CreateTransactionHTTP() duplicate candidates = %v, want %v", candidates, tt.expectedCandidates)
+			}
+			if len(tt.expectedCandidates) == 0 {
+				if len(response.Warnings) != 0 {
+					t.Errorf("CreateTransactionHTTP() warnings = %v, want none", response.Warnings)
+				}
+				return
+			}
+			expectedWarning := "Possible duplicate of " + strings.Join(tt.expectedCandidates, ", ")
+			if len(response.Warnings) != 1 || response.Warnings[0] != expectedWarning {
+				t.Errorf("CreateTransactionHTTP() warnings = %v, want %q", response.Warnings, expectedWarning)
+			}
+		})
+	}
+}
+
+func TestCreateTransactionFraudChecks(t *testing.T) {
+	tests := []struct {
+		name               string
+		action             domainfraud.Action
+		expectedCode       int
+		expectedStatus     domaintransaction.TransactionStatus
+		expectedWarning    string
+		expectedWithdrawal bool
+	}{
+		{
+			name:               "allowed",
+			action:             domainfraud.ActionAllow,
+			expectedCode:       http.StatusCreated,
+			expectedStatus:     domaintransaction.TransactionStatusCompleted,
+			expectedWithdrawal: true,
+		},
+		{
+			name:               "alerted",
+			action:             domainfraud.ActionAlert,
+			expectedCode:       http.StatusCreated,
+			expectedStatus:     domaintransaction.TransactionStatusCompleted,
+			expectedWithdrawal: true,
+		},
+		{
+			name:            "held",
+			action:          domainfraud.ActionHold,
+			expectedCode:    http.StatusCreated,
+			expectedStatus:  domaintransaction.TransactionStatusPending,
+			expectedWarning: "Held for review by the fraud checks; release it with POST /api/v1/transactions/tx-1/release",
+		},
+		{
+			name:           "blocked",
+			action:         domainfraud.ActionBlock,
+			expectedCode:   http.StatusForbidden,
+			expectedStatus: domaintransaction.TransactionStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fraudService := &MockFraudService{action: tt.action}
+			fixture := setupTransactionHandler(fraudService)
+
+			w, response := fixture.createTransaction(t, 1500, "Supermercado Día")
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("CreateTransactionHTTP() status = %v, want %v: %s", w.Code, tt.expectedCode, w.Body.String())
+			}
+			if withdrawn := len(fixture.accounts.withdrawals) > 0; withdrawn != tt.expectedWithdrawal {
+				t.Errorf("CreateTransactionHTTP() withdrew funds = %v, want %v", withdrawn, tt.expectedWithdrawal)
+			}
+
+			// The transaction and its score are saved whatever the action
+			if len(fixture.transactions.transactions) != 1 || fixture.transactions.transactions[0].Status != tt.expectedStatus {
+				t.Fatalf("CreateTransactionHTTP() saved %d transactions, want one %s", len(fixture.transactions.transactions), tt.expectedStatus)
+			}
+			if len(fraudService.recorded) != 1 || fraudService.recorded[0].TransactionID != "tx-1" {
+				t.Errorf("CreateTransactionHTTP() recorded scores = %v, want the score of tx-1", fraudService.recorded)
+			}
+
+			if tt.expectedCode != http.StatusCreated {
+				var errorResponse ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &errorResponse); err != nil {
+					t.Fatalf("failed to parse response: %v", err)
+				}
+				if errorResponse.Error != "Transaction blocked" || !strings.Contains(errorResponse.Message, "First use of card card-9") {
+					t.Errorf("CreateTransactionHTTP() error = %+v, want the block with its reasons", errorResponse)
+				}
+				return
+			}
+
+			if response.Status != string(tt.expectedStatus) {
+				t.Errorf("CreateTransactionHTTP() status = %s, want %s", response.Status, tt.expectedStatus)
+			}
+			var warnings []string
+			if tt.expectedWarning != "" {
+				warnings = []string{tt.expectedWarning}
+			}
+			if strings.Join(response.Warnings, "|") != strings.Join(warnings, "|") {
+				t.Errorf("CreateTransactionHTTP() warnings = %v, want %v", response.Warnings, warnings)
+			}
+		})
+	}
+}
+
+func TestListTransactionsPaging(t *testing.T) {
+	amountCursor := domaintransaction.NewCursor(&domaintransaction.Transaction{ID: "tx-1", Amount: 1500}, domaintransaction.SortByAmount, true).Encode()
+
+	tests := []struct {
+		name               string
+		stored             int
+		query              string
+		expectedCode       int
+		expectedCount      int
+		expectedNextCursor string // ID of the transaction the next cursor points after, empty for none
+	}{
+		{
+			name:               "full page",
+			stored:             4,
+			query:              "limit=2",
+			expectedCode:       http.StatusOK,
+			expectedCount:      2,
+			expectedNextCursor: "tx-2",
+		},
+		{
+			// The page after it comes back empty and without a cursor
+			name:               "full page ending with the last transaction",
+			stored:             4,
+			query:              "limit=4",
+			expectedCode:       http.StatusOK,
+			expectedCount:      4,
+			expectedNextCursor: "tx-4",
+		},
+		{
+			name:          "last page",
+			stored:        3,
+			query:         "limit=4",
+			expectedCode:  http.StatusOK,
+			expectedCount: 3,
+		},
+		{
+			name:          "empty page",
+			query:         "limit=4&cursor=" + amountCursor + "&orderBy=amount",
+			expectedCode:  http.StatusOK,
+			expectedCount: 0,
+		},
+		{
+			name:         "sort column outside the whitelist",
+			stored:       4,
+			query:        "orderBy=merchant_name",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "cursor that does not decode",
+			stored:       4,
+			query:        "cursor=not-a-cursor",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "cursor of another order",
+			stored:       4,
+			query:        "cursor=" + amountCursor + "&orderBy=amount&order=desc",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := setupTransactionHandler(nil)
+			for i := 0; i < tt.stored; i++ {
+				fixture.addTransaction(1500, "Supermercado Día", 60-i)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions?"+tt.query, nil)
+			req.Header.Set("X-User-ID", handlerTestUserID)
+			w := httptest.NewRecorder()
+			fixture.handler.ListTransactionsHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("ListTransactionsHTTP() status = %v, want %v: %s", w.Code, tt.expectedCode, w.Body.String())
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response TransactionListResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if len(response.Transactions) != tt.expectedCount || response.Total != tt.stored {
+				t.Errorf("ListTransactionsHTTP() = %d of %d transactions, want %d of %d", len(response.Transactions), response.Total, tt.expectedCount, tt.stored)
+			}
+
+			if tt.expectedNextCursor == "" {
+				if response.NextCursor != "" {
+					t.Errorf("ListTransactionsHTTP() next cursor = %s, want none", response.NextCursor)
+				}
+				return
+			}
+			cursor, err := domaintransaction.DecodeCursor(response.NextCursor)
+			if err != nil {
+				t.Fatalf("DecodeCursor() unexpected error: %v", err)
+			}
+			if cursor.ID != tt.expectedNextCursor || cursor.SortKey != domaintransaction.SortByCreatedAt || cursor.Ascending {
+				t.Errorf("ListTransactionsHTTP() next cursor = %+v, want after %s newest first", *cursor, tt.expectedNextCursor)
+			}
+		})
+	}
+}
+
+func TestGetTransactionSharedAccount(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		expectedCode int
+	}{
+		{name: "owner", userID: handlerTestUserID, expectedCode: http.StatusOK},
+		{name: "viewer of the shared account", userID: "user-viewer", expectedCode: http.StatusOK},
+		{name: "co-owner of the shared account", userID: "user-co-owner", expectedCode: http.StatusOK},
+		{name: "member of another account", userID: "user-other", expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := setupTransactionHandler(nil)
+			fixture.transactions.sharedAccounts = map[string][]string{
+				"user-viewer":   {"account-1"},
+				"user-co-owner": {"account-1"},
+				"user-other":    {"account-2"},
+			}
+			transaction := fixture.addTransaction(1500, "Supermercado Día", 10)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions/"+transaction.ID, nil)
+			req.Header.Set("X-User-ID", tt.userID)
+			w := httptest.NewRecorder()
+			fixture.handler.GetTransactionHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("GetTransactionHTTP() status = %v, want %v: %s", w.Code, tt.expectedCode, w.Body.String())
+			}
+			if tt.expectedCode != http.StatusOK {
+				if !strings.Contains(w.Body.String(), "unauthorized") {
+					t.Errorf("GetTransactionHTTP() = %s, want unauthorized", w.Body.String())
+				}
+				return
+			}
+			var response TransactionResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if response.ID != transaction.ID {
+				t.Errorf("GetTransactionHTTP() = %s, want %s", response.ID, transaction.ID)
+			}
+		})
+	}
+}
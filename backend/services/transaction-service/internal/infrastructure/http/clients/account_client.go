@@ -5,19 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 )
 
 // AccountClient maneja la comunicación con el account-service
 type AccountClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL       string
+	internalToken string // Token compartido que identifica al servicio en el account-service
+	httpClient    *http.Client
 }
 
 // NewAccountClient crea una nueva instancia del cliente
 func NewAccountClient(baseURL string) *AccountClient {
 	return &AccountClient{
-		baseURL: baseURL,
+		baseURL:       baseURL,
+		internalToken: os.Getenv("INTERNAL_SERVICE_TOKEN"),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -204,6 +207,7 @@ type CardChargeRequest struct {
 	Amount      float64 `json:"amount"`
 	Description string  `json:"description"`
 	Reference   string  `json:"reference,omitempty"`
+	PerformedBy string  `json:"-"` // Usuario que opera la tarjeta; vacío solo en operaciones internas
 }
 
 // CardPaymentRequest representa una solicitud de pago a tarjeta de crédito
@@ -212,6 +216,7 @@ type CardPaymentRequest struct {
 	Amount        float64 `json:"amount"`
 	PaymentMethod string  `json:"payment_method"`
 	Reference     string  `json:"reference,omitempty"`
+	PerformedBy   string  `json:"-"` // Usuario que opera la tarjeta; vacío solo en operaciones internas
 }
 
 // DebitTransactionRequest representa una solicitud de transacción con tarjeta de débito
@@ -221,6 +226,7 @@ type DebitTransactionRequest struct {
 	Description  string  `json:"description"`
 	MerchantName string  `json:"merchant_name"`
 	Reference    string  `json:"reference,omitempty"`
+	PerformedBy  string  `json:"-"` // Usuario que opera la tarjeta; vacío solo en operaciones internas
 }
 
 // CardOperationResponse representa la respuesta de operaciones de tarjetas
@@ -235,29 +241,42 @@ type CardOperationResponse struct {
 // ChargeCard procesa un cargo en una tarjeta de crédito
 func (c *AccountClient) ChargeCard(req CardChargeRequest) (*CardOperationResponse, error) {
 	url := fmt.Sprintf("%s/api/cards/%s/charge", c.baseURL, req.CardID)
-	return c.processCardOperation(url, req)
+	return c.processCardOperation(url, req.PerformedBy, req)
 }
 
 // PaymentCard procesa un pago a una tarjeta de crédito
 func (c *AccountClient) PaymentCard(req CardPaymentRequest) (*CardOperationResponse, error) {
 	url := fmt.Sprintf("%s/api/cards/%s/payment", c.baseURL, req.CardID)
-	return c.processCardOperation(url, req)
+	return c.processCardOperation(url, req.PerformedBy, req)
 }
 
 // ProcessDebitTransaction procesa una transacción con tarjeta de débito
 func (c *AccountClient) ProcessDebitTransaction(req DebitTransactionRequest) (*CardOperationResponse, error) {
 	url := fmt.Sprintf("%s/api/cards/%s/transaction", c.baseURL, req.CardID)
-	return c.processCardOperation(url, req)
+	return c.processCardOperation(url, req.PerformedBy, req)
 }
 
-// Helper method para operaciones de tarjetas
-func (c *AccountClient) processCardOperation(url string, request interface{}) (*CardOperationResponse, error) {
+// Helper method para operaciones de tarjetas. El account-service verifica que el usuario pueda
+// operar la tarjeta; sin usuario, la operación solo se acepta con el token interno.
+func (c *AccountClient) processCardOperation(url, performedBy string, request interface{}) (*CardOperationResponse, error) {
 	requestBody, err := json.Marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf("error marshaling request: %w", err)
 	}
 
-	resp, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(requestBody))
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if performedBy != "" {
+		httpReq.Header.Set("X-User-ID", performedBy)
+	}
+	if c.internalToken != "" {
+		httpReq.Header.Set("X-Internal-Service-Token", c.internalToken)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("error calling account service: %w", err)
 	}
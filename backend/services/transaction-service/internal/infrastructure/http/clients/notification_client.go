@@ -0,0 +1,133 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// NotificationClient maneja la comunicación con el notification-service
+type NotificationClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewNotificationClient crea una nueva instancia del cliente
+func NewNotificationClient(baseURL string) *NotificationClient {
+	return &NotificationClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// ScheduledTransactionFailure representa la falla de una ocurrencia de una transacción programada
+type ScheduledTransactionFailure struct {
+	UserID       string    `json:"userId"`
+	ScheduleID   string    `json:"scheduleId"`
+	Description  string    `json:"description"`
+	Amount       float64   `json:"amount"`
+	Currency     string    `json:"currency"`
+	ScheduledFor time.Time `json:"scheduledFor"`
+	Reason       string    `json:"reason"`
+	Paused       bool      `json:"paused"` // La programación quedó pausada por fallas consecutivas
+}
+
+// NotifyScheduledTransactionFailure avisa al usuario que una transacción programada no se pudo ejecutar
+func (c *NotificationClient) NotifyScheduledTransactionFailure(failure ScheduledTransactionFailure) error {
+	url := fmt.Sprintf("%s/api/notifications/scheduled-transaction-failure", c.baseURL)
+
+	requestBody, err := json.Marshal(failure)
+	if err != nil {
+		return fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return fmt.Errorf("error calling notification service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("notification service returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// FraudAlert representa una transacción cuyo puntaje de riesgo alcanzó el umbral de alerta del usuario
+type FraudAlert struct {
+	UserID        string    `json:"userId"`
+	TransactionID string    `json:"transactionId"`
+	Description   string    `json:"description"`
+	MerchantName  string    `json:"merchantName"`
+	Amount        float64   `json:"amount"`
+	Currency      string    `json:"currency"`
+	Score         int       `json:"score"`
+	Action        string    `json:"action"`  // alert, hold o block
+	Reasons       []string  `json:"reasons"` // Detalle de las señales que sumaron puntaje
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// NotifyFraudAlert avisa al usuario de una transacción sospechosa y de lo que se hizo con ella
+func (c *NotificationClient) NotifyFraudAlert(alert FraudAlert) error {
+	url := fmt.Sprintf("%s/api/notifications/fraud-alert", c.baseURL)
+
+	requestBody, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return fmt.Errorf("error calling notification service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("notification service returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// BudgetAlert representa un presupuesto mensual que alcanzó un umbral de alerta (50%, 80% o 100%)
+type BudgetAlert struct {
+	UserID       string  `json:"userId"`
+	BudgetID     string  `json:"budgetId"`
+	BudgetName   string  `json:"budgetName"`
+	Scope        string  `json:"scope"` // category, merchant o card
+	Month        string  `json:"month"` // YYYY-MM
+	Threshold    int     `json:"threshold"`
+	Limit        float64 `json:"limit"`
+	Actual       float64 `json:"actual"`
+	Remaining    float64 `json:"remaining"`
+	PercentUsed  float64 `json:"percentUsed"`
+	Installments float64 `json:"installments"` // Parte del gasto que son cuotas del mes
+	Currency     string  `json:"currency"`
+}
+
+// NotifyBudgetAlert avisa al usuario que un presupuesto alcanzó un umbral de alerta
+func (c *NotificationClient) NotifyBudgetAlert(alert BudgetAlert) error {
+	url := fmt.Sprintf("%s/api/notifications/budget-alert", c.baseURL)
+
+	requestBody, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return fmt.Errorf("error calling notification service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("notification service returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// AttachmentCleaner periodically deletes the attachments of purged transactions and installment plans
+type AttachmentCleaner struct {
+	attachmentService service.AttachmentServiceInterface
+	interval          time.Duration
+	stop              chan struct{}
+	done              chan struct{}
+	stopOnce          sync.Once
+}
+
+// NewAttachmentCleaner creates a new cleaner that runs every interval
+func NewAttachmentCleaner(attachmentService service.AttachmentServiceInterface, interval time.Duration) *AttachmentCleaner {
+	return &AttachmentCleaner{
+		attachmentService: attachmentService,
+		interval:          interval,
+		stop:              make(chan struct{}),
+		done:              make(chan struct{}),
+	}
+}
+
+// Start runs the cleaner in the background until Stop is called
+func (c *AttachmentCleaner) Start() {
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		c.runOnce()
+		for {
+			select {
+			case <-ticker.C:
+				c.runOnce()
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the cleaner and waits for the current pass to finish
+func (c *AttachmentCleaner) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+	<-c.done
+}
+
+// runOnce purges the orphan attachments found in a pass
+func (c *AttachmentCleaner) runOnce() {
+	purged, err := c.attachmentService.PurgeOrphans()
+	if err != nil {
+		log.Printf("Attachment cleanup failed: %v", err)
+		return
+	}
+
+	if purged > 0 {
+		log.Printf("Attachment cleanup: %d orphan attachments deleted", purged)
+	}
+}
@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// BudgetRefresher periodically refreshes the current month of the budgets read by reports and the chatbot
+// and sends the alerts of the thresholds they reached
+type BudgetRefresher struct {
+	budgetService service.BudgetServiceInterface
+	interval      time.Duration
+	stop          chan struct{}
+	done          chan struct{}
+	stopOnce      sync.Once
+}
+
+// NewBudgetRefresher creates a new refresher that runs every interval
+func NewBudgetRefresher(budgetService service.BudgetServiceInterface, interval time.Duration) *BudgetRefresher {
+	return &BudgetRefresher{
+		budgetService: budgetService,
+		interval:      interval,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start runs the refresher in the background until Stop is called
+func (r *BudgetRefresher) Start() {
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		r.runOnce()
+		for {
+			select {
+			case <-ticker.C:
+				r.runOnce()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the refresher and waits for the current pass to finish
+func (r *BudgetRefresher) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+}
+
+// runOnce refreshes every budget that applies to the current month
+func (r *BudgetRefresher) runOnce() {
+	refreshed, err := r.budgetService.RefreshAll(time.Now())
+	if err != nil {
+		log.Printf("Budget refresh failed: %v", err)
+		return
+	}
+
+	log.Printf("Budget refresh: %d budgets refreshed", refreshed)
+}
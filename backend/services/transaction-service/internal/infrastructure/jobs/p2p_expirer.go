@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// P2PExpirer periodically expires the transfers between users nobody answered in time, refunding the sender
+type P2PExpirer struct {
+	p2pService service.P2PServiceInterface
+	interval   time.Duration
+	stop       chan struct{}
+	done       chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewP2PExpirer creates a new expirer that runs every interval
+func NewP2PExpirer(p2pService service.P2PServiceInterface, interval time.Duration) *P2PExpirer {
+	return &P2PExpirer{
+		p2pService: p2pService,
+		interval:   interval,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start runs the expirer in the background until Stop is called
+func (e *P2PExpirer) Start() {
+	go func() {
+		defer close(e.done)
+
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		e.runOnce()
+		for {
+			select {
+			case <-ticker.C:
+				e.runOnce()
+			case <-e.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the expirer and waits for the current pass to finish
+func (e *P2PExpirer) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stop)
+	})
+	<-e.done
+}
+
+// runOnce expires the transfers past their expiry found in a pass
+func (e *P2PExpirer) runOnce() {
+	expired, err := e.p2pService.ExpirePending(time.Now())
+	if err != nil {
+		log.Printf("Transfer expiry failed: %v", err)
+		return
+	}
+
+	if expired > 0 {
+		log.Printf("Transfer expiry: %d unanswered transfers expired", expired)
+	}
+}
@@ -0,0 +1,84 @@
+package jobs
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// ScheduleRunner periodically materializes the due occurrences of scheduled transactions
+type ScheduleRunner struct {
+	scheduleService service.ScheduleServiceInterface
+	interval        time.Duration
+	stop            chan struct{}
+	done            chan struct{}
+	stopOnce        sync.Once
+}
+
+// NewScheduleRunner creates a new scheduler that runs every interval
+func NewScheduleRunner(scheduleService service.ScheduleServiceInterface, interval time.Duration) *ScheduleRunner {
+	return &ScheduleRunner{
+		scheduleService: scheduleService,
+		interval:        interval,
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+}
+
+// Start runs the scheduler in the background until Stop is called
+func (r *ScheduleRunner) Start() {
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		r.runOnce()
+		for {
+			select {
+			case <-ticker.C:
+				r.runOnce()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduler and waits for the current pass to finish
+func (r *ScheduleRunner) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+}
+
+// runOnce processes every due occurrence, in batches, until none is left
+func (r *ScheduleRunner) runOnce() {
+	for {
+		summary, err := r.scheduleService.RunDueSchedules(time.Now())
+		if err != nil {
+			log.Printf("Scheduled transactions run failed: %v", err)
+			return
+		}
+		if summary.Due == 0 {
+			return
+		}
+
+		log.Printf("Scheduled transactions run: %d due, %d executed, %d failed, %d paused",
+			summary.Due, summary.Executed, summary.Failed, summary.Paused)
+
+		// A batch where nothing moved forward would be picked up again; wait for the next tick
+		if summary.Executed+summary.Failed == 0 {
+			return
+		}
+
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+	}
+}
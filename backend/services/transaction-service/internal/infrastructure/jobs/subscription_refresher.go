@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// SubscriptionRefresher periodically refreshes the detected subscriptions read by reports and the chatbot
+type SubscriptionRefresher struct {
+	subscriptionService service.SubscriptionServiceInterface
+	interval            time.Duration
+	stop                chan struct{}
+	done                chan struct{}
+	stopOnce            sync.Once
+}
+
+// NewSubscriptionRefresher creates a new refresher that runs every interval
+func NewSubscriptionRefresher(subscriptionService service.SubscriptionServiceInterface, interval time.Duration) *SubscriptionRefresher {
+	return &SubscriptionRefresher{
+		subscriptionService: subscriptionService,
+		interval:            interval,
+		stop:                make(chan struct{}),
+		done:                make(chan struct{}),
+	}
+}
+
+// Start runs the refresher in the background until Stop is called
+func (r *SubscriptionRefresher) Start() {
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		r.runOnce()
+		for {
+			select {
+			case <-ticker.C:
+				r.runOnce()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the refresher and waits for the current pass to finish
+func (r *SubscriptionRefresher) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+}
+
+// runOnce refreshes the subscriptions of every user with recent charges
+func (r *SubscriptionRefresher) runOnce() {
+	refreshed, err := r.subscriptionService.RefreshAll(time.Now())
+	if err != nil {
+		log.Printf("Subscription refresh failed: %v", err)
+		return
+	}
+
+	log.Printf("Subscription refresh: %d users refreshed", refreshed)
+}
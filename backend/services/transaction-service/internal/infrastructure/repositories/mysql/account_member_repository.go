@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// AccountMemberRepository implements the AccountMemberRepositoryInterface for MySQL
+// It reads the account_members and cards tables of the account service
+type AccountMemberRepository struct {
+	db *sql.DB
+}
+
+// NewAccountMemberRepository creates a new MySQL account member repository
+func NewAccountMemberRepository(db *sql.DB) service.AccountMemberRepositoryInterface {
+	return &AccountMemberRepository{
+		db: db,
+	}
+}
+
+// GetActiveMembership returns the active membership of a user in an account
+func (r *AccountMemberRepository) GetActiveMembership(accountID, userID string) (*service.AccountMembership, error) {
+	query := `
+		SELECT account_id, user_id, role, card_id
+		FROM account_members
+		WHERE account_id = ? AND user_id = ? AND status = 'active'
+		LIMIT 1`
+
+	membership := &service.AccountMembership{}
+	var cardID sql.NullString
+	err := r.db.QueryRow(query, accountID, userID).Scan(&membership.AccountID, &membership.UserID, &membership.Role, &cardID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account membership: %w", err)
+	}
+	if cardID.Valid {
+		membership.CardID = &cardID.String
+	}
+	return membership, nil
+}
+
+// GetCardAccountID returns the account a card belongs to
+func (r *AccountMemberRepository) GetCardAccountID(cardID string) (string, error) {
+	var accountID string
+	err := r.db.QueryRow(`SELECT account_id FROM cards WHERE id = ? AND deleted_at IS NULL`, cardID).Scan(&accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get account of card: %w", err)
+	}
+	return accountID, nil
+}
@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// AllocationRepository implements the AllocationRepositoryInterface for MySQL
+type AllocationRepository struct {
+	db *sql.DB
+}
+
+// NewAllocationRepository creates a new MySQL allocation repository
+func NewAllocationRepository(db *sql.DB) service.AllocationRepositoryInterface {
+	return &AllocationRepository{
+		db: db,
+	}
+}
+
+// GetByTransactionID retrieves the allocations of a transaction in the order they were created
+func (r *AllocationRepository) GetByTransactionID(transactionID string) ([]*domaintransaction.Allocation, error) {
+	query := `
+		SELECT id, transaction_id, amount, category_id, note, counterpart_user_id, created_at
+		FROM transaction_allocations
+		WHERE transaction_id = ?
+		ORDER BY position ASC`
+
+	rows, err := r.db.Query(query, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query allocations: %w", err)
+	}
+	defer rows.Close()
+
+	allocations := []*domaintransaction.Allocation{}
+	for rows.Next() {
+		allocation := &domaintransaction.Allocation{}
+		var note sql.NullString
+
+		err := rows.Scan(
+			&allocation.ID, &allocation.TransactionID, &allocation.Amount, &allocation.CategoryID,
+			&note, &allocation.CounterpartUserID, &allocation.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan allocation: %w", err)
+		}
+
+		allocation.Note = note.String
+		allocations = append(allocations, allocation)
+	}
+
+	return allocations, rows.Err()
+}
+
+// ReplaceForTransaction atomically replaces every allocation of a transaction
+func (r *AllocationRepository) ReplaceForTransaction(transactionID string, allocations []*domaintransaction.Allocation) ([]*domaintransaction.Allocation, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM transaction_allocations WHERE transaction_id = ?", transactionID); err != nil {
+		return nil, fmt.Errorf("failed to delete allocations: %w", err)
+	}
+
+	query := `
+		INSERT INTO transaction_allocations (
+			id, transaction_id, position, amount, category_id, note, counterpart_user_id, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, NOW())`
+
+	for i, allocation := range allocations {
+		allocation.ID = fmt.Sprintf("alloc_%d_%d", time.Now().UnixNano(), i)
+		allocation.TransactionID = transactionID
+
+		_, err := tx.Exec(query,
+			allocation.ID, transactionID, i, allocation.Amount, allocation.CategoryID,
+			allocation.Note, allocation.CounterpartUserID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create allocation: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit allocations: %w", err)
+	}
+
+	return r.GetByTransactionID(transactionID)
+}
+
+// DeleteByTransactionID removes the allocations of a transaction
+func (r *AllocationRepository) DeleteByTransactionID(transactionID string) error {
+	if _, err := r.db.Exec("DELETE FROM transaction_allocations WHERE transaction_id = ?", transactionID); err != nil {
+		return fmt.Errorf("failed to delete allocations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainattachment "github.com/fintrack/transaction-service/internal/core/domain/entities/attachment"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// AttachmentRepository implements the AttachmentRepositoryInterface for MySQL
+type AttachmentRepository struct {
+	db *sql.DB
+}
+
+// NewAttachmentRepository creates a new MySQL attachment repository
+func NewAttachmentRepository(db *sql.DB) service.AttachmentRepositoryInterface {
+	return &AttachmentRepository{
+		db: db,
+	}
+}
+
+const attachmentColumns = `
+	id, user_id, owner_type, owner_id, file_name, content_type, size_bytes, storage_key, created_at`
+
+const orphanAttachmentColumns = `
+	a.id, a.user_id, a.owner_type, a.owner_id, a.file_name, a.content_type, a.size_bytes, a.storage_key, a.created_at`
+
+// Create stores the metadata of an attachment whose content is already in the storage, if it fits in the quota.
+// The user is locked until the insert commits, so concurrent uploads cannot exceed the quota together.
+func (r *AttachmentRepository) Create(attachment *domainattachment.Attachment, quota int64) (*domainattachment.Attachment, error) {
+	attachment.CreatedAt = time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var userID string
+	if err := tx.QueryRow(`SELECT id FROM users WHERE id = ? FOR UPDATE`, attachment.UserID).Scan(&userID); err != nil {
+		return nil, fmt.Errorf("failed to lock the user of the attachment: %w", err)
+	}
+
+	query := `
+		INSERT INTO transaction_attachments (
+			id, user_id, owner_type, owner_id, file_name, content_type, size_bytes, storage_key, created_at
+		)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?
+		FROM DUAL
+		WHERE (SELECT COALESCE(SUM(size_bytes), 0) FROM transaction_attachments WHERE user_id = ?) + ? <= ?`
+
+	result, err := tx.Exec(query,
+		attachment.ID, attachment.UserID, attachment.OwnerType, attachment.OwnerID, attachment.FileName,
+		attachment.ContentType, attachment.Size, attachment.StorageKey, attachment.CreatedAt,
+		attachment.UserID, attachment.Size, quota,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	if rows == 0 {
+		return nil, service.ErrAttachmentQuotaExceeded
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// GetByID retrieves an attachment by ID
+func (r *AttachmentRepository) GetByID(id string) (*domainattachment.Attachment, error) {
+	query := "SELECT" + attachmentColumns + " FROM transaction_attachments WHERE id = ?"
+
+	attachment, err := scanAttachment(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("attachment not found with ID: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// GetByOwner returns the attachments of a transaction or installment plan, oldest first
+func (r *AttachmentRepository) GetByOwner(ownerType domainattachment.OwnerType, ownerID string) ([]*domainattachment.Attachment, error) {
+	query := "SELECT" + attachmentColumns + `
+		FROM transaction_attachments
+		WHERE owner_type = ? AND owner_id = ?
+		ORDER BY created_at, id`
+
+	return r.queryAttachments(query, ownerType, ownerID)
+}
+
+// GetByUserID returns the attachments of the transactions the user can see, or of their installment plans
+func (r *AttachmentRepository) GetByUserID(userID string, ownerType domainattachment.OwnerType) ([]*domainattachment.Attachment, error) {
+	var owners string
+	var args []interface{}
+	switch ownerType {
+	case domainattachment.OwnerTransaction:
+		scope := userScope(userID)
+		owners = "SELECT id FROM transactions WHERE " + scope.condition
+		args = scope.args
+	case domainattachment.OwnerInstallmentPlan:
+		owners = "SELECT id FROM installment_plans WHERE user_id = ?"
+		args = []interface{}{userID}
+	default:
+		return nil, fmt.Errorf("invalid attachment owner type: %s", ownerType)
+	}
+
+	query := "SELECT" + attachmentColumns + `
+		FROM transaction_attachments
+		WHERE owner_type = ? AND owner_id IN (` + owners + `)
+		ORDER BY owner_id, created_at, id`
+
+	return r.queryAttachments(query, append([]interface{}{ownerType}, args...)...)
+}
+
+// Delete removes the metadata of an attachment
+func (r *AttachmentRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM transaction_attachments WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("attachment not found with ID: %s", id)
+	}
+
+	return nil
+}
+
+// GetUsage returns how many files and bytes the user uploaded
+func (r *AttachmentRepository) GetUsage(userID string) (int, int64, error) {
+	query := "SELECT COUNT(*), COALESCE(SUM(size_bytes), 0) FROM transaction_attachments WHERE user_id = ?"
+
+	var files int
+	var used int64
+	if err := r.db.QueryRow(query, userID).Scan(&files, &used); err != nil {
+		return 0, 0, fmt.Errorf("failed to get attachment usage: %w", err)
+	}
+
+	return files, used, nil
+}
+
+// GetOrphans returns attachments whose transaction or installment plan no longer exists, e.g. after an
+// import rollback or when the account service deletes a plan
+func (r *AttachmentRepository) GetOrphans(limit int) ([]*domainattachment.Attachment, error) {
+	query := "SELECT" + orphanAttachmentColumns + `
+		FROM transaction_attachments a
+		LEFT JOIN transactions t ON a.owner_type = 'transaction' AND t.id = a.owner_id
+		LEFT JOIN installment_plans p ON a.owner_type = 'installment_plan' AND p.id = a.owner_id
+		WHERE t.id IS NULL AND p.id IS NULL
+		ORDER BY a.created_at
+		LIMIT ?`
+
+	return r.queryAttachments(query, limit)
+}
+
+// GetInstallmentPlanUserID returns the owner of an installment plan (managed by the account service)
+func (r *AttachmentRepository) GetInstallmentPlanUserID(planID string) (string, error) {
+	var userID string
+	err := r.db.QueryRow("SELECT user_id FROM installment_plans WHERE id = ?", planID).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("installment plan not found with ID: %s", planID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get installment plan: %w", err)
+	}
+
+	return userID, nil
+}
+
+func (r *AttachmentRepository) queryAttachments(query string, args ...interface{}) ([]*domainattachment.Attachment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []*domainattachment.Attachment{}
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+// attachmentScanner is implemented by *sql.Row and *sql.Rows
+type attachmentScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAttachment(scanner attachmentScanner) (*domainattachment.Attachment, error) {
+	attachment := &domainattachment.Attachment{}
+
+	err := scanner.Scan(
+		&attachment.ID, &attachment.UserID, &attachment.OwnerType, &attachment.OwnerID, &attachment.FileName,
+		&attachment.ContentType, &attachment.Size, &attachment.StorageKey, &attachment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
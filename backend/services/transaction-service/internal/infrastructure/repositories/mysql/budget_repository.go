@@ -0,0 +1,297 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	domainbudget "github.com/fintrack/transaction-service/internal/core/domain/entities/budget"
+	"github.com/fintrack/transaction-service/internal/core/service"
+	gomysql "github.com/go-sql-driver/mysql"
+)
+
+// BudgetRepository implements the BudgetRepositoryInterface for MySQL
+type BudgetRepository struct {
+	db *sql.DB
+}
+
+// NewBudgetRepository creates a new MySQL budget repository
+func NewBudgetRepository(db *sql.DB) service.BudgetRepositoryInterface {
+	return &BudgetRepository{
+		db: db,
+	}
+}
+
+const budgetColumns = `
+	id, user_id, name, scope, category_id, merchant_name, card_id, amount, currency, rollover,
+	start_month, end_month, alerts_enabled, created_at, updated_at`
+
+// Create persists a new budget
+func (r *BudgetRepository) Create(budget *domainbudget.Budget) error {
+	_, err := r.db.Exec(`
+		INSERT INTO budgets (
+			id, user_id, name, scope, category_id, merchant_name, card_id, amount, currency, rollover,
+			start_month, end_month, alerts_enabled, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		budget.ID, budget.UserID, budget.Name, budget.Scope, budget.CategoryID, budget.MerchantName, budget.CardID,
+		budget.Amount, budget.Currency, budget.Rollover, budget.StartMonth, budget.EndMonth, budget.AlertsEnabled,
+		budget.CreatedAt, budget.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create budget: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a budget
+func (r *BudgetRepository) GetByID(id string) (*domainbudget.Budget, error) {
+	budget, err := scanBudget(r.db.QueryRow("SELECT"+budgetColumns+" FROM budgets WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("budget not found with ID: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+	return budget, nil
+}
+
+// GetByUserID returns the budgets of a user, by name
+func (r *BudgetRepository) GetByUserID(userID string) ([]*domainbudget.Budget, error) {
+	return r.query("SELECT"+budgetColumns+`
+		FROM budgets
+		WHERE user_id = ?
+		ORDER BY name ASC, created_at ASC`, userID)
+}
+
+// GetActive returns the budgets of every user that apply to a month
+func (r *BudgetRepository) GetActive(month string) ([]*domainbudget.Budget, error) {
+	return r.query("SELECT"+budgetColumns+`
+		FROM budgets
+		WHERE start_month <= ? AND (end_month IS NULL OR end_month >= ?)
+		ORDER BY user_id ASC, created_at ASC`, month, month)
+}
+
+// Update replaces the settings of a budget and drops the snapshot of its months, computed with the
+// previous settings
+func (r *BudgetRepository) Update(budget *domainbudget.Budget) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE budgets
+		SET name = ?, scope = ?, category_id = ?, merchant_name = ?, card_id = ?, amount = ?, currency = ?,
+			rollover = ?, start_month = ?, end_month = ?, alerts_enabled = ?, updated_at = ?
+		WHERE id = ?`,
+		budget.Name, budget.Scope, budget.CategoryID, budget.MerchantName, budget.CardID, budget.Amount,
+		budget.Currency, budget.Rollover, budget.StartMonth, budget.EndMonth, budget.AlertsEnabled,
+		budget.UpdatedAt, budget.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update budget: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("budget not found with ID: %s", budget.ID)
+	}
+
+	if _, err := tx.Exec("DELETE FROM budget_periods WHERE budget_id = ?", budget.ID); err != nil {
+		return fmt.Errorf("failed to delete budget months: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit budget: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a budget; its months and alerts go with it
+func (r *BudgetRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM budgets WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("budget not found with ID: %s", id)
+	}
+	return nil
+}
+
+// GetSpending sums the expenses in the scope and currency of a budget between two dates. Only the own
+// parts of split transactions count, like in reports. Purchases paid in installments count through the
+// installments due in the range, attributed to the category of the purchase and the merchant and card
+// of the plan.
+func (r *BudgetRepository) GetSpending(budget *domainbudget.Budget, from, to time.Time) (*service.BudgetSpending, error) {
+	var transactionScope, installmentScope string
+	var scopeArgs []interface{}
+	switch budget.Scope {
+	case domainbudget.ScopeCategory:
+		transactionScope = "AND (c.id = ? OR c.parent_id = ?)"
+		installmentScope = "AND (c.id = ? OR c.parent_id = ?)"
+		scopeArgs = []interface{}{*budget.CategoryID, *budget.CategoryID}
+	case domainbudget.ScopeMerchant:
+		transactionScope = "AND t.merchant_name = ?"
+		installmentScope = "AND p.merchant_name = ?"
+		scopeArgs = []interface{}{*budget.MerchantName}
+	case domainbudget.ScopeCard:
+		transactionScope = "AND t.from_card_id = ?"
+		installmentScope = "AND p.card_id = ?"
+		scopeArgs = []interface{}{*budget.CardID}
+	default:
+		return nil, fmt.Errorf("invalid scope: %s", budget.Scope)
+	}
+
+	spending := &service.BudgetSpending{}
+
+	args := append([]interface{}{budget.UserID, budget.Currency, from, to}, scopeArgs...)
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(COALESCE(a.amount, t.amount)), 0)
+		FROM transactions t
+		LEFT JOIN transaction_allocations a ON a.transaction_id = t.id
+		LEFT JOIN transaction_categories c ON c.id = COALESCE(a.category_id, t.category_id)
+		WHERE t.user_id = ?
+			AND t.status = 'completed'
+			AND t.type IN ('debit_purchase', 'credit_charge', 'wallet_withdrawal', 'account_withdraw')
+			AND t.currency = ?
+			AND t.created_at >= ? AND t.created_at < ?
+			AND a.counterpart_user_id IS NULL
+			AND NOT EXISTS (SELECT 1 FROM installment_plans p WHERE p.transaction_id = t.id)
+			`+transactionScope, args...,
+	).Scan(&spending.Transactions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+
+	args = append([]interface{}{budget.UserID, budget.Currency, from, to}, scopeArgs...)
+	err = r.db.QueryRow(`
+		SELECT COALESCE(SUM(i.amount), 0)
+		FROM installments i
+		JOIN installment_plans p ON p.id = i.plan_id
+		JOIN cards cd ON cd.id = p.card_id
+		JOIN accounts ac ON ac.id = cd.account_id
+		LEFT JOIN transactions t ON t.id = p.transaction_id
+		LEFT JOIN transaction_categories c ON c.id = t.category_id
+		WHERE p.user_id = ?
+			AND p.status <> 'cancelled'
+			AND i.status <> 'cancelled'
+			AND ac.currency = ?
+			AND i.due_date >= ? AND i.due_date < ?
+			`+installmentScope, args...,
+	).Scan(&spending.Installments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum installments: %w", err)
+	}
+
+	return spending, nil
+}
+
+// SavePeriods creates or replaces the snapshot of months of budgets
+func (r *BudgetRepository) SavePeriods(periods []*domainbudget.Period) error {
+	if len(periods) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, period := range periods {
+		_, err := tx.Exec(`
+			INSERT INTO budget_periods (
+				budget_id, month, user_id, amount, carried_over, limit_amount, spent, installments,
+				actual, remaining, percent_used, status, computed_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE
+				amount = VALUES(amount), carried_over = VALUES(carried_over), limit_amount = VALUES(limit_amount),
+				spent = VALUES(spent), installments = VALUES(installments), actual = VALUES(actual),
+				remaining = VALUES(remaining), percent_used = VALUES(percent_used), status = VALUES(status),
+				computed_at = VALUES(computed_at)`,
+			period.BudgetID, period.Month, period.UserID, period.Amount, period.CarriedOver, period.Limit,
+			period.Spent, period.Installments, period.Actual, period.Remaining, period.PercentUsed,
+			period.Status, period.ComputedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save budget month: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit budget months: %w", err)
+	}
+	return nil
+}
+
+// ClaimAlert records that an alert threshold of a month was reached. The primary key on budget, month
+// and threshold makes a second claim fail, so it returns false.
+func (r *BudgetRepository) ClaimAlert(period *domainbudget.Period, threshold int) (bool, error) {
+	_, err := r.db.Exec(`
+		INSERT INTO budget_alerts (budget_id, month, threshold, actual, limit_amount)
+		VALUES (?, ?, ?, ?, ?)`,
+		period.BudgetID, period.Month, threshold, period.Actual, period.Limit,
+	)
+	if err != nil {
+		var mysqlErr *gomysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create budget alert: %w", err)
+	}
+	return true, nil
+}
+
+// query runs a query that returns budgets
+func (r *BudgetRepository) query(query string, args ...interface{}) ([]*domainbudget.Budget, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query budgets: %w", err)
+	}
+	defer rows.Close()
+
+	budgets := []*domainbudget.Budget{}
+	for rows.Next() {
+		budget, err := scanBudget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan budget: %w", err)
+		}
+		budgets = append(budgets, budget)
+	}
+
+	return budgets, rows.Err()
+}
+
+// budgetScanner is satisfied by *sql.Row and *sql.Rows
+type budgetScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBudget(scanner budgetScanner) (*domainbudget.Budget, error) {
+	budget := &domainbudget.Budget{}
+	var categoryID, merchantName, cardID, endMonth sql.NullString
+
+	err := scanner.Scan(
+		&budget.ID, &budget.UserID, &budget.Name, &budget.Scope, &categoryID, &merchantName, &cardID,
+		&budget.Amount, &budget.Currency, &budget.Rollover, &budget.StartMonth, &endMonth,
+		&budget.AlertsEnabled, &budget.CreatedAt, &budget.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if categoryID.Valid {
+		budget.CategoryID = &categoryID.String
+	}
+	if merchantName.Valid {
+		budget.MerchantName = &merchantName.String
+	}
+	if cardID.Valid {
+		budget.CardID = &cardID.String
+	}
+	if endMonth.Valid {
+		budget.EndMonth = &endMonth.String
+	}
+	return budget, nil
+}
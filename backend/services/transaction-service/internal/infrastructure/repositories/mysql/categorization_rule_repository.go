@@ -0,0 +1,154 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domaincategory "github.com/fintrack/transaction-service/internal/core/domain/entities/category"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// CategorizationRuleRepository implements the CategorizationRuleRepositoryInterface for MySQL
+type CategorizationRuleRepository struct {
+	db *sql.DB
+}
+
+// NewCategorizationRuleRepository creates a new MySQL categorization rule repository
+func NewCategorizationRuleRepository(db *sql.DB) service.CategorizationRuleRepositoryInterface {
+	return &CategorizationRuleRepository{
+		db: db,
+	}
+}
+
+const categorizationRuleColumns = `id, user_id, category_id, name, merchant_pattern, description_regex,
+	min_amount, max_amount, card_id, priority, is_active, created_at, updated_at`
+
+// Create inserts a new categorization rule
+func (r *CategorizationRuleRepository) Create(rule *domaincategory.CategorizationRule) (*domaincategory.CategorizationRule, error) {
+	if rule.ID == "" {
+		rule.ID = fmt.Sprintf("rule_%d", time.Now().UnixNano())
+	}
+
+	query := `
+		INSERT INTO categorization_rules (
+			id, user_id, category_id, name, merchant_pattern, description_regex,
+			min_amount, max_amount, card_id, priority, is_active, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`
+
+	_, err := r.db.Exec(query,
+		rule.ID, rule.UserID, rule.CategoryID, rule.Name, rule.MerchantPattern, rule.DescriptionRegex,
+		rule.MinAmount, rule.MaxAmount, rule.CardID, rule.Priority, rule.IsActive,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create categorization rule: %w", err)
+	}
+
+	return r.GetByID(rule.ID)
+}
+
+// GetByID retrieves a categorization rule by its ID
+func (r *CategorizationRuleRepository) GetByID(id string) (*domaincategory.CategorizationRule, error) {
+	query := `SELECT ` + categorizationRuleColumns + ` FROM categorization_rules WHERE id = ?`
+
+	rule, err := scanCategorizationRule(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("categorization rule not found with ID: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get categorization rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// Update updates an existing categorization rule
+func (r *CategorizationRuleRepository) Update(rule *domaincategory.CategorizationRule) (*domaincategory.CategorizationRule, error) {
+	query := `
+		UPDATE categorization_rules SET
+			category_id = ?, name = ?, merchant_pattern = ?, description_regex = ?,
+			min_amount = ?, max_amount = ?, card_id = ?, priority = ?, is_active = ?,
+			updated_at = NOW()
+		WHERE id = ?`
+
+	_, err := r.db.Exec(query,
+		rule.CategoryID, rule.Name, rule.MerchantPattern, rule.DescriptionRegex,
+		rule.MinAmount, rule.MaxAmount, rule.CardID, rule.Priority, rule.IsActive, rule.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update categorization rule: %w", err)
+	}
+
+	return r.GetByID(rule.ID)
+}
+
+// Delete removes a categorization rule
+func (r *CategorizationRuleRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM categorization_rules WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete categorization rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("categorization rule not found with ID: %s", id)
+	}
+
+	return nil
+}
+
+// GetByUserID returns the rules of a user by priority, highest first
+func (r *CategorizationRuleRepository) GetByUserID(userID string) ([]*domaincategory.CategorizationRule, error) {
+	query := `SELECT ` + categorizationRuleColumns + ` FROM categorization_rules
+		WHERE user_id = ?
+		ORDER BY priority DESC, created_at ASC`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categorization rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*domaincategory.CategorizationRule
+	for rows.Next() {
+		rule, err := scanCategorizationRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan categorization rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// DeleteByCategory removes the rules of a user that assign the given category
+func (r *CategorizationRuleRepository) DeleteByCategory(userID, categoryID string) error {
+	_, err := r.db.Exec("DELETE FROM categorization_rules WHERE user_id = ? AND category_id = ?", userID, categoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete categorization rules: %w", err)
+	}
+	return nil
+}
+
+func scanCategorizationRule(scanner categoryScanner) (*domaincategory.CategorizationRule, error) {
+	rule := &domaincategory.CategorizationRule{}
+	var merchantPattern, descriptionRegex sql.NullString
+
+	err := scanner.Scan(
+		&rule.ID, &rule.UserID, &rule.CategoryID, &rule.Name, &merchantPattern, &descriptionRegex,
+		&rule.MinAmount, &rule.MaxAmount, &rule.CardID, &rule.Priority, &rule.IsActive,
+		&rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.MerchantPattern = merchantPattern.String
+	rule.DescriptionRegex = descriptionRegex.String
+	// A stored expression that no longer compiles leaves the rule matching nothing instead of failing the load
+	_ = rule.CompileDescriptionRegex()
+	return rule, nil
+}
@@ -0,0 +1,145 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domaincategory "github.com/fintrack/transaction-service/internal/core/domain/entities/category"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// CategoryRepository implements the CategoryRepositoryInterface for MySQL
+type CategoryRepository struct {
+	db *sql.DB
+}
+
+// NewCategoryRepository creates a new MySQL category repository
+func NewCategoryRepository(db *sql.DB) service.CategoryRepositoryInterface {
+	return &CategoryRepository{
+		db: db,
+	}
+}
+
+const categoryColumns = `id, user_id, parent_id, name, kind, icon, is_default, created_at, updated_at`
+
+// Create inserts a new custom category
+func (r *CategoryRepository) Create(category *domaincategory.Category) (*domaincategory.Category, error) {
+	if category.ID == "" {
+		category.ID = fmt.Sprintf("cat_%d", time.Now().UnixNano())
+	}
+
+	query := `
+		INSERT INTO transaction_categories (
+			id, user_id, parent_id, name, kind, icon, is_default, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`
+
+	_, err := r.db.Exec(query,
+		category.ID, category.UserID, category.ParentID, category.Name,
+		category.Kind, category.Icon, category.IsDefault,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+
+	return r.GetByID(category.ID)
+}
+
+// GetByID retrieves a category by its ID
+func (r *CategoryRepository) GetByID(id string) (*domaincategory.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM transaction_categories WHERE id = ?`
+
+	category, err := scanCategory(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("category not found with ID: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return category, nil
+}
+
+// Update updates the name and icon of a category
+func (r *CategoryRepository) Update(category *domaincategory.Category) (*domaincategory.Category, error) {
+	query := `UPDATE transaction_categories SET name = ?, icon = ?, updated_at = NOW() WHERE id = ?`
+
+	if _, err := r.db.Exec(query, category.Name, category.Icon, category.ID); err != nil {
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+
+	return r.GetByID(category.ID)
+}
+
+// Delete removes a category
+func (r *CategoryRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM transaction_categories WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("category not found with ID: %s", id)
+	}
+
+	return nil
+}
+
+// GetForUser returns the default categories plus the custom categories of the user
+func (r *CategoryRepository) GetForUser(userID string) ([]*domaincategory.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM transaction_categories
+		WHERE user_id IS NULL OR user_id = ?
+		ORDER BY name ASC`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*domaincategory.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+// CountChildren counts the subcategories of a category
+func (r *CategoryRepository) CountChildren(id string) (int, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM transaction_categories WHERE parent_id = ?", id).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count subcategories: %w", err)
+	}
+	return count, nil
+}
+
+// categoryScanner is satisfied by *sql.Row and *sql.Rows
+type categoryScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCategory(scanner categoryScanner) (*domaincategory.Category, error) {
+	category := &domaincategory.Category{}
+	var icon sql.NullString
+
+	err := scanner.Scan(
+		&category.ID, &category.UserID, &category.ParentID, &category.Name,
+		&category.Kind, &icon, &category.IsDefault, &category.CreatedAt, &category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	category.Icon = icon.String
+	return category, nil
+}
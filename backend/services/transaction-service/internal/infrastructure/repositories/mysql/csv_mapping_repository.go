@@ -0,0 +1,142 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainstatement "github.com/fintrack/transaction-service/internal/core/domain/entities/statement"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// CSVMappingRepository implements the CSVMappingRepositoryInterface for MySQL
+type CSVMappingRepository struct {
+	db *sql.DB
+}
+
+// NewCSVMappingRepository creates a new MySQL CSV column mapping repository
+func NewCSVMappingRepository(db *sql.DB) service.CSVMappingRepositoryInterface {
+	return &CSVMappingRepository{
+		db: db,
+	}
+}
+
+const csvMappingColumns = `
+	id, user_id, name, delimiter, skip_rows, date_column, date_format, amount_column, debit_column,
+	credit_column, description_column, merchant_column, reference_column, balance_column,
+	currency_column, decimal_separator, invert_sign, currency, created_at, updated_at`
+
+// Create stores a new column mapping
+func (r *CSVMappingRepository) Create(mapping *domainstatement.CSVMapping) (*domainstatement.CSVMapping, error) {
+	mapping.ID = fmt.Sprintf("map_%d", time.Now().UnixNano())
+
+	query := `
+		INSERT INTO import_csv_mappings (
+			id, user_id, name, delimiter, skip_rows, date_column, date_format, amount_column, debit_column,
+			credit_column, description_column, merchant_column, reference_column, balance_column,
+			currency_column, decimal_separator, invert_sign, currency
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query,
+		mapping.ID, mapping.UserID, mapping.Name, mapping.Delimiter, mapping.SkipRows, mapping.DateColumn,
+		mapping.DateFormat, mapping.AmountColumn, mapping.DebitColumn, mapping.CreditColumn,
+		mapping.DescriptionColumn, mapping.MerchantColumn, mapping.ReferenceColumn, mapping.BalanceColumn,
+		mapping.CurrencyColumn, mapping.DecimalSeparator, mapping.InvertSign, mapping.Currency,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create column mapping: %w", err)
+	}
+
+	return r.GetByID(mapping.ID)
+}
+
+// GetByID retrieves a column mapping by ID
+func (r *CSVMappingRepository) GetByID(id string) (*domainstatement.CSVMapping, error) {
+	query := "SELECT" + csvMappingColumns + " FROM import_csv_mappings WHERE id = ?"
+
+	mapping := &domainstatement.CSVMapping{}
+	err := r.db.QueryRow(query, id).Scan(csvMappingFields(mapping)...)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("column mapping not found with ID: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column mapping: %w", err)
+	}
+
+	return mapping, nil
+}
+
+// GetByUserID returns the column mappings of a user by name
+func (r *CSVMappingRepository) GetByUserID(userID string) ([]*domainstatement.CSVMapping, error) {
+	query := "SELECT" + csvMappingColumns + " FROM import_csv_mappings WHERE user_id = ? ORDER BY name ASC"
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query column mappings: %w", err)
+	}
+	defer rows.Close()
+
+	mappings := []*domainstatement.CSVMapping{}
+	for rows.Next() {
+		mapping := &domainstatement.CSVMapping{}
+		if err := rows.Scan(csvMappingFields(mapping)...); err != nil {
+			return nil, fmt.Errorf("failed to scan column mapping: %w", err)
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, rows.Err()
+}
+
+// Update replaces the columns of a mapping
+func (r *CSVMappingRepository) Update(mapping *domainstatement.CSVMapping) (*domainstatement.CSVMapping, error) {
+	query := `
+		UPDATE import_csv_mappings
+		SET name = ?, delimiter = ?, skip_rows = ?, date_column = ?, date_format = ?, amount_column = ?,
+			debit_column = ?, credit_column = ?, description_column = ?, merchant_column = ?,
+			reference_column = ?, balance_column = ?, currency_column = ?, decimal_separator = ?,
+			invert_sign = ?, currency = ?
+		WHERE id = ?`
+
+	_, err := r.db.Exec(query,
+		mapping.Name, mapping.Delimiter, mapping.SkipRows, mapping.DateColumn, mapping.DateFormat,
+		mapping.AmountColumn, mapping.DebitColumn, mapping.CreditColumn, mapping.DescriptionColumn,
+		mapping.MerchantColumn, mapping.ReferenceColumn, mapping.BalanceColumn, mapping.CurrencyColumn,
+		mapping.DecimalSeparator, mapping.InvertSign, mapping.Currency, mapping.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update column mapping: %w", err)
+	}
+
+	// MySQL reports no affected rows when nothing changed, so the lookup also tells if the mapping exists
+	return r.GetByID(mapping.ID)
+}
+
+// Delete removes a column mapping
+func (r *CSVMappingRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM import_csv_mappings WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete column mapping: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("column mapping not found with ID: %s", id)
+	}
+
+	return nil
+}
+
+// csvMappingFields returns the scan destinations in the order of csvMappingColumns
+func csvMappingFields(mapping *domainstatement.CSVMapping) []interface{} {
+	return []interface{}{
+		&mapping.ID, &mapping.UserID, &mapping.Name, &mapping.Delimiter, &mapping.SkipRows, &mapping.DateColumn,
+		&mapping.DateFormat, &mapping.AmountColumn, &mapping.DebitColumn, &mapping.CreditColumn,
+		&mapping.DescriptionColumn, &mapping.MerchantColumn, &mapping.ReferenceColumn, &mapping.BalanceColumn,
+		&mapping.CurrencyColumn, &mapping.DecimalSeparator, &mapping.InvertSign, &mapping.Currency,
+		&mapping.CreatedAt, &mapping.UpdatedAt,
+	}
+}
@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// DuplicateRepository implements the DuplicateRepositoryInterface for MySQL
+type DuplicateRepository struct {
+	db *sql.DB
+}
+
+// NewDuplicateRepository creates a new MySQL duplicate repository
+func NewDuplicateRepository(db *sql.DB) service.DuplicateRepositoryInterface {
+	return &DuplicateRepository{
+		db: db,
+	}
+}
+
+// duplicateReferences are the columns that point to a transaction and follow it when it is merged.
+// Installment plans and installments belong to the account service and have no foreign key, and the
+// audit entries would be deleted with the duplicate by their cascade.
+var duplicateReferences = []struct {
+	table     string
+	column    string
+	condition string
+}{
+	{"installment_plans", "transaction_id", ""},
+	{"installments", "payment_transaction_id", ""},
+	{"transaction_audit", "transaction_id", ""},
+	{"transaction_attachments", "owner_id", "owner_type = 'transaction'"},
+	{"scheduled_transaction_runs", "transaction_id", ""},
+	{"reconciliation_items", "transaction_id", ""},
+}
+
+// Merge saves the kept transaction, moves the references of the duplicates to it, deletes the duplicates
+// and logs the merge, in a single database transaction
+func (r *DuplicateRepository) Merge(keep *domaintransaction.Transaction, duplicateIDs []string, audit *service.TransactionAuditEntry) error {
+	metadataJSON, err := json.Marshal(keep.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	tagsJSON, err := json.Marshal(keep.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	changedFieldsJSON, err := json.Marshal(audit.ChangedFields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal changed fields: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE transactions SET
+			reference_id = ?, external_id = ?, merchant_name = ?, merchant_id = ?, notes = ?,
+			category_id = ?, category_source = ?, metadata = ?, tags = ?, updated_at = NOW()
+		WHERE id = ?`,
+		keep.ReferenceID, keep.ExternalID, keep.MerchantName, keep.MerchantID, keep.Notes,
+		keep.CategoryID, nullableCategorySource(keep.CategorySource), string(metadataJSON), string(tagsJSON), keep.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update kept transaction: %w", err)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(duplicateIDs)), ",")
+	duplicateArgs := make([]interface{}, len(duplicateIDs))
+	for i, id := range duplicateIDs {
+		duplicateArgs[i] = id
+	}
+
+	for _, reference := range duplicateReferences {
+		query := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s IN (%s)", reference.table, reference.column, reference.column, placeholders)
+		if reference.condition != "" {
+			query += " AND " + reference.condition
+		}
+		if _, err := tx.Exec(query, append([]interface{}{keep.ID}, duplicateArgs...)...); err != nil {
+			return fmt.Errorf("failed to move %s references: %w", reference.table, err)
+		}
+	}
+
+	result, err := tx.Exec(
+		fmt.Sprintf("DELETE FROM transactions WHERE user_id = ? AND id IN (%s)", placeholders),
+		append([]interface{}{keep.UserID}, duplicateArgs...)...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete duplicate transactions: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if int(deleted) != len(duplicateIDs) {
+		return fmt.Errorf("failed to delete duplicate transactions: %d of %d were deleted", deleted, len(duplicateIDs))
+	}
+
+	if audit.ID == "" {
+		audit.ID = fmt.Sprintf("audit_%d", time.Now().UnixNano())
+	}
+	_, err = tx.Exec(`
+		INSERT INTO transaction_audit (
+			id, transaction_id, action, old_status, new_status, changed_fields, changed_by, change_reason, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		audit.ID, audit.TransactionID, audit.Action, audit.OldStatus, audit.NewStatus,
+		string(changedFieldsJSON), audit.ChangedBy, audit.ChangeReason, audit.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit merge: %w", err)
+	}
+
+	return nil
+}
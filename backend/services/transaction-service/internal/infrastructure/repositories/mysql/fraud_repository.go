@@ -0,0 +1,173 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	domainfraud "github.com/fintrack/transaction-service/internal/core/domain/entities/fraud"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// FraudRepository implements the FraudRepositoryInterface for MySQL
+type FraudRepository struct {
+	db *sql.DB
+}
+
+// NewFraudRepository creates a new MySQL fraud repository
+func NewFraudRepository(db *sql.DB) service.FraudRepositoryInterface {
+	return &FraudRepository{
+		db: db,
+	}
+}
+
+const riskScoreColumns = `
+	id, transaction_id, user_id, score, action, reasons, created_at`
+
+// GetSettings returns the risk scoring settings of a user, nil when the user did not configure them
+func (r *FraudRepository) GetSettings(userID string) (*domainfraud.Settings, error) {
+	query := `
+		SELECT user_id, enabled, alert_threshold, hold_threshold, block_threshold, amount_deviation,
+			velocity_count, velocity_window_minutes, quiet_hours_start, quiet_hours_end, created_at, updated_at
+		FROM fraud_settings
+		WHERE user_id = ?`
+
+	settings := &domainfraud.Settings{}
+	err := r.db.QueryRow(query, userID).Scan(
+		&settings.UserID, &settings.Enabled, &settings.AlertThreshold, &settings.HoldThreshold,
+		&settings.BlockThreshold, &settings.AmountDeviation, &settings.VelocityCount,
+		&settings.VelocityWindowMinutes, &settings.QuietHoursStart, &settings.QuietHoursEnd,
+		&settings.CreatedAt, &settings.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fraud settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SaveSettings creates or replaces the risk scoring settings of a user
+func (r *FraudRepository) SaveSettings(settings *domainfraud.Settings) error {
+	query := `
+		INSERT INTO fraud_settings (
+			user_id, enabled, alert_threshold, hold_threshold, block_threshold, amount_deviation,
+			velocity_count, velocity_window_minutes, quiet_hours_start, quiet_hours_end, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
+		ON DUPLICATE KEY UPDATE
+			enabled = VALUES(enabled), alert_threshold = VALUES(alert_threshold),
+			hold_threshold = VALUES(hold_threshold), block_threshold = VALUES(block_threshold),
+			amount_deviation = VALUES(amount_deviation), velocity_count = VALUES(velocity_count),
+			velocity_window_minutes = VALUES(velocity_window_minutes), quiet_hours_start = VALUES(quiet_hours_start),
+			quiet_hours_end = VALUES(quiet_hours_end), updated_at = NOW()`
+
+	_, err := r.db.Exec(query,
+		settings.UserID, settings.Enabled, settings.AlertThreshold, settings.HoldThreshold,
+		settings.BlockThreshold, settings.AmountDeviation, settings.VelocityCount,
+		settings.VelocityWindowMinutes, settings.QuietHoursStart, settings.QuietHoursEnd,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save fraud settings: %w", err)
+	}
+
+	return nil
+}
+
+// CreateScore persists the risk score of a transaction with its reasons
+func (r *FraudRepository) CreateScore(score *domainfraud.Score) error {
+	reasons, err := json.Marshal(score.Reasons)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reasons: %w", err)
+	}
+
+	query := `
+		INSERT INTO transaction_risk_scores (` + riskScoreColumns + `
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.Exec(query,
+		score.ID, score.TransactionID, score.UserID, score.Score, score.Action, string(reasons), score.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create risk score: %w", err)
+	}
+
+	return nil
+}
+
+// GetScoreByTransactionID returns the latest risk score of a transaction
+func (r *FraudRepository) GetScoreByTransactionID(transactionID string) (*domainfraud.Score, error) {
+	query := "SELECT" + riskScoreColumns + `
+		FROM transaction_risk_scores
+		WHERE transaction_id = ?
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	score, err := scanRiskScore(r.db.QueryRow(query, transactionID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("risk score not found for transaction: %s", transactionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk score: %w", err)
+	}
+
+	return score, nil
+}
+
+// GetScoresByUserID returns the risk scores of the transactions of a user, latest first
+func (r *FraudRepository) GetScoresByUserID(userID string, minScore int, action domainfraud.Action, limit int) ([]*domainfraud.Score, error) {
+	query := "SELECT" + riskScoreColumns + `
+		FROM transaction_risk_scores
+		WHERE user_id = ? AND score >= ?`
+	args := []interface{}{userID, minScore}
+	if action != "" {
+		query += " AND action = ?"
+		args = append(args, action)
+	}
+	query += " ORDER BY created_at DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query risk scores: %w", err)
+	}
+	defer rows.Close()
+
+	scores := []*domainfraud.Score{}
+	for rows.Next() {
+		score, err := scanRiskScore(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan risk score: %w", err)
+		}
+		scores = append(scores, score)
+	}
+
+	return scores, rows.Err()
+}
+
+// riskScoreScanner is implemented by *sql.Row and *sql.Rows
+type riskScoreScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRiskScore(scanner riskScoreScanner) (*domainfraud.Score, error) {
+	score := &domainfraud.Score{}
+	var reasons sql.NullString
+
+	err := scanner.Scan(
+		&score.ID, &score.TransactionID, &score.UserID, &score.Score, &score.Action, &reasons, &score.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	score.Reasons = []domainfraud.Reason{}
+	if reasons.Valid && reasons.String != "" {
+		if err := json.Unmarshal([]byte(reasons.String), &score.Reasons); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal reasons: %w", err)
+		}
+	}
+
+	return score, nil
+}
@@ -0,0 +1,370 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	domaingroup "github.com/fintrack/transaction-service/internal/core/domain/entities/group"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// GroupRepository implements the GroupRepositoryInterface for MySQL
+type GroupRepository struct {
+	db *sql.DB
+}
+
+// NewGroupRepository creates a new MySQL shared expense group repository
+func NewGroupRepository(db *sql.DB) service.GroupRepositoryInterface {
+	return &GroupRepository{
+		db: db,
+	}
+}
+
+const expenseGroupColumns = `
+	g.id, g.name, g.currency, g.created_by, g.created_at, g.updated_at`
+
+const groupExpenseColumns = `
+	id, group_id, paid_by, amount, description, split_method, transaction_id, expense_date, created_by, created_at`
+
+// CreateGroup persists a group with its members
+func (r *GroupRepository) CreateGroup(group *domaingroup.Group) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO expense_groups (id, name, currency, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		group.ID, group.Name, group.Currency, group.CreatedBy, group.CreatedAt, group.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+
+	for _, member := range group.Members {
+		_, err = tx.Exec(`
+			INSERT INTO expense_group_members (group_id, user_id, role, joined_at)
+			VALUES (?, ?, ?, ?)`,
+			group.ID, member.UserID, member.Role, member.JoinedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to add group member: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit group: %w", err)
+	}
+	return nil
+}
+
+// GetGroup returns a group with its members and their names
+func (r *GroupRepository) GetGroup(id string) (*domaingroup.Group, error) {
+	group := &domaingroup.Group{}
+	err := r.db.QueryRow("SELECT"+expenseGroupColumns+`
+		FROM expense_groups g
+		WHERE g.id = ?`, id,
+	).Scan(&group.ID, &group.Name, &group.Currency, &group.CreatedBy, &group.CreatedAt, &group.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("group not found with ID: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT m.group_id, m.user_id, CONCAT(u.first_name, ' ', u.last_name), u.email, m.role, m.joined_at
+		FROM expense_group_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.group_id = ?
+		ORDER BY m.joined_at ASC, m.user_id ASC`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query group members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		member := &domaingroup.Member{}
+		if err := rows.Scan(&member.GroupID, &member.UserID, &member.Name, &member.Email, &member.Role, &member.JoinedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group member: %w", err)
+		}
+		group.Members = append(group.Members, member)
+	}
+
+	return group, rows.Err()
+}
+
+// GetGroupsByUserID returns the groups a user is a member of, latest first, without their members
+func (r *GroupRepository) GetGroupsByUserID(userID string) ([]*domaingroup.Group, error) {
+	rows, err := r.db.Query("SELECT"+expenseGroupColumns+`
+		FROM expense_groups g
+		JOIN expense_group_members m ON m.group_id = g.id
+		WHERE m.user_id = ?
+		ORDER BY g.created_at DESC, g.id DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []*domaingroup.Group{}
+	for rows.Next() {
+		group := &domaingroup.Group{}
+		if err := rows.Scan(&group.ID, &group.Name, &group.Currency, &group.CreatedBy, &group.CreatedAt, &group.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, rows.Err()
+}
+
+// AddMember adds a user to a group
+func (r *GroupRepository) AddMember(member *domaingroup.Member) error {
+	_, err := r.db.Exec(`
+		INSERT INTO expense_group_members (group_id, user_id, role, joined_at)
+		VALUES (?, ?, ?, ?)`,
+		member.GroupID, member.UserID, member.Role, member.JoinedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add group member: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember removes a user from a group; the expenses and settlements of the user are kept
+func (r *GroupRepository) RemoveMember(groupID string, userID string) error {
+	result, err := r.db.Exec(`
+		DELETE FROM expense_group_members
+		WHERE group_id = ? AND user_id = ?`, groupID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove group member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("member not found with ID: %s", userID)
+	}
+	return nil
+}
+
+// CreateExpense persists an expense with its shares
+func (r *GroupRepository) CreateExpense(expense *domaingroup.Expense) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO group_expenses (`+groupExpenseColumns+`
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		expense.ID, expense.GroupID, expense.PaidBy, expense.Amount, expense.Description, expense.SplitMethod,
+		expense.TransactionID, expense.ExpenseDate, expense.CreatedBy, expense.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create expense: %w", err)
+	}
+
+	for position, share := range expense.Shares {
+		_, err = tx.Exec(`
+			INSERT INTO group_expense_shares (expense_id, user_id, position, amount, percentage)
+			VALUES (?, ?, ?, ?, ?)`,
+			expense.ID, share.UserID, position, share.Amount, share.Percentage,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create expense share: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit expense: %w", err)
+	}
+	return nil
+}
+
+// GetExpense returns an expense with its shares
+func (r *GroupRepository) GetExpense(id string) (*domaingroup.Expense, error) {
+	expense, err := scanGroupExpense(r.db.QueryRow("SELECT"+groupExpenseColumns+`
+		FROM group_expenses
+		WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("expense not found with ID: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expense: %w", err)
+	}
+
+	if err := r.loadShares([]*domaingroup.Expense{expense}); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+// GetExpenses returns the expenses of a group with their shares, latest first; 0 returns them all
+func (r *GroupRepository) GetExpenses(groupID string, limit int) ([]*domaingroup.Expense, error) {
+	query := "SELECT" + groupExpenseColumns + `
+		FROM group_expenses
+		WHERE group_id = ?
+		ORDER BY expense_date DESC, created_at DESC, id DESC`
+	args := []interface{}{groupID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expenses: %w", err)
+	}
+	defer rows.Close()
+
+	expenses := []*domaingroup.Expense{}
+	for rows.Next() {
+		expense, err := scanGroupExpense(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expense: %w", err)
+		}
+		expenses = append(expenses, expense)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadShares(expenses); err != nil {
+		return nil, err
+	}
+	return expenses, nil
+}
+
+// DeleteExpense deletes an expense; its shares go with it
+func (r *GroupRepository) DeleteExpense(id string) error {
+	result, err := r.db.Exec("DELETE FROM group_expenses WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete expense: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("expense not found with ID: %s", id)
+	}
+	return nil
+}
+
+// CreateSettlement persists a settlement
+func (r *GroupRepository) CreateSettlement(settlement *domaingroup.Settlement) error {
+	_, err := r.db.Exec(`
+		INSERT INTO group_settlements (id, group_id, from_user_id, to_user_id, amount, transfer_id, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		settlement.ID, settlement.GroupID, settlement.FromUserID, settlement.ToUserID, settlement.Amount,
+		settlement.TransferID, settlement.CreatedBy, settlement.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement: %w", err)
+	}
+	return nil
+}
+
+// GetSettlements returns the settlements of a group with the status of their transfers, latest first
+func (r *GroupRepository) GetSettlements(groupID string) ([]*domaingroup.Settlement, error) {
+	rows, err := r.db.Query(`
+		SELECT s.id, s.group_id, s.from_user_id, s.to_user_id, s.amount, s.transfer_id,
+			COALESCE(t.status, ''), s.created_by, s.created_at
+		FROM group_settlements s
+		LEFT JOIN p2p_transfers t ON t.id = s.transfer_id
+		WHERE s.group_id = ?
+		ORDER BY s.created_at DESC, s.id DESC`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query settlements: %w", err)
+	}
+	defer rows.Close()
+
+	settlements := []*domaingroup.Settlement{}
+	for rows.Next() {
+		settlement := &domaingroup.Settlement{}
+		err := rows.Scan(
+			&settlement.ID, &settlement.GroupID, &settlement.FromUserID, &settlement.ToUserID, &settlement.Amount,
+			&settlement.TransferID, &settlement.TransferStatus, &settlement.CreatedBy, &settlement.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan settlement: %w", err)
+		}
+		settlements = append(settlements, settlement)
+	}
+
+	return settlements, rows.Err()
+}
+
+// loadShares fills the shares of the expenses in one query
+func (r *GroupRepository) loadShares(expenses []*domaingroup.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*domaingroup.Expense, len(expenses))
+	placeholders := make([]string, len(expenses))
+	args := make([]interface{}, len(expenses))
+	for i, expense := range expenses {
+		expense.Shares = []*domaingroup.Share{}
+		byID[expense.ID] = expense
+		placeholders[i] = "?"
+		args[i] = expense.ID
+	}
+
+	rows, err := r.db.Query(`
+		SELECT expense_id, user_id, amount, percentage
+		FROM group_expense_shares
+		WHERE expense_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY expense_id, position`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query expense shares: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		share := &domaingroup.Share{}
+		var percentage sql.NullFloat64
+		if err := rows.Scan(&share.ExpenseID, &share.UserID, &share.Amount, &percentage); err != nil {
+			return fmt.Errorf("failed to scan expense share: %w", err)
+		}
+		if percentage.Valid {
+			share.Percentage = &percentage.Float64
+		}
+		if expense, exists := byID[share.ExpenseID]; exists {
+			expense.Shares = append(expense.Shares, share)
+		}
+	}
+
+	return rows.Err()
+}
+
+// groupExpenseScanner is implemented by *sql.Row and *sql.Rows
+type groupExpenseScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanGroupExpense(scanner groupExpenseScanner) (*domaingroup.Expense, error) {
+	expense := &domaingroup.Expense{}
+	var transactionID sql.NullString
+
+	err := scanner.Scan(
+		&expense.ID, &expense.GroupID, &expense.PaidBy, &expense.Amount, &expense.Description, &expense.SplitMethod,
+		&transactionID, &expense.ExpenseDate, &expense.CreatedBy, &expense.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if transactionID.Valid {
+		expense.TransactionID = &transactionID.String
+	}
+	return expense, nil
+}
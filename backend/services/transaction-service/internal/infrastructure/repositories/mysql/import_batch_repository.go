@@ -0,0 +1,277 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainstatement "github.com/fintrack/transaction-service/internal/core/domain/entities/statement"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// ImportBatchRepository implements the ImportBatchRepositoryInterface for MySQL
+type ImportBatchRepository struct {
+	db *sql.DB
+}
+
+// NewImportBatchRepository creates a new MySQL import batch repository
+func NewImportBatchRepository(db *sql.DB) service.ImportBatchRepositoryInterface {
+	return &ImportBatchRepository{
+		db: db,
+	}
+}
+
+const importBatchColumns = `
+	id, user_id, format, file_name, account_id, card_id, currency, status, total_rows,
+	imported_count, duplicate_count, invalid_count, from_date, to_date, created_at, rolled_back_at`
+
+// Create stores a new import batch
+func (r *ImportBatchRepository) Create(batch *domainstatement.ImportBatch) (*domainstatement.ImportBatch, error) {
+	batch.ID = fmt.Sprintf("imp_%d", time.Now().UnixNano())
+
+	query := `
+		INSERT INTO import_batches (
+			id, user_id, format, file_name, account_id, card_id, currency, status, total_rows,
+			imported_count, duplicate_count, invalid_count, from_date, to_date
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query,
+		batch.ID, batch.UserID, batch.Format, batch.FileName, batch.AccountID, batch.CardID, batch.Currency,
+		batch.Status, batch.TotalRows, batch.ImportedCount, batch.DuplicateCount, batch.InvalidCount,
+		batch.FromDate, batch.ToDate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create import batch: %w", err)
+	}
+
+	return r.GetByID(batch.ID)
+}
+
+// Complete saves the rows of an import together with its counters and final status
+func (r *ImportBatchRepository) Complete(batch *domainstatement.ImportBatch, rows []*domainstatement.ImportRow) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO import_batch_rows (
+			id, batch_id, line_number, status, posted_at, amount, currency, description, merchant,
+			external_id, balance, transaction_id, duplicate_of_id, error_message
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for i, row := range rows {
+		row.ID = fmt.Sprintf("improw_%d_%d", time.Now().UnixNano(), i)
+		row.BatchID = batch.ID
+
+		var postedAt *time.Time
+		if !row.PostedAt.IsZero() {
+			postedAt = &row.PostedAt
+		}
+
+		_, err := tx.Exec(query,
+			row.ID, row.BatchID, row.RowNumber, row.Status, postedAt, row.Amount, row.Currency,
+			row.Description, row.Merchant, row.ExternalID, row.Balance, row.TransactionID,
+			row.DuplicateOfID, row.Error,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create import row: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE import_batches
+		SET status = ?, imported_count = ?, duplicate_count = ?, invalid_count = ?
+		WHERE id = ?`,
+		batch.Status, batch.ImportedCount, batch.DuplicateCount, batch.InvalidCount, batch.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update import batch: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit import batch: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatus changes the status of an import batch
+func (r *ImportBatchRepository) UpdateStatus(batchID string, status domainstatement.BatchStatus) error {
+	result, err := r.db.Exec("UPDATE import_batches SET status = ? WHERE id = ?", status, batchID)
+	if err != nil {
+		return fmt.Errorf("failed to update import batch status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("import batch not found with ID: %s", batchID)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an import batch by ID
+func (r *ImportBatchRepository) GetByID(id string) (*domainstatement.ImportBatch, error) {
+	query := "SELECT" + importBatchColumns + " FROM import_batches WHERE id = ?"
+
+	batch, err := scanImportBatch(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("import batch not found with ID: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import batch: %w", err)
+	}
+
+	return batch, nil
+}
+
+// GetByUserID returns the latest import batches of a user
+func (r *ImportBatchRepository) GetByUserID(userID string, limit int) ([]*domainstatement.ImportBatch, error) {
+	query := "SELECT" + importBatchColumns + `
+		FROM import_batches
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+		LIMIT ?`
+
+	rows, err := r.db.Query(query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query import batches: %w", err)
+	}
+	defer rows.Close()
+
+	batches := []*domainstatement.ImportBatch{}
+	for rows.Next() {
+		batch, err := scanImportBatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import batch: %w", err)
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches, rows.Err()
+}
+
+// GetRows returns the rows of an import batch in file order
+func (r *ImportBatchRepository) GetRows(batchID string) ([]*domainstatement.ImportRow, error) {
+	query := `
+		SELECT id, batch_id, line_number, status, posted_at, amount, currency, description, merchant,
+			external_id, balance, transaction_id, duplicate_of_id, error_message
+		FROM import_batch_rows
+		WHERE batch_id = ?
+		ORDER BY line_number ASC`
+
+	rows, err := r.db.Query(query, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query import rows: %w", err)
+	}
+	defer rows.Close()
+
+	importRows := []*domainstatement.ImportRow{}
+	for rows.Next() {
+		row := &domainstatement.ImportRow{}
+		var postedAt sql.NullTime
+		var currency, description, merchant, externalID, errorMessage sql.NullString
+		var balance sql.NullFloat64
+
+		err := rows.Scan(
+			&row.ID, &row.BatchID, &row.RowNumber, &row.Status, &postedAt, &row.Amount, &currency,
+			&description, &merchant, &externalID, &balance, &row.TransactionID, &row.DuplicateOfID,
+			&errorMessage,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import row: %w", err)
+		}
+
+		if postedAt.Valid {
+			row.PostedAt = postedAt.Time
+		}
+		if balance.Valid {
+			row.Balance = &balance.Float64
+		}
+		row.Currency = currency.String
+		row.Description = description.String
+		row.Merchant = merchant.String
+		row.ExternalID = externalID.String
+		row.Error = errorMessage.String
+
+		importRows = append(importRows, row)
+	}
+
+	return importRows, rows.Err()
+}
+
+// Rollback deletes the transactions created by an import and marks it as rolled back
+func (r *ImportBatchRepository) Rollback(batch *domainstatement.ImportBatch) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		DELETE FROM transactions
+		WHERE user_id = ?
+			AND JSON_UNQUOTE(JSON_EXTRACT(metadata, '$.importBatchId')) = ?`,
+		batch.UserID, batch.ID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete imported transactions: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE import_batch_rows
+		SET status = ?
+		WHERE batch_id = ? AND status = ?`,
+		domainstatement.RowStatusRolledBack, batch.ID, domainstatement.RowStatusImported,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update import rows: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE import_batches
+		SET status = ?, rolled_back_at = NOW()
+		WHERE id = ?`,
+		domainstatement.BatchStatusRolledBack, batch.ID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update import batch: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit rollback: %w", err)
+	}
+
+	return int(deleted), nil
+}
+
+// importBatchScanner is implemented by *sql.Row and *sql.Rows
+type importBatchScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanImportBatch(scanner importBatchScanner) (*domainstatement.ImportBatch, error) {
+	batch := &domainstatement.ImportBatch{}
+	var fileName sql.NullString
+
+	err := scanner.Scan(
+		&batch.ID, &batch.UserID, &batch.Format, &fileName, &batch.AccountID, &batch.CardID,
+		&batch.Currency, &batch.Status, &batch.TotalRows, &batch.ImportedCount, &batch.DuplicateCount,
+		&batch.InvalidCount, &batch.FromDate, &batch.ToDate, &batch.CreatedAt, &batch.RolledBackAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	batch.FileName = fileName.String
+	return batch, nil
+}
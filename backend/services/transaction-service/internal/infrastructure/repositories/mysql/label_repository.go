@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// userAccountsCondition selects the own accounts of a user and the ones shared with them
+const userAccountsCondition = `(a.user_id = ? OR a.id IN (
+	SELECT account_id FROM account_members WHERE user_id = ? AND status = 'active'))`
+
+// LabelRepository implements the LabelRepositoryInterface for MySQL
+type LabelRepository struct {
+	db *sql.DB
+}
+
+// NewLabelRepository creates a new MySQL label repository
+func NewLabelRepository(db *sql.DB) service.LabelRepositoryInterface {
+	return &LabelRepository{
+		db: db,
+	}
+}
+
+// GetAccountLabels returns the own and shared accounts of a user, including the deleted ones, by ID
+func (r *LabelRepository) GetAccountLabels(userID string) (map[string]*service.AccountLabel, error) {
+	rows, err := r.db.Query(`
+		SELECT a.id, a.name, a.currency
+		FROM accounts a
+		WHERE `+userAccountsCondition, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	labels := make(map[string]*service.AccountLabel)
+	for rows.Next() {
+		label := &service.AccountLabel{}
+		if err := rows.Scan(&label.ID, &label.Name, &label.Currency); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		labels[label.ID] = label
+	}
+
+	return labels, nil
+}
+
+// GetCardLabels returns the cards of the own and shared accounts of a user, including the deleted ones, by ID
+func (r *LabelRepository) GetCardLabels(userID string) (map[string]*service.CardLabel, error) {
+	rows, err := r.db.Query(`
+		SELECT c.id, c.nickname, c.card_brand, c.last_four_digits, a.currency
+		FROM cards c
+		JOIN accounts a ON a.id = c.account_id
+		WHERE `+userAccountsCondition, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cards: %w", err)
+	}
+	defer rows.Close()
+
+	labels := make(map[string]*service.CardLabel)
+	for rows.Next() {
+		label := &service.CardLabel{}
+		var nickname sql.NullString
+		var brand, lastFour string
+		if err := rows.Scan(&label.ID, &nickname, &brand, &lastFour, &label.Currency); err != nil {
+			return nil, fmt.Errorf("failed to scan card: %w", err)
+		}
+
+		label.Name = strings.TrimSpace(nickname.String)
+		if label.Name == "" {
+			label.Name = fmt.Sprintf("%s •••• %s", strings.ToUpper(brand), lastFour)
+		}
+		labels[label.ID] = label
+	}
+
+	return labels, nil
+}
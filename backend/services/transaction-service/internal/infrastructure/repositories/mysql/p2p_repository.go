@@ -0,0 +1,488 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	domainp2p "github.com/fintrack/transaction-service/internal/core/domain/entities/p2p"
+	"github.com/fintrack/transaction-service/internal/core/service"
+	gomysql "github.com/go-sql-driver/mysql"
+)
+
+// P2PRepository implements the P2PRepositoryInterface for MySQL
+type P2PRepository struct {
+	db *sql.DB
+}
+
+// NewP2PRepository creates a new MySQL peer-to-peer transfer repository
+func NewP2PRepository(db *sql.DB) service.P2PRepositoryInterface {
+	return &P2PRepository{
+		db: db,
+	}
+}
+
+const p2pProfileColumns = `
+	user_id, alias, default_account_id, auto_accept, daily_amount_limit, daily_count_limit, created_at, updated_at`
+
+// p2pTransferColumns are the columns of a transfer with the name and alias of both parties
+const p2pTransferColumns = `
+	t.id, t.kind, t.sender_user_id, t.recipient_user_id, t.sender_account_id, t.recipient_account_id,
+	t.amount, t.currency, t.description, t.status, t.reason,
+	t.sender_transaction_id, t.recipient_transaction_id, t.refund_transaction_id,
+	t.debited_at, t.expires_at, t.responded_at, t.completed_at, t.created_at, t.updated_at,
+	CONCAT(su.first_name, ' ', su.last_name), COALESCE(sp.alias, ''),
+	CONCAT(ru.first_name, ' ', ru.last_name), COALESCE(rp.alias, '')`
+
+const p2pTransferTables = `
+	FROM p2p_transfers t
+	JOIN users su ON su.id = t.sender_user_id
+	JOIN users ru ON ru.id = t.recipient_user_id
+	LEFT JOIN p2p_profiles sp ON sp.user_id = t.sender_user_id
+	LEFT JOIN p2p_profiles rp ON rp.user_id = t.recipient_user_id`
+
+const p2pPartyQuery = `
+	SELECT u.id, CONCAT(u.first_name, ' ', u.last_name), u.email, COALESCE(p.alias, '')
+	FROM users u
+	LEFT JOIN p2p_profiles p ON p.user_id = u.id`
+
+// GetProfile returns the profile of a user, nil when the user did not create one
+func (r *P2PRepository) GetProfile(userID string) (*domainp2p.Profile, error) {
+	query := "SELECT" + p2pProfileColumns + `
+		FROM p2p_profiles
+		WHERE user_id = ?`
+
+	profile, err := scanP2PProfile(r.db.QueryRow(query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get p2p profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// GetProfileByAlias returns the profile with an alias
+func (r *P2PRepository) GetProfileByAlias(alias string) (*domainp2p.Profile, error) {
+	query := "SELECT" + p2pProfileColumns + `
+		FROM p2p_profiles
+		WHERE alias = ?`
+
+	profile, err := scanP2PProfile(r.db.QueryRow(query, alias))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found with alias: %s", alias)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get p2p profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// SaveProfile creates or replaces the profile of a user
+func (r *P2PRepository) SaveProfile(profile *domainp2p.Profile) error {
+	query := `
+		INSERT INTO p2p_profiles (
+			user_id, alias, default_account_id, auto_accept, daily_amount_limit, daily_count_limit, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())
+		ON DUPLICATE KEY UPDATE
+			alias = VALUES(alias), default_account_id = VALUES(default_account_id),
+			auto_accept = VALUES(auto_accept), daily_amount_limit = VALUES(daily_amount_limit),
+			daily_count_limit = VALUES(daily_count_limit), updated_at = NOW()`
+
+	var alias interface{}
+	if profile.Alias != "" {
+		alias = profile.Alias
+	}
+
+	_, err := r.db.Exec(query,
+		profile.UserID, alias, profile.DefaultAccountID, profile.AutoAccept,
+		profile.DailyAmountLimit, profile.DailyCountLimit,
+	)
+	if err != nil {
+		var mysqlErr *gomysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return service.ErrAliasTaken
+		}
+		return fmt.Errorf("failed to save p2p profile: %w", err)
+	}
+
+	return nil
+}
+
+// GetParty returns an active user as shown to the other side of a transfer
+func (r *P2PRepository) GetParty(userID string) (*domainp2p.Party, error) {
+	party, err := scanP2PParty(r.db.QueryRow(p2pPartyQuery+`
+		WHERE u.id = ? AND u.is_active = 1`, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found with ID: %s", userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return party, nil
+}
+
+// GetPartyByEmail returns the active user with an email
+func (r *P2PRepository) GetPartyByEmail(email string) (*domainp2p.Party, error) {
+	party, err := scanP2PParty(r.db.QueryRow(p2pPartyQuery+`
+		WHERE LOWER(u.email) = ? AND u.is_active = 1`, email))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found with email: %s", email)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return party, nil
+}
+
+// GetAccounts returns the active own accounts of a user, oldest first
+func (r *P2PRepository) GetAccounts(userID string) ([]*domainp2p.Account, error) {
+	rows, err := r.db.Query(`
+		SELECT id, user_id, name, account_type, currency
+		FROM accounts
+		WHERE user_id = ? AND is_active = TRUE AND deleted_at IS NULL
+		ORDER BY created_at ASC, id ASC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []*domainp2p.Account{}
+	for rows.Next() {
+		account := &domainp2p.Account{}
+		if err := rows.Scan(&account.ID, &account.UserID, &account.Name, &account.AccountType, &account.Currency); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// Create persists a new transfer
+func (r *P2PRepository) Create(transfer *domainp2p.Transfer) error {
+	return insertP2PTransfer(r.db, transfer)
+}
+
+// CreateWithinLimits creates a transfer only if it fits in the daily limits of its sender
+func (r *P2PRepository) CreateWithinLimits(transfer *domainp2p.Transfer, limits domainp2p.Limits, since time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkP2PLimits(tx, transfer, limits, since); err != nil {
+		return err
+	}
+	if err := insertP2PTransfer(tx, transfer); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CheckWithinLimits checks that a transfer already recorded still fits in the daily limits of its sender
+func (r *P2PRepository) CheckWithinLimits(transfer *domainp2p.Transfer, limits domainp2p.Limits, since time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkP2PLimits(tx, transfer, limits, since); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkP2PLimits locks the sender of a transfer until the transaction ends, so the checks of their transfers
+// run one at a time and each one sees the transfers reserved before it, and checks that the transfer fits
+func checkP2PLimits(tx *sql.Tx, transfer *domainp2p.Transfer, limits domainp2p.Limits, since time.Time) error {
+	var senderID string
+	if err := tx.QueryRow(`SELECT id FROM users WHERE id = ? FOR UPDATE`, transfer.SenderUserID).Scan(&senderID); err != nil {
+		return fmt.Errorf("failed to lock the sender of the transfer: %w", err)
+	}
+
+	usage := &domainp2p.Usage{}
+	err := tx.QueryRow(p2pDailyUsageQuery+` AND id <> ?`,
+		transfer.SenderUserID, transfer.Currency, since, transfer.ID,
+	).Scan(&usage.Amount, &usage.Count)
+	if err != nil {
+		return fmt.Errorf("failed to get daily transfer usage: %w", err)
+	}
+
+	return domainp2p.CheckLimits(limits, usage, transfer.Amount)
+}
+
+// p2pExecer is implemented by *sql.DB and *sql.Tx
+type p2pExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertP2PTransfer inserts a new transfer
+func insertP2PTransfer(db p2pExecer, transfer *domainp2p.Transfer) error {
+	query := `
+		INSERT INTO p2p_transfers (
+			id, kind, sender_user_id, recipient_user_id, sender_account_id, recipient_account_id,
+			amount, currency, description, status, reason,
+			sender_transaction_id, recipient_transaction_id, refund_transaction_id,
+			debited_at, expires_at, responded_at, completed_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := db.Exec(query,
+		transfer.ID, transfer.Kind, transfer.SenderUserID, transfer.RecipientUserID,
+		transfer.SenderAccountID, transfer.RecipientAccountID,
+		transfer.Amount, transfer.Currency, transfer.Description, transfer.Status, transfer.Reason,
+		transfer.SenderTransactionID, transfer.RecipientTransactionID, transfer.RefundTransactionID,
+		transfer.DebitedAt, transfer.ExpiresAt, transfer.RespondedAt, transfer.CompletedAt,
+		transfer.CreatedAt, transfer.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns a transfer with the name and alias of both parties
+func (r *P2PRepository) GetByID(id string) (*domainp2p.Transfer, error) {
+	query := "SELECT" + p2pTransferColumns + p2pTransferTables + `
+		WHERE t.id = ?`
+
+	transfer, err := scanP2PTransfer(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("transfer not found with ID: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+// Update saves the accounts, transactions, status and lifecycle of a transfer
+func (r *P2PRepository) Update(transfer *domainp2p.Transfer) error {
+	query := `
+		UPDATE p2p_transfers SET
+			sender_account_id = ?, recipient_account_id = ?, status = ?, reason = ?,
+			sender_transaction_id = ?, recipient_transaction_id = ?, refund_transaction_id = ?,
+			debited_at = ?, responded_at = ?, completed_at = ?, updated_at = ?
+		WHERE id = ?`
+
+	result, err := r.db.Exec(query,
+		transfer.SenderAccountID, transfer.RecipientAccountID, transfer.Status, transfer.Reason,
+		transfer.SenderTransactionID, transfer.RecipientTransactionID, transfer.RefundTransactionID,
+		transfer.DebitedAt, transfer.RespondedAt, transfer.CompletedAt, transfer.UpdatedAt,
+		transfer.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("transfer not found with ID: %s", transfer.ID)
+	}
+
+	return nil
+}
+
+// Claim moves a transfer from one status to another only if it still has the first one
+func (r *P2PRepository) Claim(id string, from, to domainp2p.TransferStatus) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE p2p_transfers SET status = ?, updated_at = NOW()
+		WHERE id = ? AND status = ?`, to, id, from)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim transfer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetByUserID returns the transfers a user sent or received, latest first
+func (r *P2PRepository) GetByUserID(userID string, filters service.P2PTransferFilters) ([]*domainp2p.Transfer, error) {
+	query := "SELECT" + p2pTransferColumns + p2pTransferTables
+	var args []interface{}
+
+	switch filters.Direction {
+	case service.P2PDirectionSent:
+		query += " WHERE t.sender_user_id = ?"
+		args = append(args, userID)
+	case service.P2PDirectionReceived:
+		query += " WHERE t.recipient_user_id = ?"
+		args = append(args, userID)
+	default:
+		query += " WHERE (t.sender_user_id = ? OR t.recipient_user_id = ?)"
+		args = append(args, userID, userID)
+	}
+	if filters.Status != "" {
+		query += " AND t.status = ?"
+		args = append(args, filters.Status)
+	}
+	if filters.Awaiting {
+		query += ` AND t.status = 'pending'
+			AND ((t.kind = 'send' AND t.recipient_user_id = ?) OR (t.kind = 'request' AND t.sender_user_id = ?))`
+		args = append(args, userID, userID)
+	}
+	query += " ORDER BY t.created_at DESC, t.id DESC LIMIT ?"
+	args = append(args, filters.Limit)
+
+	return r.queryTransfers(query, args...)
+}
+
+// GetExpired returns the pending transfers past their expiry, oldest first
+func (r *P2PRepository) GetExpired(now time.Time, limit int) ([]*domainp2p.Transfer, error) {
+	query := "SELECT" + p2pTransferColumns + p2pTransferTables + `
+		WHERE t.status = 'pending' AND t.expires_at <= ?
+		ORDER BY t.expires_at ASC
+		LIMIT ?`
+
+	return r.queryTransfers(query, now, limit)
+}
+
+// p2pDailyUsageQuery sums what a user sent to other users in a currency since a time, refunded transfers aside.
+// Transfers being debited count as well, so a transfer reserves its amount from the moment it is checked.
+const p2pDailyUsageQuery = `
+	SELECT COALESCE(SUM(amount), 0), COUNT(*)
+	FROM p2p_transfers
+	WHERE sender_user_id = ? AND currency = ?
+		AND status IN ('pending', 'processing', 'completed')
+		AND (debited_at >= ? OR (debited_at IS NULL AND status = 'processing'))`
+
+// GetDailyUsage sums what a user sent to other users in a currency since a time, refunded transfers aside
+func (r *P2PRepository) GetDailyUsage(userID string, currency string, since time.Time) (*domainp2p.Usage, error) {
+	usage := &domainp2p.Usage{}
+	err := r.db.QueryRow(p2pDailyUsageQuery, userID, currency, since).Scan(&usage.Amount, &usage.Count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily transfer usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// queryTransfers runs a query over the transfer columns
+func (r *P2PRepository) queryTransfers(query string, args ...interface{}) ([]*domainp2p.Transfer, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfers: %w", err)
+	}
+	defer rows.Close()
+
+	transfers := []*domainp2p.Transfer{}
+	for rows.Next() {
+		transfer, err := scanP2PTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transfer: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, rows.Err()
+}
+
+// p2pScanner is implemented by *sql.Row and *sql.Rows
+type p2pScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanP2PProfile(scanner p2pScanner) (*domainp2p.Profile, error) {
+	profile := &domainp2p.Profile{}
+	var alias, defaultAccountID sql.NullString
+	var dailyAmountLimit sql.NullFloat64
+	var dailyCountLimit sql.NullInt64
+
+	err := scanner.Scan(
+		&profile.UserID, &alias, &defaultAccountID, &profile.AutoAccept,
+		&dailyAmountLimit, &dailyCountLimit, &profile.CreatedAt, &profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Alias = alias.String
+	if defaultAccountID.Valid {
+		profile.DefaultAccountID = &defaultAccountID.String
+	}
+	if dailyAmountLimit.Valid {
+		profile.DailyAmountLimit = &dailyAmountLimit.Float64
+	}
+	if dailyCountLimit.Valid {
+		count := int(dailyCountLimit.Int64)
+		profile.DailyCountLimit = &count
+	}
+
+	return profile, nil
+}
+
+func scanP2PParty(scanner p2pScanner) (*domainp2p.Party, error) {
+	party := &domainp2p.Party{}
+	if err := scanner.Scan(&party.UserID, &party.Name, &party.Email, &party.Alias); err != nil {
+		return nil, err
+	}
+	return party, nil
+}
+
+// scanP2PTransfer scans a transfer. The parties are shown by name and alias, without their emails.
+func scanP2PTransfer(scanner p2pScanner) (*domainp2p.Transfer, error) {
+	transfer := &domainp2p.Transfer{
+		Sender:    &domainp2p.Party{},
+		Recipient: &domainp2p.Party{},
+	}
+	var senderAccountID, recipientAccountID sql.NullString
+	var senderTransactionID, recipientTransactionID, refundTransactionID sql.NullString
+	var debitedAt, respondedAt, completedAt sql.NullTime
+
+	err := scanner.Scan(
+		&transfer.ID, &transfer.Kind, &transfer.SenderUserID, &transfer.RecipientUserID,
+		&senderAccountID, &recipientAccountID,
+		&transfer.Amount, &transfer.Currency, &transfer.Description, &transfer.Status, &transfer.Reason,
+		&senderTransactionID, &recipientTransactionID, &refundTransactionID,
+		&debitedAt, &transfer.ExpiresAt, &respondedAt, &completedAt, &transfer.CreatedAt, &transfer.UpdatedAt,
+		&transfer.Sender.Name, &transfer.Sender.Alias, &transfer.Recipient.Name, &transfer.Recipient.Alias,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer.Sender.UserID = transfer.SenderUserID
+	transfer.Recipient.UserID = transfer.RecipientUserID
+	if senderAccountID.Valid {
+		transfer.SenderAccountID = &senderAccountID.String
+	}
+	if recipientAccountID.Valid {
+		transfer.RecipientAccountID = &recipientAccountID.String
+	}
+	if senderTransactionID.Valid {
+		transfer.SenderTransactionID = &senderTransactionID.String
+	}
+	if recipientTransactionID.Valid {
+		transfer.RecipientTransactionID = &recipientTransactionID.String
+	}
+	if refundTransactionID.Valid {
+		transfer.RefundTransactionID = &refundTransactionID.String
+	}
+	if debitedAt.Valid {
+		transfer.DebitedAt = &debitedAt.Time
+	}
+	if respondedAt.Valid {
+		transfer.RespondedAt = &respondedAt.Time
+	}
+	if completedAt.Valid {
+		transfer.CompletedAt = &completedAt.Time
+	}
+
+	return transfer, nil
+}
@@ -0,0 +1,305 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainreconciliation "github.com/fintrack/transaction-service/internal/core/domain/entities/reconciliation"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// ReconciliationRepository implements the ReconciliationRepositoryInterface for MySQL
+type ReconciliationRepository struct {
+	db *sql.DB
+}
+
+// NewReconciliationRepository creates a new MySQL reconciliation repository
+func NewReconciliationRepository(db *sql.DB) service.ReconciliationRepositoryInterface {
+	return &ReconciliationRepository{
+		db: db,
+	}
+}
+
+const reconciliationColumns = `
+	id, user_id, account_id, card_id, currency, source, import_batch_id, file_name, statement_from,
+	statement_to, date_window_days, amount_tolerance, matched_count, pending_count,
+	missing_in_fintrack_count, missing_in_bank_count, completed_at, created_at, updated_at`
+
+const reconciliationItemColumns = `
+	i.id, i.reconciliation_id, i.status, i.match_method, i.score, i.confirmed, i.confirmed_at,
+	i.import_row_id, i.bank_date, i.bank_amount, i.bank_description, i.bank_external_id,
+	i.transaction_id, i.transaction_date, i.transaction_amount, i.transaction_description`
+
+// Create stores a reconciliation with its items
+func (r *ReconciliationRepository) Create(reconciliation *domainreconciliation.Reconciliation, items []*domainreconciliation.Item) (*domainreconciliation.Reconciliation, error) {
+	reconciliation.ID = fmt.Sprintf("rec_%d", time.Now().UnixNano())
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO reconciliations (
+			id, user_id, account_id, card_id, currency, source, import_batch_id, file_name, statement_from,
+			statement_to, date_window_days, amount_tolerance, matched_count, pending_count,
+			missing_in_fintrack_count, missing_in_bank_count, completed_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(query,
+		reconciliation.ID, reconciliation.UserID, reconciliation.AccountID, reconciliation.CardID,
+		reconciliation.Currency, reconciliation.Source, reconciliation.ImportBatchID, reconciliation.FileName,
+		reconciliation.StatementFrom, reconciliation.StatementTo, reconciliation.DateWindowDays,
+		reconciliation.AmountTolerance, reconciliation.MatchedCount, reconciliation.PendingCount,
+		reconciliation.MissingInFinTrackCount, reconciliation.MissingInBankCount, reconciliation.CompletedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reconciliation: %w", err)
+	}
+
+	for i, item := range items {
+		item.ReconciliationID = reconciliation.ID
+		if err := insertReconciliationItem(tx, item, i); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit reconciliation: %w", err)
+	}
+
+	return r.GetByID(reconciliation.ID)
+}
+
+// GetByID retrieves a reconciliation by ID
+func (r *ReconciliationRepository) GetByID(id string) (*domainreconciliation.Reconciliation, error) {
+	query := "SELECT" + reconciliationColumns + " FROM reconciliations WHERE id = ?"
+
+	reconciliation, err := scanReconciliation(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reconciliation not found with ID: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliation: %w", err)
+	}
+
+	return reconciliation, nil
+}
+
+// GetByUserID returns the reconciliations of a user, latest statements first
+func (r *ReconciliationRepository) GetByUserID(userID string, accountID, cardID *string) ([]*domainreconciliation.Reconciliation, error) {
+	whereConditions := []string{"user_id = ?"}
+	args := []interface{}{userID}
+	if accountID != nil {
+		whereConditions = append(whereConditions, "account_id = ?")
+		args = append(args, *accountID)
+	}
+	if cardID != nil {
+		whereConditions = append(whereConditions, "card_id = ?")
+		args = append(args, *cardID)
+	}
+
+	query := "SELECT" + reconciliationColumns + `
+		FROM reconciliations
+		WHERE ` + strings.Join(whereConditions, " AND ") + `
+		ORDER BY statement_to DESC, created_at DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reconciliations: %w", err)
+	}
+	defer rows.Close()
+
+	reconciliations := []*domainreconciliation.Reconciliation{}
+	for rows.Next() {
+		reconciliation, err := scanReconciliation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reconciliation: %w", err)
+		}
+		reconciliations = append(reconciliations, reconciliation)
+	}
+
+	return reconciliations, rows.Err()
+}
+
+// GetItems returns the items of a reconciliation by date
+func (r *ReconciliationRepository) GetItems(reconciliationID string) ([]*domainreconciliation.Item, error) {
+	query := "SELECT" + reconciliationItemColumns + `
+		FROM reconciliation_items i
+		WHERE i.reconciliation_id = ?
+		ORDER BY COALESCE(i.bank_date, i.transaction_date) ASC, i.id ASC`
+
+	return r.queryItems(query, reconciliationID)
+}
+
+// SaveItems updates the changed items, creates the ones without ID and deletes the removed ones,
+// together with the counters of the reconciliation
+func (r *ReconciliationRepository) SaveItems(reconciliation *domainreconciliation.Reconciliation, changed []*domainreconciliation.Item, removedIDs []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateQuery := `
+		UPDATE reconciliation_items
+		SET status = ?, match_method = ?, score = ?, confirmed = ?, confirmed_at = ?,
+			transaction_id = ?, transaction_date = ?, transaction_amount = ?, transaction_description = ?
+		WHERE id = ? AND reconciliation_id = ?`
+
+	for i, item := range changed {
+		if item.ID == "" {
+			item.ReconciliationID = reconciliation.ID
+			if err := insertReconciliationItem(tx, item, i); err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err := tx.Exec(updateQuery,
+			item.Status, nullableString(string(item.MatchMethod)), item.Score, item.Confirmed, item.ConfirmedAt,
+			item.TransactionID, item.TransactionDate, item.TransactionAmount, item.TransactionDescription,
+			item.ID, reconciliation.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update reconciliation item: %w", err)
+		}
+	}
+
+	for _, id := range removedIDs {
+		if _, err := tx.Exec("DELETE FROM reconciliation_items WHERE id = ? AND reconciliation_id = ?", id, reconciliation.ID); err != nil {
+			return fmt.Errorf("failed to delete reconciliation item: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reconciliations
+		SET matched_count = ?, pending_count = ?, missing_in_fintrack_count = ?, missing_in_bank_count = ?,
+			completed_at = ?
+		WHERE id = ?`,
+		reconciliation.MatchedCount, reconciliation.PendingCount, reconciliation.MissingInFinTrackCount,
+		reconciliation.MissingInBankCount, reconciliation.CompletedAt, reconciliation.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update reconciliation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit reconciliation items: %w", err)
+	}
+
+	return nil
+}
+
+// GetItemsUntil returns the items of the reconciliations of an account or card dated up to a date
+func (r *ReconciliationRepository) GetItemsUntil(userID string, accountID, cardID *string, until time.Time) ([]*domainreconciliation.Item, error) {
+	whereConditions := []string{"rc.user_id = ?", "COALESCE(i.bank_date, i.transaction_date) <= ?"}
+	args := []interface{}{userID, until}
+	if accountID != nil {
+		whereConditions = append(whereConditions, "rc.account_id = ?")
+		args = append(args, *accountID)
+	}
+	if cardID != nil {
+		whereConditions = append(whereConditions, "rc.card_id = ?")
+		args = append(args, *cardID)
+	}
+
+	query := "SELECT" + reconciliationItemColumns + `
+		FROM reconciliation_items i
+		INNER JOIN reconciliations rc ON rc.id = i.reconciliation_id
+		WHERE ` + strings.Join(whereConditions, " AND ") + `
+		ORDER BY COALESCE(i.bank_date, i.transaction_date) DESC, i.id ASC`
+
+	return r.queryItems(query, args...)
+}
+
+func (r *ReconciliationRepository) queryItems(query string, args ...interface{}) ([]*domainreconciliation.Item, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reconciliation items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*domainreconciliation.Item{}
+	for rows.Next() {
+		item := &domainreconciliation.Item{}
+		var matchMethod, bankDescription, bankExternalID, transactionDescription sql.NullString
+
+		err := rows.Scan(
+			&item.ID, &item.ReconciliationID, &item.Status, &matchMethod, &item.Score, &item.Confirmed,
+			&item.ConfirmedAt, &item.ImportRowID, &item.BankDate, &item.BankAmount, &bankDescription,
+			&bankExternalID, &item.TransactionID, &item.TransactionDate, &item.TransactionAmount,
+			&transactionDescription,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reconciliation item: %w", err)
+		}
+
+		item.MatchMethod = domainreconciliation.MatchMethod(matchMethod.String)
+		item.BankDescription = bankDescription.String
+		item.BankExternalID = bankExternalID.String
+		item.TransactionDescription = transactionDescription.String
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func insertReconciliationItem(tx *sql.Tx, item *domainreconciliation.Item, index int) error {
+	item.ID = fmt.Sprintf("reci_%d_%d", time.Now().UnixNano(), index)
+
+	query := `
+		INSERT INTO reconciliation_items (
+			id, reconciliation_id, status, match_method, score, confirmed, confirmed_at, import_row_id,
+			bank_date, bank_amount, bank_description, bank_external_id, transaction_id, transaction_date,
+			transaction_amount, transaction_description
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(query,
+		item.ID, item.ReconciliationID, item.Status, nullableString(string(item.MatchMethod)), item.Score,
+		item.Confirmed, item.ConfirmedAt, item.ImportRowID, item.BankDate, item.BankAmount,
+		item.BankDescription, item.BankExternalID, item.TransactionID, item.TransactionDate,
+		item.TransactionAmount, item.TransactionDescription,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create reconciliation item: %w", err)
+	}
+	return nil
+}
+
+// reconciliationScanner is implemented by *sql.Row and *sql.Rows
+type reconciliationScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanReconciliation(scanner reconciliationScanner) (*domainreconciliation.Reconciliation, error) {
+	reconciliation := &domainreconciliation.Reconciliation{}
+	var fileName sql.NullString
+
+	err := scanner.Scan(
+		&reconciliation.ID, &reconciliation.UserID, &reconciliation.AccountID, &reconciliation.CardID,
+		&reconciliation.Currency, &reconciliation.Source, &reconciliation.ImportBatchID, &fileName,
+		&reconciliation.StatementFrom, &reconciliation.StatementTo, &reconciliation.DateWindowDays,
+		&reconciliation.AmountTolerance, &reconciliation.MatchedCount, &reconciliation.PendingCount,
+		&reconciliation.MissingInFinTrackCount, &reconciliation.MissingInBankCount, &reconciliation.CompletedAt,
+		&reconciliation.CreatedAt, &reconciliation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation.FileName = fileName.String
+	return reconciliation, nil
+}
+
+// nullableString stores empty strings as NULL
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,261 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	domainschedule "github.com/fintrack/transaction-service/internal/core/domain/entities/schedule"
+	"github.com/fintrack/transaction-service/internal/core/service"
+	gomysql "github.com/go-sql-driver/mysql"
+)
+
+// mysqlDuplicateEntry is the MySQL error number of a unique key violation
+const mysqlDuplicateEntry = 1062
+
+// ScheduledTransactionRepository implements the ScheduledTransactionRepositoryInterface for MySQL
+type ScheduledTransactionRepository struct {
+	db *sql.DB
+}
+
+// NewScheduledTransactionRepository creates a new MySQL scheduled transaction repository
+func NewScheduledTransactionRepository(db *sql.DB) service.ScheduledTransactionRepositoryInterface {
+	return &ScheduledTransactionRepository{
+		db: db,
+	}
+}
+
+const scheduledTransactionColumns = `id, user_id, type, amount, currency, from_account_id, to_account_id,
+	from_card_id, to_card_id, description, merchant_name, category_id, recurrence, start_at, status,
+	next_occurrence_at, next_run_at, next_amount, next_description, occurrence_count, consecutive_failures,
+	last_run_at, last_error, created_at, updated_at`
+
+const scheduledRunColumns = `id, schedule_id, scheduled_for, status, transaction_id, amount, error, created_at, updated_at`
+
+// Create inserts a new scheduled transaction
+func (r *ScheduledTransactionRepository) Create(schedule *domainschedule.ScheduledTransaction) (*domainschedule.ScheduledTransaction, error) {
+	if schedule.ID == "" {
+		schedule.ID = fmt.Sprintf("sched_%d", time.Now().UnixNano())
+	}
+
+	query := `
+		INSERT INTO scheduled_transactions (
+			id, user_id, type, amount, currency, from_account_id, to_account_id,
+			from_card_id, to_card_id, description, merchant_name, category_id, recurrence, start_at, status,
+			next_occurrence_at, next_run_at, next_amount, next_description, occurrence_count, consecutive_failures,
+			last_run_at, last_error, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`
+
+	_, err := r.db.Exec(query,
+		schedule.ID, schedule.UserID, schedule.Type, schedule.Amount, schedule.Currency,
+		schedule.FromAccountID, schedule.ToAccountID, schedule.FromCardID, schedule.ToCardID,
+		schedule.Description, schedule.MerchantName, schedule.CategoryID, schedule.Recurrence,
+		schedule.StartAt, schedule.Status, schedule.NextOccurrenceAt, schedule.NextRunAt,
+		schedule.NextAmount, schedule.NextDescription, schedule.OccurrenceCount,
+		schedule.ConsecutiveFailures, schedule.LastRunAt, schedule.LastError,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scheduled transaction: %w", err)
+	}
+
+	return r.GetByID(schedule.ID)
+}
+
+// GetByID retrieves a scheduled transaction by its ID
+func (r *ScheduledTransactionRepository) GetByID(id string) (*domainschedule.ScheduledTransaction, error) {
+	query := `SELECT ` + scheduledTransactionColumns + ` FROM scheduled_transactions WHERE id = ?`
+
+	schedule, err := scanScheduledTransaction(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("scheduled transaction not found with ID: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get scheduled transaction: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// Update saves the template, status and next occurrence of a scheduled transaction
+func (r *ScheduledTransactionRepository) Update(schedule *domainschedule.ScheduledTransaction) (*domainschedule.ScheduledTransaction, error) {
+	query := `
+		UPDATE scheduled_transactions SET
+			type = ?, amount = ?, currency = ?, from_account_id = ?, to_account_id = ?,
+			from_card_id = ?, to_card_id = ?, description = ?, merchant_name = ?, category_id = ?,
+			recurrence = ?, start_at = ?, status = ?, next_occurrence_at = ?, next_run_at = ?,
+			next_amount = ?, next_description = ?, occurrence_count = ?, consecutive_failures = ?,
+			last_run_at = ?, last_error = ?, updated_at = NOW()
+		WHERE id = ?`
+
+	_, err := r.db.Exec(query,
+		schedule.Type, schedule.Amount, schedule.Currency, schedule.FromAccountID, schedule.ToAccountID,
+		schedule.FromCardID, schedule.ToCardID, schedule.Description, schedule.MerchantName, schedule.CategoryID,
+		schedule.Recurrence, schedule.StartAt, schedule.Status, schedule.NextOccurrenceAt, schedule.NextRunAt,
+		schedule.NextAmount, schedule.NextDescription, schedule.OccurrenceCount, schedule.ConsecutiveFailures,
+		schedule.LastRunAt, schedule.LastError, schedule.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update scheduled transaction: %w", err)
+	}
+
+	return r.GetByID(schedule.ID)
+}
+
+// GetByUserID returns the scheduled transactions of a user by next run, optionally filtered by status
+func (r *ScheduledTransactionRepository) GetByUserID(userID string, status *domainschedule.ScheduleStatus) ([]*domainschedule.ScheduledTransaction, error) {
+	query := `SELECT ` + scheduledTransactionColumns + ` FROM scheduled_transactions WHERE user_id = ?`
+	args := []interface{}{userID}
+	if status != nil {
+		query += ` AND status = ?`
+		args = append(args, *status)
+	}
+	query += ` ORDER BY next_run_at IS NULL, next_run_at ASC, created_at DESC`
+
+	return r.querySchedules(query, args...)
+}
+
+// GetDue returns active schedules whose next run is at or before now, oldest first
+func (r *ScheduledTransactionRepository) GetDue(now time.Time, limit int) ([]*domainschedule.ScheduledTransaction, error) {
+	query := `SELECT ` + scheduledTransactionColumns + ` FROM scheduled_transactions
+		WHERE status = ? AND next_run_at IS NOT NULL AND next_run_at <= ?
+		ORDER BY next_run_at ASC
+		LIMIT ?`
+
+	return r.querySchedules(query, domainschedule.ScheduleStatusActive, now, limit)
+}
+
+// CreateRun claims an occurrence of a schedule. The unique key on (schedule_id, scheduled_for)
+// makes a second claim of the same occurrence fail with ErrRunAlreadyClaimed.
+func (r *ScheduledTransactionRepository) CreateRun(run *domainschedule.ScheduledRun) (*domainschedule.ScheduledRun, error) {
+	if run.ID == "" {
+		run.ID = fmt.Sprintf("srun_%d", time.Now().UnixNano())
+	}
+
+	query := `
+		INSERT INTO scheduled_transaction_runs (
+			id, schedule_id, scheduled_for, status, transaction_id, amount, error, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`
+
+	_, err := r.db.Exec(query,
+		run.ID, run.ScheduleID, run.ScheduledFor, run.Status, run.TransactionID, run.Amount, run.Error,
+	)
+	if err != nil {
+		var mysqlErr *gomysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return nil, service.ErrRunAlreadyClaimed
+		}
+		return nil, fmt.Errorf("failed to create scheduled run: %w", err)
+	}
+
+	now := time.Now()
+	run.CreatedAt = now
+	run.UpdatedAt = now
+	return run, nil
+}
+
+// UpdateRun saves the outcome of a run
+func (r *ScheduledTransactionRepository) UpdateRun(run *domainschedule.ScheduledRun) error {
+	query := `
+		UPDATE scheduled_transaction_runs
+		SET status = ?, transaction_id = ?, amount = ?, error = ?, updated_at = NOW()
+		WHERE id = ?`
+
+	if _, err := r.db.Exec(query, run.Status, run.TransactionID, run.Amount, run.Error, run.ID); err != nil {
+		return fmt.Errorf("failed to update scheduled run: %w", err)
+	}
+	return nil
+}
+
+// GetRuns returns the latest runs of a schedule, newest occurrence first
+func (r *ScheduledTransactionRepository) GetRuns(scheduleID string, limit int) ([]*domainschedule.ScheduledRun, error) {
+	query := `SELECT ` + scheduledRunColumns + ` FROM scheduled_transaction_runs
+		WHERE schedule_id = ?
+		ORDER BY scheduled_for DESC
+		LIMIT ?`
+
+	rows, err := r.db.Query(query, scheduleID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scheduled runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []*domainschedule.ScheduledRun{}
+	for rows.Next() {
+		run := &domainschedule.ScheduledRun{}
+		var errorMessage sql.NullString
+
+		err := rows.Scan(
+			&run.ID, &run.ScheduleID, &run.ScheduledFor, &run.Status, &run.TransactionID,
+			&run.Amount, &errorMessage, &run.CreatedAt, &run.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled run: %w", err)
+		}
+
+		run.Error = errorMessage.String
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+func (r *ScheduledTransactionRepository) querySchedules(query string, args ...interface{}) ([]*domainschedule.ScheduledTransaction, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scheduled transactions: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []*domainschedule.ScheduledTransaction{}
+	for rows.Next() {
+		schedule, err := scanScheduledTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled transaction: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+func scanScheduledTransaction(scanner categoryScanner) (*domainschedule.ScheduledTransaction, error) {
+	schedule := &domainschedule.ScheduledTransaction{}
+	var description, merchantName, recurrence, lastError sql.NullString
+	var nextAmount sql.NullFloat64
+	var nextDescription sql.NullString
+	var nextOccurrenceAt, nextRunAt, lastRunAt sql.NullTime
+
+	err := scanner.Scan(
+		&schedule.ID, &schedule.UserID, &schedule.Type, &schedule.Amount, &schedule.Currency,
+		&schedule.FromAccountID, &schedule.ToAccountID, &schedule.FromCardID, &schedule.ToCardID,
+		&description, &merchantName, &schedule.CategoryID, &recurrence, &schedule.StartAt, &schedule.Status,
+		&nextOccurrenceAt, &nextRunAt, &nextAmount, &nextDescription, &schedule.OccurrenceCount,
+		&schedule.ConsecutiveFailures, &lastRunAt, &lastError, &schedule.CreatedAt, &schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Description = description.String
+	schedule.MerchantName = merchantName.String
+	schedule.Recurrence = recurrence.String
+	schedule.LastError = lastError.String
+	if nextAmount.Valid {
+		schedule.NextAmount = &nextAmount.Float64
+	}
+	if nextDescription.Valid {
+		schedule.NextDescription = &nextDescription.String
+	}
+	if nextOccurrenceAt.Valid {
+		schedule.NextOccurrenceAt = &nextOccurrenceAt.Time
+	}
+	if nextRunAt.Valid {
+		schedule.NextRunAt = &nextRunAt.Time
+	}
+	if lastRunAt.Valid {
+		schedule.LastRunAt = &lastRunAt.Time
+	}
+
+	return schedule, nil
+}
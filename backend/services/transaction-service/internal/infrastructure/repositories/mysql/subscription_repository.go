@@ -0,0 +1,149 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainsubscription "github.com/fintrack/transaction-service/internal/core/domain/entities/subscription"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// SubscriptionRepository implements the SubscriptionRepositoryInterface for MySQL
+type SubscriptionRepository struct {
+	db *sql.DB
+}
+
+// NewSubscriptionRepository creates a new MySQL subscription repository
+func NewSubscriptionRepository(db *sql.DB) service.SubscriptionRepositoryInterface {
+	return &SubscriptionRepository{
+		db: db,
+	}
+}
+
+// ReplaceForUser atomically replaces the detected subscriptions of a user
+func (r *SubscriptionRepository) ReplaceForUser(userID string, subscriptions []*domainsubscription.Subscription) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM detected_subscriptions WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("failed to delete subscriptions: %w", err)
+	}
+
+	query := `
+		INSERT INTO detected_subscriptions (
+			id, user_id, merchant_key, merchant_name, currency, period, amount, annualized_cost,
+			charge_count, first_charge_at, last_charge_at, next_expected_at, status,
+			previous_amount, price_changed_at, price_changes, card_id, account_id, category_id,
+			last_transaction_id, detected_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for i, subscription := range subscriptions {
+		subscription.ID = fmt.Sprintf("sub_%d_%d", time.Now().UnixNano(), i)
+
+		priceChanges, err := json.Marshal(subscription.PriceChanges)
+		if err != nil {
+			return fmt.Errorf("failed to marshal price changes: %w", err)
+		}
+
+		// The latest price change is also kept in columns so other services can query it
+		var previousAmount *float64
+		var priceChangedAt *time.Time
+		if change := subscription.LatestPriceChange(); change != nil {
+			previousAmount = &change.PreviousAmount
+			priceChangedAt = &change.Date
+		}
+
+		_, err = tx.Exec(query,
+			subscription.ID, userID, subscription.MerchantKey, subscription.MerchantName, subscription.Currency,
+			subscription.Period, subscription.Amount, subscription.AnnualizedCost, subscription.ChargeCount,
+			subscription.FirstChargeAt, subscription.LastChargeAt, subscription.NextExpectedAt, subscription.Status,
+			previousAmount, priceChangedAt, string(priceChanges), subscription.CardID, subscription.AccountID,
+			subscription.CategoryID, subscription.LastTransactionID, subscription.DetectedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create subscription: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit subscriptions: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID returns the last detected subscriptions of a user, highest cost first
+func (r *SubscriptionRepository) GetByUserID(userID string) ([]*domainsubscription.Subscription, error) {
+	query := `
+		SELECT id, user_id, merchant_key, merchant_name, currency, period, amount, annualized_cost,
+			charge_count, first_charge_at, last_charge_at, next_expected_at, status, price_changes,
+			card_id, account_id, category_id, last_transaction_id, detected_at
+		FROM detected_subscriptions
+		WHERE user_id = ?
+		ORDER BY status = 'cancelled', annualized_cost DESC`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []*domainsubscription.Subscription{}
+	for rows.Next() {
+		subscription := &domainsubscription.Subscription{}
+		var priceChanges sql.NullString
+
+		err := rows.Scan(
+			&subscription.ID, &subscription.UserID, &subscription.MerchantKey, &subscription.MerchantName,
+			&subscription.Currency, &subscription.Period, &subscription.Amount, &subscription.AnnualizedCost,
+			&subscription.ChargeCount, &subscription.FirstChargeAt, &subscription.LastChargeAt,
+			&subscription.NextExpectedAt, &subscription.Status, &priceChanges, &subscription.CardID,
+			&subscription.AccountID, &subscription.CategoryID, &subscription.LastTransactionID, &subscription.DetectedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+
+		if priceChanges.Valid && priceChanges.String != "" {
+			if err := json.Unmarshal([]byte(priceChanges.String), &subscription.PriceChanges); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal price changes: %w", err)
+			}
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// GetUsersWithCharges returns the users with completed card or account debits since the given date
+func (r *SubscriptionRepository) GetUsersWithCharges(since time.Time) ([]string, error) {
+	query := `
+		SELECT DISTINCT user_id
+		FROM transactions
+		WHERE type IN ('credit_charge', 'debit_purchase', 'account_withdraw')
+			AND status = 'completed'
+			AND created_at >= ?`
+
+	rows, err := r.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users with charges: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan user ID: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
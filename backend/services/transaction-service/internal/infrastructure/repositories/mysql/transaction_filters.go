@@ -0,0 +1,253 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+// fullTextMinTokenLength is the default innodb_ft_min_token_size: shorter words are not indexed
+const fullTextMinTokenLength = 3
+
+// fullTextStopwords is the default InnoDB stopword list. These words are not indexed either, so they are
+// searched like the short ones.
+var fullTextStopwords = map[string]bool{
+	"a": true, "about": true, "an": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"com": true, "de": true, "en": true, "for": true, "from": true, "how": true, "i": true, "in": true,
+	"is": true, "it": true, "la": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "was": true, "what": true, "when": true, "where": true, "who": true,
+	"will": true, "with": true, "und": true, "www": true,
+}
+
+// transactionScope restricts a listing to the transactions of a user, an account or a card. outgoing
+// selects the ones that took money out of it, for the amount sign filter.
+type transactionScope struct {
+	condition    string
+	args         []interface{}
+	outgoing     string
+	outgoingArgs []interface{}
+}
+
+// userScope covers the own transactions of a user, the ones they performed as a member of a shared account
+// and, for members with visibility over the whole account, every transaction of the shared accounts
+func userScope(userID string) transactionScope {
+	return transactionScope{
+		condition: `(user_id = ? OR initiated_by = ?
+			OR from_account_id IN (` + sharedAccountIDsQuery + `)
+			OR to_account_id IN (` + sharedAccountIDsQuery + `))`,
+		args:     []interface{}{userID, userID, userID, userID},
+		outgoing: "(from_account_id IS NOT NULL OR from_card_id IS NOT NULL)",
+	}
+}
+
+// memberVisibility is the condition of the transactions a user can see within an account or a card: their own,
+// the ones they performed, every one of the accounts shared with them with visibility over the whole account
+// and, for card holders, only the ones of their card
+func memberVisibility(userID string) (string, []interface{}) {
+	condition := `(user_id = ? OR initiated_by = ?
+		OR from_account_id IN (` + sharedAccountIDsQuery + `)
+		OR to_account_id IN (` + sharedAccountIDsQuery + `)
+		OR from_card_id IN (` + holderCardIDsQuery + `)
+		OR to_card_id IN (` + holderCardIDsQuery + `))`
+	return condition, []interface{}{userID, userID, userID, userID, userID, userID}
+}
+
+// accountScope covers the transactions of an account that a user can see
+func accountScope(accountID, userID string) transactionScope {
+	visibility, visibilityArgs := memberVisibility(userID)
+	return transactionScope{
+		condition:    "(from_account_id = ? OR to_account_id = ?) AND " + visibility,
+		args:         append([]interface{}{accountID, accountID}, visibilityArgs...),
+		outgoing:     "from_account_id = ?",
+		outgoingArgs: []interface{}{accountID},
+	}
+}
+
+// cardScope covers the transactions of a card that a user can see
+func cardScope(cardID, userID string) transactionScope {
+	visibility, visibilityArgs := memberVisibility(userID)
+	return transactionScope{
+		condition:    "(from_card_id = ? OR to_card_id = ?) AND " + visibility,
+		args:         append([]interface{}{cardID, cardID}, visibilityArgs...),
+		outgoing:     "from_card_id = ?",
+		outgoingArgs: []interface{}{cardID},
+	}
+}
+
+// where builds the WHERE clause of the scope and the filters. The cursor is left out: it only applies to pages.
+func (s transactionScope) where(filters service.TransactionFilters) (string, []interface{}) {
+	whereConditions := []string{s.condition}
+	args := append([]interface{}{}, s.args...)
+
+	if len(filters.Types) > 0 {
+		placeholders := make([]string, len(filters.Types))
+		for i, transactionType := range filters.Types {
+			placeholders[i] = "?"
+			args = append(args, transactionType)
+		}
+		whereConditions = append(whereConditions, fmt.Sprintf("type IN (%s)", strings.Join(placeholders, ",")))
+	}
+
+	if len(filters.Statuses) > 0 {
+		placeholders := make([]string, len(filters.Statuses))
+		for i, status := range filters.Statuses {
+			placeholders[i] = "?"
+			args = append(args, status)
+		}
+		whereConditions = append(whereConditions, fmt.Sprintf("status IN (%s)", strings.Join(placeholders, ",")))
+	}
+
+	if len(filters.Currencies) > 0 {
+		placeholders := make([]string, len(filters.Currencies))
+		for i, currency := range filters.Currencies {
+			placeholders[i] = "?"
+			args = append(args, strings.ToUpper(currency))
+		}
+		whereConditions = append(whereConditions, fmt.Sprintf("currency IN (%s)", strings.Join(placeholders, ",")))
+	}
+
+	if filters.FromDate != nil {
+		whereConditions = append(whereConditions, "created_at >= ?")
+		args = append(args, *filters.FromDate)
+	}
+
+	if filters.ToDate != nil {
+		whereConditions = append(whereConditions, "created_at <= ?")
+		args = append(args, *filters.ToDate)
+	}
+
+	if filters.MinAmount != nil {
+		whereConditions = append(whereConditions, "amount >= ?")
+		args = append(args, *filters.MinAmount)
+	}
+
+	if filters.MaxAmount != nil {
+		whereConditions = append(whereConditions, "amount <= ?")
+		args = append(args, *filters.MaxAmount)
+	}
+
+	switch filters.AmountSign {
+	case service.AmountSignNegative:
+		whereConditions = append(whereConditions, s.outgoing)
+		args = append(args, s.outgoingArgs...)
+	case service.AmountSignPositive:
+		// IS NOT TRUE keeps the transactions without a source, where the comparison is NULL
+		whereConditions = append(whereConditions, "("+s.outgoing+") IS NOT TRUE")
+		args = append(args, s.outgoingArgs...)
+	}
+
+	if filters.AccountID != nil {
+		whereConditions = append(whereConditions, "(from_account_id = ? OR to_account_id = ?)")
+		args = append(args, *filters.AccountID, *filters.AccountID)
+	}
+
+	if filters.CardID != nil {
+		whereConditions = append(whereConditions, "(from_card_id = ? OR to_card_id = ?)")
+		args = append(args, *filters.CardID, *filters.CardID)
+	}
+
+	if filters.MerchantName != nil {
+		whereConditions = append(whereConditions, "merchant_name = ?")
+		args = append(args, *filters.MerchantName)
+	}
+
+	if filters.PaymentMethod != nil {
+		whereConditions = append(whereConditions, "payment_method = ?")
+		args = append(args, *filters.PaymentMethod)
+	}
+
+	if filters.CategoryID != nil {
+		// A category includes the transactions of its subcategories and the split transactions with a part in it
+		whereConditions = append(whereConditions, `(category_id = ? OR category_id IN (SELECT id FROM transaction_categories WHERE parent_id = ?)
+			OR id IN (SELECT transaction_id FROM transaction_allocations WHERE category_id = ?
+				OR category_id IN (SELECT id FROM transaction_categories WHERE parent_id = ?)))`)
+		args = append(args, *filters.CategoryID, *filters.CategoryID, *filters.CategoryID, *filters.CategoryID)
+	}
+
+	if filters.Uncategorized {
+		whereConditions = append(whereConditions, "category_id IS NULL")
+	}
+
+	for _, tag := range filters.Tags {
+		whereConditions = append(whereConditions, "JSON_CONTAINS(tags, JSON_QUOTE(?))")
+		args = append(args, tag)
+	}
+
+	if condition, searchArgs := searchCondition(filters.Search); condition != "" {
+		whereConditions = append(whereConditions, condition)
+		args = append(args, searchArgs...)
+	}
+
+	return strings.Join(whereConditions, " AND "), args
+}
+
+// searchCondition matches every word of a free text search. Indexed words go to the FULLTEXT index as
+// required prefixes; short words and stopwords, which the index skips, are matched with LIKE.
+func searchCondition(search string) (string, []interface{}) {
+	words := strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var fullText []string
+	var conditions []string
+	var args []interface{}
+	for _, word := range words {
+		if len([]rune(word)) >= fullTextMinTokenLength && !fullTextStopwords[word] {
+			fullText = append(fullText, "+"+word+"*")
+			continue
+		}
+		pattern := "%" + word + "%"
+		conditions = append(conditions, "(description LIKE ? OR merchant_name LIKE ? OR notes LIKE ? OR tags_text LIKE ?)")
+		args = append(args, pattern, pattern, pattern, pattern)
+	}
+
+	if len(fullText) > 0 {
+		conditions = append([]string{"MATCH(description, merchant_name, notes, tags_text) AGAINST (? IN BOOLEAN MODE)"}, conditions...)
+		args = append([]interface{}{strings.Join(fullText, " ")}, args...)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// transactionsOrder returns the sort column and direction of the filters. Unknown columns sort by creation date.
+func transactionsOrder(filters service.TransactionFilters) (string, string) {
+	sortKey, ascending, err := domaintransaction.ParseSort(filters.OrderBy, filters.Order)
+	if err != nil {
+		sortKey, ascending = domaintransaction.SortByCreatedAt, false
+	}
+
+	if ascending {
+		return sortKey, "ASC"
+	}
+	return sortKey, "DESC"
+}
+
+// transactionsOrderBy builds the ORDER BY clause of the filters, with the ID as tiebreaker
+func transactionsOrderBy(filters service.TransactionFilters) string {
+	sortKey, direction := transactionsOrder(filters)
+	return fmt.Sprintf("%s %s, id %s", sortKey, direction, direction)
+}
+
+// cursorCondition selects the transactions after the cursor in the order of the filters
+func cursorCondition(filters service.TransactionFilters) (string, []interface{}, error) {
+	sortKey, direction := transactionsOrder(filters)
+	cursor := filters.Cursor
+	if cursor.SortKey != sortKey || cursor.Ascending != (direction == "ASC") {
+		return "", nil, fmt.Errorf("cursor was issued for another order")
+	}
+
+	value, err := cursor.SortValue()
+	if err != nil {
+		return "", nil, err
+	}
+
+	operator := "<"
+	if direction == "ASC" {
+		operator = ">"
+	}
+	condition := fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", sortKey, operator, sortKey, operator)
+	return condition, []interface{}{value, value, cursor.ID}, nil
+}
@@ -0,0 +1,289 @@
+package mysql
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	domaintransaction "github.com/fintrack/transaction-service/internal/core/domain/entities/transaction"
+	"github.com/fintrack/transaction-service/internal/core/service"
+)
+
+func TestCursorCondition(t *testing.T) {
+	createdAt := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		orderBy           string
+		order             string
+		cursor            *domaintransaction.Cursor
+		expectedCondition string
+		expectedArgs      []interface{}
+		expectError       bool
+	}{
+		{
+			name:              "newest first by default",
+			cursor:            &domaintransaction.Cursor{SortKey: "created_at", Value: "2025-03-10T12:00:00Z", ID: "tx-5"},
+			expectedCondition: "(created_at < ? OR (created_at = ? AND id < ?))",
+			expectedArgs:      []interface{}{createdAt, createdAt, "tx-5"},
+		},
+		{
+			name:              "amount ascending",
+			orderBy:           "amount",
+			cursor:            &domaintransaction.Cursor{SortKey: "amount", Value: "1500.00", ID: "tx-5", Ascending: true},
+			expectedCondition: "(amount > ? OR (amount = ? AND id > ?))",
+			expectedArgs:      []interface{}{1500.0, 1500.0, "tx-5"},
+		},
+		{
+			name:              "update date descending",
+			orderBy:           "updatedAt",
+			order:             "desc",
+			cursor:            &domaintransaction.Cursor{SortKey: "updated_at", Value: "2025-03-10T12:00:00Z", ID: "tx-5"},
+			expectedCondition: "(updated_at < ? OR (updated_at = ? AND id < ?))",
+			expectedArgs:      []interface{}{createdAt, createdAt, "tx-5"},
+		},
+		{
+			name:        "cursor of another sort key",
+			orderBy:     "amount",
+			cursor:      &domaintransaction.Cursor{SortKey: "created_at", Value: "2025-03-10T12:00:00Z", ID: "tx-5", Ascending: true},
+			expectError: true,
+		},
+		{
+			name:        "cursor of another direction",
+			orderBy:     "amount",
+			order:       "desc",
+			cursor:      &domaintransaction.Cursor{SortKey: "amount", Value: "1500.00", ID: "tx-5", Ascending: true},
+			expectError: true,
+		},
+		{
+			name:        "sort value that does not parse",
+			orderBy:     "amount",
+			cursor:      &domaintransaction.Cursor{SortKey: "amount", Value: "mucho", ID: "tx-5", Ascending: true},
+			expectError: true,
+		},
+		{
+			// An orderBy outside the whitelist never reaches the query: it sorts by creation date
+			name:              "column outside the whitelist",
+			orderBy:           "merchant_name",
+			cursor:            &domaintransaction.Cursor{SortKey: "created_at", Value: "2025-03-10T12:00:00Z", ID: "tx-5"},
+			expectedCondition: "(created_at < ? OR (created_at = ? AND id < ?))",
+			expectedArgs:      []interface{}{createdAt, createdAt, "tx-5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := service.TransactionFilters{OrderBy: tt.orderBy, Order: tt.order, Cursor: tt.cursor}
+
+			condition, args, err := cursorCondition(filters)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("cursorCondition() = %s, expected error but got none", condition)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cursorCondition() unexpected error: %v", err)
+			}
+			if condition != tt.expectedCondition {
+				t.Errorf("cursorCondition() = %s, want %s", condition, tt.expectedCondition)
+			}
+			if len(args) != len(tt.expectedArgs) {
+				t.Fatalf("cursorCondition() args = %v, want %v", args, tt.expectedArgs)
+			}
+			for i, arg := range args {
+				if date, ok := arg.(time.Time); ok {
+					if !date.Equal(tt.expectedArgs[i].(time.Time)) {
+						t.Errorf("cursorCondition() arg %d = %v, want %v", i, arg, tt.expectedArgs[i])
+					}
+				} else if arg != tt.expectedArgs[i] {
+					t.Errorf("cursorCondition() arg %d = %v, want %v", i, arg, tt.expectedArgs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionsOrderBy(t *testing.T) {
+	tests := []struct {
+		orderBy  string
+		order    string
+		expected string
+	}{
+		{"", "", "created_at DESC, id DESC"},
+		{"date", "", "created_at ASC, id ASC"},
+		{"amount", "desc", "amount DESC, id DESC"},
+		{"updated_at", "asc", "updated_at ASC, id ASC"},
+		{"merchant_name", "asc", "created_at DESC, id DESC"},
+		{"amount; DROP TABLE transactions", "", "created_at DESC, id DESC"},
+	}
+
+	for _, tt := range tests {
+		filters := service.TransactionFilters{OrderBy: tt.orderBy, Order: tt.order}
+		if got := transactionsOrderBy(filters); got != tt.expected {
+			t.Errorf("transactionsOrderBy(%q, %q) = %s, want %s", tt.orderBy, tt.order, got, tt.expected)
+		}
+	}
+}
+
+// keysetCondition matches the conditions built by cursorCondition
+var keysetCondition = regexp.MustCompile(`^\((\w+) ([<>]) \? OR \((\w+) = \? AND id ([<>]) \?\)\)$`)
+
+// sortValue returns the value of a transaction in a sort column, as MySQL compares it
+func sortValue(transaction *domaintransaction.Transaction, column string) float64 {
+	switch column {
+	case domaintransaction.SortByAmount:
+		return transaction.Amount
+	case domaintransaction.SortByUpdatedAt:
+		return float64(transaction.UpdatedAt.UnixNano())
+	default:
+		return float64(transaction.CreatedAt.UnixNano())
+	}
+}
+
+// afterCursor evaluates a condition of cursorCondition on a transaction, as the database would
+func afterCursor(t *testing.T, condition string, args []interface{}, transaction *domaintransaction.Transaction) bool {
+	t.Helper()
+	parts := keysetCondition.FindStringSubmatch(condition)
+	if parts == nil || parts[1] != parts[3] || parts[2] != parts[4] {
+		t.Fatalf("cursorCondition() = %s, not a keyset condition", condition)
+	}
+
+	var value float64
+	switch arg := args[0].(type) {
+	case time.Time:
+		value = float64(arg.UnixNano())
+	case float64:
+		value = arg
+	}
+	cursorID := args[2].(string)
+
+	column, greater := parts[1], parts[2] == ">"
+	own := sortValue(transaction, column)
+	if own == value {
+		return (transaction.ID > cursorID) == greater && transaction.ID != cursorID
+	}
+	return (own > value) == greater
+}
+
+// page returns the transactions of a page as the repository queries them: after the cursor in the order of
+// the filters, up to the limit
+func page(t *testing.T, transactions []*domaintransaction.Transaction, filters service.TransactionFilters) []*domaintransaction.Transaction {
+	t.Helper()
+	var selected []*domaintransaction.Transaction
+	for _, transaction := range transactions {
+		if filters.Cursor != nil {
+			condition, args, err := cursorCondition(filters)
+			if err != nil {
+				t.Fatalf("cursorCondition() unexpected error: %v", err)
+			}
+			if !afterCursor(t, condition, args, transaction) {
+				continue
+			}
+		}
+		selected = append(selected, transaction)
+	}
+
+	column, direction := transactionsOrder(filters)
+	sort.Slice(selected, func(i, j int) bool {
+		a, b := sortValue(selected[i], column), sortValue(selected[j], column)
+		if a == b {
+			return (selected[i].ID < selected[j].ID) == (direction == "ASC")
+		}
+		return (a < b) == (direction == "ASC")
+	})
+
+	if len(selected) > filters.Limit {
+		selected = selected[:filters.Limit]
+	}
+	return selected
+}
+
+func TestCursorPaging(t *testing.T) {
+	base := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	// Batches of transactions share their creation date and amount, so most pages end within a tie
+	var transactions []*domaintransaction.Transaction
+	for i := 0; i < 12; i++ {
+		transactions = append(transactions, &domaintransaction.Transaction{
+			ID:        fmt.Sprintf("tx-%02d", i),
+			Amount:    float64(1000 + (i%3)*500),
+			CreatedAt: base.Add(time.Duration(i/4) * time.Minute),
+			UpdatedAt: base.Add(time.Duration(i%2) * time.Hour),
+		})
+	}
+
+	tests := []struct {
+		orderBy string
+		order   string
+		limit   int
+	}{
+		{"", "", 5},
+		{"created_at", "asc", 3},
+		{"amount", "asc", 5},
+		{"amount", "desc", 4},
+		{"updated_at", "desc", 6},
+		{"amount", "asc", 12},
+		{"amount", "asc", 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s by %d", tt.orderBy, tt.order, tt.limit), func(t *testing.T) {
+			filters := service.TransactionFilters{OrderBy: tt.orderBy, Order: tt.order, Limit: tt.limit}
+			expected := page(t, transactions, service.TransactionFilters{OrderBy: tt.orderBy, Order: tt.order, Limit: len(transactions)})
+			sortKey, ascending, err := domaintransaction.ParseSort(tt.orderBy, tt.order)
+			if err != nil {
+				t.Fatalf("ParseSort() unexpected error: %v", err)
+			}
+
+			var listed []string
+			for pages := 0; ; pages++ {
+				if pages > len(transactions) {
+					t.Fatal("paging did not end")
+				}
+				transactionsOfPage := page(t, transactions, filters)
+				for _, transaction := range transactionsOfPage {
+					listed = append(listed, transaction.ID)
+				}
+				// As the list handler does, a full page gets the cursor of its last transaction
+				if len(transactionsOfPage) < tt.limit {
+					break
+				}
+				cursor, err := domaintransaction.DecodeCursor(domaintransaction.NewCursor(transactionsOfPage[len(transactionsOfPage)-1], sortKey, ascending).Encode())
+				if err != nil {
+					t.Fatalf("DecodeCursor() unexpected error: %v", err)
+				}
+				filters.Cursor = cursor
+			}
+
+			var expectedIDs []string
+			for _, transaction := range expected {
+				expectedIDs = append(expectedIDs, transaction.ID)
+			}
+			if strings.Join(listed, ",") != strings.Join(expectedIDs, ",") {
+				t.Errorf("paging listed %v, want %v", listed, expectedIDs)
+			}
+		})
+	}
+}
+
+func TestCursorPagingEmptyLastPage(t *testing.T) {
+	transactions := []*domaintransaction.Transaction{
+		{ID: "tx-1", Amount: 500},
+		{ID: "tx-2", Amount: 500},
+		{ID: "tx-3", Amount: 700},
+		{ID: "tx-4", Amount: 700},
+	}
+	filters := service.TransactionFilters{OrderBy: "amount", Limit: 2}
+
+	first := page(t, transactions, filters)
+	filters.Cursor = domaintransaction.NewCursor(first[1], domaintransaction.SortByAmount, true)
+	second := page(t, transactions, filters)
+	filters.Cursor = domaintransaction.NewCursor(second[1], domaintransaction.SortByAmount, true)
+
+	if last := page(t, transactions, filters); len(last) != 0 {
+		t.Errorf("page after the last transaction = %d transactions, want none", len(last))
+	}
+}
@@ -12,6 +12,15 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 )
 
+// sharedAccountIDsQuery selects the accounts shared with a user with visibility over all their transactions
+const sharedAccountIDsQuery = `SELECT account_id FROM account_members
+	WHERE user_id = ? AND status = 'active' AND role IN ('owner', 'co_owner', 'viewer')`
+
+// holderCardIDsQuery selects the cards a user holds in shared accounts as card holder. Card holders only see
+// the transactions of their own card.
+const holderCardIDsQuery = `SELECT card_id FROM account_members
+	WHERE user_id = ? AND status = 'active' AND role = 'card_holder' AND card_id IS NOT NULL`
+
 // TransactionRepository implements the TransactionRepositoryInterface for MySQL
 type TransactionRepository struct {
 	db *sql.DB
@@ -24,8 +33,24 @@ func NewTransactionRepository(db *sql.DB) service.TransactionRepositoryInterface
 	}
 }
 
+// sqlExecutor is satisfied by both *sql.DB and *sql.Tx
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
 // Create inserts a new transaction into the database
 func (r *TransactionRepository) Create(transaction *domaintransaction.Transaction) (*domaintransaction.Transaction, error) {
+	if err := r.insert(r.db, transaction); err != nil {
+		return nil, err
+	}
+
+	// Return the created transaction with timestamps
+	return r.GetByID(transaction.ID)
+}
+
+// insert writes a new transaction with the given executor. Transactions that already have a creation
+// date (e.g. imported from a bank statement) keep it; the rest are created now.
+func (r *TransactionRepository) insert(exec sqlExecutor, transaction *domaintransaction.Transaction) error {
 	// Generate ID if not set
 	if transaction.ID == "" {
 		transaction.ID = r.generateID()
@@ -42,68 +67,53 @@ func (r *TransactionRepository) Create(transaction *domaintransaction.Transactio
 		tagsJSON = []byte("[]")
 	}
 
+	var createdAt *time.Time
+	if !transaction.CreatedAt.IsZero() {
+		createdAt = &transaction.CreatedAt
+	}
+
 	query := `
 		INSERT INTO transactions (
 			id, reference_id, external_id, type, status, amount, currency,
 			from_account_id, to_account_id, from_card_id, to_card_id,
 			user_id, initiated_by, description, payment_method,
-			merchant_name, merchant_id, previous_balance, new_balance,
+			merchant_name, merchant_id, notes, category_id, category_source, previous_balance, new_balance,
 			processed_at, failed_at, failure_reason, metadata, tags,
 			created_at, updated_at
 		) VALUES (
 			?, ?, ?, ?, ?, ?, ?,
 			?, ?, ?, ?,
 			?, ?, ?, ?,
-			?, ?, ?, ?,
+			?, ?, ?, ?, ?, ?, ?,
 			?, ?, ?, ?, ?,
-			NOW(), NOW()
+			COALESCE(?, NOW()), NOW()
 		)`
 
-	_, err = r.db.Exec(query,
+	_, err = exec.Exec(query,
 		transaction.ID, transaction.ReferenceID, transaction.ExternalID,
 		transaction.Type, transaction.Status, transaction.Amount, transaction.Currency,
 		transaction.FromAccountID, transaction.ToAccountID, transaction.FromCardID, transaction.ToCardID,
 		transaction.UserID, transaction.InitiatedBy, transaction.Description, transaction.PaymentMethod,
-		transaction.MerchantName, transaction.MerchantID, transaction.PreviousBalance, transaction.NewBalance,
+		transaction.MerchantName, transaction.MerchantID, transaction.Notes, transaction.CategoryID, nullableCategorySource(transaction.CategorySource),
+		transaction.PreviousBalance, transaction.NewBalance,
 		transaction.ProcessedAt, transaction.FailedAt, transaction.FailureReason,
-		string(metadataJSON), string(tagsJSON),
+		string(metadataJSON), string(tagsJSON), createdAt,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %w", err)
+		return fmt.Errorf("failed to create transaction: %w", err)
 	}
 
-	// Return the created transaction with timestamps
-	return r.GetByID(transaction.ID)
+	return nil
 }
 
 // GetByID retrieves a transaction by its ID
 func (r *TransactionRepository) GetByID(id string) (*domaintransaction.Transaction, error) {
-	query := `
-		SELECT id, reference_id, external_id, type, status, amount, currency,
-			   from_account_id, to_account_id, from_card_id, to_card_id,
-			   user_id, initiated_by, description, payment_method,
-			   merchant_name, merchant_id, previous_balance, new_balance,
-			   processed_at, failed_at, failure_reason, metadata, tags,
-			   created_at, updated_at
+	query := `SELECT` + transactionColumns + `
 		FROM transactions
 		WHERE id = ?`
 
-	row := r.db.QueryRow(query, id)
-
-	transaction := &domaintransaction.Transaction{}
-	var metadataJSON, tagsJSON string
-
-	err := row.Scan(
-		&transaction.ID, &transaction.ReferenceID, &transaction.ExternalID,
-		&transaction.Type, &transaction.Status, &transaction.Amount, &transaction.Currency,
-		&transaction.FromAccountID, &transaction.ToAccountID, &transaction.FromCardID, &transaction.ToCardID,
-		&transaction.UserID, &transaction.InitiatedBy, &transaction.Description, &transaction.PaymentMethod,
-		&transaction.MerchantName, &transaction.MerchantID, &transaction.PreviousBalance, &transaction.NewBalance,
-		&transaction.ProcessedAt, &transaction.FailedAt, &transaction.FailureReason,
-		&metadataJSON, &tagsJSON, &transaction.CreatedAt, &transaction.UpdatedAt,
-	)
-
+	transaction, err := scanTransaction(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("transaction not found with ID: %s", id)
@@ -111,17 +121,22 @@ func (r *TransactionRepository) GetByID(id string) (*domaintransaction.Transacti
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
 
-	// Deserialize JSON fields
-	if metadataJSON != "" {
-		json.Unmarshal([]byte(metadataJSON), &transaction.Metadata)
-	}
-	if tagsJSON != "" {
-		json.Unmarshal([]byte(tagsJSON), &transaction.Tags)
-	}
-
 	return transaction, nil
 }
 
+// IsVisibleTo tells whether a user can see a transaction as a member of its account or card, with the
+// visibility rule of the account and card listings
+func (r *TransactionRepository) IsVisibleTo(id, userID string) (bool, error) {
+	visibility, args := memberVisibility(userID)
+	query := `SELECT EXISTS(SELECT 1 FROM transactions WHERE id = ? AND ` + visibility + `)`
+
+	var visible bool
+	if err := r.db.QueryRow(query, append([]interface{}{id}, args...)...).Scan(&visible); err != nil {
+		return false, fmt.Errorf("failed to check transaction visibility: %w", err)
+	}
+	return visible, nil
+}
+
 // Update updates an existing transaction
 func (r *TransactionRepository) Update(transaction *domaintransaction.Transaction) (*domaintransaction.Transaction, error) {
 	metadataJSON, _ := json.Marshal(transaction.Metadata)
@@ -132,7 +147,8 @@ func (r *TransactionRepository) Update(transaction *domaintransaction.Transactio
 			reference_id = ?, external_id = ?, type = ?, status = ?,
 			amount = ?, currency = ?, from_account_id = ?, to_account_id = ?,
 			from_card_id = ?, to_card_id = ?, description = ?, payment_method = ?,
-			merchant_name = ?, merchant_id = ?, previous_balance = ?, new_balance = ?,
+			merchant_name = ?, merchant_id = ?, notes = ?, category_id = ?, category_source = ?,
+			previous_balance = ?, new_balance = ?,
 			processed_at = ?, failed_at = ?, failure_reason = ?,
 			metadata = ?, tags = ?, updated_at = NOW()
 		WHERE id = ?`
@@ -141,7 +157,8 @@ func (r *TransactionRepository) Update(transaction *domaintransaction.Transactio
 		transaction.ReferenceID, transaction.ExternalID, transaction.Type, transaction.Status,
 		transaction.Amount, transaction.Currency, transaction.FromAccountID, transaction.ToAccountID,
 		transaction.FromCardID, transaction.ToCardID, transaction.Description, transaction.PaymentMethod,
-		transaction.MerchantName, transaction.MerchantID, transaction.PreviousBalance, transaction.NewBalance,
+		transaction.MerchantName, transaction.MerchantID, transaction.Notes, transaction.CategoryID, nullableCategorySource(transaction.CategorySource),
+		transaction.PreviousBalance, transaction.NewBalance,
 		transaction.ProcessedAt, transaction.FailedAt, transaction.FailureReason,
 		string(metadataJSON), string(tagsJSON), transaction.ID,
 	)
@@ -173,188 +190,79 @@ func (r *TransactionRepository) Delete(id string) error {
 	return nil
 }
 
-// GetByUserID retrieves transactions for a user with filtering
+// GetByUserID retrieves transactions for a user with filtering. Besides their own transactions it
+// includes the ones they performed as a member of a shared account and, for members with visibility
+// over the whole account, every transaction of the shared accounts.
 func (r *TransactionRepository) GetByUserID(userID string, filters service.TransactionFilters) ([]*domaintransaction.Transaction, int, error) {
-	// Build WHERE clause
-	whereConditions := []string{"user_id = ?"}
-	args := []interface{}{userID}
-
-	// Add filters
-	if len(filters.Types) > 0 {
-		placeholders := make([]string, len(filters.Types))
-		for i, transactionType := range filters.Types {
-			placeholders[i] = "?"
-			args = append(args, transactionType)
-		}
-		whereConditions = append(whereConditions, fmt.Sprintf("type IN (%s)", strings.Join(placeholders, ",")))
-	}
-
-	if len(filters.Statuses) > 0 {
-		placeholders := make([]string, len(filters.Statuses))
-		for i, status := range filters.Statuses {
-			placeholders[i] = "?"
-			args = append(args, status)
-		}
-		whereConditions = append(whereConditions, fmt.Sprintf("status IN (%s)", strings.Join(placeholders, ",")))
-	}
-
-	if filters.FromDate != nil {
-		whereConditions = append(whereConditions, "created_at >= ?")
-		args = append(args, *filters.FromDate)
-	}
-
-	if filters.ToDate != nil {
-		whereConditions = append(whereConditions, "created_at <= ?")
-		args = append(args, *filters.ToDate)
-	}
-
-	if filters.MinAmount != nil {
-		whereConditions = append(whereConditions, "amount >= ?")
-		args = append(args, *filters.MinAmount)
-	}
-
-	if filters.MaxAmount != nil {
-		whereConditions = append(whereConditions, "amount <= ?")
-		args = append(args, *filters.MaxAmount)
-	}
-
-	whereClause := strings.Join(whereConditions, " AND ")
-
-	// Count total records
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM transactions WHERE %s", whereClause)
-	var total int
-	err := r.db.QueryRow(countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
-	}
+	return r.executeFilteredQuery(userScope(userID), filters)
+}
 
-	// Build ORDER BY clause
-	orderBy := "created_at DESC"
-	if filters.OrderBy != "" {
-		direction := "ASC"
-		if filters.Order == "desc" {
-			direction = "DESC"
-		}
-		orderBy = fmt.Sprintf("%s %s", filters.OrderBy, direction)
-	}
+// StreamByUserID calls fn for every transaction GetByUserID would return with the same filters, ignoring
+// the pagination. Rows are read one at a time, so the result is never held in memory.
+func (r *TransactionRepository) StreamByUserID(userID string, filters service.TransactionFilters, fn func(*domaintransaction.Transaction) error) error {
+	whereClause, args := userScope(userID).where(filters)
 
-	// Build main query
-	query := fmt.Sprintf(`
-		SELECT id, reference_id, external_id, type, status, amount, currency,
-			   from_account_id, to_account_id, from_card_id, to_card_id,
-			   user_id, initiated_by, description, payment_method,
-			   merchant_name, merchant_id, previous_balance, new_balance,
-			   processed_at, failed_at, failure_reason, metadata, tags,
-			   created_at, updated_at
+	query := fmt.Sprintf(`SELECT`+transactionColumns+`
 		FROM transactions
 		WHERE %s
-		ORDER BY %s
-		LIMIT ? OFFSET ?`, whereClause, orderBy)
-
-	// Add pagination
-	limit := filters.Limit
-	if limit <= 0 {
-		limit = 20 // Default limit
-	}
-	offset := filters.Offset
-	if offset < 0 {
-		offset = 0
-	}
-
-	args = append(args, limit, offset)
+		ORDER BY %s`, whereClause, transactionsOrderBy(filters))
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query transactions: %w", err)
+		return fmt.Errorf("failed to query transactions: %w", err)
 	}
 	defer rows.Close()
 
-	var transactions []*domaintransaction.Transaction
 	for rows.Next() {
-		transaction := &domaintransaction.Transaction{}
-		var metadataJSON, tagsJSON string
-
-		err := rows.Scan(
-			&transaction.ID, &transaction.ReferenceID, &transaction.ExternalID,
-			&transaction.Type, &transaction.Status, &transaction.Amount, &transaction.Currency,
-			&transaction.FromAccountID, &transaction.ToAccountID, &transaction.FromCardID, &transaction.ToCardID,
-			&transaction.UserID, &transaction.InitiatedBy, &transaction.Description, &transaction.PaymentMethod,
-			&transaction.MerchantName, &transaction.MerchantID, &transaction.PreviousBalance, &transaction.NewBalance,
-			&transaction.ProcessedAt, &transaction.FailedAt, &transaction.FailureReason,
-			&metadataJSON, &tagsJSON, &transaction.CreatedAt, &transaction.UpdatedAt,
-		)
-
+		transaction, err := scanTransaction(rows)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan transaction: %w", err)
-		}
-
-		// Deserialize JSON fields
-		if metadataJSON != "" {
-			json.Unmarshal([]byte(metadataJSON), &transaction.Metadata)
+			return fmt.Errorf("failed to scan transaction: %w", err)
 		}
-		if tagsJSON != "" {
-			json.Unmarshal([]byte(tagsJSON), &transaction.Tags)
+		if err := fn(transaction); err != nil {
+			return err
 		}
-
-		transactions = append(transactions, transaction)
 	}
 
-	return transactions, total, nil
-}
-
-// GetByAccountID retrieves transactions for an account
-func (r *TransactionRepository) GetByAccountID(accountID string, filters service.TransactionFilters) ([]*domaintransaction.Transaction, int, error) {
-	// Similar to GetByUserID but filter by account
-	whereConditions := []string{"(from_account_id = ? OR to_account_id = ?)"}
-	args := []interface{}{accountID, accountID}
-
-	// Add other filters (similar to GetByUserID implementation)
-	// ... rest of implementation similar to GetByUserID
-
-	return r.executeFilteredQuery(whereConditions, args, filters)
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read transactions: %w", err)
+	}
+	return nil
 }
 
-// GetByCardID retrieves transactions for a card
-func (r *TransactionRepository) GetByCardID(cardID string, filters service.TransactionFilters) ([]*domaintransaction.Transaction, int, error) {
-	whereConditions := []string{"(from_card_id = ? OR to_card_id = ?)"}
-	args := []interface{}{cardID, cardID}
-
-	return r.executeFilteredQuery(whereConditions, args, filters)
+// transactionColumns are the columns read by scanTransaction
+const transactionColumns = `
+	id, reference_id, external_id, type, status, amount, currency,
+	from_account_id, to_account_id, from_card_id, to_card_id,
+	user_id, initiated_by, description, payment_method,
+	merchant_name, merchant_id, notes, category_id, category_source, previous_balance, new_balance,
+	processed_at, failed_at, failure_reason, metadata, tags,
+	created_at, updated_at`
+
+// transactionScanner is satisfied by both *sql.Row and *sql.Rows
+type transactionScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-// GetByReferenceID retrieves a transaction by reference ID
-func (r *TransactionRepository) GetByReferenceID(referenceID string) (*domaintransaction.Transaction, error) {
-	query := `
-		SELECT id, reference_id, external_id, type, status, amount, currency,
-			   from_account_id, to_account_id, from_card_id, to_card_id,
-			   user_id, initiated_by, description, payment_method,
-			   merchant_name, merchant_id, previous_balance, new_balance,
-			   processed_at, failed_at, failure_reason, metadata, tags,
-			   created_at, updated_at
-		FROM transactions
-		WHERE reference_id = ?`
-
-	row := r.db.QueryRow(query, referenceID)
-
+// scanTransaction reads a row selected with transactionColumns
+func scanTransaction(scanner transactionScanner) (*domaintransaction.Transaction, error) {
 	transaction := &domaintransaction.Transaction{}
 	var metadataJSON, tagsJSON string
+	var notes, categorySource sql.NullString
 
-	err := row.Scan(
+	err := scanner.Scan(
 		&transaction.ID, &transaction.ReferenceID, &transaction.ExternalID,
 		&transaction.Type, &transaction.Status, &transaction.Amount, &transaction.Currency,
 		&transaction.FromAccountID, &transaction.ToAccountID, &transaction.FromCardID, &transaction.ToCardID,
 		&transaction.UserID, &transaction.InitiatedBy, &transaction.Description, &transaction.PaymentMethod,
-		&transaction.MerchantName, &transaction.MerchantID, &transaction.PreviousBalance, &transaction.NewBalance,
+		&transaction.MerchantName, &transaction.MerchantID, &notes, &transaction.CategoryID, &categorySource,
+		&transaction.PreviousBalance, &transaction.NewBalance,
 		&transaction.ProcessedAt, &transaction.FailedAt, &transaction.FailureReason,
 		&metadataJSON, &tagsJSON, &transaction.CreatedAt, &transaction.UpdatedAt,
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("transaction not found with reference ID: %s", referenceID)
-		}
-		return nil, fmt.Errorf("failed to get transaction: %w", err)
+		return nil, err
 	}
+	transaction.Notes = notes.String
 
 	// Deserialize JSON fields
 	if metadataJSON != "" {
@@ -363,37 +271,45 @@ func (r *TransactionRepository) GetByReferenceID(referenceID string) (*domaintra
 	if tagsJSON != "" {
 		json.Unmarshal([]byte(tagsJSON), &transaction.Tags)
 	}
+	transaction.CategorySource = domaintransaction.CategorySource(categorySource.String)
 
 	return transaction, nil
 }
 
-// GetByExternalID retrieves a transaction by external ID
-func (r *TransactionRepository) GetByExternalID(externalID string) (*domaintransaction.Transaction, error) {
-	query := `
-		SELECT id, reference_id, external_id, type, status, amount, currency,
-			   from_account_id, to_account_id, from_card_id, to_card_id,
-			   user_id, initiated_by, description, payment_method,
-			   merchant_name, merchant_id, previous_balance, new_balance,
-			   processed_at, failed_at, failure_reason, metadata, tags,
-			   created_at, updated_at
+// GetByAccountID retrieves the transactions of an account that a user can see
+func (r *TransactionRepository) GetByAccountID(accountID, userID string, filters service.TransactionFilters) ([]*domaintransaction.Transaction, int, error) {
+	return r.executeFilteredQuery(accountScope(accountID, userID), filters)
+}
+
+// GetByCardID retrieves the transactions of a card that a user can see
+func (r *TransactionRepository) GetByCardID(cardID, userID string, filters service.TransactionFilters) ([]*domaintransaction.Transaction, int, error) {
+	return r.executeFilteredQuery(cardScope(cardID, userID), filters)
+}
+
+// GetByReferenceID retrieves a transaction by reference ID
+func (r *TransactionRepository) GetByReferenceID(referenceID string) (*domaintransaction.Transaction, error) {
+	query := `SELECT` + transactionColumns + `
 		FROM transactions
-		WHERE external_id = ?`
+		WHERE reference_id = ?`
 
-	row := r.db.QueryRow(query, externalID)
+	transaction, err := scanTransaction(r.db.QueryRow(query, referenceID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("transaction not found with reference ID: %s", referenceID)
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
 
-	transaction := &domaintransaction.Transaction{}
-	var metadataJSON, tagsJSON string
+	return transaction, nil
+}
 
-	err := row.Scan(
-		&transaction.ID, &transaction.ReferenceID, &transaction.ExternalID,
-		&transaction.Type, &transaction.Status, &transaction.Amount, &transaction.Currency,
-		&transaction.FromAccountID, &transaction.ToAccountID, &transaction.FromCardID, &transaction.ToCardID,
-		&transaction.UserID, &transaction.InitiatedBy, &transaction.Description, &transaction.PaymentMethod,
-		&transaction.MerchantName, &transaction.MerchantID, &transaction.PreviousBalance, &transaction.NewBalance,
-		&transaction.ProcessedAt, &transaction.FailedAt, &transaction.FailureReason,
-		&metadataJSON, &tagsJSON, &transaction.CreatedAt, &transaction.UpdatedAt,
-	)
+// GetByExternalID retrieves a transaction by external ID
+func (r *TransactionRepository) GetByExternalID(externalID string) (*domaintransaction.Transaction, error) {
+	query := `SELECT` + transactionColumns + `
+		FROM transactions
+		WHERE external_id = ?`
 
+	transaction, err := scanTransaction(r.db.QueryRow(query, externalID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("transaction not found with external ID: %s", externalID)
@@ -401,18 +317,10 @@ func (r *TransactionRepository) GetByExternalID(externalID string) (*domaintrans
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
 
-	// Deserialize JSON fields
-	if metadataJSON != "" {
-		json.Unmarshal([]byte(metadataJSON), &transaction.Metadata)
-	}
-	if tagsJSON != "" {
-		json.Unmarshal([]byte(tagsJSON), &transaction.Tags)
-	}
-
 	return transaction, nil
 }
 
-// CreateBatch creates multiple transactions in a batch
+// CreateBatch creates multiple transactions atomically: either all of them are created or none
 func (r *TransactionRepository) CreateBatch(transactions []*domaintransaction.Transaction) ([]*domaintransaction.Transaction, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -420,20 +328,29 @@ func (r *TransactionRepository) CreateBatch(transactions []*domaintransaction.Tr
 	}
 	defer tx.Rollback()
 
-	var createdTransactions []*domaintransaction.Transaction
-
-	for _, transaction := range transactions {
-		created, err := r.Create(transaction)
-		if err != nil {
+	for i, transaction := range transactions {
+		// IDs are generated here so they stay unique within the batch
+		if transaction.ID == "" {
+			transaction.ID = fmt.Sprintf("txn_%d_%d", time.Now().UnixNano(), i)
+		}
+		if err := r.insert(tx, transaction); err != nil {
 			return nil, fmt.Errorf("failed to create transaction in batch: %w", err)
 		}
-		createdTransactions = append(createdTransactions, created)
 	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit batch transaction: %w", err)
 	}
 
+	var createdTransactions []*domaintransaction.Transaction
+	for _, transaction := range transactions {
+		created, err := r.GetByID(transaction.ID)
+		if err != nil {
+			return nil, err
+		}
+		createdTransactions = append(createdTransactions, created)
+	}
+
 	return createdTransactions, nil
 }
 
@@ -555,14 +472,152 @@ func (r *TransactionRepository) GetMonthlyTransactionVolume(userID string, year
 	return volume, nil
 }
 
+// GetForCategorization retrieves a page of the transactions owned by a user, oldest first, to apply
+// categorization rules over them. Manually categorized transactions are skipped unless includeManual is set.
+func (r *TransactionRepository) GetForCategorization(userID string, fromDate, toDate *time.Time, includeManual bool, limit, offset int) ([]*domaintransaction.Transaction, error) {
+	whereConditions := []string{"user_id = ?"}
+	args := []interface{}{userID}
+
+	if fromDate != nil {
+		whereConditions = append(whereConditions, "created_at >= ?")
+		args = append(args, *fromDate)
+	}
+	if toDate != nil {
+		whereConditions = append(whereConditions, "created_at <= ?")
+		args = append(args, *toDate)
+	}
+	if !includeManual {
+		whereConditions = append(whereConditions, "(category_source IS NULL OR category_source <> ?)")
+		args = append(args, domaintransaction.CategorySourceManual)
+	}
+
+	query := fmt.Sprintf(`SELECT`+transactionColumns+`
+		FROM transactions
+		WHERE %s
+		ORDER BY created_at ASC, id ASC
+		LIMIT ? OFFSET ?`, strings.Join(whereConditions, " AND "))
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions for categorization: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*domaintransaction.Transaction
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+// UpdateCategory sets the category of a transaction without touching the rest of its fields
+func (r *TransactionRepository) UpdateCategory(id string, categoryID *string, source domaintransaction.CategorySource) error {
+	query := "UPDATE transactions SET category_id = ?, category_source = ?, updated_at = NOW() WHERE id = ?"
+	result, err := r.db.Exec(query, categoryID, nullableCategorySource(source), id)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction category: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("transaction not found with ID: %s", id)
+	}
+
+	return nil
+}
+
+// ReassignCategory moves the transactions of a user from one category to another (or leaves them
+// uncategorized when toCategoryID is nil)
+func (r *TransactionRepository) ReassignCategory(userID, fromCategoryID string, toCategoryID *string) error {
+	query := "UPDATE transactions SET category_id = ?, category_source = IF(? IS NULL, NULL, category_source), updated_at = NOW() WHERE user_id = ? AND category_id = ?"
+	if _, err := r.db.Exec(query, toCategoryID, toCategoryID, userID, fromCategoryID); err != nil {
+		return fmt.Errorf("failed to reassign transactions category: %w", err)
+	}
+
+	// Allocations of split transactions follow the same category
+	allocationsQuery := `UPDATE transaction_allocations a JOIN transactions t ON t.id = a.transaction_id
+		SET a.category_id = ? WHERE t.user_id = ? AND a.category_id = ?`
+	if _, err := r.db.Exec(allocationsQuery, toCategoryID, userID, fromCategoryID); err != nil {
+		return fmt.Errorf("failed to reassign allocations category: %w", err)
+	}
+	return nil
+}
+
 // Helper methods
 
+// nullableCategorySource stores uncategorized transactions with a NULL source
+func nullableCategorySource(source domaintransaction.CategorySource) interface{} {
+	if source == "" {
+		return nil
+	}
+	return string(source)
+}
+
 func (r *TransactionRepository) generateID() string {
 	return fmt.Sprintf("txn_%d", time.Now().UnixNano())
 }
 
-func (r *TransactionRepository) executeFilteredQuery(whereConditions []string, args []interface{}, filters service.TransactionFilters) ([]*domaintransaction.Transaction, int, error) {
-	// Implementation for common filtered query logic
-	// This would contain the common logic for filtering across different methods
-	return nil, 0, fmt.Errorf("not implemented yet")
+// executeFilteredQuery returns a page of the transactions of a scope matching the filters, and how many
+// match in total. Pages start after the cursor when there is one, otherwise at the offset.
+func (r *TransactionRepository) executeFilteredQuery(scope transactionScope, filters service.TransactionFilters) ([]*domaintransaction.Transaction, int, error) {
+	whereClause, args := scope.where(filters)
+
+	// Count total records
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM transactions WHERE %s", whereClause)
+	var total int
+	err := r.db.QueryRow(countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
+	}
+
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = 20 // Default limit
+	}
+	offset := filters.Offset
+	if offset < 0 || filters.Cursor != nil {
+		offset = 0
+	}
+
+	if filters.Cursor != nil {
+		condition, cursorArgs, err := cursorCondition(filters)
+		if err != nil {
+			return nil, 0, err
+		}
+		whereClause += " AND " + condition
+		args = append(args, cursorArgs...)
+	}
+
+	query := fmt.Sprintf(`SELECT`+transactionColumns+`
+		FROM transactions
+		WHERE %s
+		ORDER BY %s
+		LIMIT ? OFFSET ?`, whereClause, transactionsOrderBy(filters))
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*domaintransaction.Transaction
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, total, rows.Err()
 }
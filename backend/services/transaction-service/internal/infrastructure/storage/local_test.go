@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorageRejectsKeysOutsideTheRoot(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "attachments")
+	storage, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage() unexpected error: %v", err)
+	}
+
+	keys := []string{
+		"",
+		"../outside.pdf",
+		"attachments/../../outside.pdf",
+		"attachments/user-1/../../../outside.pdf",
+		"/etc/passwd",
+		filepath.Join(base, "outside.pdf"),
+		`attachments\..\..\outside.pdf`,
+		"..",
+	}
+
+	for _, key := range keys {
+		t.Run(key, func(t *testing.T) {
+			if err := storage.Put(key, strings.NewReader("contenido"), 9, "application/pdf"); err == nil || !strings.Contains(err.Error(), "invalid storage key") {
+				t.Errorf("Put(%q) error = %v, want invalid storage key", key, err)
+			}
+			if _, err := storage.Get(key); err == nil || !strings.Contains(err.Error(), "invalid storage key") {
+				t.Errorf("Get(%q) error = %v, want invalid storage key", key, err)
+			}
+			if err := storage.Delete(key); err == nil || !strings.Contains(err.Error(), "invalid storage key") {
+				t.Errorf("Delete(%q) error = %v, want invalid storage key", key, err)
+			}
+		})
+	}
+
+	// Nothing was written next to the root
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		t.Fatalf("failed to read directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("storage wrote %d entries outside its root", len(entries)-1)
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+	storage, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage() unexpected error: %v", err)
+	}
+	key := "attachments/user-1/att_1.pdf"
+
+	if err := storage.Put(key, strings.NewReader("%PDF-1.4"), 8, "application/pdf"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "attachments", "user-1", "att_1.pdf")); err != nil {
+		t.Errorf("Put() did not store the file under the root: %v", err)
+	}
+
+	file, err := storage.Get(key)
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	content, err := io.ReadAll(file)
+	file.Close()
+	if err != nil || string(content) != "%PDF-1.4" {
+		t.Errorf("Get() = %q, %v, want %q", content, err, "%PDF-1.4")
+	}
+
+	if err := storage.Delete(key); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if _, err := storage.Get(key); err == nil {
+		t.Error("Get() after Delete() expected error but got none")
+	}
+	if err := storage.Delete(key); err != nil {
+		t.Errorf("Delete() of a missing key unexpected error: %v", err)
+	}
+}
+
+func TestLocalStoragePutSizeMismatch(t *testing.T) {
+	root := t.TempDir()
+	storage, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage() unexpected error: %v", err)
+	}
+
+	err = storage.Put("attachments/user-1/att_1.pdf", strings.NewReader("%PDF"), 8, "application/pdf")
+	if err == nil || !strings.Contains(err.Error(), "size mismatch") {
+		t.Fatalf("Put() error = %v, want size mismatch", err)
+	}
+
+	// Neither the file nor its temporary copy is left behind
+	entries, err := os.ReadDir(filepath.Join(root, "attachments", "user-1"))
+	if err != nil {
+		t.Fatalf("failed to read directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Put() left %d files after failing", len(entries))
+	}
+}
@@ -13,7 +13,30 @@ INSERT IGNORE INTO migration_history (migration_file) VALUES
 ('06_V6__transactions.sql'),
 ('07_V7__installments.sql'),
 ('08_V8__notifications.sql'),
-('09_V9__conversation_history.sql');
+('09_V9__conversation_history.sql'),
+('10_V10__add_installment_transaction_types.sql'),
+('11_V11__account_members.sql'),
+('12_V12__credit_lines.sql'),
+('13_V13__card_controls.sql'),
+('14_V14__card_renewals.sql'),
+('15_V15__card_number_access_logs.sql'),
+('16_V16__account_closures.sql'),
+('17_V17__credit_limit_changes.sql'),
+('18_V18__transaction_categories.sql'),
+('19_V19__transaction_allocations.sql'),
+('20_V20__scheduled_transactions.sql'),
+('21_V21__detected_subscriptions.sql'),
+('22_V22__statement_imports.sql'),
+('23_V23__reconciliations.sql'),
+('24_V24__transaction_search.sql'),
+('25_V25__transaction_attachments.sql'),
+('26_V26__fraud_scoring.sql'),
+('27_V27__p2p_transfers.sql'),
+('28_V28__shared_expense_groups.sql'),
+('29_V29__budgets.sql'),
+('30_V30__debt_payoff_plans.sql'),
+('31_V31__inflation_rates.sql'),
+('32_V32__card_expiry_notices.sql');
 
 -- Show migration summary
 SELECT 
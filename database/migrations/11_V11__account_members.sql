@@ -0,0 +1,55 @@
+-- =====================================================
+-- FinTrack Account Service - Database Migration
+-- Version: V11__account_members.sql
+-- Description: Create account_members table for shared accounts with member roles
+-- =====================================================
+
+-- Create account_members table
+CREATE TABLE IF NOT EXISTS account_members (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    account_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+
+    -- Access level
+    role VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+
+    -- Card holders are restricted to a single card of the account
+    card_id VARCHAR(36) NULL,
+
+    -- Invitation tracking
+    invited_by VARCHAR(36) NOT NULL,
+    invited_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    accepted_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Constraints
+    CONSTRAINT chk_account_member_role_valid CHECK (role IN ('owner', 'co_owner', 'viewer', 'card_holder')),
+    CONSTRAINT chk_account_member_status_valid CHECK (status IN ('pending', 'active', 'declined', 'revoked')),
+    CONSTRAINT chk_account_member_card_holder CHECK (
+        (role = 'card_holder' AND card_id IS NOT NULL) OR (role <> 'card_holder' AND card_id IS NULL)
+    ),
+
+    -- Foreign key constraints (will be enabled when services are fully integrated)
+    -- FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    -- FOREIGN KEY (card_id) REFERENCES cards(id) ON DELETE CASCADE,
+    -- FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+
+    -- Indexes for performance optimization
+    INDEX idx_account_members_account_id (account_id),
+    INDEX idx_account_members_user_id (user_id),
+    INDEX idx_account_members_card_id (card_id),
+    INDEX idx_account_members_status (status),
+    INDEX idx_account_members_user_status_role (user_id, status, role)
+);
+
+ALTER TABLE account_members COMMENT = 'Users with access to accounts owned by other users (shared accounts)';
+
+-- Transactions already store the acting user in initiated_by; index it so members can query
+-- the operations they performed on shared accounts
+CREATE INDEX idx_transactions_initiated_by ON transactions(initiated_by);
@@ -0,0 +1,44 @@
+-- =====================================================
+-- FinTrack Account Service - Database Migration
+-- Version: V12__credit_lines.sql
+-- Description: Create credit_lines table so several credit cards (primary and additional
+--              cards) can share one account-level credit limit with optional per-card sub-limits
+-- =====================================================
+
+-- Create credit_lines table
+CREATE TABLE IF NOT EXISTS credit_lines (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    account_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+
+    -- Limit shared by every card of the line
+    total_limit DECIMAL(15,2) NOT NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+
+    -- Constraints
+    CONSTRAINT chk_credit_line_total_limit_positive CHECK (total_limit > 0),
+
+    -- Foreign key constraints (will be enabled when services are fully integrated)
+    -- FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+
+    -- Indexes for performance optimization
+    INDEX idx_credit_lines_account_id (account_id),
+    INDEX idx_credit_lines_deleted_at (deleted_at)
+);
+
+ALTER TABLE credit_lines COMMENT = 'Credit limits shared by several credit cards of the same account';
+
+-- Link cards to their credit line
+ALTER TABLE cards
+ADD COLUMN credit_line_id VARCHAR(36) NULL COMMENT 'Shared credit line; when set the card draws from the line limit instead of credit_limit',
+ADD COLUMN is_additional BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Additional card (extension) issued under the line for another holder',
+ADD COLUMN sub_limit DECIMAL(15,2) NULL COMMENT 'Optional cap for the card within the line limit';
+
+CREATE INDEX idx_cards_credit_line_id ON cards(credit_line_id);
+
+ALTER TABLE cards ADD CONSTRAINT chk_cards_sub_limit_positive CHECK (sub_limit IS NULL OR sub_limit > 0);
@@ -0,0 +1,72 @@
+-- =====================================================
+-- FinTrack Account Service - Database Migration
+-- Version: V13__card_controls.sql
+-- Description: Create card_controls and card_control_rejections tables for per-card
+--              spending controls (merchant/category blocks, online toggle, per-transaction
+--              cap, allowed currencies, temporary freeze) and the log of rejected operations
+-- =====================================================
+
+-- Create card_controls table (one row per card, missing row = no restrictions)
+CREATE TABLE IF NOT EXISTS card_controls (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    card_id VARCHAR(36) NOT NULL,
+
+    -- Blocks (JSON arrays of strings)
+    blocked_merchants JSON NULL,
+    blocked_categories JSON NULL,
+
+    -- Channel and amount restrictions
+    online_purchases_blocked BOOLEAN NOT NULL DEFAULT FALSE,
+    max_transaction_amount DECIMAL(15,2) NULL,
+
+    -- Allowed currencies (JSON array of ISO codes, empty = any)
+    allowed_currencies JSON NULL,
+
+    -- Temporary freeze, lifted automatically once frozen_until passes
+    frozen_until TIMESTAMP NULL,
+    freeze_reason VARCHAR(255) NULL,
+
+    -- Audit fields
+    updated_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Constraints
+    CONSTRAINT uk_card_controls_card_id UNIQUE (card_id),
+    CONSTRAINT chk_card_controls_max_amount_positive CHECK (max_transaction_amount IS NULL OR max_transaction_amount > 0)
+
+    -- Foreign key constraints (will be enabled when services are fully integrated)
+    -- FOREIGN KEY (card_id) REFERENCES cards(id) ON DELETE CASCADE
+);
+
+ALTER TABLE card_controls COMMENT = 'Fine-grained spending controls of a card';
+
+-- Create card_control_rejections table
+CREATE TABLE IF NOT EXISTS card_control_rejections (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    card_id VARCHAR(36) NOT NULL,
+
+    -- Rejection details
+    reason VARCHAR(50) NOT NULL COMMENT 'card_frozen, merchant_blocked, category_blocked, online_purchases_disabled, transaction_limit_exceeded, currency_not_allowed',
+    message VARCHAR(255) NOT NULL,
+
+    -- Rejected operation
+    amount DECIMAL(15,2) NOT NULL,
+    currency VARCHAR(3) NULL,
+    merchant_name VARCHAR(255) NULL,
+    category VARCHAR(100) NULL,
+    is_online BOOLEAN NOT NULL DEFAULT FALSE,
+    attempted_by VARCHAR(36) NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_card_control_rejections_card_id (card_id),
+    INDEX idx_card_control_rejections_reason (reason),
+    INDEX idx_card_control_rejections_created_at (created_at)
+);
+
+ALTER TABLE card_control_rejections COMMENT = 'Card operations rejected by the spending controls';
@@ -0,0 +1,14 @@
+-- =====================================================
+-- FinTrack Account Service - Database Migration
+-- Version: V14__card_renewals.sql
+-- Description: Track card renewals so a replacement card points to the card it replaced.
+--              Cards are expired by the notification-service card expiry job.
+-- =====================================================
+
+ALTER TABLE cards
+ADD COLUMN replaces_card_id VARCHAR(36) NULL COMMENT 'Card renewed by this one; it inherited its balance, controls, card holders and active installment plans';
+
+CREATE INDEX idx_cards_replaces_card_id ON cards(replaces_card_id);
+
+-- Speeds up the daily expiry job
+CREATE INDEX idx_cards_expiration ON cards(status, expiration_year, expiration_month);
@@ -0,0 +1,34 @@
+-- =====================================================
+-- FinTrack Account Service - Database Migration
+-- Version: V15__card_number_access_logs.sql
+-- Description: Audit log of the administrative access to card numbers. Card numbers are
+--              sealed with per-record data keys wrapped by the master keyring, and
+--              cards.key_fingerprint records the master key that wraps each one.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS card_number_access_logs (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    card_id VARCHAR(36) NOT NULL,
+
+    -- Access details
+    accessed_by VARCHAR(36) NOT NULL COMMENT 'Administrator that requested the card number',
+    reason VARCHAR(255) NOT NULL,
+    granted BOOLEAN NOT NULL DEFAULT FALSE,
+    failure_reason VARCHAR(255) NULL,
+    key_fingerprint VARCHAR(64) NULL COMMENT 'Master key that wrapped the card number at the time',
+    client_ip VARCHAR(45) NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_card_number_access_logs_card_id (card_id),
+    INDEX idx_card_number_access_logs_accessed_by (accessed_by),
+    INDEX idx_card_number_access_logs_created_at (created_at)
+);
+
+ALTER TABLE card_number_access_logs COMMENT = 'Audited decryption of card numbers by administrators';
+
+-- Speeds up the key rotation, which scans the cards not wrapped by the active key
+CREATE INDEX idx_cards_key_fingerprint ON cards(key_fingerprint);
@@ -0,0 +1,61 @@
+-- =====================================================
+-- FinTrack Account Service - Database Migration
+-- Version: V16__account_closures.sql
+-- Description: Account closure process. Accounts are closed after their final settlement
+--              (balance transferred, cards settled, installment plans cancelled or
+--              transferred) instead of being soft deleted, and every step is audited.
+-- =====================================================
+
+ALTER TABLE accounts
+ADD COLUMN closed_at TIMESTAMP NULL COMMENT 'Set when the closure process completes; closed accounts cannot be reactivated';
+
+CREATE TABLE IF NOT EXISTS account_closures (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    account_id VARCHAR(36) NOT NULL,
+
+    -- Closure details
+    requested_by VARCHAR(36) NOT NULL,
+    reason VARCHAR(255) NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending, completed or cancelled',
+    statement JSON NULL COMMENT 'Final closing statement, generated when the closure completes',
+
+    -- Audit fields
+    completed_at TIMESTAMP NULL,
+    cancelled_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_account_closures_account_id (account_id),
+    INDEX idx_account_closures_status (status),
+
+    CONSTRAINT fk_account_closures_account FOREIGN KEY (account_id) REFERENCES accounts(id)
+);
+
+ALTER TABLE account_closures COMMENT = 'Closure processes of accounts with their closing statement';
+
+CREATE TABLE IF NOT EXISTS account_closure_events (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    closure_id VARCHAR(36) NOT NULL,
+    account_id VARCHAR(36) NOT NULL,
+
+    -- Step details
+    step VARCHAR(30) NOT NULL COMMENT 'started, balance_transferred, card_settled, plan_cancelled, plan_transferred, completed or cancelled',
+    reference_id VARCHAR(36) NULL COMMENT 'Account, card or installment plan involved',
+    amount DECIMAL(15,2) NULL,
+    details TEXT NULL,
+    performed_by VARCHAR(36) NOT NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_account_closure_events_closure_id (closure_id),
+    INDEX idx_account_closure_events_account_id (account_id),
+
+    CONSTRAINT fk_account_closure_events_closure FOREIGN KEY (closure_id) REFERENCES account_closures(id) ON DELETE CASCADE
+);
+
+ALTER TABLE account_closure_events COMMENT = 'Audit trail of every step of an account closure';
@@ -0,0 +1,70 @@
+-- =====================================================
+-- FinTrack Account Service - Database Migration
+-- Version: V17__credit_limit_changes.sql
+-- Description: Credit limit change requests for credit card accounts and credit cards.
+--              Increases above the approval threshold wait for a treasurer, decreases
+--              below the debt plus installment commitments are rejected, and every limit
+--              that applied is kept with its effective period.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS credit_limit_change_requests (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    account_id VARCHAR(36) NOT NULL,
+    card_id VARCHAR(36) NULL COMMENT 'Set when the limit of a credit card changes',
+    target_type VARCHAR(10) NOT NULL COMMENT 'account or card',
+
+    -- Change details
+    previous_limit DECIMAL(15,2) NOT NULL,
+    requested_limit DECIMAL(15,2) NOT NULL,
+    reason VARCHAR(255) NULL,
+    requested_by VARCHAR(36) NOT NULL,
+
+    -- Review
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending, applied or rejected',
+    requires_approval BOOLEAN NOT NULL DEFAULT FALSE,
+    reviewed_by VARCHAR(36) NULL,
+    review_note VARCHAR(255) NULL,
+    reviewed_at TIMESTAMP NULL,
+    applied_at TIMESTAMP NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_credit_limit_change_requests_account_id (account_id),
+    INDEX idx_credit_limit_change_requests_card_id (card_id),
+    INDEX idx_credit_limit_change_requests_status (status),
+
+    CONSTRAINT fk_credit_limit_change_requests_account FOREIGN KEY (account_id) REFERENCES accounts(id)
+);
+
+ALTER TABLE credit_limit_change_requests COMMENT = 'Requests to increase or decrease credit limits, with their treasury review';
+
+CREATE TABLE IF NOT EXISTS credit_limit_history (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    account_id VARCHAR(36) NOT NULL,
+    card_id VARCHAR(36) NULL,
+    target_type VARCHAR(10) NOT NULL COMMENT 'account or card',
+
+    -- Limit in effect
+    credit_limit DECIMAL(15,2) NOT NULL,
+    change_request_id VARCHAR(36) NULL COMMENT 'Empty for the limit set before the history started',
+    changed_by VARCHAR(36) NULL,
+    effective_from TIMESTAMP NOT NULL,
+    effective_to TIMESTAMP NULL COMMENT 'Empty for the limit in effect',
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_credit_limit_history_account_id (account_id),
+    INDEX idx_credit_limit_history_card_id (card_id),
+    INDEX idx_credit_limit_history_effective_from (effective_from),
+
+    CONSTRAINT fk_credit_limit_history_account FOREIGN KEY (account_id) REFERENCES accounts(id)
+);
+
+ALTER TABLE credit_limit_history COMMENT = 'Credit limits of accounts and cards with the period they applied';
@@ -0,0 +1,113 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V18__transaction_categories.sql
+-- Description: Two-level category tree for transactions (default categories shared by
+--              every user plus custom ones) and user-defined rules that categorize new
+--              transactions by merchant, description, amount range or card.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS transaction_categories (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NULL COMMENT 'Empty for default categories',
+    parent_id VARCHAR(36) NULL COMMENT 'Empty for top level categories',
+
+    -- Category details
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(10) NOT NULL DEFAULT 'expense' COMMENT 'expense or income',
+    icon VARCHAR(50) NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_transaction_categories_user_id (user_id),
+    INDEX idx_transaction_categories_parent_id (parent_id),
+
+    CONSTRAINT fk_transaction_categories_parent FOREIGN KEY (parent_id) REFERENCES transaction_categories(id),
+    CONSTRAINT chk_valid_category_kind CHECK (kind IN ('expense', 'income'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE transaction_categories COMMENT = 'Default and user defined transaction categories (category > subcategory)';
+
+CREATE TABLE IF NOT EXISTS categorization_rules (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    category_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NULL,
+
+    -- Conditions (all the ones set must match)
+    merchant_pattern VARCHAR(255) NULL COMMENT 'Case insensitive substring of the merchant name',
+    description_regex VARCHAR(255) NULL COMMENT 'Case insensitive regular expression over the description',
+    min_amount DECIMAL(15,2) NULL,
+    max_amount DECIMAL(15,2) NULL,
+    card_id VARCHAR(36) NULL,
+
+    -- Evaluation
+    priority INT NOT NULL DEFAULT 0 COMMENT 'Highest priority is evaluated first, the first match wins',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_categorization_rules_user_priority (user_id, priority),
+    INDEX idx_categorization_rules_category_id (category_id),
+
+    CONSTRAINT fk_categorization_rules_category FOREIGN KEY (category_id) REFERENCES transaction_categories(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE categorization_rules COMMENT = 'User defined rules that assign a category to new transactions';
+
+-- Category of each transaction and whether it was chosen by hand or by a rule
+ALTER TABLE transactions
+ADD COLUMN category_id VARCHAR(36) NULL COMMENT 'Empty for uncategorized transactions',
+ADD COLUMN category_source VARCHAR(10) NULL COMMENT 'manual or rule';
+
+CREATE INDEX idx_transactions_user_category ON transactions(user_id, category_id);
+
+-- Default category tree
+INSERT IGNORE INTO transaction_categories (id, user_id, parent_id, name, kind, icon, is_default) VALUES
+('cat-supermercado', NULL, NULL, 'Supermercado', 'expense', 'shopping-cart', TRUE),
+('cat-comida', NULL, NULL, 'Comida y delivery', 'expense', 'utensils', TRUE),
+('cat-transporte', NULL, NULL, 'Transporte', 'expense', 'car', TRUE),
+('cat-servicios', NULL, NULL, 'Servicios', 'expense', 'bolt', TRUE),
+('cat-vivienda', NULL, NULL, 'Vivienda', 'expense', 'home', TRUE),
+('cat-impuestos', NULL, NULL, 'Impuestos', 'expense', 'landmark', TRUE),
+('cat-salud', NULL, NULL, 'Salud', 'expense', 'heart-pulse', TRUE),
+('cat-educacion', NULL, NULL, 'Educación', 'expense', 'graduation-cap', TRUE),
+('cat-entretenimiento', NULL, NULL, 'Entretenimiento', 'expense', 'film', TRUE),
+('cat-compras', NULL, NULL, 'Compras', 'expense', 'shopping-bag', TRUE),
+('cat-viajes', NULL, NULL, 'Viajes', 'expense', 'plane', TRUE),
+('cat-transferencias', NULL, NULL, 'Transferencias', 'expense', 'arrow-right-left', TRUE),
+('cat-ahorro', NULL, NULL, 'Ahorro e inversión', 'expense', 'piggy-bank', TRUE),
+('cat-otros', NULL, NULL, 'Otros', 'expense', 'ellipsis', TRUE),
+('cat-ingresos', NULL, NULL, 'Ingresos', 'income', 'wallet', TRUE);
+
+INSERT IGNORE INTO transaction_categories (id, user_id, parent_id, name, kind, icon, is_default) VALUES
+('cat-combustible', NULL, 'cat-transporte', 'Combustible', 'expense', 'fuel', TRUE),
+('cat-transporte-publico', NULL, 'cat-transporte', 'Transporte público', 'expense', 'bus', TRUE),
+('cat-peajes', NULL, 'cat-transporte', 'Peajes y estacionamiento', 'expense', 'parking', TRUE),
+('cat-luz', NULL, 'cat-servicios', 'Luz', 'expense', 'lightbulb', TRUE),
+('cat-gas', NULL, 'cat-servicios', 'Gas', 'expense', 'flame', TRUE),
+('cat-agua', NULL, 'cat-servicios', 'Agua', 'expense', 'droplet', TRUE),
+('cat-internet', NULL, 'cat-servicios', 'Internet y telefonía', 'expense', 'wifi', TRUE),
+('cat-alquiler', NULL, 'cat-vivienda', 'Alquiler', 'expense', 'key', TRUE),
+('cat-expensas', NULL, 'cat-vivienda', 'Expensas', 'expense', 'building', TRUE),
+('cat-abl', NULL, 'cat-impuestos', 'ABL', 'expense', 'receipt', TRUE),
+('cat-monotributo', NULL, 'cat-impuestos', 'Monotributo', 'expense', 'receipt', TRUE),
+('cat-afip', NULL, 'cat-impuestos', 'AFIP', 'expense', 'receipt', TRUE),
+('cat-prepaga', NULL, 'cat-salud', 'Prepaga', 'expense', 'stethoscope', TRUE),
+('cat-farmacia', NULL, 'cat-salud', 'Farmacia', 'expense', 'pill', TRUE),
+('cat-streaming', NULL, 'cat-entretenimiento', 'Streaming', 'expense', 'tv', TRUE),
+('cat-salidas', NULL, 'cat-entretenimiento', 'Salidas', 'expense', 'glass', TRUE),
+('cat-ropa', NULL, 'cat-compras', 'Ropa', 'expense', 'shirt', TRUE),
+('cat-electronica', NULL, 'cat-compras', 'Electrónica', 'expense', 'laptop', TRUE),
+('cat-sueldo', NULL, 'cat-ingresos', 'Sueldo', 'income', 'briefcase', TRUE),
+('cat-aguinaldo', NULL, 'cat-ingresos', 'Aguinaldo', 'income', 'gift', TRUE),
+('cat-honorarios', NULL, 'cat-ingresos', 'Honorarios', 'income', 'file-text', TRUE),
+('cat-reintegros', NULL, 'cat-ingresos', 'Reintegros', 'income', 'rotate-ccw', TRUE);
@@ -0,0 +1,34 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V19__transaction_allocations.sql
+-- Description: Split transactions. A transaction can be divided into allocations with
+--              their own amount, category, note and counterpart user; the allocations
+--              always add up to the transaction amount.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS transaction_allocations (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    transaction_id VARCHAR(36) NOT NULL,
+    position INT NOT NULL DEFAULT 0 COMMENT 'Order of the allocation inside the split',
+
+    -- Allocation details
+    amount DECIMAL(15,2) NOT NULL,
+    category_id VARCHAR(36) NULL COMMENT 'Empty keeps the category of the transaction',
+    note VARCHAR(255) NULL,
+    counterpart_user_id VARCHAR(36) NULL COMMENT 'Person this part belongs to; not counted as spending of the owner',
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_transaction_allocations_transaction_id (transaction_id),
+    INDEX idx_transaction_allocations_category_id (category_id),
+    INDEX idx_transaction_allocations_counterpart (counterpart_user_id),
+
+    CONSTRAINT fk_transaction_allocations_transaction FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transaction_allocations_category FOREIGN KEY (category_id) REFERENCES transaction_categories(id),
+    CONSTRAINT chk_allocation_amount_positive CHECK (amount > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE transaction_allocations COMMENT = 'Parts of split transactions by category or person';
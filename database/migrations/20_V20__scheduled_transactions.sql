@@ -0,0 +1,81 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V20__scheduled_transactions.sql
+-- Description: Recurring and future-dated transactions. A schedule is a transaction
+--              template with an RRULE-like recurrence (or none for one-off transactions)
+--              that the scheduler materializes; every occurrence leaves a run.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS scheduled_transactions (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+
+    -- Transaction template
+    type VARCHAR(50) NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'ARS',
+    from_account_id VARCHAR(36) NULL,
+    to_account_id VARCHAR(36) NULL,
+    from_card_id VARCHAR(36) NULL,
+    to_card_id VARCHAR(36) NULL,
+    description TEXT NULL,
+    merchant_name VARCHAR(255) NULL,
+    category_id VARCHAR(36) NULL,
+
+    -- Schedule
+    recurrence VARCHAR(255) NULL COMMENT 'RRULE subset, e.g. FREQ=MONTHLY;BYMONTHDAY=5. Empty for one-off transactions',
+    start_at DATETIME NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' COMMENT 'active, paused, completed, canceled',
+
+    -- Next occurrence
+    next_occurrence_at DATETIME NULL COMMENT 'Date of the next occurrence in the schedule',
+    next_run_at DATETIME NULL COMMENT 'When the next occurrence runs; differs when it was moved',
+    next_amount DECIMAL(15,2) NULL COMMENT 'Overrides the amount of the next occurrence only',
+    next_description TEXT NULL COMMENT 'Overrides the description of the next occurrence only',
+
+    -- Execution tracking
+    occurrence_count INT NOT NULL DEFAULT 0,
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    last_run_at DATETIME NULL,
+    last_error TEXT NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_scheduled_transactions_user_id (user_id),
+    INDEX idx_scheduled_transactions_due (status, next_run_at),
+
+    CONSTRAINT fk_scheduled_transactions_category FOREIGN KEY (category_id) REFERENCES transaction_categories(id) ON DELETE SET NULL,
+    CONSTRAINT chk_scheduled_amount_positive CHECK (amount > 0),
+    CONSTRAINT chk_scheduled_status CHECK (status IN ('active', 'paused', 'completed', 'canceled'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE scheduled_transactions COMMENT = 'Recurring and future-dated transaction templates';
+
+CREATE TABLE IF NOT EXISTS scheduled_transaction_runs (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    schedule_id VARCHAR(36) NOT NULL,
+    scheduled_for DATETIME NOT NULL COMMENT 'Date of the occurrence in the schedule',
+
+    -- Outcome
+    status VARCHAR(20) NOT NULL COMMENT 'processing, executed, skipped, failed',
+    transaction_id VARCHAR(36) NULL COMMENT 'Transaction created by the occurrence',
+    amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    error TEXT NULL COMMENT 'Failure reason, or why the occurrence was skipped',
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    UNIQUE KEY uk_scheduled_runs_occurrence (schedule_id, scheduled_for) COMMENT 'An occurrence is materialized only once',
+    INDEX idx_scheduled_runs_transaction_id (transaction_id),
+
+    CONSTRAINT fk_scheduled_runs_schedule FOREIGN KEY (schedule_id) REFERENCES scheduled_transactions(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE scheduled_transaction_runs COMMENT = 'Execution history of scheduled transactions';
@@ -0,0 +1,51 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V21__detected_subscriptions.sql
+-- Description: Snapshot of the subscriptions detected from the transaction history
+--              (recurring charges by merchant, amount and period). Refreshed by the
+--              transaction service and read by report-service and the chatbot.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS detected_subscriptions (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+
+    -- Merchant
+    merchant_key VARCHAR(255) NOT NULL COMMENT 'Normalized merchant used to group the charges',
+    merchant_name VARCHAR(255) NOT NULL,
+
+    -- Billing
+    currency VARCHAR(3) NOT NULL DEFAULT 'ARS',
+    period VARCHAR(20) NOT NULL COMMENT 'weekly, biweekly, monthly, quarterly, yearly',
+    amount DECIMAL(15,2) NOT NULL COMMENT 'Last amount charged',
+    annualized_cost DECIMAL(15,2) NOT NULL,
+    charge_count INT NOT NULL DEFAULT 0,
+    first_charge_at DATETIME NOT NULL,
+    last_charge_at DATETIME NOT NULL,
+    next_expected_at DATETIME NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' COMMENT 'active, missed, cancelled',
+
+    -- Price changes
+    previous_amount DECIMAL(15,2) NULL COMMENT 'Amount before the latest price change',
+    price_changed_at DATETIME NULL COMMENT 'Date of the latest price change',
+    price_changes JSON NULL COMMENT 'Every price change kept by the subscription',
+
+    -- Payment method and category of the last charge
+    card_id VARCHAR(36) NULL,
+    account_id VARCHAR(36) NULL,
+    category_id VARCHAR(36) NULL,
+    last_transaction_id VARCHAR(36) NOT NULL,
+
+    -- Audit fields
+    detected_at DATETIME NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_detected_subscriptions_user_id (user_id, status),
+
+    CONSTRAINT chk_detected_subscriptions_period CHECK (period IN ('weekly', 'biweekly', 'monthly', 'quarterly', 'yearly')),
+    CONSTRAINT chk_detected_subscriptions_status CHECK (status IN ('active', 'missed', 'cancelled'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE detected_subscriptions COMMENT = 'Subscriptions detected from the transaction history';
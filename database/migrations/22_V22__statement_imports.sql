@@ -0,0 +1,112 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V22__statement_imports.sql
+-- Description: Bank statement imports (CSV, OFX, QIF and bank exports). Each import
+--              keeps its rows and what happened to them; the transactions it creates
+--              carry the batch ID in metadata.importBatchId so it can be rolled back.
+--              Users can save the column mappings of their CSV exports.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS import_batches (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+
+    -- Source file
+    format VARCHAR(20) NOT NULL COMMENT 'csv, ofx, qif or the name of a bank parser',
+    file_name VARCHAR(255) NULL,
+
+    -- Destination (exactly one)
+    account_id VARCHAR(36) NULL,
+    card_id VARCHAR(36) NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'ARS',
+
+    -- Outcome
+    status VARCHAR(20) NOT NULL DEFAULT 'processing' COMMENT 'processing, completed, failed, rolled_back',
+    total_rows INT NOT NULL DEFAULT 0,
+    imported_count INT NOT NULL DEFAULT 0,
+    duplicate_count INT NOT NULL DEFAULT 0,
+    invalid_count INT NOT NULL DEFAULT 0,
+    from_date DATETIME NULL COMMENT 'First movement of the statement',
+    to_date DATETIME NULL COMMENT 'Last movement of the statement',
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    rolled_back_at TIMESTAMP NULL,
+
+    -- Indexes for performance optimization
+    INDEX idx_import_batches_user_id (user_id, created_at),
+    INDEX idx_import_batches_account_id (account_id),
+    INDEX idx_import_batches_card_id (card_id),
+
+    CONSTRAINT chk_import_batches_status CHECK (status IN ('processing', 'completed', 'failed', 'rolled_back')),
+    CONSTRAINT chk_import_batches_destination CHECK ((account_id IS NULL) <> (card_id IS NULL))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE import_batches COMMENT = 'Bank statement imports';
+
+CREATE TABLE IF NOT EXISTS import_batch_rows (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    batch_id VARCHAR(36) NOT NULL,
+    line_number INT NOT NULL COMMENT 'Position of the movement in the file, starting at 1',
+    status VARCHAR(20) NOT NULL COMMENT 'imported, duplicate, invalid, rolled_back',
+
+    -- Movement as read from the statement (debits negative)
+    posted_at DATETIME NULL,
+    amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NULL,
+    description TEXT NULL,
+    merchant TEXT NULL,
+    external_id VARCHAR(255) NULL COMMENT 'Identifier of the movement in the bank',
+    balance DECIMAL(15,2) NULL,
+
+    -- Outcome
+    transaction_id VARCHAR(36) NULL COMMENT 'Transaction created by the row',
+    duplicate_of_id VARCHAR(36) NULL COMMENT 'Tracked transaction the row matched',
+    error_message TEXT NULL,
+
+    -- Indexes for performance optimization
+    INDEX idx_import_batch_rows_batch_id (batch_id, line_number),
+    INDEX idx_import_batch_rows_transaction_id (transaction_id),
+
+    CONSTRAINT fk_import_batch_rows_batch FOREIGN KEY (batch_id) REFERENCES import_batches(id) ON DELETE CASCADE,
+    CONSTRAINT chk_import_batch_rows_status CHECK (status IN ('new', 'imported', 'duplicate', 'invalid', 'rolled_back'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE import_batch_rows COMMENT = 'Rows of the bank statement imports';
+
+CREATE TABLE IF NOT EXISTS import_csv_mappings (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+
+    -- File layout
+    delimiter VARCHAR(1) NOT NULL DEFAULT '' COMMENT 'Detected from the header when empty',
+    skip_rows INT NOT NULL DEFAULT 0,
+    decimal_separator VARCHAR(1) NOT NULL DEFAULT '' COMMENT 'Detected from the amounts when empty',
+    date_format VARCHAR(30) NOT NULL DEFAULT '' COMMENT 'DD/MM/YYYY when empty',
+    invert_sign BOOLEAN NOT NULL DEFAULT FALSE,
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+
+    -- Column headers ("|" separates alternatives)
+    date_column VARCHAR(255) NOT NULL,
+    amount_column VARCHAR(255) NOT NULL DEFAULT '',
+    debit_column VARCHAR(255) NOT NULL DEFAULT '',
+    credit_column VARCHAR(255) NOT NULL DEFAULT '',
+    description_column VARCHAR(255) NOT NULL DEFAULT '',
+    merchant_column VARCHAR(255) NOT NULL DEFAULT '',
+    reference_column VARCHAR(255) NOT NULL DEFAULT '',
+    balance_column VARCHAR(255) NOT NULL DEFAULT '',
+    currency_column VARCHAR(255) NOT NULL DEFAULT '',
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    UNIQUE KEY uk_import_csv_mappings_user_name (user_id, name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE import_csv_mappings COMMENT = 'Saved CSV column mappings of the users';
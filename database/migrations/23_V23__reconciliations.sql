@@ -0,0 +1,86 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V23__reconciliations.sql
+-- Description: Reconciliation of bank statements (imports or uploaded files) with the
+--              transactions tracked in FinTrack. Each item is a statement movement,
+--              a transaction, or both when they match.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS reconciliations (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+
+    -- Account or card of the statement (exactly one)
+    account_id VARCHAR(36) NULL,
+    card_id VARCHAR(36) NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'ARS',
+
+    -- Statement
+    source VARCHAR(20) NOT NULL COMMENT 'import, upload',
+    import_batch_id VARCHAR(36) NULL,
+    file_name VARCHAR(255) NULL,
+    statement_from DATE NOT NULL,
+    statement_to DATE NOT NULL,
+
+    -- Matching configuration
+    date_window_days INT NOT NULL DEFAULT 3,
+    amount_tolerance DECIMAL(15,2) NOT NULL DEFAULT 0,
+
+    -- Outcome
+    matched_count INT NOT NULL DEFAULT 0,
+    pending_count INT NOT NULL DEFAULT 0 COMMENT 'Matches waiting for the user to confirm them',
+    missing_in_fintrack_count INT NOT NULL DEFAULT 0,
+    missing_in_bank_count INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP NULL COMMENT 'When every item got confirmed',
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_reconciliations_user_id (user_id, statement_to),
+    INDEX idx_reconciliations_account_id (account_id, statement_to),
+    INDEX idx_reconciliations_card_id (card_id, statement_to),
+
+    CONSTRAINT fk_reconciliations_import_batch FOREIGN KEY (import_batch_id) REFERENCES import_batches(id) ON DELETE SET NULL,
+    CONSTRAINT chk_reconciliations_source CHECK (source IN ('import', 'upload')),
+    CONSTRAINT chk_reconciliations_destination CHECK ((account_id IS NULL) <> (card_id IS NULL))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE reconciliations COMMENT = 'Reconciliations of bank statements with tracked transactions';
+
+CREATE TABLE IF NOT EXISTS reconciliation_items (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    reconciliation_id VARCHAR(36) NOT NULL,
+    status VARCHAR(20) NOT NULL COMMENT 'matched, missing_in_fintrack, missing_in_bank',
+
+    -- Match
+    match_method VARCHAR(20) NULL COMMENT 'import, external_id, fuzzy, manual',
+    score DECIMAL(4,2) NOT NULL DEFAULT 0,
+    confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+    confirmed_at TIMESTAMP NULL,
+
+    -- Statement side (debits negative)
+    import_row_id VARCHAR(36) NULL,
+    bank_date DATETIME NULL,
+    bank_amount DECIMAL(15,2) NULL,
+    bank_description TEXT NULL,
+    bank_external_id VARCHAR(255) NULL,
+
+    -- FinTrack side (signed like the statement)
+    transaction_id VARCHAR(36) NULL,
+    transaction_date DATETIME NULL,
+    transaction_amount DECIMAL(15,2) NULL,
+    transaction_description TEXT NULL,
+
+    -- Indexes for performance optimization
+    INDEX idx_reconciliation_items_reconciliation_id (reconciliation_id, status),
+    INDEX idx_reconciliation_items_transaction_id (transaction_id),
+
+    CONSTRAINT fk_reconciliation_items_reconciliation FOREIGN KEY (reconciliation_id) REFERENCES reconciliations(id) ON DELETE CASCADE,
+    CONSTRAINT chk_reconciliation_items_status CHECK (status IN ('matched', 'missing_in_fintrack', 'missing_in_bank'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE reconciliation_items COMMENT = 'Statement movements and transactions of the reconciliations';
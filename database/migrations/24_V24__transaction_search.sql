@@ -0,0 +1,19 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V24__transaction_search.sql
+-- Description: Free text search over transactions (description, merchant, notes and
+--              tags) with a FULLTEXT index, and indexes for keyset (cursor) pagination.
+-- =====================================================
+
+-- Notes of the user and a plain text copy of the tags, which FULLTEXT cannot index as JSON
+ALTER TABLE transactions
+ADD COLUMN notes TEXT NULL COMMENT 'Free text of the user',
+ADD COLUMN tags_text VARCHAR(2000) GENERATED ALWAYS AS (CAST(tags AS CHAR(2000))) STORED COMMENT 'Tags as text for the search index';
+
+-- Indexes for performance optimization
+CREATE FULLTEXT INDEX ft_transactions_search ON transactions(description, merchant_name, notes, tags_text);
+
+-- Cursor pagination sorts by a column and breaks ties by ID
+CREATE INDEX idx_transactions_user_created_id ON transactions(user_id, created_at, id);
+CREATE INDEX idx_transactions_user_amount_id ON transactions(user_id, amount, id);
+CREATE INDEX idx_transactions_currency ON transactions(currency);
@@ -0,0 +1,37 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V25__transaction_attachments.sql
+-- Description: Receipts and invoices (facturas) attached to transactions and installment
+--              plans. The content lives in the attachment storage (local disk or S3
+--              compatible); this table keeps the metadata and the per-user usage.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS transaction_attachments (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL COMMENT 'Who uploaded it; counts against their quota',
+
+    -- Record the file belongs to. There is no foreign key because installment plans belong to the
+    -- account service: attachments of purged records are deleted by the attachment cleaner.
+    owner_type VARCHAR(20) NOT NULL COMMENT 'transaction, installment_plan',
+    owner_id VARCHAR(36) NOT NULL,
+
+    -- File
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL COMMENT 'Sniffed from the content',
+    size_bytes BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_transaction_attachments_owner (owner_type, owner_id, created_at),
+    INDEX idx_transaction_attachments_user_id (user_id),
+    UNIQUE KEY uk_transaction_attachments_storage_key (storage_key),
+
+    CONSTRAINT chk_transaction_attachments_owner_type CHECK (owner_type IN ('transaction', 'installment_plan')),
+    CONSTRAINT chk_transaction_attachments_size CHECK (size_bytes > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE transaction_attachments COMMENT = 'Receipts and invoices attached to transactions and installment plans';
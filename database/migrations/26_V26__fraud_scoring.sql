@@ -0,0 +1,61 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V26__fraud_scoring.sql
+-- Description: Rule-based fraud and anomaly scoring of incoming transactions. Users tune
+--              the thresholds from which a transaction is alerted, held or blocked, and
+--              every score is kept with the signals that made it up.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS fraud_settings (
+    -- Core identity: one row per user, the defaults apply until it exists
+    user_id VARCHAR(36) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+
+    -- Thresholds over the 0-100 score, 0 disables the level
+    alert_threshold INT NOT NULL DEFAULT 40,
+    hold_threshold INT NOT NULL DEFAULT 70,
+    block_threshold INT NOT NULL DEFAULT 90,
+
+    -- Signals
+    amount_deviation DECIMAL(5,2) NOT NULL DEFAULT 3.00 COMMENT 'Standard deviations above the average amount',
+    velocity_count INT NOT NULL DEFAULT 5,
+    velocity_window_minutes INT NOT NULL DEFAULT 10,
+    quiet_hours_start TINYINT NOT NULL DEFAULT 0,
+    quiet_hours_end TINYINT NOT NULL DEFAULT 6 COMMENT 'Exclusive; equal to the start disables the quiet hours',
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_fraud_settings_thresholds CHECK (
+        alert_threshold BETWEEN 0 AND 100 AND hold_threshold BETWEEN 0 AND 100 AND block_threshold BETWEEN 0 AND 100
+    ),
+    CONSTRAINT chk_fraud_settings_quiet_hours CHECK (quiet_hours_start BETWEEN 0 AND 23 AND quiet_hours_end BETWEEN 0 AND 23)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE fraud_settings COMMENT = 'Risk scoring thresholds of each user';
+
+CREATE TABLE IF NOT EXISTS transaction_risk_scores (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    transaction_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+
+    -- Assessment
+    score INT NOT NULL,
+    action VARCHAR(10) NOT NULL COMMENT 'allow, alert, hold, block',
+    reasons JSON NULL COMMENT 'Signals with their points and detail',
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_transaction_risk_scores_transaction (transaction_id, created_at),
+    INDEX idx_transaction_risk_scores_user (user_id, created_at),
+
+    CONSTRAINT fk_transaction_risk_scores_transaction FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+    CONSTRAINT chk_transaction_risk_scores_score CHECK (score BETWEEN 0 AND 100),
+    CONSTRAINT chk_transaction_risk_scores_action CHECK (action IN ('allow', 'alert', 'hold', 'block'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE transaction_risk_scores COMMENT = 'Fraud and anomaly risk score of each incoming transaction';
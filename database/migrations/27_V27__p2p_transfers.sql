@@ -0,0 +1,77 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V27__p2p_transfers.sql
+-- Description: Transfers between FinTrack users found by email or by a CVU-style alias.
+--              Each user has a profile with the alias, the account transfers go into and
+--              own daily limits; each transfer keeps both sides and the refund, if any.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS p2p_profiles (
+    -- Core identity: one row per user, created when the user sets any field
+    user_id VARCHAR(36) PRIMARY KEY,
+    alias VARCHAR(20) NULL COMMENT 'Lowercase letters, digits and dots, unique across users',
+
+    -- Receiving
+    default_account_id VARCHAR(36) NULL COMMENT 'Where accepted transfers go; the oldest wallet otherwise',
+    auto_accept BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- Own daily limits, only lower than the ones of the platform
+    daily_amount_limit DECIMAL(15,2) NULL,
+    daily_count_limit INT NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    CONSTRAINT uk_p2p_profiles_alias UNIQUE (alias),
+    CONSTRAINT chk_p2p_profiles_limits CHECK (
+        (daily_amount_limit IS NULL OR daily_amount_limit > 0) AND (daily_count_limit IS NULL OR daily_count_limit > 0)
+    )
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE p2p_profiles COMMENT = 'How each user is found and paid by other users';
+
+CREATE TABLE IF NOT EXISTS p2p_transfers (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    kind VARCHAR(10) NOT NULL COMMENT 'send, request',
+    sender_user_id VARCHAR(36) NOT NULL COMMENT 'The user the money comes from',
+    recipient_user_id VARCHAR(36) NOT NULL COMMENT 'The user the money goes to',
+    sender_account_id VARCHAR(36) NULL,
+    recipient_account_id VARCHAR(36) NULL,
+
+    -- Transfer details
+    amount DECIMAL(15,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reason VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Why it was declined, cancelled or failed',
+
+    -- Transactions of each side
+    sender_transaction_id VARCHAR(36) NULL,
+    recipient_transaction_id VARCHAR(36) NULL,
+    refund_transaction_id VARCHAR(36) NULL,
+
+    -- Lifecycle
+    debited_at TIMESTAMP NULL COMMENT 'When the money left the sender; counts against the daily limit',
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_p2p_transfers_sender (sender_user_id, created_at),
+    INDEX idx_p2p_transfers_recipient (recipient_user_id, created_at),
+    INDEX idx_p2p_transfers_usage (sender_user_id, currency, debited_at),
+    INDEX idx_p2p_transfers_expiry (status, expires_at),
+
+    CONSTRAINT chk_p2p_transfers_kind CHECK (kind IN ('send', 'request')),
+    CONSTRAINT chk_p2p_transfers_status CHECK (status IN ('pending', 'processing', 'completed', 'declined', 'cancelled', 'expired', 'failed')),
+    CONSTRAINT chk_p2p_transfers_amount CHECK (amount > 0),
+    CONSTRAINT chk_p2p_transfers_parties CHECK (sender_user_id <> recipient_user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE p2p_transfers COMMENT = 'Money sent and requested between FinTrack users';
@@ -0,0 +1,116 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V28__shared_expense_groups.sql
+-- Description: Groups of users sharing expenses. An expense is paid by one member and
+--              split among members equally, by percentage or by exact amounts; members
+--              settle up through transfers between users.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS expense_groups (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    currency VARCHAR(3) NOT NULL COMMENT 'All the expenses and settlements of the group are in it',
+    created_by VARCHAR(36) NOT NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE expense_groups COMMENT = 'Groups of users sharing expenses';
+
+CREATE TABLE IF NOT EXISTS expense_group_members (
+    -- Core identity
+    group_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    role VARCHAR(10) NOT NULL DEFAULT 'member' COMMENT 'owner, member',
+
+    -- Audit fields
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (group_id, user_id),
+
+    -- Indexes for performance optimization
+    INDEX idx_expense_group_members_user (user_id),
+
+    CONSTRAINT fk_expense_group_members_group FOREIGN KEY (group_id) REFERENCES expense_groups(id) ON DELETE CASCADE,
+    CONSTRAINT chk_expense_group_members_role CHECK (role IN ('owner', 'member'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE expense_group_members COMMENT = 'Users of each shared expense group';
+
+CREATE TABLE IF NOT EXISTS group_expenses (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    group_id VARCHAR(36) NOT NULL,
+    paid_by VARCHAR(36) NOT NULL,
+
+    -- Expense details
+    amount DECIMAL(15,2) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    split_method VARCHAR(20) NOT NULL DEFAULT 'equal' COMMENT 'equal, percentage, exact',
+    transaction_id VARCHAR(36) NULL COMMENT 'Transaction of the payer it comes from, split into allocations',
+    expense_date TIMESTAMP NOT NULL,
+
+    -- Audit fields
+    created_by VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_group_expenses_group (group_id, expense_date),
+    INDEX idx_group_expenses_transaction (transaction_id),
+
+    CONSTRAINT fk_group_expenses_group FOREIGN KEY (group_id) REFERENCES expense_groups(id) ON DELETE CASCADE,
+    CONSTRAINT fk_group_expenses_transaction FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL,
+    CONSTRAINT chk_group_expenses_amount CHECK (amount > 0),
+    CONSTRAINT chk_group_expenses_split_method CHECK (split_method IN ('equal', 'percentage', 'exact'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE group_expenses COMMENT = 'Expenses paid by one member of a group and shared with others';
+
+CREATE TABLE IF NOT EXISTS group_expense_shares (
+    -- Core identity
+    expense_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    position INT NOT NULL DEFAULT 0 COMMENT 'Order of the participant inside the split',
+
+    -- Share details
+    amount DECIMAL(15,2) NOT NULL,
+    percentage DECIMAL(5,2) NULL COMMENT 'Only in percentage splits',
+
+    PRIMARY KEY (expense_id, user_id),
+
+    -- Indexes for performance optimization
+    INDEX idx_group_expense_shares_user (user_id),
+
+    CONSTRAINT fk_group_expense_shares_expense FOREIGN KEY (expense_id) REFERENCES group_expenses(id) ON DELETE CASCADE,
+    CONSTRAINT chk_group_expense_shares_amount CHECK (amount >= 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE group_expense_shares COMMENT = 'What each participant owes of a group expense';
+
+CREATE TABLE IF NOT EXISTS group_settlements (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    group_id VARCHAR(36) NOT NULL,
+    from_user_id VARCHAR(36) NOT NULL,
+    to_user_id VARCHAR(36) NOT NULL,
+
+    -- Settlement details
+    amount DECIMAL(15,2) NOT NULL,
+    transfer_id VARCHAR(36) NOT NULL COMMENT 'Transfer between users that pays it; stops counting if it is declined or expires',
+
+    -- Audit fields
+    created_by VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_group_settlements_group (group_id, created_at),
+
+    CONSTRAINT fk_group_settlements_group FOREIGN KEY (group_id) REFERENCES expense_groups(id) ON DELETE CASCADE,
+    CONSTRAINT fk_group_settlements_transfer FOREIGN KEY (transfer_id) REFERENCES p2p_transfers(id),
+    CONSTRAINT chk_group_settlements_amount CHECK (amount > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE group_settlements COMMENT = 'Payments between members that settle a group';
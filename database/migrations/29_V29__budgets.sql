@@ -0,0 +1,95 @@
+-- =====================================================
+-- FinTrack Transaction Service - Database Migration
+-- Version: V29__budgets.sql
+-- Description: Monthly budgets by category, merchant or card with rollover options,
+--              the snapshot of each month against the actual spending (read by
+--              report-service and the chatbot) and the alert thresholds already sent.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS budgets (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+
+    -- Scope
+    scope VARCHAR(20) NOT NULL COMMENT 'category, merchant, card',
+    category_id VARCHAR(36) NULL COMMENT 'Includes its subcategories',
+    merchant_name VARCHAR(255) NULL,
+    card_id VARCHAR(36) NULL,
+
+    -- Plan
+    amount DECIMAL(15,2) NOT NULL COMMENT 'Limit of every month before rollover',
+    currency VARCHAR(3) NOT NULL DEFAULT 'ARS',
+    rollover VARCHAR(10) NOT NULL DEFAULT 'none' COMMENT 'none, unspent, full',
+    start_month CHAR(7) NOT NULL COMMENT 'YYYY-MM',
+    end_month CHAR(7) NULL COMMENT 'YYYY-MM, no end when NULL',
+    alerts_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_budgets_user_id (user_id),
+    INDEX idx_budgets_months (start_month, end_month),
+
+    CONSTRAINT fk_budgets_category FOREIGN KEY (category_id) REFERENCES transaction_categories(id) ON DELETE CASCADE,
+    CONSTRAINT chk_budgets_scope CHECK (scope IN ('category', 'merchant', 'card')),
+    CONSTRAINT chk_budgets_amount CHECK (amount > 0),
+    CONSTRAINT chk_budgets_rollover CHECK (rollover IN ('none', 'unspent', 'full'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE budgets COMMENT = 'Monthly spending plans of users by category, merchant or card';
+
+CREATE TABLE IF NOT EXISTS budget_periods (
+    -- Core identity
+    budget_id VARCHAR(36) NOT NULL,
+    month CHAR(7) NOT NULL COMMENT 'YYYY-MM',
+    user_id VARCHAR(36) NOT NULL,
+
+    -- Budget versus actual
+    amount DECIMAL(15,2) NOT NULL,
+    carried_over DECIMAL(15,2) NOT NULL DEFAULT 0 COMMENT 'Rolled over from the previous month, negative when overspent',
+    limit_amount DECIMAL(15,2) NOT NULL,
+    spent DECIMAL(15,2) NOT NULL DEFAULT 0 COMMENT 'Transactions of the month, purchases in installments aside',
+    installments DECIMAL(15,2) NOT NULL DEFAULT 0 COMMENT 'Installments due in the month',
+    actual DECIMAL(15,2) NOT NULL DEFAULT 0,
+    remaining DECIMAL(15,2) NOT NULL DEFAULT 0,
+    percent_used DECIMAL(7,2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'on_track' COMMENT 'on_track, warning, exceeded',
+
+    -- Audit fields
+    computed_at DATETIME NOT NULL,
+
+    PRIMARY KEY (budget_id, month),
+
+    -- Indexes for performance optimization
+    INDEX idx_budget_periods_user_month (user_id, month),
+
+    CONSTRAINT fk_budget_periods_budget FOREIGN KEY (budget_id) REFERENCES budgets(id) ON DELETE CASCADE,
+    CONSTRAINT chk_budget_periods_status CHECK (status IN ('on_track', 'warning', 'exceeded'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE budget_periods COMMENT = 'Snapshot of each month of a budget against the actual spending';
+
+CREATE TABLE IF NOT EXISTS budget_alerts (
+    -- Core identity
+    budget_id VARCHAR(36) NOT NULL,
+    month CHAR(7) NOT NULL COMMENT 'YYYY-MM',
+    threshold INT NOT NULL COMMENT 'Percentage of the limit: 50, 80, 100',
+
+    -- Spending when it was reached
+    actual DECIMAL(15,2) NOT NULL,
+    limit_amount DECIMAL(15,2) NOT NULL,
+
+    -- Audit fields
+    reached_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (budget_id, month, threshold),
+
+    CONSTRAINT fk_budget_alerts_budget FOREIGN KEY (budget_id) REFERENCES budgets(id) ON DELETE CASCADE,
+    CONSTRAINT chk_budget_alerts_threshold CHECK (threshold IN (50, 80, 100))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE budget_alerts COMMENT = 'Alert thresholds each budget reached per month, sent once';
@@ -0,0 +1,46 @@
+-- =====================================================
+-- FinTrack Account Service - Database Migration
+-- Version: V30__debt_payoff_plans.sql
+-- Description: Debt payoff plans. A plan is a simulation of paying off the credit card
+--              balances and installment plans of a user with a monthly budget and a
+--              strategy (avalanche, snowball or custom), saved with its month by month
+--              schedule to track the progress against it. A user follows one plan at a time.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS debt_payoff_plans (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+
+    -- Simulation inputs
+    strategy VARCHAR(20) NOT NULL COMMENT 'avalanche, snowball or custom',
+    monthly_budget DECIMAL(15,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    custom_order JSON NULL COMMENT 'Card and installment plan IDs in payoff order, for the custom strategy',
+    card_rate DECIMAL(5,2) NOT NULL COMMENT 'Monthly interest rate (percent) of the card balances',
+
+    -- Simulation results
+    starting_debt DECIMAL(15,2) NOT NULL,
+    total_interest DECIMAL(15,2) NOT NULL,
+    total_paid DECIMAL(15,2) NOT NULL,
+    months INT NOT NULL,
+    start_date TIMESTAMP NOT NULL,
+    payoff_date TIMESTAMP NOT NULL,
+    debts JSON NULL COMMENT 'Debts at the start of the plan',
+    schedule JSON NULL COMMENT 'Payments, interest and balances month by month',
+
+    -- Tracking
+    status VARCHAR(20) NOT NULL DEFAULT 'active' COMMENT 'active, completed or abandoned',
+    completed_at TIMESTAMP NULL,
+    abandoned_at TIMESTAMP NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    INDEX idx_debt_payoff_plans_user_id (user_id),
+    INDEX idx_debt_payoff_plans_status (status)
+);
+
+ALTER TABLE debt_payoff_plans COMMENT = 'Debt payoff plans chosen by the users, with their schedule';
@@ -0,0 +1,27 @@
+-- =====================================================
+-- FinTrack Account Service - Database Migration
+-- Version: V31__inflation_rates.sql
+-- Description: Monthly inflation series (official CPI, market expectations survey, ...)
+--              used by the "cuotas vs contado" calculator to bring each installment to
+--              today's money. A series has one rate per month.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS inflation_rates (
+    -- Core identity
+    id VARCHAR(36) PRIMARY KEY,
+    series VARCHAR(30) NOT NULL COMMENT 'Series name, e.g. indec-cpi',
+    period DATE NOT NULL COMMENT 'First day of the month',
+
+    -- Rate
+    monthly_rate DECIMAL(7,4) NOT NULL COMMENT 'Monthly inflation (percent)',
+    updated_by VARCHAR(36) NULL,
+
+    -- Audit fields
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Indexes for performance optimization
+    UNIQUE INDEX idx_inflation_rates_series_period (series, period)
+);
+
+ALTER TABLE inflation_rates COMMENT = 'Monthly inflation series for the cash versus installments calculator';
@@ -0,0 +1,10 @@
+-- =====================================================
+-- FinTrack Account Service - Database Migration
+-- Version: V32__card_expiry_notices.sql
+-- Description: Record when the expiry notice of a card was sent. The notification-service
+--              expiry job notices every active card expiring within the notice window that
+--              has no notice yet, so a missed or failed daily run is caught up by the next one.
+-- =====================================================
+
+ALTER TABLE cards
+ADD COLUMN expiry_notice_sent_at TIMESTAMP NULL COMMENT 'When the owner was notified that the card is about to expire';
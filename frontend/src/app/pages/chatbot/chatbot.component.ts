@@ -99,6 +99,20 @@ export class ChatbotComponent {
       description: 'Ranking de gastos por comercio',
       period: 'month',
       contextType: 'merchants'
+    },
+    {
+      label: 'Mis suscripciones',
+      message: 'qué suscripciones estoy pagando y cuánto me cuestan al año',
+      description: 'Cobros recurrentes, aumentos y cancelaciones',
+      period: 'all',
+      contextType: 'subscriptions'
+    },
+    {
+      label: 'Mis presupuestos',
+      message: 'cómo voy con mis presupuestos este mes',
+      description: 'Presupuesto vs gasto real, con cuotas del mes',
+      period: 'month',
+      contextType: 'budgets'
     }
   ];
 
@@ -117,7 +131,9 @@ export class ChatbotComponent {
     { value: 'income', label: 'Enfoque en ingresos' },
     { value: 'cards', label: 'Enfoque en tarjetas' },
     { value: 'installments', label: 'Enfoque en cuotas' },
-    { value: 'merchants', label: 'Enfoque en comercios' }
+    { value: 'merchants', label: 'Enfoque en comercios' },
+    { value: 'subscriptions', label: 'Enfoque en suscripciones' },
+    { value: 'budgets', label: 'Enfoque en presupuestos' }
   ];
 
   runQuery(): void {
@@ -1,7 +1,7 @@
 import { inject, Injectable } from '@angular/core';
 import { HttpClient, HttpParams } from '@angular/common/http';
-import { Observable, of, from } from 'rxjs';
-import { map, catchError, switchMap } from 'rxjs/operators';
+import { Observable, of } from 'rxjs';
+import { map, catchError } from 'rxjs/operators';
 import { environment } from '../../environments/environment';
 import { EncryptionService } from './encryption.service';
 import {
@@ -13,8 +13,7 @@ import {
   CardValidationError,
   CardBrand,
   CardType,
-  CardStatus,
-  EncryptedCardData
+  CardStatus
 } from '../models';
 
 @Injectable({ providedIn: 'root' })
@@ -25,40 +24,32 @@ export class CardService {
 
   // CRUD Operations
   createCard(cardData: CreateCardRequest): Observable<Card> {
-    // Encriptar datos sensibles antes de enviar
-    return from(this.encryptCardData(cardData)).pipe(
-      switchMap(encryptedData => {
-        // Usar el endpoint de cards con el accountId del usuario
-        const cardPayload = {
-          card_type: cardData.cardType,
-          card_brand: this.detectCardBrand(cardData.cardNumber),
-          last_four_digits: this.getLastFourDigits(cardData.cardNumber),
-          masked_number: this.maskCardNumber(cardData.cardNumber),
-          holder_name: cardData.holderName,
-          expiration_month: cardData.expirationMonth,
-          expiration_year: cardData.expirationYear,
-          nickname: cardData.nickname,
-          encrypted_number: encryptedData.encryptedNumber,
-          key_fingerprint: encryptedData.keyFingerprint,
-          // Campos específicos para tarjetas de crédito
-          ...(cardData.cardType === CardType.CREDIT && cardData.creditLimit && {
-            credit_limit: cardData.creditLimit
-          }),
-          ...(cardData.closingDate && {
-            closing_date: new Date(cardData.closingDate).toISOString().split('T')[0]
-          }),
-          ...(cardData.dueDate && {
-            due_date: new Date(cardData.dueDate).toISOString().split('T')[0]
-          })
-        };
+    // El número completo viaja solo por HTTPS: el servidor valida, detecta la marca,
+    // enmascara y cifra el número. El CVV nunca se envía.
+    const cardPayload = {
+      card_number: cardData.cardNumber.replace(/[\s-]/g, ''),
+      card_type: cardData.cardType,
+      holder_name: cardData.holderName,
+      expiration_month: cardData.expirationMonth,
+      expiration_year: cardData.expirationYear,
+      nickname: cardData.nickname,
+      // Campos específicos para tarjetas de crédito
+      ...(cardData.cardType === CardType.CREDIT && cardData.creditLimit && {
+        credit_limit: cardData.creditLimit
+      }),
+      ...(cardData.closingDate && {
+        closing_date: new Date(cardData.closingDate).toISOString().split('T')[0]
+      }),
+      ...(cardData.dueDate && {
+        due_date: new Date(cardData.dueDate).toISOString().split('T')[0]
+      })
+    };
 
-        return this.http.post<any>(`${this.apiUrl}/${cardData.accountId}/cards`, cardPayload).pipe(
-          map(response => this.mapCardResponseToCard(response)),
-          catchError(error => {
-            console.error('Error creating card:', error);
-            throw new Error('Error al crear la tarjeta: ' + (error.error?.message || error.message));
-          })
-        );
+    return this.http.post<any>(`${this.apiUrl}/${cardData.accountId}/cards`, cardPayload).pipe(
+      map(response => this.mapCardResponseToCard(response)),
+      catchError(error => {
+        console.error('Error creating card:', error.status);
+        throw new Error('Error al crear la tarjeta: ' + (error.error?.error || error.error?.message || error.message));
       })
     );
   }
@@ -352,24 +343,6 @@ export class CardService {
   }
 
   // Private Methods
-  private async encryptCardData(cardData: CreateCardRequest): Promise<EncryptedCardData> {
-    try {
-      const [encryptedNumber, encryptedCvv] = await Promise.all([
-        this.encryptionService.encryptSensitiveData(cardData.cardNumber),
-        this.encryptionService.encryptSensitiveData(cardData.cvv)
-      ]);
-
-      return {
-        encryptedNumber: encryptedNumber.encryptedData,
-        encryptedCvv: encryptedCvv.encryptedData,
-        keyFingerprint: encryptedNumber.keyFingerprint
-      };
-    } catch (error) {
-      console.error('Error encrypting card data:', error);
-      throw new Error('Error al procesar datos de la tarjeta');
-    }
-  }
-
   private mapBrandStringToEnum(brandString: string): CardBrand {
     const mapping: { [key: string]: CardBrand } = {
       'visa': CardBrand.VISA,
@@ -80,17 +80,13 @@ if ($accounts -and $accounts.Count -gt 0) {
 # 3. Crear una tarjeta de prueba
 Write-Host "`n3. 💳 Creando tarjeta de prueba..." -ForegroundColor Yellow
 $newCard = @{
+    card_number = "4111 1111 1111 1111"
     card_type = "debit"
     card_brand = "visa"
-    last_four_digits = "1234"
-    masked_number = "**** **** **** 1234"
     holder_name = "JUAN PEREZ"
     expiration_month = 12
     expiration_year = 2027
     nickname = "Tarjeta de Prueba"
-    encrypted_number = "encrypted_test_data"
-    encrypted_cvv = "encrypted_cvv_data"
-    key_fingerprint = "test_fingerprint_123"
 }
 
 $createdCard = Invoke-ApiRequest -Method "POST" -Uri "$API_BASE/accounts/$ACCOUNT_ID/cards" -Body $newCard